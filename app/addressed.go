@@ -0,0 +1,172 @@
+package app
+
+import (
+	"claude-squad/log"
+	"claude-squad/session"
+	"claude-squad/session/git"
+	"fmt"
+	"strings"
+)
+
+// addressedLineWindow is how many lines away from a comment's line a change may be and still
+// count as addressing it.
+const addressedLineWindow = 3
+
+// sentComment is a PR comment sent to an agent, remembered so later changes can be matched
+// against the lines it is about.
+type sentComment struct {
+	key     string
+	author  string
+	path    string
+	line    int
+	summary string
+	// sinceSHA is the worktree's HEAD when the comment was sent
+	sinceSHA string
+}
+
+// commentKey identifies a comment across fetches. Reviews, review comments and issue comments
+// are numbered separately by GitHub, so the type is part of the key.
+func commentKey(comment *git.PRComment) string {
+	return fmt.Sprintf("%s:%d", comment.Type, comment.ID)
+}
+
+// rememberSentComments records the comments about to be sent to the instance's agent.
+func (m *home) rememberSentComments(instance *session.Instance, comments []*git.PRComment) {
+	worktree, err := instance.GetGitWorktree()
+	if err != nil {
+		return
+	}
+	sha, err := worktree.GetCurrentCommitSHA()
+	if err != nil {
+		log.WarningLog.Printf("not tracking sent comments for %s: %v", instance.Title, err)
+		return
+	}
+
+	if m.sentComments == nil {
+		m.sentComments = make(map[string][]sentComment)
+	}
+	sent := m.sentComments[instance.Title]
+	for _, comment := range comments {
+		entry := sentComment{
+			key:      commentKey(comment),
+			author:   comment.Author,
+			path:     comment.Path,
+			line:     comment.Line,
+			summary:  firstLine(comment.Body),
+			sinceSHA: sha,
+		}
+		replaced := false
+		for i := range sent {
+			if sent[i].key == entry.key {
+				sent[i] = entry
+				replaced = true
+			}
+		}
+		if !replaced {
+			sent = append(sent, entry)
+		}
+	}
+	m.sentComments[instance.Title] = sent
+}
+
+// addressedComments returns the keys of the instance's sent comments whose lines were changed
+// since they were sent. Comments without a file can't be matched and are never included.
+func (m *home) addressedComments(instance *session.Instance) (map[string]bool, error) {
+	addressed := make(map[string]bool)
+	sent := m.sentComments[instance.Title]
+	if len(sent) == 0 {
+		return addressed, nil
+	}
+	worktree, err := instance.GetGitWorktree()
+	if err != nil {
+		return nil, err
+	}
+
+	changesSince := make(map[string]git.ChangedLines)
+	for _, comment := range sent {
+		if comment.path == "" {
+			continue
+		}
+		changed, ok := changesSince[comment.sinceSHA]
+		if !ok {
+			changed, err = worktree.ChangedLinesSince(comment.sinceSHA)
+			if err != nil {
+				return nil, err
+			}
+			changesSince[comment.sinceSHA] = changed
+		}
+		if changed.Touches(comment.path, comment.line, addressedLineWindow) {
+			addressed[comment.key] = true
+		}
+	}
+	return addressed, nil
+}
+
+// markAddressedComments flags the PR's comments that were sent to the instance's agent and those
+// it likely addressed, for display in the review.
+func (m *home) markAddressedComments(instance *session.Instance, pr *git.PullRequest) {
+	sent := m.sentComments[instance.Title]
+	if len(sent) == 0 {
+		return
+	}
+	addressed, err := m.addressedComments(instance)
+	if err != nil {
+		log.WarningLog.Printf("failed to check which comments were addressed: %v", err)
+	}
+	sentKeys := make(map[string]bool, len(sent))
+	for _, comment := range sent {
+		sentKeys[comment.key] = true
+	}
+	for _, comment := range pr.AllComments {
+		key := commentKey(comment)
+		comment.Sent = sentKeys[key]
+		comment.LikelyAddressed = addressed[key]
+	}
+}
+
+// remainingCommentsChecklist lists the instance's sent comments that no change has touched yet,
+// or "" if there are none.
+func (m *home) remainingCommentsChecklist(instance *session.Instance) string {
+	sent := m.sentComments[instance.Title]
+	if len(sent) == 0 {
+		return ""
+	}
+	addressed, err := m.addressedComments(instance)
+	if err != nil {
+		log.WarningLog.Printf("failed to check which comments were addressed: %v", err)
+		return ""
+	}
+
+	var remaining []string
+	for _, comment := range sent {
+		if addressed[comment.key] {
+			continue
+		}
+		location := "general"
+		if comment.path != "" {
+			location = comment.path
+			if comment.line > 0 {
+				location += fmt.Sprintf(":%d", comment.line)
+			}
+		}
+		remaining = append(remaining, fmt.Sprintf("☐ %s @%s: %s", location, comment.author, comment.summary))
+	}
+	if len(remaining) == 0 {
+		return fmt.Sprintf("All %d comments sent to the agent look addressed.", len(sent))
+	}
+	return fmt.Sprintf("%d of %d comments sent to the agent have no matching changes yet:\n%s",
+		len(remaining), len(sent), strings.Join(remaining, "\n"))
+}
+
+// firstLine returns the first non-empty line of text, truncated for checklists.
+func firstLine(text string) string {
+	for _, line := range strings.Split(text, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			if len(line) > 60 {
+				line = line[:57] + "..."
+			}
+			return line
+		}
+	}
+	return ""
+}
@@ -0,0 +1,94 @@
+package app
+
+import (
+	"claude-squad/session"
+	"claude-squad/session/git"
+	"claude-squad/ui"
+	"claude-squad/ui/overlay"
+	"fmt"
+	"path/filepath"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// samePath returns true if a and b name the same directory, following symlinks such as macOS's
+// /tmp, since git reports resolved paths.
+func samePath(a, b string) bool {
+	if filepath.Clean(a) == filepath.Clean(b) {
+		return true
+	}
+	resolvedA, errA := filepath.EvalSymlinks(a)
+	resolvedB, errB := filepath.EvalSymlinks(b)
+	return errA == nil && errB == nil && resolvedA == resolvedB
+}
+
+// unadoptedWorktrees returns the worktrees no instance runs in or owns the branch of.
+func unadoptedWorktrees(worktrees []git.Worktree, instances []*session.Instance) []git.Worktree {
+	var unadopted []git.Worktree
+	for _, worktree := range worktrees {
+		adopted := false
+		for _, instance := range instances {
+			if worktree.Branch != "" && instance.Branch == worktree.Branch {
+				adopted = true
+				break
+			}
+			if tree, err := instance.GetGitWorktree(); err == nil && samePath(tree.GetWorktreePath(), worktree.Path) {
+				adopted = true
+				break
+			}
+		}
+		if !adopted {
+			unadopted = append(unadopted, worktree)
+		}
+	}
+	return unadopted
+}
+
+// showAdoptWorktrees lists the repository's worktrees that were created outside claude-squad, to
+// adopt one as a new instance that runs the program in it where it is.
+func (m *home) showAdoptWorktrees() tea.Cmd {
+	if m.remoteHost != "" {
+		return m.notify(ui.ToastInfo, "Worktrees can't be adopted on remote hosts")
+	}
+	worktrees, err := git.ListWorktrees(m.repoPath)
+	if err != nil {
+		return m.handleError(err)
+	}
+	worktrees = unadoptedWorktrees(worktrees, m.list.GetInstances())
+	if len(worktrees) == 0 {
+		return m.notify(ui.ToastInfo, fmt.Sprintf("No worktrees to adopt in %s", filepath.Base(m.repoPath)))
+	}
+
+	items := make([]overlay.ListItem, len(worktrees))
+	for n, worktree := range worktrees {
+		title := worktree.Branch
+		if title == "" {
+			title = "(detached HEAD)"
+		}
+		items[n] = overlay.ListItem{Title: title, Description: worktree.Path}
+	}
+	return m.selectFromList("Adopt Worktree", items, func(idx int) tea.Cmd {
+		return m.adoptWorktree(worktrees[idx])
+	})
+}
+
+// adoptWorktree creates an instance for worktree and starts it there.
+func (m *home) adoptWorktree(worktree git.Worktree) tea.Cmd {
+	if err := m.checkNewInstance(m.program); err != nil {
+		return m.handleError(err)
+	}
+
+	taken := make(map[string]bool)
+	for _, instance := range m.list.GetInstances() {
+		taken[instance.Title] = true
+	}
+	title := uniqueTitle(titleFromBranch(worktree.Branch, m.appConfig.BranchPrefix), taken)
+	instance, err := session.NewAdoptedInstance(session.InstanceOptions{
+		Title:   title,
+		Path:    m.repoPath,
+		Program: m.program,
+		AutoYes: m.autoYes,
+	}, worktree)
+	_, cmd := m.startSelectedInstance(instance, err)
+	return cmd
+}
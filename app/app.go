@@ -1,14 +1,21 @@
 package app
 
 import (
+	"claude-squad/approvals"
 	"claude-squad/config"
+	"claude-squad/diagnostics"
 	"claude-squad/keys"
 	"claude-squad/log"
+	"claude-squad/notify"
+	"claude-squad/scripting"
 	"claude-squad/session"
 	"claude-squad/session/git"
+	"claude-squad/session/tmux"
+	"claude-squad/share"
 	"claude-squad/ui"
 	"claude-squad/ui/overlay"
 	"context"
+	"errors"
 	"fmt"
 	"os"
 	"os/exec"
@@ -17,7 +24,10 @@ import (
 	"strconv"
 	"strings"
 	"time"
+	"unicode"
+	"unicode/utf8"
 
+	"github.com/atotto/clipboard"
 	"github.com/charmbracelet/bubbles/spinner"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
@@ -32,7 +42,15 @@ func Run(ctx context.Context, program string, autoYes bool) error {
 		tea.WithAltScreen(),
 		tea.WithMouseCellMotion(), // Mouse scroll
 	)
-	_, err := p.Run()
+	stopSignals := forwardSnapshotSignals(p)
+	model, err := p.Run()
+	stopSignals()
+	if h, ok := model.(*home); ok {
+		h.finishSnapshots()
+		if h.exitMessage != "" {
+			fmt.Println(h.exitMessage)
+		}
+	}
 	return err
 }
 
@@ -64,6 +82,48 @@ const (
 	stateGitStatus
 	// stateCommentDetail is the state when displaying full PR comment content.
 	stateCommentDetail
+	// stateCheckpoint is the state when naming a checkpoint note.
+	stateCheckpoint
+	// stateBranchImport is the state when selecting branches to import as paused instances.
+	stateBranchImport
+	// stateListSelect is the state when picking an item from a list overlay.
+	stateListSelect
+	// stateOutcome is the state when rating the outcome of a killed instance.
+	stateOutcome
+	// statePromptPreview is the state when previewing the prompt for a PR comment.
+	statePromptPreview
+	// statePromptQueue is the state when editing an instance's prompt queue.
+	statePromptQueue
+	// stateConflicts is the state when resolving the conflicts of a rebase.
+	stateConflicts
+	// stateBaseRef is the state when entering the commit or tag a new instance starts from.
+	stateBaseRef
+	// stateInstanceSettings is the state when editing the settings of an instance.
+	stateInstanceSettings
+	// stateAutoYesPolicy is the state when editing which prompts auto-yes confirms.
+	stateAutoYesPolicy
+	// stateCommitMessage is the state when editing the commit message of a push.
+	stateCommitMessage
+	// stateSearch is the state when searching across all instances.
+	stateSearch
+	// stateGroupName is the state when naming a new group or renaming one.
+	stateGroupName
+	// stateRepoPath is the state when entering the path of a repository to add.
+	stateRepoPath
+	// stateWorktreePath is the state when entering the path to move a worktree to.
+	stateWorktreePath
+	// stateBroadcast is the state when entering the filter and prompt of a broadcast.
+	stateBroadcast
+	// stateFilter is the state when typing a filter narrowing the list.
+	stateFilter
+	// stateWorkPrompt is the state when editing the prompt to resume work on a PR.
+	stateWorkPrompt
+	// stateRebaseLog is the state when showing the step log of an update with main.
+	stateRebaseLog
+	// stateInteractiveRebase is the state when editing the commits of a branch to rewrite.
+	stateInteractiveRebase
+	// stateDiffBase is the state when picking the branch, tag or commit the diff tab diffs against.
+	stateDiffBase
 )
 
 type home struct {
@@ -73,6 +133,10 @@ type home struct {
 
 	program string
 	autoYes bool
+	// repoPath is the repository new instances are created in
+	repoPath string
+	// remoteHost is the configured remote host new instances run on instead, if set
+	remoteHost string
 
 	// storage is the interface for saving/loading data to/from the app's state
 	storage *session.Storage
@@ -95,6 +159,9 @@ type home struct {
 
 	// promptAfterName tracks if we should enter prompt mode after naming
 	promptAfterName bool
+	// autoNamePending is true when the name step was skipped and the new instance is named
+	// from its prompt once entered
+	autoNamePending bool
 
 	// keySent is used to manage underlining menu items
 	keySent bool
@@ -114,8 +181,12 @@ type home struct {
 	menu *ui.Menu
 	// tabbedWindow displays the tabbed window with AI, diff, and terminal panes
 	tabbedWindow *ui.TabbedWindow
-	// errBox displays error messages
-	errBox *ui.ErrBox
+	// toastBox displays stacked notifications (errors, warnings, successes)
+	toastBox *ui.ToastBox
+	// scrollAccel speeds up line scrolling while a scroll key is held down
+	scrollAccel ui.ScrollAccelerator
+	// gitProgress displays progress of long-running git fetch/clone/push operations
+	gitProgress *ui.GitProgressBar
 	// global spinner instance. we plumb this down to where it's needed
 	spinner spinner.Model
 	// textInputOverlay handles text input with state
@@ -126,6 +197,100 @@ type home struct {
 	confirmationOverlay *overlay.ConfirmationOverlay
 	// branchSelectorOverlay displays branch selection interface
 	branchSelectorOverlay *overlay.BranchSelectorOverlay
+	// diffBaseOverlay picks the ref the diff tab diffs against
+	diffBaseOverlay *overlay.BranchSelectorOverlay
+	// branchImportOverlay displays the branch import wizard
+	branchImportOverlay *overlay.BranchImportOverlay
+	// listSelectorOverlay displays a single-choice list; onListSelect handles the chosen index
+	listSelectorOverlay *overlay.ListSelectorOverlay
+	onListSelect        func(idx int) tea.Cmd
+	// searchOverlay searches across all instances; onSearchSelect jumps to the chosen result
+	searchOverlay  *overlay.SearchOverlay
+	onSearchSelect func(idx int) tea.Cmd
+
+	// outcomeOverlay asks for the outcome of an instance being killed; pendingKill is the kill
+	// that runs once the outcome is saved or skipped
+	outcomeOverlay *overlay.OutcomeOverlay
+	pendingKill    *pendingKill
+
+	// promptQueueOverlay edits the selected instance's prompt queue
+	promptQueueOverlay *overlay.PromptQueueOverlay
+
+	// lastMetadataCheck is when each instance was last checked for changes, to check it again
+	// at its tick rate
+	lastMetadataCheck map[*session.Instance]time.Time
+	// operations are the background operations in flight, shown in the menu
+	operations operations
+	// tabInstance is the instance whose tab is shown, to switch tabs when another is selected
+	tabInstance *session.Instance
+	// scripts are the user's scripts handling events, and scriptErrors why some failed to load
+	scripts      *scripting.Engine
+	scriptErrors []error
+
+	// instanceSettingsOverlay edits the settings of settingsInstance
+	instanceSettingsOverlay *overlay.InstanceSettingsOverlay
+	settingsInstance        *session.Instance
+	// autoYesPolicyOverlay edits the auto-yes policy
+	autoYesPolicyOverlay *overlay.AutoYesPolicyOverlay
+
+	// pushInstance is the instance whose push the commit message prompt is for
+	pushInstance *session.Instance
+	// groupInstance is the instance moved to the group being named, and renamingGroup the group
+	// being renamed instead
+	groupInstance *session.Instance
+	renamingGroup string
+	// movingInstance is the instance whose worktree the path prompt is for
+	movingInstance *session.Instance
+	// broadcastFilter is the last filter a prompt was broadcast with, and broadcastTargets the
+	// instances matching it while their prompt is entered
+	broadcastFilter  string
+	broadcastTargets []*session.Instance
+	// workPromptInstance is the instance the PR work prompt being edited is for
+	workPromptInstance *session.Instance
+	// autoRebaseFailures are the instances whose automatic rebase onto main last failed
+	autoRebaseFailures map[*session.Instance]autoRebaseFailure
+	// lastCoordinatorDigest is the digest last sent to the coordinator, not sent again unchanged
+	lastCoordinatorDigest string
+	// approvals is the inbox confirmations are published to for remote approval, nil when off
+	approvals *approvals.Inbox
+	// approvalOverlay is the confirmation published as approvalRequest
+	approvalOverlay *overlay.ConfirmationOverlay
+	approvalRequest approvals.Request
+
+	// conflictOverlay resolves the conflicts of conflictInstance's rebase
+	conflictOverlay  *overlay.ConflictOverlay
+	conflictInstance *session.Instance
+
+	// notifier posts instance events to the configured webhook
+	notifier *notify.Notifier
+	// busySince records when each instance's agent started its current task, by title
+	busySince map[string]time.Time
+	// agentWorking records the instances whose agent is working, by title, to notice when it
+	// finishes; finishedOrder holds the instances that finished, most recent last
+	agentWorking  map[string]bool
+	finishedOrder []*session.Instance
+	// pendingResolves holds the review threads to resolve once each instance's agent has finished
+	// its fix prompt, by title
+	pendingResolves map[string]*pendingThreadResolve
+
+	// exitMessage is printed after the program exits
+	exitMessage string
+	// cleanExit is true if the user quit, so the UI snapshot is removed rather than kept to
+	// restore after a crash
+	cleanExit bool
+	// storageChanges receives when the stored instances change, so those other claude-squad
+	// processes add or remove show up here too
+	storageChanges <-chan struct{}
+	// undoStack holds the destructive operations that can be undone, the last one on top
+	undoStack []undoEntry
+
+	// sentComments are the PR comments sent to each instance's agent, keyed by instance title
+	sentComments map[string][]sentComment
+
+	// policy holds the administrator's rules, which override appConfig
+	policy *config.Policy
+	// authIssues are the credential problems found by the last check, shown as a banner
+	authIssues []git.AuthIssue
 	// prReviewOverlay handles PR comment review
 	prReviewOverlay *ui.PRReviewModel
 	// historyOverlay displays scrollable history content
@@ -154,11 +319,29 @@ type home struct {
 	rebaseBranchName string
 	// rebaseOriginalSHA is the commit SHA before rebase started
 	rebaseOriginalSHA string
+	// rebaseLogs keep the step log of each instance's last update with main; rebaseLog is the
+	// one shown
+	rebaseLogs map[*session.Instance]*overlay.StepLogOverlay
+	rebaseLog  *overlay.StepLogOverlay
+	// interactiveRebaseOverlay edits the commits of interactiveRebaseInstance's branch
+	interactiveRebaseOverlay  *overlay.InteractiveRebaseOverlay
+	interactiveRebaseInstance *session.Instance
 }
 
 func newHome(ctx context.Context, program string, autoYes bool) *home {
 	// Load application config
 	appConfig := config.LoadConfig()
+	policy, err := config.LoadPolicy()
+	if err != nil {
+		fmt.Printf("Failed to load policy: %v\n", err)
+		os.Exit(1)
+	}
+
+	// Snapshot storage before this run touches it, so there is a restore point from before
+	// any state migration
+	if _, err := config.CreateBackupIfChanged("startup", appConfig.BackupCount); err != nil {
+		log.WarningLog.Printf("failed to back up storage: %v", err)
+	}
 
 	// Load application state
 	appState := config.LoadState()
@@ -170,7 +353,7 @@ func newHome(ctx context.Context, program string, autoYes bool) *home {
 	}
 
 	// Initialize storage
-	storage, err := session.NewStorage(appState)
+	storage, err := session.OpenStorage(appConfig.StorageBackend, appState)
 	if err != nil {
 		fmt.Printf("Failed to initialize storage: %v\n", err)
 		os.Exit(1)
@@ -180,15 +363,28 @@ func newHome(ctx context.Context, program string, autoYes bool) *home {
 	updateChecker := NewUpdateChecker()
 	updateChecker.StartBackgroundCheck()
 
+	tmux.StartResourceMonitor(ctx, resourceSampleInterval)
+	git.SetCommitHistoryDepth(appConfig.CommitHistoryDepth)
+
 	menu := ui.NewMenu()
 	menu.SetUpdateChecker(updateChecker)
 
+	notifier := notify.New(appConfig.WebhookURL)
+	testPane := ui.NewTestPane(appConfig)
+	testPane.SetOnTestsFailed(func(instance *session.Instance, failedFiles []string) {
+		if !instance.MuteNotifications {
+			go postEvent(notifier, instanceEvent(notify.EventTestsFailed, instance,
+				fmt.Sprintf("Tests failed in %d file(s)", len(failedFiles))))
+		}
+	})
+
 	h := &home{
 		ctx:           ctx,
 		spinner:       spinner.New(spinner.WithSpinner(spinner.MiniDot)),
 		menu:          menu,
-		tabbedWindow:  ui.NewTabbedWindow(ui.NewPreviewPane(), ui.NewDiffPane(), ui.NewTerminalPane(), ui.NewJestPane(appConfig)),
-		errBox:        ui.NewErrBox(),
+		tabbedWindow:  ui.NewTabbedWindow(ui.NewPreviewPane(), ui.NewDiffPane(), ui.NewTerminalPane(), testPane),
+		toastBox:      ui.NewToastBox(),
+		gitProgress:   ui.NewGitProgressBar(),
 		storage:       storage,
 		appConfig:     appConfig,
 		program:       program,
@@ -196,8 +392,20 @@ func newHome(ctx context.Context, program string, autoYes bool) *home {
 		state:         stateDefault,
 		appState:      appState,
 		updateChecker: updateChecker,
+		notifier:      notifier,
+		policy:        policy,
 	}
 	h.list = ui.NewList(&h.spinner, autoYes)
+	h.scripts, h.scriptErrors = loadScripts()
+	if appConfig.RemoteApprovals {
+		if h.approvals, err = openApprovals(); err != nil {
+			log.WarningLog.Printf("remote approvals are off: %v", err)
+		}
+	}
+	h.repoPath = "."
+	if root, err := git.FindRepoRoot("."); err == nil {
+		h.repoPath = root
+	}
 
 	// Load saved instances
 	instances, err := storage.LoadInstances()
@@ -214,6 +422,13 @@ func newHome(ctx context.Context, program string, autoYes bool) *home {
 			instance.AutoYes = true
 		}
 	}
+	groups, err := storage.LoadGroups()
+	if err != nil {
+		log.ErrorLog.Printf("failed to load groups: %v", err)
+	}
+	h.list.SetGroups(groups)
+	h.offerSnapshotRestore()
+	h.watchStorage()
 
 	return h
 }
@@ -231,12 +446,34 @@ func (m *home) updateHandleWindowSizeEvent(msg tea.WindowSizeMsg) {
 
 	// Menu takes 10% of height, list and window take 90%
 	contentHeight := int(float32(msg.Height) * 0.9)
-	menuHeight := msg.Height - contentHeight - 1     // minus 1 for error box
-	m.errBox.SetSize(int(float32(msg.Width)*0.9), 1) // error box takes 1 row
+	if len(m.authIssues) > 0 {
+		contentHeight-- // auth banner
+	}
+	menuHeight := msg.Height - contentHeight - toastRows
+	m.toastBox.SetSize(int(float32(msg.Width)*0.9), toastRows)
+	m.gitProgress.SetWidth(int(float32(msg.Width) * 0.9))
 
 	m.tabbedWindow.SetSize(tabsWidth, contentHeight)
 	m.list.SetSize(listWidth, contentHeight)
 
+	if m.outcomeOverlay != nil {
+		m.outcomeOverlay.SetSize(int(float32(msg.Width)*0.6), 0)
+	}
+	if m.rebaseLog != nil {
+		m.rebaseLog.SetSize(int(float32(msg.Width)*0.7), int(float32(msg.Height)*0.7))
+	}
+	if m.interactiveRebaseOverlay != nil {
+		m.interactiveRebaseOverlay.SetSize(int(float32(msg.Width)*0.7), int(float32(msg.Height)*0.7))
+	}
+	if m.searchOverlay != nil {
+		m.searchOverlay.SetSize(int(float32(msg.Width)*0.7), int(float32(msg.Height)*0.7))
+	}
+	if m.listSelectorOverlay != nil {
+		m.listSelectorOverlay.SetSize(int(float32(msg.Width)*0.6), int(float32(msg.Height)*0.6))
+	}
+	if m.branchImportOverlay != nil {
+		m.branchImportOverlay.SetSize(int(float32(msg.Width)*0.8), int(float32(msg.Height)*0.8))
+	}
 	if m.textInputOverlay != nil {
 		m.textInputOverlay.SetSize(int(float32(msg.Width)*0.6), int(float32(msg.Height)*0.4))
 	}
@@ -247,6 +484,18 @@ func (m *home) updateHandleWindowSizeEvent(msg tea.WindowSizeMsg) {
 	if m.historyOverlay != nil {
 		m.historyOverlay.SetSize(int(float32(msg.Width)*0.9), int(float32(msg.Height)*0.9))
 	}
+	if m.promptQueueOverlay != nil {
+		m.promptQueueOverlay.SetSize(int(float32(msg.Width)*0.6), int(float32(msg.Height)*0.6))
+	}
+	if m.instanceSettingsOverlay != nil {
+		m.instanceSettingsOverlay.SetSize(int(float32(msg.Width)*0.6), 0)
+	}
+	if m.autoYesPolicyOverlay != nil {
+		m.autoYesPolicyOverlay.SetSize(int(float32(msg.Width)*0.6), 0)
+	}
+	if m.conflictOverlay != nil {
+		m.conflictOverlay.SetSize(int(float32(msg.Width)*0.85), int(float32(msg.Height)*0.85))
+	}
 
 	previewWidth, previewHeight := m.tabbedWindow.GetPreviewSize()
 	if err := m.list.SetSessionPreviewSize(previewWidth, previewHeight); err != nil {
@@ -260,11 +509,25 @@ func (m *home) Init() tea.Cmd {
 	// update the spinner, which sends a new spinner.TickMsg. I think this lasts forever lol.
 	return tea.Batch(
 		m.spinner.Tick,
+		previewTick(m.previewInterval()),
+		tickUpdateMetadata(m.tickRates().MetadataTick()),
 		func() tea.Msg {
-			time.Sleep(100 * time.Millisecond)
-			return previewTickMsg{}
+			// Give the UI a moment to settle before the first round of gh calls
+			time.Sleep(2 * time.Second)
+			return prStatusTickMsg{}
 		},
-		tickUpdateMetadataCmd,
+		m.scheduleBackup(),
+		m.scheduleSnapshot(),
+		m.schedulePRCommentPoll(),
+		m.scheduleMainWatch(),
+		m.scheduleCoordinatorDigest(m.coordinatorDigestInterval()),
+		m.scheduleApprovals(),
+		m.waitForStorageChange(),
+		m.scheduleAutoPause(),
+		m.checkAuth(true),
+		m.reportPolicyViolations(),
+		m.reportScriptErrors(),
+		m.reportSessionReconcile(),
 	)
 }
 
@@ -278,6 +541,9 @@ func (m *home) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			// Check if selection is complete
 			if m.branchSelectorOverlay.IsSelected() {
 				selectedBranch := m.branchSelectorOverlay.SelectedBranch()
+				if ref := m.branchSelectorOverlay.SelectedRef(); ref != "" {
+					return m.createInstanceFromRef(ref)
+				}
 				if selectedBranch == "" {
 					// User cancelled
 					m.state = stateDefault
@@ -310,13 +576,13 @@ func (m *home) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		switch msg.(type) {
 		case ui.PRReviewCompleteMsg:
 			// Handle accepted comments
-			acceptedComments := msg.(ui.PRReviewCompleteMsg).AcceptedComments
+			completeMsg := msg.(ui.PRReviewCompleteMsg)
 			m.state = stateDefault
 			m.prReviewOverlay = nil
 
-			// Process accepted comments with Claude
-			if len(acceptedComments) > 0 {
-				return m, m.processAcceptedComments(acceptedComments)
+			// Send the accepted comments to the agent as one prompt
+			if len(completeMsg.AcceptedComments) > 0 {
+				return m, m.reviewAcceptedComments(completeMsg.PR, completeMsg.AcceptedComments)
 			}
 			return m, nil
 		case ui.PRReviewCancelMsg:
@@ -331,33 +597,66 @@ func (m *home) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			// We'll set the size in the next WindowSizeMsg
 			m.state = stateCommentDetail
 			return m, tea.WindowSize()
+		case ui.PRReviewPreviewPromptMsg:
+			m.textOverlay = overlay.NewTextOverlay(m.previewCommentPrompt(msg.(ui.PRReviewPreviewPromptMsg).Comment))
+			width, height := m.calculateOverlayDimensions()
+			m.textOverlay.SetSize(width, height)
+			m.state = statePromptPreview
+			return m, nil
 		case ui.PRRequestResolveConfirmationMsg:
 			return m.requestResolveAllConversationsConfirmation()
+		case ui.PRReviewReplyMsg:
+			return m, tea.Batch(cmd, m.postPRReply(msg.(ui.PRReviewReplyMsg)))
+		case ui.PRReviewReplyPostedMsg:
+			if err := msg.(ui.PRReviewReplyPostedMsg).Err; err != nil {
+				return m, m.handleError(fmt.Errorf("failed to reply to PR comment: %w", err))
+			}
 		}
 
 		return m, cmd
 	}
 
 	switch msg := msg.(type) {
-	case hideErrMsg:
-		m.errBox.Clear()
+	case hideToastMsg:
+		m.toastBox.Dismiss(msg.id)
+	case gitProgressMsg:
+		m.gitProgress.Update(msg.title, msg.progress)
+		if op := m.operations.get(msg.op); op != nil {
+			op.progress = gitProgressText(msg.progress)
+		}
+		if msg.log != nil {
+			msg.log.SetProgress(gitProgressText(msg.progress))
+		}
+		return m, msg.next
+	case gitStepMsg:
+		msg.log.AddStep(msg.step)
+		return m, msg.next
+	case operationDoneMsg:
+		return m, m.finishOperation(msg)
+	case gitProgressDoneMsg:
+		m.gitProgress.Finish(msg.title)
+		return m, nil
 	case previewTickMsg:
 		cmd := m.instanceChanged()
-		return m, tea.Batch(
-			cmd,
-			func() tea.Msg {
-				time.Sleep(100 * time.Millisecond)
-				return previewTickMsg{}
-			},
-		)
+		return m, tea.Batch(cmd, previewTick(m.previewInterval()))
 	case keyupMsg:
 		m.menu.ClearKeydown()
 		return m, nil
 	case tickUpdateMetadataMessage:
-		for _, instance := range m.list.GetInstances() {
-			if !instance.Started() || instance.Paused() {
+		var queueCmds []tea.Cmd
+		rates, now := m.tickRates(), time.Now()
+		instances := m.list.GetInstances()
+		m.forgetMetadataChecks(instances)
+		for _, instance := range instances {
+			if !instance.Started() || instance.Paused() || !m.metadataDue(instance, now, rates) {
+				continue
+			}
+			// Broken instances wait for the user to repair them instead of failing on every tick
+			if broken, cmd := m.checkWorktree(instance); broken {
+				queueCmds = append(queueCmds, cmd)
 				continue
 			}
+			previous := instance.Status
 			updated, prompt := instance.HasUpdated()
 			if updated {
 				instance.SetStatus(session.Running)
@@ -371,8 +670,90 @@ func (m *home) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			if err := instance.UpdateDiffStats(); err != nil {
 				log.WarningLog.Printf("could not update diff stats: %v", err)
 			}
+			instance.SampleActivity()
+			instance.UpdateUsage()
+			instance.UpdateContainerStatus()
+			instance.UpdateServiceStates()
+			queueCmds = append(queueCmds, m.trackReadiness(instance), m.summarizeFinishedRun(instance), m.dispatchQueuedPrompt(instance),
+				m.resolveThreadsWhenDone(instance), m.recordWorkTime(instance), m.watchFinished(instance))
+			if instance.Status != previous {
+				event := scriptEvent(scripting.EventStatusChanged, instance, "")
+				event.PreviousStatus = statusLabels[previous]
+				queueCmds = append(queueCmds, m.runScripts(event))
+			}
 		}
-		return m, tickUpdateMetadataCmd
+		return m, tea.Batch(append(queueCmds, tickUpdateMetadata(rates.MetadataTick()))...)
+	case backupTickMsg:
+		keep := m.appConfig.BackupCount
+		return m, tea.Batch(func() tea.Msg {
+			if _, err := config.CreateBackupIfChanged("scheduled", keep); err != nil {
+				log.WarningLog.Printf("scheduled storage backup failed: %v", err)
+			}
+			return nil
+		}, m.scheduleBackup())
+	case snapshotTickMsg:
+		m.saveSnapshot()
+		return m, tea.Batch(m.scheduleSnapshot(), m.saveScrollback())
+	case snapshotSignalMsg:
+		m.saveSnapshot()
+		return m, m.notify(ui.ToastInfo, "Saved a snapshot of the UI state")
+	case restoreSnapshotMsg:
+		return m, m.restoreSnapshot(msg.snapshot)
+	case undoneMsg:
+		return m, m.handleUndone(msg)
+	case storageChangedMsg:
+		return m, tea.Batch(m.refreshFromStorage(), m.waitForStorageChange())
+	case autoPauseTickMsg:
+		return m, tea.Batch(m.autoPauseIdle(time.Time(msg)), m.scheduleAutoPause())
+	case authCheckTickMsg:
+		return m, m.checkAuth(true)
+	case authCheckNowMsg:
+		return m, m.checkAuth(false)
+	case authStatusMsg:
+		return m, m.handleAuthStatus(msg)
+	case outcomePromptMsg:
+		m.pendingKill = msg.kill
+		m.outcomeOverlay = overlay.NewOutcomeOverlay(
+			fmt.Sprintf("How did '%s' go?", msg.kill.instance.Title), session.OutcomeResults, session.OutcomeEfforts)
+		m.state = stateOutcome
+		return m, tea.WindowSize()
+	case reauthMsg:
+		return m, m.reauthenticate()
+	case prStatusTickMsg:
+		// Refresh PR badges in the background; each instance caches its own status
+		instances := m.list.GetInstances()
+		return m, func() tea.Msg {
+			for _, instance := range instances {
+				if err := instance.UpdatePRStatus(); err != nil {
+					log.WarningLog.Printf("could not update PR status for %s: %v", instance.Title, err)
+				}
+				if err := instance.UpdateCIChecks(); err != nil {
+					log.WarningLog.Printf("could not update CI checks for %s: %v", instance.Title, err)
+				}
+			}
+			time.Sleep(prStatusPollInterval)
+			return prStatusTickMsg{}
+		}
+	case ciChecksMsg:
+		return m, m.showCIChecks(msg)
+	case clipboardMsg:
+		return m, m.showClipboard(msg)
+	case prWorkPromptMsg:
+		return m, m.showPRWorkPrompt(msg)
+	case prCommentPollTickMsg:
+		return m, m.pollPRComments()
+	case mainWatchTickMsg:
+		return m, m.watchMain()
+	case mainWatchResultMsg:
+		return m, m.handleMainWatchResult(msg)
+	case coordinatorTickMsg:
+		return m, m.sendCoordinatorDigest()
+	case approvalsTickMsg:
+		return m.syncApprovals()
+	case diffBaseRefsMsg:
+		return m, m.showDiffBaseSelector(msg)
+	case prCommentsArrivedMsg:
+		return m, m.handlePRCommentsArrived(msg)
 	case tea.MouseMsg:
 		// Handle mouse wheel events for scrolling the diff/preview pane
 		if msg.Action == tea.MouseActionPress {
@@ -412,6 +793,8 @@ func (m *home) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case error:
 		// Handle errors from confirmation actions
 		return m, m.handleError(msg)
+	case pushCompleteMsg:
+		return m, m.runScripts(scriptEvent(scripting.EventPushComplete, msg.instance, ""))
 	case instanceChangedMsg:
 		// Handle instance changed after confirmation action
 		return m, m.instanceChanged()
@@ -422,14 +805,34 @@ func (m *home) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.list.Kill()
 			return m, m.handleError(msg.err)
 		}
+		// Send the prompt the instance was created with, if any, once the agent is ready for it
+		var sendPrompt tea.Cmd
+		if prompt := msg.instance.Prompt; prompt != "" {
+			msg.instance.Prompt = ""
+			sendPrompt = sendStartupPrompt(msg.instance, prompt)
+		}
 		// Show help screen on successful creation
 		m.showHelpScreen(helpStart(msg.instance), nil)
-		return m, m.instanceChanged()
+		return m, tea.Batch(m.instanceChanged(), sendPrompt, m.warnRenamedBranch(msg.instance),
+			m.runScripts(scriptEvent(scripting.EventInstanceCreated, msg.instance, "")))
 	case instanceDeletedMsg:
 		// Handle instance deletion completion
 		if msg.err != nil {
 			return m, m.handleError(msg.err)
 		}
+		if msg.removed != nil {
+			m.list.RemoveInstance(msg.removed)
+		}
+		if msg.undo != nil {
+			m.pushUndo(*msg.undo)
+			if msg.notice == "" {
+				msg.notice = fmt.Sprintf("Killed '%s'", msg.title)
+			}
+			msg.notice += fmt.Sprintf("; %s undoes it", undoKey())
+		}
+		if msg.notice != "" {
+			return m, tea.Batch(m.instanceChanged(), m.showSuccess(msg.notice))
+		}
 		return m, m.instanceChanged()
 	case startRebaseMsg:
 		// Handle the actual rebase after confirmation
@@ -463,32 +866,110 @@ func (m *home) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, m.handleError(fmt.Errorf("failed to get current commit: %w", err))
 		}
 
-		// Perform the rebase
-		if err := worktree.RebaseWithMain(); err != nil {
+		// Perform the rebase in the background, streaming its steps and fetch/clone progress to
+		// its log
+		strategy := msg.strategy
+		stepLog, showLog := m.startRebaseLog(instance, strategy)
+		return m, tea.Batch(showLog, m.runWithGitSteps("rebase", instance.Title, worktree, stepLog, func(wt *git.GitWorktree) tea.Msg {
+			err := wt.MergeWithMain(strategy)
+			return rebaseFinishedMsg{
+				instance:    instance,
+				branchName:  wt.GetBranchName(),
+				originalSHA: currentSHA,
+				strategy:    strategy,
+				err:         err,
+			}
+		}))
+	case patchResultMsg:
+		if msg.err != nil {
+			return m, m.handleError(msg.err)
+		}
+		if len(msg.conflicts) > 0 {
+			return m, m.notify(ui.ToastWarning, fmt.Sprintf("%s with conflicts in: %s", msg.message, strings.Join(msg.conflicts, ", ")))
+		}
+		if msg.path != "" {
+			if err := m.copyResult(msg.instance, "patch path", msg.path); err != nil {
+				return m, m.handleError(err)
+			}
+		}
+		return m, m.showSuccess(msg.message)
+	case checkpointResultMsg:
+		if msg.err != nil {
+			return m, m.handleError(msg.err)
+		}
+		checkpoint := msg.instance.AddCheckpoint(msg.name, msg.summary)
+		if err := m.storage.SaveInstances(m.list.GetInstances()); err != nil {
+			return m, m.handleError(err)
+		}
+		return m, m.showSuccess(fmt.Sprintf("Saved checkpoint '%s' for '%s'", checkpoint.Name, msg.instance.Title))
+	case shareResultMsg:
+		if msg.err != nil {
+			return m, m.handleError(msg.err)
+		}
+		return m, m.showShareResult(msg)
+	case metricsExportedMsg:
+		return m, m.handleMetricsExported(msg)
+	case diagnosticsResultMsg:
+		if msg.err != nil {
+			return m, m.handleError(msg.err)
+		}
+		_ = clipboard.WriteAll(msg.path)
+		return m, m.showSuccess(fmt.Sprintf("Diagnostics bundle written to %s (path copied to clipboard)", msg.path))
+	case rebaseFinishedMsg:
+		// The step log shows the outcome when it's on screen, rather than a toast
+		logShown := m.rebaseLogShown(msg.instance)
+		if stepLog := m.rebaseLogs[msg.instance]; stepLog != nil {
+			stepLog.Finish(msg.err)
+		}
+		if msg.err != nil {
+			if errors.Is(msg.err, git.ErrAborted) {
+				return m, m.notify(ui.ToastInfo, fmt.Sprintf("Aborted updating %s with main; its backup branch is kept", msg.branchName))
+			}
+			// Conflicts left in the worktree are resolved in the conflict overlay
+			if rebaseErr, ok := msg.err.(*git.RebaseConflictError); ok && rebaseErr.TempDir == "" {
+				return m, tea.Batch(
+					m.notify(ui.ToastWarning, fmt.Sprintf("Rebase of %s stopped on conflicts", msg.branchName)),
+					m.showConflicts(msg.instance),
+				)
+			}
 			// Check if this is a rebase conflict error that needs polling
-			if rebaseErr, ok := err.(*git.RebaseConflictError); ok {
-				log.InfoLog.Printf("Rebase conflict detected for branch %s", worktree.GetBranchName())
+			if rebaseErr, ok := msg.err.(*git.RebaseConflictError); ok {
+				log.InfoLog.Printf("Rebase conflict detected for branch %s", msg.branchName)
 
 				// Display the error with instructions
-				errorCmd := m.handleError(fmt.Errorf("Rebase conflicts detected. IDE opened at %s\nResolve conflicts, complete rebase, and push to remote", rebaseErr.TempDir))
+				conflictErr := fmt.Errorf("Rebase conflicts detected. IDE opened at %s\nResolve conflicts, complete rebase, and push to remote", rebaseErr.TempDir)
+				var errorCmd tea.Cmd
+				if logShown {
+					m.reportRebaseError(conflictErr)
+				} else {
+					errorCmd = m.handleError(conflictErr)
+				}
 
 				// Set rebase in progress state
 				m.rebaseInProgress = true
-				m.rebaseInstance = instance
-				m.rebaseBranchName = worktree.GetBranchName()
-				m.rebaseOriginalSHA = currentSHA
+				m.rebaseInstance = msg.instance
+				m.rebaseBranchName = msg.branchName
+				m.rebaseOriginalSHA = msg.originalSHA
 
 				// Start polling the remote for changes
-				pollingCmd := m.createRemotePollingCmd(worktree.GetBranchName(), currentSHA)
+				pollingCmd := m.createRemotePollingCmd(msg.branchName, msg.originalSHA)
 
 				// Return both commands so error displays AND polling starts
 				return m, tea.Batch(errorCmd, pollingCmd)
 			}
-			return m, m.handleError(err)
+			if logShown {
+				m.reportRebaseError(msg.err)
+				return m, nil
+			}
+			return m, m.handleError(msg.err)
 		}
 
 		// Success
-		return m, m.instanceChanged()
+		return m, tea.Batch(
+			m.instanceChanged(),
+			m.sendInstanceEvent(notify.EventRebaseComplete, msg.instance,
+				mergeSummary(msg.strategy, msg.branchName)),
+		)
 	case startGitResetMsg:
 		// Handle the actual git reset after confirmation
 		if m.pendingResetInstance == nil {
@@ -508,26 +989,29 @@ func (m *home) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		// Get branch name before reset
 		branchName := worktree.GetBranchName()
 
+		// Record the commit and uncommitted changes the reset discards, to undo it
+		state, stateErr := worktree.SaveState()
+		if stateErr != nil {
+			log.WarningLog.Printf("the reset of '%s' can't be undone: %v", instance.Title, stateErr)
+		}
+
 		// Perform the reset
 		if err := worktree.ResetToOrigin(); err != nil {
 			return m, m.handleError(err)
 		}
 
-		// Show success message in the status bar
-		successMsg := fmt.Sprintf("✓ Git reset for branch %s completed successfully", branchName)
-		m.errBox.SetError(fmt.Errorf(successMsg))
-
-		// Also add to log for history
-		timestamp := time.Now().Format("15:04:05")
-		m.errorLog = append(m.errorLog, fmt.Sprintf("[%s] %s", timestamp, successMsg))
-
-		// Refresh instances and hide message after a delay
+		message := fmt.Sprintf("Git reset for branch %s completed successfully", branchName)
+		if stateErr == nil {
+			m.pushUndo(undoEntry{
+				description: fmt.Sprintf("reset of '%s' to origin/%s", instance.Title, worktree.PushBranch()),
+				reset:       instance,
+				resetState:  state,
+			})
+			message += fmt.Sprintf("; %s undoes it", undoKey())
+		}
 		return m, tea.Batch(
 			m.instanceChanged(),
-			func() tea.Msg {
-				time.Sleep(3 * time.Second)
-				return hideErrMsg{}
-			},
+			m.showSuccess(message),
 		)
 
 	case remotePollingMsg:
@@ -570,16 +1054,18 @@ func (m *home) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 
 			// Clear rebase state
+			rebased := m.rebaseInstance
 			m.rebaseInProgress = false
 			m.rebaseInstance = nil
 			m.rebaseBranchName = ""
 			m.rebaseOriginalSHA = ""
 
-			// Show success
-			timestamp := time.Now().Format("15:04:05")
-			m.errorLog = append(m.errorLog, fmt.Sprintf("[%s] Rebase completed successfully", timestamp))
-
-			return m, m.instanceChanged()
+			return m, tea.Batch(
+				m.instanceChanged(),
+				m.showSuccess(fmt.Sprintf("Rebase of %s completed successfully", msg.branchName)),
+				m.sendInstanceEvent(notify.EventRebaseComplete, rebased,
+					fmt.Sprintf("Rebase of %s completed after resolving conflicts", msg.branchName)),
+			)
 		}
 
 		// Continue polling
@@ -588,19 +1074,98 @@ func (m *home) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		var cmd tea.Cmd
 		m.spinner, cmd = m.spinner.Update(msg)
 		return m, cmd
+	case suggestedTestsMsg:
+		return m, m.handleSuggestedTests(msg)
+	case suggestedTestsWrittenMsg:
+		return m, m.handleSuggestedTestsWritten(msg)
+	case newInstanceMsg:
+		if msg.seed != session.SeedNone {
+			// The changes go to the configured program, the choice of another is skipped
+			return m.startNewInstance(msg.promptAfterName, msg.baseRef, m.program, msg.seed)
+		}
+		return m.chooseProgram(msg.promptAfterName, msg.baseRef)
+	case inspectArchiveMsg:
+		return m, m.inspectArchive(msg.archive)
+	case restoreArchiveMsg:
+		return m, m.restoreArchive(msg.archive)
+	case mergeToolDoneMsg:
+		return m, m.handleMergeToolDone(msg)
+	case groupActionMsg:
+		return m, m.handleGroupAction(msg)
+	case searchDocsMsg:
+		// Another overlay may have been opened while the content was captured
+		if m.state != stateDefault {
+			return m, nil
+		}
+		return m, m.showSearch(msg.docs)
+	case gitCommandsMsg:
+		msg.overlay.SetCommands(msg.commands)
+		return m, nil
+	case historyExportedMsg:
+		return m, m.handleHistoryExported(msg)
+	case branchFixedMsg:
+		return m, m.handleBranchFixed(msg)
+	case rebaseStepsMsg:
+		return m, m.showInteractiveRebase(msg)
+	case commitsRewrittenMsg:
+		return m, m.handleCommitsRewritten(msg)
+	case worktreeRepairedMsg:
+		return m, m.handleWorktreeRepaired(msg)
+	case backupBranchMsg:
+		return m, tea.Batch(m.showSuccess(msg.message), m.instanceChanged())
+	case serviceMsg:
+		if err := m.storage.SaveInstances(m.list.GetInstances()); err != nil {
+			return m, m.handleError(err)
+		}
+		return m, m.showSuccess(msg.message)
+	case serviceOutputMsg:
+		return m, m.showServiceOutput(msg)
+	case broadcastDoneMsg:
+		return m, m.showBroadcastReport(msg)
+	case pushMessageMsg:
+		return m, m.showPushMessagePrompt(msg)
+	case baseRefPromptMsg:
+		return m, m.showBaseRefPrompt(msg)
+	case processCommentsMsg:
+		return m, m.handleProcessComments(msg)
 	case allCommentsProcessedMsg:
 		// Comments have been processed, return to default state
 		m.state = stateDefault
 		m.textOverlay = nil
 
-		// Show success message
-		// Note: Using error box for now to show success message
-		successErr := fmt.Errorf("✓ PR comments processed successfully!")
-		m.errBox.SetError(successErr)
-		return m, func() tea.Msg {
-			time.Sleep(3 * time.Second)
-			return hideErrMsg{}
+		if msg.err != nil {
+			return m, m.handleError(msg.err)
+		}
+		if len(msg.threads) > 0 {
+			if m.pendingResolves == nil {
+				m.pendingResolves = make(map[string]*pendingThreadResolve)
+			}
+			m.pendingResolves[msg.instance] = &pendingThreadResolve{
+				pr:           msg.pr,
+				worktreePath: msg.worktreePath,
+				threads:      msg.threads,
+				sentAt:       time.Now(),
+			}
+			return m, m.showSuccess(fmt.Sprintf("PR comments sent; %d review threads will be resolved when the agent is done", len(msg.threads)))
+		}
+		return m, m.showSuccess("PR comments sent to the agent")
+	case ui.PRReviewReplyPostedMsg:
+		// The review overlay was in the background or closed when the reply was posted
+		if m.prReviewOverlay != nil {
+			*m.prReviewOverlay, _ = m.prReviewOverlay.Update(msg)
+		}
+		if msg.Err != nil {
+			return m, m.handleError(fmt.Errorf("failed to reply to PR comment: %w", msg.Err))
+		}
+		return m, m.showSuccess(fmt.Sprintf("Replied to @%s", msg.Parent.Author))
+	case threadsResolvedMsg:
+		if msg.err != nil {
+			m.errorLog = append(m.errorLog, fmt.Sprintf("[%s] Failed to resolve review threads for '%s': %v",
+				time.Now().Format("15:04:05"), msg.instance, msg.err))
+			return m, m.notify(ui.ToastWarning, fmt.Sprintf("Resolved %d of %d review threads for '%s'",
+				msg.resolved, msg.total, msg.instance))
 		}
+		return m, m.showSuccess(fmt.Sprintf("Resolved %d review threads for '%s'", msg.resolved, msg.instance))
 	case resolveConversationsMsg:
 		// Show result of resolving conversations
 		m.state = stateDefault
@@ -611,43 +1176,18 @@ func (m *home) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.errorLog = append(m.errorLog, msg.logs...)
 		}
 
-		timestamp := time.Now().Format("15:04:05")
-		
 		if msg.err != nil {
-			// Log the error
-			m.errorLog = append(m.errorLog, fmt.Sprintf("[%s] Failed to resolve conversations: %v", timestamp, msg.err))
-			m.errBox.SetError(msg.err)
-		} else {
-			// Show success message
-			var message string
-			if msg.total == 0 {
-				message = "✓ No unresolved review threads found"
-				m.errorLog = append(m.errorLog, fmt.Sprintf("[%s] No unresolved review threads found on PR", timestamp))
-			} else if msg.resolved == msg.total {
-				message = fmt.Sprintf("✓ Successfully resolved all %d review threads", msg.total)
-				m.errorLog = append(m.errorLog, fmt.Sprintf("[%s] Successfully resolved all %d review threads", timestamp, msg.total))
-			} else {
-				message = fmt.Sprintf("✓ Resolved %d of %d review threads", msg.resolved, msg.total)
-				m.errorLog = append(m.errorLog, fmt.Sprintf("[%s] Resolved %d of %d review threads (some failed)", timestamp, msg.resolved, msg.total))
-			}
-			
-			successErr := fmt.Errorf(message)
-			m.errBox.SetError(successErr)
-		}
-		
-		// Keep log size manageable
-		if len(m.errorLog) > 100 {
-			m.errorLog = m.errorLog[len(m.errorLog)-100:]
+			return m, m.handleError(fmt.Errorf("failed to resolve conversations: %w", msg.err))
 		}
 
-		// Return command to hide error after delay only if no error
-		if msg.err == nil {
-			return m, func() tea.Msg {
-				time.Sleep(3 * time.Second)
-				return hideErrMsg{}
-			}
+		switch {
+		case msg.total == 0:
+			return m, m.notify(ui.ToastInfo, "No unresolved review threads found")
+		case msg.resolved == msg.total:
+			return m, m.showSuccess(fmt.Sprintf("Successfully resolved all %d review threads", msg.total))
+		default:
+			return m, m.notify(ui.ToastWarning, fmt.Sprintf("Resolved %d of %d review threads (some failed)", msg.resolved, msg.total))
 		}
-		return m, nil
 	case ui.PRResolveAllConversationsMsg:
 		// Resolve all conversations on the PR
 		m.state = stateHelp
@@ -662,8 +1202,7 @@ func (m *home) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m, m.resolveAllPRConversations()
 	case testStartedMsg:
 		// Show non-obtrusive message that tests are running
-		m.errBox.SetError(fmt.Errorf("Running Jest tests..."))
-		return m, nil
+		return m, m.notify(ui.ToastInfo, "Running tests...")
 	case testProgressMsg:
 		// Update test progress
 		var status string
@@ -672,8 +1211,7 @@ func (m *home) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		} else {
 			status = fmt.Sprintf("Tests complete: %d/%d passed, %d failed", msg.passed, msg.total, msg.failed)
 		}
-		m.errBox.SetError(fmt.Errorf(status))
-		return m, nil
+		return m, m.notify(ui.ToastInfo, status)
 	case testResultsMsg:
 		// Handle test results
 		if msg.err != nil {
@@ -695,21 +1233,11 @@ func (m *home) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				cmd.Start()
 			}
 			// Show brief status about failed tests with counts
-			m.errBox.SetError(fmt.Errorf("Tests completed: %d/%d passed, %d failed. Opening failed files in IDE (%s)",
+			return m, m.notify(ui.ToastWarning, fmt.Sprintf("Tests completed: %d/%d passed, %d failed. Opening failed files in IDE (%s)",
 				finalStats.passed, finalStats.total, finalStats.failed, ideCommand))
-		} else {
-			// All tests passed
-			m.errBox.SetError(fmt.Errorf("All tests passed! %d/%d test suites completed",
-				finalStats.passed, finalStats.total))
-		}
-
-		// Auto-hide the message after 5 seconds (give more time to read the stats)
-		return m, func() tea.Msg {
-			select {
-			case <-time.After(5 * time.Second):
-			}
-			return hideErrMsg{}
 		}
+		return m, m.showSuccess(fmt.Sprintf("All tests passed! %d/%d test suites completed",
+			finalStats.passed, finalStats.total))
 	}
 	return m, nil
 }
@@ -718,6 +1246,8 @@ func (m *home) handleQuit() (tea.Model, tea.Cmd) {
 	if err := m.storage.SaveInstances(m.list.GetInstances()); err != nil {
 		return m, m.handleError(err)
 	}
+	m.withdrawApproval()
+	m.cleanExit = true
 	return m, tea.Quit
 }
 
@@ -728,7 +1258,7 @@ func (m *home) handleMenuHighlighting(msg tea.KeyMsg) (cmd tea.Cmd, returnEarly
 		m.keySent = false
 		return nil, false
 	}
-	if m.state == statePrompt || m.state == stateHelp || m.state == stateConfirm {
+	if m.state == statePrompt || m.state == stateHelp || m.state == stateConfirm || m.state == stateFilter {
 		return nil, false
 	}
 	// If it's in the global keymap, we should try to highlight it.
@@ -740,7 +1270,8 @@ func (m *home) handleMenuHighlighting(msg tea.KeyMsg) (cmd tea.Cmd, returnEarly
 	if m.list.GetSelectedInstance() != nil && m.list.GetSelectedInstance().Paused() && name == keys.KeyEnter {
 		return nil, false
 	}
-	if name == keys.KeyShiftDown || name == keys.KeyShiftUp {
+	// Scroll keys skip the highlight round-trip so key repeat stays responsive
+	if isScrollKey(name) {
 		return nil, false
 	}
 
@@ -755,6 +1286,17 @@ func (m *home) handleMenuHighlighting(msg tea.KeyMsg) (cmd tea.Cmd, returnEarly
 		m.keydownCallback(name)), true
 }
 
+// isScrollKey returns true for keys that scroll the active pane
+func isScrollKey(name keys.KeyName) bool {
+	switch name {
+	case keys.KeyShiftUp, keys.KeyShiftDown, keys.KeyPageUp, keys.KeyPageDown,
+		keys.KeyHalfPageUp, keys.KeyHalfPageDown, keys.KeyJumpUp, keys.KeyJumpDown,
+		keys.KeyPanLeft, keys.KeyPanRight:
+		return true
+	}
+	return false
+}
+
 func (m *home) handleKeyPress(msg tea.KeyMsg) (mod tea.Model, cmd tea.Cmd) {
 	cmd, returnEarly := m.handleMenuHighlighting(msg)
 	if returnEarly {
@@ -785,6 +1327,86 @@ func (m *home) handleKeyPress(msg tea.KeyMsg) (mod tea.Model, cmd tea.Cmd) {
 		return m.handleCommentDetailState(msg)
 	}
 
+	if m.state == statePromptQueue {
+		return m.handlePromptQueueState(msg)
+	}
+
+	if m.state == stateInstanceSettings {
+		return m.handleInstanceSettingsState(msg)
+	}
+
+	if m.state == stateAutoYesPolicy {
+		return m.handleAutoYesPolicyState(msg)
+	}
+
+	if m.state == stateCommitMessage {
+		return m.handleCommitMessageState(msg)
+	}
+
+	if m.state == stateGroupName {
+		return m.handleGroupNameState(msg)
+	}
+
+	if m.state == stateRepoPath {
+		return m.handleRepoPathState(msg)
+	}
+
+	if m.state == stateWorktreePath {
+		return m.handleWorktreePathState(msg)
+	}
+
+	if m.state == stateBroadcast {
+		return m.handleBroadcastState(msg)
+	}
+
+	if m.state == stateWorkPrompt {
+		return m.handleWorkPromptState(msg)
+	}
+
+	if m.state == stateConflicts {
+		return m.handleConflictsState(msg)
+	}
+
+	if m.state == statePromptPreview {
+		if m.textOverlay == nil || m.textOverlay.HandleKeyPress(msg) {
+			m.state = statePRReview
+			m.textOverlay = nil
+		}
+		return m, nil
+	}
+
+	if m.state == stateBranchImport {
+		return m.handleBranchImportState(msg)
+	}
+
+	if m.state == stateListSelect {
+		return m.handleListSelectState(msg)
+	}
+
+	if m.state == stateSearch {
+		return m.handleSearchState(msg)
+	}
+
+	if m.state == stateFilter {
+		return m.handleFilterState(msg)
+	}
+
+	if m.state == stateOutcome {
+		return m.handleOutcomeState(msg)
+	}
+
+	if m.state == stateRebaseLog {
+		return m.handleRebaseLogState(msg)
+	}
+
+	if m.state == stateInteractiveRebase {
+		return m.handleInteractiveRebaseState(msg)
+	}
+
+	if m.state == stateDiffBase {
+		return m.handleDiffBaseState(msg)
+	}
+
 	if m.state == stateNew {
 		// Handle quit commands first. Don't handle q because the user might want to type that.
 		if msg.String() == "ctrl+c" {
@@ -805,7 +1427,16 @@ func (m *home) handleKeyPress(msg tea.KeyMsg) (mod tea.Model, cmd tea.Cmd) {
 		// Start the instance (enable previews etc) and go back to the main menu state.
 		case tea.KeyEnter:
 			if len(instance.Title) == 0 {
-				return m, m.handleError(fmt.Errorf("title cannot be empty"))
+				if !m.promptAfterName {
+					return m, m.handleError(fmt.Errorf("title cannot be empty"))
+				}
+				// Skip naming; the title is generated from the prompt once it is entered
+				m.promptAfterName = false
+				m.autoNamePending = true
+				m.state = statePrompt
+				m.menu.SetState(ui.StatePrompt)
+				m.textInputOverlay = overlay.NewTextInputOverlay("Enter prompt (the session is named from it)", "")
+				return m, tea.WindowSize()
 			}
 
 			// Start the instance asynchronously
@@ -835,8 +1466,8 @@ func (m *home) handleKeyPress(msg tea.KeyMsg) (mod tea.Model, cmd tea.Cmd) {
 
 			return m, tea.Batch(tea.WindowSize(), m.instanceChanged(), cmd)
 		case tea.KeyRunes:
-			if len(instance.Title) >= 32 {
-				return m, m.handleError(fmt.Errorf("title cannot be longer than 32 characters"))
+			if utf8.RuneCountInString(instance.Title) >= maxTitleLength {
+				return m, m.handleError(fmt.Errorf("title cannot be longer than %d characters", maxTitleLength))
 			}
 			if err := instance.SetTitle(instance.Title + string(msg.Runes)); err != nil {
 				return m, m.handleError(err)
@@ -845,7 +1476,8 @@ func (m *home) handleKeyPress(msg tea.KeyMsg) (mod tea.Model, cmd tea.Cmd) {
 			if len(instance.Title) == 0 {
 				return m, nil
 			}
-			if err := instance.SetTitle(instance.Title[:len(instance.Title)-1]); err != nil {
+			_, size := utf8.DecodeLastRuneInString(instance.Title)
+			if err := instance.SetTitle(instance.Title[:len(instance.Title)-size]); err != nil {
 				return m, m.handleError(err)
 			}
 		case tea.KeySpace:
@@ -873,29 +1505,72 @@ func (m *home) handleKeyPress(msg tea.KeyMsg) (mod tea.Model, cmd tea.Cmd) {
 
 		// Check if the form was submitted or canceled
 		if shouldClose {
+			if m.autoNamePending {
+				return m.startAutoNamedInstance()
+			}
 			selected := m.list.GetSelectedInstance()
 			// TODO: this should never happen since we set the instance in the previous state.
 			if selected == nil {
 				return m, nil
 			}
+			var sendPrompt tea.Cmd
 			if m.textInputOverlay.IsSubmitted() {
-				if err := selected.SendPrompt(m.textInputOverlay.GetValue()); err != nil {
-					// TODO: we probably end up in a bad state here.
-					return m, m.handleError(err)
+				if selected.Started() {
+					sendPrompt = sendStartupPrompt(selected, m.textInputOverlay.GetValue())
+				} else {
+					// Still starting; the prompt is sent once the instance is created
+					selected.Prompt = m.textInputOverlay.GetValue()
 				}
 			}
 
 			// Close the overlay and reset state
 			m.textInputOverlay = nil
 			m.state = stateDefault
-			return m, tea.Sequence(
+			return m, tea.Batch(sendPrompt, tea.Sequence(
 				tea.WindowSize(),
 				func() tea.Msg {
 					m.menu.SetState(ui.StateDefault)
 					m.showHelpScreen(helpStart(selected), nil)
 					return nil
 				},
-			)
+			))
+		}
+
+		return m, nil
+	} else if m.state == stateBaseRef {
+		if !m.textInputOverlay.HandleKeyPress(msg) {
+			return m, nil
+		}
+		submitted, ref := m.textInputOverlay.IsSubmitted(), m.textInputOverlay.GetValue()
+		m.textInputOverlay = nil
+		m.state = stateDefault
+		m.menu.SetState(ui.StateDefault)
+		if !submitted {
+			m.promptAfterName = false
+			return m, tea.WindowSize()
+		}
+		return m.submitBaseRef(ref)
+	} else if m.state == stateCheckpoint {
+		shouldClose := m.textInputOverlay.HandleKeyPress(msg)
+
+		if shouldClose {
+			selected := m.list.GetSelectedInstance()
+			if selected == nil {
+				return m, nil
+			}
+
+			var finalCmd tea.Cmd = tea.WindowSize()
+			if m.textInputOverlay.IsSubmitted() {
+				finalCmd = tea.Batch(tea.WindowSize(),
+					m.notify(ui.ToastInfo, fmt.Sprintf("Asked '%s' for a checkpoint summary...", selected.Title)),
+					m.captureCheckpoint(selected, m.textInputOverlay.GetValue()))
+			}
+
+			m.textInputOverlay = nil
+			m.state = stateDefault
+			m.menu.SetState(ui.StateDefault)
+
+			return m, finalCmd
 		}
 
 		return m, nil
@@ -932,39 +1607,9 @@ func (m *home) handleKeyPress(msg tea.KeyMsg) (mod tea.Model, cmd tea.Cmd) {
 	if m.state == stateConfirm {
 		shouldClose := m.confirmationOverlay.HandleKeyPress(msg)
 		if shouldClose {
-			// Capture confirmation state before clearing overlay
-			wasConfirmed := m.confirmationOverlay.IsConfirmed()
-
-			// Check if we should return to PR review state
-			returnToPRReview := m.prReviewOverlay != nil
-
-			m.confirmationOverlay = nil
-
-			// Execute pending command if confirmed
-			if wasConfirmed && m.pendingCmd != nil {
-				cmd := m.pendingCmd
-				m.pendingCmd = nil
-				// Execute the action and get the result
-				result := cmd()
-				// If result is a tea.Cmd, return it to be executed
-				if resultCmd, ok := result.(tea.Cmd); ok {
-					return m, resultCmd
-				}
-				// Otherwise handle as a message
-				return m.Update(result)
-			}
-			m.pendingCmd = nil
-
-			// Set appropriate state after handling confirmation
-			if returnToPRReview {
-				m.state = statePRReview
-			} else {
-				m.state = stateDefault
-			}
-
-			return m, nil
+			return m.closeConfirmation()
 		}
-		return m, nil
+		return m, m.confirmationOverlay.PendingCmd()
 	}
 
 	// Exit scrolling mode when ESC is pressed and preview or terminal pane is in scrolling mode
@@ -991,6 +1636,12 @@ func (m *home) handleKeyPress(msg tea.KeyMsg) (mod tea.Model, cmd tea.Cmd) {
 			}
 			return m, m.instanceChanged()
 		}
+
+		// Otherwise clear a filter narrowing the list
+		if m.list.Filter() != "" {
+			m.list.SetFilter("")
+			return m, m.instanceChanged()
+		}
 	}
 
 	// Handle quit commands first
@@ -998,11 +1649,11 @@ func (m *home) handleKeyPress(msg tea.KeyMsg) (mod tea.Model, cmd tea.Cmd) {
 		return m.handleQuit()
 	}
 
-	// Handle Jest-specific keybindings when in Jest tab
-	if m.tabbedWindow.IsInJestTab() {
+	// Handle test-tab keybindings when in the test tab
+	if m.tabbedWindow.IsInTestTab() {
 		switch msg.String() {
 		case "r":
-			m.tabbedWindow.JestRerunTests()
+			m.tabbedWindow.RerunTests()
 			return m, nil
 		}
 	}
@@ -1022,123 +1673,111 @@ func (m *home) handleKeyPress(msg tea.KeyMsg) (mod tea.Model, cmd tea.Cmd) {
 		m.keybindingEditorOverlay = overlay.NewKeybindingEditorOverlay()
 		return m, nil
 	case keys.KeyPrompt:
-		if m.list.NumInstances() >= GlobalInstanceLimit {
-			return m, m.handleError(
-				fmt.Errorf("you can't create more than %d instances", GlobalInstanceLimit))
-		}
-		instance, err := session.NewInstance(session.InstanceOptions{
-			Title:   "",
-			Path:    ".",
-			Program: m.program,
-		})
-		if err != nil {
+		if err := m.checkNewInstance(m.program); err != nil {
 			return m, m.handleError(err)
 		}
-
-		m.newInstanceFinalizer = m.list.AddInstance(instance)
-		m.list.SetSelectedInstance(m.list.NumInstances() - 1)
-		m.state = stateNew
-		m.menu.SetState(ui.StateNewInstance)
-		m.promptAfterName = true
-
-		return m, nil
+		if m.confirmRepoState(true) {
+			return m, nil
+		}
+		return m.chooseProgram(true, "")
 	case keys.KeyNew:
-		if m.list.NumInstances() >= GlobalInstanceLimit {
-			return m, m.handleError(
-				fmt.Errorf("you can't create more than %d instances", GlobalInstanceLimit))
-		}
-		instance, err := session.NewInstance(session.InstanceOptions{
-			Title:   "",
-			Path:    ".",
-			Program: m.program,
-		})
-		if err != nil {
+		if err := m.checkNewInstance(m.program); err != nil {
 			return m, m.handleError(err)
 		}
-
-		m.newInstanceFinalizer = m.list.AddInstance(instance)
-		m.list.SetSelectedInstance(m.list.NumInstances() - 1)
-		m.state = stateNew
-		m.menu.SetState(ui.StateNewInstance)
-
-		return m, nil
+		if m.confirmRepoState(false) {
+			return m, nil
+		}
+		return m.chooseProgram(false, "")
+	case keys.KeyNewFromChanges:
+		return m, m.confirmNewFromChanges()
 	case keys.KeyExistingBranch:
-		if m.list.NumInstances() >= GlobalInstanceLimit {
-			return m, m.handleError(
-				fmt.Errorf("you can't create more than %d instances", GlobalInstanceLimit))
+		if err := m.checkNewInstance(m.program); err != nil {
+			return m, m.handleError(err)
+		}
+		if m.remoteHost != "" {
+			return m, m.notify(ui.ToastInfo, "Instances on remote hosts start on new branches; press n instead")
 		}
 
 		// Show branch selector
 		m.state = stateBranchSelect
 		m.menu.SetState(ui.StateNewInstance)
 
-		// Get list of remote branches
-		branches, err := git.ListRemoteBranchesFromRepo(".")
+		// Get list of remote branches and tags; a commit can be entered even without either
+		branches, err := git.ListRemoteBranchesFromRepo(m.repoPath)
 		if err != nil {
+			m.state = stateDefault
+			m.menu.SetState(ui.StateDefault)
 			return m, m.handleError(fmt.Errorf("failed to list remote branches: %w", err))
 		}
-
-		// Check if there are any branches
-		if len(branches) == 0 {
+		tags, err := git.ListTagsFromRepo(m.repoPath)
+		if err != nil {
 			m.state = stateDefault
 			m.menu.SetState(ui.StateDefault)
-			return m, m.handleError(fmt.Errorf("no remote branches found"))
+			return m, m.handleError(err)
 		}
 
 		// Create branch selector overlay
-		m.branchSelectorOverlay = overlay.NewBranchSelectorOverlay(branches)
+		m.branchSelectorOverlay = overlay.NewBranchSelectorOverlay(branches, tags)
 
 		// Initialize the branch selector
 		return m, m.branchSelectorOverlay.Init()
 	case keys.KeyUp:
 		if m.scrollLocked && m.tabbedWindow.IsInDiffTab() {
-			m.tabbedWindow.ScrollUp()
+			m.tabbedWindow.ScrollBy(-m.scrollAccel.Step(-1))
 		} else {
 			m.list.Up()
 		}
 		return m, m.instanceChanged()
 	case keys.KeyDown:
 		if m.scrollLocked && m.tabbedWindow.IsInDiffTab() {
-			m.tabbedWindow.ScrollDown()
+			m.tabbedWindow.ScrollBy(m.scrollAccel.Step(1))
 		} else {
 			m.list.Down()
 		}
 		return m, m.instanceChanged()
 	case keys.KeyShiftUp:
-		m.tabbedWindow.ScrollUp()
+		if step := m.scrollAccel.Step(-1); step > 1 {
+			m.tabbedWindow.ScrollBy(-step)
+		} else {
+			m.tabbedWindow.ScrollUp()
+		}
 		return m, nil
 	case keys.KeyShiftDown:
-		m.tabbedWindow.ScrollDown()
+		if step := m.scrollAccel.Step(1); step > 1 {
+			m.tabbedWindow.ScrollBy(step)
+		} else {
+			m.tabbedWindow.ScrollDown()
+		}
 		return m, nil
 	case keys.KeyHome:
-		if m.tabbedWindow.IsInDiffTab() {
-			m.tabbedWindow.ScrollToTop()
-		}
+		m.tabbedWindow.ScrollToTop()
 		return m, m.instanceChanged()
 	case keys.KeyEnd:
-		if m.tabbedWindow.IsInDiffTab() {
-			m.tabbedWindow.ScrollToBottom()
-		}
+		m.tabbedWindow.ScrollToBottom()
 		return m, m.instanceChanged()
 	case keys.KeyPageUp:
-		if m.tabbedWindow.IsInDiffTab() {
-			m.tabbedWindow.PageUp()
-		}
+		m.tabbedWindow.PageUp()
 		return m, m.instanceChanged()
 	case keys.KeyPageDown:
-		if m.tabbedWindow.IsInDiffTab() {
-			m.tabbedWindow.PageDown()
-		}
+		m.tabbedWindow.PageDown()
+		return m, m.instanceChanged()
+	case keys.KeyHalfPageUp:
+		m.tabbedWindow.HalfPageUp()
+		return m, m.instanceChanged()
+	case keys.KeyHalfPageDown:
+		m.tabbedWindow.HalfPageDown()
+		return m, m.instanceChanged()
+	case keys.KeyJumpUp:
+		m.tabbedWindow.ScrollBy(-ui.JumpLines)
+		return m, m.instanceChanged()
+	case keys.KeyJumpDown:
+		m.tabbedWindow.ScrollBy(ui.JumpLines)
 		return m, m.instanceChanged()
 	case keys.KeyAltUp:
-		if m.tabbedWindow.IsInDiffTab() {
-			m.tabbedWindow.JumpToPrevFile()
-		}
+		m.tabbedWindow.JumpToPrevFile()
 		return m, m.instanceChanged()
 	case keys.KeyAltDown:
-		if m.tabbedWindow.IsInDiffTab() {
-			m.tabbedWindow.JumpToNextFile()
-		}
+		m.tabbedWindow.JumpToNextFile()
 		return m, m.instanceChanged()
 	case keys.KeyTab:
 		return m.handleTabSwitch(false)
@@ -1154,6 +1793,23 @@ func (m *home) handleKeyPress(msg tea.KeyMsg) (mod tea.Model, cmd tea.Cmd) {
 			m.tabbedWindow.SetDiffModeLastCommit()
 		}
 		return m, m.instanceChanged()
+	case keys.KeyDiffBase:
+		return m, m.loadDiffBaseRefs()
+	case keys.KeyDiffStaging:
+		if m.tabbedWindow.IsInDiffTab() {
+			m.tabbedWindow.SetDiffModeStaging()
+		}
+		return m, m.instanceChanged()
+	case keys.KeyUndo:
+		return m, m.undo()
+	case keys.KeyToggleStageHunk:
+		return m.toggleStageHunk()
+	case keys.KeyToggleStageFile:
+		return m.toggleStageFile()
+	case keys.KeyDiscardHunk:
+		return m.discardHunk(false)
+	case keys.KeyDiscardFile:
+		return m.discardHunk(true)
 	case keys.KeyLeft:
 		if m.tabbedWindow.IsInDiffTab() {
 			m.tabbedWindow.NavigateToPrevCommit()
@@ -1164,6 +1820,31 @@ func (m *home) handleKeyPress(msg tea.KeyMsg) (mod tea.Model, cmd tea.Cmd) {
 			m.tabbedWindow.NavigateToNextCommit()
 		}
 		return m, m.instanceChanged()
+	case keys.KeyToggleWrap:
+		wrap, ok := m.tabbedWindow.ToggleWrap()
+		if !ok {
+			return m, nil
+		}
+		if wrap {
+			return m, m.notify(ui.ToastInfo, "Soft wrap on")
+		}
+		return m, m.notify(ui.ToastInfo, fmt.Sprintf("Soft wrap off: %s/%s pan long lines",
+			keys.GlobalkeyBindings[keys.KeyPanLeft].Help().Key, keys.GlobalkeyBindings[keys.KeyPanRight].Help().Key))
+	case keys.KeyToggleSideBySide:
+		sideBySide, ok := m.tabbedWindow.ToggleSideBySide()
+		if !ok {
+			return m, nil
+		}
+		if sideBySide {
+			return m, m.notify(ui.ToastInfo, "Side-by-side diff")
+		}
+		return m, m.notify(ui.ToastInfo, "Unified diff")
+	case keys.KeyPanLeft:
+		m.tabbedWindow.Pan(-ui.PanColumns)
+		return m, nil
+	case keys.KeyPanRight:
+		m.tabbedWindow.Pan(ui.PanColumns)
+		return m, nil
 	case keys.KeyScrollLock:
 		if m.tabbedWindow.IsInDiffTab() {
 			m.scrollLocked = !m.scrollLocked
@@ -1211,43 +1892,85 @@ func (m *home) handleKeyPress(msg tea.KeyMsg) (mod tea.Model, cmd tea.Cmd) {
 		}
 
 		// Create the kill action as a tea.Cmd
-		killAction := func() tea.Msg {
+		undoDescription := fmt.Sprintf("kill of '%s'", selected.Title)
+		killAction := func(outcome *session.Outcome) tea.Msg {
 			// Delete from storage first
 			if err := m.storage.DeleteInstance(selected.Title); err != nil {
 				return err
 			}
+			m.saveOutcome(m.outcomeRecord(selected, session.DispositionDeleted, outcome), "")
 
 			// Start async kill and return a command
 			// The kill logic will handle checked out branches
 			return m.killInstanceAsync(selected)
 		}
 
+		// Kill the session but keep its branch (with any uncommitted work committed)
+		keepBranchAction := func(outcome *session.Outcome) tea.Msg {
+			if err := m.storage.DeleteInstance(selected.Title); err != nil {
+				return err
+			}
+			record := m.outcomeRecord(selected, session.DispositionKept, outcome)
+			return m.teardownInstanceAsync(selected, func() (string, *undoEntry, error) {
+				kept := selected.KeptBranch()
+				if err := selected.KillKeepBranch(); err != nil {
+					return "", nil, err
+				}
+				m.saveOutcome(record, "")
+				return fmt.Sprintf("Killed '%s', kept branch %s", selected.Title, selected.Branch),
+					&undoEntry{description: undoDescription, killed: &kept}, nil
+			})
+		}
+
+		// Kill the session, move its branch out of the way under archive/ and save the session
+		// to the archive to browse or restore later
+		archiveAction := func(outcome *session.Outcome) tea.Msg {
+			if err := m.storage.DeleteInstance(selected.Title); err != nil {
+				return err
+			}
+			record := m.outcomeRecord(selected, session.DispositionArchived, outcome)
+			return m.teardownInstanceAsync(selected, func() (string, *undoEntry, error) {
+				data := selected.ToInstanceData()
+				archived, path, err := selected.Archive()
+				if err != nil {
+					return "", nil, err
+				}
+				m.saveOutcome(record, archived)
+				killed := session.KilledInstance{Data: data, Archive: &session.ArchiveEntry{
+					Path:     path,
+					Metadata: session.ArchiveMetadata{Instance: data, ArchivedBranch: archived},
+				}}
+				return fmt.Sprintf("Archived '%s' with branch %s; %s restores it", selected.Title, archived,
+						keys.GlobalkeyBindings[keys.KeyArchives].Help().Key),
+					&undoEntry{description: undoDescription, killed: &killed}, nil
+			})
+		}
+
+		// Deleting the branch, which is force-killed if it can't be removed cleanly, has to be
+		// confirmed again by typing the session's name
+		deleteAction := func() tea.Msg {
+			m.confirmTyped(fmt.Sprintf("[!] Delete session '%s' and its branch %s?", selected.Title, selected.Branch),
+				selected.Title, m.killWithOutcome(selected, killAction))
+			return m.offerGitCommands(func() ([]string, error) { return selected.KillGitCommands(false) })
+		}
+
 		// Show confirmation modal
 		message := fmt.Sprintf("[!] Kill session '%s'?", selected.Title)
-		return m, m.confirmAction(message, killAction)
+		m.confirmChoices(message, []confirmChoice{
+			{key: "d", label: "delete session and branch", action: deleteAction},
+			{key: "k", label: "delete session, keep branch", action: m.killWithOutcome(selected, keepBranchAction)},
+			{key: "a", label: "delete session, archive branch", action: m.killWithOutcome(selected, archiveAction)},
+		})
+		return m, m.offerGitCommands(killPlans(selected))
 	case keys.KeySubmit:
 		selected := m.list.GetSelectedInstance()
 		if selected == nil {
 			return m, nil
 		}
-
-		// Create the push action as a tea.Cmd
-		pushAction := func() tea.Msg {
-			// Default commit message with timestamp
-			commitMsg := fmt.Sprintf("[claudesquad] update from '%s' on %s", selected.Title, time.Now().Format(time.RFC822))
-			worktree, err := selected.GetGitWorktree()
-			if err != nil {
-				return err
-			}
-			if err = worktree.PushChanges(commitMsg, true); err != nil {
-				return err
-			}
-			return nil
+		if cmd := m.requireRemote(selected, "Pushing"); cmd != nil {
+			return m, cmd
 		}
-
-		// Show confirmation modal
-		message := fmt.Sprintf("[!] Push changes from session '%s'?", selected.Title)
-		return m, m.confirmAction(message, pushAction)
+		return m, m.suggestPushMessage(selected)
 	case keys.KeyCheckout:
 		selected := m.list.GetSelectedInstance()
 		if selected == nil {
@@ -1267,6 +1990,12 @@ func (m *home) handleKeyPress(msg tea.KeyMsg) (mod tea.Model, cmd tea.Cmd) {
 		if selected == nil {
 			return m, nil
 		}
+		if selected.Broken() {
+			return m, m.confirmRepair(selected)
+		}
+		if err := m.policy.CheckProgram(selected.Program); err != nil {
+			return m, m.handleError(err)
+		}
 		if err := selected.Resume(); err != nil {
 			return m, m.handleError(err)
 		}
@@ -1285,23 +2014,35 @@ func (m *home) handleKeyPress(msg tea.KeyMsg) (mod tea.Model, cmd tea.Cmd) {
 			return m, nil
 		}
 
+		// Resume resolving a rebase that stopped on conflicts
+		if worktree, err := selected.GetGitWorktree(); err == nil && worktree.IsRebaseInProgress() {
+			return m, m.showConflicts(selected)
+		}
+		if cmd := m.requireRemote(selected, "Updating with main"); cmd != nil {
+			return m, cmd
+		}
+
 		// Show confirmation modal
-		message := fmt.Sprintf("[!] Rebase session '%s' with main branch?", selected.Title)
+		message := fmt.Sprintf("[!] Update session '%s' with main branch?", selected.Title)
 
 		// Store the selected instance for the rebase
 		m.pendingRebaseInstance = selected
 
-		// Create a simple action that just returns a message to trigger the actual rebase
-		rebaseAction := func() tea.Msg {
-			return startRebaseMsg{}
-		}
-
-		return m, m.confirmAction(message, rebaseAction)
+		message, choices := m.withAuthWarning(message, m.mergeStrategyChoices())
+		m.confirmChoices(message, choices)
+		return m, m.offerGitCommands(mergeStrategyPlans(selected))
+	case keys.KeyRebaseLog:
+		return m, m.showLastRebaseLog()
+	case keys.KeyInteractiveRebase:
+		return m, m.loadRebaseSteps()
 	case keys.KeyPRReview:
 		selected := m.list.GetSelectedInstance()
 		if selected == nil {
 			return m, nil
 		}
+		if cmd := m.requireRemote(selected, "PR review"); cmd != nil {
+			return m, cmd
+		}
 
 		// Check if instance is started
 		if !selected.Started() {
@@ -1335,6 +2076,8 @@ func (m *home) handleKeyPress(msg tea.KeyMsg) (mod tea.Model, cmd tea.Cmd) {
 
 		// Preprocess comments for better performance
 		pr.PreprocessComments()
+		m.markAddressedComments(selected, pr)
+		selected.MarkCommentsReviewed()
 
 		// Show PR review UI
 		m.state = statePRReview
@@ -1343,7 +2086,8 @@ func (m *home) handleKeyPress(msg tea.KeyMsg) (mod tea.Model, cmd tea.Cmd) {
 
 		// Initialize the PR review model
 		initCmd := prReviewModel.Init()
-		return m, initCmd
+		return m, tea.Batch(initCmd, m.sendInstanceEvent(notify.EventPRCommentsFetched, selected,
+			fmt.Sprintf("Fetched %d comment(s) on PR #%d", len(pr.AllComments), pr.Number)))
 	case keys.KeyPRResolveConversations:
 		return m.requestResolveAllConversationsConfirmation()
 	case keys.KeyBookmark:
@@ -1356,15 +2100,130 @@ func (m *home) handleKeyPress(msg tea.KeyMsg) (mod tea.Model, cmd tea.Cmd) {
 		m.menu.SetState(ui.StateBookmark)
 		m.textInputOverlay = overlay.NewTextInputOverlay("Enter bookmark message (or leave empty for auto-generated)", "")
 		return m, nil
+	case keys.KeyCheckpoint:
+		selected := m.list.GetSelectedInstance()
+		if selected == nil || selected.Paused() || !selected.TmuxAlive() {
+			return m, nil
+		}
+		m.state = stateCheckpoint
+		m.menu.SetState(ui.StateBookmark)
+		m.textInputOverlay = overlay.NewTextInputOverlay("Enter checkpoint name (or leave empty for a timestamp)", "")
+		return m, nil
+	case keys.KeyDetails:
+		selected := m.list.GetSelectedInstance()
+		if selected == nil {
+			return m, nil
+		}
+		m.historyOverlay = overlay.NewHistoryOverlay(fmt.Sprintf("Details - %s", selected.Title), instanceDetails(selected))
+		m.historyOverlay.OnDismiss = func() {
+			m.state = stateDefault
+			m.menu.SetState(ui.StateDefault)
+			m.historyOverlay = nil
+		}
+		m.state = stateHistory
+		return m, tea.WindowSize()
+	case keys.KeyCIChecks:
+		return m, m.loadCIChecks()
+	case keys.KeyWorkOnPR:
+		return m, m.composePRWorkPrompt()
+	case keys.KeyGitStats:
+		m.historyOverlay = overlay.NewHistoryOverlay("Git Command Stats", gitStatsContent(git.CommandStats()))
+		m.historyOverlay.OnDismiss = func() {
+			m.state = stateDefault
+			m.menu.SetState(ui.StateDefault)
+			m.historyOverlay = nil
+		}
+		m.state = stateHistory
+		return m, tea.WindowSize()
+	case keys.KeyImportBranches:
+		if m.remoteHost != "" {
+			return m, m.notify(ui.ToastInfo, "Branches can't be imported from remote hosts")
+		}
+		branches, err := git.ListImportableBranchesFromRepo(m.repoPath)
+		if err != nil {
+			return m, m.handleError(err)
+		}
+		// Skip branches that already belong to an instance
+		inUse := make(map[string]bool)
+		for _, instance := range m.list.GetInstances() {
+			inUse[instance.Branch] = true
+		}
+		importable := make([]git.BranchInfo, 0, len(branches))
+		for _, branch := range branches {
+			if !inUse[branch.Name] {
+				importable = append(importable, branch)
+			}
+		}
+		m.branchImportOverlay = overlay.NewBranchImportOverlay(importable, m.appConfig.BranchPrefix)
+		m.state = stateBranchImport
+		return m, tea.WindowSize()
+	case keys.KeyAdoptWorktree:
+		return m, m.showAdoptWorktrees()
+	case keys.KeyShare:
+		selected := m.list.GetSelectedInstance()
+		if selected == nil || !selected.Started() {
+			return m, nil
+		}
+		choices := []confirmChoice{
+			{key: "l", label: "share diff as a link", action: m.shareInstance(selected, false, true)},
+		}
+		if m.hasSummarizer(config.SummarizeDiff) {
+			choices = append(choices, confirmChoice{key: "s", label: "share diff with a written summary",
+				action: m.shareInstance(selected, true, true)})
+		} else if len(selected.Checkpoints) > 0 {
+			choices = append(choices, confirmChoice{key: "s", label: "share diff with latest checkpoint summary",
+				action: m.shareInstance(selected, true, true)})
+		}
+		choices = append(choices, confirmChoice{key: "f", label: "write HTML file only", action: m.shareInstance(selected, false, false)})
+		return m, m.confirmChoices(fmt.Sprintf("Share diff of '%s'", selected.Title), choices)
+	case keys.KeyTemplate:
+		return m, m.showTemplates()
+	case keys.KeyBackups:
+		return m, m.showBackups()
+	case keys.KeyBackupBranches:
+		return m, m.showBackupBranches()
+	case keys.KeyServices:
+		return m, m.showServices()
+	case keys.KeyOpenBrowser:
+		return m, m.openBrowser()
+	case keys.KeyBroadcast:
+		return m, m.showBroadcastFilter()
+	case keys.KeyArchives:
+		return m, m.showArchives()
+	case keys.KeyReauth:
+		return m, m.reauthenticate()
+	case keys.KeySuggestTests:
+		return m, m.suggestTests()
+	case keys.KeyPromptQueue:
+		return m, m.showPromptQueue()
+	case keys.KeyInstanceSettings:
+		return m, m.showInstanceSettings()
+	case keys.KeyCoordinator:
+		return m, m.toggleCoordinator()
+	case keys.KeyAutoYesPolicy:
+		return m, m.showAutoYesPolicy()
+	case keys.KeyOperations:
+		return m, m.showOperations()
+	case keys.KeyExportMetrics:
+		return m, m.exportMetrics()
 	case keys.KeyHistory:
 		return m, m.showHistoryView()
+	case keys.KeyLastFinished:
+		return m, m.jumpToFinished()
+	case keys.KeySearch:
+		if m.list.NumInstances() == 0 {
+			return m, nil
+		}
+		return m, collectSearchDocs(m.list.GetInstances())
+	case keys.KeyFilter:
+		return m, m.startFilter()
 	case keys.KeyTest:
 		selected := m.list.GetSelectedInstance()
 		if selected == nil {
 			return m, nil
 		}
-		// Run Jest tests in the web directory
-		cmd := m.runJestTests(selected)
+		// Run the configured test command in the test tab
+		cmd := m.runTests(selected)
 		return m, cmd
 	case keys.KeyGitStatus:
 		selected := m.list.GetSelectedInstance()
@@ -1391,6 +2250,9 @@ func (m *home) handleKeyPress(msg tea.KeyMsg) (mod tea.Model, cmd tea.Cmd) {
 		if selected == nil {
 			return m, nil
 		}
+		if cmd := m.requireRemote(selected, "Resetting to origin"); cmd != nil {
+			return m, cmd
+		}
 
 		// Check if instance is paused
 		if selected.Paused() {
@@ -1404,7 +2266,7 @@ func (m *home) handleKeyPress(msg tea.KeyMsg) (mod tea.Model, cmd tea.Cmd) {
 		}
 
 		// Show confirmation modal
-		message := fmt.Sprintf("[!] Reset session '%s' to origin/%s?", selected.Title, worktree.GetBranchName())
+		message := fmt.Sprintf("[!] Reset session '%s' to origin/%s?", selected.Title, worktree.PushBranch())
 
 		// Store the selected instance for the reset
 		m.pendingResetInstance = selected
@@ -1414,12 +2276,94 @@ func (m *home) handleKeyPress(msg tea.KeyMsg) (mod tea.Model, cmd tea.Cmd) {
 			return startGitResetMsg{}
 		}
 
-		return m, m.confirmAction(message, resetAction)
+		m.confirmTyped(message, selected.Title, resetAction)
+		return m, m.offerGitCommands(worktreePlan(selected, (*git.GitWorktree).ResetToOrigin))
+	case keys.KeyFixBranch:
+		selected := m.list.GetSelectedInstance()
+		if selected == nil {
+			return m, nil
+		}
+		return m, m.confirmFixBranch(selected)
+	case keys.KeyMoveToGroup:
+		selected := m.list.GetSelectedInstance()
+		if selected == nil {
+			return m, nil
+		}
+		return m, m.showMoveToGroup(selected)
+	case keys.KeyToggleGroup:
+		return m, m.toggleGroup()
+	case keys.KeyMoveWorktree:
+		selected := m.list.GetSelectedInstance()
+		if selected == nil {
+			return m, nil
+		}
+		return m, m.showWorktreePathPrompt(selected)
+	case keys.KeyRepos:
+		return m, m.showRepoPicker()
+	case keys.KeyClipboard:
+		selected := m.list.GetSelectedInstance()
+		if selected == nil {
+			return m, nil
+		}
+		return m, m.loadClipboard(selected)
+	case keys.KeyGroupActions:
+		return m, m.confirmGroupActions()
+	case keys.KeyPatch:
+		selected := m.list.GetSelectedInstance()
+		if selected == nil {
+			return m, nil
+		}
+
+		if selected.Paused() {
+			return m, m.handleError(fmt.Errorf(instancePausedError, selected.Title))
+		}
+
+		worktree, err := selected.GetGitWorktree()
+		if err != nil {
+			return m, m.handleError(fmt.Errorf("failed to get git worktree: %w", err))
+		}
+
+		exportAction := func() tea.Msg {
+			return tea.Cmd(func() tea.Msg {
+				patchPath, err := worktree.ExportPatch()
+				if err != nil {
+					return patchResultMsg{err: err}
+				}
+				return patchResultMsg{message: fmt.Sprintf("Patch written to %s (path copied to clipboard)", patchPath),
+					instance: selected, path: patchPath}
+			})
+		}
+
+		applyAction := func() tea.Msg {
+			return tea.Cmd(func() tea.Msg {
+				result, err := worktree.ApplyPatchToRepo()
+				if err != nil {
+					return patchResultMsg{err: err}
+				}
+				return patchResultMsg{
+					message:   fmt.Sprintf("Applied changes from %s to %s", worktree.GetBranchName(), worktree.GetRepoPath()),
+					conflicts: result.Conflicts,
+				}
+			})
+		}
+
+		message := fmt.Sprintf("Patch for session '%s'", selected.Title)
+		return m, m.confirmChoices(message, []confirmChoice{
+			{key: "e", label: "export as .patch file", action: exportAction},
+			{key: "a", label: "apply to main checkout (git apply --3way)", action: applyAction},
+		})
 	case keys.KeyEnter:
 		if m.list.NumInstances() == 0 {
 			return m, nil
 		}
 		selected := m.list.GetSelectedInstance()
+		if selected != nil && selected.Broken() {
+			return m, m.confirmRepair(selected)
+		}
+		// Enter on a group's header folds it
+		if selected == nil && m.list.SelectedGroup() != "" {
+			return m, m.toggleGroup()
+		}
 		if selected == nil || selected.Paused() || !selected.TmuxAlive() {
 			return m, nil
 		}
@@ -1473,6 +2417,7 @@ func (m *home) handleTabSwitch(reverse bool) (tea.Model, tea.Cmd) {
 	} else {
 		m.tabbedWindow.Toggle()
 	}
+	m.rememberTab()
 	m.menu.SetInDiffTab(m.tabbedWindow.IsInDiffTab())
 	return m, m.instanceChanged()
 }
@@ -1576,11 +2521,33 @@ const (
 	overlayWidthRatio  = 0.8
 	overlayHeightRatio = 0.9
 
+	// prStatusPollInterval is how often PR badges are refreshed in the background
+	prStatusPollInterval = 30 * time.Second
+
+	// authCheckInterval is how often gh and git credentials are checked in the background
+	authCheckInterval = 5 * time.Minute
+
+	// resourceSampleInterval is how often the CPU and memory of instances' processes are sampled
+	resourceSampleInterval = 3 * time.Second
+
+	// maxTitleLength is the longest instance title that can be entered
+	maxTitleLength = 32
+
+	// autoNameWords is how many words of a prompt are used to name an instance
+	autoNameWords = 5
+
+	// checkpointTimeout is how long to wait for the agent to write a checkpoint summary
+	checkpointTimeout = 3 * time.Minute
+
+	// toastRows is the number of rows reserved below the menu for stacked toasts
+	toastRows = 2
+
 	// Error messages
 	cannotRebaseUncommittedChangesError = "cannot rebase: you have uncommitted changes. Press 'c' to checkout and commit, or stash them first"
 	instancePausedError                 = "instance '%s' is paused. Press 'r' to resume it first"
 	noPullRequestFoundError             = "no pull request found for this branch. Push the branch with 'p' first to create a PR: %w"
 	noExternalDiffToolConfiguredError   = "no external diff tool configured. Set 'diff_command' in ~/.claude-squad/config.json or repository's CLAUDE.md"
+	noMergeToolConfiguredError          = "no merge tool configured. Set 'default_merge_command' in ~/.claude-squad/config.json or 'merge_command' in repository's CLAUDE.md"
 )
 
 func (m *home) createBookmarkCommit(instance *session.Instance, userMessage string) tea.Cmd {
@@ -1596,10 +2563,22 @@ func (m *home) createBookmarkCommit(instance *session.Instance, userMessage stri
 			return fmt.Errorf("failed to get current branch: %w", err)
 		}
 
+		staged, err := worktree.HasStagedChanges()
+		if err != nil {
+			return err
+		}
+
 		var commitMessage string
 		if userMessage != "" {
 			// Use user-provided message
 			commitMessage = fmt.Sprintf("[BOOKMARK] %s", userMessage)
+		} else if staged {
+			// The bookmark commits the staged changes, so describe them
+			generated, err := worktree.GenerateCommitMessage()
+			if err != nil {
+				return err
+			}
+			commitMessage = fmt.Sprintf("[BOOKMARK] %s", generated)
 		} else {
 			// Generate message from commits since last bookmark
 			lastBookmarkSHA, err := worktree.FindLastBookmarkCommit(currentBranch)
@@ -1634,18 +2613,18 @@ func (m *home) createBookmarkCommit(instance *session.Instance, userMessage stri
 	}
 }
 
-func (m *home) runJestTests(instance *session.Instance) tea.Cmd {
+func (m *home) runTests(instance *session.Instance) tea.Cmd {
 	return tea.Sequence(
-		// First, switch to Jest tab
+		// First, switch to the test tab
 		func() tea.Msg {
-			// Set the active tab to JestTab directly
-			m.tabbedWindow.SetTab(ui.JestTab)
+			// Set the active tab to TestTab directly
+			m.tabbedWindow.SetTab(ui.TestTab)
 			m.menu.SetInDiffTab(false)
 			return nil
 		},
-		// Then update the Jest pane with test results
+		// Then run the tests in the test pane
 		func() tea.Msg {
-			m.tabbedWindow.UpdateJest(instance)
+			m.tabbedWindow.UpdateTests(instance)
 			return nil
 		},
 	)
@@ -1692,8 +2671,13 @@ func parseJestFinalStats(output string) testStats {
 func (m *home) instanceChanged() tea.Cmd {
 	// selected may be nil
 	selected := m.list.GetSelectedInstance()
+	// An instance that finished is seen once it's selected
+	if selected != nil {
+		selected.ClearFinished()
+	}
 
 	// Update the tabbed window with the current instance
+	m.showInstanceTab(selected)
 	m.tabbedWindow.SetInstance(selected)
 
 	m.tabbedWindow.UpdateDiff(selected)
@@ -1713,6 +2697,9 @@ func (m *home) requestResolveAllConversationsConfirmation() (tea.Model, tea.Cmd)
 	if selected == nil {
 		return m, m.handleError(fmt.Errorf("no instance selected"))
 	}
+	if cmd := m.requireRemote(selected, "Resolving PR conversations"); cmd != nil {
+		return m, cmd
+	}
 
 	// Check if instance is started
 	if !selected.Started() {
@@ -1750,18 +2737,7 @@ func (m *home) requestResolveAllConversationsConfirmation() (tea.Model, tea.Cmd)
 	if fetchError == nil {
 		if len(threads) == 0 {
 			// No unresolved conversations
-			m.errorLog = append(m.errorLog, fmt.Sprintf("[%s] No unresolved review threads found on PR", timestamp))
-			
-			// For PR review state, just show error
-			if m.state == statePRReview {
-				m.errBox.SetError(fmt.Errorf("No unresolved review threads found on this PR"))
-				return m, func() tea.Msg {
-					time.Sleep(2 * time.Second)
-					return hideErrMsg{}
-				}
-			}
-			// For main menu, return error
-			return m, m.handleError(fmt.Errorf("no unresolved review threads found on this PR"))
+			return m, m.notify(ui.ToastInfo, "No unresolved review threads found on this PR")
 		}
 		message = fmt.Sprintf("Found %d unresolved review threads on this PR.\n\nAre you sure you want to resolve all %d threads?\n\nNote: Only review threads (line comments) can be resolved.\nGeneral PR comments cannot be resolved.\n\nThis action cannot be undone.", len(threads), len(threads))
 	} else {
@@ -1769,8 +2745,9 @@ func (m *home) requestResolveAllConversationsConfirmation() (tea.Model, tea.Cmd)
 		m.errorLog = append(m.errorLog, fmt.Sprintf("[%s] Error fetching thread count: %v", timestamp, fetchError))
 		
 		if strings.Contains(fetchError.Error(), "no pull request found") || 
-		   strings.Contains(fetchError.Error(), "no open pull requests") {
-			message = fmt.Sprintf("Error: %v\n\nThis feature requires an open GitHub pull request for the current branch.\n\nMake sure you:\n1. Have an open PR for this branch\n2. Are authenticated with 'gh auth login'\n3. Are in a git repository", fetchError)
+		   strings.Contains(fetchError.Error(), "no open pull requests") ||
+		   strings.Contains(fetchError.Error(), "no merge request found") {
+			message = fmt.Sprintf("Error: %v\n\nThis feature requires an open pull request (or GitLab merge request) for the current branch.\n\nMake sure you:\n1. Have an open PR for this branch\n2. Are authenticated with 'gh auth login' (or 'glab auth login')\n3. Are in a git repository", fetchError)
 			
 			// For main menu, return error immediately
 			if m.state != statePRReview {
@@ -1782,6 +2759,11 @@ func (m *home) requestResolveAllConversationsConfirmation() (tea.Model, tea.Cmd)
 		}
 	}
 
+	// List the comments sent to the agent that no change has touched yet
+	if checklist := m.remainingCommentsChecklist(selected); checklist != "" {
+		message += "\n\n" + checklist
+	}
+
 	// Store the pending command to resolve conversations
 	m.pendingCmd = func() tea.Msg {
 		// When confirmed, send the message to resolve all conversations
@@ -1814,18 +2796,140 @@ func (m *home) keydownCallback(name keys.KeyName) tea.Cmd {
 	}
 }
 
-// hideErrMsg implements tea.Msg and clears the error text from the screen.
-type hideErrMsg struct{}
+// hideToastMsg implements tea.Msg and dismisses the toast with the given id.
+type hideToastMsg struct {
+	id int
+}
 
 // previewTickMsg implements tea.Msg and triggers a preview update
 type previewTickMsg struct{}
 
 type tickUpdateMetadataMessage struct{}
 
+// backupTickMsg triggers a scheduled storage backup
+type backupTickMsg struct{}
+
+// scheduleBackup waits for the configured backup interval, then triggers a backup.
+func (m *home) scheduleBackup() tea.Cmd {
+	interval := time.Duration(m.appConfig.BackupIntervalMinutes) * time.Minute
+	return tea.Tick(interval, func(time.Time) tea.Msg {
+		return backupTickMsg{}
+	})
+}
+
+// autoPauseTickMsg triggers a check for idle instances to pause, at the time it carries
+type autoPauseTickMsg time.Time
+
+// autoPauseCheckInterval is how often instances are checked for having been idle too long.
+const autoPauseCheckInterval = time.Minute
+
+// scheduleAutoPause waits for the next idle check, or returns nil if auto-pausing is disabled.
+func (m *home) scheduleAutoPause() tea.Cmd {
+	if m.appConfig.AutoPauseAfterMinutes <= 0 {
+		return nil
+	}
+	return tea.Tick(autoPauseCheckInterval, func(t time.Time) tea.Msg {
+		return autoPauseTickMsg(t)
+	})
+}
+
+// autoPauseIdle pauses the instances whose agent has been ready, with no queued prompts, for
+// longer than the configured idle time, committing their changes like checking out does.
+func (m *home) autoPauseIdle(now time.Time) tea.Cmd {
+	idle := time.Duration(m.appConfig.AutoPauseAfterMinutes) * time.Minute
+	var cmds []tea.Cmd
+	paused := false
+	for _, instance := range m.list.GetInstances() {
+		if !instance.Started() || instance.Status != session.Ready || instance.Broken() ||
+			len(instance.PromptQueue) > 0 || instance.ReadySince().IsZero() || now.Sub(instance.ReadySince()) < idle {
+			continue
+		}
+		if err := instance.Pause(); err != nil {
+			cmds = append(cmds, m.handleError(fmt.Errorf("failed to auto-pause '%s': %w", instance.Title, err)))
+			continue
+		}
+		paused = true
+		cmds = append(cmds, m.notify(ui.ToastInfo, fmt.Sprintf("Paused '%s' after %d idle minutes. Press 'r' to resume.",
+			instance.Title, m.appConfig.AutoPauseAfterMinutes)))
+	}
+	if !paused {
+		return tea.Batch(cmds...)
+	}
+	if err := m.storage.SaveInstances(m.list.GetInstances()); err != nil {
+		cmds = append(cmds, m.handleError(err))
+	}
+	return tea.Batch(append(cmds, m.instanceChanged())...)
+}
+
+// prStatusTickMsg triggers a background refresh of the PR status shown for each instance
+type prStatusTickMsg struct{}
+
 type instanceChangedMsg struct{}
 
-// startRebaseMsg is sent to trigger the actual rebase after confirmation
-type startRebaseMsg struct{}
+// startRebaseMsg is sent to trigger the actual rebase after confirmation. The strategy picks
+// between rebasing onto main and merging or squash merging main into the branch.
+type startRebaseMsg struct {
+	strategy git.MergeStrategy
+}
+
+// rebaseFinishedMsg is sent when a background rebase completes
+type rebaseFinishedMsg struct {
+	instance    *session.Instance
+	branchName  string
+	originalSHA string
+	strategy    git.MergeStrategy
+	err         error
+}
+
+// patchResultMsg is sent when exporting or applying an instance patch finishes
+type patchResultMsg struct {
+	message   string
+	conflicts []string
+	// instance exported the patch at path, which is copied to the clipboard
+	instance *session.Instance
+	path     string
+	err      error
+}
+
+// checkpointResultMsg is sent when the agent has written (or failed to write) a checkpoint summary
+type checkpointResultMsg struct {
+	instance *session.Instance
+	name     string
+	summary  string
+	err      error
+}
+
+// shareResultMsg is sent when an instance's diff has been published as an HTML page
+type shareResultMsg struct {
+	instance *session.Instance
+	title    string
+	path     string
+	url      string
+	err      error
+}
+
+// diagnosticsResultMsg is sent when writing a diagnostics bundle finishes
+type diagnosticsResultMsg struct {
+	path string
+	err  error
+}
+
+// gitProgressMsg carries a progress update from a running git operation. next waits for the
+// following update.
+type gitProgressMsg struct {
+	// op is the operation running git
+	op       int
+	title    string
+	progress git.GitProgress
+	// log is the step log of the operation, if it keeps one
+	log  *overlay.StepLogOverlay
+	next tea.Cmd
+}
+
+// gitProgressDoneMsg is sent when the git operation for the instance has finished
+type gitProgressDoneMsg struct {
+	title string
+}
 
 // startGitResetMsg is sent to trigger the actual git reset after confirmation
 type startGitResetMsg struct{}
@@ -1846,6 +2950,12 @@ type instanceCreatedMsg struct {
 type instanceDeletedMsg struct {
 	title string
 	err   error
+	// notice is an optional success message to show once the instance is gone
+	notice string
+	// undo brings the instance back, if it can be
+	undo *undoEntry
+	// removed is the instance to remove from the list, if it's still there
+	removed *session.Instance
 }
 
 // testResultsMsg is sent when test results are available
@@ -1866,16 +2976,20 @@ type testProgressMsg struct {
 	running bool
 }
 
-// tickUpdateMetadataCmd is the callback to update the metadata of the instances every 500ms. Note that we iterate
-// overall the instances and capture their output. It's a pretty expensive operation. Let's do it 2x a second only.
-var tickUpdateMetadataCmd = func() tea.Msg {
-	time.Sleep(500 * time.Millisecond)
-	return tickUpdateMetadataMessage{}
+// sendStartupPrompt sends the first prompt of a starting instance once its agent accepts input,
+// without blocking the UI while it waits.
+func sendStartupPrompt(instance *session.Instance, prompt string) tea.Cmd {
+	return func() tea.Msg {
+		if err := instance.SendStartupPrompt(prompt); err != nil {
+			return fmt.Errorf("failed to send the prompt to %s: %w", instance.Title, err)
+		}
+		return nil
+	}
 }
 
 // startInstanceAsync starts an instance asynchronously and returns a tea.Cmd
 func (m *home) startInstanceAsync(instance *session.Instance) tea.Cmd {
-	return func() tea.Msg {
+	return m.trackOperation("start", instance.Title, false, func(context.Context) tea.Msg {
 		var resultErr error
 		done := make(chan struct{})
 
@@ -1891,16 +3005,105 @@ func (m *home) startInstanceAsync(instance *session.Instance) tea.Cmd {
 			instance: instance,
 			err:      resultErr,
 		}
+	})
+}
+
+// startAutoNamedInstance names the pending instance from the submitted prompt, starts it and
+// sends the prompt once it is running. Cancelling the prompt discards the instance.
+func (m *home) startAutoNamedInstance() (tea.Model, tea.Cmd) {
+	m.autoNamePending = false
+	instance := m.list.GetInstances()[m.list.NumInstances()-1]
+	submitted := m.textInputOverlay.IsSubmitted()
+	prompt := strings.TrimSpace(m.textInputOverlay.GetValue())
+	m.textInputOverlay = nil
+	m.state = stateDefault
+	m.menu.SetState(ui.StateDefault)
+
+	title := titleFromPrompt(prompt)
+	if !submitted || title == "" {
+		m.list.Kill()
+		if submitted {
+			return m, tea.Batch(tea.WindowSize(), m.instanceChanged(),
+				m.handleError(fmt.Errorf("cannot name a session from an empty prompt")))
+		}
+		return m, tea.Batch(tea.WindowSize(), m.instanceChanged())
+	}
+
+	existing := make(map[string]bool)
+	for _, other := range m.list.GetInstances() {
+		if other != instance {
+			existing[other.Title] = true
+		}
+	}
+	if err := instance.SetTitle(uniqueTitle(title, existing)); err != nil {
+		return m, m.handleError(err)
 	}
+	instance.Prompt = prompt
+
+	cmd := m.startInstanceAsync(instance)
+	if err := m.storage.SaveInstances(m.list.GetInstances()); err != nil {
+		return m, m.handleError(err)
+	}
+	m.newInstanceFinalizer()
+	if m.autoYes {
+		instance.AutoYes = true
+	}
+
+	return m, tea.Batch(tea.WindowSize(), m.instanceChanged(), cmd)
+}
+
+// titleFromPrompt builds an instance title from the first words of a prompt, lower-cased and
+// joined with dashes so it also makes a valid branch name.
+func titleFromPrompt(prompt string) string {
+	words := strings.FieldsFunc(strings.ToLower(prompt), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	if len(words) > autoNameWords {
+		words = words[:autoNameWords]
+	}
+
+	// Cut by runes so a title in another script isn't cut inside a character
+	title := []rune(strings.Join(words, "-"))
+	for len(title) > maxTitleLength {
+		cut := strings.LastIndex(string(title[:maxTitleLength]), "-")
+		if cut <= 0 {
+			title = title[:maxTitleLength]
+			break
+		}
+		title = []rune(string(title[:maxTitleLength])[:cut])
+	}
+	return string(title)
+}
+
+// uniqueTitle appends a numeric suffix to title if it is already taken.
+func uniqueTitle(title string, taken map[string]bool) string {
+	candidate := title
+	for n := 2; taken[candidate]; n++ {
+		suffix := fmt.Sprintf("-%d", n)
+		base := []rune(title)
+		if len(base)+len(suffix) > maxTitleLength {
+			base = base[:maxTitleLength-len(suffix)]
+		}
+		candidate = string(base) + suffix
+	}
+	return candidate
 }
 
 // killInstanceAsync kills an instance asynchronously and returns a tea.Cmd
 func (m *home) killInstanceAsync(instance *session.Instance) tea.Cmd {
-	return func() tea.Msg {
+	return m.trackOperation("kill", instance.Title, false, func(context.Context) tea.Msg {
 		var resultErr error
 		done := make(chan struct{})
 		title := instance.Title
 
+		// The branch is recorded before the kill deletes it, to undo the kill
+		var undo *undoEntry
+		if killed, err := instance.PrepareKill(); err != nil {
+			log.WarningLog.Printf("the kill of '%s' can't be undone: %v", title, err)
+		} else {
+			undo = &undoEntry{description: fmt.Sprintf("kill of '%s'", title), killed: &killed}
+		}
+
 		instance.KillAsync(func(err error) {
 			if err != nil {
 				// If normal kill fails, try force kill
@@ -1940,8 +3143,24 @@ func (m *home) killInstanceAsync(instance *session.Instance) tea.Cmd {
 		return instanceDeletedMsg{
 			title: title,
 			err:   resultErr,
+			undo:  undo,
 		}
-	}
+	})
+}
+
+// teardownInstanceAsync runs teardown in the background and has the instance removed from the
+// list once it succeeds. The string returned by teardown is shown as a success message, and the
+// entry it returns, if any, undoes it.
+func (m *home) teardownInstanceAsync(instance *session.Instance, teardown func() (string, *undoEntry, error)) tea.Cmd {
+	return m.trackOperation("kill", instance.Title, false, func(context.Context) tea.Msg {
+		instance.SetStatus(session.Deleting)
+		notice, undo, err := teardown()
+		if err != nil {
+			instance.SetStatus(session.Ready)
+			return instanceDeletedMsg{title: instance.Title, err: err}
+		}
+		return instanceDeletedMsg{title: instance.Title, notice: notice, undo: undo, removed: instance}
+	})
 }
 
 // calculateOverlayDimensions returns the width and height for overlay components
@@ -1951,32 +3170,69 @@ func (m *home) calculateOverlayDimensions() (width, height int) {
 	return width, height
 }
 
-// handleError handles all errors which get bubbled up to the app. sets the error message. We return a callback tea.Cmd that returns a hideErrMsg message
-// which clears the error message after 3 seconds.
+// handleError handles all errors which get bubbled up to the app. It shows an error toast
+// and records the error in the error log.
 func (m *home) handleError(err error) tea.Cmd {
 	log.ErrorLog.Printf("%v", err)
-	m.errBox.SetError(err)
+	return m.notify(ui.ToastError, err.Error())
+}
 
-	// Store error in the error log with timestamp
-	timestamp := time.Now().Format("15:04:05")
-	errorMsg := fmt.Sprintf("[%s] %v", timestamp, err)
-	m.errorLog = append(m.errorLog, errorMsg)
+// showSuccess shows a success toast. Successes are not recorded in the error log.
+func (m *home) showSuccess(message string) tea.Cmd {
+	log.InfoLog.Printf("%s", message)
+	return m.notify(ui.ToastSuccess, message)
+}
 
-	// Keep only the last 100 errors to prevent memory issues
-	if len(m.errorLog) > 100 {
-		m.errorLog = m.errorLog[len(m.errorLog)-100:]
+// notify pushes a toast of the given level and returns a tea.Cmd that dismisses it once the
+// configured duration for that level has elapsed. Warnings and errors are also appended to
+// the error log and get a "view log" action hint.
+func (m *home) notify(level ui.ToastLevel, message string) tea.Cmd {
+	var action *ui.ToastAction
+	if level == ui.ToastWarning || level == ui.ToastError {
+		m.appendErrorLog(message)
+		action = &ui.ToastAction{
+			Key:   keys.GlobalkeyBindings[keys.KeyErrorLog].Help().Key,
+			Label: "view log",
+		}
+	}
+
+	id := m.toastBox.Push(level, message, action)
+
+	duration := ui.DefaultToastDuration(level)
+	if m.appConfig != nil {
+		if d := m.appConfig.GetToastDuration(level.String()); d > 0 {
+			duration = d
+		}
 	}
 
 	return func() tea.Msg {
 		select {
 		case <-m.ctx.Done():
-		case <-time.After(3 * time.Second):
+		case <-time.After(duration):
 		}
 
-		return hideErrMsg{}
+		return hideToastMsg{id: id}
 	}
 }
 
+// appendErrorLog stores a message in the error log with a timestamp.
+func (m *home) appendErrorLog(message string) {
+	timestamp := time.Now().Format("15:04:05")
+	m.errorLog = append(m.errorLog, fmt.Sprintf("[%s] %s", timestamp, message))
+
+	// Keep only the last 100 errors to prevent memory issues
+	if len(m.errorLog) > 100 {
+		m.errorLog = m.errorLog[len(m.errorLog)-100:]
+	}
+}
+
+// runWithGitProgress runs op in the background with a worktree that reports fetch, clone and
+// push progress, and streams that progress to the UI until op returns. It's listed as a pending
+// operation of kind, and cancelling it stops the git command running.
+func (m *home) runWithGitProgress(kind, title string, worktree *git.GitWorktree, op func(wt *git.GitWorktree) tea.Msg) tea.Cmd {
+	return m.runWithGitSteps(kind, title, worktree, nil, op)
+}
+
 // createRemotePollingCmd creates a command that polls the remote for branch changes
 func (m *home) createRemotePollingCmd(branchName string, originalSHA string) tea.Cmd {
 	return func() tea.Msg {
@@ -1990,6 +3246,42 @@ func (m *home) createRemotePollingCmd(branchName string, originalSHA string) tea
 	}
 }
 
+// closeConfirmation closes the answered confirmation modal, executing the pending command if
+// it was confirmed.
+func (m *home) closeConfirmation() (tea.Model, tea.Cmd) {
+	// Capture confirmation state before clearing overlay
+	wasConfirmed := m.confirmationOverlay.IsConfirmed()
+
+	// Check if we should return to PR review state
+	returnToPRReview := m.prReviewOverlay != nil
+
+	m.confirmationOverlay = nil
+
+	// Execute pending command if confirmed
+	if wasConfirmed && m.pendingCmd != nil {
+		cmd := m.pendingCmd
+		m.pendingCmd = nil
+		// Execute the action and get the result
+		result := cmd()
+		// If result is a tea.Cmd, return it to be executed
+		if resultCmd, ok := result.(tea.Cmd); ok {
+			return m, resultCmd
+		}
+		// Otherwise handle as a message
+		return m.Update(result)
+	}
+	m.pendingCmd = nil
+
+	// Set appropriate state after handling confirmation
+	if returnToPRReview {
+		m.state = statePRReview
+	} else {
+		m.state = stateDefault
+	}
+
+	return m, nil
+}
+
 // confirmAction shows a confirmation modal and stores the action to execute on confirm
 func (m *home) confirmAction(message string, action tea.Cmd) tea.Cmd {
 	m.state = stateConfirm
@@ -2015,6 +3307,63 @@ func (m *home) confirmAction(message string, action tea.Cmd) tea.Cmd {
 	return nil
 }
 
+// confirmTyped shows a confirmation modal for an action that's hard to undo, which confirms
+// only once phrase, usually the instance's name, is typed. The action is executed the same way
+// as confirmAction's.
+func (m *home) confirmTyped(message, phrase string, action tea.Cmd) tea.Cmd {
+	m.state = stateConfirm
+	m.confirmationOverlay = overlay.NewTypedConfirmationOverlay(message, phrase)
+	m.confirmationOverlay.SetWidth(50)
+	m.pendingCmd = action
+	m.confirmationOverlay.OnConfirm = func() {
+		m.state = stateDefault
+	}
+	m.confirmationOverlay.OnCancel = func() {
+		m.state = stateDefault
+		m.pendingCmd = nil
+	}
+	return nil
+}
+
+// confirmChoice is one option of a multi-choice confirmation
+type confirmChoice struct {
+	key    string
+	label  string
+	action tea.Cmd
+}
+
+// confirmChoices shows a confirmation modal offering several labeled choices. The action of
+// the selected choice is executed the same way as confirmAction's.
+func (m *home) confirmChoices(message string, choices []confirmChoice) tea.Cmd {
+	m.state = stateConfirm
+
+	overlayChoices := make([]overlay.ConfirmationChoice, 0, len(choices))
+	for _, choice := range choices {
+		action := choice.action
+		overlayChoices = append(overlayChoices, overlay.ConfirmationChoice{
+			Key:   choice.key,
+			Label: choice.label,
+			OnSelect: func() {
+				m.pendingCmd = action
+			},
+		})
+	}
+
+	m.confirmationOverlay = overlay.NewConfirmationOverlayWithChoices(message, overlayChoices)
+	m.confirmationOverlay.SetWidth(50)
+
+	m.confirmationOverlay.OnConfirm = func() {
+		m.state = stateDefault
+	}
+
+	m.confirmationOverlay.OnCancel = func() {
+		m.state = stateDefault
+		m.pendingCmd = nil
+	}
+
+	return nil
+}
+
 func (m *home) View() string {
 	listWithPadding := lipgloss.NewStyle().PaddingTop(1).Render(m.list.String())
 	previewWithPadding := lipgloss.NewStyle().PaddingTop(1).Render(m.tabbedWindow.String())
@@ -2031,13 +3380,15 @@ func (m *home) View() string {
 			m.spinner.View(), m.rebaseBranchName))
 	}
 
-	mainView := lipgloss.JoinVertical(
-		lipgloss.Center,
-		rebaseIndicator,
-		listAndPreview,
-		m.menu.String(),
-		m.errBox.String(),
-	)
+	sections := []string{rebaseIndicator}
+	if banner := m.authBanner(); banner != "" {
+		sections = append(sections, banner)
+	}
+	if m.gitProgress.Active() {
+		sections = append(sections, m.gitProgress.String())
+	}
+	sections = append(sections, listAndPreview, m.menu.String(), m.toastBox.String())
+	mainView := lipgloss.JoinVertical(lipgloss.Center, sections...)
 
 	if m.state == statePrompt {
 		if m.textInputOverlay == nil {
@@ -2069,6 +3420,12 @@ func (m *home) View() string {
 			return mainView
 		}
 		return overlay.PlaceOverlay(0, 0, m.branchSelectorOverlay.View(), mainView, true, true)
+	} else if m.state == stateDiffBase {
+		if m.diffBaseOverlay == nil {
+			m.state = stateDefault
+			return mainView
+		}
+		return overlay.PlaceOverlay(0, 0, m.diffBaseOverlay.View(), mainView, true, true)
 	} else if m.state == stateErrorLog {
 		if m.textOverlay == nil {
 			log.ErrorLog.Printf("error log overlay is nil")
@@ -2091,6 +3448,56 @@ func (m *home) View() string {
 			return mainView
 		}
 		return overlay.PlaceOverlay(0, 0, m.textInputOverlay.Render(), mainView, true, true)
+	} else if m.state == stateOutcome {
+		if m.outcomeOverlay == nil {
+			log.ErrorLog.Printf("outcome overlay is nil")
+			m.state = stateDefault
+			return mainView
+		}
+		return overlay.PlaceOverlay(0, 0, m.outcomeOverlay.Render(), mainView, true, true)
+	} else if m.state == stateRebaseLog {
+		if m.rebaseLog == nil {
+			log.ErrorLog.Printf("rebase log overlay is nil")
+			m.state = stateDefault
+			return mainView
+		}
+		return overlay.PlaceOverlay(0, 0, m.rebaseLog.Render(), mainView, true, true)
+	} else if m.state == stateInteractiveRebase {
+		if m.interactiveRebaseOverlay == nil {
+			log.ErrorLog.Printf("interactive rebase overlay is nil")
+			m.state = stateDefault
+			return mainView
+		}
+		return overlay.PlaceOverlay(0, 0, m.interactiveRebaseOverlay.Render(), mainView, true, true)
+	} else if m.state == stateSearch {
+		if m.searchOverlay == nil {
+			log.ErrorLog.Printf("search overlay is nil")
+			m.state = stateDefault
+			return mainView
+		}
+		return overlay.PlaceOverlay(0, 0, m.searchOverlay.Render(), mainView, true, true)
+	} else if m.state == stateListSelect {
+		if m.listSelectorOverlay == nil {
+			log.ErrorLog.Printf("list selector overlay is nil")
+			m.state = stateDefault
+			return mainView
+		}
+		return overlay.PlaceOverlay(0, 0, m.listSelectorOverlay.Render(), mainView, true, true)
+	} else if m.state == stateBranchImport {
+		if m.branchImportOverlay == nil {
+			log.ErrorLog.Printf("branch import overlay is nil")
+			m.state = stateDefault
+			return mainView
+		}
+		return overlay.PlaceOverlay(0, 0, m.branchImportOverlay.Render(), mainView, true, true)
+	} else if m.state == stateCheckpoint || m.state == stateBaseRef || m.state == stateCommitMessage || m.state == stateGroupName ||
+		m.state == stateRepoPath || m.state == stateWorktreePath || m.state == stateBroadcast || m.state == stateWorkPrompt {
+		if m.textInputOverlay == nil {
+			log.ErrorLog.Printf("text input overlay is nil")
+			m.state = stateDefault
+			return mainView
+		}
+		return overlay.PlaceOverlay(0, 0, m.textInputOverlay.Render(), mainView, true, true)
 	} else if m.state == stateHistory {
 		if m.historyOverlay == nil {
 			log.ErrorLog.Printf("history overlay is nil")
@@ -2119,6 +3526,16 @@ func (m *home) View() string {
 			return mainView
 		}
 		return overlay.PlaceOverlay(0, 0, m.commentDetailOverlay.Render(), mainView, true, true)
+	} else if m.state == statePromptPreview && m.textOverlay != nil {
+		return overlay.PlaceOverlay(0, 0, m.textOverlay.Render(), mainView, true, true)
+	} else if m.state == statePromptQueue && m.promptQueueOverlay != nil {
+		return overlay.PlaceOverlay(0, 0, m.promptQueueOverlay.Render(), mainView, true, true)
+	} else if m.state == stateInstanceSettings && m.instanceSettingsOverlay != nil {
+		return overlay.PlaceOverlay(0, 0, m.instanceSettingsOverlay.Render(), mainView, true, true)
+	} else if m.state == stateAutoYesPolicy && m.autoYesPolicyOverlay != nil {
+		return overlay.PlaceOverlay(0, 0, m.autoYesPolicyOverlay.Render(), mainView, true, true)
+	} else if m.state == stateConflicts && m.conflictOverlay != nil {
+		return overlay.PlaceOverlay(0, 0, m.conflictOverlay.Render(), mainView, true, true)
 	}
 
 	return mainView
@@ -2128,9 +3545,264 @@ func (m *home) handleErrorLogState(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	// Any key press closes the error log
 	m.state = stateDefault
 	m.textOverlay = nil
+	if msg.String() == "e" {
+		return m, m.confirmDiagnosticsExport()
+	}
 	return m, nil
 }
 
+// shareInstance returns a confirm action that publishes the instance's diff as an HTML page,
+// optionally with a summary, and serves it if serve is set and enabled. The summary is written by
+// the summarizer if one is configured, and is the latest checkpoint's otherwise.
+func (m *home) shareInstance(instance *session.Instance, withSummary, serve bool) tea.Cmd {
+	return func() tea.Msg {
+		return tea.Cmd(func() tea.Msg {
+			worktree, err := instance.GetGitWorktree()
+			if err != nil {
+				return shareResultMsg{err: fmt.Errorf("failed to get git worktree: %w", err)}
+			}
+			stats := worktree.Diff()
+			if stats.Error != nil {
+				return shareResultMsg{err: fmt.Errorf("failed to get diff: %w", stats.Error)}
+			}
+
+			page := share.Page{
+				Title:   instance.Title,
+				Branch:  worktree.GetBranchName(),
+				Repo:    worktree.GetRepoName(),
+				Added:   stats.Added,
+				Removed: stats.Removed,
+				Diff:    stats.Content,
+			}
+			if withSummary {
+				summary, err := m.summarize(config.SummarizeDiff, worktree.GetWorktreePath(), diffSummaryPrompt, stats.Content)
+				if err != nil {
+					log.WarningLog.Printf("sharing the latest checkpoint summary instead: %v", err)
+				}
+				if summary == "" && len(instance.Checkpoints) > 0 {
+					summary = instance.Checkpoints[len(instance.Checkpoints)-1].Summary
+				}
+				page.Summary = summary
+			}
+
+			name, err := share.Publish(page)
+			if err != nil {
+				return shareResultMsg{err: err}
+			}
+			dir, err := share.Dir()
+			if err != nil {
+				return shareResultMsg{err: err}
+			}
+			result := shareResultMsg{instance: instance, title: instance.Title, path: filepath.Join(dir, name)}
+
+			if serve && m.appConfig.ShareServerEnabled() {
+				base, err := share.EnsureServer(m.appConfig.ShareAddr)
+				if err != nil {
+					return shareResultMsg{err: err}
+				}
+				result.url = base + "/" + name
+			}
+			return result
+		})
+	}
+}
+
+// showShareResult displays the published page's link with a QR code, copying the link (or the
+// file path if the page is not served) to the clipboard.
+func (m *home) showShareResult(msg shareResultMsg) tea.Cmd {
+	link := msg.url
+	if link == "" {
+		link = "file://" + msg.path
+	}
+	if err := m.copyResult(msg.instance, "share link", link); err != nil {
+		return m.handleError(err)
+	}
+
+	lines := []string{
+		titleStyle.Render(fmt.Sprintf("Shared diff - %s", msg.title)),
+		"",
+		descStyle.Render(link),
+		dimStyle.Render("(copied to clipboard)"),
+	}
+	if msg.url != "" {
+		if code, err := share.QRCode(msg.url); err == nil {
+			lines = append(lines, "", code)
+		}
+	}
+	lines = append(lines, "", dimStyle.Render("File: "+msg.path))
+
+	m.textOverlay = overlay.NewTextOverlay(lipgloss.JoinVertical(lipgloss.Left, lines...))
+	m.state = stateHelp
+	m.menu.SetState(ui.StateDefault)
+	return nil
+}
+
+// captureCheckpoint asks the agent for a context summary in the background and stores it as a
+// checkpoint note once it appears in the pane.
+func (m *home) captureCheckpoint(instance *session.Instance, name string) tea.Cmd {
+	return func() tea.Msg {
+		summary, err := instance.CaptureCheckpoint(checkpointTimeout)
+		return checkpointResultMsg{instance: instance, name: name, summary: summary, err: err}
+	}
+}
+
+// confirmDiagnosticsExport asks whether to redact the diagnostics bundle, then writes it in
+// the background.
+func (m *home) confirmDiagnosticsExport() tea.Cmd {
+	errorLog := append([]string{}, m.errorLog...)
+	export := func(redact bool) func() tea.Msg {
+		return func() tea.Msg {
+			return tea.Cmd(func() tea.Msg {
+				path, err := diagnostics.CreateBundle(diagnostics.Options{
+					Redact: redact,
+					Errors: errorLog,
+				})
+				return diagnosticsResultMsg{path: path, err: err}
+			})
+		}
+	}
+
+	return m.confirmChoices("Export diagnostics bundle for a bug report?", []confirmChoice{
+		{key: "r", label: "redacted (paths, emails and tokens scrubbed)", action: export(true)},
+		{key: "f", label: "full (no redaction)", action: export(false)},
+	})
+}
+
+// selectFromList shows a list overlay and calls onSelect with the chosen index, unless the
+// selection is cancelled.
+func (m *home) selectFromList(title string, items []overlay.ListItem, onSelect func(idx int) tea.Cmd) tea.Cmd {
+	m.listSelectorOverlay = overlay.NewListSelectorOverlay(title, items)
+	m.onListSelect = onSelect
+	m.state = stateListSelect
+	return tea.WindowSize()
+}
+
+// handleListSelectState handles key events for the list selector overlay
+func (m *home) handleListSelectState(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if !m.listSelectorOverlay.HandleKeyPress(msg) {
+		return m, nil
+	}
+
+	idx := m.listSelectorOverlay.SelectedIndex()
+	onSelect := m.onListSelect
+	m.listSelectorOverlay = nil
+	m.onListSelect = nil
+	m.state = stateDefault
+	m.menu.SetState(ui.StateDefault)
+
+	if idx < 0 || onSelect == nil {
+		return m, tea.WindowSize()
+	}
+	return m, tea.Batch(tea.WindowSize(), onSelect(idx))
+}
+
+// showBackups lists storage backups and restores the chosen one. Restoring exits the app
+// without saving, so the restored state is loaded on the next start.
+func (m *home) showBackups() tea.Cmd {
+	backups, err := config.ListBackups()
+	if err != nil {
+		return m.handleError(err)
+	}
+	if len(backups) == 0 {
+		return m.notify(ui.ToastInfo, "No storage backups yet")
+	}
+
+	items := make([]overlay.ListItem, len(backups))
+	for i, backup := range backups {
+		items[i] = overlay.ListItem{
+			Title:       backup.CreatedAt.Format("2006-01-02 15:04:05"),
+			Description: fmt.Sprintf("%s • %s", backup.Reason, strings.Join(backup.Files, ", ")),
+		}
+	}
+
+	return m.selectFromList("Restore Storage Backup", items, func(idx int) tea.Cmd {
+		backup := backups[idx]
+		restore := func() tea.Msg {
+			if err := config.RestoreBackup(backup.Name, m.appConfig.BackupCount); err != nil {
+				return err
+			}
+			m.exitMessage = fmt.Sprintf("Restored storage backup %s. Restart claude-squad to load it.", backup.Name)
+			m.cleanExit = true
+			// A command rather than its message, for closeConfirmation to run it
+			return tea.Cmd(tea.Quit)
+		}
+		message := fmt.Sprintf("[!] Restore backup from %s? claude-squad exits without saving the current state.",
+			backup.CreatedAt.Format("2006-01-02 15:04:05"))
+		return m.confirmAction(message, restore)
+	})
+}
+
+// handleBranchImportState handles key events in the branch import wizard and imports the
+// selected branches as paused instances once confirmed.
+func (m *home) handleBranchImportState(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if !m.branchImportOverlay.HandleKeyPress(msg) {
+		return m, nil
+	}
+
+	branches := m.branchImportOverlay.SelectedBranches()
+	imported := m.branchImportOverlay.IsImported()
+	m.branchImportOverlay = nil
+	m.state = stateDefault
+	m.menu.SetState(ui.StateDefault)
+	if !imported {
+		return m, tea.WindowSize()
+	}
+
+	if limit := m.instanceLimit(); m.list.NumInstances()+len(branches) > limit {
+		return m, m.handleError(fmt.Errorf("importing %d branches would exceed the limit of %d instances", len(branches), limit))
+	}
+	if err := m.policy.CheckProgram(m.program); err != nil {
+		return m, m.handleError(err)
+	}
+
+	taken := make(map[string]bool)
+	for _, instance := range m.list.GetInstances() {
+		taken[instance.Title] = true
+	}
+
+	var errs []string
+	count := 0
+	for _, branch := range branches {
+		title := uniqueTitle(titleFromBranch(branch, m.appConfig.BranchPrefix), taken)
+		instance, err := session.NewImportedInstance(session.InstanceOptions{
+			Title:      title,
+			Path:       m.repoPath,
+			Program:    m.program,
+			BranchName: branch,
+		})
+		if err != nil {
+			errs = append(errs, err.Error())
+			continue
+		}
+		taken[title] = true
+		m.list.AddInstance(instance)()
+		count++
+	}
+
+	if err := m.storage.SaveInstances(m.list.GetInstances()); err != nil {
+		return m, m.handleError(err)
+	}
+
+	cmds := []tea.Cmd{tea.WindowSize(), m.instanceChanged()}
+	if count > 0 {
+		cmds = append(cmds, m.showSuccess(fmt.Sprintf("Imported %d branches as paused sessions", count)))
+	}
+	if len(errs) > 0 {
+		cmds = append(cmds, m.handleError(fmt.Errorf("failed to import %d branches: %s", len(errs), strings.Join(errs, "; "))))
+	}
+	return m, tea.Batch(cmds...)
+}
+
+// titleFromBranch builds an instance title from a branch name by dropping the configured branch
+// prefix and replacing path separators.
+func titleFromBranch(branch, prefix string) string {
+	title := strings.ReplaceAll(strings.TrimPrefix(branch, prefix), "/", "-")
+	if runes := []rune(title); len(runes) > maxTitleLength {
+		title = string(runes[:maxTitleLength])
+	}
+	return title
+}
+
 // handleHistoryState handles key events when in history state
 func (m *home) handleHistoryState(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	// Let the history overlay handle the key press
@@ -2219,7 +3891,10 @@ func (m *home) showErrorLog() (tea.Model, tea.Cmd) {
 	// Create content for error log
 	var content string
 	if len(m.errorLog) == 0 {
-		content = "No errors have been logged."
+		content = lipgloss.JoinVertical(lipgloss.Left,
+			"No errors have been logged.",
+			"",
+			dimStyle.Render("Press e to export a diagnostics bundle • any other key to close"))
 	} else {
 		content = lipgloss.JoinVertical(lipgloss.Left,
 			titleStyle.Render("Error Log"),
@@ -2237,7 +3912,7 @@ func (m *home) showErrorLog() (tea.Model, tea.Cmd) {
 		content = lipgloss.JoinVertical(lipgloss.Left,
 			content,
 			"",
-			dimStyle.Render("Press any key to close"))
+			dimStyle.Render("Press e to export a diagnostics bundle • any other key to close"))
 	}
 
 	// Create text overlay
@@ -2257,20 +3932,48 @@ func (m *home) createInstanceWithBranch(branchName string) (tea.Model, tea.Cmd)
 	// Create a new instance with the selected branch
 	instance, err := session.NewInstanceWithBranch(session.InstanceOptions{
 		Title:      title,
-		Path:       ".",
+		Path:       m.repoPath,
 		Program:    m.program,
 		BranchName: branchName,
 	})
+	return m.startSelectedInstance(instance, err)
+}
+
+// createInstanceFromRef creates an instance on a new branch starting at a tag or commit picked in
+// the branch selector, named after the tag or abbreviated commit.
+func (m *home) createInstanceFromRef(ref string) (tea.Model, tea.Cmd) {
+	sha, err := git.ResolveCommit(m.repoPath, ref)
+	if err != nil {
+		return m.startSelectedInstance(nil, err)
+	}
+
+	name := strings.TrimPrefix(ref, "refs/tags/")
+	if len(name) > 7 && strings.HasPrefix(sha, name) {
+		name = name[:7]
+	}
+	title := fmt.Sprintf("%s-%s", name, time.Now().Format("150405"))
+
+	instance, err := session.NewInstance(session.InstanceOptions{
+		Title:      title,
+		Path:       m.repoPath,
+		Program:    m.program,
+		BaseBranch: ref,
+	})
+	return m.startSelectedInstance(instance, err)
+}
+
+// startSelectedInstance adds and starts an instance created from the branch selector, or reports
+// the error creating it.
+func (m *home) startSelectedInstance(instance *session.Instance, err error) (tea.Model, tea.Cmd) {
+	m.branchSelectorOverlay = nil
 	if err != nil {
 		m.state = stateDefault
 		m.menu.SetState(ui.StateDefault)
-		m.branchSelectorOverlay = nil
 		return m, m.handleError(err)
 	}
 
 	m.newInstanceFinalizer = m.list.AddInstance(instance)
 	m.list.SetSelectedInstance(m.list.NumInstances() - 1)
-	m.branchSelectorOverlay = nil
 
 	// Start the instance asynchronously
 	cmd := m.startInstanceAsync(instance)
@@ -2297,41 +4000,30 @@ func (m *home) showHistoryView() tea.Cmd {
 		return nil
 	}
 
-	var content string
-	var title string
-	var err error
+	// Capture both panes up front so the overlay can switch between them; a pane that can't be
+	// captured shows its error instead
+	views := []overlay.HistoryView{
+		{Name: "AI", Content: historyContent(selected.GetAIFullHistory())},
+		{Name: "Terminal", Content: historyContent(selected.GetTerminalFullHistory())},
+		{Name: "Combined", Content: historyContent(combinedHistory(selected))},
+	}
 
-	// Determine which pane's history to show based on the active tab
+	// Start on the pane matching the active tab
+	active := 0
 	if m.tabbedWindow.IsInTerminalTab() {
-		// Show terminal pane history
-		content, err = selected.GetTerminalFullHistory()
-		if err != nil {
-			return m.handleError(fmt.Errorf("failed to get terminal history: %v", err))
-		}
-		title = fmt.Sprintf("Terminal History - %s", selected.Title)
-	} else if m.tabbedWindow.IsInAITab() {
-		// Show AI pane history
-		content, err = selected.GetAIFullHistory()
-		if err != nil {
-			return m.handleError(fmt.Errorf("failed to get AI history: %v", err))
-		}
-		title = fmt.Sprintf("AI History - %s", selected.Title)
-	} else {
-		// Default to AI pane if we're in diff view
-		content, err = selected.GetAIFullHistory()
-		if err != nil {
-			return m.handleError(fmt.Errorf("failed to get AI history: %v", err))
-		}
-		title = fmt.Sprintf("AI History - %s", selected.Title)
+		active = 1
 	}
 
 	// Create the history overlay
-	m.historyOverlay = overlay.NewHistoryOverlay(title, content)
+	m.historyOverlay = overlay.NewTabbedHistoryOverlay(fmt.Sprintf("History - %s", selected.Title), views, active)
 	m.historyOverlay.OnDismiss = func() {
 		m.state = stateDefault
 		m.menu.SetState(ui.StateDefault)
 		m.historyOverlay = nil
 	}
+	m.historyOverlay.OnExport = func() tea.Cmd {
+		return m.exportHistory(selected)
+	}
 
 	// Set state to history
 	m.state = stateHistory
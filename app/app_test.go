@@ -1,15 +1,25 @@
 package app
 
 import (
+	"claude-squad/approvals"
 	"claude-squad/config"
+	"claude-squad/keys"
 	"claude-squad/log"
+	"claude-squad/scripting"
 	"claude-squad/session"
+	"claude-squad/session/git"
+	"claude-squad/session/tmux"
 	"claude-squad/ui"
 	"claude-squad/ui/overlay"
 	"context"
+	"encoding/json"
 	"fmt"
 	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/charmbracelet/bubbles/spinner"
 	tea "github.com/charmbracelet/bubbletea"
@@ -105,6 +115,66 @@ func TestConfirmationModalStateTransitions(t *testing.T) {
 	})
 }
 
+// TestConfirmChoices tests that multi-choice confirmations run the action of the chosen option
+func TestConfirmChoices(t *testing.T) {
+	h := &home{
+		ctx:       context.Background(),
+		state:     stateDefault,
+		appConfig: config.DefaultConfig(),
+	}
+
+	var chosen string
+	choice := func(name string) tea.Cmd {
+		return func() tea.Msg {
+			chosen = name
+			return nil
+		}
+	}
+
+	newConfirm := func() {
+		chosen = ""
+		h.confirmChoices("[!] Kill session 'test'?", []confirmChoice{
+			{key: "d", label: "delete", action: choice("delete")},
+			{key: "k", label: "keep branch", action: choice("keep")},
+		})
+	}
+
+	t.Run("selecting a choice runs its action", func(t *testing.T) {
+		newConfirm()
+		require.Equal(t, stateConfirm, h.state)
+
+		model, _ := h.handleKeyPress(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("k")})
+		homeModel := model.(*home)
+
+		assert.Equal(t, "keep", chosen)
+		assert.Equal(t, stateDefault, homeModel.state)
+		assert.Nil(t, homeModel.confirmationOverlay)
+		assert.Nil(t, homeModel.pendingCmd)
+	})
+
+	t.Run("y does not confirm a multi-choice prompt", func(t *testing.T) {
+		newConfirm()
+
+		model, _ := h.handleKeyPress(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("y")})
+		homeModel := model.(*home)
+
+		assert.Equal(t, "", chosen)
+		assert.Equal(t, stateConfirm, homeModel.state)
+		assert.NotNil(t, homeModel.confirmationOverlay)
+	})
+
+	t.Run("esc cancels without running any action", func(t *testing.T) {
+		newConfirm()
+
+		model, _ := h.handleKeyPress(tea.KeyMsg{Type: tea.KeyEscape})
+		homeModel := model.(*home)
+
+		assert.Equal(t, "", chosen)
+		assert.Equal(t, stateDefault, homeModel.state)
+		assert.Nil(t, homeModel.pendingCmd)
+	})
+}
+
 // TestConfirmationModalKeyHandling tests the actual key handling in confirmation state
 func TestConfirmationModalKeyHandling(t *testing.T) {
 	// Import needed packages
@@ -464,3 +534,1008 @@ func TestConfirmationModalVisualAppearance(t *testing.T) {
 	// Test that the danger indicator is preserved
 	assert.Contains(t, rendered, "[!")
 }
+
+func TestTitleFromPrompt(t *testing.T) {
+	tests := []struct {
+		prompt   string
+		expected string
+	}{
+		{"Fix the login bug", "fix-the-login-bug"},
+		{"Add retries to the HTTP client, then update docs", "add-retries-to-the-http"},
+		{"  refactor: parser/lexer (v2)!  ", "refactor-parser-lexer-v2"},
+		{"internationalization localization accessibility", "internationalization"},
+		{"!!!", ""},
+		{"Исправить ошибку входа в личный кабинет", "исправить-ошибку-входа-в-личный"},
+		{"国际化本地化无障碍功能国际化本地化无障碍功能国际化本地化无障碍功能国际化本地化", "国际化本地化无障碍功能国际化本地化无障碍功能国际化本地化无障碍功"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.prompt, func(t *testing.T) {
+			assert.Equal(t, tt.expected, titleFromPrompt(tt.prompt))
+		})
+	}
+}
+
+func TestUniqueTitle(t *testing.T) {
+	taken := map[string]bool{"fix-bug": true, "fix-bug-2": true}
+	assert.Equal(t, "fix-bug-3", uniqueTitle("fix-bug", taken))
+	assert.Equal(t, "new-task", uniqueTitle("new-task", taken))
+
+	long := strings.Repeat("a", maxTitleLength)
+	assert.Equal(t, strings.Repeat("a", maxTitleLength-2)+"-2", uniqueTitle(long, map[string]bool{long: true}))
+	wide := strings.Repeat("ж", maxTitleLength)
+	assert.Equal(t, strings.Repeat("ж", maxTitleLength-2)+"-2", uniqueTitle(wide, map[string]bool{wide: true}))
+}
+
+func TestTitleFromBranch(t *testing.T) {
+	assert.Equal(t, "fix-login", titleFromBranch("cs/fix-login", "cs/"))
+	assert.Equal(t, "feature-api-v2", titleFromBranch("feature/api/v2", "cs/"))
+	assert.Equal(t, strings.Repeat("a", maxTitleLength), titleFromBranch(strings.Repeat("a", 40), ""))
+	assert.Equal(t, strings.Repeat("ж", maxTitleLength), titleFromBranch(strings.Repeat("ж", 40), ""),
+		"long titles are cut between characters")
+}
+
+func TestNewInstanceTitleRunes(t *testing.T) {
+	spinner := spinner.New(spinner.WithSpinner(spinner.MiniDot))
+	h := &home{
+		ctx:       context.Background(),
+		state:     stateNew,
+		appConfig: config.DefaultConfig(),
+		list:      ui.NewList(&spinner, false),
+		toastBox:  ui.NewToastBox(),
+	}
+	instance, err := session.NewInstance(session.InstanceOptions{Title: "", Path: ".", Program: "claude"})
+	require.NoError(t, err)
+	h.list.AddInstance(instance)()
+
+	// Titles are limited by characters, not bytes
+	for n := 0; n < maxTitleLength; n++ {
+		h.handleKeyPress(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("ж")})
+	}
+	assert.Equal(t, strings.Repeat("ж", maxTitleLength), instance.Title)
+	h.handleKeyPress(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("ж")})
+	assert.Equal(t, strings.Repeat("ж", maxTitleLength), instance.Title)
+
+	// Backspace removes a whole character
+	h.handleKeyPress(tea.KeyMsg{Type: tea.KeyBackspace})
+	assert.Equal(t, strings.Repeat("ж", maxTitleLength-1), instance.Title)
+}
+
+func TestRenderCombinedHistory(t *testing.T) {
+	at := time.Date(2024, 1, 1, 12, 0, 5, 0, time.UTC)
+	content := renderCombinedHistory([]tmux.TimedLine{
+		{Text: "old prompt", Source: "ai"},
+		{Text: "$ go test", Source: "terminal", At: at},
+		{Text: "PASS", Source: "terminal", At: at.Add(100 * time.Millisecond)},
+		{Text: "done", Source: "ai", At: at.Add(2 * time.Second)},
+	})
+
+	lines := strings.Split(content, "\n")
+	require.Len(t, lines, 7)
+	assert.Contains(t, lines[0], "time unknown")
+	assert.Contains(t, lines[1], "old prompt")
+	assert.Contains(t, lines[2], "12:00:05")
+	assert.Contains(t, lines[3], "$ go test")
+	// Lines within the same second share a separator
+	assert.Contains(t, lines[4], "PASS")
+	assert.Contains(t, lines[5], "12:00:07")
+	assert.Contains(t, lines[6], "done")
+}
+
+func TestDefaultCommentPromptTemplate(t *testing.T) {
+	comment := &git.PRComment{Type: "review_comment", Author: "alice", Path: "main.go", Line: 12, Body: "Rename this."}
+	data, ok := newCommentPromptData(comment, 2, 3, "claude")
+	require.True(t, ok)
+	prompt, err := renderCommentPrompt(defaultCommentPromptTemplate, data)
+	require.NoError(t, err)
+	assert.Equal(t, "Processing PR comment 2 of 3 from @alice\n\n"+
+		"=== PR REVIEW COMMENT ===\n\n"+
+		"Author: @alice\nType: Review Comment\nFile: main.go (line 12)\n"+
+		"\nComment:\n---\nRename this.\n---\n\n"+
+		"This is a line-specific review comment. Please address the specific code feedback. "+
+		"If the comment is asking a question, provide a clear answer. If it's suggesting a change, implement it. "+
+		"If you need clarification, explain what's unclear.", prompt)
+
+	split := &git.PRComment{Type: "issue_comment", Author: "bob", IsSplit: true, SplitPieces: []git.CommentPiece{
+		{Content: "first", Accepted: true},
+		{Content: "skipped"},
+		{Content: "second", Accepted: true},
+	}}
+	data, ok = newCommentPromptData(split, 1, 1, "claude")
+	require.True(t, ok)
+	prompt, err = renderCommentPrompt(defaultCommentPromptTemplate, data)
+	require.NoError(t, err)
+	assert.Contains(t, prompt, "from @bob (2 pieces selected)\n")
+	assert.Contains(t, prompt, "Type: General Comment\n\nComment:\n---\nNote: This comment has been split")
+	assert.Contains(t, prompt, "included:\n\nfirst\n\nsecond\n---\n")
+	assert.NotContains(t, prompt, "skipped")
+
+	split.SplitPieces[0].Accepted = false
+	split.SplitPieces[2].Accepted = false
+	_, ok = newCommentPromptData(split, 1, 1, "claude")
+	assert.False(t, ok)
+}
+
+func TestCommentPromptTemplateSelection(t *testing.T) {
+	h := &home{appConfig: &config.Config{
+		CommentPromptTemplate:       "{{.Path}}:{{.Line}} {{.Body}}",
+		AgentCommentPromptTemplates: map[string]string{"aider": "/ask {{.Body}}"},
+	}}
+
+	text, source := h.commentPromptTemplate("/usr/local/bin/aider --model x")
+	assert.Equal(t, "/ask {{.Body}}", text)
+	assert.Contains(t, source, "aider")
+
+	text, _ = h.commentPromptTemplate("claude")
+	data, ok := newCommentPromptData(&git.PRComment{Type: "review_comment", Path: "a.go", Line: 3, Body: "fix"}, 1, 1, "claude")
+	require.True(t, ok)
+	prompt, err := renderCommentPrompt(text, data)
+	require.NoError(t, err)
+	assert.Equal(t, "a.go:3 fix", prompt)
+
+	_, err = renderCommentPrompt("{{.Missing}}", data)
+	assert.Error(t, err)
+}
+
+func TestFormatFixPrompt(t *testing.T) {
+	spinner := spinner.New(spinner.WithSpinner(spinner.MiniDot))
+	h := &home{appConfig: &config.Config{}, list: ui.NewList(&spinner, false), program: "claude"}
+	comments := []*git.PRComment{
+		{Type: "review_comment", Author: "alice", Path: "main.go", Line: 12, Body: "Rename this.\n", ThreadID: "T1"},
+		{Type: "issue_comment", Author: "bob", IsSplit: true, SplitPieces: []git.CommentPiece{{Content: "nothing accepted"}}},
+		{Type: "issue_comment", Author: "carol", IsSplit: true, SplitPieces: []git.CommentPiece{
+			{Content: "first", Accepted: true},
+			{Content: "skipped"},
+			{Content: "second", Accepted: true},
+		}},
+	}
+
+	assert.Equal(t, "Please address the following 2 accepted PR comments. Work through them in order: "+
+		"implement requested changes, answer questions, and explain what is unclear if you need clarification.\n"+
+		"\n### 1. main.go:12 (Review Comment from @alice)\n\nRename this.\n"+
+		"\n### 2. General (General Comment from @carol)\nOnly these selected parts of the comment apply:\n"+
+		"\n- first\n\n- second\n"+
+		"\nWhen you have addressed all of them, summarize what you changed for each.", h.formatFixPrompt(comments))
+	assert.Empty(t, h.formatFixPrompt(comments[1:2]))
+
+	h.appConfig.CommentPromptTemplate = "{{.Index}}/{{.Total}} {{.Author}}"
+	assert.Equal(t, "1/2 alice\n\n2/2 carol", h.formatFixPrompt(comments))
+
+	assert.Equal(t, []string{"T1"}, reviewThreads(comments))
+	comments[0].IsResolved = true
+	assert.Empty(t, reviewThreads(comments))
+}
+
+func TestParseSuggestedTestFiles(t *testing.T) {
+	output := "Here are the tests.\n" +
+		"=== FILE: pkg/a_test.go ===\npackage pkg\n\nfunc TestA(t *testing.T) {}\n=== END FILE ===\n" +
+		"Some commentary.\n" +
+		"=== FILE: web/b.test.ts ===\ntest('b', () => {})\n=== END FILE ===\n" +
+		"=== FILE: unterminated.go ===\npackage x\n"
+	files := parseSuggestedTestFiles(output)
+	require.Len(t, files, 2)
+	assert.Equal(t, "pkg/a_test.go", files[0].path)
+	assert.Equal(t, "package pkg\n\nfunc TestA(t *testing.T) {}\n", files[0].content)
+	assert.Equal(t, "web/b.test.ts", files[1].path)
+	assert.Equal(t, "test('b', () => {})\n", files[1].content)
+}
+
+func TestSummarize(t *testing.T) {
+	h := &home{appConfig: config.DefaultConfig()}
+	dir := t.TempDir()
+
+	// Without a summarizer the features keep their built-in behavior
+	summary, err := h.summarize(config.SummarizeDiff, dir, diffSummaryPrompt, "diff")
+	require.NoError(t, err)
+	assert.Empty(t, summary)
+
+	h.appConfig.Summarizer = &config.SummarizerConfig{
+		Command: "tail -n 1",
+		Overrides: map[string]config.SummarizerOverride{
+			config.SummarizeCommitMessage: {Command: "sleep 5", TimeoutSeconds: 1},
+		},
+	}
+	summary, err = h.summarize(config.SummarizeDiff, dir, diffSummaryPrompt, "first\nlast\n")
+	require.NoError(t, err)
+	assert.Equal(t, "last", summary, "the input follows the prompt on stdin")
+
+	_, err = h.summarize(config.SummarizeCommitMessage, dir, commitMessagePrompt, "diff")
+	assert.ErrorContains(t, err, "timed out")
+}
+
+func TestSplitPRDescription(t *testing.T) {
+	title, body := splitPRDescription("\n# Add CSV export\n\nAdds an export button.\n\n- CSV only\n")
+	assert.Equal(t, "Add CSV export", title)
+	assert.Equal(t, "Adds an export button.\n\n- CSV only", body)
+
+	title, body = splitPRDescription("Fix typo")
+	assert.Equal(t, "Fix typo", title)
+	assert.Empty(t, body)
+}
+
+func TestResolveSuggestedPath(t *testing.T) {
+	target, err := resolveSuggestedPath("/work/tree", "pkg/./a_test.go")
+	require.NoError(t, err)
+	assert.Equal(t, "/work/tree/pkg/a_test.go", target)
+
+	for _, p := range []string{"/etc/passwd", "../outside_test.go", "pkg/../../x_test.go", "."} {
+		_, err := resolveSuggestedPath("/work/tree", p)
+		assert.Error(t, err, p)
+	}
+}
+
+func TestConventionTestFiles(t *testing.T) {
+	tracked := []string{
+		"README.md",
+		"other/x_test.go",
+		"pkg/a.go",
+		"pkg/a_test.go",
+		"web/src/__tests__/view.tsx",
+		"web/src/button.spec.ts",
+	}
+	picked := conventionTestFiles(tracked, []string{"pkg/a.go"})
+	assert.Equal(t, []string{"pkg/a_test.go", "other/x_test.go", "web/src/__tests__/view.tsx", "web/src/button.spec.ts"}, picked)
+}
+
+func TestMergeStrategyChoices(t *testing.T) {
+	keysOf := func(choices []confirmChoice) []string {
+		var keys []string
+		for _, choice := range choices {
+			keys = append(keys, choice.key)
+		}
+		return keys
+	}
+
+	h := &home{appConfig: &config.Config{}}
+	assert.Equal(t, []string{"r", "m", "s"}, keysOf(h.mergeStrategyChoices()))
+
+	h.appConfig.MergeStrategy = "squash"
+	choices := h.mergeStrategyChoices()
+	assert.Equal(t, []string{"s", "r", "m"}, keysOf(choices))
+	assert.Equal(t, startRebaseMsg{strategy: git.MergeStrategySquash}, choices[0].action())
+
+	h.appConfig.MergeStrategy = "octopus"
+	assert.Equal(t, []string{"r", "m", "s"}, keysOf(h.mergeStrategyChoices()))
+}
+
+func TestCheckNewInstanceAppliesPolicy(t *testing.T) {
+	spinner := spinner.New(spinner.WithSpinner(spinner.MiniDot))
+	h := &home{
+		appConfig: config.DefaultConfig(),
+		list:      ui.NewList(&spinner, false),
+		policy:    &config.Policy{Source: "policy.json", AllowedPrograms: []string{"claude"}, MaxInstances: 1},
+	}
+
+	assert.Equal(t, 1, h.instanceLimit())
+	assert.NoError(t, h.checkNewInstance("claude --model opus"))
+	assert.ErrorContains(t, h.checkNewInstance("aider"), "not allowed")
+
+	instance, err := session.NewInstance(session.InstanceOptions{Title: "one", Path: ".", Program: "claude"})
+	require.NoError(t, err)
+	h.list.AddInstance(instance)()
+	assert.ErrorContains(t, h.checkNewInstance("claude"), "more than 1 instances")
+}
+
+func TestProgramChoices(t *testing.T) {
+	claude := config.ProgramSuggestion{Program: "claude", Reason: "CLAUDE.md found"}
+	aider := config.ProgramSuggestion{Program: "aider", Reason: ".aider.conf.yml found"}
+	templates := []config.InstanceTemplate{
+		{Name: "review", Program: "aider --model sonnet"},
+		{Name: "default", Prompt: "hi"},
+	}
+
+	assert.Nil(t, programChoices("/usr/bin/claude", []config.ProgramSuggestion{claude}, templates),
+		"no list when the repository only suggests the default program")
+
+	choices := programChoices("/usr/bin/claude", []config.ProgramSuggestion{claude, aider}, templates)
+	require.Len(t, choices, 3)
+	assert.Equal(t, "/usr/bin/claude", choices[0].program)
+	assert.Equal(t, "default • CLAUDE.md found", choices[0].item.Description)
+	assert.Equal(t, "aider", choices[1].program)
+	require.NotNil(t, choices[2].template)
+	assert.Equal(t, "review", choices[2].template.Name)
+}
+
+func TestInstanceSettingsForm(t *testing.T) {
+	instance, err := session.NewInstance(session.InstanceOptions{Title: "one", Path: ".", Program: "claude"})
+	require.NoError(t, err)
+	instance.Tags = []string{"old"}
+
+	form := overlay.NewInstanceSettingsOverlay("Settings", overlay.InstanceSettings{
+		Notifications: true,
+		Tags:          instance.Tags,
+	}, 0)
+	press := func(keys ...string) bool {
+		closed := false
+		for _, key := range keys {
+			msg := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(key)}
+			switch key {
+			case "enter":
+				msg = tea.KeyMsg{Type: tea.KeyEnter}
+			case "down":
+				msg = tea.KeyMsg{Type: tea.KeyDown}
+			case "right":
+				msg = tea.KeyMsg{Type: tea.KeyRight}
+			}
+			closed = form.HandleKeyPress(msg)
+		}
+		return closed
+	}
+
+	assert.False(t, press("down", "down", "s", "o", "o", "n", "enter"), "an invalid budget keeps the form open")
+
+	// Turn auto-yes on, mute notifications, set a budget and add a tag
+	form = overlay.NewInstanceSettingsOverlay("Settings", overlay.InstanceSettings{Notifications: true, Tags: instance.Tags}, 0)
+	assert.True(t, press("right", "down", "right", "down", "4", "5", "m", "down", ",", " ", "a", ",", "o", "l", "d", "enter"))
+	require.True(t, form.IsSubmitted())
+
+	require.NoError(t, applyInstanceSettings(instance, form.Settings(), nil))
+	assert.True(t, instance.AutoYes)
+	assert.True(t, instance.MuteNotifications)
+	assert.Equal(t, 45*time.Minute, instance.TimeBudget)
+	assert.Equal(t, []string{"old", "a"}, instance.Tags)
+
+	data := instance.ToInstanceData()
+	assert.Equal(t, []string{"old", "a"}, data.Tags)
+	assert.Equal(t, 45*time.Minute, data.TimeBudget)
+	assert.True(t, data.MuteNotifications)
+
+	// A policy turning auto-yes off locks the toggle, and the settings can't turn it on anyway
+	policy := &config.Policy{Settings: map[string]json.RawMessage{"auto_yes": json.RawMessage("false")}, Source: "policy.json"}
+	instance.AutoYes = false
+	form = overlay.NewInstanceSettingsOverlay("Settings", overlay.InstanceSettings{Notifications: true}, 0)
+	form.LockAutoYes()
+	assert.True(t, press("right", "enter"))
+	assert.False(t, form.Settings().AutoYes)
+	assert.ErrorContains(t, applyInstanceSettings(instance, overlay.InstanceSettings{AutoYes: true}, policy), "auto-yes is disabled")
+	assert.False(t, instance.AutoYes)
+}
+
+func TestAutoYesPolicyForm(t *testing.T) {
+	form := overlay.NewAutoYesPolicyOverlay(&config.AutoYesPolicy{Allow: []string{`Run tests\?`}})
+	press := func(keys ...tea.KeyMsg) bool {
+		closed := false
+		for _, key := range keys {
+			closed = form.HandleKeyPress(key)
+		}
+		return closed
+	}
+	typed := func(text string) tea.KeyMsg { return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(text)} }
+	save := tea.KeyMsg{Type: tea.KeyCtrlS}
+
+	// Deny a pattern that doesn't compile, then fix it
+	assert.False(t, press(tea.KeyMsg{Type: tea.KeyTab}, typed("(?i)delete("), save), "an invalid pattern keeps the form open")
+	assert.True(t, press(tea.KeyMsg{Type: tea.KeyBackspace}, tea.KeyMsg{Type: tea.KeyEnter}, typed("rm -rf"), save))
+	require.True(t, form.IsSubmitted())
+	assert.Equal(t, &config.AutoYesPolicy{Allow: []string{`Run tests\?`}, Deny: []string{"(?i)delete", "rm -rf"}}, form.Policy())
+
+	assert.Nil(t, overlay.NewAutoYesPolicyOverlay(nil).Policy(), "empty lists are no policy")
+}
+
+func TestSearchInstances(t *testing.T) {
+	payments := &session.Instance{Title: "payments", Branch: "cs/payments"}
+	docs := []searchDoc{
+		{instance: payments, fields: []searchField{
+			{name: "title", tab: -1, text: payments.Title},
+			{name: "branch", tab: -1, text: payments.Branch},
+			{name: "diff", tab: ui.DiffTab, text: "diff --git a/api/payments.go b/api/payments.go\n--- a/api/payments.go\n+++ b/api/payments.go\n@@ -1 +1 @@\n-old\n+func Refund() {}"},
+		}},
+		{instance: &session.Instance{Title: "docs"}, fields: []searchField{
+			{name: "AI", tab: ui.AITab, text: "I'll update README.md\nDone updating readme.md"},
+		}},
+	}
+
+	hits := searchInstances(docs, "PAYMENTS.GO")
+	require.Len(t, hits, 1)
+	assert.Equal(t, payments, hits[0].instance)
+	assert.Equal(t, ui.DiffTab, hits[0].tab)
+	assert.Equal(t, 3, hits[0].count)
+
+	// A match in a diff names the file it is in
+	hits = searchInstances(docs, "refund")
+	require.Len(t, hits, 1)
+	assert.Equal(t, "api/payments.go: +func Refund() {}", hits[0].snippet)
+
+	hits = searchInstances(docs, "readme")
+	require.Len(t, hits, 1)
+	assert.Equal(t, "AI", hits[0].field)
+	assert.Equal(t, "I'll update README.md", hits[0].snippet)
+	assert.Equal(t, 2, hits[0].count)
+
+	assert.Len(t, searchInstances(docs, "payments"), 3, "title, branch and diff each match once")
+	assert.Empty(t, searchInstances(docs, "  "))
+}
+
+func TestOfferGitCommands(t *testing.T) {
+	plan := choicePlans(
+		choicePlan{key: "d", plan: func() ([]string, error) { return []string{"git worktree prune"}, nil }},
+		choicePlan{key: "k", plan: func() ([]string, error) {
+			return []string{"git add ."}, fmt.Errorf("failed to commit\ndetails")
+		}},
+	)
+	want := []string{"d:", "  git worktree prune", "k:", "  git add .", "  (stops here: failed to commit)"}
+
+	t.Run("shown when toggled", func(t *testing.T) {
+		h := &home{appConfig: &config.Config{}}
+		h.confirmChoices("Kill?", []confirmChoice{{key: "d", label: "delete"}})
+		assert.Nil(t, h.offerGitCommands(plan))
+
+		assert.False(t, h.confirmationOverlay.HandleKeyPress(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(overlay.CommandsKey)}))
+		cmd := h.confirmationOverlay.PendingCmd()
+		require.NotNil(t, cmd)
+		msg, ok := cmd().(gitCommandsMsg)
+		require.True(t, ok)
+		assert.Equal(t, want, msg.commands)
+		assert.Same(t, h.confirmationOverlay, msg.overlay)
+
+		// Hiding and showing again doesn't load them again
+		h.confirmationOverlay.SetCommands(msg.commands)
+		h.confirmationOverlay.ToggleCommands()
+		h.confirmationOverlay.ToggleCommands()
+		assert.Nil(t, h.confirmationOverlay.PendingCmd())
+	})
+
+	t.Run("shown by default", func(t *testing.T) {
+		h := &home{appConfig: &config.Config{ShowGitCommands: true}}
+		h.confirmAction("Reset?", nil)
+		cmd := h.offerGitCommands(plan)
+		require.NotNil(t, cmd)
+		assert.IsType(t, gitCommandsMsg{}, cmd())
+	})
+}
+
+func TestClipboardPreview(t *testing.T) {
+	assert.Equal(t, "squad/fix-login", clipboardPreview("squad/fix-login"))
+	assert.Equal(t, "func main() { (+2 lines)", clipboardPreview("\nfunc main() {\n\trun()\n}\n"))
+
+	long := clipboardPreview(strings.Repeat("x", 100))
+	assert.Equal(t, clipboardPreviewWidth, len([]rune(long)))
+	assert.True(t, strings.HasSuffix(long, "…"))
+}
+
+func TestAddRepo(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	repo := t.TempDir()
+	require.NoError(t, exec.Command("git", "init", repo).Run())
+	subdir := filepath.Join(repo, "pkg")
+	require.NoError(t, os.Mkdir(subdir, 0755))
+
+	h := &home{appConfig: &config.Config{}, toastBox: ui.NewToastBox(), repoPath: "."}
+	h.addRepo(subdir)
+	assert.Equal(t, repo, h.repoPath)
+	assert.Equal(t, []string{repo}, h.appConfig.Repos)
+	assert.Equal(t, []string{repo}, config.LoadConfig().Repos)
+	assert.Contains(t, h.repoChoices(), repo)
+}
+
+func TestRemoteHostChoice(t *testing.T) {
+	h := &home{appConfig: &config.Config{RemoteHosts: map[string]config.RemoteHost{
+		"gpu":   {Host: "gpu.example.com", RepoPath: "/srv/api"},
+		"build": {Host: "build.example.com", RepoPath: "/home/me/api"},
+	}}, toastBox: ui.NewToastBox(), repoPath: "/src/api"}
+	assert.Equal(t, []string{"build", "gpu"}, h.remoteHostChoices())
+
+	h.useRemoteHost("gpu")
+	assert.Equal(t, "/srv/api", h.instanceRepoPath())
+	// The local checkout isn't inspected for instances on a remote host
+	assert.False(t, h.confirmRepoState(false))
+
+	h.useRepo("/src/web")
+	assert.Empty(t, h.remoteHost)
+	assert.Equal(t, "/src/web", h.instanceRepoPath())
+}
+
+func TestTakenServicePorts(t *testing.T) {
+	web := &session.Instance{Title: "web", Services: []session.Service{{Name: "web", Port: 3000}, {Name: "api", Port: 8080}}}
+	api := &session.Instance{Title: "api", Services: []session.Service{{Name: "web", Port: 3001}}}
+	idle := &session.Instance{Title: "idle"}
+	instances := []*session.Instance{web, api, idle}
+
+	assert.Equal(t, map[int]bool{3001: true}, takenServicePorts(instances, web))
+	assert.Equal(t, map[int]bool{3000: true, 3001: true, 8080: true}, takenServicePorts(instances, idle))
+}
+
+func TestUnadoptedWorktrees(t *testing.T) {
+	worktrees := []git.Worktree{
+		{Path: "/src/api-login", Branch: "login"},
+		{Path: "/src/api-search", Branch: "search"},
+		{Path: "/tmp/bisect"},
+	}
+	instances := []*session.Instance{{Title: "login", Branch: "login"}}
+
+	assert.Equal(t, worktrees[1:], unadoptedWorktrees(worktrees, instances))
+	assert.True(t, samePath("/src/api/", "/src/api"))
+	assert.False(t, samePath("/src/api", "/src/web"))
+}
+
+func TestBrowserCommand(t *testing.T) {
+	url := "http://localhost:3001"
+	assert.Equal(t, []string{"open", url}, browserCommand("darwin", url).Args)
+	assert.Equal(t, []string{"xdg-open", url}, browserCommand("linux", url).Args)
+	assert.Equal(t, []string{"rundll32", "url.dll,FileProtocolHandler", url}, browserCommand("windows", url).Args)
+}
+
+func TestInstanceFilter(t *testing.T) {
+	web := &session.Instance{Title: "web", Tags: []string{"frontend", "urgent"}, Group: "checkout", Status: session.Ready, Program: "claude"}
+	admin := &session.Instance{Title: "admin", Tags: []string{"frontend"}, Status: session.Paused, Program: "aider"}
+	api := &session.Instance{Title: "api", Tags: []string{"backend"}, Group: "checkout", Status: session.Running, Program: "claude"}
+	instances := []*session.Instance{web, admin, api}
+
+	matching := func(expression string) []string {
+		terms, err := parseInstanceFilter(expression)
+		require.NoError(t, err)
+		var titles []string
+		for _, instance := range instances {
+			if matchesFilter(instance, terms) {
+				titles = append(titles, instance.Title)
+			}
+		}
+		return titles
+	}
+	assert.Equal(t, []string{"web", "admin"}, matching("frontend"))
+	assert.Equal(t, []string{"web"}, matching("frontend !status:paused"))
+	assert.Equal(t, []string{"web", "api"}, matching("group:checkout program:claude"))
+	assert.Equal(t, []string{"web", "admin"}, matching("tag:front*"))
+	assert.Empty(t, matching("title:nope"))
+
+	for _, invalid := range []string{"", "owner:me", "tag:[", "tag:"} {
+		_, err := parseInstanceFilter(invalid)
+		assert.Error(t, err, invalid)
+	}
+}
+
+func TestBroadcastReport(t *testing.T) {
+	report := broadcastReport(broadcastDoneMsg{filter: "frontend", prompt: "run the type checker", results: []broadcastResult{
+		{title: "web", outcome: "sent"},
+		{title: "api", outcome: "queued", reason: "agent is working"},
+		{title: "admin", outcome: "skipped", reason: "paused"},
+		{title: "docs", outcome: "sent", err: fmt.Errorf("tmux session not initialized")},
+	}})
+	assert.Contains(t, report, "Sent 1, queued 1, skipped 1, failed 1")
+	assert.Contains(t, report, "queued   api (agent is working)")
+	assert.Contains(t, report, "failed   docs (tmux session not initialized)")
+}
+
+func TestRelativizeRepoPaths(t *testing.T) {
+	repo, worktree := "/src/api", "/home/me/.claude-squad/worktrees/fix_123"
+	for in, want := range map[string]string{
+		"Look at /src/api/handlers/login.go:42.":        "Look at handlers/login.go:42.",
+		"Run `go test /src/api/...` from /src/api":      "Run `go test ...` from " + worktree,
+		"See (/src/api/README.md) and /src/api-v2/x.go": "See (README.md) and /src/api-v2/x.go",
+		"Not /mirror/src/api/main.go":                   "Not /mirror/src/api/main.go",
+		"Already relative: handlers/login.go":           "Already relative: handlers/login.go",
+	} {
+		assert.Equal(t, want, relativizeRepoPaths(in, repo, worktree), in)
+	}
+
+	// A worktree inside the repository keeps its own paths relative to itself
+	nested := "/src/api/.worktrees/fix"
+	assert.Equal(t, "edit main.go and util.go", relativizeRepoPaths("edit /src/api/.worktrees/fix/main.go and /src/api/util.go", repo, nested))
+	assert.Equal(t, "same /src/api/x.go", relativizeRepoPaths("same /src/api/x.go", repo, repo))
+}
+
+func TestMetadataDue(t *testing.T) {
+	s := spinner.New(spinner.WithSpinner(spinner.MiniDot))
+	list := ui.NewList(&s, false)
+	h := &home{appConfig: config.DefaultConfig(), list: list}
+
+	selected := &session.Instance{Title: "selected", Status: session.Ready}
+	working := &session.Instance{Title: "working", Status: session.Running}
+	idle := &session.Instance{Title: "idle", Status: session.Ready}
+	for _, instance := range []*session.Instance{selected, working, idle} {
+		list.AddInstance(instance)()
+	}
+	list.SetSelectedInstance(0)
+
+	rates := h.tickRates()
+	start := time.Now()
+	checked := make(map[string]int)
+	for now := start; now.Before(start.Add(6 * time.Second)); now = now.Add(rates.MetadataTick()) {
+		for _, instance := range list.GetInstances() {
+			if h.metadataDue(instance, now, rates) {
+				checked[instance.Title]++
+			}
+		}
+	}
+	assert.Equal(t, map[string]int{"selected": 12, "working": 6, "idle": 2}, checked)
+
+	// Removed instances are forgotten
+	h.forgetMetadataChecks([]*session.Instance{selected})
+	assert.Len(t, h.lastMetadataCheck, 1)
+}
+
+func TestOperations(t *testing.T) {
+	h := &home{ctx: context.Background(), menu: ui.NewMenu()}
+
+	cancelled := make(chan bool, 1)
+	rebase := h.trackOperation("rebase", "my-task", true, func(ctx context.Context) tea.Msg {
+		<-ctx.Done()
+		cancelled <- true
+		return "rebased"
+	})
+	h.trackOperation("push", "other-task", true, func(context.Context) tea.Msg { return nil })
+	h.trackOperation("start", "third", false, func(context.Context) tea.Msg { return nil })
+	h.trackOperation("kill", "fourth", false, func(context.Context) tea.Msg { return nil })
+
+	assert.Equal(t, "⟳ rebase my-task, ⇪ push other-task, ▶ start third, +1 more", h.operations.summary(maxMenuOperations))
+	assert.Nil(t, h.operations.pending[2].cancel, "operations that can't be cancelled have no cancel")
+
+	// Cancelling stops the operation, which then leaves the registry and passes on its result
+	op := h.operations.pending[0]
+	op.cancelled = true
+	op.cancel()
+	done, ok := rebase().(operationDoneMsg)
+	require.True(t, ok)
+	assert.True(t, <-cancelled)
+	assert.Equal(t, op.id, done.id)
+
+	cmd := h.finishOperation(done)
+	require.NotNil(t, cmd)
+	assert.Equal(t, "rebased", cmd())
+	assert.Nil(t, h.operations.get(op.id))
+	assert.Equal(t, "⇪ push other-task, ▶ start third, ✕ kill fourth", h.operations.summary(maxMenuOperations))
+}
+
+func TestInstanceTab(t *testing.T) {
+	s := spinner.New(spinner.WithSpinner(spinner.MiniDot))
+	list := ui.NewList(&s, false)
+	cfg := config.DefaultConfig()
+	cfg.DefaultTab = "diff"
+	h := &home{
+		appConfig:    cfg,
+		list:         list,
+		menu:         ui.NewMenu(),
+		tabbedWindow: ui.NewTabbedWindow(ui.NewPreviewPane(), ui.NewDiffPane(), ui.NewTerminalPane(), ui.NewTestPane(cfg)),
+	}
+	review := &session.Instance{Title: "review"}
+	coding := &session.Instance{Title: "coding", Tab: "ai"}
+	list.AddInstance(review)()
+	list.AddInstance(coding)()
+
+	// Instances without a tab open on the configured default, others on their own
+	h.showInstanceTab(review)
+	assert.Equal(t, ui.DiffTab, h.tabbedWindow.ActiveTab())
+	h.showInstanceTab(coding)
+	assert.Equal(t, ui.AITab, h.tabbedWindow.ActiveTab())
+
+	// A tab switched to is remembered for the instance
+	list.SetSelectedInstance(0)
+	h.showInstanceTab(review)
+	h.tabbedWindow.SetTab(ui.TerminalTab)
+	h.rememberTab()
+	assert.Equal(t, "terminal", review.Tab)
+	h.showInstanceTab(coding)
+	h.showInstanceTab(review)
+	assert.Equal(t, ui.TerminalTab, h.tabbedWindow.ActiveTab())
+
+	// Reselecting the same instance keeps the tab shown, and unknown tabs are ignored
+	h.tabbedWindow.SetTab(ui.TestTab)
+	h.showInstanceTab(review)
+	assert.Equal(t, ui.TestTab, h.tabbedWindow.ActiveTab())
+	coding.Tab = "logs"
+	h.showInstanceTab(coding)
+	assert.Equal(t, ui.TestTab, h.tabbedWindow.ActiveTab())
+}
+
+func TestInstanceDeletedRemovesInstance(t *testing.T) {
+	s := spinner.New(spinner.WithSpinner(spinner.MiniDot))
+	list := ui.NewList(&s, false)
+	cfg := config.DefaultConfig()
+	h := &home{
+		appConfig:    cfg,
+		list:         list,
+		menu:         ui.NewMenu(),
+		toastBox:     ui.NewToastBox(),
+		tabbedWindow: ui.NewTabbedWindow(ui.NewPreviewPane(), ui.NewDiffPane(), ui.NewTerminalPane(), ui.NewTestPane(cfg)),
+	}
+	first := &session.Instance{Title: "first"}
+	killed := &session.Instance{Title: "killed"}
+	list.AddInstance(first)()
+	list.AddInstance(killed)()
+
+	// The torn down instance is removed even when another one was selected meanwhile
+	list.SetSelectedInstance(0)
+	h.Update(instanceDeletedMsg{title: "killed", notice: "Killed 'killed'", removed: killed})
+	assert.Equal(t, []*session.Instance{first}, list.GetInstances())
+	assert.Equal(t, first, list.GetSelectedInstance())
+}
+
+func TestJumpToFinished(t *testing.T) {
+	s := spinner.New(spinner.WithSpinner(spinner.MiniDot))
+	list := ui.NewList(&s, false)
+	cfg := config.DefaultConfig()
+	cfg.DisableFinishBell = true
+	h := &home{
+		appConfig:    cfg,
+		list:         list,
+		menu:         ui.NewMenu(),
+		toastBox:     ui.NewToastBox(),
+		tabbedWindow: ui.NewTabbedWindow(ui.NewPreviewPane(), ui.NewDiffPane(), ui.NewTerminalPane(), ui.NewTestPane(cfg)),
+	}
+	first := &session.Instance{Title: "first"}
+	second := &session.Instance{Title: "second"}
+	killed := &session.Instance{Title: "killed"}
+	for _, instance := range []*session.Instance{first, second} {
+		list.AddInstance(instance)()
+	}
+
+	h.jumpToFinished()
+	assert.Empty(t, h.finishedOrder)
+
+	// The most recently finished instance not seen yet is selected, skipping killed ones
+	first.MarkFinished("agent is ready")
+	second.MarkFinished("'make' exited in the terminal")
+	killed.MarkFinished("agent is ready")
+	h.finishedOrder = []*session.Instance{first, second, killed}
+	h.jumpToFinished()
+	assert.Equal(t, second, list.GetSelectedInstance())
+	assert.Empty(t, second.Finished(), "selecting an instance clears its mark")
+	assert.Equal(t, []*session.Instance{first, second}, h.finishedOrder)
+
+	// Jumping again walks back to the other unseen one, then stays on the last finished
+	h.jumpToFinished()
+	assert.Equal(t, first, list.GetSelectedInstance())
+	h.jumpToFinished()
+	assert.Equal(t, second, list.GetSelectedInstance())
+}
+
+func TestSnapshotRestore(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	s := spinner.New(spinner.WithSpinner(spinner.MiniDot))
+	cfg := config.DefaultConfig()
+	h := &home{
+		appConfig:    cfg,
+		list:         ui.NewList(&s, false),
+		menu:         ui.NewMenu(),
+		toastBox:     ui.NewToastBox(),
+		tabbedWindow: ui.NewTabbedWindow(ui.NewPreviewPane(), ui.NewDiffPane(), ui.NewTerminalPane(), ui.NewTestPane(cfg)),
+	}
+	h.list.AddInstance(&session.Instance{Title: "web"})()
+	h.list.AddInstance(&session.Instance{Title: "api"})()
+
+	// Nothing is offered after a normal quit, or for an instance that is gone
+	h.offerSnapshotRestore()
+	assert.Equal(t, stateDefault, h.state)
+	require.NoError(t, config.SaveSnapshot(&config.Snapshot{Selected: "gone"}))
+	h.offerSnapshotRestore()
+	assert.Equal(t, stateDefault, h.state)
+
+	snapshot := &config.Snapshot{SavedAt: time.Now(), Selected: "api", Tab: "diff", PromptDraft: "Add a test"}
+	require.NoError(t, config.SaveSnapshot(snapshot))
+	h.offerSnapshotRestore()
+	assert.Equal(t, stateConfirm, h.state)
+	require.NotNil(t, h.pendingCmd)
+	msg, ok := h.pendingCmd().(restoreSnapshotMsg)
+	require.True(t, ok)
+	assert.Equal(t, snapshot.Selected, msg.snapshot.Selected)
+
+	h.state = stateDefault
+	h.restoreSnapshot(msg.snapshot)
+	assert.Equal(t, "api", h.list.GetSelectedInstance().Title)
+	assert.Equal(t, ui.DiffTab, h.tabbedWindow.ActiveTab())
+	assert.Equal(t, stateDefault, h.state, "a prompt is only reopened for a started instance")
+
+	// Quitting normally removes the snapshot
+	h.cleanExit = true
+	h.finishSnapshots()
+	left, err := config.LoadSnapshot()
+	require.NoError(t, err)
+	assert.Nil(t, left)
+}
+
+func TestRunScripts(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "followup.star"), []byte(`
+def followup(event):
+    send_prompt(event.title, "Summarize what you did")
+    run_tests("missing")
+
+on("rebase_complete", followup)
+`), 0644))
+	engine, errs := scripting.Load(dir)
+	require.Empty(t, errs)
+
+	s := spinner.New(spinner.WithSpinner(spinner.MiniDot))
+	h := &home{ctx: context.Background(), list: ui.NewList(&s, false), toastBox: ui.NewToastBox(), scripts: engine}
+	instance := &session.Instance{Title: "api", Status: session.Paused}
+	h.list.AddInstance(instance)()
+
+	// Events nobody handles don't run anything
+	assert.Nil(t, h.runScripts(scriptEvent(scripting.EventPushComplete, instance, "")))
+	assert.Empty(t, h.errorLog)
+
+	// Actions that can't be done are reported
+	assert.NotNil(t, h.runScripts(scriptEvent(scripting.EventRebaseComplete, instance, "")))
+	require.Len(t, h.errorLog, 2)
+	assert.Contains(t, h.errorLog[0], "script followup.star: session 'api' is paused")
+	assert.Contains(t, h.errorLog[1], "script followup.star: no session 'missing' to run_tests")
+}
+
+func TestRestoreBackupQuits(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	configDir, err := config.GetConfigDir()
+	require.NoError(t, err)
+	require.NoError(t, os.MkdirAll(configDir, 0755))
+	statePath := filepath.Join(configDir, config.StateFileName)
+	require.NoError(t, os.WriteFile(statePath, []byte(`{"instances":[]}`), 0644))
+	_, err = config.CreateBackup("startup", 5)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(statePath, []byte(`{corrupt`), 0644))
+
+	s := spinner.New(spinner.WithSpinner(spinner.MiniDot))
+	cfg := config.DefaultConfig()
+	h := &home{
+		appConfig:    cfg,
+		list:         ui.NewList(&s, false),
+		menu:         ui.NewMenu(),
+		toastBox:     ui.NewToastBox(),
+		tabbedWindow: ui.NewTabbedWindow(ui.NewPreviewPane(), ui.NewDiffPane(), ui.NewTerminalPane(), ui.NewTestPane(cfg)),
+	}
+	h.showBackups()
+	require.NotNil(t, h.onListSelect)
+	h.onListSelect(0)
+	require.Equal(t, stateConfirm, h.state)
+	assert.True(t, h.confirmationOverlay.Confirm(""))
+
+	// Restoring exits, so the restored state isn't overwritten by the next save
+	_, cmd := h.closeConfirmation()
+	require.NotNil(t, cmd)
+	assert.IsType(t, tea.QuitMsg{}, cmd())
+	assert.True(t, h.cleanExit)
+	data, err := os.ReadFile(statePath)
+	require.NoError(t, err)
+	assert.Equal(t, `{"instances":[]}`, string(data))
+}
+
+func TestUndoStack(t *testing.T) {
+	s := spinner.New(spinner.WithSpinner(spinner.MiniDot))
+	h := &home{
+		appConfig: config.DefaultConfig(),
+		list:      ui.NewList(&s, false),
+		toastBox:  ui.NewToastBox(),
+	}
+	gone := &session.Instance{Title: "gone"}
+	for n := 0; n < maxUndo+5; n++ {
+		h.pushUndo(undoEntry{description: fmt.Sprintf("reset %d", n), reset: gone})
+	}
+	require.Len(t, h.undoStack, maxUndo)
+	assert.Equal(t, "reset 5", h.undoStack[0].description, "the oldest operations are dropped")
+
+	// Undoing takes the newest operation off, even when it can't be undone anymore
+	assert.NotNil(t, h.undo())
+	require.Len(t, h.undoStack, maxUndo-1)
+	assert.Equal(t, fmt.Sprintf("reset %d", maxUndo+3), h.undoStack[len(h.undoStack)-1].description)
+
+	// Undo has a key of its own, leaving the staging view's alone
+	assert.Equal(t, keys.KeyUndo, keys.GlobalKeyStringsMap[undoKey()])
+	assert.Equal(t, keys.KeyDiffStaging, keys.GlobalKeyStringsMap["u"])
+}
+
+func TestCoordinator(t *testing.T) {
+	lead := &session.Instance{Title: "lead", Status: session.Ready}
+	web := &session.Instance{Title: "web", Branch: "me/web", Status: session.Running}
+	api := &session.Instance{Title: "api", Branch: "me/api", Status: session.Paused}
+	instances := []*session.Instance{lead, web, api}
+
+	// Only one instance coordinates at a time
+	assert.True(t, setCoordinator(instances, web))
+	assert.True(t, setCoordinator(instances, lead))
+	assert.True(t, lead.Coordinator)
+	assert.False(t, web.Coordinator)
+	assert.True(t, lead.ToInstanceData().Coordinator)
+
+	digest := session.FleetDigest(lead, instances)
+	assert.NotContains(t, digest, "## lead")
+	assert.Contains(t, digest, "## web\n- status: working\n- branch: me/web\n")
+	assert.Contains(t, digest, "## api\n- status: paused\n- branch: me/api\n")
+	assert.Equal(t, digest, session.FleetDigest(lead, instances), "an unchanged fleet gives the same digest")
+	assert.Contains(t, session.FleetDigest(lead, []*session.Instance{lead}), "no other sessions")
+
+	assert.False(t, setCoordinator(instances, lead))
+	assert.False(t, lead.Coordinator)
+}
+
+func TestRemoteApprovals(t *testing.T) {
+	dir := t.TempDir()
+	inbox, err := approvals.Open(dir)
+	require.NoError(t, err)
+	data, err := os.ReadFile(filepath.Join(dir, "secret"))
+	require.NoError(t, err)
+	secret := []byte(strings.TrimSpace(string(data)))
+	s := spinner.New(spinner.WithSpinner(spinner.MiniDot))
+	cfg := config.DefaultConfig()
+	h := &home{
+		appConfig:    cfg,
+		list:         ui.NewList(&s, false),
+		menu:         ui.NewMenu(),
+		toastBox:     ui.NewToastBox(),
+		tabbedWindow: ui.NewTabbedWindow(ui.NewPreviewPane(), ui.NewDiffPane(), ui.NewTerminalPane(), ui.NewTestPane(cfg)),
+		approvals:    inbox,
+	}
+	respondTyped := func(id, choice, phrase, token string) {
+		data, err := json.Marshal(approvals.Response{ID: id, Choice: choice, Phrase: phrase, By: "bot", Token: token})
+		require.NoError(t, err)
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "responses", id+".json"), data, 0600))
+	}
+	respond := func(id, choice string, token string) {
+		respondTyped(id, choice, "", token)
+	}
+	audit := func() []approvals.AuditEntry {
+		data, err := os.ReadFile(filepath.Join(dir, "audit.jsonl"))
+		require.NoError(t, err)
+		var entries []approvals.AuditEntry
+		for _, line := range strings.Split(strings.TrimSpace(string(data)), "\n") {
+			var entry approvals.AuditEntry
+			require.NoError(t, json.Unmarshal([]byte(line), &entry))
+			entries = append(entries, entry)
+		}
+		return entries
+	}
+
+	confirmed := false
+	h.confirmAction("Kill session 'web'?", func() tea.Msg {
+		confirmed = true
+		return tea.Cmd(nil)
+	})
+	h.syncApprovals()
+	id := h.approvalRequest.ID
+	require.NotEmpty(t, id)
+	assert.FileExists(t, filepath.Join(dir, "pending", id+".json"))
+
+	// A response with a bad token or choice leaves the confirmation open
+	respond(id, approvals.Approve, approvals.Sign([]byte("guess"), id, approvals.Approve, ""))
+	h.syncApprovals()
+	respond(id, "m", approvals.Sign(secret, id, "m", ""))
+	h.syncApprovals()
+	assert.Equal(t, stateConfirm, h.state)
+	assert.False(t, confirmed)
+
+	respond(id, approvals.Approve, approvals.Sign(secret, id, approvals.Approve, ""))
+	h.syncApprovals()
+	assert.True(t, confirmed)
+	assert.Equal(t, stateDefault, h.state)
+	assert.NoFileExists(t, filepath.Join(dir, "pending", id+".json"))
+	entries := audit()
+	require.Len(t, entries, 4)
+	assert.Equal(t, approvals.OutcomeRejected, entries[1].Outcome)
+	assert.Equal(t, approvals.OutcomeRejected, entries[2].Outcome)
+	assert.Equal(t, approvals.OutcomeApproved, entries[3].Outcome)
+	assert.Equal(t, "bot", entries[3].By)
+
+	// A confirmation answered in the UI is closed in the inbox too
+	h.confirmChoices("Merge how?", []confirmChoice{{key: "m", label: "merge"}, {key: "r", label: "rebase"}})
+	h.syncApprovals()
+	require.Len(t, h.approvalRequest.Choices, 2)
+	assert.True(t, h.confirmationOverlay.Confirm("r"))
+	h.closeConfirmation()
+	h.syncApprovals()
+	entries = audit()
+	last := entries[len(entries)-1]
+	assert.Equal(t, approvals.OutcomeApproved, last.Outcome)
+	assert.Equal(t, "r", last.Choice)
+	assert.Equal(t, "local", last.By)
+	pending, err := os.ReadDir(filepath.Join(dir, "pending"))
+	require.NoError(t, err)
+	assert.Empty(t, pending)
+
+	// A typed confirmation needs its phrase, covered by the token, not just an approve
+	confirmed = false
+	h.confirmTyped("Force kill 'web'?", "web", func() tea.Msg {
+		confirmed = true
+		return nil
+	})
+	h.syncApprovals()
+	id = h.approvalRequest.ID
+	assert.Equal(t, "web", h.approvalRequest.Phrase)
+	respond(id, approvals.Approve, approvals.Sign(secret, id, approvals.Approve, ""))
+	h.syncApprovals()
+	respondTyped(id, approvals.Approve, "web", approvals.Sign(secret, id, approvals.Approve, ""))
+	h.syncApprovals()
+	respondTyped(id, approvals.Approve, "api", approvals.Sign(secret, id, approvals.Approve, "api"))
+	h.syncApprovals()
+	assert.Equal(t, stateConfirm, h.state)
+	assert.False(t, confirmed)
+	respondTyped(id, approvals.Approve, "web", approvals.Sign(secret, id, approvals.Approve, "web"))
+	h.syncApprovals()
+	assert.True(t, confirmed)
+	assert.Equal(t, stateDefault, h.state)
+}
@@ -0,0 +1,171 @@
+package app
+
+import (
+	"claude-squad/approvals"
+	"claude-squad/config"
+	"claude-squad/log"
+	"claude-squad/notify"
+	"claude-squad/ui"
+	"claude-squad/ui/overlay"
+	"fmt"
+	"path/filepath"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// approvalsPollInterval is how often the approvals inbox is checked for responses.
+const approvalsPollInterval = 2 * time.Second
+
+// approvalsTickMsg triggers publishing the open confirmation and checking for its response
+type approvalsTickMsg struct{}
+
+// openApprovals opens the approvals inbox in the config directory.
+func openApprovals() (*approvals.Inbox, error) {
+	dir, err := config.GetConfigDir()
+	if err != nil {
+		return nil, err
+	}
+	return approvals.Open(filepath.Join(dir, "approvals"))
+}
+
+// scheduleApprovals waits, then triggers the next check of the approvals inbox. Nothing is
+// scheduled when remote approvals are off.
+func (m *home) scheduleApprovals() tea.Cmd {
+	if m.approvals == nil {
+		return nil
+	}
+	return tea.Tick(approvalsPollInterval, func(time.Time) tea.Msg {
+		return approvalsTickMsg{}
+	})
+}
+
+// syncApprovals publishes the open confirmation to the approvals inbox and answers it with a
+// valid response. A published confirmation answered in the UI, or gone, is closed in the inbox.
+func (m *home) syncApprovals() (tea.Model, tea.Cmd) {
+	next := m.scheduleApprovals()
+	open := m.confirmationOverlay
+	if m.state != stateConfirm {
+		open = nil
+	}
+	if m.approvalOverlay != nil && m.approvalOverlay != open {
+		m.closeApproval(localAnswer(m.approvalOverlay))
+	}
+	if open == nil {
+		return m, next
+	}
+	if m.approvalOverlay == nil {
+		return m, tea.Batch(next, m.publishApproval(open))
+	}
+
+	request := m.approvalRequest
+	response, err := m.approvals.Response(request.ID)
+	if err != nil {
+		if err := m.approvals.Reject(request, err.Error()); err != nil {
+			log.WarningLog.Printf("%v", err)
+		}
+		return m, tea.Batch(next, m.notify(ui.ToastWarning, fmt.Sprintf("Ignored a remote approval: %v", err)))
+	}
+	if response == nil {
+		return m, next
+	}
+
+	by := response.By
+	if by == "" {
+		by = "remote"
+	}
+	entry := approvals.AuditEntry{ID: request.ID, Instance: request.Instance, Choice: response.Choice, By: by}
+	// A typed confirmation is only approved with its phrase, as if it was typed
+	if response.Choice != approvals.Deny && response.Phrase != request.Phrase {
+		if err := m.approvals.Reject(request, "the phrase doesn't match"); err != nil {
+			log.WarningLog.Printf("%v", err)
+		}
+		return m, next
+	}
+	open.TypePhrase(response.Phrase)
+	switch {
+	case response.Choice == approvals.Deny:
+		open.Cancel()
+		entry.Outcome = approvals.OutcomeDenied
+	case response.Choice == approvals.Approve && len(open.Choices()) == 0 && open.Confirm(""):
+		entry.Outcome = approvals.OutcomeApproved
+	case response.Choice != approvals.Approve && open.Confirm(response.Choice):
+		entry.Outcome = approvals.OutcomeApproved
+	default:
+		if err := m.approvals.Reject(request, fmt.Sprintf("%q is not one of the choices", response.Choice)); err != nil {
+			log.WarningLog.Printf("%v", err)
+		}
+		return m, next
+	}
+	m.closeApproval(entry)
+
+	verb := "approved"
+	if entry.Outcome == approvals.OutcomeDenied {
+		verb = "denied"
+	}
+	toast := m.notify(ui.ToastInfo, fmt.Sprintf("%s %s: %s", by, verb, firstLine(request.Message)))
+	model, cmd := m.closeConfirmation()
+	return model, tea.Batch(next, toast, cmd)
+}
+
+// publishApproval publishes the open confirmation to the approvals inbox, and reports it to the
+// webhook so whoever answers remotely hears of it.
+func (m *home) publishApproval(open *overlay.ConfirmationOverlay) tea.Cmd {
+	id, err := approvals.NewID()
+	if err != nil {
+		log.WarningLog.Printf("%v", err)
+		return nil
+	}
+	request := approvals.Request{ID: id, Message: open.Message(), Phrase: open.Phrase(), CreatedAt: time.Now()}
+	for _, choice := range open.Choices() {
+		request.Choices = append(request.Choices, approvals.Choice{Key: choice.Key, Label: choice.Label})
+	}
+	if len(request.Choices) == 0 {
+		request.Choices = []approvals.Choice{{Key: approvals.Approve, Label: "Confirm"}}
+	}
+	var branch string
+	if selected := m.list.GetSelectedInstance(); selected != nil {
+		request.Instance, branch = selected.Title, selected.Branch
+	}
+	if err := m.approvals.Publish(request); err != nil {
+		log.WarningLog.Printf("%v", err)
+		return nil
+	}
+	m.approvalOverlay, m.approvalRequest = open, request
+	return m.sendEvent(notify.NewEvent(notify.EventApprovalRequested, request.Instance, branch,
+		fmt.Sprintf("Waiting for approval %s: %s", id, firstLine(request.Message))))
+}
+
+// closeApproval closes the published confirmation in the approvals inbox with the entry's
+// outcome.
+func (m *home) closeApproval(entry approvals.AuditEntry) {
+	entry.ID, entry.Instance = m.approvalRequest.ID, m.approvalRequest.Instance
+	if err := m.approvals.Close(entry); err != nil {
+		log.WarningLog.Printf("%v", err)
+	}
+	m.approvalOverlay, m.approvalRequest = nil, approvals.Request{}
+}
+
+// withdrawApproval closes the published confirmation unanswered, e.g. when quitting.
+func (m *home) withdrawApproval() {
+	if m.approvalOverlay != nil {
+		m.closeApproval(approvals.AuditEntry{Outcome: approvals.OutcomeWithdrawn})
+	}
+}
+
+// localAnswer returns the audit entry of a published confirmation that closed in the UI: answered
+// there, or withdrawn if it went away unanswered.
+func localAnswer(closed *overlay.ConfirmationOverlay) approvals.AuditEntry {
+	switch {
+	case !closed.Dismissed:
+		return approvals.AuditEntry{Outcome: approvals.OutcomeWithdrawn}
+	case closed.IsConfirmed():
+		choice := closed.SelectedChoice()
+		if choice == "" {
+			choice = approvals.Approve
+		}
+		return approvals.AuditEntry{Outcome: approvals.OutcomeApproved, Choice: choice, By: "local"}
+	default:
+		return approvals.AuditEntry{Outcome: approvals.OutcomeDenied, Choice: approvals.Deny, By: "local"}
+	}
+}
@@ -0,0 +1,137 @@
+package app
+
+import (
+	"claude-squad/session"
+	"claude-squad/ui"
+	"claude-squad/ui/overlay"
+	"fmt"
+	"os"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// showArchives lists the archived sessions and offers to inspect, restore or delete the chosen
+// one.
+func (m *home) showArchives() tea.Cmd {
+	archives, err := session.ListArchives()
+	if err != nil {
+		return m.handleError(err)
+	}
+	if len(archives) == 0 {
+		return m.notify(ui.ToastInfo, "No archived sessions yet. Archive one when killing it.")
+	}
+
+	items := make([]overlay.ListItem, len(archives))
+	for i, archive := range archives {
+		data := archive.Metadata.Instance
+		items[i] = overlay.ListItem{
+			Title: data.Title,
+			Description: fmt.Sprintf("%s • %s • archived %s", archive.Metadata.ArchivedBranch, data.Program,
+				archive.Metadata.ArchivedAt.Format("2006-01-02 15:04")),
+		}
+	}
+
+	return m.selectFromList("Archived Sessions", items, func(idx int) tea.Cmd {
+		archive := archives[idx]
+		return m.confirmChoices(fmt.Sprintf("Archived session '%s'", archive.Metadata.Instance.Title), []confirmChoice{
+			{key: "i", label: "inspect", action: func() tea.Msg { return inspectArchiveMsg{archive: archive} }},
+			{key: "r", label: "restore as a paused session", action: func() tea.Msg { return restoreArchiveMsg{archive: archive} }},
+			{key: "d", label: "delete archive, keep branch", action: func() tea.Msg {
+				if err := os.Remove(archive.Path); err != nil {
+					return fmt.Errorf("failed to delete archive: %w", err)
+				}
+				return nil
+			}},
+		})
+	})
+}
+
+// inspectArchiveMsg asks to show the contents of an archive.
+type inspectArchiveMsg struct {
+	archive session.ArchiveEntry
+}
+
+// restoreArchiveMsg asks to restore an archived session.
+type restoreArchiveMsg struct {
+	archive session.ArchiveEntry
+}
+
+// inspectArchive shows an archive's metadata, scrollback and changes in the history overlay.
+func (m *home) inspectArchive(archive session.ArchiveEntry) tea.Cmd {
+	views := []overlay.HistoryView{{Name: "Details", Content: archiveDetails(archive.Metadata)}}
+	for _, file := range []struct{ name, view string }{
+		{session.ArchiveScrollbackFile, "AI"},
+		{session.ArchiveTerminalFile, "Terminal"},
+		{session.ArchiveCommitsFile, "Commits"},
+		{session.ArchivePatchFile, "Changes"},
+	} {
+		content, err := session.ReadArchiveFile(archive.Path, file.name)
+		if err != nil {
+			return m.handleError(err)
+		}
+		if content != "" {
+			views = append(views, overlay.HistoryView{Name: file.view, Content: content})
+		}
+	}
+
+	m.historyOverlay = overlay.NewTabbedHistoryOverlay(fmt.Sprintf("Archive - %s", archive.Metadata.Instance.Title), views, 0)
+	m.historyOverlay.OnDismiss = func() {
+		m.state = stateDefault
+		m.menu.SetState(ui.StateDefault)
+		m.historyOverlay = nil
+	}
+	m.state = stateHistory
+	return tea.WindowSize()
+}
+
+// archiveDetails describes an archived session.
+func archiveDetails(metadata session.ArchiveMetadata) string {
+	field := func(name, value string) string {
+		return keyStyle.Render(fmt.Sprintf("%-10s", name)) + descStyle.Render(value)
+	}
+	data := metadata.Instance
+	lines := []string{
+		field("Branch", metadata.ArchivedBranch),
+		field("Program", data.Program),
+		field("Repo", data.Worktree.RepoPath),
+		field("Created", data.CreatedAt.Format("2006-01-02 15:04")),
+		field("Archived", metadata.ArchivedAt.Format("2006-01-02 15:04")),
+		field("Worked", data.WorkTime.String()),
+	}
+	if len(data.Tags) > 0 {
+		lines = append(lines, field("Tags", strings.Join(data.Tags, ", ")))
+	}
+	if len(data.PromptQueue) > 0 {
+		lines = append(lines, field("Queued", fmt.Sprintf("%d prompts", len(data.PromptQueue))))
+	}
+	if len(data.Checkpoints) > 0 {
+		lines = append(lines, field("Checkpts", fmt.Sprintf("%d", len(data.Checkpoints))))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// restoreArchive recreates an archived session as a paused instance, under a free title.
+func (m *home) restoreArchive(archive session.ArchiveEntry) tea.Cmd {
+	if m.list.NumInstances() >= m.instanceLimit() {
+		return m.handleError(fmt.Errorf("cannot restore: the limit of %d instances is reached", m.instanceLimit()))
+	}
+	if err := m.policy.CheckProgram(archive.Metadata.Instance.Program); err != nil {
+		return m.handleError(err)
+	}
+
+	taken := make(map[string]bool)
+	for _, instance := range m.list.GetInstances() {
+		taken[instance.Title] = true
+	}
+	instance, err := session.RestoreArchive(archive, uniqueTitle(archive.Metadata.Instance.Title, taken))
+	if err != nil {
+		return m.handleError(err)
+	}
+	m.list.AddInstance(instance)()
+	if err := m.storage.SaveInstances(m.list.GetInstances()); err != nil {
+		return m.handleError(err)
+	}
+	return tea.Batch(tea.WindowSize(), m.instanceChanged(),
+		m.showSuccess(fmt.Sprintf("Restored '%s' on branch %s; press r to resume it", instance.Title, instance.Branch)))
+}
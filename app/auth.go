@@ -0,0 +1,134 @@
+package app
+
+import (
+	"claude-squad/keys"
+	"claude-squad/log"
+	"claude-squad/session/git"
+	"claude-squad/ui"
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// authCheckTickMsg triggers a background check of gh and git credentials
+type authCheckTickMsg struct{}
+
+// authStatusMsg carries the result of a credential check. scheduled is true for the periodic
+// check, which schedules the next one.
+type authStatusMsg struct {
+	issues    []git.AuthIssue
+	scheduled bool
+}
+
+// authCheckNowMsg triggers an immediate, unscheduled credential check
+type authCheckNowMsg struct{}
+
+// reauthMsg asks the model to run the re-auth command
+type reauthMsg struct{}
+
+var authBannerStyle = lipgloss.NewStyle().
+	Foreground(lipgloss.AdaptiveColor{Light: "#d19a00", Dark: "#ffcc00"}).
+	Bold(true)
+
+// authRepoPaths returns the distinct repositories of the started instances.
+func (m *home) authRepoPaths() []string {
+	seen := make(map[string]bool)
+	var paths []string
+	for _, instance := range m.list.GetInstances() {
+		worktree, err := instance.GetGitWorktree()
+		if err != nil || worktree == nil || seen[worktree.GetRepoPath()] {
+			continue
+		}
+		seen[worktree.GetRepoPath()] = true
+		paths = append(paths, worktree.GetRepoPath())
+	}
+	return paths
+}
+
+// checkAuth probes credentials in the background and reports them with an authStatusMsg.
+func (m *home) checkAuth(scheduled bool) tea.Cmd {
+	repoPaths := m.authRepoPaths()
+	return func() tea.Msg {
+		return authStatusMsg{issues: git.CheckAuth(repoPaths), scheduled: scheduled}
+	}
+}
+
+// handleAuthStatus stores the latest credential issues, warning once when a new problem appears.
+func (m *home) handleAuthStatus(msg authStatusMsg) tea.Cmd {
+	var cmds []tea.Cmd
+	if msg.scheduled {
+		cmds = append(cmds, tea.Tick(authCheckInterval, func(time.Time) tea.Msg {
+			return authCheckTickMsg{}
+		}))
+	}
+
+	hadIssues := len(m.authIssues) > 0
+	m.authIssues = msg.issues
+	for _, issue := range msg.issues {
+		log.WarningLog.Printf("credential check failed for %s: %s", issue.Source, issue.Message)
+	}
+
+	switch {
+	case !hadIssues && len(msg.issues) > 0:
+		// The banner takes a row from the content area
+		cmds = append(cmds, tea.WindowSize(),
+			m.notify(ui.ToastWarning, fmt.Sprintf("%s: %s", msg.issues[0].Source, msg.issues[0].Message)))
+	case hadIssues && len(msg.issues) == 0:
+		cmds = append(cmds, tea.WindowSize(), m.notify(ui.ToastSuccess, "Credentials are valid again"))
+	}
+	return tea.Batch(cmds...)
+}
+
+// reauthenticate suspends the UI and runs the re-auth command of the first fixable issue, then
+// checks credentials again.
+func (m *home) reauthenticate() tea.Cmd {
+	for _, issue := range m.authIssues {
+		if len(issue.ReauthCommand) == 0 {
+			continue
+		}
+		cmd := exec.Command(issue.ReauthCommand[0], issue.ReauthCommand[1:]...)
+		return tea.ExecProcess(cmd, func(err error) tea.Msg {
+			if err != nil {
+				return fmt.Errorf("failed to run %s: %w", strings.Join(issue.ReauthCommand, " "), err)
+			}
+			return authCheckNowMsg{}
+		})
+	}
+	if len(m.authIssues) > 0 {
+		return m.notify(ui.ToastWarning, m.authIssues[0].Message)
+	}
+	return m.checkAuth(false)
+}
+
+// authBanner renders the persistent credential warning, or "" if credentials are fine.
+func (m *home) authBanner() string {
+	if len(m.authIssues) == 0 {
+		return ""
+	}
+	issue := m.authIssues[0]
+	text := fmt.Sprintf("⚠ %s: %s", issue.Source, issue.Message)
+	if len(m.authIssues) > 1 {
+		text += fmt.Sprintf(" (+%d more)", len(m.authIssues)-1)
+	}
+	if len(issue.ReauthCommand) > 0 {
+		text += fmt.Sprintf(" • press %s to run '%s'",
+			keys.GlobalkeyBindings[keys.KeyReauth].Help().Key, strings.Join(issue.ReauthCommand, " "))
+	}
+	return authBannerStyle.Render(text)
+}
+
+// withAuthWarning prefixes a confirmation for a remote git operation with the current credential
+// problem and offers to re-authenticate first, so the operation doesn't fail halfway through.
+func (m *home) withAuthWarning(message string, choices []confirmChoice) (string, []confirmChoice) {
+	if len(m.authIssues) == 0 {
+		return message, choices
+	}
+	message = fmt.Sprintf("%s\n\nCredentials look expired (%s: %s).", message, m.authIssues[0].Source, m.authIssues[0].Message)
+	return message, append(choices, confirmChoice{key: "a", label: "re-authenticate first", action: func() tea.Msg {
+		return reauthMsg{}
+	}})
+}
@@ -0,0 +1,48 @@
+package app
+
+import (
+	"claude-squad/config"
+	"claude-squad/ui"
+	"claude-squad/ui/overlay"
+	"slices"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// showAutoYesPolicy opens the editor of the auto-yes policy, which applies to every instance in
+// auto-yes mode.
+func (m *home) showAutoYesPolicy() tea.Cmd {
+	m.autoYesPolicyOverlay = overlay.NewAutoYesPolicyOverlay(config.LoadConfig().AutoYesPolicy)
+	m.state = stateAutoYesPolicy
+	return tea.WindowSize()
+}
+
+// handleAutoYesPolicyState passes key presses to the policy editor and, once it is saved, writes
+// the policy to the config file, where instances read it the next time they are prompted.
+func (m *home) handleAutoYesPolicyState(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if m.autoYesPolicyOverlay == nil {
+		m.state = stateDefault
+		return m, nil
+	}
+	if !m.autoYesPolicyOverlay.HandleKeyPress(msg) {
+		return m, nil
+	}
+	form := m.autoYesPolicyOverlay
+	m.autoYesPolicyOverlay = nil
+	m.state = stateDefault
+	m.menu.SetState(ui.StateDefault)
+	if !form.IsSubmitted() {
+		return m, tea.WindowSize()
+	}
+
+	policy := form.Policy()
+	if err := config.SaveAutoYesPolicy(policy); err != nil {
+		return m, tea.Batch(tea.WindowSize(), m.handleError(err))
+	}
+	m.appConfig.AutoYesPolicy = policy
+	if slices.Contains(config.LoadConfig().PolicyOverrides(), "auto_yes_policy") {
+		return m, tea.Batch(tea.WindowSize(),
+			m.notify(ui.ToastWarning, "Saved the auto-yes policy, but the administrator's policy overrides it"))
+	}
+	return m, tea.Batch(tea.WindowSize(), m.notify(ui.ToastSuccess, "Saved the auto-yes policy"))
+}
@@ -0,0 +1,79 @@
+package app
+
+import (
+	"claude-squad/session/git"
+	"claude-squad/ui"
+	"claude-squad/ui/overlay"
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// backupBranchMsg reports that a backup branch was restored or deleted.
+type backupBranchMsg struct {
+	message string
+}
+
+// backupLocation describes where a backup branch exists.
+func backupLocation(backup git.Backup) string {
+	switch {
+	case backup.Local && backup.Remote:
+		return "local and origin"
+	case backup.Remote:
+		return "origin only"
+	default:
+		return "local only"
+	}
+}
+
+// showBackupBranches lists the backup branches of the selected instance's branch, made when it
+// was updated with main or reset to origin, to restore or delete one.
+func (m *home) showBackupBranches() tea.Cmd {
+	selected := m.list.GetSelectedInstance()
+	if selected == nil || !selected.Started() {
+		return nil
+	}
+	worktree, err := selected.GetGitWorktree()
+	if err != nil {
+		return m.handleError(err)
+	}
+	backups, err := worktree.ListBackups()
+	if err != nil {
+		return m.handleError(err)
+	}
+	if len(backups) == 0 {
+		return m.notify(ui.ToastInfo, fmt.Sprintf("No backup branches of %s yet", selected.Branch))
+	}
+
+	items := make([]overlay.ListItem, len(backups))
+	for i, backup := range backups {
+		items[i] = overlay.ListItem{
+			Title:       backup.CreatedAt.Format("2006-01-02 15:04:05"),
+			Description: fmt.Sprintf("%s %s • %s", backup.Commit, backup.Subject, backupLocation(backup)),
+		}
+	}
+
+	return m.selectFromList("Backups of "+selected.Branch, items, func(idx int) tea.Cmd {
+		backup := backups[idx]
+		restore := func() tea.Msg {
+			if selected.Paused() {
+				return fmt.Errorf("resume '%s' before restoring a backup", selected.Title)
+			}
+			current, err := worktree.RestoreBackup(backup)
+			if err != nil {
+				return err
+			}
+			return backupBranchMsg{message: fmt.Sprintf("Restored %s. Previous commit backed up as %s", backup.Name, current)}
+		}
+		remove := func() tea.Msg {
+			if err := worktree.DeleteBackup(backup); err != nil {
+				return err
+			}
+			return backupBranchMsg{message: fmt.Sprintf("Deleted backup branch %s", backup.Name)}
+		}
+		return m.confirmChoices(fmt.Sprintf("Backup %s (%s)", backup.Name, backupLocation(backup)), []confirmChoice{
+			{key: "r", label: "reset branch to this backup", action: restore},
+			{key: "d", label: "delete backup", action: remove},
+		})
+	})
+}
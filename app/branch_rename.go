@@ -0,0 +1,67 @@
+package app
+
+import (
+	"claude-squad/keys"
+	"claude-squad/session"
+	"claude-squad/ui"
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// branchFixedMsg reports that an instance's renamed branch was renamed back or retargeted.
+type branchFixedMsg struct {
+	message string
+}
+
+// warnRenamedBranch warns that a new instance works on a suffixed copy of the branch it was
+// created for, since its pushes would otherwise silently go to a new remote branch.
+func (m *home) warnRenamedBranch(instance *session.Instance) tea.Cmd {
+	requested := instance.RenamedFrom()
+	if requested == "" {
+		return nil
+	}
+	return m.notify(ui.ToastWarning, fmt.Sprintf(
+		"%s is checked out elsewhere, so '%s' is on %s and pushes to origin/%s. Press %s to fix.",
+		requested, instance.Title, instance.Branch, instance.PushBranch(),
+		keys.GlobalkeyBindings[keys.KeyFixBranch].Help().Key))
+}
+
+// confirmFixBranch offers to rename an instance's suffixed branch back to the branch it was
+// created for, or to keep it and push to that branch instead.
+func (m *home) confirmFixBranch(instance *session.Instance) tea.Cmd {
+	requested := instance.RenamedFrom()
+	if requested == "" {
+		return m.notify(ui.ToastInfo, fmt.Sprintf("The branch of '%s' wasn't renamed", instance.Title))
+	}
+
+	renamed := instance.Branch
+	rename := func() tea.Msg {
+		if err := instance.RenameBranchBack(); err != nil {
+			return err
+		}
+		return branchFixedMsg{message: fmt.Sprintf("Renamed %s back to %s", renamed, requested)}
+	}
+	retarget := func() tea.Msg {
+		if err := instance.PushToRequestedBranch(); err != nil {
+			return err
+		}
+		return branchFixedMsg{message: fmt.Sprintf("%s now tracks and pushes to origin/%s", renamed, requested)}
+	}
+
+	choices := []confirmChoice{{key: "r", label: "rename back to " + requested, action: rename}}
+	if instance.PushBranch() != requested {
+		choices = append(choices, confirmChoice{key: "p", label: "push to origin/" + requested, action: retarget})
+	}
+	return m.confirmChoices(fmt.Sprintf(
+		"[!] '%s' is on %s because %s was checked out elsewhere. Pushes go to origin/%s.",
+		instance.Title, renamed, requested, instance.PushBranch()), choices)
+}
+
+// handleBranchFixed saves the fixed branch and reports it.
+func (m *home) handleBranchFixed(msg branchFixedMsg) tea.Cmd {
+	if err := m.storage.SaveInstances(m.list.GetInstances()); err != nil {
+		return m.handleError(err)
+	}
+	return tea.Batch(m.showSuccess(msg.message), m.instanceChanged())
+}
@@ -0,0 +1,237 @@
+package app
+
+import (
+	"claude-squad/session"
+	"claude-squad/ui"
+	"claude-squad/ui/overlay"
+	"fmt"
+	"path"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// filterKeys are the instance fields a filter term can match with key:pattern.
+var filterKeys = map[string]bool{"tag": true, "group": true, "status": true, "program": true, "branch": true, "repo": true, "title": true}
+
+// filterTerm is one condition of an instance filter: a field matching a glob pattern, negated
+// with a leading !.
+type filterTerm struct {
+	key     string
+	pattern string
+	negate  bool
+}
+
+// parseInstanceFilter parses a filter expression: space-separated terms that must all match. A
+// term is key:pattern for one of filterKeys, or a bare pattern matching a tag. Patterns are
+// globs, e.g. "tag:front*" or "!status:paused".
+func parseInstanceFilter(expression string) ([]filterTerm, error) {
+	var terms []filterTerm
+	for _, word := range strings.Fields(expression) {
+		term := filterTerm{key: "tag"}
+		word, term.negate = strings.CutPrefix(word, "!")
+		if key, pattern, ok := strings.Cut(word, ":"); ok {
+			if !filterKeys[key] {
+				return nil, fmt.Errorf("unknown filter key %q", key)
+			}
+			term.key, word = key, pattern
+		}
+		if _, err := path.Match(word, ""); err != nil || word == "" {
+			return nil, fmt.Errorf("invalid filter pattern %q", word)
+		}
+		term.pattern = word
+		terms = append(terms, term)
+	}
+	if len(terms) == 0 {
+		return nil, fmt.Errorf("the filter is empty")
+	}
+	return terms, nil
+}
+
+// filterValues returns the values of the instance's field key that a term matches against.
+func filterValues(instance *session.Instance, key string) []string {
+	switch key {
+	case "tag":
+		return instance.Tags
+	case "group":
+		return []string{instance.Group}
+	case "status":
+		return []string{statusLabels[instance.Status]}
+	case "program":
+		return []string{instance.Program}
+	case "branch":
+		return []string{instance.Branch}
+	case "title":
+		return []string{instance.Title}
+	case "repo":
+		if repo, err := instance.RepoName(); err == nil {
+			return []string{repo}
+		}
+	}
+	return nil
+}
+
+// matchesFilter returns true if the instance matches every term.
+func matchesFilter(instance *session.Instance, terms []filterTerm) bool {
+	for _, term := range terms {
+		matched := false
+		for _, value := range filterValues(instance, term.key) {
+			if ok, _ := path.Match(term.pattern, value); ok {
+				matched = true
+				break
+			}
+		}
+		if matched == term.negate {
+			return false
+		}
+	}
+	return true
+}
+
+// broadcastResult is how a broadcast prompt was delivered to one instance.
+type broadcastResult struct {
+	title string
+	// outcome is "sent", "queued" or "skipped"; err is set when sending failed
+	outcome string
+	reason  string
+	err     error
+}
+
+// broadcastDoneMsg reports the delivery of a broadcast prompt.
+type broadcastDoneMsg struct {
+	filter  string
+	prompt  string
+	results []broadcastResult
+}
+
+// showBroadcastFilter asks for the filter choosing which instances a prompt is broadcast to,
+// starting from the last one used.
+func (m *home) showBroadcastFilter() tea.Cmd {
+	if m.list.NumInstances() == 0 {
+		return nil
+	}
+	m.broadcastTargets = nil
+	m.state = stateBroadcast
+	m.menu.SetState(ui.StateBookmark)
+	m.textInputOverlay = overlay.NewTextInputOverlay(
+		"Broadcast to sessions matching (tags, or tag:, group:, status:, program:, branch:, repo:, title: globs; ! negates)",
+		m.broadcastFilter)
+	return tea.WindowSize()
+}
+
+// handleBroadcastState passes key presses to the filter prompt and then to the prompt for the
+// matching instances, broadcasting the prompt once it is submitted.
+func (m *home) handleBroadcastState(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if !m.textInputOverlay.HandleKeyPress(msg) {
+		return m, nil
+	}
+	submitted, value := m.textInputOverlay.IsSubmitted(), strings.TrimSpace(m.textInputOverlay.GetValue())
+	targets := m.broadcastTargets
+	m.textInputOverlay = nil
+	m.broadcastTargets = nil
+	m.state = stateDefault
+	m.menu.SetState(ui.StateDefault)
+	if !submitted || value == "" {
+		return m, tea.WindowSize()
+	}
+
+	if targets == nil {
+		terms, err := parseInstanceFilter(value)
+		if err != nil {
+			return m, tea.Batch(tea.WindowSize(), m.handleError(err))
+		}
+		m.broadcastFilter = value
+		for _, instance := range m.list.GetInstances() {
+			if matchesFilter(instance, terms) {
+				targets = append(targets, instance)
+			}
+		}
+		if len(targets) == 0 {
+			return m, tea.Batch(tea.WindowSize(), m.notify(ui.ToastInfo, fmt.Sprintf("No sessions match '%s'", value)))
+		}
+		titles := make([]string, len(targets))
+		for n, instance := range targets {
+			titles[n] = instance.Title
+		}
+		m.broadcastTargets = targets
+		m.state = stateBroadcast
+		m.menu.SetState(ui.StateBookmark)
+		m.textInputOverlay = overlay.NewTextInputOverlay(
+			fmt.Sprintf("Prompt for %d sessions: %s", len(targets), strings.Join(titles, ", ")), "")
+		return m, tea.WindowSize()
+	}
+	return m, tea.Batch(tea.WindowSize(), m.broadcast(targets, value))
+}
+
+// broadcast sends prompt to the targets whose agent is waiting for input and queues it for those
+// whose agent is working. Paused instances are skipped. The queues change here, on the UI
+// goroutine, while the prompts are typed in the background.
+func (m *home) broadcast(targets []*session.Instance, prompt string) tea.Cmd {
+	results := make([]broadcastResult, len(targets))
+	var ready []int
+	queued := false
+	for n, instance := range targets {
+		results[n] = broadcastResult{title: instance.Title}
+		switch {
+		case !instance.Started() || instance.Paused():
+			results[n].outcome, results[n].reason = "skipped", "paused"
+		case instance.Status == session.Ready:
+			ready = append(ready, n)
+		case instance.Status == session.Running:
+			instance.EnqueuePrompt(prompt)
+			results[n].outcome, results[n].reason = "queued", "agent is working"
+			queued = true
+		default:
+			results[n].outcome, results[n].reason = "skipped", statusLabels[instance.Status]
+		}
+	}
+	if queued {
+		if err := m.storage.SaveInstances(m.list.GetInstances()); err != nil {
+			return m.handleError(err)
+		}
+	}
+
+	filter := m.broadcastFilter
+	return func() tea.Msg {
+		for _, n := range ready {
+			results[n].outcome = "sent"
+			if err := targets[n].SendPrompt(prompt); err != nil {
+				results[n].err = err
+			}
+		}
+		return broadcastDoneMsg{filter: filter, prompt: prompt, results: results}
+	}
+}
+
+// broadcastReport renders the delivery results of a broadcast prompt.
+func broadcastReport(msg broadcastDoneMsg) string {
+	counts := make(map[string]int)
+	var lines []string
+	for _, result := range msg.results {
+		outcome, detail := result.outcome, result.reason
+		if result.err != nil {
+			outcome, detail = "failed", result.err.Error()
+		}
+		counts[outcome]++
+		line := fmt.Sprintf("%-8s %s", outcome, result.title)
+		if detail != "" {
+			line += " (" + detail + ")"
+		}
+		lines = append(lines, line)
+	}
+	summary := fmt.Sprintf("Filter: %s\nPrompt: %s\n\nSent %d, queued %d, skipped %d, failed %d\n",
+		msg.filter, msg.prompt, counts["sent"], counts["queued"], counts["skipped"], counts["failed"])
+	return summary + "\n" + strings.Join(lines, "\n")
+}
+
+// showBroadcastReport shows the delivery results of a broadcast prompt.
+func (m *home) showBroadcastReport(msg broadcastDoneMsg) tea.Cmd {
+	m.historyOverlay = overlay.NewHistoryOverlay("Broadcast", broadcastReport(msg))
+	m.historyOverlay.OnDismiss = func() {
+		m.state = stateDefault
+		m.menu.SetState(ui.StateDefault)
+		m.historyOverlay = nil
+	}
+	m.state = stateHistory
+	return tea.WindowSize()
+}
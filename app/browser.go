@@ -0,0 +1,72 @@
+package app
+
+import (
+	"claude-squad/config"
+	"claude-squad/keys"
+	"claude-squad/session"
+	"claude-squad/ui"
+	"claude-squad/ui/overlay"
+	"fmt"
+	"os/exec"
+	"runtime"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// browserCommand returns the command opening url in the default browser on goos.
+func browserCommand(goos, url string) *exec.Cmd {
+	switch goos {
+	case "darwin":
+		return exec.Command("open", url)
+	case "windows":
+		return exec.Command("rundll32", "url.dll,FileProtocolHandler", url)
+	default:
+		return exec.Command("xdg-open", url)
+	}
+}
+
+// openInBrowser opens url in the default browser.
+func (m *home) openInBrowser(url string) tea.Cmd {
+	return tea.Batch(m.notify(ui.ToastInfo, "Opening "+url), func() tea.Msg {
+		if output, err := browserCommand(runtime.GOOS, url).CombinedOutput(); err != nil {
+			return fmt.Errorf("failed to open %s: %s (%w)", url, output, err)
+		}
+		return nil
+	})
+}
+
+// openBrowser opens the URL of the selected instance's running dev server in the browser,
+// letting the user pick one when several run.
+func (m *home) openBrowser() tea.Cmd {
+	selected := m.list.GetSelectedInstance()
+	if selected == nil || !selected.Started() || selected.Paused() {
+		return nil
+	}
+	states, err := selected.ServiceStates()
+	if err != nil {
+		return m.handleError(err)
+	}
+	var running []config.ServiceConfig
+	for _, svc := range config.LoadRepoConfig(m.servicesConfigPath(selected)).Services {
+		if states[svc.Name] == session.ServiceRunning {
+			running = append(running, svc)
+		}
+	}
+	if len(running) == 0 {
+		return m.notify(ui.ToastInfo, fmt.Sprintf("No dev servers of '%s' are running. Start one with %s",
+			selected.Title, keys.GlobalkeyBindings[keys.KeyServices].Help().Key))
+	}
+
+	urls := make([]string, len(running))
+	items := make([]overlay.ListItem, len(running))
+	for n, svc := range running {
+		urls[n] = svc.URLFor(selected.ServicePort(svc.Name))
+		items[n] = overlay.ListItem{Title: svc.Name, Description: urls[n]}
+	}
+	if len(urls) == 1 {
+		return m.openInBrowser(urls[0])
+	}
+	return m.selectFromList("Open in Browser", items, func(idx int) tea.Cmd {
+		return m.openInBrowser(urls[idx])
+	})
+}
@@ -0,0 +1,72 @@
+package app
+
+import (
+	"claude-squad/session"
+	"claude-squad/session/git"
+	"claude-squad/ui"
+	"claude-squad/ui/overlay"
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// ciCheckIcons marks the state of each check in the CI checks list
+var ciCheckIcons = map[string]string{
+	git.CIStateSuccess: "✓",
+	git.CIStateFailure: "✗",
+	git.CIStatePending: "…",
+}
+
+// ciChecksMsg carries the CI checks fetched for an instance's branch
+type ciChecksMsg struct {
+	instance *session.Instance
+	checks   []git.CICheck
+	err      error
+}
+
+// loadCIChecks fetches the CI checks of the selected instance's branch in the background, to
+// list them.
+func (m *home) loadCIChecks() tea.Cmd {
+	selected := m.list.GetSelectedInstance()
+	if selected == nil {
+		return nil
+	}
+	if cmd := m.requireRemote(selected, "CI checks"); cmd != nil {
+		return cmd
+	}
+	return tea.Batch(m.notify(ui.ToastInfo, fmt.Sprintf("Fetching CI checks of '%s'...", selected.Title)), func() tea.Msg {
+		checks, err := selected.LoadCIChecks()
+		return ciChecksMsg{instance: selected, checks: checks, err: err}
+	})
+}
+
+// showCIChecks lists the fetched CI checks, failed ones first; selecting one opens its details in
+// the browser.
+func (m *home) showCIChecks(msg ciChecksMsg) tea.Cmd {
+	if msg.err != nil {
+		return m.handleError(fmt.Errorf("failed to fetch CI checks: %w", msg.err))
+	}
+	if len(msg.checks) == 0 {
+		return m.notify(ui.ToastInfo, fmt.Sprintf("No CI checks have run on the pushed branch of '%s'", msg.instance.Title))
+	}
+
+	var checks []git.CICheck
+	for _, state := range []string{git.CIStateFailure, git.CIStatePending, git.CIStateSuccess} {
+		for _, check := range msg.checks {
+			if check.State == state {
+				checks = append(checks, check)
+			}
+		}
+	}
+	items := make([]overlay.ListItem, len(checks))
+	for n, check := range checks {
+		items[n] = overlay.ListItem{Title: ciCheckIcons[check.State] + " " + check.Name, Description: check.URL}
+	}
+	title := fmt.Sprintf("CI Checks - %s (%s)", msg.instance.Title, msg.instance.CIState())
+	return m.selectFromList(title, items, func(idx int) tea.Cmd {
+		if checks[idx].URL == "" {
+			return m.notify(ui.ToastInfo, fmt.Sprintf("%s has no link to its details", checks[idx].Name))
+		}
+		return m.openInBrowser(checks[idx].URL)
+	})
+}
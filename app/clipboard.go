@@ -0,0 +1,120 @@
+package app
+
+import (
+	"claude-squad/log"
+	"claude-squad/session"
+	"claude-squad/ui/overlay"
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// maxOfferedCodeBlocks is how many of the agent's latest code blocks the clipboard overlay offers.
+const maxOfferedCodeBlocks = 3
+
+// clipboardPreviewWidth is how many characters of a snippet the clipboard overlay shows.
+const clipboardPreviewWidth = 60
+
+// clipboardSnippet is a snippet the clipboard overlay offers to copy.
+type clipboardSnippet struct {
+	label string
+	text  string
+	// source describes where the snippet comes from, for snippets that aren't in the history yet
+	source string
+}
+
+// clipboardMsg carries the instance's HEAD commit and the agent's output read for the clipboard
+// overlay. Either is empty if it couldn't be read.
+type clipboardMsg struct {
+	instance *session.Instance
+	sha      string
+	output   string
+}
+
+// loadClipboard reads the instance's HEAD commit and the agent's output in the background, for
+// the clipboard overlay.
+func (m *home) loadClipboard(instance *session.Instance) tea.Cmd {
+	return func() tea.Msg {
+		msg := clipboardMsg{instance: instance}
+		if worktree, err := instance.GetGitWorktree(); err == nil && instance.Started() && !instance.Paused() {
+			if sha, err := worktree.GetCurrentCommitSHA(); err == nil {
+				msg.sha = sha
+			}
+		}
+		if output, err := instance.GetAIFullHistory(); err == nil {
+			msg.output = output
+		}
+		return msg
+	}
+}
+
+// showClipboard lets the user copy the instance's branch, commit, the hunk at the top of the
+// staging view or the agent's latest code blocks, or copy a snippet from its clipboard history
+// again.
+func (m *home) showClipboard(msg clipboardMsg) tea.Cmd {
+	instance := msg.instance
+	snippets := []clipboardSnippet{{label: "branch", text: instance.Branch, source: "branch name"}}
+	if msg.sha != "" {
+		snippets = append(snippets, clipboardSnippet{label: "commit", text: msg.sha, source: "HEAD commit SHA"})
+	}
+	if hunk, _, ok := m.tabbedWindow.SelectedHunk(); ok {
+		snippets = append(snippets, clipboardSnippet{label: "hunk", text: hunk.Body, source: "hunk of " + hunk.Path})
+	}
+	blocks := session.CodeBlocks(msg.output)
+	for _, block := range blocks[:min(len(blocks), maxOfferedCodeBlocks)] {
+		snippets = append(snippets, clipboardSnippet{label: "code block", text: block, source: "code block from the agent"})
+	}
+	offered := len(snippets)
+	for _, entry := range instance.ClipboardHistory() {
+		snippets = append(snippets, clipboardSnippet{label: entry.Label, text: entry.Text,
+			source: fmt.Sprintf("copied %s at %s", entry.Label, entry.CopiedAt.Format("Jan 2 15:04"))})
+	}
+
+	items := make([]overlay.ListItem, len(snippets))
+	for n, snippet := range snippets {
+		title := "Copy " + snippet.source
+		if n >= offered {
+			title = snippet.source
+		}
+		items[n] = overlay.ListItem{Title: title, Description: clipboardPreview(snippet.text)}
+	}
+	return m.selectFromList(fmt.Sprintf("Clipboard: %s", instance.Title), items, func(idx int) tea.Cmd {
+		return m.copySnippet(instance, snippets[idx])
+	})
+}
+
+// copySnippet copies a snippet to the system clipboard, recording it in the instance's history.
+func (m *home) copySnippet(instance *session.Instance, snippet clipboardSnippet) tea.Cmd {
+	if err := instance.Copy(snippet.label, snippet.text); err != nil {
+		return m.handleError(err)
+	}
+	if err := m.storage.SaveInstances(m.list.GetInstances()); err != nil {
+		return m.handleError(err)
+	}
+	return m.showSuccess(fmt.Sprintf("Copied %s to the clipboard", snippet.label))
+}
+
+// copyResult copies the result of an action, such as a file path or a link, to the system
+// clipboard, recording it in the instance's clipboard history. The result is shown whether or
+// not the clipboard can be written to, so that's only logged.
+func (m *home) copyResult(instance *session.Instance, label, text string) error {
+	if err := instance.Copy(label, text); err != nil {
+		log.WarningLog.Print(err)
+	}
+	return m.storage.SaveInstances(m.list.GetInstances())
+}
+
+// clipboardPreview returns the first line of text, cut to fit the overlay, noting how many more
+// lines it has.
+func clipboardPreview(text string) string {
+	lines := strings.Split(strings.TrimSpace(text), "\n")
+	preview := strings.TrimSpace(lines[0])
+	if runes := []rune(preview); len(runes) > clipboardPreviewWidth {
+		preview = string(runes[:clipboardPreviewWidth-1]) + "…"
+	}
+	if len(lines) > 1 {
+		preview += fmt.Sprintf(" (+%d lines)", len(lines)-1)
+	}
+	return preview
+}
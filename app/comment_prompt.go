@@ -0,0 +1,238 @@
+package app
+
+import (
+	"claude-squad/log"
+	"claude-squad/session/git"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"text/template"
+)
+
+// defaultCommentPromptTemplate is the built-in format for turning an accepted PR comment into a
+// prompt. Custom templates receive the same commentPromptData.
+const defaultCommentPromptTemplate = `Processing PR comment {{.Index}} of {{.Total}} from @{{.Author}}{{if .IsSplit}} ({{len .Pieces}} pieces selected){{end}}
+
+=== {{.Heading}} ===
+
+Author: @{{.Author}}
+Type: {{.TypeLabel}}
+{{if .Path}}File: {{.Path}}{{if .Line}} (line {{.Line}}){{end}}
+{{end}}
+Comment:
+---
+{{if .IsSplit}}Note: This comment has been split into pieces. Only the following selected pieces are included:
+
+{{end}}{{.Body}}
+---
+
+{{.Guidance}}If the comment is asking a question, provide a clear answer. If it's suggesting a change, implement it. If you need clarification, explain what's unclear.`
+
+// fixPromptTemplate combines every accepted comment into the single prompt sent to the agent.
+// It is executed with the commentPromptData of the comments.
+const fixPromptTemplate = `Please address the following {{len .}} accepted PR comment{{if gt (len .) 1}}s{{end}}. Work through them in order: implement requested changes, answer questions, and explain what is unclear if you need clarification.
+{{range .}}
+### {{.Index}}. {{if .Path}}{{.Path}}{{if .Line}}:{{.Line}}{{end}}{{else}}General{{end}} ({{.TypeLabel}} from @{{.Author}})
+{{if .IsSplit}}Only these selected parts of the comment apply:
+{{range .Pieces}}
+- {{.}}
+{{end}}{{else}}
+{{.Body}}
+{{end}}{{end}}
+When you have addressed all of them, summarize what you changed for each.`
+
+// commentPromptData is what a comment prompt template can reference.
+type commentPromptData struct {
+	// Index and Total are the comment's 1-based position in the batch being processed
+	Index int
+	Total int
+	// Author is the GitHub login of the commenter, without the @
+	Author string
+	// Type is the raw comment type: review, review_comment or issue_comment
+	Type string
+	// TypeLabel is a readable form of Type, e.g. "Review Comment"
+	TypeLabel string
+	// Heading is an upper-case title for the comment type, e.g. "PR REVIEW COMMENT"
+	Heading string
+	// Path and Line locate a line comment; both are empty for general comments
+	Path string
+	Line int
+	// Body is the comment text, or the accepted pieces joined by blank lines for a split comment
+	Body string
+	// Pieces are the accepted pieces of a split comment
+	Pieces  []string
+	IsSplit bool
+	// Guidance is a type-specific instruction sentence, ending with a space if not empty
+	Guidance string
+	// Program is the agent the prompt is sent to
+	Program string
+}
+
+// newCommentPromptData collects the template fields for a comment. ok is false if the comment
+// is split and none of its pieces were accepted, in which case there is nothing to send.
+func newCommentPromptData(comment *git.PRComment, index, total int, program string) (data commentPromptData, ok bool) {
+	data = commentPromptData{
+		Index:     index,
+		Total:     total,
+		Author:    comment.Author,
+		Type:      comment.Type,
+		TypeLabel: comment.Type,
+		Heading:   "PR COMMENT",
+		Path:      comment.Path,
+		Line:      comment.Line,
+		Body:      comment.Body,
+		IsSplit:   comment.IsSplit,
+		Program:   program,
+	}
+
+	switch comment.Type {
+	case "review":
+		data.TypeLabel = "PR Review"
+		data.Heading = "PR REVIEW"
+		data.Guidance = "This is a general PR review. Please address the overall feedback provided. "
+	case "review_comment":
+		data.TypeLabel = "Review Comment"
+		data.Heading = "PR REVIEW COMMENT"
+		data.Guidance = "This is a line-specific review comment. Please address the specific code feedback. "
+	case "issue_comment":
+		data.TypeLabel = "General Comment"
+		data.Heading = "PR GENERAL COMMENT"
+		data.Guidance = "This is a general PR discussion comment. Please respond appropriately. "
+	}
+
+	if comment.IsSplit {
+		for _, piece := range comment.GetAcceptedPieces() {
+			data.Pieces = append(data.Pieces, piece.Content)
+		}
+		if len(data.Pieces) == 0 {
+			return data, false
+		}
+		data.Body = strings.Join(data.Pieces, "\n\n")
+	}
+	return data, true
+}
+
+// renderCommentPrompt executes a comment prompt template.
+func renderCommentPrompt(text string, data commentPromptData) (string, error) {
+	tmpl, err := template.New("comment_prompt").Option("missingkey=error").Parse(text)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse comment prompt template: %w", err)
+	}
+	var out strings.Builder
+	if err := tmpl.Execute(&out, data); err != nil {
+		return "", fmt.Errorf("failed to render comment prompt template: %w", err)
+	}
+	return out.String(), nil
+}
+
+// agentName returns the executable name of a program command, e.g. "aider" for
+// "/usr/local/bin/aider --model x".
+func agentName(program string) string {
+	fields := strings.Fields(program)
+	if len(fields) == 0 {
+		return ""
+	}
+	return filepath.Base(fields[0])
+}
+
+// commentPromptProgram returns the program comment prompts are sent to: the selected instance's,
+// or the default program if nothing is selected.
+func (m *home) commentPromptProgram() string {
+	if selected := m.list.GetSelectedInstance(); selected != nil {
+		return selected.Program
+	}
+	return m.program
+}
+
+// builtinCommentPromptSource describes the built-in comment prompt template.
+const builtinCommentPromptSource = "built-in template"
+
+// commentPromptTemplate returns the configured template for the program and a description of
+// where it came from.
+func (m *home) commentPromptTemplate(program string) (text string, source string) {
+	if m.appConfig != nil {
+		name := agentName(program)
+		if custom, ok := m.appConfig.AgentCommentPromptTemplates[name]; ok && custom != "" {
+			return custom, fmt.Sprintf("agent_comment_prompt_templates[%q]", name)
+		}
+		if m.appConfig.CommentPromptTemplate != "" {
+			return m.appConfig.CommentPromptTemplate, "comment_prompt_template"
+		}
+	}
+	return defaultCommentPromptTemplate, builtinCommentPromptSource
+}
+
+// formatCommentAsPrompt turns a comment into the prompt sent to the selected instance's agent.
+// It returns "" for a split comment with no accepted pieces. A broken custom template is logged
+// and the built-in format is used instead.
+func (m *home) formatCommentAsPrompt(comment *git.PRComment, index int, total int) string {
+	program := m.commentPromptProgram()
+	data, ok := newCommentPromptData(comment, index, total, program)
+	if !ok {
+		return ""
+	}
+
+	text, source := m.commentPromptTemplate(program)
+	prompt, err := renderCommentPrompt(text, data)
+	if err != nil {
+		log.ErrorLog.Printf("%s: %v; using the built-in template", source, err)
+		prompt, _ = renderCommentPrompt(defaultCommentPromptTemplate, data)
+	}
+	return prompt
+}
+
+// formatFixPrompt combines the comments into one prompt for the selected instance's agent,
+// skipping split comments with no accepted pieces. With a custom comment prompt template each
+// comment is rendered with it instead of the built-in layout. It returns "" if nothing is left.
+func (m *home) formatFixPrompt(comments []*git.PRComment) string {
+	var sendable []*git.PRComment
+	for _, comment := range comments {
+		if !comment.IsSplit || len(comment.GetAcceptedPieces()) > 0 {
+			sendable = append(sendable, comment)
+		}
+	}
+	if len(sendable) == 0 {
+		return ""
+	}
+
+	program := m.commentPromptProgram()
+	if _, source := m.commentPromptTemplate(program); source != builtinCommentPromptSource {
+		prompts := make([]string, 0, len(sendable))
+		for i, comment := range sendable {
+			prompts = append(prompts, m.formatCommentAsPrompt(comment, i+1, len(sendable)))
+		}
+		return strings.Join(prompts, "\n\n")
+	}
+
+	entries := make([]commentPromptData, 0, len(sendable))
+	for i, comment := range sendable {
+		data, _ := newCommentPromptData(comment, i+1, len(sendable), program)
+		data.Body = strings.TrimSpace(data.Body)
+		entries = append(entries, data)
+	}
+	tmpl := template.Must(template.New("fix_prompt").Parse(fixPromptTemplate))
+	var out strings.Builder
+	if err := tmpl.Execute(&out, entries); err != nil {
+		log.ErrorLog.Printf("failed to render fix prompt: %v", err)
+		return ""
+	}
+	return out.String()
+}
+
+// previewCommentPrompt renders the prompt the comment would be sent as, prefixed with the
+// template it came from. Template errors are shown rather than hidden behind the fallback.
+func (m *home) previewCommentPrompt(comment *git.PRComment) string {
+	program := m.commentPromptProgram()
+	text, source := m.commentPromptTemplate(program)
+	header := fmt.Sprintf("Prompt preview for %s (%s)\n\n", agentName(program), source)
+
+	data, ok := newCommentPromptData(comment, 1, 1, program)
+	if !ok {
+		return header + "No pieces of this split comment are accepted, so it will be skipped."
+	}
+	prompt, err := renderCommentPrompt(text, data)
+	if err != nil {
+		return header + fmt.Sprintf("Error: %v\n\nThe built-in template will be used instead.", err)
+	}
+	return header + localizePrompt(m.list.GetSelectedInstance(), prompt)
+}
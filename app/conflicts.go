@@ -0,0 +1,224 @@
+package app
+
+import (
+	"claude-squad/config"
+	"claude-squad/keys"
+	"claude-squad/log"
+	"claude-squad/notify"
+	"claude-squad/session"
+	"claude-squad/session/git"
+	"claude-squad/ui"
+	"claude-squad/ui/overlay"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// showConflicts opens the conflict overlay for an instance whose rebase stopped on conflicts.
+func (m *home) showConflicts(instance *session.Instance) tea.Cmd {
+	worktree, err := instance.GetGitWorktree()
+	if err != nil {
+		return m.handleError(fmt.Errorf("failed to get git worktree: %w", err))
+	}
+	files, err := worktree.LoadConflicts()
+	if err != nil {
+		return m.handleError(err)
+	}
+
+	m.conflictInstance = instance
+	m.conflictOverlay = overlay.NewConflictOverlay(
+		fmt.Sprintf("Resolve rebase conflicts in '%s' (%s)", instance.Title, worktree.GetBranchName()), files)
+	m.state = stateConflicts
+	return tea.WindowSize()
+}
+
+// closeConflicts hides the conflict overlay.
+func (m *home) closeConflicts() {
+	m.conflictOverlay = nil
+	m.conflictInstance = nil
+	m.state = stateDefault
+}
+
+// reloadConflicts refreshes the overlay's files after a change to the worktree.
+func (m *home) reloadConflicts(worktree *git.GitWorktree) error {
+	files, err := worktree.LoadConflicts()
+	if err != nil {
+		return err
+	}
+	m.conflictOverlay.SetFiles(files)
+	return nil
+}
+
+// handleConflictsState passes key presses to the conflict overlay and carries out what it asks.
+func (m *home) handleConflictsState(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if m.conflictOverlay == nil || m.conflictInstance == nil {
+		m.closeConflicts()
+		return m, nil
+	}
+	instance := m.conflictInstance
+	worktree, err := instance.GetGitWorktree()
+	if err != nil {
+		m.closeConflicts()
+		return m, m.handleError(fmt.Errorf("failed to get git worktree: %w", err))
+	}
+
+	action := m.conflictOverlay.HandleKeyPress(msg)
+	switch action {
+	case overlay.ConflictActionClose:
+		m.closeConflicts()
+		return m, m.notify(ui.ToastInfo, fmt.Sprintf("Rebase of '%s' is still in progress; press %s to resume resolving it",
+			instance.Title, keys.GlobalkeyBindings[keys.KeyRebase].Help().Key))
+	case overlay.ConflictActionSave:
+		file := m.conflictOverlay.CurrentFile()
+		resolved, err := worktree.SaveConflictResolution(file, m.conflictOverlay.CurrentChoices())
+		if err != nil {
+			return m, m.handleError(err)
+		}
+		if err := m.reloadConflicts(worktree); err != nil {
+			return m, m.handleError(err)
+		}
+		if !resolved {
+			return m, m.notify(ui.ToastInfo, fmt.Sprintf("Saved %s; pick a side for its remaining hunks", file.Path))
+		}
+		return m, m.conflictProgress(file.Path)
+	case overlay.ConflictActionTakeOurs, overlay.ConflictActionTakeTheirs:
+		file := m.conflictOverlay.CurrentFile()
+		choice := git.ConflictOurs
+		if action == overlay.ConflictActionTakeTheirs {
+			choice = git.ConflictTheirs
+		}
+		if err := worktree.ResolveConflictWithSide(file.Path, choice); err != nil {
+			return m, m.handleError(err)
+		}
+		if err := m.reloadConflicts(worktree); err != nil {
+			return m, m.handleError(err)
+		}
+		return m, m.conflictProgress(file.Path)
+	case overlay.ConflictActionOpenFile:
+		return m, m.openFileInIDE(instance, m.conflictOverlay.CurrentFile().Path)
+	case overlay.ConflictActionMergeTool:
+		return m, m.runMergeTool(instance, worktree, m.conflictOverlay.CurrentFile().Path)
+	case overlay.ConflictActionContinue:
+		return m, m.continueRebase(instance, worktree)
+	case overlay.ConflictActionAbort:
+		m.closeConflicts()
+		if err := worktree.AbortRebase(); err != nil {
+			return m, m.handleError(err)
+		}
+		return m, tea.Batch(m.instanceChanged(), m.notify(ui.ToastWarning,
+			fmt.Sprintf("Aborted the rebase of %s; the branch is back where it was", worktree.GetBranchName())))
+	}
+	return m, nil
+}
+
+// mergeToolDoneMsg reports that the merge tool resolving a conflicted file exited.
+type mergeToolDoneMsg struct {
+	instance *session.Instance
+	path     string
+	err      error
+}
+
+// runMergeTool opens a conflicted file in the configured merge tool, with its base, our and
+// their versions extracted to temporary files. The tool runs in the background; the file is
+// marked resolved when it exits.
+func (m *home) runMergeTool(instance *session.Instance, worktree *git.GitWorktree, path string) tea.Cmd {
+	command := config.GetEffectiveMergeCommand(worktree.GetWorktreePath(), m.appConfig)
+	if command == "" {
+		return m.handleError(fmt.Errorf(noMergeToolConfiguredError))
+	}
+	files, err := worktree.ExtractMergeFiles(path)
+	if err != nil {
+		return m.handleError(err)
+	}
+	args, err := git.MergeToolArgs(command, files)
+	if err != nil {
+		_ = files.Cleanup()
+		return m.handleError(err)
+	}
+
+	run := func() tea.Msg {
+		defer func() {
+			if err := files.Cleanup(); err != nil {
+				log.WarningLog.Print(err)
+			}
+		}()
+		cmd := exec.Command(args[0], args[1:]...)
+		cmd.Dir = worktree.GetWorktreePath()
+		if output, err := cmd.CombinedOutput(); err != nil {
+			err = fmt.Errorf("merge tool %s failed: %w", args[0], err)
+			if details := strings.TrimSpace(string(output)); details != "" {
+				err = fmt.Errorf("%w: %s", err, details)
+			}
+			return mergeToolDoneMsg{instance: instance, path: path, err: err}
+		}
+		return mergeToolDoneMsg{instance: instance, path: path}
+	}
+	return tea.Batch(m.notify(ui.ToastInfo, fmt.Sprintf("Opened %s in %s; it is marked resolved when the tool exits", path, args[0])), run)
+}
+
+// handleMergeToolDone marks the file the merge tool resolved as resolved, unless conflict
+// markers remain in it.
+func (m *home) handleMergeToolDone(msg mergeToolDoneMsg) tea.Cmd {
+	if msg.err != nil {
+		return m.handleError(msg.err)
+	}
+	worktree, err := msg.instance.GetGitWorktree()
+	if err != nil {
+		return m.handleError(fmt.Errorf("failed to get git worktree: %w", err))
+	}
+	staged, err := worktree.StageMergedFile(msg.path)
+	if err != nil {
+		return m.handleError(err)
+	}
+	if !staged {
+		return m.notify(ui.ToastWarning, fmt.Sprintf("%s still has conflict markers; it was left unresolved", msg.path))
+	}
+	// The overlay may have been closed, or moved on to another instance, while the tool ran
+	if m.conflictOverlay == nil || m.conflictInstance != msg.instance {
+		return m.notify(ui.ToastSuccess, fmt.Sprintf("Resolved %s", msg.path))
+	}
+	if err := m.reloadConflicts(worktree); err != nil {
+		return m.handleError(err)
+	}
+	return m.conflictProgress(msg.path)
+}
+
+// conflictProgress reports a resolved file and how many are left.
+func (m *home) conflictProgress(path string) tea.Cmd {
+	if remaining := m.conflictOverlay.Remaining(); remaining > 0 {
+		return m.notify(ui.ToastInfo, fmt.Sprintf("Resolved %s; %d file(s) left", path, remaining))
+	}
+	return m.notify(ui.ToastSuccess, fmt.Sprintf("Resolved %s; press c to continue the rebase", path))
+}
+
+// continueRebase continues the rebase once every file is resolved. If a later commit conflicts
+// as well the overlay shows its conflicts; otherwise the rebase is done.
+func (m *home) continueRebase(instance *session.Instance, worktree *git.GitWorktree) tea.Cmd {
+	if remaining := m.conflictOverlay.Remaining(); remaining > 0 {
+		return m.notify(ui.ToastWarning, fmt.Sprintf("%d file(s) still have conflicts", remaining))
+	}
+
+	err := worktree.ContinueRebase()
+	var conflictErr *git.RebaseConflictError
+	if errors.As(err, &conflictErr) {
+		if err := m.reloadConflicts(worktree); err != nil {
+			return m.handleError(err)
+		}
+		return m.notify(ui.ToastWarning, fmt.Sprintf("The next commit conflicts too: %d file(s)", m.conflictOverlay.Remaining()))
+	}
+	if err != nil {
+		return m.handleError(err)
+	}
+
+	m.closeConflicts()
+	branch := worktree.GetBranchName()
+	return tea.Batch(
+		m.instanceChanged(),
+		m.showSuccess(fmt.Sprintf("Rebase of %s completed successfully", branch)),
+		m.sendInstanceEvent(notify.EventRebaseComplete, instance,
+			fmt.Sprintf("Rebase of %s completed after resolving conflicts", branch)),
+	)
+}
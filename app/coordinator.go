@@ -0,0 +1,93 @@
+package app
+
+import (
+	"claude-squad/session"
+	"claude-squad/ui"
+	"fmt"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// coordinatorRetryDelay is how soon a digest is tried again when the coordinator's agent was busy.
+const coordinatorRetryDelay = 30 * time.Second
+
+// coordinatorTickMsg triggers sending the coordinator a digest of the other instances
+type coordinatorTickMsg struct{}
+
+// scheduleCoordinatorDigest waits for delay, then triggers the coordinator's next digest.
+func (m *home) scheduleCoordinatorDigest(delay time.Duration) tea.Cmd {
+	return tea.Tick(delay, func(time.Time) tea.Msg {
+		return coordinatorTickMsg{}
+	})
+}
+
+// coordinatorDigestInterval is how often the coordinator is sent a digest.
+func (m *home) coordinatorDigestInterval() time.Duration {
+	return time.Duration(m.appConfig.CoordinatorDigestMinutes) * time.Minute
+}
+
+// coordinator returns the instance designated as coordinator, or nil if there is none.
+func (m *home) coordinator() *session.Instance {
+	for _, instance := range m.list.GetInstances() {
+		if instance.Coordinator {
+			return instance
+		}
+	}
+	return nil
+}
+
+// sendCoordinatorDigest sends the coordinator's agent a digest of the other instances once it is
+// idle with nothing queued, unless they haven't changed since the last digest, and schedules the
+// next one.
+func (m *home) sendCoordinatorDigest() tea.Cmd {
+	coordinator := m.coordinator()
+	if coordinator == nil || !coordinator.Started() || coordinator.Paused() {
+		return m.scheduleCoordinatorDigest(m.coordinatorDigestInterval())
+	}
+	if coordinator.Status != session.Ready || time.Since(coordinator.ReadySince()) < session.QueueDispatchIdle ||
+		len(coordinator.QueuedPrompts()) > 0 {
+		return m.scheduleCoordinatorDigest(coordinatorRetryDelay)
+	}
+
+	next := m.scheduleCoordinatorDigest(m.coordinatorDigestInterval())
+	digest := session.FleetDigest(coordinator, m.list.GetInstances())
+	if digest == m.lastCoordinatorDigest {
+		return next
+	}
+	if err := coordinator.SendPrompt(digest); err != nil {
+		return tea.Batch(next, m.handleError(fmt.Errorf("failed to send the digest to '%s': %w", coordinator.Title, err)))
+	}
+	m.lastCoordinatorDigest = digest
+	return next
+}
+
+// toggleCoordinator makes the selected instance the coordinator, replacing any other, or stops
+// it being one.
+func (m *home) toggleCoordinator() tea.Cmd {
+	selected := m.list.GetSelectedInstance()
+	if selected == nil {
+		return nil
+	}
+	coordinating := setCoordinator(m.list.GetInstances(), selected)
+	m.lastCoordinatorDigest = ""
+	if err := m.storage.SaveInstances(m.list.GetInstances()); err != nil {
+		return m.handleError(err)
+	}
+	if !coordinating {
+		return m.notify(ui.ToastInfo, fmt.Sprintf("'%s' is no longer the coordinator", selected.Title))
+	}
+	return m.notify(ui.ToastInfo, fmt.Sprintf("'%s' is now the coordinator, sent a digest of the other sessions every %d minutes",
+		selected.Title, m.appConfig.CoordinatorDigestMinutes))
+}
+
+// setCoordinator toggles whether instance is the coordinator, keeping at most one, and returns
+// whether it is one now.
+func setCoordinator(instances []*session.Instance, instance *session.Instance) bool {
+	coordinating := !instance.Coordinator
+	for _, other := range instances {
+		other.Coordinator = false
+	}
+	instance.Coordinator = coordinating
+	return coordinating
+}
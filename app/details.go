@@ -0,0 +1,110 @@
+package app
+
+import (
+	"claude-squad/keys"
+	"claude-squad/session"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// statusLabels maps instance statuses to the labels shown in the details overlay.
+var statusLabels = map[session.Status]string{
+	session.Running:  "running",
+	session.Ready:    "ready",
+	session.Loading:  "loading",
+	session.Paused:   "paused",
+	session.Creating: "creating",
+	session.Deleting: "deleting",
+}
+
+// maxDetailsPromptRuns is how many of the last prompts the details overlay lists.
+const maxDetailsPromptRuns = 10
+
+// instanceDetails renders the details overlay content for an instance: its metadata followed
+// by the last prompts sent with summaries of their runs, and its checkpoint notes, newest first.
+func instanceDetails(instance *session.Instance) string {
+	field := func(name, value string) string {
+		return keyStyle.Render(fmt.Sprintf("%-10s", name)) + descStyle.Render(value)
+	}
+
+	lines := []string{
+		field("Branch", instance.Branch),
+		field("Status", statusLabels[instance.Status]),
+		field("Program", instance.Program),
+		field("Created", instance.CreatedAt.Format("2006-01-02 15:04")),
+	}
+	if requested := instance.RenamedFrom(); requested != "" {
+		lines = append(lines, field("Renamed", fmt.Sprintf("from %s, pushes to origin/%s", requested, instance.PushBranch())))
+	}
+	if worktree, err := instance.GetGitWorktree(); err == nil && worktree != nil {
+		lines = append(lines, field("Worktree", worktree.GetWorktreePath()))
+	}
+	if len(instance.Tags) > 0 {
+		lines = append(lines, field("Tags", strings.Join(instance.Tags, ", ")))
+	}
+	worked := instance.WorkTime.Round(time.Second).String()
+	if instance.TimeBudget > 0 {
+		worked += " of " + instance.TimeBudget.String()
+		if instance.OverBudget() {
+			worked += " (over budget)"
+		}
+	}
+	lines = append(lines, field("Worked", worked))
+	if instance.MuteNotifications {
+		lines = append(lines, field("Notify", "muted"))
+	}
+	if instance.Coordinator {
+		lines = append(lines, field("Role", "coordinator"))
+	}
+	if len(instance.Services) > 0 {
+		states, _ := instance.ServiceStates()
+		services := make([]string, len(instance.Services))
+		for n, svc := range instance.Services {
+			state := states[svc.Name]
+			if state == "" {
+				state = session.ServiceStopped
+			}
+			services[n] = fmt.Sprintf("%s :%d (%s)", svc.Name, svc.Port, state)
+		}
+		lines = append(lines, field("Services", strings.Join(services, ", ")))
+	}
+	if pr := instance.GetPRStatus(); pr != nil {
+		lines = append(lines, field("PR", fmt.Sprintf("#%d %s %s", pr.Number, pr.State, pr.URL)))
+	}
+
+	runs := instance.PromptRuns()
+	lines = append(lines, "", headerStyle.Render(fmt.Sprintf("Prompts (%d):", len(runs))))
+	if len(runs) == 0 {
+		lines = append(lines, dimStyle.Render("No prompts sent yet."))
+	}
+	for i := len(runs) - 1; i >= 0 && i >= len(runs)-maxDetailsPromptRuns; i-- {
+		run := runs[i]
+		result := "working on it"
+		if run.Summary != nil {
+			result = fmt.Sprintf("%s after %s", run.Summary, run.Summary.FinishedAt.Sub(run.SentAt).Round(time.Second))
+		}
+		lines = append(lines,
+			dimStyle.Render(run.SentAt.Format(time.DateTime))+" "+descStyle.Render(firstLine(run.Prompt)),
+			"  "+result,
+		)
+	}
+
+	lines = append(lines, "", headerStyle.Render(fmt.Sprintf("Checkpoints (%d):", len(instance.Checkpoints))))
+	if len(instance.Checkpoints) == 0 {
+		lines = append(lines, dimStyle.Render(fmt.Sprintf("No checkpoints yet. Press %s to ask the agent for a context summary.",
+			keys.GlobalkeyBindings[keys.KeyCheckpoint].Help().Key)))
+	}
+	for i := len(instance.Checkpoints) - 1; i >= 0; i-- {
+		checkpoint := instance.Checkpoints[i]
+		lines = append(lines,
+			"",
+			titleStyle.Render(checkpoint.Name)+" "+dimStyle.Render(checkpoint.CreatedAt.Format(time.DateTime)),
+			checkpoint.Summary,
+		)
+	}
+
+	return lipgloss.JoinVertical(lipgloss.Left, lines...)
+}
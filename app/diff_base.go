@@ -0,0 +1,96 @@
+package app
+
+import (
+	"claude-squad/session"
+	"claude-squad/session/git"
+	"claude-squad/ui"
+	"claude-squad/ui/overlay"
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// diffBaseRefsMsg carries the branches and tags of an instance's repository to pick a diff base
+// from.
+type diffBaseRefsMsg struct {
+	instance *session.Instance
+	branches []git.BranchInfo
+	tags     []git.BranchInfo
+}
+
+// loadDiffBaseRefs lists the branches and tags to diff the selected instance against, or goes
+// back to diffing against its branch's base if another base is shown.
+func (m *home) loadDiffBaseRefs() tea.Cmd {
+	selected := m.list.GetSelectedInstance()
+	if selected == nil || !selected.Started() || selected.Paused() {
+		return nil
+	}
+	if base := m.tabbedWindow.DiffBase(); base != "" {
+		m.tabbedWindow.SetDiffBase("")
+		return tea.Batch(m.instanceChanged(),
+			m.notify(ui.ToastInfo, fmt.Sprintf("Diffing '%s' against its branch's base again", selected.Title)))
+	}
+	worktree, err := selected.GetGitWorktree()
+	if err != nil {
+		return m.handleError(err)
+	}
+	// Remote hosts' repositories can't be listed from here; a ref can still be entered
+	if selected.Host != "" {
+		return func() tea.Msg {
+			return diffBaseRefsMsg{instance: selected}
+		}
+	}
+	repo := worktree.GetRepoPath()
+	return func() tea.Msg {
+		branches, err := git.ListRemoteBranchesFromRepo(repo)
+		if err != nil {
+			return fmt.Errorf("failed to list remote branches: %w", err)
+		}
+		tags, err := git.ListTagsFromRepo(repo)
+		if err != nil {
+			return err
+		}
+		return diffBaseRefsMsg{instance: selected, branches: branches, tags: tags}
+	}
+}
+
+// showDiffBaseSelector opens the picker of the branch, tag or commit to diff the instance against.
+func (m *home) showDiffBaseSelector(msg diffBaseRefsMsg) tea.Cmd {
+	if msg.instance != m.list.GetSelectedInstance() {
+		return nil
+	}
+	m.diffBaseOverlay = overlay.NewBranchSelectorOverlay(msg.branches, msg.tags)
+	m.diffBaseOverlay.SetPurpose(fmt.Sprintf("Diff '%s' Against a Branch, Tag or Commit", msg.instance.Title),
+		"The diff tab shows every change since this commit.")
+	m.state = stateDiffBase
+	return m.diffBaseOverlay.Init()
+}
+
+// handleDiffBaseState passes key presses to the picker and, once a ref is picked, shows the diff
+// against it in the diff tab.
+func (m *home) handleDiffBaseState(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if m.diffBaseOverlay == nil {
+		m.state = stateDefault
+		return m, nil
+	}
+	_, cmd := m.diffBaseOverlay.Update(msg)
+	if !m.diffBaseOverlay.IsSelected() {
+		return m, cmd
+	}
+	ref := m.diffBaseOverlay.SelectedRef()
+	if ref == "" {
+		ref = m.diffBaseOverlay.SelectedBranch()
+	}
+	m.diffBaseOverlay = nil
+	m.state = stateDefault
+	if ref == "" {
+		return m, nil
+	}
+	// The diff pane only follows the selected instance while it's shown
+	m.tabbedWindow.SetTab(ui.DiffTab)
+	m.rememberTab()
+	m.menu.SetInDiffTab(true)
+	m.tabbedWindow.UpdateDiff(m.list.GetSelectedInstance())
+	m.tabbedWindow.SetDiffBase(ref)
+	return m, m.instanceChanged()
+}
@@ -0,0 +1,54 @@
+package app
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// startFilter starts typing a filter narrowing the list, continuing the current one.
+func (m *home) startFilter() tea.Cmd {
+	m.state = stateFilter
+	m.list.SetFilterEditing(true)
+	return nil
+}
+
+// handleFilterState edits the list's filter as it's typed. Enter keeps the filter and returns to
+// the list, Esc clears it.
+func (m *home) handleFilterState(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	filter := []rune(m.list.Filter())
+	switch msg.Type {
+	case tea.KeyEsc:
+		m.finishFilter()
+		m.list.SetFilter("")
+		return m, m.instanceChanged()
+	case tea.KeyEnter:
+		m.finishFilter()
+		return m, m.instanceChanged()
+	case tea.KeyUp:
+		m.list.Up()
+		return m, m.instanceChanged()
+	case tea.KeyDown:
+		m.list.Down()
+		return m, m.instanceChanged()
+	case tea.KeyBackspace:
+		if len(filter) == 0 {
+			return m, nil
+		}
+		filter = filter[:len(filter)-1]
+	case tea.KeyCtrlU:
+		filter = nil
+	case tea.KeySpace:
+		filter = append(filter, ' ')
+	case tea.KeyRunes:
+		filter = append(filter, msg.Runes...)
+	default:
+		return m, nil
+	}
+	m.list.SetFilter(string(filter))
+	return m, m.instanceChanged()
+}
+
+// finishFilter stops typing the filter, which stays applied.
+func (m *home) finishFilter() {
+	m.state = stateDefault
+	m.list.SetFilterEditing(false)
+}
@@ -0,0 +1,96 @@
+package app
+
+import (
+	"claude-squad/log"
+	"claude-squad/session"
+	"claude-squad/ui"
+	"fmt"
+	"os"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// watchFinished notices when the instance's agent finishes working or a command run in its
+// terminal pane exits, marking the instance in the list and ringing the terminal bell.
+func (m *home) watchFinished(instance *session.Instance) tea.Cmd {
+	if m.agentWorking == nil {
+		m.agentWorking = make(map[string]bool)
+	}
+	var what string
+	switch instance.Status {
+	case session.Running:
+		m.agentWorking[instance.Title] = true
+	case session.Ready:
+		// The status flickers to Ready between bursts of output, so wait for it to settle
+		if m.agentWorking[instance.Title] && time.Since(instance.ReadySince()) >= session.QueueDispatchIdle {
+			delete(m.agentWorking, instance.Title)
+			what = "agent is ready"
+		}
+	}
+	command, err := instance.TerminalCommandFinished()
+	if err != nil {
+		log.WarningLog.Printf("could not check the terminal of %s: %v", instance.Title, err)
+	}
+	if what == "" {
+		what = command
+	}
+	if what == "" {
+		return nil
+	}
+
+	instance.MarkFinished(what)
+	for n, finished := range m.finishedOrder {
+		if finished == instance {
+			m.finishedOrder = append(m.finishedOrder[:n], m.finishedOrder[n+1:]...)
+			break
+		}
+	}
+	m.finishedOrder = append(m.finishedOrder, instance)
+	if m.appConfig.DisableFinishBell {
+		return nil
+	}
+	return ringBell
+}
+
+// ringBell rings the terminal bell. It's written to stderr, which is the terminal the UI is
+// drawn on, so it doesn't go through the renderer.
+func ringBell() tea.Msg {
+	fmt.Fprint(os.Stderr, "\a")
+	return nil
+}
+
+// jumpToFinished selects the most recently finished instance not looked at yet, or else the most
+// recently finished one, so repeated presses walk back through the unseen ones.
+func (m *home) jumpToFinished() tea.Cmd {
+	listed := make(map[*session.Instance]int)
+	for idx, instance := range m.list.GetInstances() {
+		listed[instance] = idx
+	}
+	// Drop the instances killed since they finished
+	finished := m.finishedOrder[:0]
+	for _, instance := range m.finishedOrder {
+		if _, ok := listed[instance]; ok {
+			finished = append(finished, instance)
+		}
+	}
+	m.finishedOrder = finished
+	if len(finished) == 0 {
+		return m.notify(ui.ToastInfo, "No session has finished yet")
+	}
+
+	target := finished[len(finished)-1]
+	for n := len(finished) - 1; n >= 0; n-- {
+		if finished[n].Finished() != "" {
+			target = finished[n]
+			break
+		}
+	}
+	what := target.Finished()
+	m.list.SetSelectedInstance(listed[target])
+	cmd := m.instanceChanged()
+	if what == "" {
+		return cmd
+	}
+	return tea.Batch(cmd, m.notify(ui.ToastInfo, fmt.Sprintf("'%s': %s", target.Title, what)))
+}
@@ -0,0 +1,109 @@
+package app
+
+import (
+	"claude-squad/session"
+	"claude-squad/session/git"
+	"claude-squad/ui/overlay"
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// gitCommandsMsg carries the commands a confirmation's action runs, loaded for its overlay.
+type gitCommandsMsg struct {
+	overlay  *overlay.ConfirmationOverlay
+	commands []string
+}
+
+// commandPlan returns the commands an action would run, found by a dry run. The error is the
+// one the action would stop at, with the commands up to it.
+type commandPlan func() ([]string, error)
+
+// choicePlan is the command plan of one choice of a confirmation.
+type choicePlan struct {
+	key  string
+	plan commandPlan
+}
+
+// offerGitCommands lets the open confirmation show the commands its action runs, as listed by
+// plan. They're shown right away if show_git_commands is set.
+func (m *home) offerGitCommands(plan commandPlan) tea.Cmd {
+	confirmation := m.confirmationOverlay
+	if confirmation == nil {
+		return nil
+	}
+	confirmation.LoadCommands = func() tea.Cmd {
+		return func() tea.Msg {
+			commands, err := plan()
+			if err != nil {
+				commands = append(commands, planStop(err))
+			}
+			return gitCommandsMsg{overlay: confirmation, commands: commands}
+		}
+	}
+	if !m.appConfig.ShowGitCommands {
+		return nil
+	}
+	confirmation.ToggleCommands()
+	return confirmation.PendingCmd()
+}
+
+// choicePlans combines the plans of several choices, listing each one's commands under its key.
+func choicePlans(plans ...choicePlan) commandPlan {
+	return func() ([]string, error) {
+		var lines []string
+		for _, choice := range plans {
+			commands, err := choice.plan()
+			if err != nil {
+				commands = append(commands, planStop(err))
+			}
+			lines = append(lines, choice.key+":")
+			for _, command := range commands {
+				lines = append(lines, "  "+command)
+			}
+		}
+		return lines, nil
+	}
+}
+
+// planStop notes the error a planned action would stop at.
+func planStop(err error) string {
+	message, _, _ := strings.Cut(err.Error(), "\n")
+	return fmt.Sprintf("(stops here: %s)", message)
+}
+
+// worktreePlan returns the plan of running fn on the instance's worktree.
+func worktreePlan(instance *session.Instance, fn func(*git.GitWorktree) error) commandPlan {
+	return func() ([]string, error) {
+		worktree, err := instance.GetGitWorktree()
+		if err != nil {
+			return nil, err
+		}
+		return worktree.DryRun(fn)
+	}
+}
+
+// mergeStrategyPlans returns the plans of updating the instance's branch with main using each
+// strategy, keyed like the update confirmation's choices.
+func mergeStrategyPlans(instance *session.Instance) commandPlan {
+	plans := make([]choicePlan, 0, len(git.MergeStrategies))
+	for _, strategy := range git.MergeStrategies {
+		strategy := strategy
+		plans = append(plans, choicePlan{
+			key: mergeStrategyChoice[strategy].key,
+			plan: worktreePlan(instance, func(wt *git.GitWorktree) error {
+				return wt.MergeWithMain(strategy)
+			}),
+		})
+	}
+	return choicePlans(plans...)
+}
+
+// killPlans returns the plans of killing the instance, deleting or keeping its branch.
+func killPlans(instance *session.Instance) commandPlan {
+	return choicePlans(
+		choicePlan{key: "d", plan: func() ([]string, error) { return instance.KillGitCommands(false) }},
+		choicePlan{key: "k", plan: func() ([]string, error) { return instance.KillGitCommands(true) }},
+	)
+}
@@ -0,0 +1,77 @@
+package app
+
+import (
+	"claude-squad/session/git"
+	"fmt"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// gitStatsContent renders the git command timings: totals per subcommand, the slowest runs and
+// the hit rates of the caches that save git commands.
+func gitStatsContent(stats git.Stats) string {
+	var count int
+	var total time.Duration
+	for _, command := range stats.Commands {
+		count += command.Count
+		total += command.Total
+	}
+	lines := []string{
+		descStyle.Render(fmt.Sprintf("%d git commands taking %s since %s (%s ago)", count, total.Round(time.Millisecond),
+			stats.Since.Format(time.TimeOnly), time.Since(stats.Since).Round(time.Second))),
+		"",
+		headerStyle.Render("By command"),
+	}
+	if len(stats.Commands) == 0 {
+		lines = append(lines, dimStyle.Render("No git commands have run yet."))
+	} else {
+		lines = append(lines, statsTable(func(w *tabwriter.Writer) {
+			fmt.Fprintln(w, "COMMAND\tCOUNT\tTOTAL\tMEAN\tMAX\tFAILED")
+			for _, command := range stats.Commands {
+				fmt.Fprintf(w, "%s\t%d\t%s\t%s\t%s\t%d\n", command.Name, command.Count, command.Total.Round(time.Millisecond),
+					command.Mean().Round(time.Millisecond), command.Max.Round(time.Millisecond), command.Failures)
+			}
+		}))
+	}
+
+	lines = append(lines, "", headerStyle.Render("Slowest commands"))
+	if len(stats.Slowest) == 0 {
+		lines = append(lines, dimStyle.Render("None yet."))
+	} else {
+		lines = append(lines, statsTable(func(w *tabwriter.Writer) {
+			for _, run := range stats.Slowest {
+				failed := ""
+				if run.Failed {
+					failed = " (failed)"
+				}
+				fmt.Fprintf(w, "%s\t%s\tgit %s%s\t%s\n", run.Duration.Round(time.Millisecond), run.At.Format(time.TimeOnly),
+					run.Args, failed, run.Dir)
+			}
+		}))
+	}
+
+	lines = append(lines, "", headerStyle.Render("Caches"))
+	if len(stats.Caches) == 0 {
+		lines = append(lines, dimStyle.Render("No cache lookups yet."))
+	} else {
+		lines = append(lines, statsTable(func(w *tabwriter.Writer) {
+			for _, cache := range stats.Caches {
+				fmt.Fprintf(w, "%s\t%.0f%% hits\t%d hits, %d misses\n", cache.Name, cache.HitRate()*100, cache.Hits, cache.Misses)
+			}
+		}))
+	}
+
+	return lipgloss.JoinVertical(lipgloss.Left, lines...)
+}
+
+// statsTable renders the rows written by write as aligned columns.
+func statsTable(write func(w *tabwriter.Writer)) string {
+	var b strings.Builder
+	w := tabwriter.NewWriter(&b, 0, 0, 2, ' ', 0)
+	write(w)
+	w.Flush()
+	return strings.TrimRight(b.String(), "\n")
+}
@@ -0,0 +1,215 @@
+package app
+
+import (
+	"claude-squad/session"
+	"claude-squad/ui"
+	"claude-squad/ui/overlay"
+	"errors"
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// saveGroups saves the groups and the instances, whose groups they record.
+func (m *home) saveGroups() tea.Cmd {
+	if err := m.storage.SaveGroups(m.list.Groups()); err != nil {
+		return m.handleError(err)
+	}
+	if err := m.storage.SaveInstances(m.list.GetInstances()); err != nil {
+		return m.handleError(err)
+	}
+	return nil
+}
+
+// showMoveToGroup lets the user pick the group to move the instance to, or name a new one.
+func (m *home) showMoveToGroup(instance *session.Instance) tea.Cmd {
+	var targets []string
+	for _, group := range m.list.Groups() {
+		if group.Name != instance.Group {
+			targets = append(targets, group.Name)
+		}
+	}
+	items := make([]overlay.ListItem, 0, len(targets)+2)
+	for _, name := range targets {
+		items = append(items, overlay.ListItem{
+			Title:       name,
+			Description: fmt.Sprintf("%d session(s)", len(m.list.GroupInstances(name))),
+		})
+	}
+	items = append(items, overlay.ListItem{Title: "New group...", Description: "name a new group to move it to"})
+	if instance.Group != "" {
+		items = append(items, overlay.ListItem{Title: "No group", Description: "remove it from " + instance.Group})
+	}
+
+	return m.selectFromList(fmt.Sprintf("Move '%s' to group", instance.Title), items, func(idx int) tea.Cmd {
+		switch {
+		case idx < len(targets):
+			return m.moveToGroup(instance, targets[idx])
+		case idx == len(targets):
+			return m.showGroupNamePrompt(instance, "")
+		}
+		return m.moveToGroup(instance, "")
+	})
+}
+
+// moveToGroup moves the instance into the named group, or out of its group if name is empty.
+func (m *home) moveToGroup(instance *session.Instance, name string) tea.Cmd {
+	m.list.MoveToGroup(instance, name)
+	message := fmt.Sprintf("Moved '%s' to group %s", instance.Title, name)
+	if name == "" {
+		message = fmt.Sprintf("Removed '%s' from its group", instance.Title)
+	}
+	return tea.Batch(m.saveGroups(), m.showSuccess(message), m.instanceChanged())
+}
+
+// showGroupNamePrompt asks for the name of a new group to move instance to, or for the new name
+// of the group renaming.
+func (m *home) showGroupNamePrompt(instance *session.Instance, renaming string) tea.Cmd {
+	m.groupInstance = instance
+	m.renamingGroup = renaming
+	m.state = stateGroupName
+	m.menu.SetState(ui.StateBookmark)
+	title := "Name of the new group"
+	if renaming != "" {
+		title = fmt.Sprintf("Rename group %s", renaming)
+	}
+	m.textInputOverlay = overlay.NewTextInputOverlay(title, renaming)
+	return tea.WindowSize()
+}
+
+// handleGroupNameState passes key presses to the group name prompt and applies the name once
+// it's submitted.
+func (m *home) handleGroupNameState(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if !m.textInputOverlay.HandleKeyPress(msg) {
+		return m, nil
+	}
+	submitted, value := m.textInputOverlay.IsSubmitted(), m.textInputOverlay.GetValue()
+	instance, renaming := m.groupInstance, m.renamingGroup
+	m.textInputOverlay = nil
+	m.groupInstance = nil
+	m.renamingGroup = ""
+	m.state = stateDefault
+	m.menu.SetState(ui.StateDefault)
+	if !submitted {
+		return m, tea.WindowSize()
+	}
+
+	name, err := session.ValidateGroupName(value)
+	if err != nil {
+		return m, tea.Batch(tea.WindowSize(), m.handleError(err))
+	}
+	if renaming != "" {
+		if err := m.list.RenameGroup(renaming, name); err != nil {
+			return m, tea.Batch(tea.WindowSize(), m.handleError(err))
+		}
+		return m, tea.Batch(tea.WindowSize(), m.saveGroups(),
+			m.showSuccess(fmt.Sprintf("Renamed group %s to %s", renaming, name)))
+	}
+	if instance == nil {
+		return m, tea.WindowSize()
+	}
+	return m, tea.Batch(tea.WindowSize(), m.moveToGroup(instance, name))
+}
+
+// toggleGroup collapses or expands the selected group.
+func (m *home) toggleGroup() tea.Cmd {
+	if !m.list.ToggleSelectedGroup() {
+		return m.notify(ui.ToastInfo, "The selected session isn't in a group")
+	}
+	if err := m.storage.SaveGroups(m.list.Groups()); err != nil {
+		return m.handleError(err)
+	}
+	return m.instanceChanged()
+}
+
+// confirmGroupActions offers the actions that apply to every instance of the selected group.
+func (m *home) confirmGroupActions() tea.Cmd {
+	name := m.list.SelectedGroup()
+	if name == "" {
+		return m.notify(ui.ToastInfo, "The selected session isn't in a group")
+	}
+	count := len(m.list.GroupInstances(name))
+	message := fmt.Sprintf("[!] Act on the %d session(s) of group %s?", count, name)
+	return m.confirmChoices(message, []confirmChoice{
+		{key: "c", label: "pause all, committing their changes", action: func() tea.Msg {
+			return groupActionMsg{group: name, action: groupPause}
+		}},
+		{key: "r", label: "resume all", action: func() tea.Msg {
+			return groupActionMsg{group: name, action: groupResume}
+		}},
+		{key: "n", label: "rename group", action: func() tea.Msg {
+			return groupActionMsg{group: name, action: groupRename}
+		}},
+		{key: "u", label: "ungroup, keeping the sessions", action: func() tea.Msg {
+			return groupActionMsg{group: name, action: groupUngroup}
+		}},
+	})
+}
+
+// groupAction is an action on every instance of a group.
+type groupAction int
+
+const (
+	groupPause groupAction = iota
+	groupResume
+	groupRename
+	groupUngroup
+)
+
+// groupActionMsg is sent to run a group action once it's confirmed.
+type groupActionMsg struct {
+	group  string
+	action groupAction
+}
+
+// handleGroupAction runs a confirmed group action.
+func (m *home) handleGroupAction(msg groupActionMsg) tea.Cmd {
+	switch msg.action {
+	case groupRename:
+		return m.showGroupNamePrompt(nil, msg.group)
+	case groupUngroup:
+		m.list.RemoveGroup(msg.group)
+		return tea.Batch(m.saveGroups(), m.showSuccess(fmt.Sprintf("Removed group %s", msg.group)), m.instanceChanged())
+	}
+
+	var errs []error
+	count := 0
+	for _, instance := range m.list.GroupInstances(msg.group) {
+		if !instance.Started() {
+			continue
+		}
+		var err error
+		switch {
+		case msg.action == groupPause && !instance.Paused():
+			err = instance.Pause()
+		case msg.action == groupResume && instance.Paused():
+			if err = m.policy.CheckProgram(instance.Program); err == nil {
+				err = instance.Resume()
+			}
+		default:
+			continue
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("'%s': %w", instance.Title, err))
+			continue
+		}
+		count++
+	}
+
+	verb, done := "pause", "Paused"
+	if msg.action == groupResume {
+		verb, done = "resume", "Resumed"
+	}
+	cmds := []tea.Cmd{m.instanceChanged(), tea.WindowSize()}
+	if count > 0 {
+		if err := m.storage.SaveInstances(m.list.GetInstances()); err != nil {
+			cmds = append(cmds, m.handleError(err))
+		}
+		cmds = append(cmds, m.showSuccess(fmt.Sprintf("%s %d session(s) of group %s", done, count, msg.group)))
+	}
+	if len(errs) > 0 {
+		cmds = append(cmds, m.handleError(fmt.Errorf("failed to %s some sessions of group %s: %w",
+			verb, msg.group, errors.Join(errs...))))
+	}
+	return tea.Batch(cmds...)
+}
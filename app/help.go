@@ -40,49 +40,96 @@ func (h helpTypeGeneral) toContent() string {
 		"A terminal UI that manages multiple Claude Code (and other local agents) in separate workspaces.",
 		"",
 		headerStyle.Render("Managing Sessions:"),
-		keyStyle.Render("n")+descStyle.Render("         - Create a new session"),
-		keyStyle.Render("N")+descStyle.Render("         - Create a new session with a prompt"),
-		keyStyle.Render("e")+descStyle.Render("         - Create session from existing branch"),
-		keyStyle.Render("D")+descStyle.Render("         - Kill (delete) the selected session"),
+		keyStyle.Render("n")+descStyle.Render("         - Create a new session (offers the program the repo suggests)"),
+		keyStyle.Render("N")+descStyle.Render("         - Create a new session with a prompt (empty name: named from prompt)"),
+		keyStyle.Render("alt+n")+descStyle.Render("     - Create a new session with the main checkout's uncommitted changes"),
+		keyStyle.Render("Q")+descStyle.Render("         - Queue prompts to send one at a time whenever the agent is ready"),
+		keyStyle.Render("T")+descStyle.Render("         - Create a new session from a template in the config"),
+		keyStyle.Render("e")+descStyle.Render("         - Create session from an existing branch, tag or commit"),
+		keyStyle.Render("I")+descStyle.Render("         - Import existing branches as paused sessions"),
+		keyStyle.Render("alt+a")+descStyle.Render("     - Adopt a worktree created outside claude-squad as a session"),
+		keyStyle.Render("D")+descStyle.Render("         - Kill the selected session (delete, keep or archive branch, rate the run)"),
+		keyStyle.Render("C")+descStyle.Render("         - Save agent context summary as a checkpoint note"),
+		keyStyle.Render("v")+descStyle.Render("         - Show session details and checkpoint notes"),
+		keyStyle.Render("E")+descStyle.Render("         - Edit session settings: auto-yes, notifications, time budget, tags"),
+		keyStyle.Render("alt+d")+descStyle.Render("     - Make the session the coordinator, sent digests of the others"),
+		keyStyle.Render("Y")+descStyle.Render("         - Edit the auto-yes policy: prompts to always or never confirm"),
+		keyStyle.Render("m")+descStyle.Render("         - Move the selected session to a group, or a new one"),
+		keyStyle.Render("z")+descStyle.Render("         - Collapse or expand the selected group"),
+		keyStyle.Render("O")+descStyle.Render("         - Group actions: pause or resume all, rename, ungroup"),
+		keyStyle.Render("alt+p")+descStyle.Render("     - Broadcast a prompt to all sessions matching a tag or filter"),
+		keyStyle.Render("J")+descStyle.Render("         - List background operations (rebase, push, start, kill) to cancel one"),
+		keyStyle.Render("L")+descStyle.Render("         - Pick the repository or remote host new sessions are created in"),
+		keyStyle.Render("ctrl-w")+descStyle.Render("    - Move the session's worktree to another path or disk"),
 		keyStyle.Render("↑/k, ↓/j")+descStyle.Render("  - Navigate between sessions"),
 		keyStyle.Render("↵/o")+descStyle.Render("       - Attach to the selected session"),
 		keyStyle.Render("ctrl-q")+descStyle.Render("    - Detach from session"),
 		"",
 		headerStyle.Render("Git & Handoff:"),
-		keyStyle.Render("p")+descStyle.Render("         - Commit and push branch to GitHub"),
+		keyStyle.Render("p")+descStyle.Render("         - Commit with an editable message and push to origin (optionally open a PR)"),
 		keyStyle.Render("c")+descStyle.Render("         - Checkout: commit changes and pause session"),
-		keyStyle.Render("r")+descStyle.Render("         - Resume a paused session"),
-		keyStyle.Render("b")+descStyle.Render("         - Rebase with main branch"),
+		keyStyle.Render("r")+descStyle.Render("         - Resume a paused session, or repair one whose worktree was deleted"),
+		keyStyle.Render("b")+descStyle.Render("         - Update with main: rebase, merge or squash (conflicts resolved in place)"),
+		keyStyle.Render("alt+l")+descStyle.Render("     - Show the step log of the session's last update with main, or abort it"),
+		keyStyle.Render("alt+i")+descStyle.Render("     - Reorder, reword, squash or drop the branch's commits"),
 		keyStyle.Render("h")+descStyle.Render("         - Git reset --hard to origin/branch"),
+		keyStyle.Render("f")+descStyle.Render("         - Rename an auto-renamed branch (⚠) back, or push it to the branch asked for"),
+		keyStyle.Render("P")+descStyle.Render("         - Export branch diff as patch or apply it to main checkout"),
+		keyStyle.Render("y")+descStyle.Render("         - Copy the branch, commit SHA or agent code blocks, or earlier copies again"),
+		keyStyle.Render("S")+descStyle.Render("         - Share diff as an HTML page link with QR code"),
 		keyStyle.Render("B")+descStyle.Render("         - Create bookmark commit"),
 		keyStyle.Render("g")+descStyle.Render("         - Show git status"),
 		keyStyle.Render("G")+descStyle.Render("         - Show git status bookmarks"),
+		keyStyle.Render("ctrl-g")+descStyle.Render("    - Show git command timings and cache hit rates"),
 		"",
 		headerStyle.Render("IDE & Tools:"),
 		keyStyle.Render("w")+descStyle.Render("         - Open current instance in IDE"),
 		keyStyle.Render("i")+descStyle.Render("         - Open current file in IDE (diff view)"),
 		keyStyle.Render("x")+descStyle.Render("         - Open in external diff tool"),
 		keyStyle.Render("t")+descStyle.Render("         - Run tests"),
+		keyStyle.Render("ctrl-t")+descStyle.Render("    - Suggest tests for the diff (agent or suggest_tests_command)"),
 		keyStyle.Render("R")+descStyle.Render("         - Review PR comments"),
+		keyStyle.Render("alt+c")+descStyle.Render("     - List the CI checks of the pushed branch"),
+		keyStyle.Render("alt+w")+descStyle.Render("     - Compose a prompt to resume work on the PR"),
 		keyStyle.Render("ctrl+r")+descStyle.Render("    - Resolve all PR conversations"),
+		keyStyle.Render("A")+descStyle.Render("         - Re-authenticate expired gh/git credentials"),
 		"",
 		headerStyle.Render("Navigation:"),
 		keyStyle.Render("tab")+descStyle.Render("       - Switch between AI, diff, and terminal tabs"),
-		keyStyle.Render("shift-↓/↑")+descStyle.Render(" - Scroll in diff view"),
+		keyStyle.Render("shift-↓/↑")+descStyle.Render(" - Scroll active pane (speeds up when held)"),
 		keyStyle.Render("s")+descStyle.Render("         - Toggle scroll lock (↓/↑ scrolls diff)"),
 		keyStyle.Render("home/end")+descStyle.Render("  - Scroll to top/bottom"),
 		keyStyle.Render("ctrl+a/e")+descStyle.Render("  - Alternative: scroll to top/bottom"),
 		keyStyle.Render("pgup/pgdn")+descStyle.Render(" - Page up/down"),
-		keyStyle.Render("alt-↓/↑")+descStyle.Render("   - Jump to next/prev file in diff"),
+		keyStyle.Render("ctrl+u/d")+descStyle.Render("  - Half page up/down"),
+		keyStyle.Render("ctrl-↓/↑")+descStyle.Render("  - Jump 10 lines down/up"),
+		keyStyle.Render("alt-↓/↑")+descStyle.Render("   - Jump to next/prev file header"),
+		keyStyle.Render("W")+descStyle.Render("         - Toggle soft wrap of long lines in AI and diff panes"),
+		keyStyle.Render("shift-←/→")+descStyle.Render(" - Pan long lines left/right when not wrapping"),
+		keyStyle.Render("V")+descStyle.Render("         - Toggle side-by-side diff with changed words highlighted"),
 		keyStyle.Render("a")+descStyle.Render("         - Show all changes in diff"),
+		keyStyle.Render("alt+v")+descStyle.Render("     - Show all changes against a branch, tag or commit (again to reset)"),
 		keyStyle.Render("d")+descStyle.Render("         - Show commit history"),
 		keyStyle.Render("←/→")+descStyle.Render("       - Navigate commits"),
+		keyStyle.Render("u")+descStyle.Render("         - Show staged and unstaged changes separately"),
+		keyStyle.Render("space/F")+descStyle.Render("   - Stage/unstage the hunk or file at the top of the staging view"),
+		keyStyle.Render("X/ctrl+x")+descStyle.Render("  - Discard the unstaged hunk or file at the top of the staging view"),
 		"",
 		headerStyle.Render("Other:"),
 		keyStyle.Render("?")+descStyle.Render("         - Show this help screen"),
-		keyStyle.Render("l")+descStyle.Render("         - View error log"),
-		keyStyle.Render("ctrl+h")+descStyle.Render("    - View pane history"),
+		keyStyle.Render("l")+descStyle.Render("         - View error log (e to export diagnostics)"),
+		keyStyle.Render("M")+descStyle.Render("         - Export metrics of all sessions as CSV and JSON"),
+		keyStyle.Render("/")+descStyle.Render("         - Filter the list by title, branch, tag or repo as you type (esc clears)"),
+		keyStyle.Render("ctrl+f")+descStyle.Render("    - Search all sessions' titles, branches, AI output and diffs"),
+		keyStyle.Render("alt+f")+descStyle.Render("     - Jump to the session that most recently finished (agent ready or terminal command exited)"),
+		keyStyle.Render("ctrl+h")+descStyle.Render("    - View pane history (tab: AI, terminal, combined; / search; e export)"),
+		keyStyle.Render("ctrl+z")+descStyle.Render("    - Undo the last kill, reset to origin or interactive rebase"),
 		keyStyle.Render("K")+descStyle.Render("         - Edit keyboard shortcuts"),
+		keyStyle.Render("alt+r")+descStyle.Render("     - Restore a storage backup"),
+		keyStyle.Render("alt+b")+descStyle.Render("     - Restore or delete a backup branch of the selected session"),
+		keyStyle.Render("alt+s")+descStyle.Render("     - Start or stop the selected session's dev servers"),
+		keyStyle.Render("alt+o")+descStyle.Render("     - Open the selected session's running dev server in the browser"),
+		keyStyle.Render("Z")+descStyle.Render("         - Browse archived sessions: inspect, restore or delete them"),
 		keyStyle.Render("q")+descStyle.Render("         - Quit the application"),
 		keyStyle.Render("mouse")+descStyle.Render("     - Use mouse wheel to scroll"),
 	)
@@ -102,20 +149,25 @@ func (h helpTypeInstanceStart) toContent() string {
 		headerStyle.Render("Managing:"),
 		keyStyle.Render("↵/o")+descStyle.Render("   - Attach to the session to interact with it directly"),
 		keyStyle.Render("tab")+descStyle.Render("   - Switch between AI, diff, and terminal tabs"),
-		keyStyle.Render("D")+descStyle.Render("     - Kill (delete) the selected session"),
+		keyStyle.Render("D")+descStyle.Render("     - Kill the selected session (delete, keep or archive branch, rate the run)"),
+		keyStyle.Render("C")+descStyle.Render("     - Save agent context summary as a checkpoint note"),
+		keyStyle.Render("v")+descStyle.Render("     - Show session details and checkpoint notes"),
 		keyStyle.Render("w")+descStyle.Render("     - Open in IDE"),
 		"",
 		headerStyle.Render("Git & Handoff:"),
 		keyStyle.Render("c")+descStyle.Render("     - Checkout this instance's branch"),
-		keyStyle.Render("p")+descStyle.Render("     - Commit and push branch to GitHub"),
-		keyStyle.Render("b")+descStyle.Render("     - Rebase with main branch"),
+		keyStyle.Render("p")+descStyle.Render("     - Commit and push branch to origin (optionally open a PR)"),
+		keyStyle.Render("b")+descStyle.Render("     - Rebase, merge or squash merge main"),
 		keyStyle.Render("h")+descStyle.Render("     - Git reset --hard to origin/branch"),
+		keyStyle.Render("P")+descStyle.Render("     - Export branch diff as patch or apply it to main checkout"),
+		keyStyle.Render("y")+descStyle.Render("     - Copy the branch, commit SHA or agent code blocks, or earlier copies again"),
+		keyStyle.Render("S")+descStyle.Render("     - Share diff as an HTML page link with QR code"),
 		keyStyle.Render("B")+descStyle.Render("     - Create bookmark commit"),
 		keyStyle.Render("g")+descStyle.Render("     - Show git status"),
 		"",
 		headerStyle.Render("Tools:"),
 		keyStyle.Render("t")+descStyle.Render("     - Run tests"),
-		keyStyle.Render("l")+descStyle.Render("     - View error log"),
+		keyStyle.Render("l")+descStyle.Render("     - View error log (e to export diagnostics)"),
 		keyStyle.Render("?")+descStyle.Render("     - Show help"),
 	)
 	return content
@@ -150,8 +202,8 @@ func (h helpTypeInstanceCheckout) toContent() string {
 		headerStyle.Render("Available Actions:"),
 		keyStyle.Render("c")+descStyle.Render(" - Checkout: commit changes and pause session"),
 		keyStyle.Render("r")+descStyle.Render(" - Resume a paused session"),
-		keyStyle.Render("p")+descStyle.Render(" - Commit and push branch to GitHub"),
-		keyStyle.Render("b")+descStyle.Render(" - Rebase with main branch"),
+		keyStyle.Render("p")+descStyle.Render(" - Commit and push branch to origin (optionally open a PR)"),
+		keyStyle.Render("b")+descStyle.Render(" - Rebase, merge or squash merge main"),
 		keyStyle.Render("h")+descStyle.Render(" - Git reset --hard to origin/branch"),
 		"",
 		dimStyle.Render("Note: The session is paused after checkout. Use 'r' to resume"),
@@ -0,0 +1,61 @@
+package app
+
+import (
+	"claude-squad/log"
+	"claude-squad/session"
+	"claude-squad/session/tmux"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+var (
+	historySourceStyles = map[string]lipgloss.Style{
+		"ai":       lipgloss.NewStyle().Foreground(lipgloss.Color("#7D56F4")).Bold(true),
+		"terminal": lipgloss.NewStyle().Foreground(lipgloss.Color("#36CFC9")).Bold(true),
+	}
+	historySourceLabels = map[string]string{
+		"ai":       "AI  │ ",
+		"terminal": "TERM│ ",
+	}
+)
+
+// historyContent returns captured history, or the capture error in its place.
+func historyContent(content string, err error) string {
+	if err != nil {
+		log.WarningLog.Printf("failed to capture history: %v", err)
+		return dimStyle.Render(fmt.Sprintf("History unavailable: %v", err))
+	}
+	return content
+}
+
+// combinedHistory renders the AI and terminal histories interleaved by time, with a gutter naming
+// each line's pane and a separator whenever the time changes.
+func combinedHistory(instance *session.Instance) (string, error) {
+	lines, err := instance.GetCombinedHistory()
+	if err != nil {
+		return "", err
+	}
+	return renderCombinedHistory(lines), nil
+}
+
+func renderCombinedHistory(lines []tmux.TimedLine) string {
+	var b strings.Builder
+	var last time.Time
+	for i, line := range lines {
+		at := line.At.Truncate(time.Second)
+		if i == 0 && at.IsZero() {
+			b.WriteString(dimStyle.Render("── earlier output, time unknown ──") + "\n")
+		} else if !at.IsZero() && !at.Equal(last) {
+			b.WriteString(dimStyle.Render(fmt.Sprintf("── %s ──", at.Format(time.TimeOnly))) + "\n")
+		}
+		last = at
+
+		b.WriteString(historySourceStyles[line.Source].Render(historySourceLabels[line.Source]))
+		// Reset so colors from a captured line don't bleed into the next gutter
+		b.WriteString(line.Text + "\x1b[0m\n")
+	}
+	return strings.TrimSuffix(b.String(), "\n")
+}
@@ -0,0 +1,35 @@
+package app
+
+import (
+	"claude-squad/session"
+	"fmt"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// historyExportedMsg is sent when exporting an instance's AI history finishes
+type historyExportedMsg struct {
+	path string
+	err  error
+}
+
+// exportHistory writes the instance's AI pane history to a markdown file in the background.
+func (m *home) exportHistory(instance *session.Instance) tea.Cmd {
+	dir, err := m.appConfig.GetHistoryExportDir()
+	if err != nil {
+		return m.handleError(err)
+	}
+	return func() tea.Msg {
+		path, err := instance.ExportAIHistory(dir, time.Now())
+		return historyExportedMsg{path: path, err: err}
+	}
+}
+
+// handleHistoryExported reports where the history was written.
+func (m *home) handleHistoryExported(msg historyExportedMsg) tea.Cmd {
+	if msg.err != nil {
+		return m.handleError(fmt.Errorf("failed to export AI history: %w", msg.err))
+	}
+	return m.showSuccess("Exported AI history to " + msg.path)
+}
@@ -0,0 +1,90 @@
+package app
+
+import (
+	"claude-squad/config"
+	"claude-squad/notify"
+	"claude-squad/session"
+	"claude-squad/ui"
+	"claude-squad/ui/overlay"
+	"fmt"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// showInstanceSettings opens the settings form of the selected instance.
+func (m *home) showInstanceSettings() tea.Cmd {
+	selected := m.list.GetSelectedInstance()
+	if selected == nil {
+		return nil
+	}
+	m.settingsInstance = selected
+	m.instanceSettingsOverlay = overlay.NewInstanceSettingsOverlay(fmt.Sprintf("Settings for '%s'", selected.Title),
+		overlay.InstanceSettings{
+			AutoYes:       selected.AutoYes,
+			Notifications: !selected.MuteNotifications,
+			TimeBudget:    selected.TimeBudget,
+			Tags:          selected.Tags,
+		}, selected.WorkTime)
+	if m.policy.Enforces("auto_yes") {
+		m.instanceSettingsOverlay.LockAutoYes()
+	}
+	m.state = stateInstanceSettings
+	return tea.WindowSize()
+}
+
+// handleInstanceSettingsState passes key presses to the settings form and, once it is saved,
+// applies the settings to the instance and saves them right away.
+func (m *home) handleInstanceSettingsState(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if m.instanceSettingsOverlay == nil || m.settingsInstance == nil {
+		m.state = stateDefault
+		return m, nil
+	}
+	if !m.instanceSettingsOverlay.HandleKeyPress(msg) {
+		return m, nil
+	}
+	form, instance := m.instanceSettingsOverlay, m.settingsInstance
+	m.instanceSettingsOverlay = nil
+	m.settingsInstance = nil
+	m.state = stateDefault
+	m.menu.SetState(ui.StateDefault)
+	if !form.IsSubmitted() {
+		return m, tea.WindowSize()
+	}
+
+	if err := applyInstanceSettings(instance, form.Settings(), m.policy); err != nil {
+		return m, tea.Batch(tea.WindowSize(), m.handleError(err))
+	}
+	if err := m.storage.SaveInstances(m.list.GetInstances()); err != nil {
+		return m, m.handleError(err)
+	}
+	return m, tea.Batch(tea.WindowSize(), m.instanceChanged(),
+		m.notify(ui.ToastSuccess, fmt.Sprintf("Saved settings for '%s'", instance.Title)))
+}
+
+// applyInstanceSettings updates the instance with the settings from the form, unless they turn on
+// auto-yes the policy turns off.
+func applyInstanceSettings(instance *session.Instance, settings overlay.InstanceSettings, policy *config.Policy) error {
+	if settings.AutoYes != instance.AutoYes {
+		if err := policy.CheckAutoYes(settings.AutoYes); err != nil {
+			return err
+		}
+	}
+	instance.AutoYes = settings.AutoYes
+	instance.MuteNotifications = !settings.Notifications
+	instance.TimeBudget = settings.TimeBudget
+	instance.Tags = settings.Tags
+	return nil
+}
+
+// recordWorkTime adds to the time the instance's agent has worked and warns once it runs past
+// its time budget.
+func (m *home) recordWorkTime(instance *session.Instance) tea.Cmd {
+	if !instance.RecordWorkTime(time.Now()) {
+		return nil
+	}
+	message := fmt.Sprintf("'%s' has worked %s, past its %s budget",
+		instance.Title, instance.WorkTime.Round(time.Second), instance.TimeBudget)
+	return tea.Batch(m.notify(ui.ToastWarning, message),
+		m.sendInstanceEvent(notify.EventBudgetExceeded, instance, message))
+}
@@ -0,0 +1,45 @@
+package app
+
+import (
+	"claude-squad/log"
+	"claude-squad/session"
+	"claude-squad/ui"
+)
+
+// instanceTab returns the tab to show when the instance is selected: the one it was left on or
+// created with, else the configured default. ok is false to keep the tab shown.
+func (m *home) instanceTab(instance *session.Instance) (tab int, ok bool) {
+	name := instance.Tab
+	if name == "" && m.appConfig != nil {
+		name = m.appConfig.DefaultTab
+	}
+	if name == "" {
+		return 0, false
+	}
+	if tab, ok = ui.TabByName(name); !ok {
+		log.WarningLog.Printf("unknown tab %q for %s, keeping the tab shown", name, instance.Title)
+	}
+	return tab, ok
+}
+
+// showInstanceTab switches to the tab of the selected instance when another one is selected.
+func (m *home) showInstanceTab(selected *session.Instance) {
+	if selected == m.tabInstance {
+		return
+	}
+	m.tabInstance = selected
+	if selected == nil {
+		return
+	}
+	if tab, ok := m.instanceTab(selected); ok {
+		m.tabbedWindow.SetTab(tab)
+		m.menu.SetInDiffTab(m.tabbedWindow.IsInDiffTab())
+	}
+}
+
+// rememberTab records the tab the user switched to as the selected instance's tab.
+func (m *home) rememberTab() {
+	if selected := m.list.GetSelectedInstance(); selected != nil {
+		selected.Tab = ui.TabName(m.tabbedWindow.ActiveTab())
+	}
+}
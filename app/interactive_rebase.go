@@ -0,0 +1,111 @@
+package app
+
+import (
+	"claude-squad/session"
+	"claude-squad/session/git"
+	"claude-squad/ui"
+	"claude-squad/ui/overlay"
+	"context"
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// rebaseStepsMsg carries the commits of an instance's branch to rewrite interactively.
+type rebaseStepsMsg struct {
+	instance *session.Instance
+	steps    []git.RebaseStep
+}
+
+// commitsRewrittenMsg reports that an instance's commits were rewritten, with the state of its
+// worktree before, to undo it.
+type commitsRewrittenMsg struct {
+	instance *session.Instance
+	state    git.WorktreeState
+	stateErr error
+}
+
+// loadRebaseSteps lists the commits of the selected instance's branch to rewrite.
+func (m *home) loadRebaseSteps() tea.Cmd {
+	selected := m.list.GetSelectedInstance()
+	if selected == nil {
+		return nil
+	}
+	if !selected.Started() || selected.Paused() {
+		return m.handleError(fmt.Errorf("resume '%s' to rewrite its commits", selected.Title))
+	}
+	worktree, err := selected.GetGitWorktree()
+	if err != nil {
+		return m.handleError(err)
+	}
+	return func() tea.Msg {
+		steps, err := worktree.RebaseSteps()
+		if err != nil {
+			return fmt.Errorf("failed to list the commits of '%s': %w", selected.Title, err)
+		}
+		return rebaseStepsMsg{instance: selected, steps: steps}
+	}
+}
+
+// showInteractiveRebase opens the commits of an instance's branch to reorder, reword, squash
+// and drop.
+func (m *home) showInteractiveRebase(msg rebaseStepsMsg) tea.Cmd {
+	if len(msg.steps) == 0 {
+		return m.notify(ui.ToastInfo, fmt.Sprintf("'%s' has no commits of its own to rewrite", msg.instance.Title))
+	}
+	m.interactiveRebaseOverlay = overlay.NewInteractiveRebaseOverlay(
+		fmt.Sprintf("Rewrite the commits of '%s'", msg.instance.Title), msg.steps)
+	m.interactiveRebaseInstance = msg.instance
+	m.state = stateInteractiveRebase
+	return tea.WindowSize()
+}
+
+// handleInteractiveRebaseState passes key presses to the interactive rebase overlay and rewrites
+// the branch once it's submitted with changes.
+func (m *home) handleInteractiveRebaseState(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if m.interactiveRebaseOverlay == nil {
+		m.state = stateDefault
+		return m, nil
+	}
+	if !m.interactiveRebaseOverlay.HandleKeyPress(msg) {
+		return m, nil
+	}
+	rebase, instance := m.interactiveRebaseOverlay, m.interactiveRebaseInstance
+	m.interactiveRebaseOverlay, m.interactiveRebaseInstance = nil, nil
+	m.state = stateDefault
+	if !rebase.Submitted() || !rebase.Changed() {
+		return m, nil
+	}
+
+	worktree, err := instance.GetGitWorktree()
+	if err != nil {
+		return m, m.handleError(err)
+	}
+	steps := rebase.Steps()
+	// The rebase can't be stopped halfway, so the operation isn't cancellable
+	op, ctx := m.startOperation("rewrite", instance.Title, false)
+	return m, op.run(ctx, func(context.Context) tea.Msg {
+		// Record the branch and uncommitted changes before, to undo the rewrite
+		state, stateErr := worktree.SaveState()
+		if err := worktree.RewriteCommits(steps); err != nil {
+			return fmt.Errorf("failed to rewrite the commits of '%s': %w", instance.Title, err)
+		}
+		return commitsRewrittenMsg{instance: instance, state: state, stateErr: stateErr}
+	})
+}
+
+// handleCommitsRewritten reports rewritten commits, recording how to undo it.
+func (m *home) handleCommitsRewritten(msg commitsRewrittenMsg) tea.Cmd {
+	message := fmt.Sprintf("Rewrote the commits of '%s'", msg.instance.Title)
+	if msg.stateErr != nil {
+		m.appendErrorLog(fmt.Sprintf("the rewrite of '%s' can't be undone: %v", msg.instance.Title, msg.stateErr))
+	} else {
+		m.pushUndo(undoEntry{
+			description: fmt.Sprintf("rewrite of the commits of '%s'", msg.instance.Title),
+			reset:       msg.instance,
+			resetState:  msg.state,
+		})
+		message += fmt.Sprintf("; %s undoes it", undoKey())
+	}
+	return tea.Batch(m.instanceChanged(), m.showSuccess(message))
+}
@@ -0,0 +1,127 @@
+package app
+
+import (
+	"claude-squad/config"
+	"claude-squad/log"
+	"claude-squad/notify"
+	"claude-squad/session"
+	"claude-squad/session/git"
+	"claude-squad/ui"
+	"fmt"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// mainWatchTickMsg triggers a background fetch of origin's main branch
+type mainWatchTickMsg struct{}
+
+// mainWatchResultMsg reports the instances rebased onto main after a fetch, and those whose
+// rebase failed
+type mainWatchResultMsg struct {
+	rebased []*session.Instance
+	failed  map[*session.Instance]autoRebaseFailure
+}
+
+// autoRebaseFailure is an automatic rebase that failed while the branch was behind main by
+// behind commits
+type autoRebaseFailure struct {
+	behind int
+	err    error
+}
+
+// scheduleMainWatch waits for the configured interval, then triggers a fetch of origin's main
+// branch. A zero interval disables it.
+func (m *home) scheduleMainWatch() tea.Cmd {
+	if m.appConfig.MainWatchMinutes <= 0 {
+		return nil
+	}
+	interval := time.Duration(m.appConfig.MainWatchMinutes) * time.Minute
+	return tea.Tick(interval, func(time.Time) tea.Msg {
+		return mainWatchTickMsg{}
+	})
+}
+
+// watchMain fetches origin's main branch once per repository in the background and counts how
+// far behind it each instance's branch is. With the rebase policy, the branches behind it whose
+// agent is waiting for input are rebased onto it if their worktree is clean. A branch whose
+// rebase failed isn't tried again until main moves on.
+func (m *home) watchMain() tea.Cmd {
+	rebase := m.appConfig.MainWatchPolicy == config.MainWatchRebase
+	type target struct {
+		instance *session.Instance
+		idle     bool
+		failedAt int
+	}
+	var targets []target
+	for _, instance := range m.list.GetInstances() {
+		if !instance.WatchesMain() {
+			continue
+		}
+		failedAt := -1
+		if failure, ok := m.autoRebaseFailures[instance]; ok {
+			failedAt = failure.behind
+		}
+		targets = append(targets, target{instance: instance, idle: instance.Status == session.Ready, failedAt: failedAt})
+	}
+
+	return func() tea.Msg {
+		result := mainWatchResultMsg{failed: make(map[*session.Instance]autoRebaseFailure)}
+		fetched := make(map[string]bool)
+		for _, t := range targets {
+			worktree, err := t.instance.GetGitWorktree()
+			if err != nil {
+				continue
+			}
+			repo := worktree.GetRepoPath()
+			if _, ok := fetched[repo]; !ok {
+				fetched[repo] = true
+				if err := t.instance.FetchMain(); err != nil {
+					log.WarningLog.Printf("could not fetch main for %s: %v", repo, err)
+					fetched[repo] = false
+				}
+			}
+			if !fetched[repo] {
+				continue
+			}
+
+			behind, err := t.instance.UpdateBehindMain()
+			if err != nil {
+				log.WarningLog.Printf("could not compare %s with main: %v", t.instance.Title, err)
+				continue
+			}
+			if !rebase || behind == 0 || !t.idle || behind == t.failedAt {
+				continue
+			}
+			if err := t.instance.AutoRebaseWithMain(); err != nil {
+				result.failed[t.instance] = autoRebaseFailure{behind: behind, err: err}
+				continue
+			}
+			result.rebased = append(result.rebased, t.instance)
+		}
+		return result
+	}
+}
+
+// handleMainWatchResult reports the automatic rebases and schedules the next fetch.
+func (m *home) handleMainWatchResult(msg mainWatchResultMsg) tea.Cmd {
+	cmds := []tea.Cmd{m.scheduleMainWatch()}
+	if m.autoRebaseFailures == nil {
+		m.autoRebaseFailures = make(map[*session.Instance]autoRebaseFailure)
+	}
+	for instance, failure := range msg.failed {
+		m.autoRebaseFailures[instance] = failure
+		cmds = append(cmds, m.notify(ui.ToastWarning, fmt.Sprintf("Could not rebase '%s' onto main automatically: %v",
+			instance.Title, failure.err)))
+	}
+	for _, instance := range msg.rebased {
+		delete(m.autoRebaseFailures, instance)
+		summary := mergeSummary(git.MergeStrategyRebase, instance.Branch)
+		cmds = append(cmds, m.showSuccess(summary+" automatically"),
+			m.sendInstanceEvent(notify.EventRebaseComplete, instance, summary))
+	}
+	if len(msg.rebased) > 0 {
+		cmds = append(cmds, m.instanceChanged())
+	}
+	return tea.Batch(cmds...)
+}
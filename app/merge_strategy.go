@@ -0,0 +1,54 @@
+package app
+
+import (
+	"claude-squad/log"
+	"claude-squad/session/git"
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// mergeStrategyChoice describes how a strategy is offered in the update confirmation.
+var mergeStrategyChoice = map[git.MergeStrategy]struct{ key, label string }{
+	git.MergeStrategyRebase: {"r", "rebase onto main"},
+	git.MergeStrategyMerge:  {"m", "merge main into branch"},
+	git.MergeStrategySquash: {"s", "squash branch onto main"},
+}
+
+// mergeStrategyChoices returns the choices for updating a branch with main, with the configured
+// strategy first.
+func (m *home) mergeStrategyChoices() []confirmChoice {
+	preferred, err := git.ParseMergeStrategy(m.appConfig.MergeStrategy)
+	if err != nil {
+		log.WarningLog.Printf("ignoring merge_strategy: %v", err)
+		preferred = git.MergeStrategyRebase
+	}
+
+	strategies := []git.MergeStrategy{preferred}
+	for _, strategy := range git.MergeStrategies {
+		if strategy != preferred {
+			strategies = append(strategies, strategy)
+		}
+	}
+
+	choices := make([]confirmChoice, 0, len(strategies))
+	for _, strategy := range strategies {
+		strategy := strategy
+		choice := mergeStrategyChoice[strategy]
+		choices = append(choices, confirmChoice{key: choice.key, label: choice.label, action: func() tea.Msg {
+			return startRebaseMsg{strategy: strategy}
+		}})
+	}
+	return choices
+}
+
+// mergeSummary describes a completed update of the branch with main.
+func mergeSummary(strategy git.MergeStrategy, branch string) string {
+	switch strategy {
+	case git.MergeStrategyMerge:
+		return fmt.Sprintf("Merged main into %s", branch)
+	case git.MergeStrategySquash:
+		return fmt.Sprintf("Squashed %s onto main", branch)
+	}
+	return fmt.Sprintf("Rebased %s onto main", branch)
+}
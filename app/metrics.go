@@ -0,0 +1,38 @@
+package app
+
+import (
+	"claude-squad/session"
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// metricsExportedMsg is sent when exporting the instances' metrics finishes
+type metricsExportedMsg struct {
+	path      string
+	instances int
+	err       error
+}
+
+// exportMetrics writes the metrics of all instances as CSV and JSON in the background.
+func (m *home) exportMetrics() tea.Cmd {
+	instances := m.list.GetInstances()
+	if len(instances) == 0 {
+		return m.handleError(fmt.Errorf("no sessions to export metrics for"))
+	}
+	return func() tea.Msg {
+		path, err := session.ExportMetrics(instances, time.Now())
+		return metricsExportedMsg{path: path, instances: len(instances), err: err}
+	}
+}
+
+// handleMetricsExported reports where the metrics were written.
+func (m *home) handleMetricsExported(msg metricsExportedMsg) tea.Cmd {
+	if msg.err != nil {
+		return m.handleError(msg.err)
+	}
+	return m.showSuccess(fmt.Sprintf("Exported metrics for %d session(s) to %s (.csv and .json)",
+		msg.instances, strings.TrimSuffix(msg.path, ".csv")))
+}
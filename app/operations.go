@@ -0,0 +1,193 @@
+package app
+
+import (
+	"claude-squad/session/git"
+	"claude-squad/ui"
+	"claude-squad/ui/overlay"
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// operationIcons mark the kinds of operations in the menu's pending operations indicator.
+var operationIcons = map[string]string{
+	"rebase":    "⟳",
+	"push":      "⇪",
+	"start":     "▶",
+	"kill":      "✕",
+	"delete":    "✕",
+	"broadcast": "»",
+}
+
+// operation is a background command the user started and is waiting on.
+type operation struct {
+	id    int
+	kind  string
+	title string
+	start time.Time
+	// progress is the last progress the operation reported, if any
+	progress string
+	// cancel stops the operation. Nil if it can't be stopped.
+	cancel    context.CancelFunc
+	cancelled bool
+}
+
+// label names the operation, e.g. "⟳ rebase my-task".
+func (op *operation) label() string {
+	icon, ok := operationIcons[op.kind]
+	if !ok {
+		icon = "•"
+	}
+	return fmt.Sprintf("%s %s %s", icon, op.kind, op.title)
+}
+
+// operations is the registry of the operations in flight, oldest first. It is only used on the
+// UI goroutine; the operations report back with messages.
+type operations struct {
+	nextID  int
+	pending []*operation
+}
+
+// add registers an operation and returns it.
+func (o *operations) add(kind, title string, cancel context.CancelFunc) *operation {
+	o.nextID++
+	op := &operation{id: o.nextID, kind: kind, title: title, start: time.Now(), cancel: cancel}
+	o.pending = append(o.pending, op)
+	return op
+}
+
+// get returns the operation with id, or nil once it finished.
+func (o *operations) get(id int) *operation {
+	for _, op := range o.pending {
+		if op.id == id {
+			return op
+		}
+	}
+	return nil
+}
+
+// remove forgets a finished operation.
+func (o *operations) remove(id int) {
+	for n, op := range o.pending {
+		if op.id == id {
+			o.pending = append(o.pending[:n], o.pending[n+1:]...)
+			return
+		}
+	}
+}
+
+// summary lists the pending operations for the menu, e.g. "⟳ rebase my-task, ⇪ push other-task",
+// naming at most max of them.
+func (o *operations) summary(max int) string {
+	if len(o.pending) == 0 {
+		return ""
+	}
+	labels := make([]string, 0, max)
+	for _, op := range o.pending {
+		if len(labels) == max {
+			labels = append(labels, fmt.Sprintf("+%d more", len(o.pending)-max))
+			break
+		}
+		labels = append(labels, op.label())
+	}
+	return strings.Join(labels, ", ")
+}
+
+// operationDoneMsg reports that an operation finished with result, which is then handled like
+// any other message.
+type operationDoneMsg struct {
+	id     int
+	result tea.Msg
+}
+
+// maxMenuOperations is how many pending operations the menu names before summing up the rest.
+const maxMenuOperations = 3
+
+// trackOperation runs run in the background as an operation shown in the menu until it finishes.
+// run gets a context that is cancelled when the user cancels the operation, if cancellable.
+func (m *home) trackOperation(kind, title string, cancellable bool, run func(ctx context.Context) tea.Msg) tea.Cmd {
+	op, ctx := m.startOperation(kind, title, cancellable)
+	return op.run(ctx, run)
+}
+
+// startOperation registers an operation that is about to run and returns it with the context
+// to run it in.
+func (m *home) startOperation(kind, title string, cancellable bool) (*operation, context.Context) {
+	ctx, cancel := context.WithCancel(m.ctx)
+	op := m.operations.add(kind, title, cancel)
+	if !cancellable {
+		op.cancel = nil
+	}
+	m.menu.SetPendingOperations(m.operations.summary(maxMenuOperations))
+	return op, ctx
+}
+
+// run returns the command running the operation with ctx and reporting when it finished.
+func (op *operation) run(ctx context.Context, run func(ctx context.Context) tea.Msg) tea.Cmd {
+	id := op.id
+	return func() tea.Msg {
+		result := run(ctx)
+		return operationDoneMsg{id: id, result: result}
+	}
+}
+
+// finishOperation removes a finished operation and passes on its result.
+func (m *home) finishOperation(msg operationDoneMsg) tea.Cmd {
+	op := m.operations.get(msg.id)
+	m.operations.remove(msg.id)
+	m.menu.SetPendingOperations(m.operations.summary(maxMenuOperations))
+	if op != nil && op.cancelled {
+		if err, ok := msg.result.(error); ok {
+			return m.notify(ui.ToastInfo, fmt.Sprintf("Cancelled %s %s: %v", op.kind, op.title, err))
+		}
+	}
+	if msg.result == nil {
+		return nil
+	}
+	return func() tea.Msg { return msg.result }
+}
+
+// gitProgressText describes a git progress update, e.g. "fetch: Receiving objects 45%".
+func gitProgressText(p git.GitProgress) string {
+	return fmt.Sprintf("%s: %s %d%%", p.Operation, p.Phase, p.Percent)
+}
+
+// showOperations lists the operations in flight with how long they have run and their progress,
+// to cancel one that can be.
+func (m *home) showOperations() tea.Cmd {
+	if len(m.operations.pending) == 0 {
+		return m.notify(ui.ToastInfo, "No operations in progress")
+	}
+	pending := append([]*operation(nil), m.operations.pending...)
+	items := make([]overlay.ListItem, len(pending))
+	for n, op := range pending {
+		details := []string{"running " + time.Since(op.start).Round(time.Second).String()}
+		if op.progress != "" {
+			details = append(details, op.progress)
+		}
+		switch {
+		case op.cancelled:
+			details = append(details, "cancelling")
+		case op.cancel == nil:
+			details = append(details, "can't be cancelled")
+		}
+		items[n] = overlay.ListItem{Title: op.label(), Description: strings.Join(details, " • ")}
+	}
+	return m.selectFromList("Operations in Progress", items, func(idx int) tea.Cmd {
+		op := pending[idx]
+		if m.operations.get(op.id) == nil {
+			return m.notify(ui.ToastInfo, fmt.Sprintf("%s %s already finished", op.kind, op.title))
+		}
+		if op.cancel == nil || op.cancelled {
+			return nil
+		}
+		return m.confirmAction(fmt.Sprintf("[!] Cancel %s of '%s'?", op.kind, op.title), func() tea.Msg {
+			op.cancelled = true
+			op.cancel()
+			return nil
+		})
+	})
+}
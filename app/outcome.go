@@ -0,0 +1,84 @@
+package app
+
+import (
+	"claude-squad/log"
+	"claude-squad/session"
+	"claude-squad/ui"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// pendingKill is a kill waiting for the outcome rating of its instance.
+type pendingKill struct {
+	instance *session.Instance
+	run      func(outcome *session.Outcome) tea.Msg
+}
+
+// outcomePromptMsg asks the model to show the outcome rating form before running a kill
+type outcomePromptMsg struct {
+	kill *pendingKill
+}
+
+// killWithOutcome wraps a kill action so the user is asked to rate the run first, unless the
+// prompt is disabled in the config.
+func (m *home) killWithOutcome(instance *session.Instance, run func(outcome *session.Outcome) tea.Msg) tea.Cmd {
+	if m.appConfig.SkipOutcomePrompt {
+		return func() tea.Msg {
+			return run(nil)
+		}
+	}
+	return func() tea.Msg {
+		return outcomePromptMsg{kill: &pendingKill{instance: instance, run: run}}
+	}
+}
+
+// handleOutcomeState handles key events for the outcome rating form. Skipping the form still
+// runs the kill, just without recording an outcome.
+func (m *home) handleOutcomeState(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if !m.outcomeOverlay.HandleKeyPress(msg) {
+		return m, nil
+	}
+
+	var outcome *session.Outcome
+	if m.outcomeOverlay.IsSubmitted() {
+		outcome = &session.Outcome{
+			Result: m.outcomeOverlay.Result(),
+			Effort: m.outcomeOverlay.Effort(),
+			Notes:  m.outcomeOverlay.Notes(),
+		}
+	}
+	kill := m.pendingKill
+	m.outcomeOverlay = nil
+	m.pendingKill = nil
+	m.state = stateDefault
+	m.menu.SetState(ui.StateDefault)
+
+	if kill == nil {
+		return m, tea.WindowSize()
+	}
+	return m, tea.Batch(tea.WindowSize(), func() tea.Msg {
+		return kill.run(outcome)
+	})
+}
+
+// outcomeRecord captures the instance's metadata for the outcome while it is still running.
+// Returns nil if the rating was skipped.
+func (m *home) outcomeRecord(instance *session.Instance, disposition string, outcome *session.Outcome) *session.OutcomeRecord {
+	if outcome == nil {
+		return nil
+	}
+	record := session.NewOutcomeRecord(instance, disposition, *outcome)
+	return &record
+}
+
+// saveOutcome records the rating of a killed instance. Failures are logged rather than
+// surfaced, since the kill itself succeeded.
+func (m *home) saveOutcome(record *session.OutcomeRecord, archivedBranch string) {
+	if record == nil {
+		return
+	}
+	record.ArchivedBranch = archivedBranch
+	if err := session.SaveOutcome(*record); err != nil {
+		log.ErrorLog.Printf("failed to save outcome for %s: %v", record.Title, err)
+	}
+}
@@ -0,0 +1,36 @@
+package app
+
+import (
+	"claude-squad/ui"
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// instanceLimit returns how many instances can exist at once under the policy.
+func (m *home) instanceLimit() int {
+	return m.policy.InstanceLimit(GlobalInstanceLimit)
+}
+
+// checkNewInstance returns an error if an instance running program can't be created, because of
+// the instance limit or the policy.
+func (m *home) checkNewInstance(program string) error {
+	if limit := m.instanceLimit(); m.list.NumInstances() >= limit {
+		return fmt.Errorf("you can't create more than %d instances", limit)
+	}
+	return m.policy.CheckProgram(program)
+}
+
+// reportPolicyViolations warns about the ways the config and existing instances break the policy.
+func (m *home) reportPolicyViolations() tea.Cmd {
+	programs := make(map[string]string)
+	for _, instance := range m.list.GetInstances() {
+		programs[instance.Title] = instance.Program
+	}
+	violations := m.policy.Violations(m.appConfig, programs)
+	if len(violations) == 0 {
+		return nil
+	}
+	return m.notify(ui.ToastWarning, fmt.Sprintf("Policy %s: %s", m.policy.Source, strings.Join(violations, "; ")))
+}
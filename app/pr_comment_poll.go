@@ -0,0 +1,79 @@
+package app
+
+import (
+	"claude-squad/keys"
+	"claude-squad/log"
+	"claude-squad/ui"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// prCommentPollTickMsg triggers a background fetch of the comments on open pull requests
+type prCommentPollTickMsg struct{}
+
+// prCommentsArrivedMsg reports how many new unresolved PR comments arrived, by instance title
+type prCommentsArrivedMsg struct {
+	arrived map[string]int
+}
+
+// schedulePRCommentPoll waits for the configured poll interval, then triggers a fetch of the
+// comments on open pull requests. A negative interval disables polling.
+func (m *home) schedulePRCommentPoll() tea.Cmd {
+	if m.appConfig.PRCommentPollMinutes < 0 {
+		return nil
+	}
+	interval := time.Duration(m.appConfig.PRCommentPollMinutes) * time.Minute
+	return tea.Tick(interval, func(time.Time) tea.Msg {
+		return prCommentPollTickMsg{}
+	})
+}
+
+// pollPRComments fetches the comments on the open pull requests of all instances in the
+// background, updating their new comment badges.
+func (m *home) pollPRComments() tea.Cmd {
+	instances := m.list.GetInstances()
+	return func() tea.Msg {
+		arrived := make(map[string]int)
+		for _, instance := range instances {
+			count, err := instance.UpdatePRComments()
+			if err != nil {
+				log.WarningLog.Printf("could not fetch PR comments for %s: %v", instance.Title, err)
+				continue
+			}
+			if count > 0 {
+				arrived[instance.Title] = count
+			}
+		}
+		return prCommentsArrivedMsg{arrived: arrived}
+	}
+}
+
+// handlePRCommentsArrived shows a toast for the instances that got new PR comments and
+// schedules the next poll.
+func (m *home) handlePRCommentsArrived(msg prCommentsArrivedMsg) tea.Cmd {
+	if len(msg.arrived) == 0 {
+		return m.schedulePRCommentPoll()
+	}
+	titles := make([]string, 0, len(msg.arrived))
+	for title := range msg.arrived {
+		titles = append(titles, title)
+	}
+	sort.Strings(titles)
+
+	var message string
+	if len(titles) == 1 {
+		message = fmt.Sprintf("%d new PR comment(s) on '%s'; %s reviews them", msg.arrived[titles[0]], titles[0],
+			keys.GlobalkeyBindings[keys.KeyPRReview].Help().Key)
+	} else {
+		counts := make([]string, len(titles))
+		for n, title := range titles {
+			counts[n] = fmt.Sprintf("'%s' (%d)", title, msg.arrived[title])
+		}
+		message = "New PR comments on " + strings.Join(counts, ", ")
+	}
+	return tea.Batch(m.notify(ui.ToastInfo, message), m.schedulePRCommentPoll())
+}
@@ -5,6 +5,7 @@ import (
 	"claude-squad/session"
 	"claude-squad/session/git"
 	"claude-squad/ui/overlay"
+	"errors"
 	"fmt"
 	"strings"
 	"time"
@@ -24,9 +25,84 @@ type commentProcessedMsg struct {
 	err     error
 }
 
-type allCommentsProcessedMsg struct{}
+// allCommentsProcessedMsg reports whether the fix prompt was sent to the instance's agent.
+// threads are the review threads to resolve once the agent has finished, if that was asked for.
+type allCommentsProcessedMsg struct {
+	err          error
+	instance     string
+	pr           *git.PullRequest
+	worktreePath string
+	threads      []string
+}
+
+// pendingThreadResolve holds the review threads waiting for an instance's agent to finish the
+// fix prompt before they are resolved.
+type pendingThreadResolve struct {
+	pr           *git.PullRequest
+	worktreePath string
+	threads      []string
+	sentAt       time.Time
+	// working is set once the agent has been seen running after the prompt was sent
+	working bool
+}
+
+// threadsResolvedMsg reports the review threads resolved after an agent finished its fix prompt.
+type threadsResolvedMsg struct {
+	instance string
+	resolved int
+	total    int
+	err      error
+}
+
+// reviewThreads returns the IDs of the unresolved review threads the comments start.
+func reviewThreads(comments []*git.PRComment) []string {
+	var threads []string
+	for _, comment := range comments {
+		if comment.ThreadID != "" && !comment.IsResolved {
+			threads = append(threads, comment.ThreadID)
+		}
+	}
+	return threads
+}
+
+// reviewAcceptedComments sends the accepted comments to the agent, first asking whether their
+// review threads should be resolved once it is done if any of them have one.
+func (m *home) reviewAcceptedComments(pr *git.PullRequest, comments []*git.PRComment) tea.Cmd {
+	threads := reviewThreads(comments)
+	if len(threads) == 0 || pr == nil {
+		return m.processAcceptedComments(pr, comments, false)
+	}
+	message := fmt.Sprintf("Send %d accepted comments to the agent?\n\n%d of them have review threads that can be resolved once the agent has finished.",
+		len(comments), len(threads))
+	return m.confirmChoices(message, []confirmChoice{
+		{key: "s", label: "Send", action: m.processAcceptedComments(pr, comments, false)},
+		{key: "r", label: "Send and resolve threads when done", action: m.processAcceptedComments(pr, comments, true)},
+	})
+}
+
+// processAcceptedComments returns a command that shows the comments being processed and sends
+// them to the selected instance's agent as one prompt. With resolve their review threads are
+// resolved when the agent has finished.
+func (m *home) processAcceptedComments(pr *git.PullRequest, comments []*git.PRComment, resolve bool) tea.Cmd {
+	return func() tea.Msg {
+		return processCommentsMsg{pr: pr, comments: comments, resolve: resolve}
+	}
+}
+
+// processCommentsMsg starts sending accepted comments to the selected instance's agent.
+type processCommentsMsg struct {
+	pr       *git.PullRequest
+	comments []*git.PRComment
+	resolve  bool
+}
 
-func (m *home) processAcceptedComments(comments []*git.PRComment) tea.Cmd {
+func (m *home) handleProcessComments(msg processCommentsMsg) tea.Cmd {
+	selected := m.list.GetSelectedInstance()
+	if selected == nil {
+		return m.handleError(fmt.Errorf("no instance selected"))
+	}
+
+	comments := msg.comments
 	// First show the processing overlay
 	progressText := fmt.Sprintf("Processing %d PR comments...\n\n", len(comments))
 	for i, comment := range comments {
@@ -39,62 +115,78 @@ func (m *home) processAcceptedComments(comments []*git.PRComment) tea.Cmd {
 		}
 		progressText += "\n"
 	}
-	progressText += "\nSending to Claude for processing..."
+	progressText += "\nSending them to the agent as one prompt..."
 
 	m.textOverlay = overlay.NewTextOverlay(progressText)
 	m.state = stateHelp
+	m.rememberSentComments(selected, comments)
 
+	prompt := localizePrompt(selected, m.formatFixPrompt(comments))
+	var threads []string
+	if msg.resolve {
+		threads = reviewThreads(comments)
+	}
 	// No need to switch tabs - SendPromptToAI sends directly to AI pane
-
-	// Return a command that processes comments
-	return m.processCommentsSequentially(comments)
-}
-
-func (m *home) processCommentsSequentially(comments []*git.PRComment) tea.Cmd {
 	return func() tea.Msg {
-		selected := m.list.GetSelectedInstance()
-		if selected == nil {
-			return fmt.Errorf("no instance selected")
-		}
-
 		// Check if instance is ready
 		if selected.Status != session.Ready && selected.Status != session.Running {
-			return fmt.Errorf("instance is not ready to receive prompts (status: %v)", selected.Status)
+			return allCommentsProcessedMsg{err: fmt.Errorf("instance is not ready to receive prompts (status: %v)", selected.Status)}
+		}
+		if prompt == "" {
+			return allCommentsProcessedMsg{err: fmt.Errorf("no accepted comment pieces to send")}
+		}
+		worktree, err := selected.GetGitWorktree()
+		if err != nil {
+			return allCommentsProcessedMsg{err: fmt.Errorf("failed to get git worktree: %w", err)}
 		}
 
-		// Process each comment
-		for i, comment := range comments {
-			prompt := m.formatCommentAsPrompt(comment, i+1, len(comments))
-
-			// Skip empty prompts (e.g., split comments with no accepted pieces)
-			if prompt == "" {
-				log.InfoLog.Printf("Skipping comment %d (empty prompt)", i+1)
-				continue
-			}
-
-			// Debug: log the prompt being sent
-			log.InfoLog.Printf("Sending PR comment %d/%d to Claude", i+1, len(comments))
-			promptPreview := prompt
-			if len(promptPreview) > 100 {
-				promptPreview = promptPreview[:100] + "..."
-			}
-			log.InfoLog.Printf("Prompt preview: %s", promptPreview)
-
-			// Send the comment to Claude
-			if err := selected.SendPromptToAI(prompt); err != nil {
-				log.ErrorLog.Printf("Failed to send comment %d to Claude: %v", i+1, err)
-				return fmt.Errorf("failed to send comment %d to Claude: %w", i+1, err)
-			}
+		log.InfoLog.Printf("Sending %d PR comments to %s as one prompt", len(comments), selected.Title)
+		if err := selected.SendPromptToAI(prompt); err != nil {
+			return allCommentsProcessedMsg{err: fmt.Errorf("failed to send PR comments to the agent: %w", err)}
+		}
+		return allCommentsProcessedMsg{
+			instance:     selected.Title,
+			pr:           msg.pr,
+			worktreePath: worktree.GetWorktreePath(),
+			threads:      threads,
+		}
+	}
+}
 
-			log.InfoLog.Printf("Successfully sent comment %d to Claude", i+1)
+// resolveThreadsWhenDone resolves the review threads waiting on the instance once its agent has
+// worked on the fix prompt and settled into Ready.
+func (m *home) resolveThreadsWhenDone(instance *session.Instance) tea.Cmd {
+	pending, ok := m.pendingResolves[instance.Title]
+	if !ok {
+		return nil
+	}
+	switch instance.Status {
+	case session.Running:
+		pending.working = true
+		return nil
+	case session.Ready:
+		if !pending.working || instance.ReadySince().Before(pending.sentAt) ||
+			time.Since(instance.ReadySince()) < session.QueueDispatchIdle {
+			return nil
+		}
+	default:
+		return nil
+	}
+	delete(m.pendingResolves, instance.Title)
 
-			// Delay between comments to give Claude time to process
-			if i < len(comments)-1 {
-				time.Sleep(2 * time.Second)
+	title := instance.Title
+	return func() tea.Msg {
+		resolved := 0
+		var errs []error
+		for _, threadID := range pending.threads {
+			if err := pending.pr.ResolveThread(pending.worktreePath, threadID); err != nil {
+				log.ErrorLog.Printf("failed to resolve review thread for %s: %v", title, err)
+				errs = append(errs, err)
+				continue
 			}
+			resolved++
 		}
-
-		return allCommentsProcessedMsg{}
+		return threadsResolvedMsg{instance: title, resolved: resolved, total: len(pending.threads), err: errors.Join(errs...)}
 	}
 }
 
@@ -105,99 +197,12 @@ func (m *home) sendCommentToClaude(comment *git.PRComment) error {
 	}
 
 	// Format the comment as a prompt for Claude
-	prompt := m.formatCommentAsPrompt(comment, 1, 1)
+	prompt := localizePrompt(selected, m.formatCommentAsPrompt(comment, 1, 1))
 
 	// Send prompt to the instance
 	return selected.SendPrompt(prompt)
 }
 
-func (m *home) formatCommentAsPrompt(comment *git.PRComment, index int, total int) string {
-	var prompt strings.Builder
-
-	// Add processing header with comment number
-	prompt.WriteString(fmt.Sprintf("Processing PR comment %d of %d from @%s", index, total, comment.Author))
-	if comment.IsSplit {
-		acceptedCount := len(comment.GetAcceptedPieces())
-		prompt.WriteString(fmt.Sprintf(" (%d pieces selected)", acceptedCount))
-	}
-	prompt.WriteString("\n\n")
-
-	// Format header based on comment type
-	switch comment.Type {
-	case "review":
-		prompt.WriteString("=== PR REVIEW ===\n\n")
-	case "review_comment":
-		prompt.WriteString("=== PR REVIEW COMMENT ===\n\n")
-	case "issue_comment":
-		prompt.WriteString("=== PR GENERAL COMMENT ===\n\n")
-	default:
-		prompt.WriteString("=== PR COMMENT ===\n\n")
-	}
-
-	prompt.WriteString(fmt.Sprintf("Author: @%s\n", comment.Author))
-
-	// Better type descriptions
-	typeDisplay := comment.Type
-	switch comment.Type {
-	case "review":
-		typeDisplay = "PR Review"
-	case "review_comment":
-		typeDisplay = "Review Comment"
-	case "issue_comment":
-		typeDisplay = "General Comment"
-	}
-	prompt.WriteString(fmt.Sprintf("Type: %s\n", typeDisplay))
-
-	if comment.Path != "" {
-		prompt.WriteString(fmt.Sprintf("File: %s", comment.Path))
-		if comment.Line > 0 {
-			prompt.WriteString(fmt.Sprintf(" (line %d)", comment.Line))
-		}
-		prompt.WriteString("\n")
-	}
-
-	prompt.WriteString("\nComment:\n")
-	prompt.WriteString("---\n")
-
-	// Handle split comments differently
-	if comment.IsSplit {
-		// Only include accepted pieces
-		acceptedPieces := comment.GetAcceptedPieces()
-		if len(acceptedPieces) == 0 {
-			// No accepted pieces, return empty prompt
-			return ""
-		}
-
-		prompt.WriteString("Note: This comment has been split into pieces. Only the following selected pieces are included:\n\n")
-		for i, piece := range acceptedPieces {
-			if i > 0 {
-				prompt.WriteString("\n\n")
-			}
-			prompt.WriteString(piece.Content)
-		}
-	} else {
-		prompt.WriteString(comment.Body)
-	}
-
-	prompt.WriteString("\n---\n\n")
-
-	// Customize instructions based on comment type
-	switch comment.Type {
-	case "review":
-		prompt.WriteString("This is a general PR review. Please address the overall feedback provided. ")
-	case "review_comment":
-		prompt.WriteString("This is a line-specific review comment. Please address the specific code feedback. ")
-	case "issue_comment":
-		prompt.WriteString("This is a general PR discussion comment. Please respond appropriately. ")
-	}
-
-	prompt.WriteString("If the comment is asking a question, provide a clear answer. ")
-	prompt.WriteString("If it's suggesting a change, implement it. ")
-	prompt.WriteString("If you need clarification, explain what's unclear.")
-
-	return prompt.String()
-}
-
 type resolveConversationsMsg struct {
 	resolved int
 	total    int
@@ -271,9 +276,10 @@ func (m *home) resolveAllPRConversations() tea.Cmd {
 				// Check for authentication errors to avoid repeated failures
 				if strings.Contains(errStr, "authentication") || 
 				   strings.Contains(errStr, "gh auth login") ||
+				   strings.Contains(errStr, "glab auth login") ||
 				   strings.Contains(errStr, "not authenticated") {
 					return resolveConversationsMsg{
-						err: fmt.Errorf("GitHub CLI not authenticated. Run 'gh auth login' first"),
+						err: fmt.Errorf("CLI not authenticated. Run 'gh auth login' (or 'glab auth login' for GitLab) first"),
 						logs: logs,
 					}
 				}
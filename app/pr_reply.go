@@ -0,0 +1,25 @@
+package app
+
+import (
+	"claude-squad/ui"
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// postPRReply returns a command that posts a reply from the PR review overlay to the forge from the
+// selected instance's worktree.
+func (m *home) postPRReply(msg ui.PRReviewReplyMsg) tea.Cmd {
+	selected := m.list.GetSelectedInstance()
+	return func() tea.Msg {
+		if selected == nil {
+			return ui.PRReviewReplyPostedMsg{Parent: msg.Comment, Err: fmt.Errorf("no instance selected")}
+		}
+		worktree, err := selected.GetGitWorktree()
+		if err != nil {
+			return ui.PRReviewReplyPostedMsg{Parent: msg.Comment, Err: fmt.Errorf("failed to get git worktree: %w", err)}
+		}
+		reply, err := msg.PR.ReplyToComment(worktree.GetWorktreePath(), msg.Comment, msg.Body)
+		return ui.PRReviewReplyPostedMsg{Parent: msg.Comment, Reply: reply, Err: err}
+	}
+}
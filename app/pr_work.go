@@ -0,0 +1,80 @@
+package app
+
+import (
+	"claude-squad/session"
+	"claude-squad/ui"
+	"claude-squad/ui/overlay"
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// prWorkPromptMsg carries the prompt composed to resume work on an instance's pull request
+type prWorkPromptMsg struct {
+	instance *session.Instance
+	prompt   string
+}
+
+// composePRWorkPrompt composes the prompt to resume work on the selected instance's pull request
+// in the background, to edit it before it is sent.
+func (m *home) composePRWorkPrompt() tea.Cmd {
+	selected := m.list.GetSelectedInstance()
+	if selected == nil {
+		return nil
+	}
+	if cmd := m.requireRemote(selected, "Working on the PR"); cmd != nil {
+		return cmd
+	}
+	return tea.Batch(m.notify(ui.ToastInfo, fmt.Sprintf("Fetching the PR of '%s'...", selected.Title)), func() tea.Msg {
+		prompt, err := selected.PRWorkPrompt()
+		if err != nil {
+			return fmt.Errorf("failed to compose the PR prompt: %w", err)
+		}
+		return prWorkPromptMsg{instance: selected, prompt: prompt}
+	})
+}
+
+// showPRWorkPrompt lets the user edit the composed prompt before it is sent.
+func (m *home) showPRWorkPrompt(msg prWorkPromptMsg) tea.Cmd {
+	m.workPromptInstance = msg.instance
+	m.state = stateWorkPrompt
+	m.menu.SetState(ui.StatePrompt)
+	m.textInputOverlay = overlay.NewTextInputOverlay(fmt.Sprintf("Prompt to work on the PR of '%s'", msg.instance.Title), msg.prompt)
+	return tea.WindowSize()
+}
+
+// handleWorkPromptState passes key presses to the PR prompt and sends it once it is submitted:
+// right away if the agent is waiting for input, or queued if it is working.
+func (m *home) handleWorkPromptState(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if !m.textInputOverlay.HandleKeyPress(msg) {
+		return m, nil
+	}
+	submitted, prompt := m.textInputOverlay.IsSubmitted(), strings.TrimSpace(m.textInputOverlay.GetValue())
+	instance := m.workPromptInstance
+	m.textInputOverlay = nil
+	m.workPromptInstance = nil
+	m.state = stateDefault
+	m.menu.SetState(ui.StateDefault)
+	if !submitted || prompt == "" || instance == nil {
+		return m, tea.WindowSize()
+	}
+
+	switch {
+	case !instance.Started() || instance.Paused():
+		return m, tea.Batch(tea.WindowSize(), m.handleError(fmt.Errorf("session '%s' is paused", instance.Title)))
+	case instance.Status == session.Running:
+		instance.EnqueuePrompt(prompt)
+		if err := m.storage.SaveInstances(m.list.GetInstances()); err != nil {
+			return m, tea.Batch(tea.WindowSize(), m.handleError(err))
+		}
+		return m, tea.Batch(tea.WindowSize(), m.notify(ui.ToastInfo,
+			fmt.Sprintf("Queued the PR prompt for '%s' until its agent finishes", instance.Title)))
+	}
+	return m, tea.Batch(tea.WindowSize(), func() tea.Msg {
+		if err := instance.SendPrompt(prompt); err != nil {
+			return fmt.Errorf("failed to send the prompt to %s: %w", instance.Title, err)
+		}
+		return nil
+	})
+}
@@ -0,0 +1,94 @@
+package app
+
+import (
+	"claude-squad/config"
+	"claude-squad/session"
+	"claude-squad/ui/overlay"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// programChoice is a program or template offered for a new instance.
+type programChoice struct {
+	item overlay.ListItem
+	// template configures the instance when the choice is a template
+	template *config.InstanceTemplate
+	program  string
+}
+
+// programChoices returns the default program followed by the programs the repository's contents
+// suggest and the templates running them. It returns nil if nothing suggests a program other
+// than the default.
+func programChoices(defaultProgram string, suggestions []config.ProgramSuggestion, templates []config.InstanceTemplate) []programChoice {
+	defaultReasons := []string{"default"}
+	var others []config.ProgramSuggestion
+	for _, suggestion := range suggestions {
+		if config.SameProgram(suggestion.Program, defaultProgram) {
+			defaultReasons = append(defaultReasons, suggestion.Reason)
+		} else {
+			others = append(others, suggestion)
+		}
+	}
+	if len(others) == 0 {
+		return nil
+	}
+
+	choices := []programChoice{{
+		item:    overlay.ListItem{Title: defaultProgram, Description: strings.Join(defaultReasons, " • ")},
+		program: defaultProgram,
+	}}
+	for _, suggestion := range others {
+		choices = append(choices, programChoice{
+			item:    overlay.ListItem{Title: suggestion.Program, Description: suggestion.Reason},
+			program: suggestion.Program,
+		})
+	}
+	for i := range templates {
+		template := &templates[i]
+		for _, suggestion := range suggestions {
+			if template.Program != "" && config.SameProgram(template.Program, suggestion.Program) {
+				choices = append(choices, programChoice{
+					item:     overlay.ListItem{Title: "Template: " + template.Name, Description: template.Program + " • " + suggestion.Reason},
+					template: template,
+				})
+				break
+			}
+		}
+	}
+	return choices
+}
+
+// chooseProgram offers the programs the repository suggests for a new instance before naming it,
+// or starts naming it with the default program if the repository suggests no other.
+func (m *home) chooseProgram(promptAfterName bool, baseRef string) (tea.Model, tea.Cmd) {
+	var suggestions []config.ProgramSuggestion
+	for _, suggestion := range config.DetectPrograms(m.repoPath) {
+		if m.policy.CheckProgram(suggestion.Program) == nil {
+			suggestions = append(suggestions, suggestion)
+		}
+	}
+	choices := programChoices(m.program, suggestions, m.appConfig.Templates)
+	if len(choices) == 0 {
+		return m.startNewInstance(promptAfterName, baseRef, m.program, session.SeedNone)
+	}
+
+	items := make([]overlay.ListItem, len(choices))
+	for i, choice := range choices {
+		items[i] = choice.item
+	}
+	return m, m.selectFromList("Program For New Session", items, func(idx int) tea.Cmd {
+		choice := choices[idx]
+		if choice.template == nil {
+			_, cmd := m.startNewInstance(promptAfterName, baseRef, choice.program, session.SeedNone)
+			return cmd
+		}
+		template := *choice.template
+		if baseRef != "" {
+			template.BaseBranch = baseRef
+		}
+		cmd := m.newInstanceFromTemplate(template)
+		m.promptAfterName = promptAfterName && template.Prompt == ""
+		return cmd
+	})
+}
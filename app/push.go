@@ -0,0 +1,126 @@
+package app
+
+import (
+	"claude-squad/config"
+	"claude-squad/session"
+	"claude-squad/session/git"
+	"claude-squad/ui"
+	"claude-squad/ui/overlay"
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// pushMessageMsg carries the commit message suggested for pushing an instance's changes. The
+// message is empty if there is nothing to commit. resume is the step an interrupted push failed
+// at, in which case message is the one the push was started with and is used again.
+type pushMessageMsg struct {
+	instance *session.Instance
+	message  string
+	resume   git.PushStep
+}
+
+// suggestPushMessage returns a command suggesting a commit message for the instance's changes,
+// written by the summarizer if one is configured.
+func (m *home) suggestPushMessage(instance *session.Instance) tea.Cmd {
+	var notice tea.Cmd
+	if m.hasSummarizer(config.SummarizeCommitMessage) {
+		notice = m.notify(ui.ToastInfo, "Writing the commit message...")
+	}
+	return tea.Batch(notice, func() tea.Msg {
+		worktree, err := instance.GetGitWorktree()
+		if err != nil {
+			return err
+		}
+		if step, message, ok := worktree.InterruptedPush(); ok {
+			return pushMessageMsg{instance: instance, message: message, resume: step}
+		}
+		message, err := worktree.GenerateCommitMessage()
+		if err != nil {
+			return err
+		}
+		if message != "" {
+			message = m.summarizeCommitMessage(worktree, message)
+		}
+		return pushMessageMsg{instance: instance, message: message}
+	})
+}
+
+// showPushMessagePrompt lets the user edit the suggested commit message before pushing. With
+// nothing to commit, it goes straight to confirming the push.
+func (m *home) showPushMessagePrompt(msg pushMessageMsg) tea.Cmd {
+	if msg.resume != "" {
+		return m.confirmPushAction(msg.instance, msg.message, fmt.Sprintf(
+			"[!] Resume the interrupted push from session '%s' at the %s step?", msg.instance.Title, msg.resume))
+	}
+	if msg.message == "" {
+		return m.confirmPush(msg.instance, "")
+	}
+	m.pushInstance = msg.instance
+	m.state = stateCommitMessage
+	m.menu.SetState(ui.StateBookmark)
+	m.textInputOverlay = overlay.NewTextInputOverlay("Commit message for the push", msg.message)
+	return tea.WindowSize()
+}
+
+// handleCommitMessageState passes key presses to the commit message prompt and confirms the
+// push once the message is submitted.
+func (m *home) handleCommitMessageState(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if !m.textInputOverlay.HandleKeyPress(msg) {
+		return m, nil
+	}
+	submitted, message := m.textInputOverlay.IsSubmitted(), strings.TrimSpace(m.textInputOverlay.GetValue())
+	instance := m.pushInstance
+	m.textInputOverlay = nil
+	m.pushInstance = nil
+	m.state = stateDefault
+	m.menu.SetState(ui.StateDefault)
+	if !submitted || instance == nil {
+		return m, tea.WindowSize()
+	}
+	if message == "" {
+		return m, tea.Batch(tea.WindowSize(), m.handleError(fmt.Errorf("commit message cannot be empty")))
+	}
+	return m, tea.Batch(tea.WindowSize(), m.confirmPush(instance, message))
+}
+
+// confirmPush asks whether to push the instance's changes, committing them with commitMsg, or
+// to push and open a pull request.
+func (m *home) confirmPush(instance *session.Instance, commitMsg string) tea.Cmd {
+	return m.confirmPushAction(instance, commitMsg, fmt.Sprintf("[!] Push changes from session '%s'?", instance.Title))
+}
+
+// confirmPushAction asks the question of confirmPush with the given message.
+func (m *home) confirmPushAction(instance *session.Instance, commitMsg, question string) tea.Cmd {
+	// The push itself runs in the background so its progress can be shown
+	push := func(openPR bool) tea.Cmd {
+		return func() tea.Msg {
+			worktree, err := instance.GetGitWorktree()
+			if err != nil {
+				return err
+			}
+			return m.runWithGitProgress("push", instance.Title, worktree, func(wt *git.GitWorktree) tea.Msg {
+				// Open the branch page, unless the pull request form is opened instead
+				if err := wt.PushChanges(commitMsg, !openPR); err != nil {
+					return err
+				}
+				if openPR {
+					if err := wt.CreatePullRequest(m.describePullRequest(wt)); err != nil {
+						return err
+					}
+				}
+				return pushCompleteMsg{instance: instance}
+			})
+		}
+	}
+
+	message, choices := m.withAuthWarning(question, []confirmChoice{
+		{key: "p", label: "push", action: push(false)},
+		{key: "r", label: "push and open pull request", action: push(true)},
+	})
+	m.confirmChoices(message, choices)
+	return m.offerGitCommands(worktreePlan(instance, func(wt *git.GitWorktree) error {
+		return wt.PushChanges(commitMsg, false)
+	}))
+}
@@ -0,0 +1,60 @@
+package app
+
+import (
+	"claude-squad/log"
+	"claude-squad/session"
+	"claude-squad/ui"
+	"claude-squad/ui/overlay"
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// showPromptQueue opens the prompt queue of the selected instance.
+func (m *home) showPromptQueue() tea.Cmd {
+	selected := m.list.GetSelectedInstance()
+	if selected == nil {
+		return nil
+	}
+	if !selected.Started() {
+		return m.handleError(fmt.Errorf("instance '%s' is not started", selected.Title))
+	}
+	m.promptQueueOverlay = overlay.NewPromptQueueOverlay(fmt.Sprintf("Prompt queue for '%s'", selected.Title), selected)
+	m.state = statePromptQueue
+	return tea.WindowSize()
+}
+
+// handlePromptQueueState passes key presses to the queue overlay and saves the queue once it closes.
+func (m *home) handlePromptQueueState(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if m.promptQueueOverlay == nil {
+		m.state = stateDefault
+		return m, nil
+	}
+	if !m.promptQueueOverlay.HandleKeyPress(msg) {
+		return m, nil
+	}
+	m.promptQueueOverlay = nil
+	m.state = stateDefault
+	if err := m.storage.SaveInstances(m.list.GetInstances()); err != nil {
+		return m, m.handleError(err)
+	}
+	return m, nil
+}
+
+// dispatchQueuedPrompt sends the instance's next queued prompt once its agent has been idle long
+// enough, reporting what was sent.
+func (m *home) dispatchQueuedPrompt(instance *session.Instance) tea.Cmd {
+	prompt, err := instance.DispatchQueuedPrompt(session.QueueDispatchIdle)
+	if err != nil {
+		log.ErrorLog.Printf("%v", err)
+		return nil
+	}
+	if prompt == "" {
+		return nil
+	}
+	if err := m.storage.SaveInstances(m.list.GetInstances()); err != nil {
+		log.WarningLog.Printf("failed to save instances after sending a queued prompt: %v", err)
+	}
+	return m.notify(ui.ToastInfo, fmt.Sprintf("Sent queued prompt to '%s' (%d left)",
+		instance.Title, len(instance.QueuedPrompts())))
+}
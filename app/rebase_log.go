@@ -0,0 +1,137 @@
+package app
+
+import (
+	"claude-squad/log"
+	"claude-squad/session"
+	"claude-squad/session/git"
+	"claude-squad/ui"
+	"claude-squad/ui/overlay"
+	"context"
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// gitStepMsg carries a step of a running git operation to its log. next waits for the following
+// step or progress update.
+type gitStepMsg struct {
+	log  *overlay.StepLogOverlay
+	step string
+	next tea.Cmd
+}
+
+// runWithGitSteps is runWithGitProgress for multi-step operations, also streaming the steps of
+// the operation to stepLog, whose abort cancels it. A nil stepLog only reports progress.
+func (m *home) runWithGitSteps(kind, title string, worktree *git.GitWorktree, stepLog *overlay.StepLogOverlay, op func(wt *git.GitWorktree) tea.Msg) tea.Cmd {
+	m.gitProgress.Start(title)
+	pending, ctx := m.startOperation(kind, title, true)
+	if stepLog != nil {
+		stepLog.OnAbort = func() {
+			pending.cancelled = true
+			pending.cancel()
+		}
+	}
+
+	updates := make(chan git.GitProgress, 16)
+	steps := make(chan string)
+	done := make(chan struct{})
+
+	var listen tea.Cmd
+	listen = func() tea.Msg {
+		select {
+		case p := <-updates:
+			return gitProgressMsg{op: pending.id, title: title, progress: p, log: stepLog, next: listen}
+		case step := <-steps:
+			return gitStepMsg{log: stepLog, step: step, next: listen}
+		case <-done:
+			return gitProgressDoneMsg{title: title}
+		}
+	}
+
+	run := pending.run(ctx, func(ctx context.Context) tea.Msg {
+		defer close(done)
+		wt := worktree.WithContext(ctx).WithProgress(func(p git.GitProgress) {
+			// Drop updates rather than stall git if the UI falls behind
+			select {
+			case updates <- p:
+			default:
+			}
+		})
+		if stepLog != nil {
+			// Steps are few and all of them are logged, so wait for the UI to take each
+			wt = wt.WithSteps(func(step string) { steps <- step })
+		}
+		return op(wt)
+	})
+
+	return tea.Batch(run, listen)
+}
+
+// startRebaseLog creates the log of updating the instance with main, replacing the log of its
+// previous update, and shows it.
+func (m *home) startRebaseLog(instance *session.Instance, strategy git.MergeStrategy) (*overlay.StepLogOverlay, tea.Cmd) {
+	stepLog := overlay.NewStepLogOverlay(rebaseLogTitle(instance.Title, strategy))
+	if m.rebaseLogs == nil {
+		m.rebaseLogs = make(map[*session.Instance]*overlay.StepLogOverlay)
+	}
+	m.rebaseLogs[instance] = stepLog
+	return stepLog, m.showRebaseLog(stepLog)
+}
+
+// rebaseLogTitle names an update of the instance with main using the strategy.
+func rebaseLogTitle(title string, strategy git.MergeStrategy) string {
+	switch strategy {
+	case git.MergeStrategyMerge:
+		return fmt.Sprintf("Merge main into '%s'", title)
+	case git.MergeStrategySquash:
+		return fmt.Sprintf("Squash '%s' onto main", title)
+	}
+	return fmt.Sprintf("Rebase '%s' onto main", title)
+}
+
+// showRebaseLog shows the step log of an update with main.
+func (m *home) showRebaseLog(stepLog *overlay.StepLogOverlay) tea.Cmd {
+	m.rebaseLog = stepLog
+	m.state = stateRebaseLog
+	return tea.WindowSize()
+}
+
+// rebaseLogShown returns true if the step log of the instance's update with main is on screen.
+func (m *home) rebaseLogShown(instance *session.Instance) bool {
+	stepLog := m.rebaseLogs[instance]
+	return stepLog != nil && m.state == stateRebaseLog && m.rebaseLog == stepLog
+}
+
+// showLastRebaseLog shows the step log of the selected instance's last update with main.
+func (m *home) showLastRebaseLog() tea.Cmd {
+	selected := m.list.GetSelectedInstance()
+	if selected == nil {
+		return nil
+	}
+	stepLog := m.rebaseLogs[selected]
+	if stepLog == nil {
+		return m.notify(ui.ToastInfo, fmt.Sprintf("'%s' hasn't been updated with main yet", selected.Title))
+	}
+	return m.showRebaseLog(stepLog)
+}
+
+// handleRebaseLogState handles key presses while the step log of an update with main is shown.
+// Hiding it leaves the update running.
+func (m *home) handleRebaseLogState(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if m.rebaseLog == nil {
+		m.state = stateDefault
+		return m, nil
+	}
+	if m.rebaseLog.HandleKeyPress(msg) {
+		m.state = stateDefault
+		m.rebaseLog = nil
+	}
+	return m, nil
+}
+
+// reportRebaseError records an error of an update with main whose log is on screen, which shows
+// it instead of a toast.
+func (m *home) reportRebaseError(err error) {
+	log.ErrorLog.Printf("%v", err)
+	m.appendErrorLog(err.Error())
+}
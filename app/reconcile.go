@@ -0,0 +1,58 @@
+package app
+
+import (
+	"claude-squad/log"
+	"claude-squad/session"
+	"claude-squad/ui"
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// saveScrollback saves the scrollback of every running instance's panes in the background, to
+// recover it if their tmux sessions are lost.
+func (m *home) saveScrollback() tea.Cmd {
+	instances := m.list.GetInstances()
+	return func() tea.Msg {
+		saveScrollback(instances)
+		return nil
+	}
+}
+
+// saveScrollback saves the scrollback of the instances' panes, logging failures.
+func saveScrollback(instances []*session.Instance) {
+	for _, instance := range instances {
+		if err := instance.SaveScrollback(); err != nil {
+			log.WarningLog.Printf("%v", err)
+		}
+	}
+}
+
+// reportSessionReconcile reports how the stored instances matched the tmux sessions running at
+// startup: the instances whose sessions were lost and recreated, and the sessions left with no
+// instance.
+func (m *home) reportSessionReconcile() tea.Cmd {
+	instances := m.list.GetInstances()
+	var lost []string
+	for _, instance := range instances {
+		if instance.SessionLost() {
+			lost = append(lost, instance.Title)
+		}
+	}
+	var parts []string
+	if len(lost) > 0 {
+		parts = append(parts, fmt.Sprintf("Recreated the lost tmux sessions of %s", strings.Join(lost, ", ")))
+	}
+	orphans, err := session.OrphanedSessions(instances)
+	if err != nil {
+		log.WarningLog.Printf("failed to list tmux sessions: %v", err)
+	}
+	if len(orphans) > 0 {
+		parts = append(parts, fmt.Sprintf("tmux sessions with no instance: %s (tmux kill-session -t <name> to remove)", strings.Join(orphans, ", ")))
+	}
+	if len(parts) == 0 {
+		return nil
+	}
+	return m.notify(ui.ToastWarning, strings.Join(parts, ". "))
+}
@@ -0,0 +1,19 @@
+package app
+
+import (
+	"claude-squad/session"
+	"claude-squad/ui"
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// requireRemote returns a command explaining that action is unavailable if the instance's
+// repository has no origin remote, or nil if it has one.
+func (m *home) requireRemote(instance *session.Instance, action string) tea.Cmd {
+	if instance.HasRemote() {
+		return nil
+	}
+	return m.notify(ui.ToastInfo, fmt.Sprintf("%s is not available for '%s': its repository has no origin remote",
+		action, instance.Title))
+}
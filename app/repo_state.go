@@ -0,0 +1,115 @@
+package app
+
+import (
+	"claude-squad/session"
+	"claude-squad/session/git"
+	"claude-squad/ui"
+	"claude-squad/ui/overlay"
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// newInstanceMsg starts naming a new instance whose branch is created from baseRef, or from the
+// default starting point if it is empty. seed brings the main checkout's changes into it.
+type newInstanceMsg struct {
+	promptAfterName bool
+	baseRef         string
+	seed            session.SeedMode
+}
+
+// baseRefPromptMsg asks for the branch, tag or commit a new instance starts from.
+type baseRefPromptMsg struct {
+	promptAfterName bool
+}
+
+// confirmRepoState explains a detached HEAD or an operation in progress in the main checkout
+// before a new instance is created, offering to start from an explicit commit or tag instead.
+// It returns false if the checkout is on a branch with nothing in progress.
+func (m *home) confirmRepoState(promptAfterName bool) bool {
+	if m.remoteHost != "" {
+		// The main checkout on the remote host isn't inspected
+		return false
+	}
+	state, err := git.GetRepoState(m.repoPath)
+	if err != nil || state.Normal() {
+		// Errors surface when the instance starts
+		return false
+	}
+
+	var message strings.Builder
+	message.WriteString(state.Describe())
+	defaultLabel := "Start from the default branch"
+	if git.HasRemote(m.repoPath) {
+		message.WriteString("\n\nNew instances start from origin's default branch, so this doesn't affect them.")
+	} else {
+		fmt.Fprintf(&message, "\n\nWithout an origin remote, new instances start from the checked out commit %s", state.Head)
+		if state.Operation != "" {
+			fmt.Fprintf(&message, ", which doesn't include the unfinished %s", state.Operation)
+		}
+		message.WriteString(".")
+		defaultLabel = "Start from " + state.Head
+	}
+
+	m.confirmChoices(message.String(), []confirmChoice{
+		{key: "d", label: defaultLabel, action: func() tea.Msg {
+			return newInstanceMsg{promptAfterName: promptAfterName}
+		}},
+		{key: "c", label: "Start from a branch, tag or commit...", action: func() tea.Msg {
+			return baseRefPromptMsg{promptAfterName: promptAfterName}
+		}},
+	})
+	return true
+}
+
+// showBaseRefPrompt asks for the branch, tag or commit a new instance starts from.
+func (m *home) showBaseRefPrompt(msg baseRefPromptMsg) tea.Cmd {
+	m.state = stateBaseRef
+	m.menu.SetState(ui.StateBookmark)
+	m.promptAfterName = msg.promptAfterName
+	m.textInputOverlay = overlay.NewTextInputOverlay("Enter the branch, tag or commit to start from", "")
+	return nil
+}
+
+// submitBaseRef starts a new instance from the entered branch, tag or commit once it resolves.
+func (m *home) submitBaseRef(ref string) (tea.Model, tea.Cmd) {
+	ref = strings.TrimSpace(ref)
+	if ref == "" {
+		m.promptAfterName = false
+		return m, nil
+	}
+	if _, err := git.ResolveCommit(m.repoPath, ref); err != nil {
+		m.promptAfterName = false
+		return m, m.handleError(err)
+	}
+	return m.chooseProgram(m.promptAfterName, ref)
+}
+
+// startNewInstance adds an unnamed instance running program to the list and starts naming it.
+// Its branch is created from baseRef, or from the default starting point if it is empty, and
+// takes the main checkout's changes as seed says.
+func (m *home) startNewInstance(promptAfterName bool, baseRef, program string, seed session.SeedMode) (tea.Model, tea.Cmd) {
+	instance, err := session.NewInstance(session.InstanceOptions{
+		Title:       "",
+		Path:        m.instanceRepoPath(),
+		Program:     program,
+		BaseBranch:  baseRef,
+		Host:        m.remoteHost,
+		SeedChanges: seed,
+	})
+	if err != nil {
+		return m, m.handleError(err)
+	}
+
+	m.newInstanceFinalizer = m.list.AddInstance(instance)
+	m.list.SetSelectedInstance(m.list.NumInstances() - 1)
+	m.state = stateNew
+	m.menu.SetState(ui.StateNewInstance)
+	m.promptAfterName = promptAfterName
+
+	if promptAfterName {
+		return m, m.notify(ui.ToastInfo, "Type a name, or press enter to name the session from its prompt")
+	}
+	return m, nil
+}
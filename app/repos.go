@@ -0,0 +1,171 @@
+package app
+
+import (
+	"claude-squad/config"
+	"claude-squad/session/git"
+	"claude-squad/ui"
+	"claude-squad/ui/overlay"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// repoChoices returns the repositories new instances can be created in: the one claude-squad was
+// started in, followed by the registered ones.
+func (m *home) repoChoices() []string {
+	repos := []string{}
+	if root, err := git.FindRepoRoot("."); err == nil {
+		repos = append(repos, root)
+	}
+	for _, repo := range m.appConfig.Repos {
+		if len(repos) == 0 || repo != repos[0] {
+			repos = append(repos, repo)
+		}
+	}
+	return repos
+}
+
+// remoteHostChoices returns the names of the configured remote hosts, sorted.
+func (m *home) remoteHostChoices() []string {
+	hosts := make([]string, 0, len(m.appConfig.RemoteHosts))
+	for name := range m.appConfig.RemoteHosts {
+		hosts = append(hosts, name)
+	}
+	sort.Strings(hosts)
+	return hosts
+}
+
+// showRepoPicker lets the user pick the repository or remote host new instances are created in,
+// add a repository, or remove a registered one.
+func (m *home) showRepoPicker() tea.Cmd {
+	repos := m.repoChoices()
+	hosts := m.remoteHostChoices()
+	items := make([]overlay.ListItem, 0, len(repos)+len(hosts)+2)
+	for _, repo := range repos {
+		description := repo
+		if repo == m.repoPath && m.remoteHost == "" {
+			description += " (current)"
+		}
+		items = append(items, overlay.ListItem{Title: filepath.Base(repo), Description: description})
+	}
+	for _, name := range hosts {
+		host := m.appConfig.RemoteHosts[name]
+		description := fmt.Sprintf("%s:%s over SSH", host.Host, host.RepoPath)
+		if name == m.remoteHost {
+			description += " (current)"
+		}
+		items = append(items, overlay.ListItem{Title: name, Description: description})
+	}
+	items = append(items, overlay.ListItem{Title: "Add repository...", Description: "enter the path of a git repository"})
+	if len(m.appConfig.Repos) > 0 {
+		items = append(items, overlay.ListItem{Title: "Remove repository...", Description: "stop offering a registered repository"})
+	}
+
+	return m.selectFromList("Create new sessions in", items, func(idx int) tea.Cmd {
+		switch {
+		case idx < len(repos):
+			return m.useRepo(repos[idx])
+		case idx < len(repos)+len(hosts):
+			return m.useRemoteHost(hosts[idx-len(repos)])
+		case idx == len(repos)+len(hosts):
+			return m.showRepoPathPrompt()
+		}
+		return m.showRemoveRepo()
+	})
+}
+
+// useRepo makes new instances be created in the repository at path.
+func (m *home) useRepo(path string) tea.Cmd {
+	m.repoPath = path
+	m.remoteHost = ""
+	return m.showSuccess(fmt.Sprintf("New sessions will be created in %s", filepath.Base(path)))
+}
+
+// useRemoteHost makes new instances run on the configured remote host name.
+func (m *home) useRemoteHost(name string) tea.Cmd {
+	m.remoteHost = name
+	return m.showSuccess(fmt.Sprintf("New sessions will run on %s", name))
+}
+
+// instanceRepoPath returns the path of the repository new instances are created in, on the
+// remote host if one is picked.
+func (m *home) instanceRepoPath() string {
+	if m.remoteHost != "" {
+		return m.appConfig.RemoteHosts[m.remoteHost].RepoPath
+	}
+	return m.repoPath
+}
+
+// showRemoveRepo lets the user pick a registered repository to remove.
+func (m *home) showRemoveRepo() tea.Cmd {
+	repos := m.appConfig.Repos
+	items := make([]overlay.ListItem, len(repos))
+	for n, repo := range repos {
+		items[n] = overlay.ListItem{Title: filepath.Base(repo), Description: repo}
+	}
+	return m.selectFromList("Remove repository", items, func(idx int) tea.Cmd {
+		removed := repos[idx]
+		updated, err := config.RemoveRepo(removed)
+		if err != nil {
+			return m.handleError(err)
+		}
+		m.appConfig.Repos = updated
+		cmds := []tea.Cmd{m.showSuccess(fmt.Sprintf("Removed repository %s", filepath.Base(removed)))}
+		if m.repoPath == removed {
+			if root, err := git.FindRepoRoot("."); err == nil {
+				cmds = append(cmds, m.useRepo(root))
+			}
+		}
+		return tea.Batch(cmds...)
+	})
+}
+
+// showRepoPathPrompt asks for the path of a repository to add.
+func (m *home) showRepoPathPrompt() tea.Cmd {
+	m.state = stateRepoPath
+	m.menu.SetState(ui.StateBookmark)
+	m.textInputOverlay = overlay.NewTextInputOverlay("Path of the git repository to add", "")
+	return tea.WindowSize()
+}
+
+// handleRepoPathState passes key presses to the repository path prompt and adds the repository
+// once it's submitted.
+func (m *home) handleRepoPathState(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if !m.textInputOverlay.HandleKeyPress(msg) {
+		return m, nil
+	}
+	submitted, value := m.textInputOverlay.IsSubmitted(), strings.TrimSpace(m.textInputOverlay.GetValue())
+	m.textInputOverlay = nil
+	m.state = stateDefault
+	m.menu.SetState(ui.StateDefault)
+	if !submitted || value == "" {
+		return m, tea.WindowSize()
+	}
+	return m, tea.Batch(tea.WindowSize(), m.addRepo(value))
+}
+
+// addRepo registers the repository containing path and creates new instances in it.
+func (m *home) addRepo(path string) tea.Cmd {
+	if rest, ok := strings.CutPrefix(path, "~"); ok {
+		if home, err := os.UserHomeDir(); err == nil {
+			path = home + rest
+		}
+	}
+	if !git.IsGitRepo(path) {
+		return m.handleError(fmt.Errorf("%s is not in a git repository", path))
+	}
+	root, err := git.FindRepoRoot(path)
+	if err != nil {
+		return m.handleError(err)
+	}
+	repos, err := config.AddRepo(root)
+	if err != nil {
+		return m.handleError(err)
+	}
+	m.appConfig.Repos = repos
+	return m.useRepo(root)
+}
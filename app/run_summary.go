@@ -0,0 +1,35 @@
+package app
+
+import (
+	"claude-squad/log"
+	"claude-squad/notify"
+	"claude-squad/session"
+	"claude-squad/ui"
+	"fmt"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// summarizeFinishedRun summarizes what the agent changed for the last prompt sent to the
+// instance once it has settled into Ready after it, attaching the summary to the prompt's entry
+// in the instance's details and announcing it.
+func (m *home) summarizeFinishedRun(instance *session.Instance) tea.Cmd {
+	run, pending := instance.PendingPromptRun()
+	if !pending || instance.Status != session.Ready {
+		return nil
+	}
+	// Ready since before the prompt was sent means the agent hasn't started on it yet
+	readySince := instance.ReadySince()
+	if readySince.Before(run.SentAt) || time.Since(readySince) < session.QueueDispatchIdle {
+		return nil
+	}
+	summary, err := instance.FinishPromptRun()
+	if err != nil {
+		log.WarningLog.Printf("could not summarize the run of %s: %v", instance.Title, err)
+		return nil
+	}
+	message := fmt.Sprintf("Finished after %s: %s", readySince.Sub(run.SentAt).Round(time.Second), summary)
+	return tea.Batch(m.notify(ui.ToastInfo, fmt.Sprintf("'%s': %s", instance.Title, message)),
+		m.sendInstanceEvent(notify.EventRunFinished, instance, message))
+}
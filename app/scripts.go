@@ -0,0 +1,153 @@
+package app
+
+import (
+	"claude-squad/config"
+	"claude-squad/log"
+	"claude-squad/scripting"
+	"claude-squad/session"
+	"claude-squad/ui"
+	"errors"
+	"fmt"
+	"path/filepath"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// pushCompleteMsg is sent when an instance's branch was pushed
+type pushCompleteMsg struct {
+	instance *session.Instance
+}
+
+// loadScripts loads the user's scripts from the scripts directory in the config directory.
+// What they print goes to the log.
+func loadScripts() (*scripting.Engine, []error) {
+	configDir, err := config.GetConfigDir()
+	if err != nil {
+		return nil, []error{fmt.Errorf("failed to get config directory: %w", err)}
+	}
+	engine, errs := scripting.Load(filepath.Join(configDir, config.ScriptsDirName))
+	engine.Print = func(script, msg string) {
+		log.InfoLog.Printf("script %s: %s", script, msg)
+	}
+	return engine, errs
+}
+
+// reportScriptErrors warns about the scripts that failed to load.
+func (m *home) reportScriptErrors() tea.Cmd {
+	if len(m.scriptErrors) == 0 {
+		return nil
+	}
+	return m.notify(ui.ToastWarning, errors.Join(m.scriptErrors...).Error())
+}
+
+// scriptEvent creates a script event about the instance.
+func scriptEvent(eventType string, instance *session.Instance, message string) scripting.Event {
+	return scripting.Event{
+		Type:    eventType,
+		Title:   instance.Title,
+		Branch:  instance.Branch,
+		Program: instance.Program,
+		Tags:    instance.Tags,
+		Status:  statusLabels[instance.Status],
+		Message: message,
+	}
+}
+
+// runScripts passes the event to the scripts handling it and performs the actions they ask for.
+func (m *home) runScripts(event scripting.Event) tea.Cmd {
+	if !m.scripts.Subscribed(event.Type) {
+		return nil
+	}
+	actions, errs := m.scripts.Dispatch(event)
+	var cmds []tea.Cmd
+	for _, err := range errs {
+		cmds = append(cmds, m.handleError(err))
+	}
+	for _, action := range actions {
+		cmds = append(cmds, m.performScriptAction(action))
+	}
+	return tea.Batch(cmds...)
+}
+
+// performScriptAction does what a script asked for.
+func (m *home) performScriptAction(action scripting.Action) tea.Cmd {
+	if action.Kind == scripting.ActionCreateInstance {
+		return m.createScriptInstance(action)
+	}
+	for idx, instance := range m.list.GetInstances() {
+		if instance.Title != action.Title {
+			continue
+		}
+		switch action.Kind {
+		case scripting.ActionSendPrompt:
+			return m.sendScriptPrompt(action, instance)
+		case scripting.ActionRunTests:
+			// The test pane runs the tests of the selected instance
+			m.list.SetSelectedInstance(idx)
+			return tea.Batch(m.instanceChanged(), m.runTests(instance))
+		}
+		return nil
+	}
+	return m.handleError(fmt.Errorf("script %s: no session '%s' to %s", action.Script, action.Title, action.Kind))
+}
+
+// sendScriptPrompt sends the prompt of a script to the instance if its agent waits for input,
+// and queues it while the agent works.
+func (m *home) sendScriptPrompt(action scripting.Action, instance *session.Instance) tea.Cmd {
+	switch {
+	case !instance.Started() || instance.Paused():
+		return m.handleError(fmt.Errorf("script %s: session '%s' is paused", action.Script, instance.Title))
+	case instance.Status == session.Running:
+		instance.EnqueuePrompt(action.Prompt)
+		if err := m.storage.SaveInstances(m.list.GetInstances()); err != nil {
+			return m.handleError(err)
+		}
+		return nil
+	}
+	return func() tea.Msg {
+		if err := instance.SendPrompt(action.Prompt); err != nil {
+			return fmt.Errorf("script %s: failed to send the prompt to %s: %w", action.Script, instance.Title, err)
+		}
+		return nil
+	}
+}
+
+// createScriptInstance creates and starts the instance a script asked for, sending it the
+// script's prompt once it runs.
+func (m *home) createScriptInstance(action scripting.Action) tea.Cmd {
+	if m.state == stateNew {
+		return m.handleError(fmt.Errorf("script %s: can't create '%s' while a session is being named", action.Script, action.Title))
+	}
+	if limit := m.instanceLimit(); m.list.NumInstances() >= limit {
+		return m.handleError(fmt.Errorf("script %s: you can't create more than %d instances", action.Script, limit))
+	}
+	program := action.Program
+	if program == "" {
+		program = m.program
+	}
+	if err := m.policy.CheckProgram(program); err != nil {
+		return m.handleError(err)
+	}
+	taken := make(map[string]bool)
+	for _, other := range m.list.GetInstances() {
+		taken[other.Title] = true
+	}
+	instance, err := session.NewInstance(session.InstanceOptions{
+		Title:   uniqueTitle(action.Title, taken),
+		Path:    m.instanceRepoPath(),
+		Program: program,
+		Host:    m.remoteHost,
+	})
+	if err != nil {
+		return m.handleError(fmt.Errorf("script %s: %w", action.Script, err))
+	}
+	instance.Prompt = action.Prompt
+
+	finalize := m.list.AddInstance(instance)
+	cmd := m.startInstanceAsync(instance)
+	if err := m.storage.SaveInstances(m.list.GetInstances()); err != nil {
+		return m.handleError(err)
+	}
+	finalize()
+	return tea.Batch(cmd, m.notify(ui.ToastInfo, fmt.Sprintf("Script %s is creating session '%s'", action.Script, instance.Title)))
+}
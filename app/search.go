@@ -0,0 +1,161 @@
+package app
+
+import (
+	"claude-squad/session"
+	"claude-squad/ui"
+	"claude-squad/ui/overlay"
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// searchField is a piece of an instance's content that can be searched, and the tab showing it.
+type searchField struct {
+	name string
+	tab  int
+	text string
+}
+
+// searchDoc is the searchable content of an instance.
+type searchDoc struct {
+	instance *session.Instance
+	fields   []searchField
+}
+
+// searchHit is where a query matched an instance.
+type searchHit struct {
+	instance *session.Instance
+	field    string
+	tab      int
+	snippet  string
+	count    int
+}
+
+// searchDocsMsg carries the content of all instances, captured for searching them.
+type searchDocsMsg struct {
+	docs []searchDoc
+}
+
+// collectSearchDocs returns a command capturing the content of the instances to search. The AI
+// pane's full history is captured for running instances; the diff is the one last computed.
+func collectSearchDocs(instances []*session.Instance) tea.Cmd {
+	return func() tea.Msg {
+		docs := make([]searchDoc, 0, len(instances))
+		for _, instance := range instances {
+			doc := searchDoc{instance: instance, fields: []searchField{
+				{name: "title", tab: -1, text: instance.Title},
+				{name: "branch", tab: -1, text: instance.Branch},
+			}}
+			if stats := instance.GetDiffStats(); stats != nil && stats.Error == nil && stats.Content != "" {
+				doc.fields = append(doc.fields, searchField{name: "diff", tab: ui.DiffTab, text: stats.Content})
+			}
+			if instance.Started() && !instance.Paused() {
+				if history, err := instance.GetAIFullHistory(); err == nil {
+					doc.fields = append(doc.fields, searchField{name: "AI", tab: ui.AITab, text: ansi.Strip(history)})
+				}
+			}
+			docs = append(docs, doc)
+		}
+		return searchDocsMsg{docs: docs}
+	}
+}
+
+// searchInstances finds query in the instances' content, ignoring case. Each field of an instance
+// matching gives one hit, with the first matching line as its snippet; hits on a diff name the
+// file the line is in.
+func searchInstances(docs []searchDoc, query string) []searchHit {
+	query = strings.ToLower(strings.TrimSpace(query))
+	if query == "" {
+		return nil
+	}
+	var hits []searchHit
+	for _, doc := range docs {
+		for _, field := range doc.fields {
+			var hit *searchHit
+			file := ""
+			for _, line := range strings.Split(field.text, "\n") {
+				if field.name == "diff" && strings.HasPrefix(line, "diff --git ") {
+					if idx := strings.LastIndex(line, " b/"); idx >= 0 {
+						file = line[idx+3:]
+					}
+				}
+				if !strings.Contains(strings.ToLower(line), query) {
+					continue
+				}
+				if hit == nil {
+					snippet := strings.TrimSpace(line)
+					if field.name == "diff" && file != "" && !strings.HasPrefix(line, "diff --git ") {
+						snippet = file + ": " + snippet
+					}
+					hit = &searchHit{instance: doc.instance, field: field.name, tab: field.tab, snippet: snippet}
+				}
+				hit.count++
+			}
+			if hit != nil {
+				hits = append(hits, *hit)
+			}
+		}
+	}
+	return hits
+}
+
+// showSearch opens the cross-instance search over the captured content of all instances.
+func (m *home) showSearch(docs []searchDoc) tea.Cmd {
+	var hits []searchHit
+	m.searchOverlay = overlay.NewSearchOverlay("Search sessions", func(query string) []overlay.ListItem {
+		hits = searchInstances(docs, query)
+		items := make([]overlay.ListItem, len(hits))
+		for i, hit := range hits {
+			where := hit.field
+			if hit.count > 1 {
+				where = fmt.Sprintf("%s, %d lines", hit.field, hit.count)
+			}
+			items[i] = overlay.ListItem{
+				Title:       fmt.Sprintf("%s (%s)", hit.instance.Title, where),
+				Description: hit.snippet,
+			}
+		}
+		return items
+	})
+	m.onSearchSelect = func(idx int) tea.Cmd {
+		return m.jumpToSearchHit(hits[idx])
+	}
+	m.state = stateSearch
+	m.menu.SetState(ui.StateDefault)
+	return tea.WindowSize()
+}
+
+// handleSearchState passes key presses to the search overlay and jumps to the chosen result.
+func (m *home) handleSearchState(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if !m.searchOverlay.HandleKeyPress(msg) {
+		return m, nil
+	}
+	idx := m.searchOverlay.SelectedIndex()
+	onSelect := m.onSearchSelect
+	m.searchOverlay = nil
+	m.onSearchSelect = nil
+	m.state = stateDefault
+	if idx < 0 || onSelect == nil {
+		return m, tea.WindowSize()
+	}
+	return m, tea.Batch(tea.WindowSize(), onSelect(idx))
+}
+
+// jumpToSearchHit selects the instance of a search result and shows the tab it matched in.
+func (m *home) jumpToSearchHit(hit searchHit) tea.Cmd {
+	for idx, instance := range m.list.GetInstances() {
+		if instance != hit.instance {
+			continue
+		}
+		m.list.SetSelectedInstance(idx)
+		m.showInstanceTab(instance)
+		if hit.tab >= 0 {
+			m.tabbedWindow.SetTab(hit.tab)
+			m.menu.SetInDiffTab(m.tabbedWindow.IsInDiffTab())
+		}
+		return m.instanceChanged()
+	}
+	return m.handleError(fmt.Errorf("session '%s' no longer exists", hit.instance.Title))
+}
@@ -0,0 +1,57 @@
+package app
+
+import (
+	"claude-squad/session"
+	"claude-squad/session/git"
+	"claude-squad/ui"
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// confirmNewFromChanges offers to start a new instance with the uncommitted changes of the main
+// checkout, moved or copied into its worktree, to hand work started by hand to an agent. The
+// branch starts from the checkout's commit so the changes apply as they are.
+func (m *home) confirmNewFromChanges() tea.Cmd {
+	if err := m.checkNewInstance(m.program); err != nil {
+		return m.handleError(err)
+	}
+	if m.remoteHost != "" {
+		return m.notify(ui.ToastInfo, "Instances on remote hosts can't take this checkout's changes")
+	}
+	state, err := git.GetRepoState(m.repoPath)
+	if err != nil {
+		return m.handleError(err)
+	}
+	if state.Operation != "" {
+		return m.handleError(fmt.Errorf("finish the %s in %s before handing its changes to a session", state.Operation, m.repoPath))
+	}
+	changes, err := git.CheckoutChanges(m.repoPath)
+	if err != nil {
+		return m.handleError(err)
+	}
+	if changes == 0 {
+		return m.notify(ui.ToastInfo, fmt.Sprintf("%s has no uncommitted changes to hand to a session", m.repoPath))
+	}
+	head, err := git.ResolveCommit(m.repoPath, "HEAD")
+	if err != nil {
+		return m.handleError(err)
+	}
+
+	on := state.Head
+	if state.Branch != "" {
+		on = state.Branch
+	}
+	start := func(seed session.SeedMode) func() tea.Msg {
+		return func() tea.Msg {
+			return newInstanceMsg{promptAfterName: true, baseRef: head, seed: seed}
+		}
+	}
+	m.confirmChoices(fmt.Sprintf(
+		"Start a new session from %s with the %d changed file(s) of the main checkout, untracked files included?",
+		on, changes), []confirmChoice{
+		{key: "m", label: "move them, leaving the checkout clean", action: start(session.SeedMove)},
+		{key: "c", label: "copy them, leaving the checkout as it is", action: start(session.SeedCopy)},
+	})
+	return nil
+}
@@ -0,0 +1,132 @@
+package app
+
+import (
+	"claude-squad/config"
+	"claude-squad/session"
+	"claude-squad/ui"
+	"claude-squad/ui/overlay"
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// serviceMsg reports that a service was started or stopped.
+type serviceMsg struct {
+	message string
+}
+
+// serviceOutputMsg carries the output of a service to show.
+type serviceOutputMsg struct {
+	title  string
+	output string
+}
+
+// servicesConfigPath returns where the repository configuration declaring the instance's
+// services is read from: its worktree, or for an instance on a remote host, the local clone.
+func (m *home) servicesConfigPath(instance *session.Instance) string {
+	if instance.Host != "" {
+		return m.repoPath
+	}
+	worktree, err := instance.GetGitWorktree()
+	if err != nil {
+		return m.repoPath
+	}
+	return worktree.GetWorktreePath()
+}
+
+// takenServicePorts returns the ports assigned to the services of the instances other than
+// instance, so a new port doesn't collide with them.
+func takenServicePorts(instances []*session.Instance, instance *session.Instance) map[int]bool {
+	taken := make(map[int]bool)
+	for _, other := range instances {
+		if other == instance {
+			continue
+		}
+		for _, svc := range other.Services {
+			taken[svc.Port] = true
+		}
+	}
+	return taken
+}
+
+// showServices lists the dev servers of the selected instance's repository with their ports and
+// states, to start a stopped one or stop, restart or read the output of a running one.
+func (m *home) showServices() tea.Cmd {
+	selected := m.list.GetSelectedInstance()
+	if selected == nil || !selected.Started() {
+		return nil
+	}
+	if selected.Paused() {
+		return m.notify(ui.ToastInfo, fmt.Sprintf("Resume '%s' to run its services", selected.Title))
+	}
+	services := config.LoadRepoConfig(m.servicesConfigPath(selected)).Services
+	if len(services) == 0 {
+		return m.notify(ui.ToastInfo, "No services configured. Add them to .claude-squad/config.json")
+	}
+	states, err := selected.ServiceStates()
+	if err != nil {
+		return m.handleError(err)
+	}
+
+	items := make([]overlay.ListItem, len(services))
+	for n, svc := range services {
+		state := states[svc.Name]
+		if state == "" {
+			state = session.ServiceStopped
+		}
+		port := "port assigned on start"
+		if assigned := selected.ServicePort(svc.Name); assigned != 0 {
+			port = fmt.Sprintf("port %d", assigned)
+		}
+		items[n] = overlay.ListItem{
+			Title:       fmt.Sprintf("%s (%s)", svc.Name, state),
+			Description: fmt.Sprintf("%s • %s", svc.Command, port),
+		}
+	}
+
+	return m.selectFromList("Services of "+selected.Title, items, func(idx int) tea.Cmd {
+		svc := services[idx]
+		taken := takenServicePorts(m.list.GetInstances(), selected)
+		start := func() tea.Msg {
+			port, err := selected.StartService(svc, taken)
+			if err != nil {
+				return err
+			}
+			return serviceMsg{message: fmt.Sprintf("Started %s on port %d", svc.Name, port)}
+		}
+		state := states[svc.Name]
+		if state == "" {
+			return start
+		}
+		stop := func() tea.Msg {
+			if err := selected.StopService(svc.Name); err != nil {
+				return err
+			}
+			return serviceMsg{message: fmt.Sprintf("Stopped %s", svc.Name)}
+		}
+		output := func() tea.Msg {
+			content, err := selected.ServiceOutput(svc.Name)
+			if err != nil {
+				return err
+			}
+			return serviceOutputMsg{title: fmt.Sprintf("%s - %s", svc.Name, selected.Title), output: content}
+		}
+		return m.confirmChoices(fmt.Sprintf("Service %s is %s on port %d", svc.Name, state, selected.ServicePort(svc.Name)), []confirmChoice{
+			{key: "o", label: "show output", action: output},
+			{key: "r", label: "restart", action: start},
+			{key: "s", label: "stop", action: stop},
+		})
+	})
+}
+
+// showServiceOutput shows the output of a service in a scrollable overlay.
+func (m *home) showServiceOutput(msg serviceOutputMsg) tea.Cmd {
+	m.historyOverlay = overlay.NewHistoryOverlay(msg.title, msg.output)
+	m.historyOverlay.OnDismiss = func() {
+		m.state = stateDefault
+		m.menu.SetState(ui.StateDefault)
+		m.historyOverlay = nil
+	}
+	m.state = stateHistory
+	return tea.WindowSize()
+}
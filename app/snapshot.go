@@ -0,0 +1,161 @@
+package app
+
+import (
+	"claude-squad/config"
+	"claude-squad/log"
+	"claude-squad/ui"
+	"claude-squad/ui/overlay"
+	"fmt"
+	"os"
+	"os/signal"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// snapshotTickMsg triggers a scheduled snapshot of the UI state
+type snapshotTickMsg struct{}
+
+// snapshotSignalMsg asks for a snapshot of the UI state right away, on a signal
+type snapshotSignalMsg struct{}
+
+// restoreSnapshotMsg restores the UI state of a run that crashed
+type restoreSnapshotMsg struct {
+	snapshot *config.Snapshot
+}
+
+// scheduleSnapshot waits for the configured snapshot interval, then triggers a snapshot.
+func (m *home) scheduleSnapshot() tea.Cmd {
+	interval := time.Duration(m.appConfig.SnapshotIntervalSeconds) * time.Second
+	return tea.Tick(interval, func(time.Time) tea.Msg {
+		return snapshotTickMsg{}
+	})
+}
+
+// snapshot captures the UI state: the selected instance, the tab and scroll positions shown, and
+// a prompt being written for it.
+func (m *home) snapshot() *config.Snapshot {
+	snapshot := &config.Snapshot{
+		SavedAt: time.Now(),
+		Tab:     ui.TabName(m.tabbedWindow.ActiveTab()),
+	}
+	snapshot.DiffOffset, snapshot.PreviewOffset, snapshot.PreviewScrolling = m.tabbedWindow.ScrollPositions()
+	if selected := m.list.GetSelectedInstance(); selected != nil && selected.Started() {
+		snapshot.Selected = selected.Title
+		// An instance named from its prompt doesn't exist until the prompt is sent
+		if m.state == statePrompt && m.textInputOverlay != nil && !m.autoNamePending {
+			snapshot.PromptDraft = m.textInputOverlay.GetValue()
+		}
+	}
+	return snapshot
+}
+
+// saveSnapshot saves the instances, with their queued prompts, and snapshots the UI state, so a
+// crash or a killed terminal loses at most one interval of either.
+func (m *home) saveSnapshot() {
+	if err := m.storage.SaveInstances(m.list.GetInstances()); err != nil {
+		log.WarningLog.Printf("failed to save instances for the snapshot: %v", err)
+	}
+	if err := config.SaveSnapshot(m.snapshot()); err != nil {
+		log.WarningLog.Printf("failed to snapshot the UI state: %v", err)
+	}
+}
+
+// finishSnapshots runs once the UI stopped, saving the instances' scrollback. A normal quit
+// removes the snapshot; any other exit, like a signal, leaves a last one to restore at the next
+// start.
+func (m *home) finishSnapshots() {
+	// The tmux sessions outlive the UI, but not a reboot
+	saveScrollback(m.list.GetInstances())
+	if !m.cleanExit {
+		m.saveSnapshot()
+		return
+	}
+	if err := config.ClearSnapshot(); err != nil {
+		log.WarningLog.Printf("failed to clear the UI snapshot: %v", err)
+	}
+}
+
+// offerSnapshotRestore asks to restore the UI state if the last run left a snapshot behind,
+// meaning it crashed or was killed.
+func (m *home) offerSnapshotRestore() {
+	snapshot, err := config.LoadSnapshot()
+	if err != nil {
+		log.WarningLog.Printf("failed to load the UI snapshot: %v", err)
+		return
+	}
+	if snapshot == nil || m.findInstance(snapshot.Selected) < 0 {
+		return
+	}
+	message := fmt.Sprintf("claude-squad didn't exit cleanly. Restore the view of '%s' from %s?",
+		snapshot.Selected, snapshot.SavedAt.Format("15:04:05"))
+	if snapshot.PromptDraft != "" {
+		message += " This reopens the unsent prompt."
+	}
+	m.confirmAction(message, func() tea.Msg {
+		return restoreSnapshotMsg{snapshot: snapshot}
+	})
+}
+
+// findInstance returns the index of the instance titled title in the list, or -1.
+func (m *home) findInstance(title string) int {
+	for idx, instance := range m.list.GetInstances() {
+		if instance.Title == title {
+			return idx
+		}
+	}
+	return -1
+}
+
+// restoreSnapshot selects the snapshot's instance and shows it as it was: on the same tab and
+// scroll positions, with the prompt that was being written.
+func (m *home) restoreSnapshot(snapshot *config.Snapshot) tea.Cmd {
+	idx := m.findInstance(snapshot.Selected)
+	if idx < 0 {
+		return m.handleError(fmt.Errorf("session '%s' no longer exists", snapshot.Selected))
+	}
+	instance := m.list.GetInstances()[idx]
+	m.list.SetSelectedInstance(idx)
+	m.showInstanceTab(instance)
+	if tab, ok := ui.TabByName(snapshot.Tab); ok {
+		m.tabbedWindow.SetTab(tab)
+		m.menu.SetInDiffTab(m.tabbedWindow.IsInDiffTab())
+	}
+	cmd := m.instanceChanged()
+	m.tabbedWindow.RestoreScrollPositions(snapshot.DiffOffset, snapshot.PreviewOffset, snapshot.PreviewScrolling)
+
+	if snapshot.PromptDraft != "" && instance.Started() {
+		m.state = statePrompt
+		m.menu.SetState(ui.StatePrompt)
+		m.textInputOverlay = overlay.NewTextInputOverlay("Enter prompt", snapshot.PromptDraft)
+		return tea.Batch(cmd, tea.WindowSize())
+	}
+	return cmd
+}
+
+// forwardSnapshotSignals snapshots the UI state when the process gets a snapshot signal, and
+// quits on the ones that end it, after which Run saves a last snapshot. It returns a function
+// that stops forwarding.
+func forwardSnapshotSignals(p *tea.Program) (stop func()) {
+	signals := make(chan os.Signal, 1)
+	notifySnapshotSignals(signals)
+	done := make(chan struct{})
+	go func() {
+		for {
+			select {
+			case <-done:
+				return
+			case sig := <-signals:
+				if quitsOnSignal(sig) {
+					p.Quit()
+					continue
+				}
+				p.Send(snapshotSignalMsg{})
+			}
+		}
+	}()
+	return func() {
+		signal.Stop(signals)
+		close(done)
+	}
+}
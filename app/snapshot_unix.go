@@ -0,0 +1,20 @@
+//go:build !windows
+
+package app
+
+import (
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+// notifySnapshotSignals relays SIGUSR1, which asks for a snapshot, and SIGHUP, which a closed
+// terminal sends, to ch.
+func notifySnapshotSignals(ch chan<- os.Signal) {
+	signal.Notify(ch, syscall.SIGUSR1, syscall.SIGHUP)
+}
+
+// quitsOnSignal returns true if the app should quit on sig after snapshotting.
+func quitsOnSignal(sig os.Signal) bool {
+	return sig == syscall.SIGHUP
+}
@@ -0,0 +1,14 @@
+//go:build windows
+
+package app
+
+import "os"
+
+// notifySnapshotSignals does nothing, since Windows has no signal to ask for a snapshot. Closing
+// the console sends SIGTERM, which quits the app and so snapshots anyway.
+func notifySnapshotSignals(ch chan<- os.Signal) {}
+
+// quitsOnSignal returns true if the app should quit on sig after snapshotting.
+func quitsOnSignal(sig os.Signal) bool {
+	return false
+}
@@ -0,0 +1,87 @@
+package app
+
+import (
+	"claude-squad/session/git"
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// stagingTarget returns the selected instance's worktree and the hunk at the top of the diff
+// tab's staging view. The worktree is nil outside the staging view, along with a command
+// reporting any error.
+func (m *home) stagingTarget() (worktree *git.GitWorktree, hunk git.DiffHunk, staged bool, cmd tea.Cmd) {
+	hunk, staged, ok := m.tabbedWindow.SelectedHunk()
+	selected := m.list.GetSelectedInstance()
+	if !ok || selected == nil {
+		return nil, hunk, false, nil
+	}
+	worktree, err := selected.GetGitWorktree()
+	if err != nil {
+		return nil, hunk, false, m.handleError(err)
+	}
+	return worktree, hunk, staged, nil
+}
+
+// toggleStageHunk stages the unstaged hunk at the top of the staging view, or unstages it if it is
+// staged.
+func (m *home) toggleStageHunk() (tea.Model, tea.Cmd) {
+	worktree, hunk, staged, cmd := m.stagingTarget()
+	if worktree == nil {
+		return m, cmd
+	}
+	var err error
+	if staged {
+		err = worktree.UnstageHunk(hunk)
+	} else {
+		err = worktree.StageHunk(hunk)
+	}
+	if err != nil {
+		return m, m.handleError(err)
+	}
+	return m, m.instanceChanged()
+}
+
+// toggleStageFile stages every change to the file at the top of the staging view, or unstages
+// them if the file's hunk there is staged.
+func (m *home) toggleStageFile() (tea.Model, tea.Cmd) {
+	worktree, hunk, staged, cmd := m.stagingTarget()
+	if worktree == nil {
+		return m, cmd
+	}
+	var err error
+	if staged {
+		err = worktree.UnstageFile(hunk.Path)
+	} else {
+		err = worktree.StageFile(hunk.Path)
+	}
+	if err != nil {
+		return m, m.handleError(err)
+	}
+	return m, m.instanceChanged()
+}
+
+// discardHunk reverts the unstaged hunk at the top of the staging view, or every unstaged change
+// to its file, once confirmed.
+func (m *home) discardHunk(wholeFile bool) (tea.Model, tea.Cmd) {
+	worktree, hunk, staged, cmd := m.stagingTarget()
+	if worktree == nil {
+		return m, cmd
+	}
+	if staged {
+		return m, m.handleError(fmt.Errorf("only unstaged changes can be discarded; unstage the hunk first"))
+	}
+
+	message := fmt.Sprintf("Discard this hunk of %s? This can't be undone.", hunk.Path)
+	discard := func() error { return worktree.DiscardHunk(hunk) }
+	if wholeFile {
+		message = fmt.Sprintf("Discard all unstaged changes to %s? This can't be undone.", hunk.Path)
+		discard = func() error { return worktree.DiscardFile(hunk.Path) }
+	}
+	return m, m.confirmAction(message, func() tea.Msg {
+		if err := discard(); err != nil {
+			return err
+		}
+		return instanceChangedMsg{}
+	})
+}
@@ -0,0 +1,100 @@
+package app
+
+import (
+	"claude-squad/log"
+	"claude-squad/session"
+	"claude-squad/ui"
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// storageChangedMsg is sent when the stored instances' file changed, possibly by another
+// claude-squad process
+type storageChangedMsg struct{}
+
+// watchStorage starts watching storage for changes by other processes. The UI works without
+// it, it just doesn't pick up those changes.
+func (m *home) watchStorage() {
+	changes, err := m.storage.Watch(m.ctx)
+	if err != nil {
+		log.WarningLog.Printf("failed to watch storage for changes: %v", err)
+		return
+	}
+	m.storageChanges = changes
+}
+
+// waitForStorageChange waits for the next change to storage.
+func (m *home) waitForStorageChange() tea.Cmd {
+	if m.storageChanges == nil {
+		return nil
+	}
+	changes := m.storageChanges
+	return func() tea.Msg {
+		if _, ok := <-changes; !ok {
+			return nil
+		}
+		return storageChangedMsg{}
+	}
+}
+
+// refreshFromStorage brings the list up to date with the instances other processes added to or
+// removed from storage. Changes this process saved itself are ignored.
+func (m *home) refreshFromStorage() tea.Cmd {
+	changed, err := m.storage.Changed()
+	if err != nil {
+		log.WarningLog.Printf("failed to check storage for changes: %v", err)
+		return nil
+	}
+	if !changed {
+		return nil
+	}
+	stored, removed, err := m.storage.ReloadInstanceData()
+	if err != nil {
+		return m.handleError(fmt.Errorf("failed to reload instances: %w", err))
+	}
+
+	var added, gone []string
+	for _, data := range stored {
+		if m.findInstance(data.Title) >= 0 {
+			continue
+		}
+		instance, err := session.FromInstanceData(data)
+		if err != nil {
+			log.WarningLog.Printf("failed to load instance %s added elsewhere: %v", data.Title, err)
+			continue
+		}
+		m.list.AddInstance(instance)()
+		if m.autoYes {
+			instance.AutoYes = true
+		}
+		added = append(added, data.Title)
+	}
+	for _, title := range removed {
+		idx := m.findInstance(title)
+		if idx < 0 {
+			continue
+		}
+		instance := m.list.GetInstances()[idx]
+		// This process is already deleting it
+		if instance.Status == session.Deleting {
+			continue
+		}
+		m.list.RemoveInstance(instance)
+		gone = append(gone, title)
+	}
+	if len(added) == 0 && len(gone) == 0 {
+		return nil
+	}
+
+	var changes []string
+	if len(added) > 0 {
+		changes = append(changes, "added "+strings.Join(added, ", "))
+	}
+	if len(gone) > 0 {
+		changes = append(changes, "removed "+strings.Join(gone, ", "))
+	}
+	return tea.Batch(m.instanceChanged(),
+		m.notify(ui.ToastInfo, "Another claude-squad "+strings.Join(changes, " and ")))
+}
@@ -0,0 +1,314 @@
+package app
+
+import (
+	"claude-squad/config"
+	"claude-squad/keys"
+	"claude-squad/log"
+	"claude-squad/session"
+	"claude-squad/session/git"
+	"claude-squad/ui"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const (
+	// suggestTestsTimeout bounds a run of the configured suggest tests command. The summarizer
+	// has its own timeout.
+	suggestTestsTimeout = 5 * time.Minute
+	// maxSuggestTestsDiff is the largest diff, in bytes, included in a suggest tests prompt
+	maxSuggestTestsDiff = 60000
+	// maxConventionFiles is how many existing test files are listed as conventions to follow
+	maxConventionFiles = 8
+	// maxExampleTestLines is how much of the closest existing test file is shown as an example
+	maxExampleTestLines = 80
+)
+
+var (
+	suggestedFileStartRe = regexp.MustCompile(`^=== FILE: (.+?) ===\s*$`)
+	suggestedFileEnd     = "=== END FILE ==="
+)
+
+// suggestedTestFile is a test file proposed by the suggest tests command.
+type suggestedTestFile struct {
+	path    string
+	content string
+}
+
+// suggestedTestsMsg carries the reply of the suggest tests command.
+type suggestedTestsMsg struct {
+	instance *session.Instance
+	files    []suggestedTestFile
+	output   string
+	err      error
+}
+
+// suggestedTestsWrittenMsg is sent once accepted test files are written to the worktree.
+type suggestedTestsWrittenMsg struct {
+	instance *session.Instance
+	paths    []string
+	run      bool
+}
+
+// isTestFile returns true if the path follows a common test file naming convention.
+func isTestFile(p string) bool {
+	base := path.Base(p)
+	switch {
+	case strings.HasSuffix(base, "_test.go"),
+		strings.Contains(base, ".test."),
+		strings.Contains(base, ".spec."),
+		strings.HasPrefix(base, "test_") && strings.HasSuffix(base, ".py"),
+		strings.HasSuffix(base, "_test.py"),
+		strings.HasSuffix(base, "_spec.rb"):
+		return true
+	}
+	return strings.Contains("/"+p, "/__tests__/")
+}
+
+// changedFilesInDiff returns the files a unified diff touches, in order.
+func changedFilesInDiff(diff string) []string {
+	var files []string
+	for _, line := range strings.Split(diff, "\n") {
+		if strings.HasPrefix(line, "+++ b/") {
+			files = append(files, strings.TrimPrefix(line, "+++ b/"))
+		}
+	}
+	return files
+}
+
+// conventionTestFiles picks existing test files to show as conventions, preferring those in the
+// directories of the changed files.
+func conventionTestFiles(tracked, changed []string) []string {
+	changedDirs := make(map[string]bool)
+	for _, file := range changed {
+		changedDirs[path.Dir(file)] = true
+	}
+
+	var near, far []string
+	for _, file := range tracked {
+		if !isTestFile(file) {
+			continue
+		}
+		if changedDirs[path.Dir(file)] {
+			near = append(near, file)
+		} else {
+			far = append(far, file)
+		}
+	}
+	picked := append(near, far...)
+	if len(picked) > maxConventionFiles {
+		picked = picked[:maxConventionFiles]
+	}
+	return picked
+}
+
+// buildSuggestTestsPrompt asks for tests covering diff. If writeFiles is true the agent writes the
+// files itself; otherwise it replies with them in the format parseSuggestedTestFiles reads.
+func buildSuggestTestsPrompt(diff string, conventions []string, examplePath, example string, writeFiles bool) string {
+	var prompt strings.Builder
+	prompt.WriteString("Suggest tests that cover the change below. Follow the conventions of the repository's existing tests: framework, file placement, naming and style. Only add or extend test files; don't change the code under test.\n\n")
+	if writeFiles {
+		prompt.WriteString("Write the test files into the repository, but don't run them.\n\n")
+	} else {
+		prompt.WriteString("Reply only with complete test files, each in this format:\n")
+		prompt.WriteString("=== FILE: <path relative to the repository root> ===\n<file content>\n" + suggestedFileEnd + "\n\n")
+	}
+
+	if len(conventions) > 0 {
+		prompt.WriteString("Existing test files:\n")
+		for _, file := range conventions {
+			prompt.WriteString("- " + file + "\n")
+		}
+		prompt.WriteString("\n")
+	}
+	if example != "" {
+		prompt.WriteString(fmt.Sprintf("Beginning of %s:\n```\n%s\n```\n\n", examplePath, example))
+	}
+
+	if len(diff) > maxSuggestTestsDiff {
+		diff = diff[:maxSuggestTestsDiff] + "\n... (diff truncated)"
+	}
+	prompt.WriteString("Change:\n```diff\n" + diff + "\n```\n")
+	return prompt.String()
+}
+
+// parseSuggestedTestFiles extracts the files of a reply in the format requested by
+// buildSuggestTestsPrompt. Text outside file blocks is ignored.
+func parseSuggestedTestFiles(output string) []suggestedTestFile {
+	var files []suggestedTestFile
+	var current *suggestedTestFile
+	var body []string
+	for _, line := range strings.Split(output, "\n") {
+		if current == nil {
+			if match := suggestedFileStartRe.FindStringSubmatch(line); match != nil {
+				current = &suggestedTestFile{path: strings.TrimSpace(match[1])}
+				body = nil
+			}
+			continue
+		}
+		if strings.TrimSpace(line) == suggestedFileEnd {
+			current.content = strings.Join(body, "\n") + "\n"
+			files = append(files, *current)
+			current = nil
+			continue
+		}
+		body = append(body, line)
+	}
+	return files
+}
+
+// resolveSuggestedPath joins a proposed path to the worktree, rejecting paths that escape it.
+func resolveSuggestedPath(worktreePath, p string) (string, error) {
+	clean := filepath.Clean(filepath.FromSlash(p))
+	if filepath.IsAbs(clean) || clean == "." || clean == ".." || strings.HasPrefix(clean, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("refusing to write %s outside the worktree", p)
+	}
+	return filepath.Join(worktreePath, clean), nil
+}
+
+// suggestTests asks for tests covering the selected instance's diff. With a suggest tests command
+// configured, the reply is offered for writing into the worktree; otherwise the instance's agent
+// is asked to write the tests itself.
+func (m *home) suggestTests() tea.Cmd {
+	selected := m.list.GetSelectedInstance()
+	if selected == nil {
+		return nil
+	}
+	if !selected.Started() || selected.Paused() {
+		return m.handleError(fmt.Errorf("instance '%s' must be running to suggest tests", selected.Title))
+	}
+	worktree, err := selected.GetGitWorktree()
+	if err != nil {
+		return m.handleError(fmt.Errorf("failed to get git worktree: %w", err))
+	}
+	stats := worktree.Diff()
+	if stats.Error != nil {
+		return m.handleError(fmt.Errorf("failed to get diff: %w", stats.Error))
+	}
+	if stats.IsEmpty() {
+		return m.notify(ui.ToastInfo, "No changes to suggest tests for")
+	}
+
+	command, timeout := "", suggestTestsTimeout
+	if m.appConfig != nil {
+		command = m.appConfig.SuggestTestsCommand
+		if command == "" {
+			command, timeout = m.appConfig.SummarizerFor(config.SummarizeTests)
+		}
+	}
+	prompt := localizePrompt(selected, suggestTestsPrompt(worktree, stats.Content, command == ""))
+
+	if command == "" {
+		if err := selected.SendPromptToAI(prompt); err != nil {
+			return m.handleError(fmt.Errorf("failed to send test request to the agent: %w", err))
+		}
+		return m.notify(ui.ToastInfo, fmt.Sprintf("Asked the agent for tests; press %s to run them when it's done",
+			keys.GlobalkeyBindings[keys.KeyTest].Help().Key))
+	}
+
+	worktreePath := worktree.GetWorktreePath()
+	return tea.Batch(
+		m.notify(ui.ToastInfo, fmt.Sprintf("Asking '%s' for tests...", command)),
+		func() tea.Msg {
+			output, err := runPromptCommand(command, worktreePath, prompt, timeout)
+			if err != nil {
+				return suggestedTestsMsg{instance: selected, err: err}
+			}
+			return suggestedTestsMsg{instance: selected, files: parseSuggestedTestFiles(output), output: output}
+		},
+	)
+}
+
+// suggestTestsPrompt gathers the repository's test conventions and builds the prompt.
+func suggestTestsPrompt(worktree *git.GitWorktree, diff string, writeFiles bool) string {
+	tracked, err := worktree.ListTrackedFiles()
+	if err != nil {
+		log.WarningLog.Printf("suggesting tests without conventions: %v", err)
+	}
+	conventions := conventionTestFiles(tracked, changedFilesInDiff(diff))
+
+	examplePath, example := "", ""
+	if len(conventions) > 0 {
+		examplePath = conventions[0]
+		if data, err := os.ReadFile(filepath.Join(worktree.GetWorktreePath(), examplePath)); err == nil {
+			lines := strings.Split(string(data), "\n")
+			if len(lines) > maxExampleTestLines {
+				lines = lines[:maxExampleTestLines]
+			}
+			example = strings.Join(lines, "\n")
+		}
+	}
+	return buildSuggestTestsPrompt(diff, conventions, examplePath, example, writeFiles)
+}
+
+// handleSuggestedTests offers to write the proposed test files into the worktree.
+func (m *home) handleSuggestedTests(msg suggestedTestsMsg) tea.Cmd {
+	if msg.err != nil {
+		return m.handleError(fmt.Errorf("failed to suggest tests: %w", msg.err))
+	}
+	if len(msg.files) == 0 {
+		log.WarningLog.Printf("suggest tests reply had no file blocks:\n%s", msg.output)
+		return m.notify(ui.ToastWarning, "The reply contained no test files (see the log for the output)")
+	}
+	worktree, err := msg.instance.GetGitWorktree()
+	if err != nil {
+		return m.handleError(fmt.Errorf("failed to get git worktree: %w", err))
+	}
+	worktreePath := worktree.GetWorktreePath()
+
+	var listing strings.Builder
+	for _, file := range msg.files {
+		target, err := resolveSuggestedPath(worktreePath, file.path)
+		if err != nil {
+			return m.handleError(err)
+		}
+		state := "new"
+		if _, err := os.Stat(target); err == nil {
+			state = "overwrites existing"
+		}
+		listing.WriteString(fmt.Sprintf("\n• %s (%s, %d lines)", file.path, state, strings.Count(file.content, "\n")))
+	}
+
+	write := func(run bool) tea.Cmd {
+		return func() tea.Msg {
+			paths := make([]string, 0, len(msg.files))
+			for _, file := range msg.files {
+				target, _ := resolveSuggestedPath(worktreePath, file.path)
+				if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+					return fmt.Errorf("failed to create directory for %s: %w", file.path, err)
+				}
+				if err := os.WriteFile(target, []byte(file.content), 0644); err != nil {
+					return fmt.Errorf("failed to write %s: %w", file.path, err)
+				}
+				paths = append(paths, file.path)
+			}
+			return suggestedTestsWrittenMsg{instance: msg.instance, paths: paths, run: run}
+		}
+	}
+
+	return m.confirmChoices(
+		fmt.Sprintf("Suggested tests for '%s':%s", msg.instance.Title, listing.String()),
+		[]confirmChoice{
+			{key: "w", label: "write and run tests", action: write(true)},
+			{key: "o", label: "write only", action: write(false)},
+		},
+	)
+}
+
+// handleSuggestedTestsWritten reports the written files and runs them in the test pane.
+func (m *home) handleSuggestedTestsWritten(msg suggestedTestsWrittenMsg) tea.Cmd {
+	cmds := []tea.Cmd{
+		m.instanceChanged(),
+		m.showSuccess(fmt.Sprintf("Wrote %d test file(s)", len(msg.paths))),
+	}
+	if msg.run {
+		cmds = append(cmds, m.runTests(msg.instance))
+	}
+	return tea.Batch(cmds...)
+}
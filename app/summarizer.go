@@ -0,0 +1,130 @@
+package app
+
+import (
+	"bytes"
+	"claude-squad/config"
+	"claude-squad/log"
+	"claude-squad/session/git"
+	"context"
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// maxSummarizerInput is the most input, in bytes, passed to the summarizer after its prompt
+const maxSummarizerInput = 100000
+
+// The prompts the summarizer gets before the input of each feature
+const (
+	commitMessagePrompt = "Write a git commit message for the changes below: a summary line of at most 72 " +
+		"characters in the imperative mood and, if the changes need explaining, a blank line and a short body. " +
+		"Reply with the commit message only."
+	prDescriptionPrompt = "Write a pull request for the branch whose commits and diff are below. Reply with " +
+		"its title on the first line, then a blank line and a description of the changes in markdown, and " +
+		"nothing else."
+	diffSummaryPrompt = "Summarize the changes in the diff below for a reviewer, in a few sentences or " +
+		"bullet points. Reply with the summary only."
+)
+
+// runPromptCommand runs command through the shell in dir with prompt on stdin, killing it after
+// timeout, and returns what it printed.
+func runPromptCommand(command, dir, prompt string, timeout time.Duration) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, "sh", "-c", command)
+	cmd.Dir = dir
+	cmd.Stdin = strings.NewReader(prompt)
+	// Programs the shell started may hold the output open after it is killed
+	cmd.WaitDelay = time.Second
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if ctx.Err() != nil {
+			return "", fmt.Errorf("'%s' timed out after %s", command, timeout)
+		}
+		return "", fmt.Errorf("'%s' failed: %w: %s", command, err, strings.TrimSpace(stderr.String()))
+	}
+	return stdout.String(), nil
+}
+
+// summarize runs the summarizer configured for the feature in dir with the prompt and input, and
+// returns what it wrote. It returns "" without an error if the feature has no summarizer.
+func (m *home) summarize(feature, dir, prompt, input string) (string, error) {
+	if m.appConfig == nil {
+		return "", nil
+	}
+	command, timeout := m.appConfig.SummarizerFor(feature)
+	if command == "" {
+		return "", nil
+	}
+	if len(input) > maxSummarizerInput {
+		input = input[:maxSummarizerInput] + "\n[... cut]\n"
+	}
+	output, err := runPromptCommand(command, dir, prompt+"\n\n"+input, timeout)
+	if err != nil {
+		return "", fmt.Errorf("summarizer for %s failed: %w", feature, err)
+	}
+	return strings.TrimSpace(output), nil
+}
+
+// hasSummarizer returns whether the feature has a summarizer configured.
+func (m *home) hasSummarizer(feature string) bool {
+	if m.appConfig == nil {
+		return false
+	}
+	command, _ := m.appConfig.SummarizerFor(feature)
+	return command != ""
+}
+
+// summarizeCommitMessage has the summarizer write the commit message for the worktree's pending
+// changes. It returns fallback if there is no summarizer or it fails.
+func (m *home) summarizeCommitMessage(worktree *git.GitWorktree, fallback string) string {
+	if !m.hasSummarizer(config.SummarizeCommitMessage) {
+		return fallback
+	}
+	changes, err := worktree.PendingChanges()
+	if err != nil || changes == "" {
+		return fallback
+	}
+	message, err := m.summarize(config.SummarizeCommitMessage, worktree.GetWorktreePath(), commitMessagePrompt, changes)
+	if err != nil || message == "" {
+		log.WarningLog.Printf("using the built-in commit message: %v", err)
+		return fallback
+	}
+	return message
+}
+
+// describePullRequest has the summarizer write the title and description of a pull request for
+// the worktree's branch. The title is empty if there is no summarizer or it fails, in which case
+// the forge fills them from the commits.
+func (m *home) describePullRequest(worktree *git.GitWorktree) (title, body string) {
+	if !m.hasSummarizer(config.SummarizePRDescription) {
+		return "", ""
+	}
+	commits, err := worktree.BranchCommits()
+	if err != nil {
+		log.WarningLog.Printf("describing the pull request without commits: %v", err)
+	}
+	stats := worktree.Diff()
+	if stats.Error != nil {
+		log.WarningLog.Printf("describing the pull request without a diff: %v", stats.Error)
+	}
+	input := "Commits:\n\n" + commits + "\n\nDiff:\n\n" + stats.Content
+	description, err := m.summarize(config.SummarizePRDescription, worktree.GetWorktreePath(), prDescriptionPrompt, input)
+	if err != nil {
+		log.WarningLog.Printf("filling the pull request from its commits: %v", err)
+		return "", ""
+	}
+	return splitPRDescription(description)
+}
+
+// splitPRDescription splits the summarizer's pull request into the title on its first line,
+// without markdown heading marks, and the description after it.
+func splitPRDescription(text string) (title, body string) {
+	title, body, _ = strings.Cut(strings.TrimSpace(text), "\n")
+	title = strings.TrimSpace(strings.TrimLeft(title, "# "))
+	return title, strings.TrimSpace(body)
+}
@@ -0,0 +1,96 @@
+package app
+
+import (
+	"claude-squad/config"
+	"claude-squad/session"
+	"claude-squad/ui"
+	"claude-squad/ui/overlay"
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// showTemplates lists the configured instance templates and starts naming a new instance from
+// the chosen one.
+func (m *home) showTemplates() tea.Cmd {
+	templates := m.appConfig.Templates
+	if len(templates) == 0 {
+		return m.notify(ui.ToastInfo, `No templates yet. Add them under "templates" in the config file.`)
+	}
+	if limit := m.instanceLimit(); m.list.NumInstances() >= limit {
+		return m.handleError(fmt.Errorf("you can't create more than %d instances", limit))
+	}
+
+	items := make([]overlay.ListItem, len(templates))
+	for i, template := range templates {
+		var details []string
+		if template.Program != "" {
+			details = append(details, template.Program)
+		}
+		if template.BaseBranch != "" {
+			details = append(details, "from "+template.BaseBranch)
+		}
+		if template.AutoYes {
+			details = append(details, "auto-yes")
+		}
+		if template.DefaultTab != "" {
+			details = append(details, "opens on "+template.DefaultTab)
+		}
+		if template.Prompt != "" {
+			prompt := strings.Join(strings.Fields(template.Prompt), " ")
+			if len(prompt) > 60 {
+				prompt = prompt[:57] + "..."
+			}
+			details = append(details, fmt.Sprintf("%q", prompt))
+		}
+		items[i] = overlay.ListItem{Title: template.Name, Description: strings.Join(details, " • ")}
+	}
+
+	return m.selectFromList("New Session From Template", items, func(idx int) tea.Cmd {
+		return m.newInstanceFromTemplate(templates[idx])
+	})
+}
+
+// newInstanceFromTemplate adds an instance configured by template to the list and starts naming
+// it, suggesting a name based on the template.
+func (m *home) newInstanceFromTemplate(template config.InstanceTemplate) tea.Cmd {
+	program := template.Program
+	if program == "" {
+		program = m.program
+	}
+	if err := m.policy.CheckProgram(program); err != nil {
+		return m.handleError(err)
+	}
+	instance, err := session.NewInstance(session.InstanceOptions{
+		Title:        "",
+		Path:         m.instanceRepoPath(),
+		Program:      program,
+		AutoYes:      template.AutoYes,
+		BaseBranch:   template.BaseBranch,
+		BranchPrefix: template.BranchPrefix,
+		Host:         m.remoteHost,
+	})
+	if err != nil {
+		return m.handleError(err)
+	}
+	instance.Prompt = template.Prompt
+	instance.Tab = template.DefaultTab
+
+	// Suggest a name based on the template; it can be edited before pressing enter
+	taken := make(map[string]bool)
+	for _, other := range m.list.GetInstances() {
+		taken[other.Title] = true
+	}
+	if title := titleFromPrompt(template.Name); title != "" {
+		if err := instance.SetTitle(uniqueTitle(title, taken)); err != nil {
+			return m.handleError(err)
+		}
+	}
+
+	m.newInstanceFinalizer = m.list.AddInstance(instance)
+	m.list.SetSelectedInstance(m.list.NumInstances() - 1)
+	m.state = stateNew
+	m.menu.SetState(ui.StateNewInstance)
+	return m.notify(ui.ToastInfo, fmt.Sprintf("Creating from template '%s': edit the name or press enter", template.Name))
+}
@@ -0,0 +1,73 @@
+package app
+
+import (
+	"claude-squad/config"
+	"claude-squad/session"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// tickRates returns how often instances are checked and the preview is redrawn.
+func (m *home) tickRates() config.TickRates {
+	if m.appConfig == nil {
+		return config.DefaultTickRates
+	}
+	return m.appConfig.GetTickRates()
+}
+
+// tickUpdateMetadata schedules the next look at the instances' metadata. Capturing the output of
+// every instance is expensive, so each instance is only checked as often as its rate asks.
+func tickUpdateMetadata(interval time.Duration) tea.Cmd {
+	return func() tea.Msg {
+		time.Sleep(interval)
+		return tickUpdateMetadataMessage{}
+	}
+}
+
+// previewTick schedules the next redraw of the selected instance's preview.
+func previewTick(interval time.Duration) tea.Cmd {
+	return func() tea.Msg {
+		time.Sleep(interval)
+		return previewTickMsg{}
+	}
+}
+
+// isWorking returns true if the instance's agent is working or starting up.
+func isWorking(instance *session.Instance) bool {
+	return instance.Status == session.Running || instance.Status == session.Loading
+}
+
+// previewInterval returns how long to wait before redrawing the preview again: fast while the
+// selected instance's agent works, slower while nothing in it should change.
+func (m *home) previewInterval() time.Duration {
+	selected := m.list.GetSelectedInstance()
+	return m.tickRates().PreviewInterval(selected != nil && isWorking(selected))
+}
+
+// metadataDue returns true if the instance is due for a check at now by its rate, recording the
+// check. Half a tick of slack keeps a check from slipping to the next tick by a millisecond.
+func (m *home) metadataDue(instance *session.Instance, now time.Time, rates config.TickRates) bool {
+	if m.lastMetadataCheck == nil {
+		m.lastMetadataCheck = make(map[*session.Instance]time.Time)
+	}
+	interval := rates.MetadataInterval(instance == m.list.GetSelectedInstance(), isWorking(instance))
+	if last, ok := m.lastMetadataCheck[instance]; ok && now.Sub(last)+rates.MetadataTick()/2 < interval {
+		return false
+	}
+	m.lastMetadataCheck[instance] = now
+	return true
+}
+
+// forgetMetadataChecks drops the check times of instances no longer in the list.
+func (m *home) forgetMetadataChecks(instances []*session.Instance) {
+	listed := make(map[*session.Instance]bool, len(instances))
+	for _, instance := range instances {
+		listed[instance] = true
+	}
+	for instance := range m.lastMetadataCheck {
+		if !listed[instance] {
+			delete(m.lastMetadataCheck, instance)
+		}
+	}
+}
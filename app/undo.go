@@ -0,0 +1,113 @@
+package app
+
+import (
+	"claude-squad/keys"
+	"claude-squad/session"
+	"claude-squad/session/git"
+	"claude-squad/ui"
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// maxUndo is how many operations are kept to undo, the oldest dropped first.
+const maxUndo = 20
+
+// undoEntry is a destructive operation that can be undone.
+type undoEntry struct {
+	// description names the operation, e.g. "kill of 'my-task'"
+	description string
+	// killed brings back a killed instance
+	killed *session.KilledInstance
+	// reset is the instance reset to origin and the state of its worktree before, to go back to
+	reset      *session.Instance
+	resetState git.WorktreeState
+}
+
+// undoneMsg reports an undone operation, with the instance it brought back, if any.
+type undoneMsg struct {
+	entry    undoEntry
+	instance *session.Instance
+	err      error
+}
+
+// pushUndo records an operation to undo.
+func (m *home) pushUndo(entry undoEntry) {
+	m.undoStack = append(m.undoStack, entry)
+	if len(m.undoStack) > maxUndo {
+		m.undoStack = m.undoStack[len(m.undoStack)-maxUndo:]
+	}
+}
+
+// undo undoes the last destructive operation.
+func (m *home) undo() tea.Cmd {
+	if len(m.undoStack) == 0 {
+		return m.notify(ui.ToastInfo, "Nothing to undo")
+	}
+	entry := m.undoStack[len(m.undoStack)-1]
+	m.undoStack = m.undoStack[:len(m.undoStack)-1]
+
+	switch {
+	case entry.killed != nil:
+		if m.list.NumInstances() >= m.instanceLimit() {
+			return m.handleError(fmt.Errorf("cannot undo the %s: the limit of %d instances is reached",
+				entry.description, m.instanceLimit()))
+		}
+		if err := m.policy.CheckProgram(entry.killed.Data.Program); err != nil {
+			return m.handleError(err)
+		}
+		taken := make(map[string]bool)
+		for _, instance := range m.list.GetInstances() {
+			taken[instance.Title] = true
+		}
+		title := uniqueTitle(entry.killed.Data.Title, taken)
+		killed := *entry.killed
+		return func() tea.Msg {
+			instance, err := killed.Restore(title)
+			return undoneMsg{entry: entry, instance: instance, err: err}
+		}
+	case entry.reset != nil:
+		instance := entry.reset
+		if idx := m.findInstance(instance.Title); idx < 0 || m.list.GetInstances()[idx] != instance {
+			return m.handleError(fmt.Errorf("cannot undo the %s: the session no longer exists", entry.description))
+		}
+		if instance.Paused() {
+			return m.handleError(fmt.Errorf("resume '%s' to undo the %s", instance.Title, entry.description))
+		}
+		return func() tea.Msg {
+			worktree, err := instance.GetGitWorktree()
+			if err == nil {
+				err = worktree.RestoreState(entry.resetState)
+			}
+			return undoneMsg{entry: entry, err: err}
+		}
+	}
+	return nil
+}
+
+// handleUndone adds back the instance an undo restored and tells what was undone.
+func (m *home) handleUndone(msg undoneMsg) tea.Cmd {
+	if msg.err != nil {
+		return m.handleError(fmt.Errorf("failed to undo the %s: %w", msg.entry.description, msg.err))
+	}
+	if msg.instance == nil {
+		return tea.Batch(m.instanceChanged(), m.showSuccess("Undid the "+msg.entry.description))
+	}
+
+	m.list.AddInstance(msg.instance)()
+	if m.autoYes {
+		msg.instance.AutoYes = true
+	}
+	if err := m.storage.SaveInstances(m.list.GetInstances()); err != nil {
+		return m.handleError(err)
+	}
+	m.list.SetSelectedInstance(m.findInstance(msg.instance.Title))
+	return tea.Batch(tea.WindowSize(), m.instanceChanged(), m.showSuccess(fmt.Sprintf(
+		"Undid the %s: '%s' is back on branch %s; press r to resume it",
+		msg.entry.description, msg.instance.Title, msg.instance.Branch)))
+}
+
+// undoKey returns the key that undoes.
+func undoKey() string {
+	return keys.GlobalkeyBindings[keys.KeyUndo].Help().Key
+}
@@ -0,0 +1,84 @@
+package app
+
+import (
+	"claude-squad/log"
+	"claude-squad/notify"
+	"claude-squad/session"
+	"context"
+	"fmt"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// readyNotifyMinBusy is how long an agent must have been working before it becoming ready is
+// reported, so quick replies and the first status check after startup don't send events.
+const readyNotifyMinBusy = 30 * time.Second
+
+// postEvent sends the event to the webhook, logging failures. Nothing is sent without a webhook.
+func postEvent(notifier *notify.Notifier, event notify.Event) {
+	if !notifier.Enabled() {
+		return
+	}
+	if err := notifier.Send(context.Background(), event); err != nil {
+		log.WarningLog.Printf("%v", err)
+	}
+}
+
+// instanceEvent creates an event about the instance.
+func instanceEvent(eventType notify.EventType, instance *session.Instance, message string) notify.Event {
+	return notify.NewEvent(eventType, instance.Title, instance.Branch, message)
+}
+
+// sendEvent returns a command posting the event to the webhook in the background.
+func (m *home) sendEvent(event notify.Event) tea.Cmd {
+	if !m.notifier.Enabled() {
+		return nil
+	}
+	notifier := m.notifier
+	return func() tea.Msg {
+		postEvent(notifier, event)
+		return nil
+	}
+}
+
+// sendInstanceEvent returns a command posting an event about the instance, unless its
+// notifications are muted. Scripts handling the event get it either way.
+func (m *home) sendInstanceEvent(eventType notify.EventType, instance *session.Instance, message string) tea.Cmd {
+	scripts := m.runScripts(scriptEvent(string(eventType), instance, message))
+	if instance.MuteNotifications {
+		return scripts
+	}
+	return tea.Batch(scripts, m.sendEvent(instanceEvent(eventType, instance, message)))
+}
+
+// trackReadiness records when the instance's agent starts working and reports when it has
+// finished a long enough task and settled into Ready.
+func (m *home) trackReadiness(instance *session.Instance) tea.Cmd {
+	if !m.notifier.Enabled() {
+		return nil
+	}
+	if m.busySince == nil {
+		m.busySince = make(map[string]time.Time)
+	}
+	busySince, busy := m.busySince[instance.Title]
+	switch instance.Status {
+	case session.Running:
+		if !busy {
+			m.busySince[instance.Title] = time.Now()
+		}
+		return nil
+	case session.Ready:
+		if !busy || time.Since(instance.ReadySince()) < session.QueueDispatchIdle {
+			return nil
+		}
+		delete(m.busySince, instance.Title)
+		worked := instance.ReadySince().Sub(busySince)
+		if worked < readyNotifyMinBusy {
+			return nil
+		}
+		return m.sendInstanceEvent(notify.EventInstanceReady, instance,
+			fmt.Sprintf("Agent finished after %s and is waiting for input", worked.Round(time.Second)))
+	}
+	return nil
+}
@@ -0,0 +1,69 @@
+package app
+
+import (
+	"claude-squad/session"
+	"claude-squad/ui"
+	"claude-squad/ui/overlay"
+	"fmt"
+	"os"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// showWorktreePathPrompt asks for the path to move the instance's worktree to, starting from its
+// current path.
+func (m *home) showWorktreePathPrompt(instance *session.Instance) tea.Cmd {
+	if !instance.Started() || instance.Paused() {
+		return m.notify(ui.ToastInfo, "Only a running session's worktree can be moved")
+	}
+	worktree, err := instance.GetGitWorktree()
+	if err != nil {
+		return m.handleError(err)
+	}
+	m.movingInstance = instance
+	m.state = stateWorktreePath
+	m.menu.SetState(ui.StateBookmark)
+	m.textInputOverlay = overlay.NewTextInputOverlay(
+		fmt.Sprintf("Move the worktree of '%s' to", instance.Title), worktree.GetWorktreePath())
+	return tea.WindowSize()
+}
+
+// handleWorktreePathState passes key presses to the worktree path prompt and confirms the move
+// once a path is submitted.
+func (m *home) handleWorktreePathState(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if !m.textInputOverlay.HandleKeyPress(msg) {
+		return m, nil
+	}
+	submitted, path := m.textInputOverlay.IsSubmitted(), strings.TrimSpace(m.textInputOverlay.GetValue())
+	instance := m.movingInstance
+	m.textInputOverlay = nil
+	m.movingInstance = nil
+	m.state = stateDefault
+	m.menu.SetState(ui.StateDefault)
+	if !submitted || path == "" || instance == nil {
+		return m, tea.WindowSize()
+	}
+	if rest, ok := strings.CutPrefix(path, "~"); ok {
+		if home, err := os.UserHomeDir(); err == nil {
+			path = home + rest
+		}
+	}
+	return m, tea.Batch(tea.WindowSize(), m.confirmMoveWorktree(instance, path))
+}
+
+// confirmMoveWorktree moves the instance's worktree to path once confirmed, restarting its agent
+// there.
+func (m *home) confirmMoveWorktree(instance *session.Instance, path string) tea.Cmd {
+	move := func() tea.Msg {
+		if err := m.policy.CheckProgram(instance.Program); err != nil {
+			return err
+		}
+		if err := instance.MoveWorktree(path); err != nil {
+			return err
+		}
+		return worktreeRepairedMsg{message: fmt.Sprintf("Moved the worktree of '%s' to %s", instance.Title, path)}
+	}
+	message := fmt.Sprintf("[!] Move the worktree of '%s' to %s? Its agent is restarted there.", instance.Title, path)
+	return m.confirmAction(message, move)
+}
@@ -0,0 +1,84 @@
+package app
+
+import (
+	"claude-squad/session"
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// isPathByte returns true for the bytes that can continue a path in prompt text.
+func isPathByte(b byte) bool {
+	return !strings.ContainsRune(" \t\r\n:;,'\"`()[]{}<>|", rune(b))
+}
+
+// relativizeRepoPaths rewrites the absolute paths into the main checkout at repoPath found in
+// text as paths relative to the worktree root, since the files only exist at those paths in the
+// worktree. Paths into the worktree itself are made relative too, and the bare repository path
+// becomes the worktree path.
+func relativizeRepoPaths(text, repoPath, worktreePath string) string {
+	repoPath, worktreePath = filepath.Clean(repoPath), filepath.Clean(worktreePath)
+	if repoPath == "." || repoPath == worktreePath {
+		return text
+	}
+
+	var out strings.Builder
+	for {
+		idx := strings.Index(text, repoPath)
+		if idx < 0 {
+			out.WriteString(text)
+			return out.String()
+		}
+		end := idx + len(repoPath)
+		for end < len(text) && isPathByte(text[end]) {
+			end++
+		}
+		match := text[idx:end]
+		relative, ok := "", false
+		// Only whole paths: not the tail of a longer path, nor the start of a sibling's name
+		if idx == 0 || !isPathByte(text[idx-1]) {
+			for _, root := range []string{worktreePath, repoPath} {
+				if match == root {
+					relative, ok = worktreePath, true
+					break
+				}
+				if rest, found := strings.CutPrefix(match, root+"/"); found {
+					relative, ok = rest, true
+					break
+				}
+			}
+		}
+		out.WriteString(text[:idx])
+		if ok {
+			out.WriteString(relative)
+		} else {
+			out.WriteString(match)
+		}
+		text = text[end:]
+	}
+}
+
+// worktreeNote tells the agent where it works, so it doesn't look for files in the main checkout.
+func worktreeNote(repoPath, worktreePath string) string {
+	return fmt.Sprintf("Note: you are working in the git worktree at %s, a separate checkout of %s. "+
+		"File paths below are relative to the worktree root; read and edit the files there, not in %s.\n\n",
+		worktreePath, repoPath, repoPath)
+}
+
+// localizePrompt prepares a prompt that references repository files for the instance's agent:
+// paths into the main checkout become worktree-relative, and a note gives the worktree's
+// location. Prompts for instances without a separate worktree are returned as they are.
+func localizePrompt(instance *session.Instance, prompt string) string {
+	if prompt == "" || instance == nil {
+		return prompt
+	}
+	worktree, err := instance.GetGitWorktree()
+	if err != nil {
+		return prompt
+	}
+	repoPath, worktreePath := worktree.GetRepoPath(), worktree.GetWorktreePath()
+	if repoPath == "" || worktreePath == "" || filepath.Clean(repoPath) == filepath.Clean(worktreePath) {
+		return prompt
+	}
+	return worktreeNote(repoPath, worktreePath) + relativizeRepoPaths(prompt, repoPath, worktreePath)
+}
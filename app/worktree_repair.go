@@ -0,0 +1,81 @@
+package app
+
+import (
+	"claude-squad/log"
+	"claude-squad/session"
+	"claude-squad/session/git"
+	"claude-squad/ui"
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// worktreeRepairedMsg reports that a broken instance was repaired, or an instance's worktree
+// moved, with what was done. A repaired or paused instance's diff stats are replaced with stats,
+// computed in the background, since the cached ones describe the deleted worktree.
+type worktreeRepairedMsg struct {
+	message  string
+	instance *session.Instance
+	stats    *git.DiffStats
+}
+
+// checkWorktree marks the instance broken if its worktree directory was deleted, warning once
+// when that happens. It returns true if the instance is broken.
+func (m *home) checkWorktree(instance *session.Instance) (bool, tea.Cmd) {
+	if !instance.CheckWorktree() {
+		return instance.Broken(), nil
+	}
+	return true, m.notify(ui.ToastWarning, fmt.Sprintf(
+		"Worktree of '%s' was deleted. Press enter on it to repair.", instance.Title))
+}
+
+// confirmRepair offers to recreate a broken instance's worktree from its branch, or to pause
+// the instance so it can be resumed later.
+func (m *home) confirmRepair(instance *session.Instance) tea.Cmd {
+	repair := func() tea.Msg {
+		if err := m.policy.CheckProgram(instance.Program); err != nil {
+			return err
+		}
+		if err := instance.RepairWorktree(); err != nil {
+			return err
+		}
+		worktree, err := instance.GetGitWorktree()
+		if err != nil {
+			return err
+		}
+		stats := worktree.Diff()
+		if stats.Error != nil {
+			log.WarningLog.Printf("could not update diff stats: %v", stats.Error)
+			stats = nil
+		}
+		return worktreeRepairedMsg{
+			message:  fmt.Sprintf("Recreated the worktree of '%s' from %s", instance.Title, instance.Branch),
+			instance: instance,
+			stats:    stats,
+		}
+	}
+	pause := func() tea.Msg {
+		if err := instance.PauseBroken(); err != nil {
+			return err
+		}
+		return worktreeRepairedMsg{
+			message:  fmt.Sprintf("Paused '%s'; uncommitted changes in the deleted worktree are lost", instance.Title),
+			instance: instance,
+		}
+	}
+	return m.confirmChoices(fmt.Sprintf("[!] The worktree of '%s' was deleted. Repair it?", instance.Title), []confirmChoice{
+		{key: "r", label: "recreate worktree from branch", action: repair},
+		{key: "p", label: "pause session", action: pause},
+	})
+}
+
+// handleWorktreeRepaired reports the repair or move and refreshes the panes of the instance.
+func (m *home) handleWorktreeRepaired(msg worktreeRepairedMsg) tea.Cmd {
+	if msg.instance != nil {
+		msg.instance.SetDiffStats(msg.stats)
+	}
+	if err := m.storage.SaveInstances(m.list.GetInstances()); err != nil {
+		return m.handleError(err)
+	}
+	return tea.Batch(m.showSuccess(msg.message), tea.WindowSize(), m.instanceChanged())
+}
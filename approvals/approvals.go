@@ -0,0 +1,231 @@
+// Package approvals publishes the confirmations waiting in the UI to a file-based inbox, so
+// external tools such as a chat bot can approve or deny them remotely with signed responses.
+// Everything that happens to a confirmation is appended to an audit log.
+package approvals
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+const (
+	// Approve is the choice confirming a confirmation without labeled choices.
+	Approve = "approve"
+	// Deny is the choice cancelling any confirmation.
+	Deny = "deny"
+)
+
+// Outcome is what happened to a confirmation, as recorded in the audit log.
+type Outcome string
+
+const (
+	// OutcomeRequested is recorded when a confirmation is published to the inbox.
+	OutcomeRequested Outcome = "requested"
+	// OutcomeApproved is recorded when a confirmation is confirmed, with one of its choices.
+	OutcomeApproved Outcome = "approved"
+	// OutcomeDenied is recorded when a confirmation is cancelled.
+	OutcomeDenied Outcome = "denied"
+	// OutcomeRejected is recorded when a response is ignored, for a bad signature or choice.
+	OutcomeRejected Outcome = "rejected"
+	// OutcomeWithdrawn is recorded when a confirmation goes away unanswered, e.g. on quitting.
+	OutcomeWithdrawn Outcome = "withdrawn"
+)
+
+// Choice is an answer a confirmation accepts besides Deny.
+type Choice struct {
+	Key   string `json:"key"`
+	Label string `json:"label"`
+}
+
+// Request is a confirmation waiting for an answer, written to pending/<id>.json.
+type Request struct {
+	ID string `json:"id"`
+	// Instance is the instance selected when the confirmation was asked, if any
+	Instance string   `json:"instance,omitempty"`
+	Message  string   `json:"message"`
+	Choices  []Choice `json:"choices"`
+	// Phrase, when set, has to be sent back with an approval, as it has to be typed in the UI
+	Phrase    string    `json:"phrase,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Response answers a request, written by an external tool to responses/<id>.json. Token is
+// Sign's signature of the ID, choice and phrase with the inbox's secret.
+type Response struct {
+	ID     string `json:"id"`
+	Choice string `json:"choice"`
+	// Phrase repeats the request's phrase to approve it
+	Phrase string `json:"phrase,omitempty"`
+	// By names who answered, for the audit log
+	By    string `json:"by,omitempty"`
+	Token string `json:"token"`
+}
+
+// AuditEntry is a line of the audit log.
+type AuditEntry struct {
+	Time     time.Time `json:"time"`
+	ID       string    `json:"id"`
+	Outcome  Outcome   `json:"outcome"`
+	Instance string    `json:"instance,omitempty"`
+	Message  string    `json:"message,omitempty"`
+	Choice   string    `json:"choice,omitempty"`
+	// By is who answered: the name in the response for remote answers, "local" for the UI
+	By     string `json:"by,omitempty"`
+	Reason string `json:"reason,omitempty"`
+}
+
+// Inbox is the directory confirmations are published to and answered in.
+type Inbox struct {
+	dir    string
+	secret []byte
+}
+
+// Open opens the inbox in dir, creating it and its secret if needed. The secret is kept in
+// dir/secret, readable only by the user, for the tools answering requests to sign with.
+func Open(dir string) (*Inbox, error) {
+	for _, sub := range []string{"pending", "responses"} {
+		if err := os.MkdirAll(filepath.Join(dir, sub), 0700); err != nil {
+			return nil, fmt.Errorf("failed to create the approvals inbox: %w", err)
+		}
+	}
+	secretPath := filepath.Join(dir, "secret")
+	secret, err := os.ReadFile(secretPath)
+	if errors.Is(err, os.ErrNotExist) {
+		secret, err = newSecret(secretPath)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the approvals secret: %w", err)
+	}
+	secret = []byte(strings.TrimSpace(string(secret)))
+	if len(secret) == 0 {
+		return nil, fmt.Errorf("the approvals secret in %s is empty", secretPath)
+	}
+	return &Inbox{dir: dir, secret: secret}, nil
+}
+
+// newSecret writes a random hex secret to path.
+func newSecret(path string) ([]byte, error) {
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return nil, err
+	}
+	secret := []byte(hex.EncodeToString(raw))
+	if err := os.WriteFile(path, append(secret, '\n'), 0600); err != nil {
+		return nil, err
+	}
+	return secret, nil
+}
+
+// NewID returns a random ID for a request. Requests never share an ID, so a response signed for
+// one can't answer another.
+func NewID() (string, error) {
+	raw := make([]byte, 8)
+	if _, err := rand.Read(raw); err != nil {
+		return "", fmt.Errorf("failed to create an approval ID: %w", err)
+	}
+	return hex.EncodeToString(raw), nil
+}
+
+// Sign returns the token answering request id with choice and phrase: the hex HMAC-SHA256,
+// keyed with the secret, of the ID, the choice and, if there is one, the phrase joined by
+// newlines.
+func Sign(secret []byte, id, choice, phrase string) string {
+	message := id + "\n" + choice
+	if phrase != "" {
+		message += "\n" + phrase
+	}
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(message))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// Publish writes the request to the inbox for external tools to answer.
+func (in *Inbox) Publish(request Request) error {
+	data, err := json.MarshalIndent(request, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode approval request: %w", err)
+	}
+	// Write then rename so tools never read a partial request
+	path := in.requestPath(request.ID)
+	if err := os.WriteFile(path+".tmp", data, 0600); err != nil {
+		return fmt.Errorf("failed to publish approval request: %w", err)
+	}
+	if err := os.Rename(path+".tmp", path); err != nil {
+		return fmt.Errorf("failed to publish approval request: %w", err)
+	}
+	return in.Audit(AuditEntry{ID: request.ID, Outcome: OutcomeRequested, Instance: request.Instance, Message: request.Message})
+}
+
+// Response returns the response to the request, or nil if there is none yet. A response that
+// isn't signed with the inbox's secret is an error, to be rejected.
+func (in *Inbox) Response(id string) (*Response, error) {
+	path := in.responsePath(id)
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read approval response: %w", err)
+	}
+	var response Response
+	if err := json.Unmarshal(data, &response); err != nil {
+		return nil, fmt.Errorf("failed to parse approval response: %w", err)
+	}
+	expected := Sign(in.secret, id, response.Choice, response.Phrase)
+	if response.ID != id || !hmac.Equal([]byte(response.Token), []byte(expected)) {
+		return nil, fmt.Errorf("approval response for %s has an invalid token", id)
+	}
+	return &response, nil
+}
+
+// Reject removes a response that can't answer the request, leaving the request open for another,
+// and records why.
+func (in *Inbox) Reject(request Request, reason string) error {
+	os.Remove(in.responsePath(request.ID))
+	return in.Audit(AuditEntry{ID: request.ID, Outcome: OutcomeRejected, Instance: request.Instance, Reason: reason})
+}
+
+// Close removes the request and its response from the inbox once it's answered or withdrawn,
+// recording the outcome.
+func (in *Inbox) Close(entry AuditEntry) error {
+	os.Remove(in.requestPath(entry.ID))
+	os.Remove(in.responsePath(entry.ID))
+	return in.Audit(entry)
+}
+
+// Audit appends the entry to the audit log, stamping it with the current time.
+func (in *Inbox) Audit(entry AuditEntry) error {
+	entry.Time = time.Now()
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("failed to encode audit entry: %w", err)
+	}
+	file, err := os.OpenFile(filepath.Join(in.dir, "audit.jsonl"), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to open the approvals audit log: %w", err)
+	}
+	defer file.Close()
+	if _, err := file.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("failed to write the approvals audit log: %w", err)
+	}
+	return nil
+}
+
+// requestPath returns the path the request with the ID is published at.
+func (in *Inbox) requestPath(id string) string {
+	return filepath.Join(in.dir, "pending", id+".json")
+}
+
+// responsePath returns the path the response to the request with the ID is written to.
+func (in *Inbox) responsePath(id string) string {
+	return filepath.Join(in.dir, "responses", id+".json")
+}
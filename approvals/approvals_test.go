@@ -0,0 +1,90 @@
+package approvals
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOpenKeepsSecret(t *testing.T) {
+	dir := t.TempDir()
+	first, err := Open(dir)
+	require.NoError(t, err)
+	info, err := os.Stat(filepath.Join(dir, "secret"))
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+
+	second, err := Open(dir)
+	require.NoError(t, err)
+	assert.Equal(t, first.secret, second.secret)
+}
+
+func TestRespond(t *testing.T) {
+	dir := t.TempDir()
+	inbox, err := Open(dir)
+	require.NoError(t, err)
+
+	require.NoError(t, inbox.Publish(Request{ID: "abc", Instance: "web", Message: "Kill session 'web'?",
+		Choices: []Choice{{Key: Approve, Label: "Confirm"}}}))
+	var published Request
+	data, err := os.ReadFile(filepath.Join(dir, "pending", "abc.json"))
+	require.NoError(t, err)
+	require.NoError(t, json.Unmarshal(data, &published))
+	assert.Equal(t, "Kill session 'web'?", published.Message)
+
+	response, err := inbox.Response("abc")
+	require.NoError(t, err)
+	assert.Nil(t, response, "no response yet")
+
+	respond := func(response Response) {
+		data, err := json.Marshal(response)
+		require.NoError(t, err)
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "responses", "abc.json"), data, 0600))
+	}
+
+	// A token signed with another secret, or for another request, doesn't answer it
+	respond(Response{ID: "abc", Choice: Approve, Token: Sign([]byte("guess"), "abc", Approve, "")})
+	_, err = inbox.Response("abc")
+	assert.Error(t, err)
+	respond(Response{ID: "abc", Choice: Approve, Token: Sign(inbox.secret, "other", Approve, "")})
+	_, err = inbox.Response("abc")
+	assert.Error(t, err)
+	require.NoError(t, inbox.Reject(published, err.Error()))
+	assert.NoFileExists(t, filepath.Join(dir, "responses", "abc.json"))
+	assert.FileExists(t, filepath.Join(dir, "pending", "abc.json"))
+
+	respond(Response{ID: "abc", Choice: Deny, By: "slack:alice", Token: Sign(inbox.secret, "abc", Deny, "")})
+	response, err = inbox.Response("abc")
+	require.NoError(t, err)
+	require.NotNil(t, response)
+	assert.Equal(t, Deny, response.Choice)
+
+	require.NoError(t, inbox.Close(AuditEntry{ID: "abc", Outcome: OutcomeDenied, By: response.By}))
+	assert.NoFileExists(t, filepath.Join(dir, "pending", "abc.json"))
+	assert.NoFileExists(t, filepath.Join(dir, "responses", "abc.json"))
+
+	audit, err := os.ReadFile(filepath.Join(dir, "audit.jsonl"))
+	require.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(string(audit)), "\n")
+	require.Len(t, lines, 3)
+	var entry AuditEntry
+	require.NoError(t, json.Unmarshal([]byte(lines[1]), &entry))
+	assert.Equal(t, OutcomeRejected, entry.Outcome)
+	require.NoError(t, json.Unmarshal([]byte(lines[2]), &entry))
+	assert.Equal(t, OutcomeDenied, entry.Outcome)
+	assert.Equal(t, "slack:alice", entry.By)
+	assert.False(t, entry.Time.IsZero())
+}
+
+func TestNewIDIsUnique(t *testing.T) {
+	first, err := NewID()
+	require.NoError(t, err)
+	second, err := NewID()
+	require.NoError(t, err)
+	assert.NotEqual(t, first, second)
+}
@@ -0,0 +1,183 @@
+// Package bench runs the same task on several agents side by side and compares the results, so
+// new models and prompts can be evaluated on a real repository.
+package bench
+
+import (
+	"claude-squad/config"
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// Defaults for the optional spec fields.
+const (
+	DefaultRuns           = 1
+	DefaultTimeoutMinutes = 30
+	DefaultSettleSeconds  = 20
+	DefaultTestCommand    = "yarn tester"
+)
+
+// defaultCostPattern matches the session cost printed by agents such as Claude Code
+// ("Total cost: $0.4213").
+const defaultCostPattern = `(?i)cost:?\s*\$([0-9]+(?:\.[0-9]+)?)`
+
+// Variant is one of the setups being compared.
+type Variant struct {
+	Name string `json:"name"`
+	// Program is the agent command, e.g. "claude --model opus". Empty uses the spec's program.
+	Program string `json:"program,omitempty"`
+	// Prompt overrides the spec's prompt for this variant.
+	Prompt string `json:"prompt,omitempty"`
+}
+
+// Spec describes a benchmark: the task, the variants to run it with and how to judge the results.
+type Spec struct {
+	Name string `json:"name"`
+	// Template names a config template that supplies the program, prompt, base branch and auto
+	// yes setting. Fields set in the spec take precedence.
+	Template   string `json:"template,omitempty"`
+	Program    string `json:"program,omitempty"`
+	Prompt     string `json:"prompt,omitempty"`
+	BaseBranch string `json:"base_branch,omitempty"`
+	AutoYes    bool   `json:"auto_yes,omitempty"`
+	// Runs is how many instances are started for each variant.
+	Runs int `json:"runs,omitempty"`
+	// TestCommand is run with sh in each worktree once the agent is done. Exit status 0 passes.
+	TestCommand string `json:"test_command,omitempty"`
+	// TimeoutMinutes bounds how long an agent may work before its run is judged as it stands.
+	TimeoutMinutes int `json:"timeout_minutes,omitempty"`
+	// SettleSeconds is how long an agent's output must stay unchanged for it to count as done.
+	SettleSeconds int `json:"settle_seconds,omitempty"`
+	// CostPrompt is sent to the agent when it is done to make it print its cost, e.g. "/cost".
+	CostPrompt string `json:"cost_prompt,omitempty"`
+	// CostPattern is a regular expression whose first group is the cost in dollars. The last
+	// match in the agent's output is used.
+	CostPattern string    `json:"cost_pattern,omitempty"`
+	Variants    []Variant `json:"variants"`
+
+	costPattern *regexp.Regexp
+}
+
+// LoadSpec reads a spec from a JSON file and fills in its defaults from cfg.
+func LoadSpec(path string, cfg *config.Config) (*Spec, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read benchmark spec: %w", err)
+	}
+	var spec Spec
+	if err := json.Unmarshal(data, &spec); err != nil {
+		return nil, fmt.Errorf("failed to parse benchmark spec: %w", err)
+	}
+	if err := spec.resolve(cfg); err != nil {
+		return nil, err
+	}
+	return &spec, nil
+}
+
+// resolve applies the template and defaults and validates the spec.
+func (s *Spec) resolve(cfg *config.Config) error {
+	if strings.TrimSpace(s.Name) == "" {
+		return fmt.Errorf("benchmark spec needs a name")
+	}
+	if s.Template != "" {
+		template, ok := findTemplate(cfg.Templates, s.Template)
+		if !ok {
+			return fmt.Errorf("template not found: %s", s.Template)
+		}
+		if s.Program == "" {
+			s.Program = template.Program
+		}
+		if s.Prompt == "" {
+			s.Prompt = template.Prompt
+		}
+		if s.BaseBranch == "" {
+			s.BaseBranch = template.BaseBranch
+		}
+		s.AutoYes = s.AutoYes || template.AutoYes
+	}
+	if s.Program == "" {
+		s.Program = cfg.DefaultProgram
+	}
+	if s.Runs <= 0 {
+		s.Runs = DefaultRuns
+	}
+	if s.TestCommand == "" {
+		s.TestCommand = DefaultTestCommand
+	}
+	if s.TimeoutMinutes <= 0 {
+		s.TimeoutMinutes = DefaultTimeoutMinutes
+	}
+	if s.SettleSeconds <= 0 {
+		s.SettleSeconds = DefaultSettleSeconds
+	}
+	if s.CostPattern == "" {
+		s.CostPattern = defaultCostPattern
+	}
+	pattern, err := regexp.Compile(s.CostPattern)
+	if err != nil {
+		return fmt.Errorf("invalid cost pattern: %w", err)
+	}
+	s.costPattern = pattern
+
+	if len(s.Variants) == 0 {
+		return fmt.Errorf("benchmark spec needs at least one variant")
+	}
+	seen := make(map[string]bool)
+	for i := range s.Variants {
+		variant := &s.Variants[i]
+		if strings.TrimSpace(variant.Name) == "" {
+			return fmt.Errorf("variant %d needs a name", i+1)
+		}
+		if seen[variant.Name] {
+			return fmt.Errorf("duplicate variant name: %s", variant.Name)
+		}
+		seen[variant.Name] = true
+		if variant.Program == "" {
+			variant.Program = s.Program
+		}
+		if variant.Prompt == "" {
+			variant.Prompt = s.Prompt
+		}
+		if strings.TrimSpace(variant.Prompt) == "" {
+			return fmt.Errorf("variant %s has no prompt", variant.Name)
+		}
+	}
+	return nil
+}
+
+func findTemplate(templates []config.InstanceTemplate, name string) (config.InstanceTemplate, bool) {
+	for _, template := range templates {
+		if template.Name == name {
+			return template, true
+		}
+	}
+	return config.InstanceTemplate{}, false
+}
+
+// timeout returns how long an agent may work.
+func (s *Spec) timeout() time.Duration {
+	return time.Duration(s.TimeoutMinutes) * time.Minute
+}
+
+// settle returns how long an agent's output must stay unchanged for it to count as done.
+func (s *Spec) settle() time.Duration {
+	return time.Duration(s.SettleSeconds) * time.Second
+}
+
+// ParseCost returns the last cost matched by pattern in output.
+func ParseCost(output string, pattern *regexp.Regexp) (float64, bool) {
+	matches := pattern.FindAllStringSubmatch(output, -1)
+	for i := len(matches) - 1; i >= 0; i-- {
+		if len(matches[i]) < 2 {
+			continue
+		}
+		var cost float64
+		if _, err := fmt.Sscanf(matches[i][1], "%g", &cost); err == nil {
+			return cost, true
+		}
+	}
+	return 0, false
+}
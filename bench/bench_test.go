@@ -0,0 +1,73 @@
+package bench
+
+import (
+	"claude-squad/config"
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeSpec(t *testing.T, body string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "spec.json")
+	require.NoError(t, os.WriteFile(path, []byte(body), 0644))
+	return path
+}
+
+func TestLoadSpec(t *testing.T) {
+	cfg := &config.Config{
+		DefaultProgram: "claude",
+		Templates: []config.InstanceTemplate{
+			{Name: "bugfix", Program: "aider", Prompt: "Fix the bug", BaseBranch: "develop", AutoYes: true},
+		},
+	}
+
+	t.Run("fills in defaults", func(t *testing.T) {
+		spec, err := LoadSpec(writeSpec(t, `{"name": "b", "prompt": "p",
+			"variants": [{"name": "one"}, {"name": "two", "program": "codex", "prompt": "q"}]}`), cfg)
+		require.NoError(t, err)
+		assert.Equal(t, DefaultRuns, spec.Runs)
+		assert.Equal(t, DefaultTestCommand, spec.TestCommand)
+		assert.Equal(t, Variant{Name: "one", Program: "claude", Prompt: "p"}, spec.Variants[0])
+		assert.Equal(t, Variant{Name: "two", Program: "codex", Prompt: "q"}, spec.Variants[1])
+	})
+
+	t.Run("applies the template under the spec's own fields", func(t *testing.T) {
+		spec, err := LoadSpec(writeSpec(t, `{"name": "b", "template": "bugfix", "prompt": "Fix it properly",
+			"variants": [{"name": "one"}]}`), cfg)
+		require.NoError(t, err)
+		assert.Equal(t, "aider", spec.Variants[0].Program)
+		assert.Equal(t, "Fix it properly", spec.Variants[0].Prompt)
+		assert.Equal(t, "develop", spec.BaseBranch)
+		assert.True(t, spec.AutoYes)
+	})
+
+	for name, body := range map[string]string{
+		"missing name":      `{"prompt": "p", "variants": [{"name": "one"}]}`,
+		"no variants":       `{"name": "b", "prompt": "p"}`,
+		"duplicate variant": `{"name": "b", "prompt": "p", "variants": [{"name": "one"}, {"name": "one"}]}`,
+		"missing prompt":    `{"name": "b", "variants": [{"name": "one"}]}`,
+		"unknown template":  `{"name": "b", "template": "nope", "variants": [{"name": "one"}]}`,
+		"bad cost pattern":  `{"name": "b", "prompt": "p", "cost_pattern": "(", "variants": [{"name": "one"}]}`,
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := LoadSpec(writeSpec(t, body), cfg)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestParseCost(t *testing.T) {
+	pattern := regexp.MustCompile(defaultCostPattern)
+
+	cost, ok := ParseCost("Total cost: $0.12\n...\nTotal cost: $1.4213\n", pattern)
+	assert.True(t, ok)
+	assert.InDelta(t, 1.4213, cost, 1e-9)
+
+	_, ok = ParseCost("no money mentioned", pattern)
+	assert.False(t, ok)
+}
@@ -0,0 +1,125 @@
+package bench
+
+import (
+	"fmt"
+	"io"
+	"text/tabwriter"
+	"time"
+)
+
+// Summary aggregates the runs of one variant.
+type Summary struct {
+	Variant  string `json:"variant"`
+	Program  string `json:"program"`
+	Runs     int    `json:"runs"`
+	Passed   int    `json:"passed"`
+	TimedOut int    `json:"timed_out"`
+	Errors   int    `json:"errors"`
+	// The means are over the runs that completed without an error.
+	MeanAdded    float64       `json:"mean_added"`
+	MeanRemoved  float64       `json:"mean_removed"`
+	MeanDuration time.Duration `json:"mean_duration_ns"`
+	// TotalCost sums the costs of the CostRuns runs that reported one.
+	TotalCost float64 `json:"total_cost"`
+	CostRuns  int     `json:"cost_runs"`
+}
+
+// PassRate is the fraction of runs whose tests passed.
+func (s Summary) PassRate() float64 {
+	if s.Runs == 0 {
+		return 0
+	}
+	return float64(s.Passed) / float64(s.Runs)
+}
+
+// Report is the full benchmark outcome, as written by --output.
+type Report struct {
+	Name      string    `json:"name"`
+	Started   time.Time `json:"started"`
+	Summaries []Summary `json:"summaries"`
+	Results   []Result  `json:"results"`
+}
+
+// Summarize groups the results by variant, in the order the variants first appear.
+func Summarize(results []Result) []Summary {
+	var summaries []Summary
+	index := make(map[string]int)
+	completed := make(map[string]int)
+	for _, result := range results {
+		i, ok := index[result.Variant]
+		if !ok {
+			i = len(summaries)
+			index[result.Variant] = i
+			summaries = append(summaries, Summary{Variant: result.Variant, Program: result.Program})
+		}
+		summary := &summaries[i]
+		summary.Runs++
+		if result.Error != "" {
+			summary.Errors++
+			continue
+		}
+		completed[result.Variant]++
+		if result.Passed {
+			summary.Passed++
+		}
+		if result.TimedOut {
+			summary.TimedOut++
+		}
+		summary.MeanAdded += float64(result.Added)
+		summary.MeanRemoved += float64(result.Removed)
+		summary.MeanDuration += result.Duration
+		if result.Cost != nil {
+			summary.TotalCost += *result.Cost
+			summary.CostRuns++
+		}
+	}
+	for i := range summaries {
+		summary := &summaries[i]
+		if n := completed[summary.Variant]; n > 0 {
+			summary.MeanAdded /= float64(n)
+			summary.MeanRemoved /= float64(n)
+			summary.MeanDuration /= time.Duration(n)
+		}
+	}
+	return summaries
+}
+
+// WriteReport writes the comparison of the variants followed by the individual runs.
+func WriteReport(w io.Writer, results []Result) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(tw, "VARIANT\tPROGRAM\tPASSED\tDIFF\tDURATION\tCOST\n")
+	for _, summary := range Summarize(results) {
+		cost := "-"
+		if summary.CostRuns > 0 {
+			cost = fmt.Sprintf("$%.2f (mean $%.2f)", summary.TotalCost, summary.TotalCost/float64(summary.CostRuns))
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%d/%d (%.0f%%)\t+%.0f/-%.0f\t%s\t%s\n", summary.Variant, summary.Program,
+			summary.Passed, summary.Runs, summary.PassRate()*100, summary.MeanAdded, summary.MeanRemoved,
+			summary.MeanDuration.Round(time.Second), cost)
+	}
+	fmt.Fprintf(tw, "\nRUN\tINSTANCE\tRESULT\tDIFF\tDURATION\tCOST\n")
+	for _, result := range results {
+		cost := "-"
+		if result.Cost != nil {
+			cost = fmt.Sprintf("$%.2f", *result.Cost)
+		}
+		fmt.Fprintf(tw, "%s #%d\t%s\t%s\t+%d/-%d\t%s\t%s\n", result.Variant, result.Run, result.Instance,
+			describe(result), result.Added, result.Removed, result.Duration.Round(time.Second), cost)
+	}
+	return tw.Flush()
+}
+
+// describe summarizes a run's outcome in a few words.
+func describe(result Result) string {
+	switch {
+	case result.Error != "":
+		return "error: " + result.Error
+	case result.TimedOut && result.Passed:
+		return "timed out, tests passed"
+	case result.TimedOut:
+		return "timed out, tests failed"
+	case result.Passed:
+		return "tests passed"
+	}
+	return "tests failed"
+}
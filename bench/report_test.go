@@ -0,0 +1,52 @@
+package bench
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSummarize(t *testing.T) {
+	cost := 0.5
+	results := []Result{
+		{Variant: "opus", Program: "claude", Run: 1, Passed: true, Added: 10, Removed: 2, Duration: 2 * time.Minute, Cost: &cost},
+		{Variant: "opus", Program: "claude", Run: 2, TimedOut: true, Added: 20, Removed: 4, Duration: 4 * time.Minute, Cost: &cost},
+		{Variant: "opus", Program: "claude", Run: 3, Error: "failed to start instance"},
+		{Variant: "aider", Program: "aider", Run: 1, Passed: true, Added: 5, Duration: time.Minute},
+	}
+
+	summaries := Summarize(results)
+	require.Len(t, summaries, 2)
+
+	opus := summaries[0]
+	assert.Equal(t, "opus", opus.Variant)
+	assert.Equal(t, 3, opus.Runs)
+	assert.Equal(t, 1, opus.Passed)
+	assert.Equal(t, 1, opus.TimedOut)
+	assert.Equal(t, 1, opus.Errors)
+	assert.InDelta(t, 1.0/3, opus.PassRate(), 1e-9)
+	assert.Equal(t, 15.0, opus.MeanAdded)
+	assert.Equal(t, 3.0, opus.MeanRemoved)
+	assert.Equal(t, 3*time.Minute, opus.MeanDuration)
+	assert.Equal(t, 1.0, opus.TotalCost)
+	assert.Equal(t, 2, opus.CostRuns)
+
+	assert.Equal(t, "aider", summaries[1].Variant)
+	assert.Equal(t, 1.0, summaries[1].PassRate())
+}
+
+func TestWriteReport(t *testing.T) {
+	var out strings.Builder
+	require.NoError(t, WriteReport(&out, []Result{
+		{Variant: "opus", Program: "claude", Run: 1, Instance: "b-opus-1", Passed: true, Added: 3, Duration: time.Minute},
+		{Variant: "opus", Program: "claude", Run: 2, Instance: "b-opus-2", Error: "boom"},
+	}))
+
+	report := out.String()
+	assert.Contains(t, report, "1/2 (50%)")
+	assert.Contains(t, report, "tests passed")
+	assert.Contains(t, report, "error: boom")
+}
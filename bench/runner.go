@@ -0,0 +1,243 @@
+package bench
+
+import (
+	"claude-squad/log"
+	"claude-squad/session"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os/exec"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// pollInterval is how often the agents' output is checked for changes.
+	pollInterval = time.Second
+	// startupQuiet is how long an agent's output must stay unchanged after it starts before it
+	// is sent the prompt, so the prompt isn't typed while the program is still loading.
+	startupQuiet = 3 * time.Second
+	// startupTimeout bounds the wait for an agent to start.
+	startupTimeout = 2 * time.Minute
+	// costWait is how long the agent gets to print its cost after the cost prompt.
+	costWait = 5 * time.Second
+	// testOutputLines is how much of the end of the test output a result keeps.
+	testOutputLines = 30
+)
+
+// Result is the outcome of one run of a variant.
+type Result struct {
+	Variant  string `json:"variant"`
+	Program  string `json:"program"`
+	Run      int    `json:"run"`
+	Instance string `json:"instance,omitempty"`
+	Branch   string `json:"branch,omitempty"`
+	Passed   bool   `json:"passed"`
+	// TimedOut is set when the agent was still working at the spec's timeout.
+	TimedOut bool          `json:"timed_out,omitempty"`
+	Added    int           `json:"added"`
+	Removed  int           `json:"removed"`
+	Duration time.Duration `json:"duration_ns"`
+	// Cost is the agent's reported cost in dollars, if it printed one.
+	Cost       *float64 `json:"cost,omitempty"`
+	TestOutput string   `json:"test_output,omitempty"`
+	// Error is set when the run could not be started or judged.
+	Error string `json:"error,omitempty"`
+}
+
+// run is a started instance and the result being filled in for it.
+type run struct {
+	instance *session.Instance
+	result   *Result
+	prompt   string
+}
+
+// Run starts every run of every variant in the repository at repoPath, waits for the agents to
+// finish, tests their work and returns the results in spec order. Progress is written to out.
+// The instances are left in place so their work can be inspected; Cleanup removes them. When ctx
+// is cancelled the results gathered so far are returned with the context's error.
+func Run(ctx context.Context, manager *session.Manager, spec *Spec, repoPath string, out io.Writer) ([]Result, error) {
+	stamp := time.Now().Format("0102-1504")
+	results := make([]Result, 0, len(spec.Variants)*spec.Runs)
+	for _, variant := range spec.Variants {
+		for n := 1; n <= spec.Runs; n++ {
+			results = append(results, Result{
+				Variant:  variant.Name,
+				Program:  variant.Program,
+				Run:      n,
+				Instance: fmt.Sprintf("%s-%s-%d-%s", slug(spec.Name), slug(variant.Name), n, stamp),
+			})
+		}
+	}
+
+	// Instances are created one at a time since they share the manager's storage, then work in
+	// parallel.
+	var runs []run
+	for i := range results {
+		result := &results[i]
+		variant := spec.Variants[i/spec.Runs]
+		instance, err := manager.Create(ctx, session.InstanceOptions{
+			Title:      result.Instance,
+			Path:       repoPath,
+			Program:    variant.Program,
+			BaseBranch: spec.BaseBranch,
+			AutoYes:    spec.AutoYes,
+		})
+		if err != nil {
+			if ctx.Err() != nil {
+				return results, ctx.Err()
+			}
+			result.Error = err.Error()
+			fmt.Fprintf(out, "%s run %d: %v\n", result.Variant, result.Run, err)
+			continue
+		}
+		result.Branch = instance.Branch
+		fmt.Fprintf(out, "Started %s run %d as %s\n", result.Variant, result.Run, result.Instance)
+		runs = append(runs, run{instance: instance, result: result, prompt: variant.Prompt})
+	}
+
+	var wg sync.WaitGroup
+	var outMu sync.Mutex
+	for _, r := range runs {
+		wg.Add(1)
+		go func(r run) {
+			defer wg.Done()
+			judge(ctx, spec, r)
+			outMu.Lock()
+			defer outMu.Unlock()
+			fmt.Fprintf(out, "Finished %s run %d: %s\n", r.result.Variant, r.result.Run, describe(*r.result))
+		}(r)
+	}
+	wg.Wait()
+	return results, ctx.Err()
+}
+
+// judge prompts the agent once it has started, waits for it to finish and records its cost, diff
+// and test result.
+func judge(ctx context.Context, spec *Spec, r run) {
+	if _, err := waitForQuiet(ctx, r.instance, startupQuiet, startupTimeout, false); err != nil {
+		r.result.Error = err.Error()
+		return
+	}
+	if err := r.instance.SendStartupPrompt(r.prompt); err != nil {
+		r.result.Error = fmt.Sprintf("failed to send prompt: %v", err)
+		return
+	}
+	started := time.Now()
+	timedOut, err := waitForQuiet(ctx, r.instance, spec.settle(), spec.timeout(), true)
+	r.result.Duration = time.Since(started)
+	r.result.TimedOut = timedOut
+	if err != nil {
+		r.result.Error = err.Error()
+		return
+	}
+
+	if cost, ok := agentCost(ctx, r.instance, spec); ok {
+		r.result.Cost = &cost
+	}
+	if err := r.instance.UpdateDiffStats(); err != nil {
+		log.WarningLog.Printf("could not update diff stats for %s: %v", r.instance.Title, err)
+	} else if stats := r.instance.GetDiffStats(); stats != nil {
+		r.result.Added = stats.Added
+		r.result.Removed = stats.Removed
+	}
+
+	worktree, err := r.instance.GetGitWorktree()
+	if err != nil {
+		r.result.Error = fmt.Sprintf("failed to get worktree: %v", err)
+		return
+	}
+	cmd := exec.CommandContext(ctx, "sh", "-c", spec.TestCommand)
+	cmd.Dir = worktree.GetWorktreePath()
+	output, err := cmd.CombinedOutput()
+	r.result.Passed = err == nil
+	r.result.TestOutput = tail(string(output), testOutputLines)
+}
+
+// waitForQuiet polls the agent until its output has stopped changing for quiet, or until timeout.
+// With requireActivity the output must change at least once first. Prompts are accepted when the
+// instance has auto yes set.
+func waitForQuiet(ctx context.Context, instance *session.Instance, quiet, timeout time.Duration,
+	requireActivity bool) (timedOut bool, err error) {
+	deadline := time.Now().Add(timeout)
+	lastChange := time.Now()
+	active := !requireActivity
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return false, ctx.Err()
+		case <-ticker.C:
+		}
+		updated, hasPrompt := instance.HasUpdated()
+		if hasPrompt {
+			instance.TapEnter()
+		}
+		if updated {
+			lastChange = time.Now()
+			active = true
+		}
+		if active && time.Since(lastChange) >= quiet {
+			return false, nil
+		}
+		if time.Now().After(deadline) {
+			return true, nil
+		}
+	}
+}
+
+// agentCost sends the spec's cost prompt, if any, and looks for a cost in the agent's output.
+func agentCost(ctx context.Context, instance *session.Instance, spec *Spec) (float64, bool) {
+	if spec.CostPrompt != "" {
+		if err := instance.SendPrompt(spec.CostPrompt); err != nil {
+			log.WarningLog.Printf("failed to send cost prompt to %s: %v", instance.Title, err)
+		} else {
+			select {
+			case <-ctx.Done():
+				return 0, false
+			case <-time.After(costWait):
+			}
+		}
+	}
+	output, err := instance.PreviewFullHistory()
+	if err != nil {
+		log.WarningLog.Printf("failed to capture output of %s: %v", instance.Title, err)
+		return 0, false
+	}
+	return ParseCost(output, spec.costPattern)
+}
+
+// Cleanup kills the instances of the results. With keepBranches their branches, including any
+// uncommitted work, are kept for later inspection.
+func Cleanup(ctx context.Context, manager *session.Manager, results []Result, keepBranches bool) error {
+	var errs []error
+	for _, result := range results {
+		if result.Branch == "" {
+			continue
+		}
+		if err := manager.Kill(ctx, result.Instance, keepBranches); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+var slugPattern = regexp.MustCompile(`[^a-z0-9]+`)
+
+// slug makes a name safe to use in instance titles and branch names.
+func slug(name string) string {
+	return strings.Trim(slugPattern.ReplaceAllString(strings.ToLower(name), "-"), "-")
+}
+
+// tail returns the last n lines of s.
+func tail(s string, n int) string {
+	lines := strings.Split(strings.TrimRight(s, "\n"), "\n")
+	if len(lines) > n {
+		lines = lines[len(lines)-n:]
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,141 @@
+package main
+
+import (
+	"claude-squad/bench"
+	"claude-squad/config"
+	"claude-squad/log"
+	"claude-squad/session"
+	"claude-squad/session/git"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	benchOutputFlag       string
+	benchKeepBranchesFlag bool
+)
+
+// benchCmd runs a benchmark spec against the current repository. Its instances are kept in a
+// store of their own so they don't count towards or show up in the TUI's list.
+var benchCmd = &cobra.Command{
+	Use:   "bench <spec.json>",
+	Short: "Run the same task with several programs or prompts and compare the results",
+	Long: `Run the same task with several programs or prompts and compare the results.
+
+The spec is a JSON file:
+
+  {
+    "name": "login-fix",
+    "prompt": "Fix the login redirect loop",
+    "test_command": "go test ./...",
+    "runs": 3,
+    "auto_yes": true,
+    "cost_prompt": "/cost",
+    "variants": [
+      {"name": "opus", "program": "claude --model opus"},
+      {"name": "sonnet", "program": "claude --model sonnet"}
+    ]
+  }
+
+"template" takes the program, prompt, base branch and auto yes setting from a config template.
+Every run gets its own instance; once an agent's output has been quiet for "settle_seconds"
+(default 20) or "timeout_minutes" (default 30) has passed, the test command is run in its
+worktree. The report compares pass rate, diff size, duration and the cost the agent printed.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		log.Initialize(false)
+		defer log.Close()
+
+		currentDir, err := filepath.Abs(".")
+		if err != nil {
+			return fmt.Errorf("failed to get current directory: %w", err)
+		}
+		if !git.IsGitRepo(currentDir) {
+			return fmt.Errorf("error: claude-squad must be run from within a git repository")
+		}
+		spec, err := bench.LoadSpec(args[0], config.LoadConfig())
+		if err != nil {
+			return err
+		}
+		manager, err := newBenchManager()
+		if err != nil {
+			return err
+		}
+
+		ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+
+		started := time.Now()
+		results, runErr := bench.Run(ctx, manager, spec, currentDir, os.Stdout)
+		fmt.Println()
+		if err := bench.WriteReport(os.Stdout, results); err != nil {
+			return err
+		}
+		if benchOutputFlag != "" {
+			if err := writeBenchReport(benchOutputFlag, bench.Report{
+				Name:      spec.Name,
+				Started:   started,
+				Summaries: bench.Summarize(results),
+				Results:   results,
+			}); err != nil {
+				return err
+			}
+		}
+
+		// Clean up even when interrupted, so no instances are left behind.
+		if err := bench.Cleanup(context.Background(), manager, results, benchKeepBranchesFlag); err != nil {
+			return fmt.Errorf("failed to clean up benchmark instances: %w", err)
+		}
+		return runErr
+	},
+}
+
+func init() {
+	benchCmd.Flags().StringVarP(&benchOutputFlag, "output", "o", "", "Also write the report as JSON to this file")
+	benchCmd.Flags().BoolVar(&benchKeepBranchesFlag, "keep-branches", false,
+		"Keep each run's branch, with its uncommitted changes committed, for inspection")
+}
+
+// newBenchManager returns a manager whose instances and worktrees live in the benchmark
+// directory of the config directory, on branches prefixed "bench/". The policy applies to them
+// like to any other instance.
+func newBenchManager() (*session.Manager, error) {
+	configDir, err := config.GetConfigDir()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get config directory: %w", err)
+	}
+	policy, err := config.LoadPolicy()
+	if err != nil {
+		return nil, err
+	}
+	dir := filepath.Join(configDir, "bench")
+	storage, err := session.NewStorage(session.NewFileStore(filepath.Join(dir, "instances.json")))
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize storage: %w", err)
+	}
+	return session.NewManager(storage, session.ManagerOptions{
+		WorktreeDir:  filepath.Join(dir, "worktrees"),
+		BranchPrefix: "bench/",
+		Policy:       policy,
+		Config:       config.LoadConfig(),
+	}), nil
+}
+
+func writeBenchReport(path string, report bench.Report) error {
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode report: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write report: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,66 @@
+package config
+
+import (
+	"fmt"
+	"regexp"
+)
+
+// AutoYesPolicy decides which prompts instances in auto-yes mode confirm, by regular expressions
+// matched against the prompt text captured from the pane.
+type AutoYesPolicy struct {
+	// Allow lists the prompts that are confirmed, e.g. ["Run tests\\?"]. Empty allows every
+	// prompt not denied.
+	Allow []string `json:"allow,omitempty"`
+	// Deny lists the prompts that are never confirmed, e.g. ["(?i)delete"]. Deny wins over allow.
+	Deny []string `json:"deny,omitempty"`
+}
+
+// Validate returns an error naming the first pattern that isn't a valid regular expression.
+func (p *AutoYesPolicy) Validate() error {
+	if p == nil {
+		return nil
+	}
+	for _, pattern := range append(append([]string{}, p.Allow...), p.Deny...) {
+		if _, err := regexp.Compile(pattern); err != nil {
+			return fmt.Errorf("invalid pattern %q: %w", pattern, err)
+		}
+	}
+	return nil
+}
+
+// Confirms returns whether a prompt with the given text is confirmed, and the reason when it
+// isn't. A nil policy confirms every prompt. A policy with an invalid pattern confirms none, so
+// a typo never lets through a prompt meant to be denied.
+func (p *AutoYesPolicy) Confirms(prompt string) (bool, string) {
+	if p == nil {
+		return true, ""
+	}
+	if err := p.Validate(); err != nil {
+		return false, err.Error()
+	}
+	for _, pattern := range p.Deny {
+		if regexp.MustCompile(pattern).MatchString(prompt) {
+			return false, fmt.Sprintf("matches deny pattern %q", pattern)
+		}
+	}
+	if len(p.Allow) == 0 {
+		return true, ""
+	}
+	for _, pattern := range p.Allow {
+		if regexp.MustCompile(pattern).MatchString(prompt) {
+			return true, ""
+		}
+	}
+	return false, "matches no allow pattern"
+}
+
+// SaveAutoYesPolicy writes the auto-yes policy to the user's config file, leaving its other
+// settings as they are. A nil policy confirms every prompt.
+func SaveAutoYesPolicy(policy *AutoYesPolicy) error {
+	if err := policy.Validate(); err != nil {
+		return err
+	}
+	cfg := loadUserConfig()
+	cfg.AutoYesPolicy = policy
+	return saveConfig(cfg)
+}
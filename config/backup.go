@@ -0,0 +1,234 @@
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	// BackupsDirName is the directory inside the config directory holding storage backups.
+	BackupsDirName = "backups"
+	// KeyBindingsFileName is the file custom keybindings are stored in.
+	KeyBindingsFileName = "keybindings.json"
+
+	backupTimeFormat = "20060102-150405.000"
+)
+
+// backupFiles are the storage files snapshotted by a backup.
+var backupFiles = []string{ConfigFileName, StateFileName, KeyBindingsFileName, OutcomesFileName, ActivityFileName,
+	DatabaseFileName}
+
+// Backup is a snapshot of the storage files.
+type Backup struct {
+	// Name is the directory name of the backup, e.g. "20240102-150405.000-startup".
+	Name string
+	// Reason describes what triggered the backup, e.g. "startup" or "scheduled".
+	Reason    string
+	CreatedAt time.Time
+	// Files are the storage files present in the backup.
+	Files []string
+}
+
+func backupsDir() (string, error) {
+	configDir, err := GetConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get config directory: %w", err)
+	}
+	return filepath.Join(configDir, BackupsDirName), nil
+}
+
+// CreateBackup copies the storage files into a new backup and prunes old backups so that at
+// most keep remain. Missing storage files are skipped.
+func CreateBackup(reason string, keep int) (*Backup, error) {
+	configDir, err := GetConfigDir()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get config directory: %w", err)
+	}
+	dir, err := backupsDir()
+	if err != nil {
+		return nil, err
+	}
+
+	// Names sort chronologically, so keep each backup strictly newer than the previous one even
+	// when two are taken within the same millisecond
+	now := time.Now().Truncate(time.Millisecond)
+	existing, err := ListBackups()
+	if err != nil {
+		return nil, err
+	}
+	if len(existing) > 0 && !now.After(existing[0].CreatedAt) {
+		now = existing[0].CreatedAt.Add(time.Millisecond)
+	}
+	name := fmt.Sprintf("%s-%s", now.Format(backupTimeFormat), reason)
+
+	target := filepath.Join(dir, name)
+	if err := os.MkdirAll(target, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create backup directory: %w", err)
+	}
+
+	backup := &Backup{Name: name, Reason: reason, CreatedAt: now}
+	for _, file := range backupFiles {
+		data, err := os.ReadFile(filepath.Join(configDir, file))
+		if os.IsNotExist(err) {
+			continue
+		} else if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", file, err)
+		}
+		if err := os.WriteFile(filepath.Join(target, file), data, 0644); err != nil {
+			return nil, fmt.Errorf("failed to back up %s: %w", file, err)
+		}
+		backup.Files = append(backup.Files, file)
+	}
+
+	if err := pruneBackups(keep); err != nil {
+		return backup, err
+	}
+	return backup, nil
+}
+
+// CreateBackupIfChanged creates a backup unless the storage files are identical to the most
+// recent backup, so scheduled backups don't rotate meaningful snapshots out. Returns nil if
+// no backup was needed.
+func CreateBackupIfChanged(reason string, keep int) (*Backup, error) {
+	backups, err := ListBackups()
+	if err != nil {
+		return nil, err
+	}
+	if len(backups) > 0 {
+		unchanged, err := matchesBackup(backups[0])
+		if err != nil {
+			return nil, err
+		}
+		if unchanged {
+			return nil, nil
+		}
+	}
+	return CreateBackup(reason, keep)
+}
+
+// matchesBackup returns true if the current storage files have the same contents as backup.
+func matchesBackup(backup Backup) (bool, error) {
+	configDir, err := GetConfigDir()
+	if err != nil {
+		return false, fmt.Errorf("failed to get config directory: %w", err)
+	}
+	dir, err := backupsDir()
+	if err != nil {
+		return false, err
+	}
+
+	for _, file := range backupFiles {
+		current, currentErr := os.ReadFile(filepath.Join(configDir, file))
+		saved, savedErr := os.ReadFile(filepath.Join(dir, backup.Name, file))
+		if os.IsNotExist(currentErr) && os.IsNotExist(savedErr) {
+			continue
+		}
+		if currentErr != nil || savedErr != nil || !bytes.Equal(current, saved) {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// ListBackups returns the available backups, newest first.
+func ListBackups() ([]Backup, error) {
+	dir, err := backupsDir()
+	if err != nil {
+		return nil, err
+	}
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to list backups: %w", err)
+	}
+
+	var backups []Backup
+	for _, entry := range entries {
+		if !entry.IsDir() || len(entry.Name()) <= len(backupTimeFormat) {
+			continue
+		}
+		createdAt, err := time.ParseInLocation(backupTimeFormat, entry.Name()[:len(backupTimeFormat)], time.Local)
+		if err != nil {
+			continue
+		}
+		backup := Backup{
+			Name:      entry.Name(),
+			Reason:    strings.TrimPrefix(entry.Name()[len(backupTimeFormat):], "-"),
+			CreatedAt: createdAt,
+		}
+		for _, file := range backupFiles {
+			if _, err := os.Stat(filepath.Join(dir, entry.Name(), file)); err == nil {
+				backup.Files = append(backup.Files, file)
+			}
+		}
+		backups = append(backups, backup)
+	}
+
+	// Names start with the timestamp, so they sort chronologically
+	sort.Slice(backups, func(i, j int) bool {
+		return backups[i].Name > backups[j].Name
+	})
+	return backups, nil
+}
+
+// RestoreBackup replaces the storage files with the ones in the named backup. The current
+// files are backed up first so a restore can be undone.
+func RestoreBackup(name string, keep int) error {
+	dir, err := backupsDir()
+	if err != nil {
+		return err
+	}
+	source := filepath.Join(dir, filepath.Base(name))
+	if _, err := os.Stat(source); err != nil {
+		return fmt.Errorf("backup %s not found: %w", name, err)
+	}
+
+	// Keep one extra so the pre-restore snapshot doesn't rotate out the backup being restored
+	if _, err := CreateBackup("pre-restore", keep+1); err != nil {
+		return fmt.Errorf("failed to back up current storage before restoring: %w", err)
+	}
+
+	configDir, err := GetConfigDir()
+	if err != nil {
+		return fmt.Errorf("failed to get config directory: %w", err)
+	}
+	for _, file := range backupFiles {
+		data, err := os.ReadFile(filepath.Join(source, file))
+		if os.IsNotExist(err) {
+			continue
+		} else if err != nil {
+			return fmt.Errorf("failed to read %s from backup: %w", file, err)
+		}
+		if err := os.WriteFile(filepath.Join(configDir, file), data, 0644); err != nil {
+			return fmt.Errorf("failed to restore %s: %w", file, err)
+		}
+	}
+	return nil
+}
+
+// pruneBackups removes the oldest backups so that at most keep remain.
+func pruneBackups(keep int) error {
+	if keep <= 0 {
+		return nil
+	}
+	backups, err := ListBackups()
+	if err != nil {
+		return err
+	}
+	dir, err := backupsDir()
+	if err != nil {
+		return err
+	}
+	for i := keep; i < len(backups); i++ {
+		if err := os.RemoveAll(filepath.Join(dir, backups[i].Name)); err != nil {
+			return fmt.Errorf("failed to remove old backup %s: %w", backups[i].Name, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,21 @@
+package config
+
+// BackupBranchRetention is how many of a branch's backup branches are kept, and for how long.
+// Backups beyond it are deleted, locally and on origin, once the branch is updated with main.
+type BackupBranchRetention struct {
+	// KeepLast is how many of the newest backups of a branch are kept. Zero keeps any number.
+	KeepLast int `json:"keep_last"`
+	// MaxAgeDays deletes backups older than this many days. Zero keeps them however old.
+	MaxAgeDays int `json:"max_age_days"`
+}
+
+// DefaultBackupBranchRetention keeps a branch's 5 newest backups from the last 30 days.
+var DefaultBackupBranchRetention = BackupBranchRetention{KeepLast: 5, MaxAgeDays: 30}
+
+// GetBackupBranchRetention returns the configured backup branch retention, or the default.
+func (c *Config) GetBackupBranchRetention() BackupBranchRetention {
+	if c.BackupBranchRetention == nil {
+		return DefaultBackupBranchRetention
+	}
+	return *c.BackupBranchRetention
+}
@@ -0,0 +1,60 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBackupAndRestore(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	configDir, err := GetConfigDir()
+	require.NoError(t, err)
+	require.NoError(t, os.MkdirAll(configDir, 0755))
+
+	statePath := filepath.Join(configDir, StateFileName)
+	require.NoError(t, os.WriteFile(statePath, []byte(`{"instances":[1]}`), 0644))
+
+	backup, err := CreateBackup("startup", 5)
+	require.NoError(t, err)
+	assert.Equal(t, "startup", backup.Reason)
+	assert.Equal(t, []string{StateFileName}, backup.Files)
+
+	// Unchanged storage doesn't produce another backup
+	unchanged, err := CreateBackupIfChanged("scheduled", 5)
+	require.NoError(t, err)
+	assert.Nil(t, unchanged)
+
+	// Corrupt the state and restore it
+	require.NoError(t, os.WriteFile(statePath, []byte(`{corrupt`), 0644))
+	require.NoError(t, RestoreBackup(backup.Name, 5))
+	data, err := os.ReadFile(statePath)
+	require.NoError(t, err)
+	assert.Equal(t, `{"instances":[1]}`, string(data))
+
+	// The corrupt state was backed up before restoring
+	backups, err := ListBackups()
+	require.NoError(t, err)
+	require.Len(t, backups, 2)
+	assert.Equal(t, "pre-restore", backups[0].Reason)
+}
+
+func TestPruneBackups(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	configDir, err := GetConfigDir()
+	require.NoError(t, err)
+	dir := filepath.Join(configDir, BackupsDirName)
+	for _, name := range []string{"20240101-000000.000-a", "20240102-000000.000-b", "20240103-000000.000-c"} {
+		require.NoError(t, os.MkdirAll(filepath.Join(dir, name), 0755))
+	}
+
+	require.NoError(t, pruneBackups(2))
+	backups, err := ListBackups()
+	require.NoError(t, err)
+	require.Len(t, backups, 2)
+	assert.Equal(t, "c", backups[0].Reason)
+	assert.Equal(t, "b", backups[1].Reason)
+}
@@ -9,7 +9,9 @@ import (
 	"os/user"
 	"path/filepath"
 	"regexp"
+	"strconv"
 	"strings"
+	"time"
 )
 
 var (
@@ -17,6 +19,10 @@ var (
 	claudeSquadSectionRe = regexp.MustCompile(`(?i)\[claude-squad\]([\s\S]*?)(?:\n\[|$)`)
 	ideCommandRe         = regexp.MustCompile(`(?m)^ide_command\s*[:=]\s*(.+)$`)
 	diffCommandRe        = regexp.MustCompile(`(?m)^diff_command\s*[:=]\s*(.+)$`)
+	mergeCommandRe       = regexp.MustCompile(`(?m)^merge_command\s*[:=]\s*(.+)$`)
+	forgeRe              = regexp.MustCompile(`(?m)^forge\s*[:=]\s*(.+)$`)
+	testCommandRe        = regexp.MustCompile(`(?m)^test_command\s*[:=]\s*(.+)$`)
+	testParserRe         = regexp.MustCompile(`(?m)^test_parser\s*[:=]\s*(.+)$`)
 )
 
 const (
@@ -24,6 +30,12 @@ const (
 	defaultProgram = "claude"
 )
 
+// Policies for instances that fell behind main, see Config.MainWatchPolicy
+const (
+	MainWatchFlag   = "flag"
+	MainWatchRebase = "rebase"
+)
+
 // GetConfigDir returns the path to the application's configuration directory
 func GetConfigDir() (string, error) {
 	homeDir, err := os.UserHomeDir()
@@ -39,6 +51,11 @@ type Config struct {
 	DefaultProgram string `json:"default_program"`
 	// AutoYes is a flag to automatically accept all prompts.
 	AutoYes bool `json:"auto_yes"`
+	// AutoYesPolicy limits the prompts auto-yes confirms. Nil confirms every prompt.
+	AutoYesPolicy *AutoYesPolicy `json:"auto_yes_policy,omitempty"`
+	// StatusHooks runs new Claude Code sessions with hooks that report when the agent works, is
+	// ready or waits for permission, instead of guessing it from the pane's content.
+	StatusHooks bool `json:"status_hooks,omitempty"`
 	// DaemonPollInterval is the interval (ms) at which the daemon polls sessions for autoyes mode.
 	DaemonPollInterval int `json:"daemon_poll_interval"`
 	// BranchPrefix is the prefix used for git branches created by the application.
@@ -47,6 +64,105 @@ type Config struct {
 	DefaultIdeCommand string `json:"default_ide_command"`
 	// DefaultDiffCommand is the default external diff command to use when none is configured per-repo
 	DefaultDiffCommand string `json:"default_diff_command"`
+	// DefaultMergeCommand is the default external merge tool command to use when none is
+	// configured per-repo. $BASE, $LOCAL, $REMOTE and $MERGED in it are replaced with the files
+	// of a conflict, as in git mergetool.
+	DefaultMergeCommand string `json:"default_merge_command,omitempty"`
+	// ToastDurations maps a toast level (info, success, warning, error) to how long (ms) the
+	// toast stays on screen before it is dismissed.
+	ToastDurations map[string]int `json:"toast_durations_ms"`
+	// ShareAddr is the address the local server for shared diff pages listens on. Use
+	// "0.0.0.0:<port>" to make links reachable by teammates, or "off" to only write files.
+	ShareAddr string `json:"share_addr"`
+	// BackupIntervalMinutes is how often the storage files are backed up while the app runs.
+	BackupIntervalMinutes int `json:"backup_interval_minutes"`
+	// BackupCount is how many storage backups are kept before the oldest are removed.
+	BackupCount int `json:"backup_count"`
+	// SnapshotIntervalSeconds is how often the UI state is snapshotted, to restore it after a
+	// crash.
+	SnapshotIntervalSeconds int `json:"snapshot_interval_seconds"`
+	// PRCommentPollMinutes is how often the comments on instances' open pull requests are
+	// fetched in the background, to flag new ones. Negative disables it.
+	PRCommentPollMinutes int `json:"pr_comment_poll_minutes"`
+	// CommitHistoryDepth is how many commits are loaded at a time when browsing commits in the
+	// diff tab. Older commits are loaded as browsing reaches them.
+	CommitHistoryDepth int `json:"commit_history_depth"`
+	// AutoPauseAfterMinutes pauses instances whose agent has been idle for this many minutes,
+	// freeing their worktree and processes. Zero disables it.
+	AutoPauseAfterMinutes int `json:"auto_pause_after_minutes,omitempty"`
+	// MainWatchMinutes is how often origin's main branch is fetched to tell which instances fell
+	// behind it. Zero disables it.
+	MainWatchMinutes int `json:"main_watch_minutes,omitempty"`
+	// MainWatchPolicy is what happens to instances behind main: "flag" only marks them in the
+	// list, "rebase" also rebases the idle ones with a clean worktree. Empty means "flag".
+	MainWatchPolicy string `json:"main_watch_policy,omitempty"`
+	// CoordinatorDigestMinutes is how often the coordinator instance's agent is sent a digest of
+	// the other instances, when one is designated and they changed since the last digest.
+	CoordinatorDigestMinutes int `json:"coordinator_digest_minutes"`
+	// HistoryExportDir is the directory AI histories are exported to as markdown. Empty uses the
+	// history directory in the config directory; a leading ~ is the home directory.
+	HistoryExportDir string `json:"history_export_dir,omitempty"`
+	// SkipOutcomePrompt disables asking for a run outcome rating when an instance is killed.
+	SkipOutcomePrompt bool `json:"skip_outcome_prompt"`
+	// DisableFinishBell stops ringing the terminal bell when an instance's agent becomes ready or a
+	// command run in its terminal pane exits. The instance is still marked in the list.
+	DisableFinishBell bool `json:"disable_finish_bell,omitempty"`
+	// DefaultTab is the tab shown when an instance is selected that has no tab of its own: "ai",
+	// "diff", "terminal" or "tests". Empty keeps the tab shown before.
+	DefaultTab string `json:"default_tab,omitempty"`
+	// Templates are named configurations new instances can be created from.
+	Templates []InstanceTemplate `json:"templates,omitempty"`
+	// CommentPromptTemplate is a Go text/template that formats an accepted PR comment as a prompt.
+	// Empty uses the built-in format.
+	CommentPromptTemplate string `json:"comment_prompt_template,omitempty"`
+	// AgentCommentPromptTemplates overrides CommentPromptTemplate per agent, keyed by the program's
+	// executable name (e.g. "claude", "aider").
+	AgentCommentPromptTemplates map[string]string `json:"agent_comment_prompt_templates,omitempty"`
+	// SuggestTestsCommand is a shell command, run in the worktree, that reads a prompt on stdin and
+	// prints proposed test files (e.g. "claude -p"). Empty uses the summarizer's, or without one
+	// sends the request to the instance's agent.
+	SuggestTestsCommand string `json:"suggest_tests_command,omitempty"`
+	// Summarizer is the external command that writes commit messages, pull request
+	// descriptions, diff summaries and suggested tests. Nil keeps their built-in behavior.
+	Summarizer *SummarizerConfig `json:"summarizer,omitempty"`
+	// WebhookURL receives a JSON POST for instance events (agent ready, rebase complete, tests
+	// failed, PR comments fetched). Slack incoming webhook URLs work as is. Empty disables it.
+	WebhookURL string `json:"webhook_url,omitempty"`
+	// RemoteApprovals publishes the confirmations waiting in the UI to the approvals inbox in the
+	// config directory, where external tools can answer them with signed responses.
+	RemoteApprovals bool `json:"remote_approvals,omitempty"`
+	// MergeStrategy is the strategy offered first when updating a branch with main: "rebase",
+	// "merge" or "squash". Empty means rebase.
+	MergeStrategy string `json:"merge_strategy,omitempty"`
+	// ShowGitCommands shows the git and gh commands rebase, reset, push and kill would run in
+	// their confirmation dialogs by default, rather than only when toggled in the dialog.
+	ShowGitCommands bool `json:"show_git_commands,omitempty"`
+	// TestRunner is the test command the test tab runs in repositories without their own. Nil
+	// detects it from the files in the worktree.
+	TestRunner *TestRunnerConfig `json:"test_runner,omitempty"`
+	// Repos are the repositories, besides the one claude-squad is started in, that new instances
+	// can be created in.
+	Repos []string `json:"repos,omitempty"`
+	// BackupBranchRetention is how long the backup branches made before updating a branch with
+	// main or resetting it are kept. Nil uses the defaults.
+	BackupBranchRetention *BackupBranchRetention `json:"backup_branch_retention,omitempty"`
+	// RemoteHosts are the machines, by name, that instances can run on over SSH.
+	RemoteHosts map[string]RemoteHost `json:"remote_hosts,omitempty"`
+	// Container runs the programs of new local instances in Docker containers.
+	Container *ContainerConfig `json:"container,omitempty"`
+	// TickRates are how often instances are checked for changes. Nil uses the defaults.
+	TickRates *TickRates `json:"tick_rates,omitempty"`
+	// StorageBackend is where instances are stored: "json" keeps them in the state file and
+	// "sqlite" in a database that also keeps the history of killed instances. Empty means json.
+	StorageBackend string `json:"storage_backend,omitempty"`
+
+	// policyOverrides names the fields whose value the policy changed
+	policyOverrides []string
+}
+
+// PolicyOverrides returns the names of the config fields the policy changed.
+func (c *Config) PolicyOverrides() []string {
+	return c.policyOverrides
 }
 
 // RepoConfig represents per-repository configuration
@@ -55,6 +171,57 @@ type RepoConfig struct {
 	IdeCommand string `json:"ide_command,omitempty"`
 	// DiffCommand is the external diff command to use for this repository
 	DiffCommand string `json:"diff_command,omitempty"`
+	// MergeCommand is the external merge tool command to use for this repository
+	MergeCommand string `json:"merge_command,omitempty"`
+	// Forge is the code host the repository lives on: "github" or "gitlab". Empty detects it
+	// from the origin remote.
+	Forge string `json:"forge,omitempty"`
+	// TestRunner is the test command the test tab runs for this repository
+	TestRunner *TestRunnerConfig `json:"test_runner,omitempty"`
+	// Services are the dev servers instances of this repository can run
+	Services []ServiceConfig `json:"services,omitempty"`
+}
+
+// ServiceConfig is a dev server an instance can run next to its agent, e.g. "npm run dev".
+type ServiceConfig struct {
+	// Name identifies the service, e.g. "web"
+	Name string `json:"name"`
+	// Command is the shell command that runs the service. It's given the port assigned to the
+	// instance in $PORT.
+	Command string `json:"command"`
+	// Port is the port the service prefers. Each instance gets the first free port from it, so
+	// parallel instances don't collide.
+	Port int `json:"port"`
+	// Dir is the directory to run the command in, relative to the worktree root. Empty means the
+	// worktree root.
+	Dir string `json:"dir,omitempty"`
+	// URL is where the service is browsed, with {port} replaced by the instance's port. Empty
+	// means http://localhost:{port}.
+	URL string `json:"url,omitempty"`
+}
+
+// TestRunnerConfig is a test command and how to find failed test files in its output.
+type TestRunnerConfig struct {
+	// Command is the shell command that runs the tests, e.g. "go test ./..." or "pytest"
+	Command string `json:"command"`
+	// Parser names the parser reading the output: "jest", "go", "pytest", "cargo" or "none".
+	// Empty picks one from the command.
+	Parser string `json:"parser,omitempty"`
+	// FailurePattern is a regular expression whose first group captures the path of a failed
+	// test file in an output line. It replaces the parser when set.
+	FailurePattern string `json:"failure_pattern,omitempty"`
+	// Dir is the directory to run the command in, relative to the worktree root. Empty means the
+	// worktree root.
+	Dir string `json:"dir,omitempty"`
+}
+
+// URLFor returns the service's URL for an instance running it on port.
+func (s ServiceConfig) URLFor(port int) string {
+	url := s.URL
+	if url == "" {
+		url = "http://localhost:{port}"
+	}
+	return strings.ReplaceAll(url, "{port}", strconv.Itoa(port))
 }
 
 // DefaultConfig returns the default configuration
@@ -77,11 +244,64 @@ func DefaultConfig() *Config {
 			}
 			return fmt.Sprintf("%s/", strings.ToLower(user.Username))
 		}(),
-		DefaultIdeCommand:  "webstorm",
-		DefaultDiffCommand: "",
+		DefaultIdeCommand:        "webstorm",
+		DefaultDiffCommand:       "",
+		ToastDurations:           defaultToastDurations(),
+		ShareAddr:                "localhost:7433",
+		BackupIntervalMinutes:    60,
+		BackupCount:              10,
+		SnapshotIntervalSeconds:  30,
+		PRCommentPollMinutes:     5,
+		CommitHistoryDepth:       20,
+		CoordinatorDigestMinutes: 10,
+	}
+}
+
+// defaultToastDurations returns the default auto-dismiss time (ms) for each toast level.
+func defaultToastDurations() map[string]int {
+	return map[string]int{
+		"info":    3000,
+		"success": 3000,
+		"warning": 5000,
+		"error":   5000,
 	}
 }
 
+// GetToastDuration returns the configured auto-dismiss time for the given toast level, or 0
+// if none is configured.
+func (c *Config) GetToastDuration(level string) time.Duration {
+	ms, ok := c.ToastDurations[level]
+	if !ok || ms <= 0 {
+		return 0
+	}
+	return time.Duration(ms) * time.Millisecond
+}
+
+// ShareServerEnabled returns true if shared diff pages should be served over HTTP.
+func (c *Config) ShareServerEnabled() bool {
+	return c.ShareAddr != "" && c.ShareAddr != "off"
+}
+
+// GetHistoryExportDir returns the directory AI histories are exported to.
+func (c *Config) GetHistoryExportDir() (string, error) {
+	dir := c.HistoryExportDir
+	if dir == "" {
+		configDir, err := GetConfigDir()
+		if err != nil {
+			return "", err
+		}
+		return filepath.Join(configDir, HistoryDirName), nil
+	}
+	if rest, ok := strings.CutPrefix(dir, "~"); ok && (rest == "" || strings.HasPrefix(rest, "/")) {
+		homeDir, err := os.UserHomeDir()
+		if err != nil {
+			return "", fmt.Errorf("failed to get home directory: %w", err)
+		}
+		dir = filepath.Join(homeDir, rest)
+	}
+	return dir, nil
+}
+
 // GetClaudeCommand attempts to find the "claude" command in the user's shell
 // It checks in the following order:
 // 1. Shell alias resolution: using "which" command
@@ -130,7 +350,25 @@ func GetClaudeCommand() (string, error) {
 	return "", fmt.Errorf("claude command not found in aliases or PATH")
 }
 
+// LoadConfig loads the user's config with the settings enforced by the policy applied.
 func LoadConfig() *Config {
+	cfg := loadUserConfig()
+	policy, err := LoadPolicy()
+	if err != nil {
+		log.ErrorLog.Printf("failed to load policy: %v", err)
+		return cfg
+	}
+	overridden, err := policy.apply(cfg)
+	if err != nil {
+		log.ErrorLog.Printf("failed to apply policy %s: %v", policy.Source, err)
+		return cfg
+	}
+	cfg.policyOverrides = overridden
+	return cfg
+}
+
+// loadUserConfig loads the config file, creating it with the defaults if it doesn't exist.
+func loadUserConfig() *Config {
 	configDir, err := GetConfigDir()
 	if err != nil {
 		log.ErrorLog.Printf("failed to get config directory: %v", err)
@@ -167,6 +405,41 @@ func LoadConfig() *Config {
 	if config.DefaultDiffCommand == "" {
 		config.DefaultDiffCommand = defaults.DefaultDiffCommand
 	}
+	if config.BackupIntervalMinutes <= 0 {
+		config.BackupIntervalMinutes = defaults.BackupIntervalMinutes
+	}
+	if config.BackupCount <= 0 {
+		config.BackupCount = defaults.BackupCount
+	}
+	if config.SnapshotIntervalSeconds <= 0 {
+		config.SnapshotIntervalSeconds = defaults.SnapshotIntervalSeconds
+	}
+	if config.PRCommentPollMinutes == 0 {
+		config.PRCommentPollMinutes = defaults.PRCommentPollMinutes
+	}
+	if config.CommitHistoryDepth <= 0 {
+		config.CommitHistoryDepth = defaults.CommitHistoryDepth
+	}
+	if config.CoordinatorDigestMinutes <= 0 {
+		config.CoordinatorDigestMinutes = defaults.CoordinatorDigestMinutes
+	}
+	if config.MainWatchPolicy != "" && config.MainWatchPolicy != MainWatchFlag && config.MainWatchPolicy != MainWatchRebase {
+		log.WarningLog.Printf("unknown main_watch_policy %q (expected %q or %q); only flagging instances behind main",
+			config.MainWatchPolicy, MainWatchFlag, MainWatchRebase)
+		config.MainWatchPolicy = MainWatchFlag
+	}
+	if config.ShareAddr == "" {
+		config.ShareAddr = defaults.ShareAddr
+	}
+	if config.ToastDurations == nil {
+		config.ToastDurations = defaults.ToastDurations
+	} else {
+		for level, ms := range defaults.ToastDurations {
+			if _, ok := config.ToastDurations[level]; !ok {
+				config.ToastDurations[level] = ms
+			}
+		}
+	}
 
 	return &config
 }
@@ -265,6 +538,24 @@ func loadRepoConfigFromCLAUDEMD(repoPath string) *RepoConfig {
 		config.DiffCommand = strings.TrimSpace(diffMatches[1])
 	}
 
+	// Parse merge_command
+	if mergeMatches := mergeCommandRe.FindStringSubmatch(configSection); len(mergeMatches) > 1 {
+		config.MergeCommand = strings.TrimSpace(mergeMatches[1])
+	}
+
+	// Parse forge
+	if forgeMatches := forgeRe.FindStringSubmatch(configSection); len(forgeMatches) > 1 {
+		config.Forge = strings.TrimSpace(forgeMatches[1])
+	}
+
+	// Parse test_command and test_parser
+	if testMatches := testCommandRe.FindStringSubmatch(configSection); len(testMatches) > 1 {
+		config.TestRunner = &TestRunnerConfig{Command: strings.TrimSpace(testMatches[1])}
+		if parserMatches := testParserRe.FindStringSubmatch(configSection); len(parserMatches) > 1 {
+			config.TestRunner.Parser = strings.TrimSpace(parserMatches[1])
+		}
+	}
+
 	return config
 }
 
@@ -280,6 +571,18 @@ func GetEffectiveIdeCommand(repoPath string, globalConfig *Config) string {
 	return "webstorm" // fallback
 }
 
+// GetEffectiveTestRunner returns the test runner to use, checking repo config first, then global
+// config. It returns nil if neither configures one.
+func GetEffectiveTestRunner(repoPath string, globalConfig *Config) *TestRunnerConfig {
+	if repoConfig := LoadRepoConfig(repoPath); repoConfig.TestRunner != nil && repoConfig.TestRunner.Command != "" {
+		return repoConfig.TestRunner
+	}
+	if globalConfig != nil && globalConfig.TestRunner != nil && globalConfig.TestRunner.Command != "" {
+		return globalConfig.TestRunner
+	}
+	return nil
+}
+
 // GetEffectiveDiffCommand returns the diff command to use, checking repo config first, then global config
 func GetEffectiveDiffCommand(repoPath string, globalConfig *Config) string {
 	repoConfig := LoadRepoConfig(repoPath)
@@ -291,3 +594,16 @@ func GetEffectiveDiffCommand(repoPath string, globalConfig *Config) string {
 	}
 	return "" // empty means use built-in diff viewer
 }
+
+// GetEffectiveMergeCommand returns the merge tool command to use, checking repo config first,
+// then global config. Empty means none is configured.
+func GetEffectiveMergeCommand(repoPath string, globalConfig *Config) string {
+	repoConfig := LoadRepoConfig(repoPath)
+	if repoConfig.MergeCommand != "" {
+		return repoConfig.MergeCommand
+	}
+	if globalConfig != nil {
+		return globalConfig.DefaultMergeCommand
+	}
+	return ""
+}
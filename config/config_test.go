@@ -7,6 +7,7 @@ import (
 	"regexp"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -108,6 +109,8 @@ func TestDefaultConfig(t *testing.T) {
 		assert.Equal(t, 1000, config.DaemonPollInterval)
 		assert.NotEmpty(t, config.BranchPrefix)
 		assert.True(t, strings.HasSuffix(config.BranchPrefix, "/"))
+		assert.Equal(t, 20, config.CommitHistoryDepth)
+		assert.Equal(t, 10, config.CoordinatorDigestMinutes)
 	})
 
 }
@@ -125,6 +128,24 @@ func TestGetConfigDir(t *testing.T) {
 	})
 }
 
+func TestGetHistoryExportDir(t *testing.T) {
+	configDir, err := GetConfigDir()
+	require.NoError(t, err)
+	homeDir, err := os.UserHomeDir()
+	require.NoError(t, err)
+
+	for configured, want := range map[string]string{
+		"":              filepath.Join(configDir, HistoryDirName),
+		"~/notes/squad": filepath.Join(homeDir, "notes", "squad"),
+		"/tmp/history":  "/tmp/history",
+		"~other/dir":    "~other/dir",
+	} {
+		dir, err := (&Config{HistoryExportDir: configured}).GetHistoryExportDir()
+		assert.NoError(t, err)
+		assert.Equal(t, want, dir, "history_export_dir %q", configured)
+	}
+}
+
 func TestLoadConfig(t *testing.T) {
 	t.Run("returns default config when file doesn't exist", func(t *testing.T) {
 		// Use a temporary home directory to avoid interfering with real config
@@ -200,6 +221,20 @@ func TestLoadConfig(t *testing.T) {
 		assert.False(t, config.AutoYes)                  // Default value
 		assert.Equal(t, 1000, config.DaemonPollInterval) // Default value
 	})
+
+	t.Run("falls back to flagging on an unknown main watch policy", func(t *testing.T) {
+		tempHome := t.TempDir()
+		configDir := filepath.Join(tempHome, ".claude-squad")
+		require.NoError(t, os.MkdirAll(configDir, 0755))
+		configContent := `{"main_watch_minutes": 10, "main_watch_policy": "merge"}`
+		require.NoError(t, os.WriteFile(filepath.Join(configDir, ConfigFileName), []byte(configContent), 0644))
+		t.Setenv("HOME", tempHome)
+
+		config := LoadConfig()
+
+		assert.Equal(t, 10, config.MainWatchMinutes)
+		assert.Equal(t, MainWatchFlag, config.MainWatchPolicy)
+	})
 }
 
 func TestSaveConfig(t *testing.T) {
@@ -237,3 +272,130 @@ func TestSaveConfig(t *testing.T) {
 		assert.Equal(t, testConfig.BranchPrefix, loadedConfig.BranchPrefix)
 	})
 }
+
+func TestGetEffectiveTestRunner(t *testing.T) {
+	global := &Config{TestRunner: &TestRunnerConfig{Command: "make test"}}
+
+	repo := t.TempDir()
+	assert.Equal(t, "make test", GetEffectiveTestRunner(repo, global).Command)
+	assert.Nil(t, GetEffectiveTestRunner(repo, &Config{}))
+
+	claudeMD := "# Notes\n\n[claude-squad]\ntest_command: go test ./...\ntest_parser: go\n"
+	require.NoError(t, os.WriteFile(filepath.Join(repo, "CLAUDE.md"), []byte(claudeMD), 0644))
+	runner := GetEffectiveTestRunner(repo, global)
+	assert.Equal(t, &TestRunnerConfig{Command: "go test ./...", Parser: "go"}, runner)
+
+	require.NoError(t, os.MkdirAll(filepath.Join(repo, ".claude-squad"), 0755))
+	repoJSON := `{"test_runner": {"command": "pytest", "dir": "api"}}`
+	require.NoError(t, os.WriteFile(filepath.Join(repo, ".claude-squad", "config.json"), []byte(repoJSON), 0644))
+	runner = GetEffectiveTestRunner(repo, global)
+	assert.Equal(t, &TestRunnerConfig{Command: "pytest", Dir: "api"}, runner)
+}
+
+func TestRepos(t *testing.T) {
+	tempHome := t.TempDir()
+	originalHome := os.Getenv("HOME")
+	os.Setenv("HOME", tempHome)
+	defer os.Setenv("HOME", originalHome)
+
+	require.NoError(t, SaveConfig(&Config{DefaultProgram: "aider"}))
+
+	repos, err := AddRepo("/src/api")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"/src/api"}, repos)
+
+	// Adding a repository twice keeps one entry
+	repos, err = AddRepo("/src/web/../api")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"/src/api"}, repos)
+
+	_, err = AddRepo("/src/web")
+	require.NoError(t, err)
+	repos, err = RemoveRepo("/src/api")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"/src/web"}, repos)
+
+	// The rest of the config file is kept
+	loaded := LoadConfig()
+	assert.Equal(t, []string{"/src/web"}, loaded.Repos)
+	assert.Equal(t, "aider", loaded.DefaultProgram)
+}
+
+func TestRemoteHost(t *testing.T) {
+	home, err := os.UserHomeDir()
+	require.NoError(t, err)
+
+	host := RemoteHost{Host: "build-box", KeyFile: "~/.ssh/build", RepoPath: "/srv/src/api"}
+	assert.Equal(t, "/srv/src/claude-squad-worktrees", host.Worktrees())
+	assert.Equal(t, home+"/.ssh/build", host.Key())
+
+	host.WorktreeDir = "/scratch/worktrees"
+	host.KeyFile = "/keys/build"
+	assert.Equal(t, "/scratch/worktrees", host.Worktrees())
+	assert.Equal(t, "/keys/build", host.Key())
+}
+
+func TestContainersEnabled(t *testing.T) {
+	cfg := DefaultConfig()
+	assert.False(t, cfg.ContainersEnabled())
+
+	cfg.Container = &ContainerConfig{Enabled: true}
+	assert.False(t, cfg.ContainersEnabled(), "containers need an image")
+
+	cfg.Container.Image = "agent:latest"
+	assert.True(t, cfg.ContainersEnabled())
+}
+
+func TestServiceURL(t *testing.T) {
+	assert.Equal(t, "http://localhost:3001", ServiceConfig{Name: "web"}.URLFor(3001))
+	assert.Equal(t, "https://app.localhost:8443/login?port=8443",
+		ServiceConfig{URL: "https://app.localhost:{port}/login?port={port}"}.URLFor(8443))
+}
+
+func TestAutoYesPolicy(t *testing.T) {
+	var none *AutoYesPolicy
+	confirmed, _ := none.Confirms("Delete files?")
+	assert.True(t, confirmed, "no policy confirms every prompt")
+
+	policy := &AutoYesPolicy{Allow: []string{`Run tests\?`, `(?i)proceed`}, Deny: []string{`(?i)delete`}}
+	confirmed, _ = policy.Confirms("Do you want to Run tests?")
+	assert.True(t, confirmed)
+	confirmed, _ = policy.Confirms("Proceed?")
+	assert.True(t, confirmed)
+	confirmed, reason := policy.Confirms("Delete files?")
+	assert.False(t, confirmed)
+	assert.Contains(t, reason, "deny")
+	confirmed, reason = policy.Confirms("Proceed and delete files?")
+	assert.False(t, confirmed, "deny wins over allow")
+	assert.Contains(t, reason, "deny")
+	confirmed, reason = policy.Confirms("Edit main.go?")
+	assert.False(t, confirmed)
+	assert.Equal(t, "matches no allow pattern", reason)
+
+	policy.Allow = nil
+	confirmed, _ = policy.Confirms("Edit main.go?")
+	assert.True(t, confirmed, "an empty allow list allows what isn't denied")
+
+	policy.Deny = []string{"(unclosed"}
+	assert.Error(t, policy.Validate())
+	confirmed, _ = policy.Confirms("Edit main.go?")
+	assert.False(t, confirmed, "an invalid pattern confirms nothing")
+	assert.Error(t, SaveAutoYesPolicy(policy))
+}
+
+func TestTickRates(t *testing.T) {
+	cfg := DefaultConfig()
+	assert.Equal(t, DefaultTickRates, cfg.GetTickRates())
+
+	cfg.TickRates = &TickRates{IdleMs: 10000, RunningMs: -1}
+	rates := cfg.GetTickRates()
+	assert.Equal(t, 10*time.Second, rates.MetadataInterval(false, false))
+	assert.Equal(t, time.Second, rates.MetadataInterval(false, true), "non-positive rates use the default")
+	assert.Equal(t, 500*time.Millisecond, rates.MetadataInterval(true, false), "the selected instance is checked fastest")
+	assert.Equal(t, 500*time.Millisecond, rates.MetadataTick())
+	assert.Equal(t, 100*time.Millisecond, rates.PreviewInterval(true))
+	assert.Equal(t, 500*time.Millisecond, rates.PreviewInterval(false))
+
+	cfg.TickRates = &TickRates{SelectedMs: 2000, RunningMs: 250}
+	assert.Equal(t, 250*time.Millisecond, cfg.GetTickRates().MetadataTick())
+}
@@ -0,0 +1,18 @@
+package config
+
+// ContainerConfig runs the programs of new local instances in Docker containers, each with only
+// its worktree and the repository's git directory mounted, to keep the agent away from the rest
+// of the filesystem.
+type ContainerConfig struct {
+	// Enabled turns containers on for new instances. Existing instances keep how they run.
+	Enabled bool `json:"enabled"`
+	// Image is the Docker image to run. It needs the program, git and sleep installed.
+	Image string `json:"image"`
+	// RunArgs are extra docker run arguments, e.g. ["-e", "ANTHROPIC_API_KEY"].
+	RunArgs []string `json:"run_args,omitempty"`
+}
+
+// ContainersEnabled returns true if new local instances run in containers.
+func (c *Config) ContainersEnabled() bool {
+	return c.Container != nil && c.Container.Enabled && c.Container.Image != ""
+}
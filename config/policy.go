@@ -0,0 +1,237 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"sort"
+	"strings"
+)
+
+// Policy holds organization-wide rules managed by an administrator. It is read from a file users
+// can't write and takes precedence over their config. Every rule is optional.
+type Policy struct {
+	// Settings overrides config fields by their JSON name, e.g. {"auto_yes": false}.
+	Settings map[string]json.RawMessage `json:"settings,omitempty"`
+	// DisableForcePush forbids pushes that can overwrite the remote branch.
+	DisableForcePush bool `json:"disable_force_push,omitempty"`
+	// PrePushChecks are shell commands run in the worktree before an instance's branch is pushed.
+	// The push is stopped if any of them fails.
+	PrePushChecks []string `json:"pre_push_checks,omitempty"`
+	// AllowedPrograms lists the executables instances may run, e.g. ["claude", "aider"]. Empty
+	// allows any program.
+	AllowedPrograms []string `json:"allowed_programs,omitempty"`
+	// MaxInstances caps how many instances can exist at once. Zero keeps the built-in limit.
+	MaxInstances int `json:"max_instances,omitempty"`
+
+	// Source is the file the policy was read from, empty when there is no policy.
+	Source string `json:"-"`
+}
+
+// policyPath is where the policy file is read from. Users can't point it elsewhere, as a missing
+// file would mean no policy; tests replace it.
+var policyPath = systemPolicyPath()
+
+// PolicyPath returns where the policy file is read from: a system-wide location only
+// administrators can write.
+func PolicyPath() string {
+	return policyPath
+}
+
+// systemPolicyPath returns the system-wide location of the policy file.
+func systemPolicyPath() string {
+	if runtime.GOOS == "windows" {
+		programData := os.Getenv("ProgramData")
+		if programData == "" {
+			programData = `C:\ProgramData`
+		}
+		return filepath.Join(programData, "claude-squad", "policy.json")
+	}
+	return "/etc/claude-squad/policy.json"
+}
+
+// LoadPolicy reads the policy file. A missing file is an empty policy; a file that can't be read
+// or parsed is an error, so a broken policy isn't silently ignored.
+func LoadPolicy() (*Policy, error) {
+	path := PolicyPath()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return &Policy{}, nil
+		}
+		return nil, fmt.Errorf("failed to read policy %s: %w", path, err)
+	}
+	var policy Policy
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&policy); err != nil {
+		return nil, fmt.Errorf("failed to parse policy %s: %w", path, err)
+	}
+	policy.Source = path
+	return &policy, nil
+}
+
+// Active reports whether a policy file was found.
+func (p *Policy) Active() bool {
+	return p != nil && p.Source != ""
+}
+
+// violation formats a policy violation so it names the policy file.
+func (p *Policy) violation(format string, args ...any) error {
+	return fmt.Errorf("%s (policy %s)", fmt.Sprintf(format, args...), p.Source)
+}
+
+// CheckProgram returns an error if the policy doesn't allow the program's executable.
+func (p *Policy) CheckProgram(program string) error {
+	if p == nil || len(p.AllowedPrograms) == 0 {
+		return nil
+	}
+	name := programName(program)
+	for _, allowed := range p.AllowedPrograms {
+		if name == allowed {
+			return nil
+		}
+	}
+	return p.violation("program %q is not allowed; allowed programs: %s", name, strings.Join(p.AllowedPrograms, ", "))
+}
+
+// InstanceLimit returns the policy's instance limit if it is below the built-in one. A built-in
+// limit of zero means no limit.
+func (p *Policy) InstanceLimit(builtin int) int {
+	if p != nil && p.MaxInstances > 0 && (builtin <= 0 || p.MaxInstances < builtin) {
+		return p.MaxInstances
+	}
+	return builtin
+}
+
+// CheckForcePush returns an error if the policy forbids force pushes.
+func (p *Policy) CheckForcePush() error {
+	if p != nil && p.DisableForcePush {
+		return p.violation("force pushes are disabled")
+	}
+	return nil
+}
+
+// Enforces reports whether the policy sets the config field with the given JSON name, so users
+// can't change it.
+func (p *Policy) Enforces(field string) bool {
+	if p == nil {
+		return false
+	}
+	_, ok := p.Settings[field]
+	return ok
+}
+
+// CheckAutoYes returns an error if auto-yes is turned on while the policy enforces it off.
+func (p *Policy) CheckAutoYes(enable bool) error {
+	if !enable || !p.Enforces("auto_yes") {
+		return nil
+	}
+	var enforced bool
+	if err := json.Unmarshal(p.Settings["auto_yes"], &enforced); err != nil || !enforced {
+		return p.violation("auto-yes is disabled")
+	}
+	return nil
+}
+
+// Violations describes where the config and the programs of existing instances break the policy.
+func (p *Policy) Violations(cfg *Config, programs map[string]string) []string {
+	if !p.Active() {
+		return nil
+	}
+	var violations []string
+	if overridden := cfg.PolicyOverrides(); len(overridden) > 0 {
+		violations = append(violations, fmt.Sprintf("enforced settings override your config: %s",
+			strings.Join(overridden, ", ")))
+	}
+	if err := p.CheckProgram(cfg.DefaultProgram); err != nil {
+		violations = append(violations, fmt.Sprintf("default program %q is not allowed", programName(cfg.DefaultProgram)))
+	}
+	var titles []string
+	for title, program := range programs {
+		if p.CheckProgram(program) != nil {
+			titles = append(titles, title)
+		}
+	}
+	if len(titles) > 0 {
+		sort.Strings(titles)
+		violations = append(violations, fmt.Sprintf("instances run programs that are not allowed: %s",
+			strings.Join(titles, ", ")))
+	}
+	return violations
+}
+
+// apply overwrites the config fields named in the policy's settings and returns the names of
+// those whose value it changed.
+func (p *Policy) apply(cfg *Config) ([]string, error) {
+	if p == nil || len(p.Settings) == 0 {
+		return nil, nil
+	}
+	data, err := json.Marshal(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal config: %w", err)
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal config: %w", err)
+	}
+
+	known := configFieldNames()
+	var overridden []string
+	for name, value := range p.Settings {
+		if !known[name] {
+			return nil, fmt.Errorf("unknown setting %q", name)
+		}
+		if current, ok := fields[name]; !ok || !jsonEqual(current, value) {
+			overridden = append(overridden, name)
+		}
+		fields[name] = value
+	}
+	if data, err = json.Marshal(fields); err != nil {
+		return nil, fmt.Errorf("failed to marshal policy settings: %w", err)
+	}
+	var enforced Config
+	if err := json.Unmarshal(data, &enforced); err != nil {
+		return nil, fmt.Errorf("invalid policy settings: %w", err)
+	}
+	*cfg = enforced
+	sort.Strings(overridden)
+	return overridden, nil
+}
+
+// configFieldNames returns the JSON names of the config fields.
+func configFieldNames() map[string]bool {
+	names := make(map[string]bool)
+	configType := reflect.TypeOf(Config{})
+	for i := 0; i < configType.NumField(); i++ {
+		name, _, _ := strings.Cut(configType.Field(i).Tag.Get("json"), ",")
+		if name != "" && name != "-" {
+			names[name] = true
+		}
+	}
+	return names
+}
+
+// jsonEqual compares two JSON values regardless of formatting.
+func jsonEqual(a, b json.RawMessage) bool {
+	var x, y any
+	if json.Unmarshal(a, &x) != nil || json.Unmarshal(b, &y) != nil {
+		return false
+	}
+	xs, _ := json.Marshal(x)
+	ys, _ := json.Marshal(y)
+	return bytes.Equal(xs, ys)
+}
+
+// programName returns the executable name of a program command line.
+func programName(program string) string {
+	fields := strings.Fields(program)
+	if len(fields) == 0 {
+		return ""
+	}
+	return filepath.Base(fields[0])
+}
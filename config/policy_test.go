@@ -0,0 +1,119 @@
+package config
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writePolicy(t *testing.T, body string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "policy.json")
+	require.NoError(t, os.WriteFile(path, []byte(body), 0644))
+	usePolicyPath(t, path)
+	return path
+}
+
+// usePolicyPath reads the policy from path for the rest of the test.
+func usePolicyPath(t *testing.T, path string) {
+	t.Helper()
+	previous := policyPath
+	policyPath = path
+	t.Cleanup(func() { policyPath = previous })
+}
+
+func TestLoadPolicy(t *testing.T) {
+	t.Run("missing file is an empty policy", func(t *testing.T) {
+		usePolicyPath(t, filepath.Join(t.TempDir(), "none.json"))
+		policy, err := LoadPolicy()
+		require.NoError(t, err)
+		assert.False(t, policy.Active())
+		assert.NoError(t, policy.CheckProgram("anything"))
+		assert.Equal(t, 10, policy.InstanceLimit(10))
+	})
+
+	t.Run("reads the rules", func(t *testing.T) {
+		path := writePolicy(t, `{"disable_force_push": true, "allowed_programs": ["claude"], "max_instances": 3}`)
+		policy, err := LoadPolicy()
+		require.NoError(t, err)
+		assert.Equal(t, path, policy.Source)
+		assert.Error(t, policy.CheckForcePush())
+		assert.NoError(t, policy.CheckProgram("/usr/local/bin/claude --model opus"))
+		assert.ErrorContains(t, policy.CheckProgram("aider"), "not allowed")
+		assert.Equal(t, 3, policy.InstanceLimit(10))
+		assert.Equal(t, 3, policy.InstanceLimit(0))
+		assert.Equal(t, 2, policy.InstanceLimit(2))
+	})
+
+	t.Run("rejects unknown rules", func(t *testing.T) {
+		writePolicy(t, `{"disable_force_pushes": true}`)
+		_, err := LoadPolicy()
+		assert.Error(t, err)
+	})
+}
+
+func TestPolicyApply(t *testing.T) {
+	cfg := &Config{DefaultProgram: "claude", AutoYes: true, BranchPrefix: "me/"}
+	enforced := &Policy{Source: "p", Settings: map[string]json.RawMessage{
+		"auto_yes":      json.RawMessage(`false`),
+		"branch_prefix": json.RawMessage(`"me/"`),
+	}}
+	overridden, err := enforced.apply(cfg)
+	require.NoError(t, err)
+	assert.False(t, cfg.AutoYes)
+	assert.Equal(t, "claude", cfg.DefaultProgram)
+	assert.Equal(t, []string{"auto_yes"}, overridden)
+
+	_, err = (&Policy{Settings: map[string]json.RawMessage{"no_such_setting": json.RawMessage(`1`)}}).apply(cfg)
+	assert.Error(t, err)
+}
+
+func TestPolicyAutoYes(t *testing.T) {
+	var none *Policy
+	assert.False(t, none.Enforces("auto_yes"))
+	assert.NoError(t, none.CheckAutoYes(true))
+
+	off := &Policy{Settings: map[string]json.RawMessage{"auto_yes": json.RawMessage("false")}, Source: "policy.json"}
+	assert.True(t, off.Enforces("auto_yes"))
+	assert.False(t, off.Enforces("default_program"))
+	assert.ErrorContains(t, off.CheckAutoYes(true), "auto-yes is disabled")
+	assert.NoError(t, off.CheckAutoYes(false))
+
+	on := &Policy{Settings: map[string]json.RawMessage{"auto_yes": json.RawMessage("true")}, Source: "policy.json"}
+	assert.NoError(t, on.CheckAutoYes(true))
+}
+
+func TestPolicyViolations(t *testing.T) {
+	policy := &Policy{Source: "p", AllowedPrograms: []string{"claude"}}
+	cfg := &Config{DefaultProgram: "aider", policyOverrides: []string{"auto_yes"}}
+
+	violations := policy.Violations(cfg, map[string]string{"a": "claude", "b": "codex", "c": "aider --yes"})
+	assert.Equal(t, []string{
+		"enforced settings override your config: auto_yes",
+		`default program "aider" is not allowed`,
+		"instances run programs that are not allowed: b, c",
+	}, violations)
+
+	assert.Empty(t, (&Policy{}).Violations(cfg, nil))
+}
+
+func TestLoadConfigAppliesPolicy(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	writePolicy(t, `{"settings": {"auto_yes": true, "backup_count": 2}}`)
+
+	cfg := LoadConfig()
+	assert.True(t, cfg.AutoYes)
+	assert.Equal(t, 2, cfg.BackupCount)
+	assert.Equal(t, []string{"auto_yes", "backup_count"}, cfg.PolicyOverrides())
+
+	// The enforced values aren't written to the user's config
+	configDir, err := GetConfigDir()
+	require.NoError(t, err)
+	data, err := os.ReadFile(filepath.Join(configDir, ConfigFileName))
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"auto_yes": false`)
+}
@@ -0,0 +1,122 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// ProgramSuggestion is a program a repository's contents suggest running in its instances.
+type ProgramSuggestion struct {
+	Program string
+	// Reason explains what in the repository suggested the program
+	Reason string
+}
+
+// programMarkers maps files agents keep in a repository to the agent, checked in order.
+var programMarkers = []struct {
+	path    string
+	program string
+}{
+	{"CLAUDE.md", "claude"},
+	{".claude", "claude"},
+	{".aider.conf.yml", "aider"},
+	{".aider.conf.yaml", "aider"},
+	{"AGENTS.md", "codex"},
+	{".codex", "codex"},
+	{"GEMINI.md", "gemini"},
+	{".gemini", "gemini"},
+}
+
+// knownAgents are the programs package.json scripts are checked for.
+var knownAgents = map[string]bool{"claude": true, "aider": true, "codex": true, "gemini": true}
+
+// DetectPrograms inspects the repository at repoPath for agent config files and package.json
+// scripts that run an agent, and returns the programs they suggest without duplicates.
+func DetectPrograms(repoPath string) []ProgramSuggestion {
+	var suggestions []ProgramSuggestion
+	seen := make(map[string]bool)
+	add := func(program, reason string) {
+		if seen[program] {
+			return
+		}
+		seen[program] = true
+		suggestions = append(suggestions, ProgramSuggestion{Program: program, Reason: reason})
+	}
+
+	for _, marker := range programMarkers {
+		if _, err := os.Stat(filepath.Join(repoPath, marker.path)); err == nil {
+			add(marker.program, marker.path+" found")
+		}
+	}
+
+	scripts, err := agentScripts(repoPath)
+	if err != nil {
+		return suggestions
+	}
+	names := make([]string, 0, len(scripts))
+	for name := range scripts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	runner := scriptRunner(repoPath)
+	for _, name := range names {
+		add(runner+" "+name, fmt.Sprintf("package.json script %q: %s", name, scripts[name]))
+	}
+	return suggestions
+}
+
+// agentScripts returns the package.json scripts in repoPath whose command runs a known agent,
+// keyed by name.
+func agentScripts(repoPath string) (map[string]string, error) {
+	data, err := os.ReadFile(filepath.Join(repoPath, "package.json"))
+	if err != nil {
+		return nil, err
+	}
+	var pkg struct {
+		Scripts map[string]string `json:"scripts"`
+	}
+	if err := json.Unmarshal(data, &pkg); err != nil {
+		return nil, fmt.Errorf("failed to parse package.json: %w", err)
+	}
+
+	scripts := make(map[string]string)
+	for name, command := range pkg.Scripts {
+		for _, word := range strings.Fields(command) {
+			if knownAgents[programName(word)] {
+				scripts[name] = command
+				break
+			}
+		}
+	}
+	return scripts, nil
+}
+
+// scriptRunner returns the command running package.json scripts with the repository's package
+// manager, picked from its lock file.
+func scriptRunner(repoPath string) string {
+	switch {
+	case fileExists(filepath.Join(repoPath, "pnpm-lock.yaml")):
+		return "pnpm run"
+	case fileExists(filepath.Join(repoPath, "yarn.lock")):
+		return "yarn run"
+	case fileExists(filepath.Join(repoPath, "bun.lockb")), fileExists(filepath.Join(repoPath, "bun.lock")):
+		return "bun run"
+	default:
+		return "npm run"
+	}
+}
+
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
+
+// SameProgram returns true if two program strings run the same executable, ignoring its
+// directory and arguments.
+func SameProgram(a, b string) bool {
+	return programName(a) != "" && programName(a) == programName(b)
+}
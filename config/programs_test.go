@@ -0,0 +1,37 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDetectPrograms(t *testing.T) {
+	dir := t.TempDir()
+	assert.Empty(t, DetectPrograms(dir))
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "CLAUDE.md"), nil, 0644))
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, ".claude"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, ".aider.conf.yml"), nil, 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "yarn.lock"), nil, 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "package.json"), []byte(`{
+		"scripts": {
+			"test": "jest",
+			"pair": "aider --model sonnet",
+			"agent": "npx @openai/codex"
+		}
+	}`), 0644))
+
+	assert.Equal(t, []ProgramSuggestion{
+		{Program: "claude", Reason: "CLAUDE.md found"},
+		{Program: "aider", Reason: ".aider.conf.yml found"},
+		{Program: "yarn run agent", Reason: `package.json script "agent": npx @openai/codex`},
+		{Program: "yarn run pair", Reason: `package.json script "pair": aider --model sonnet`},
+	}, DetectPrograms(dir))
+
+	assert.True(t, SameProgram("/usr/local/bin/claude", "claude --continue"))
+	assert.False(t, SameProgram("claude", "aider"))
+}
@@ -0,0 +1,44 @@
+package config
+
+import (
+	"os"
+	"path"
+	"strings"
+)
+
+// RemoteHost is a machine instances can run on over SSH. It needs tmux, git and the agent
+// installed, a clone of the repository, and key or agent authentication that doesn't prompt.
+type RemoteHost struct {
+	// Host is the name or address of the machine.
+	Host string `json:"host"`
+	// User logs in to the machine. Empty uses ssh's default.
+	User string `json:"user,omitempty"`
+	// KeyFile is the private key to authenticate with; a leading ~ is the home directory. Empty
+	// uses ssh's default.
+	KeyFile string `json:"key_file,omitempty"`
+	// Port is the SSH port. Zero uses ssh's default.
+	Port int `json:"port,omitempty"`
+	// RepoPath is the absolute path of the repository's clone on the machine.
+	RepoPath string `json:"repo_path"`
+	// WorktreeDir is the absolute path on the machine worktrees are created in. Empty uses a
+	// claude-squad-worktrees directory next to the clone.
+	WorktreeDir string `json:"worktree_dir,omitempty"`
+}
+
+// Worktrees returns the directory on the machine worktrees are created in.
+func (h RemoteHost) Worktrees() string {
+	if h.WorktreeDir != "" {
+		return h.WorktreeDir
+	}
+	return path.Join(path.Dir(h.RepoPath), "claude-squad-worktrees")
+}
+
+// Key returns the path of the private key, with a leading ~ expanded.
+func (h RemoteHost) Key() string {
+	if rest, ok := strings.CutPrefix(h.KeyFile, "~"); ok {
+		if home, err := os.UserHomeDir(); err == nil {
+			return home + rest
+		}
+	}
+	return h.KeyFile
+}
@@ -0,0 +1,43 @@
+package config
+
+import (
+	"fmt"
+	"path/filepath"
+)
+
+// AddRepo registers the repository at path so new instances can be created in it, and returns
+// the registered repositories. Only the config file is changed, so values the policy enforces
+// aren't written to it.
+func AddRepo(path string) ([]string, error) {
+	path, err := filepath.Abs(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve repository path: %w", err)
+	}
+	cfg := loadUserConfig()
+	for _, repo := range cfg.Repos {
+		if repo == path {
+			return cfg.Repos, nil
+		}
+	}
+	cfg.Repos = append(cfg.Repos, path)
+	if err := saveConfig(cfg); err != nil {
+		return nil, fmt.Errorf("failed to save repositories: %w", err)
+	}
+	return cfg.Repos, nil
+}
+
+// RemoveRepo unregisters the repository at path and returns the registered repositories.
+func RemoveRepo(path string) ([]string, error) {
+	cfg := loadUserConfig()
+	repos := make([]string, 0, len(cfg.Repos))
+	for _, repo := range cfg.Repos {
+		if repo != path {
+			repos = append(repos, repo)
+		}
+	}
+	cfg.Repos = repos
+	if err := saveConfig(cfg); err != nil {
+		return nil, fmt.Errorf("failed to save repositories: %w", err)
+	}
+	return cfg.Repos, nil
+}
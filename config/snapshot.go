@@ -0,0 +1,106 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// SnapshotFileName is the file the UI state is snapshotted to while the app runs. It is removed
+// when the app quits normally, so finding it at startup means the last run crashed or was killed.
+const SnapshotFileName = "snapshot.json"
+
+// Snapshot is the state of the UI at a point in time, to restore after a crash.
+type Snapshot struct {
+	SavedAt time.Time `json:"saved_at"`
+	// Selected is the title of the selected instance
+	Selected string `json:"selected,omitempty"`
+	// Tab is the name of the tab that was shown, e.g. "diff"
+	Tab string `json:"tab,omitempty"`
+	// DiffOffset is the line the diff was scrolled to
+	DiffOffset int `json:"diff_offset,omitempty"`
+	// PreviewScrolling is true if the preview was scrolled back through the agent's history, to
+	// PreviewOffset
+	PreviewScrolling bool `json:"preview_scrolling,omitempty"`
+	PreviewOffset    int  `json:"preview_offset,omitempty"`
+	// PromptDraft is a prompt for the selected instance that was being written but not yet sent
+	PromptDraft string `json:"prompt_draft,omitempty"`
+}
+
+func snapshotPath() (string, error) {
+	configDir, err := GetConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get config directory: %w", err)
+	}
+	return filepath.Join(configDir, SnapshotFileName), nil
+}
+
+// SaveSnapshot writes the snapshot, replacing the last one atomically so a crash while writing
+// leaves the last one intact.
+func SaveSnapshot(snapshot *Snapshot) error {
+	path, err := snapshotPath()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create config directory: %w", err)
+	}
+	data, err := json.MarshalIndent(snapshot, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal snapshot: %w", err)
+	}
+
+	tmp := path + ".tmp"
+	f, err := os.Create(tmp)
+	if err != nil {
+		return fmt.Errorf("failed to create snapshot: %w", err)
+	}
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return fmt.Errorf("failed to write snapshot: %w", err)
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return fmt.Errorf("failed to sync snapshot: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("failed to close snapshot: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return fmt.Errorf("failed to replace snapshot: %w", err)
+	}
+	return nil
+}
+
+// LoadSnapshot returns the snapshot left by the last run, or nil if it quit normally.
+func LoadSnapshot() (*Snapshot, error) {
+	path, err := snapshotPath()
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to read snapshot: %w", err)
+	}
+	var snapshot Snapshot
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		return nil, fmt.Errorf("failed to parse snapshot: %w", err)
+	}
+	return &snapshot, nil
+}
+
+// ClearSnapshot removes the snapshot, once the app quits normally or it was dealt with.
+func ClearSnapshot() error {
+	path, err := snapshotPath()
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to remove snapshot: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,42 @@
+package config
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSnapshot(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	// A normal quit leaves no snapshot
+	snapshot, err := LoadSnapshot()
+	require.NoError(t, err)
+	assert.Nil(t, snapshot)
+
+	saved := &Snapshot{
+		SavedAt:          time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+		Selected:         "my-task",
+		Tab:              "diff",
+		DiffOffset:       42,
+		PreviewScrolling: true,
+		PreviewOffset:    7,
+		PromptDraft:      "Also add a test",
+	}
+	require.NoError(t, SaveSnapshot(saved))
+	saved.Tab = "terminal"
+	require.NoError(t, SaveSnapshot(saved), "a snapshot replaces the last one")
+
+	snapshot, err = LoadSnapshot()
+	require.NoError(t, err)
+	require.NotNil(t, snapshot)
+	assert.Equal(t, *saved, *snapshot)
+
+	require.NoError(t, ClearSnapshot())
+	snapshot, err = LoadSnapshot()
+	require.NoError(t, err)
+	assert.Nil(t, snapshot)
+	require.NoError(t, ClearSnapshot(), "clearing no snapshot is fine")
+}
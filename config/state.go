@@ -11,6 +11,27 @@ import (
 const (
 	StateFileName     = "state.json"
 	InstancesFileName = "instances.json"
+	OutcomesFileName  = "outcomes.jsonl"
+	// ActivityFileName is the file the activity of killed instances is kept in for reports.
+	ActivityFileName = "activity.jsonl"
+	// MetricsDirName is the directory metrics exports are written to.
+	MetricsDirName = "metrics"
+	// ArchiveDirName is the directory archived sessions are written to.
+	ArchiveDirName = "archive"
+	// HistoryDirName is the default directory AI histories are exported to.
+	HistoryDirName = "history"
+	// ScriptsDirName is the directory the user's Starlark scripts are loaded from.
+	ScriptsDirName = "scripts"
+	// DatabaseFileName is the database instances are stored in with the sqlite storage backend.
+	DatabaseFileName = "instances.db"
+	// StorageLockFileName is the file processes lock while they change the state file.
+	StorageLockFileName = "storage.lock"
+)
+
+// Storage backends instances can be stored in.
+const (
+	StorageBackendJSON   = "json"
+	StorageBackendSQLite = "sqlite"
 )
 
 // InstanceStorage handles instance-related operations
@@ -23,6 +44,27 @@ type InstanceStorage interface {
 	DeleteAllInstances() error
 }
 
+// GroupStorage handles the instance groups. Instance storage that doesn't implement it has no
+// groups.
+type GroupStorage interface {
+	// SaveGroups saves the raw group data
+	SaveGroups(groupsJSON json.RawMessage) error
+	// GetGroups returns the raw group data
+	GetGroups() json.RawMessage
+}
+
+// SharedStorage is instance storage that several claude-squad processes, like the TUI in two
+// terminals or the TUI and the CLI, can use at once.
+type SharedStorage interface {
+	// UpdateInstances replaces the stored instance data with what update returns for the data
+	// stored now, holding off the other processes in between
+	UpdateInstances(update func(stored json.RawMessage) (json.RawMessage, error)) error
+	// ReloadInstances returns the instance data stored now, with the changes of other processes
+	ReloadInstances() (json.RawMessage, error)
+	// WatchPath returns the file that changes when the stored instances do
+	WatchPath() string
+}
+
 // AppState handles application-level state
 type AppState interface {
 	// GetHelpScreensSeen returns the bitmask of seen help screens
@@ -43,6 +85,8 @@ type State struct {
 	HelpScreensSeen uint32 `json:"help_screens_seen"`
 	// Instances stores the serialized instance data as raw JSON
 	InstancesData json.RawMessage `json:"instances"`
+	// GroupsData stores the serialized instance groups as raw JSON
+	GroupsData json.RawMessage `json:"groups,omitempty"`
 }
 
 // DefaultState returns the default state
@@ -103,15 +147,74 @@ func SaveState(state *State) error {
 		return fmt.Errorf("failed to marshal state: %w", err)
 	}
 
-	return os.WriteFile(statePath, data, 0644)
+	// Replace the file atomically, so other processes never read it half written
+	tmp := statePath + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return fmt.Errorf("failed to write state: %w", err)
+	}
+	if err := os.Rename(tmp, statePath); err != nil {
+		return fmt.Errorf("failed to replace state: %w", err)
+	}
+	return nil
+}
+
+// readState reads the state file as other processes left it, or returns nil if there is none.
+func readState() (*State, error) {
+	configDir, err := GetConfigDir()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get config directory: %w", err)
+	}
+	data, err := os.ReadFile(filepath.Join(configDir, StateFileName))
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to read state: %w", err)
+	}
+	var state State
+	if err := json.Unmarshal(data, &state); err != nil {
+		return nil, fmt.Errorf("failed to parse state: %w", err)
+	}
+	return &state, nil
+}
+
+// update applies change to the state as it is stored now and saves it, holding off other
+// processes in between, so the changes they made to the rest of the state are kept.
+func (s *State) update(change func() error) error {
+	configDir, err := GetConfigDir()
+	if err != nil {
+		return fmt.Errorf("failed to get config directory: %w", err)
+	}
+	lock, err := LockFile(filepath.Join(configDir, StorageLockFileName))
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := lock.Unlock(); err != nil {
+			log.WarningLog.Printf("failed to unlock state: %v", err)
+		}
+	}()
+
+	stored, err := readState()
+	if err != nil {
+		// A corrupt state file is replaced with this process's state
+		log.WarningLog.Printf("failed to reload state, overwriting it: %v", err)
+	} else if stored != nil {
+		*s = *stored
+	}
+	if err := change(); err != nil {
+		return err
+	}
+	return SaveState(s)
 }
 
 // InstanceStorage interface implementation
 
 // SaveInstances saves the raw instance data
 func (s *State) SaveInstances(instancesJSON json.RawMessage) error {
-	s.InstancesData = instancesJSON
-	return SaveState(s)
+	return s.update(func() error {
+		s.InstancesData = instancesJSON
+		return nil
+	})
 }
 
 // GetInstances returns the raw instance data
@@ -121,8 +224,60 @@ func (s *State) GetInstances() json.RawMessage {
 
 // DeleteAllInstances removes all stored instances
 func (s *State) DeleteAllInstances() error {
-	s.InstancesData = json.RawMessage("[]")
-	return SaveState(s)
+	return s.update(func() error {
+		s.InstancesData = json.RawMessage("[]")
+		return nil
+	})
+}
+
+// SharedStorage interface implementation
+
+// UpdateInstances replaces the stored instance data with what update returns for it
+func (s *State) UpdateInstances(update func(stored json.RawMessage) (json.RawMessage, error)) error {
+	return s.update(func() error {
+		updated, err := update(s.InstancesData)
+		if err != nil {
+			return err
+		}
+		s.InstancesData = updated
+		return nil
+	})
+}
+
+// ReloadInstances returns the instance data in the state file now
+func (s *State) ReloadInstances() (json.RawMessage, error) {
+	stored, err := readState()
+	if err != nil {
+		return nil, err
+	}
+	if stored == nil {
+		return s.InstancesData, nil
+	}
+	return stored.InstancesData, nil
+}
+
+// WatchPath returns the path of the state file
+func (s *State) WatchPath() string {
+	configDir, err := GetConfigDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(configDir, StateFileName)
+}
+
+// GroupStorage interface implementation
+
+// SaveGroups saves the raw group data
+func (s *State) SaveGroups(groupsJSON json.RawMessage) error {
+	return s.update(func() error {
+		s.GroupsData = groupsJSON
+		return nil
+	})
+}
+
+// GetGroups returns the raw group data
+func (s *State) GetGroups() json.RawMessage {
+	return s.GroupsData
 }
 
 // AppState interface implementation
@@ -134,6 +289,8 @@ func (s *State) GetHelpScreensSeen() uint32 {
 
 // SetHelpScreensSeen updates the bitmask of seen help screens
 func (s *State) SetHelpScreensSeen(seen uint32) error {
-	s.HelpScreensSeen = seen
-	return SaveState(s)
+	return s.update(func() error {
+		s.HelpScreensSeen = seen
+		return nil
+	})
 }
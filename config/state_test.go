@@ -0,0 +1,65 @@
+package config
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStateSharedBetweenProcesses(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	// Two processes loading the same state
+	first := LoadState()
+	second := LoadState()
+
+	require.NoError(t, first.SaveInstances(json.RawMessage(`[{"title":"a"}]`)))
+	require.NoError(t, second.SaveGroups(json.RawMessage(`[{"name":"g"}]`)))
+	assert.JSONEq(t, `[{"title":"a"}]`, string(second.GetInstances()),
+		"saving groups keeps the instances the other process saved")
+
+	stored, err := first.ReloadInstances()
+	require.NoError(t, err)
+	assert.JSONEq(t, `[{"title":"a"}]`, string(stored))
+
+	require.NoError(t, first.UpdateInstances(func(stored json.RawMessage) (json.RawMessage, error) {
+		assert.JSONEq(t, `[{"title":"a"}]`, string(stored))
+		return json.RawMessage(`[{"title":"a"},{"title":"b"}]`), nil
+	}))
+	reloaded := LoadState()
+	assert.JSONEq(t, `[{"title":"a"},{"title":"b"}]`, string(reloaded.GetInstances()))
+	assert.JSONEq(t, `[{"name":"g"}]`, string(reloaded.GetGroups()))
+	configDir, err := GetConfigDir()
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(configDir, StateFileName), first.WatchPath())
+}
+
+func TestLockFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "test.lock")
+	lock, err := LockFile(path)
+	require.NoError(t, err)
+
+	locked := make(chan *FileLock)
+	go func() {
+		second, err := LockFile(path)
+		assert.NoError(t, err)
+		locked <- second
+	}()
+
+	select {
+	case <-locked:
+		t.Fatal("the lock was taken twice")
+	case <-time.After(100 * time.Millisecond):
+	}
+	require.NoError(t, lock.Unlock())
+	select {
+	case second := <-locked:
+		require.NoError(t, second.Unlock())
+	case <-time.After(5 * time.Second):
+		t.Fatal("the lock wasn't released")
+	}
+}
@@ -0,0 +1,61 @@
+package config
+
+import "time"
+
+// Features the summarizer writes for, the keys of SummarizerConfig.Overrides
+const (
+	SummarizeCommitMessage = "commit_message"
+	SummarizePRDescription = "pr_description"
+	SummarizeDiff          = "diff_summary"
+	SummarizeTests         = "suggest_tests"
+)
+
+// SummarizerOff as an override's command keeps the feature's built-in behavior.
+const SummarizerOff = "off"
+
+// defaultSummarizerTimeout bounds a run of the summarizer without a configured timeout
+const defaultSummarizerTimeout = 2 * time.Minute
+
+// SummarizerConfig is an external command, such as "claude -p" or "ollama run llama3", that
+// writes the summaries and generated text of features instead of their built-in behavior or the
+// instance's agent. It runs through the shell in the worktree, reads a prompt followed by its
+// input on stdin and prints the result.
+type SummarizerConfig struct {
+	Command string `json:"command"`
+	// TimeoutSeconds is how long a run may take before it is killed. Zero uses 120 seconds.
+	TimeoutSeconds int `json:"timeout_seconds,omitempty"`
+	// Overrides replace the command or timeout for single features, keyed by feature.
+	Overrides map[string]SummarizerOverride `json:"overrides,omitempty"`
+}
+
+// SummarizerOverride replaces the summarizer's settings for one feature. Empty fields keep them.
+type SummarizerOverride struct {
+	// Command replaces the summarizer's command; SummarizerOff disables it for the feature.
+	Command        string `json:"command,omitempty"`
+	TimeoutSeconds int    `json:"timeout_seconds,omitempty"`
+}
+
+// SummarizerFor returns the summarizer command for the feature and how long it may run. The
+// command is empty if the feature has none, in which case it keeps its built-in behavior.
+func (c *Config) SummarizerFor(feature string) (string, time.Duration) {
+	if c.Summarizer == nil {
+		return "", 0
+	}
+	command, seconds := c.Summarizer.Command, c.Summarizer.TimeoutSeconds
+	if override, ok := c.Summarizer.Overrides[feature]; ok {
+		if override.Command != "" {
+			command = override.Command
+		}
+		if override.TimeoutSeconds > 0 {
+			seconds = override.TimeoutSeconds
+		}
+	}
+	if command == SummarizerOff {
+		return "", 0
+	}
+	timeout := defaultSummarizerTimeout
+	if seconds > 0 {
+		timeout = time.Duration(seconds) * time.Second
+	}
+	return command, timeout
+}
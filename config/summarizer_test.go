@@ -0,0 +1,38 @@
+package config
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSummarizerFor(t *testing.T) {
+	cfg := DefaultConfig()
+	command, _ := cfg.SummarizerFor(SummarizeCommitMessage)
+	assert.Empty(t, command, "no summarizer is configured by default")
+
+	cfg.Summarizer = &SummarizerConfig{
+		Command: "ollama run llama3",
+		Overrides: map[string]SummarizerOverride{
+			SummarizePRDescription: {Command: "claude -p", TimeoutSeconds: 300},
+			SummarizeDiff:          {TimeoutSeconds: 30},
+			SummarizeTests:         {Command: SummarizerOff},
+		},
+	}
+
+	command, timeout := cfg.SummarizerFor(SummarizeCommitMessage)
+	assert.Equal(t, "ollama run llama3", command)
+	assert.Equal(t, defaultSummarizerTimeout, timeout)
+
+	command, timeout = cfg.SummarizerFor(SummarizePRDescription)
+	assert.Equal(t, "claude -p", command)
+	assert.Equal(t, 5*time.Minute, timeout)
+
+	command, timeout = cfg.SummarizerFor(SummarizeDiff)
+	assert.Equal(t, "ollama run llama3", command)
+	assert.Equal(t, 30*time.Second, timeout)
+
+	command, _ = cfg.SummarizerFor(SummarizeTests)
+	assert.Empty(t, command, "an override can turn the summarizer off for a feature")
+}
@@ -0,0 +1,19 @@
+package config
+
+// InstanceTemplate is a named, reusable configuration for new instances.
+type InstanceTemplate struct {
+	Name string `json:"name"`
+	// Program overrides the default program, e.g. "aider --model ollama_chat/gemma3:1b".
+	Program string `json:"program,omitempty"`
+	// BaseBranch is the branch the instance's branch is created from. Defaults to the remote
+	// default branch.
+	BaseBranch string `json:"base_branch,omitempty"`
+	// BranchPrefix overrides the configured branch prefix.
+	BranchPrefix string `json:"branch_prefix,omitempty"`
+	// Prompt is sent to the agent once the instance has started.
+	Prompt string `json:"prompt,omitempty"`
+	// AutoYes automatically accepts the agent's prompts.
+	AutoYes bool `json:"auto_yes,omitempty"`
+	// DefaultTab is the tab the instances open on, overriding the configured default tab.
+	DefaultTab string `json:"default_tab,omitempty"`
+}
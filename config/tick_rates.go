@@ -0,0 +1,75 @@
+package config
+
+import "time"
+
+// TickRates are how often, in milliseconds, instances are checked for new output, status and
+// diff changes. Instances nobody watches are checked less often, to save CPU when most of them
+// are idle. Unset or non-positive rates use the defaults.
+type TickRates struct {
+	// SelectedMs is the rate for the selected instance.
+	SelectedMs int `json:"selected_ms,omitempty"`
+	// RunningMs is the rate for other instances whose agent is working.
+	RunningMs int `json:"running_ms,omitempty"`
+	// IdleMs is the rate for other instances whose agent waits for input.
+	IdleMs int `json:"idle_ms,omitempty"`
+	// PreviewMs is how often the selected instance's preview is redrawn while its agent works.
+	PreviewMs int `json:"preview_ms,omitempty"`
+	// PreviewIdleMs is how often it's redrawn while its agent waits for input.
+	PreviewIdleMs int `json:"preview_idle_ms,omitempty"`
+}
+
+// DefaultTickRates check the selected instance twice a second and idle background ones every
+// few seconds.
+var DefaultTickRates = TickRates{SelectedMs: 500, RunningMs: 1000, IdleMs: 3000, PreviewMs: 100, PreviewIdleMs: 500}
+
+// GetTickRates returns the configured tick rates, with the defaults for those not set.
+func (c *Config) GetTickRates() TickRates {
+	rates := DefaultTickRates
+	if c.TickRates == nil {
+		return rates
+	}
+	for _, rate := range []struct{ configured, effective *int }{
+		{&c.TickRates.SelectedMs, &rates.SelectedMs},
+		{&c.TickRates.RunningMs, &rates.RunningMs},
+		{&c.TickRates.IdleMs, &rates.IdleMs},
+		{&c.TickRates.PreviewMs, &rates.PreviewMs},
+		{&c.TickRates.PreviewIdleMs, &rates.PreviewIdleMs},
+	} {
+		if *rate.configured > 0 {
+			*rate.effective = *rate.configured
+		}
+	}
+	return rates
+}
+
+// ms returns a rate in milliseconds as a duration.
+func ms(rate int) time.Duration {
+	return time.Duration(rate) * time.Millisecond
+}
+
+// MetadataInterval returns how long to wait between checks of an instance, by whether it's
+// selected and whether its agent is working.
+func (r TickRates) MetadataInterval(selected, running bool) time.Duration {
+	switch {
+	case selected:
+		return ms(r.SelectedMs)
+	case running:
+		return ms(r.RunningMs)
+	default:
+		return ms(r.IdleMs)
+	}
+}
+
+// MetadataTick returns how often the instances are looked at: often enough for the fastest rate.
+func (r TickRates) MetadataTick() time.Duration {
+	return ms(min(r.SelectedMs, r.RunningMs, r.IdleMs))
+}
+
+// PreviewInterval returns how long to wait between redraws of the selected instance's preview, by
+// whether its agent is working.
+func (r TickRates) PreviewInterval(running bool) time.Duration {
+	if running {
+		return ms(r.PreviewMs)
+	}
+	return ms(r.PreviewIdleMs)
+}
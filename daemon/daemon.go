@@ -4,6 +4,7 @@ import (
 	"claude-squad/config"
 	"claude-squad/log"
 	"claude-squad/session"
+	"claude-squad/share"
 	"fmt"
 	"os"
 	"os/exec"
@@ -19,7 +20,7 @@ import (
 func RunDaemon(cfg *config.Config) error {
 	log.InfoLog.Printf("starting daemon")
 	state := config.LoadState()
-	storage, err := session.NewStorage(state)
+	storage, err := session.OpenStorage(cfg.StorageBackend, state)
 	if err != nil {
 		return fmt.Errorf("failed to initialize storage: %w", err)
 	}
@@ -33,6 +34,15 @@ func RunDaemon(cfg *config.Config) error {
 		instance.AutoYes = true
 	}
 
+	// Keep shared diff pages reachable while the TUI is closed
+	if cfg.ShareServerEnabled() {
+		go func() {
+			if err := share.Serve(cfg.ShareAddr); err != nil {
+				log.WarningLog.Printf("share server stopped: %v", err)
+			}
+		}()
+	}
+
 	pollInterval := time.Duration(cfg.DaemonPollInterval) * time.Millisecond
 
 	// If we get an error for a session, it's likely that we'll keep getting the error. Log every 30 seconds.
@@ -0,0 +1,343 @@
+package main
+
+import (
+	"claude-squad/app"
+	"claude-squad/config"
+	"claude-squad/log"
+	"claude-squad/session"
+	"claude-squad/session/git"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// maxInstanceNameLength matches the title limit of the TUI.
+const maxInstanceNameLength = 32
+
+var (
+	instanceNameFlag       string
+	instanceProgramFlag    string
+	instancePromptFlag     string
+	instanceBaseBranchFlag string
+	instanceKeepBranchFlag bool
+	listJSONFlag           bool
+	historyProgramFlag     string
+	historyDaysFlag        int
+	historyLimitFlag       int
+	historyJSONFlag        bool
+)
+
+// The instance commands manage sessions without the TUI, for scripts and shell aliases. They
+// read and write the same state as the TUI, which overwrites it with its own list when it saves,
+// so don't use them on a repo while the TUI is open.
+var (
+	createCmd = &cobra.Command{
+		Use:   "create",
+		Short: "Create an instance in the current repository without opening the UI",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			log.Initialize(false)
+			defer log.Close()
+
+			if err := validateInstanceName(instanceNameFlag); err != nil {
+				return err
+			}
+			currentDir, err := filepath.Abs(".")
+			if err != nil {
+				return fmt.Errorf("failed to get current directory: %w", err)
+			}
+			if !git.IsGitRepo(currentDir) {
+				return fmt.Errorf("error: claude-squad must be run from within a git repository")
+			}
+
+			manager, err := newInstanceManager()
+			if err != nil {
+				return err
+			}
+			instance, err := manager.Create(cmd.Context(), session.InstanceOptions{
+				Title:      instanceNameFlag,
+				Path:       currentDir,
+				Program:    instanceProgramFlag,
+				BaseBranch: instanceBaseBranchFlag,
+			})
+			if err != nil {
+				return err
+			}
+			if instancePromptFlag != "" {
+				if err := instance.SendStartupPrompt(instancePromptFlag); err != nil {
+					return fmt.Errorf("failed to send prompt: %w", err)
+				}
+			}
+			fmt.Printf("Created instance %s on branch %s\n", instance.Title, instance.Branch)
+			return nil
+		},
+	}
+
+	listCmd = &cobra.Command{
+		Use:   "list",
+		Short: "List stored instances",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			log.Initialize(false)
+			defer log.Close()
+
+			manager, err := newInstanceManager()
+			if err != nil {
+				return err
+			}
+			instances, err := manager.List(cmd.Context())
+			if err != nil {
+				return err
+			}
+			if listJSONFlag {
+				return printInstancesJSON(os.Stdout, instances)
+			}
+			if len(instances) == 0 {
+				fmt.Println("No instances")
+				return nil
+			}
+			w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+			fmt.Fprintf(w, "NAME\tSTATUS\tBRANCH\tPROGRAM\tCREATED\n")
+			for _, instance := range instances {
+				fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", instance.Title, statusName(instance.Status),
+					instance.Branch, instance.Program, instance.CreatedAt.Format(time.DateTime))
+			}
+			return w.Flush()
+		},
+	}
+
+	historyCmd = &cobra.Command{
+		Use:   "history [search]",
+		Short: "List killed instances, searching their names and branches (needs the sqlite storage backend)",
+		Args:  cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			log.Initialize(false)
+			defer log.Close()
+
+			storage, err := session.OpenStorage(config.LoadConfig().StorageBackend, config.LoadState())
+			if err != nil {
+				return fmt.Errorf("failed to initialize storage: %w", err)
+			}
+			defer storage.Close()
+
+			query := session.HistoryQuery{Program: historyProgramFlag, Limit: historyLimitFlag}
+			if len(args) > 0 {
+				query.Search = args[0]
+			}
+			if historyDaysFlag > 0 {
+				query.Since = time.Now().AddDate(0, 0, -historyDaysFlag)
+			}
+			entries, err := storage.History(query)
+			if err != nil {
+				return err
+			}
+			if historyJSONFlag {
+				encoder := json.NewEncoder(os.Stdout)
+				encoder.SetIndent("", "  ")
+				if entries == nil {
+					entries = []session.HistoryEntry{}
+				}
+				return encoder.Encode(entries)
+			}
+			if len(entries) == 0 {
+				fmt.Println("No killed instances")
+				return nil
+			}
+			w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+			fmt.Fprintf(w, "NAME\tBRANCH\tPROGRAM\tCREATED\tKILLED\tUSAGE\n")
+			for _, entry := range entries {
+				fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n", entry.Title, entry.Branch, entry.Program,
+					entry.CreatedAt.Format(time.DateTime), entry.EndedAt.Format(time.DateTime), entry.Data.Metrics.Usage)
+			}
+			return w.Flush()
+		},
+	}
+
+	killCmd = &cobra.Command{
+		Use:   "kill",
+		Short: "Kill an instance, removing its worktree and tmux session",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			log.Initialize(false)
+			defer log.Close()
+
+			manager, err := newInstanceManager()
+			if err != nil {
+				return err
+			}
+			if err := manager.Kill(cmd.Context(), instanceNameFlag, instanceKeepBranchFlag); err != nil {
+				return err
+			}
+			fmt.Printf("Killed instance %s\n", instanceNameFlag)
+			return nil
+		},
+	}
+
+	pauseCmd = &cobra.Command{
+		Use:   "pause",
+		Short: "Pause an instance, committing its changes and removing its worktree",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return updateInstance(cmd, "Paused", (*session.Manager).Pause)
+		},
+	}
+
+	resumeCmd = &cobra.Command{
+		Use:   "resume",
+		Short: "Resume a paused instance",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return updateInstance(cmd, "Resumed", (*session.Manager).Resume)
+		},
+	}
+
+	sendCmd = &cobra.Command{
+		Use:   "send [prompt]",
+		Short: "Send a prompt to an instance; reads the prompt from stdin if no argument is given",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			log.Initialize(false)
+			defer log.Close()
+
+			prompt := strings.Join(args, " ")
+			if len(args) == 0 {
+				data, err := io.ReadAll(os.Stdin)
+				if err != nil {
+					return fmt.Errorf("failed to read prompt from stdin: %w", err)
+				}
+				prompt = strings.TrimSpace(string(data))
+			}
+
+			manager, err := newInstanceManager()
+			if err != nil {
+				return err
+			}
+			if err := manager.Send(cmd.Context(), instanceNameFlag, prompt); err != nil {
+				return err
+			}
+			fmt.Printf("Sent prompt to %s\n", instanceNameFlag)
+			return nil
+		},
+	}
+)
+
+func init() {
+	for _, cmd := range []*cobra.Command{createCmd, killCmd, pauseCmd, resumeCmd, sendCmd} {
+		cmd.Flags().StringVarP(&instanceNameFlag, "name", "n", "", "Name of the instance")
+		if err := cmd.MarkFlagRequired("name"); err != nil {
+			panic(err)
+		}
+	}
+	createCmd.Flags().StringVarP(&instanceProgramFlag, "program", "p", "",
+		"Program to run in the instance (default from config)")
+	createCmd.Flags().StringVar(&instancePromptFlag, "prompt", "", "Prompt to send once the instance starts")
+	createCmd.Flags().StringVar(&instanceBaseBranchFlag, "base", "",
+		"Branch, tag or commit to create the instance's branch from (default: the remote default branch)")
+	killCmd.Flags().BoolVar(&instanceKeepBranchFlag, "keep-branch", false,
+		"Commit uncommitted changes and keep the branch instead of deleting it")
+	listCmd.Flags().BoolVar(&listJSONFlag, "json", false, "Print instances as JSON")
+	historyCmd.Flags().StringVarP(&historyProgramFlag, "program", "p", "", "Only list instances running this program")
+	historyCmd.Flags().IntVar(&historyDaysFlag, "days", 0, "Only list instances killed in the last number of days (0 for all)")
+	historyCmd.Flags().IntVar(&historyLimitFlag, "limit", 20, "Most instances to list (0 for all)")
+	historyCmd.Flags().BoolVar(&historyJSONFlag, "json", false, "Print instances as JSON")
+}
+
+// newInstanceManager returns a manager for the instances saved by the TUI, using the TUI's
+// config, default program, instance limit and policy.
+func newInstanceManager() (*session.Manager, error) {
+	policy, err := config.LoadPolicy()
+	if err != nil {
+		return nil, err
+	}
+	cfg := config.LoadConfig()
+	storage, err := session.OpenStorage(cfg.StorageBackend, config.LoadState())
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize storage: %w", err)
+	}
+	return session.NewManager(storage, session.ManagerOptions{
+		DefaultProgram: cfg.DefaultProgram,
+		MaxInstances:   app.GlobalInstanceLimit,
+		Policy:         policy,
+		Config:         cfg,
+	}), nil
+}
+
+func validateInstanceName(name string) error {
+	if strings.TrimSpace(name) == "" {
+		return fmt.Errorf("instance name cannot be empty")
+	}
+	if len(name) > maxInstanceNameLength {
+		return fmt.Errorf("instance name cannot be longer than %d characters", maxInstanceNameLength)
+	}
+	return nil
+}
+
+// updateInstance applies a manager state change to the instance named by --name.
+func updateInstance(cmd *cobra.Command, verb string,
+	change func(*session.Manager, context.Context, string) (*session.Instance, error)) error {
+	log.Initialize(false)
+	defer log.Close()
+
+	manager, err := newInstanceManager()
+	if err != nil {
+		return err
+	}
+	instance, err := change(manager, cmd.Context(), instanceNameFlag)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("%s instance %s\n", verb, instance.Title)
+	return nil
+}
+
+func statusName(status session.Status) string {
+	switch status {
+	case session.Running:
+		return "running"
+	case session.Ready:
+		return "ready"
+	case session.Loading:
+		return "loading"
+	case session.Paused:
+		return "paused"
+	case session.Creating:
+		return "creating"
+	case session.Deleting:
+		return "deleting"
+	}
+	return "unknown"
+}
+
+// listedInstance is the JSON form of an instance printed by list --json.
+type listedInstance struct {
+	Name      string    `json:"name"`
+	Status    string    `json:"status"`
+	Branch    string    `json:"branch"`
+	Program   string    `json:"program"`
+	Path      string    `json:"path"`
+	Worktree  string    `json:"worktree,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func printInstancesJSON(w io.Writer, instances []*session.Instance) error {
+	listed := make([]listedInstance, 0, len(instances))
+	for _, instance := range instances {
+		entry := listedInstance{
+			Name:      instance.Title,
+			Status:    statusName(instance.Status),
+			Branch:    instance.Branch,
+			Program:   instance.Program,
+			Path:      instance.Path,
+			CreatedAt: instance.CreatedAt,
+		}
+		if worktree, err := instance.GetGitWorktree(); err == nil && worktree != nil && !instance.Paused() {
+			entry.Worktree = worktree.GetWorktreePath()
+		}
+		listed = append(listed, entry)
+	}
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(listed)
+}
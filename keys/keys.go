@@ -15,6 +15,7 @@ const (
 	KeyDown
 	KeyEnter
 	KeyNew
+	KeyNewFromChanges // Key for creating a new instance with the main checkout's uncommitted changes
 	KeyKill
 	KeyQuit
 	KeyReview
@@ -29,15 +30,18 @@ const (
 
 	KeyCheckout
 	KeyResume
-	KeyPrompt         // New key for entering a prompt
-	KeyHelp           // Key for showing help screen
-	KeyExistingBranch // Key for creating instance from existing branch
-	KeyErrorLog       // Key for showing error log
-	KeyOpenIDE        // Key for opening IDE
-	KeyRebase         // Key for rebasing with main branch
-	KeyBookmark       // Key for creating a bookmark commit
-	KeyTest           // Key for running Jest tests
-	KeyExternalDiff   // Key for opening in external diff tool
+	KeyPrompt            // New key for entering a prompt
+	KeyTemplate          // Key for creating a new session from a config template
+	KeyHelp              // Key for showing help screen
+	KeyExistingBranch    // Key for creating instance from existing branch
+	KeyErrorLog          // Key for showing error log
+	KeyOpenIDE           // Key for opening IDE
+	KeyRebase            // Key for rebasing with main branch
+	KeyRebaseLog         // Key for showing the log of the last update with main
+	KeyInteractiveRebase // Key for reordering, squashing, rewording and dropping the branch's commits
+	KeyBookmark          // Key for creating a bookmark commit
+	KeyTest              // Key for running tests
+	KeyExternalDiff      // Key for opening in external diff tool
 
 	// Jest keybindings
 	KeyJestNextFailure     // Key for navigating to next Jest failure
@@ -54,8 +58,18 @@ const (
 	KeyPageDown
 	KeyAltUp
 	KeyAltDown
+	KeyHalfPageUp
+	KeyHalfPageDown
+	KeyJumpUp
+	KeyJumpDown
 	KeyDiffAll
 	KeyDiffLastCommit
+	KeyDiffStaging     // Key for the diff tab's view of staged and unstaged hunks
+	KeyDiffBase        // Key for diffing against a branch, tag or commit picked from a list
+	KeyToggleStageHunk // Key for staging or unstaging the hunk at the top of the staging view
+	KeyToggleStageFile // Key for staging or unstaging the whole file at the top of the staging view
+	KeyDiscardHunk     // Key for discarding the unstaged hunk at the top of the staging view
+	KeyDiscardFile     // Key for discarding the unstaged changes to the file at the top of the staging view
 	KeyLeft
 	KeyRight
 	KeyScrollLock
@@ -66,59 +80,149 @@ const (
 	KeyGitStatusBookmark // Key for showing git status overlay in bookmark mode
 	KeyCheckUpdate       // Key for checking for updates
 	KeyGitReset          // Key for git reset --hard origin/branch
+	KeyFixBranch         // Key for renaming back or retargeting an auto-renamed branch
+	KeyMoveToGroup       // Key for moving an instance to another group
+	KeyToggleGroup       // Key for collapsing or expanding a group
+	KeyGroupActions      // Key for acting on every instance of a group
+	KeyPatch             // Key for exporting or applying the instance diff as a patch
+	KeyClipboard         // Key for copying snippets again from the instance's clipboard history
+	KeyRepos             // Key for picking the repository or remote host new instances are created in
+	KeyMoveWorktree      // Key for moving the instance's worktree to another path
+	KeyCheckpoint        // Key for saving an agent context summary as a checkpoint note
+	KeyDetails           // Key for showing instance details and checkpoint notes
+	KeyCIChecks          // Key for listing the CI checks of the selected instance's branch
+	KeyWorkOnPR          // Key for composing a prompt to resume work on the PR
+	KeySearch            // Key for searching across all instances
+	KeyLastFinished      // Key for jumping to the most recently finished instance
+	KeyFilter            // Key for narrowing the list to the instances matching what is typed
+	KeyGitStats          // Key for showing git command timing statistics
+	KeyImportBranches    // Key for importing existing branches as paused instances
+	KeyAdoptWorktree     // Key for adopting a worktree created outside claude-squad as an instance
+	KeyShare             // Key for publishing the instance diff as a shareable HTML page
+	KeyBackups           // Key for listing and restoring storage backups
+	KeyBackupBranches    // Key for browsing, restoring and deleting an instance's backup branches
+	KeyServices          // Key for starting and stopping the selected instance's dev servers
+	KeyOpenBrowser       // Key for opening the selected instance's dev server in the browser
+	KeyBroadcast         // Key for sending a prompt to every instance matching a filter
+	KeyArchives          // Key for browsing and restoring archived sessions
+	KeyReauth            // Key for re-authenticating expired gh/git credentials
+	KeySuggestTests      // Key for asking for tests that cover the current diff
+	KeyPromptQueue       // Key for viewing and editing the selected instance's prompt queue
+	KeyInstanceSettings  // Key for editing the selected instance's settings while it runs
+	KeyCoordinator       // Key for making the selected instance the coordinator sent digests of the others
+	KeyAutoYesPolicy     // Key for editing which prompts auto-yes confirms
+	KeyOperations        // Key for listing the background operations in flight
+	KeyExportMetrics     // Key for exporting the metrics of all instances as CSV and JSON
+	KeyToggleWrap        // Key for switching the preview and diff between soft wrap and horizontal panning
+	KeyToggleSideBySide  // Key for switching the diff between unified and side-by-side columns
+	KeyPanLeft           // Key for panning long lines left
+	KeyPanRight          // Key for panning long lines right
+	KeyUndo              // Key for undoing the last kill, reset or rewrite of a branch
 )
 
 // GlobalKeyStringsMap is a global, immutable map string to keybinding.
 var GlobalKeyStringsMap = map[string]KeyName{
-	"up":         KeyUp,
-	"k":          KeyUp,
-	"down":       KeyDown,
-	"j":          KeyDown,
-	"shift+up":   KeyShiftUp,
-	"shift+down": KeyShiftDown,
-	"home":       KeyHome,
-	"end":        KeyEnd,
-	"ctrl+a":     KeyHome,
-	"ctrl+e":     KeyEnd,
-	"ctrl+home":  KeyHome,
-	"ctrl+end":   KeyEnd,
-	"pgup":       KeyPageUp,
-	"pgdown":     KeyPageDown,
-	"alt+up":     KeyAltUp,
-	"alt+down":   KeyAltDown,
-	"a":          KeyDiffAll,
-	"d":          KeyDiffLastCommit,
-	"left":       KeyLeft,
-	"right":      KeyRight,
-	"s":          KeyScrollLock,
-	"N":          KeyPrompt,
-	"enter":      KeyEnter,
-	"o":          KeyEnter,
-	"n":          KeyNew,
-	"e":          KeyExistingBranch,
-	"D":          KeyKill,
-	"q":          KeyQuit,
-	"tab":        KeyTab,
-	"shift+tab":  KeyShiftTab,
-	"c":          KeyCheckout,
-	"r":          KeyResume,
-	"p":          KeySubmit,
-	"?":          KeyHelp,
-	"l":          KeyErrorLog,
-	"w":          KeyOpenIDE,
-	"i":          KeyOpenInIDE,
-	"b":          KeyRebase,
-	"B":          KeyBookmark,
-	"R":          KeyPRReview,
-	"ctrl+r":     KeyPRResolveConversations,
-	"ctrl+h":     KeyHistory,
-	"K":          KeyEditKeybindings,
-	"t":          KeyTest,
-	"x":          KeyExternalDiff,
-	"g":          KeyGitStatus,
-	"G":          KeyGitStatusBookmark,
-	"U":          KeyCheckUpdate,
-	"h":          KeyGitReset,
+	"up":          KeyUp,
+	"k":           KeyUp,
+	"down":        KeyDown,
+	"j":           KeyDown,
+	"shift+up":    KeyShiftUp,
+	"shift+down":  KeyShiftDown,
+	"home":        KeyHome,
+	"end":         KeyEnd,
+	"ctrl+a":      KeyHome,
+	"ctrl+e":      KeyEnd,
+	"ctrl+home":   KeyHome,
+	"ctrl+end":    KeyEnd,
+	"pgup":        KeyPageUp,
+	"pgdown":      KeyPageDown,
+	"alt+up":      KeyAltUp,
+	"alt+down":    KeyAltDown,
+	"ctrl+u":      KeyHalfPageUp,
+	"ctrl+d":      KeyHalfPageDown,
+	"ctrl+up":     KeyJumpUp,
+	"ctrl+down":   KeyJumpDown,
+	"a":           KeyDiffAll,
+	"d":           KeyDiffLastCommit,
+	"u":           KeyDiffStaging,
+	"alt+v":       KeyDiffBase,
+	" ":           KeyToggleStageHunk,
+	"F":           KeyToggleStageFile,
+	"X":           KeyDiscardHunk,
+	"ctrl+x":      KeyDiscardFile,
+	"left":        KeyLeft,
+	"right":       KeyRight,
+	"s":           KeyScrollLock,
+	"N":           KeyPrompt,
+	"T":           KeyTemplate,
+	"enter":       KeyEnter,
+	"o":           KeyEnter,
+	"n":           KeyNew,
+	"alt+n":       KeyNewFromChanges,
+	"e":           KeyExistingBranch,
+	"D":           KeyKill,
+	"q":           KeyQuit,
+	"tab":         KeyTab,
+	"shift+tab":   KeyShiftTab,
+	"c":           KeyCheckout,
+	"r":           KeyResume,
+	"p":           KeySubmit,
+	"?":           KeyHelp,
+	"l":           KeyErrorLog,
+	"w":           KeyOpenIDE,
+	"i":           KeyOpenInIDE,
+	"b":           KeyRebase,
+	"alt+l":       KeyRebaseLog,
+	"alt+i":       KeyInteractiveRebase,
+	"B":           KeyBookmark,
+	"R":           KeyPRReview,
+	"ctrl+r":      KeyPRResolveConversations,
+	"ctrl+h":      KeyHistory,
+	"K":           KeyEditKeybindings,
+	"t":           KeyTest,
+	"x":           KeyExternalDiff,
+	"g":           KeyGitStatus,
+	"G":           KeyGitStatusBookmark,
+	"U":           KeyCheckUpdate,
+	"h":           KeyGitReset,
+	"f":           KeyFixBranch,
+	"m":           KeyMoveToGroup,
+	"z":           KeyToggleGroup,
+	"O":           KeyGroupActions,
+	"P":           KeyPatch,
+	"y":           KeyClipboard,
+	"L":           KeyRepos,
+	"ctrl+w":      KeyMoveWorktree,
+	"C":           KeyCheckpoint,
+	"v":           KeyDetails,
+	"alt+c":       KeyCIChecks,
+	"alt+w":       KeyWorkOnPR,
+	"ctrl+f":      KeySearch,
+	"alt+f":       KeyLastFinished,
+	"/":           KeyFilter,
+	"ctrl+g":      KeyGitStats,
+	"I":           KeyImportBranches,
+	"alt+a":       KeyAdoptWorktree,
+	"S":           KeyShare,
+	"alt+r":       KeyBackups,
+	"alt+b":       KeyBackupBranches,
+	"alt+s":       KeyServices,
+	"alt+o":       KeyOpenBrowser,
+	"alt+p":       KeyBroadcast,
+	"Z":           KeyArchives,
+	"A":           KeyReauth,
+	"ctrl+t":      KeySuggestTests,
+	"Q":           KeyPromptQueue,
+	"E":           KeyInstanceSettings,
+	"alt+d":       KeyCoordinator,
+	"Y":           KeyAutoYesPolicy,
+	"J":           KeyOperations,
+	"M":           KeyExportMetrics,
+	"W":           KeyToggleWrap,
+	"V":           KeyToggleSideBySide,
+	"shift+left":  KeyPanLeft,
+	"shift+right": KeyPanRight,
+	"ctrl+z":      KeyUndo,
 
 	// Jest navigation - these are only active in Jest tab
 	// "n" and "p" are already taken globally, so we'll handle them contextually
@@ -167,6 +271,22 @@ var GlobalkeyBindings = map[KeyName]key.Binding{
 		key.WithKeys("alt+down"),
 		key.WithHelp("alt+↓", "next file"),
 	),
+	KeyHalfPageUp: key.NewBinding(
+		key.WithKeys("ctrl+u"),
+		key.WithHelp("ctrl+u", "half page up"),
+	),
+	KeyHalfPageDown: key.NewBinding(
+		key.WithKeys("ctrl+d"),
+		key.WithHelp("ctrl+d", "half page down"),
+	),
+	KeyJumpUp: key.NewBinding(
+		key.WithKeys("ctrl+up"),
+		key.WithHelp("ctrl+↑", "jump up"),
+	),
+	KeyJumpDown: key.NewBinding(
+		key.WithKeys("ctrl+down"),
+		key.WithHelp("ctrl+↓", "jump down"),
+	),
 	KeyDiffAll: key.NewBinding(
 		key.WithKeys("a"),
 		key.WithHelp("a", "all changes"),
@@ -175,6 +295,30 @@ var GlobalkeyBindings = map[KeyName]key.Binding{
 		key.WithKeys("d"),
 		key.WithHelp("d", "last commit diff"),
 	),
+	KeyDiffStaging: key.NewBinding(
+		key.WithKeys("u"),
+		key.WithHelp("u", "staging view"),
+	),
+	KeyDiffBase: key.NewBinding(
+		key.WithKeys("alt+v"),
+		key.WithHelp("alt+v", "diff base"),
+	),
+	KeyToggleStageHunk: key.NewBinding(
+		key.WithKeys(" "),
+		key.WithHelp("space", "stage/unstage hunk"),
+	),
+	KeyToggleStageFile: key.NewBinding(
+		key.WithKeys("F"),
+		key.WithHelp("F", "stage/unstage file"),
+	),
+	KeyDiscardHunk: key.NewBinding(
+		key.WithKeys("X"),
+		key.WithHelp("X", "discard hunk"),
+	),
+	KeyDiscardFile: key.NewBinding(
+		key.WithKeys("ctrl+x"),
+		key.WithHelp("ctrl+x", "discard file"),
+	),
 	KeyLeft: key.NewBinding(
 		key.WithKeys("left"),
 		key.WithHelp("←", "prev commit"),
@@ -199,6 +343,10 @@ var GlobalkeyBindings = map[KeyName]key.Binding{
 		key.WithKeys("n"),
 		key.WithHelp("n", "new"),
 	),
+	KeyNewFromChanges: key.NewBinding(
+		key.WithKeys("alt+n"),
+		key.WithHelp("alt+n", "new with changes"),
+	),
 	KeyExistingBranch: key.NewBinding(
 		key.WithKeys("e"),
 		key.WithHelp("e", "existing branch"),
@@ -223,6 +371,10 @@ var GlobalkeyBindings = map[KeyName]key.Binding{
 		key.WithKeys("N"),
 		key.WithHelp("N", "new with prompt"),
 	),
+	KeyTemplate: key.NewBinding(
+		key.WithKeys("T"),
+		key.WithHelp("T", "template"),
+	),
 	KeyCheckout: key.NewBinding(
 		key.WithKeys("c"),
 		key.WithHelp("c", "checkout"),
@@ -251,6 +403,14 @@ var GlobalkeyBindings = map[KeyName]key.Binding{
 		key.WithKeys("b"),
 		key.WithHelp("b", "rebase"),
 	),
+	KeyRebaseLog: key.NewBinding(
+		key.WithKeys("alt+l"),
+		key.WithHelp("alt+l", "rebase log"),
+	),
+	KeyInteractiveRebase: key.NewBinding(
+		key.WithKeys("alt+i"),
+		key.WithHelp("alt+i", "rewrite commits"),
+	),
 	KeyPRReview: key.NewBinding(
 		key.WithKeys("R"),
 		key.WithHelp("R", "review PR comments"),
@@ -295,6 +455,158 @@ var GlobalkeyBindings = map[KeyName]key.Binding{
 		key.WithKeys("h"),
 		key.WithHelp("h", "git reset --hard"),
 	),
+	KeyFixBranch: key.NewBinding(
+		key.WithKeys("f"),
+		key.WithHelp("f", "fix renamed branch"),
+	),
+	KeyMoveToGroup: key.NewBinding(
+		key.WithKeys("m"),
+		key.WithHelp("m", "move to group"),
+	),
+	KeyToggleGroup: key.NewBinding(
+		key.WithKeys("z"),
+		key.WithHelp("z", "fold group"),
+	),
+	KeyGroupActions: key.NewBinding(
+		key.WithKeys("O"),
+		key.WithHelp("O", "group actions"),
+	),
+	KeyPatch: key.NewBinding(
+		key.WithKeys("P"),
+		key.WithHelp("P", "export/apply patch"),
+	),
+	KeyClipboard: key.NewBinding(
+		key.WithKeys("y"),
+		key.WithHelp("y", "clipboard"),
+	),
+	KeyRepos: key.NewBinding(
+		key.WithKeys("L"),
+		key.WithHelp("L", "repos"),
+	),
+	KeyMoveWorktree: key.NewBinding(
+		key.WithKeys("ctrl+w"),
+		key.WithHelp("ctrl+w", "move worktree"),
+	),
+	KeyCheckpoint: key.NewBinding(
+		key.WithKeys("C"),
+		key.WithHelp("C", "checkpoint"),
+	),
+	KeyDetails: key.NewBinding(
+		key.WithKeys("v"),
+		key.WithHelp("v", "details"),
+	),
+	KeyCIChecks: key.NewBinding(
+		key.WithKeys("alt+c"),
+		key.WithHelp("alt+c", "CI checks"),
+	),
+	KeyWorkOnPR: key.NewBinding(
+		key.WithKeys("alt+w"),
+		key.WithHelp("alt+w", "work on PR"),
+	),
+	KeySearch: key.NewBinding(
+		key.WithKeys("ctrl+f"),
+		key.WithHelp("ctrl+f", "search sessions"),
+	),
+	KeyLastFinished: key.NewBinding(
+		key.WithKeys("alt+f"),
+		key.WithHelp("alt+f", "last finished"),
+	),
+	KeyFilter: key.NewBinding(
+		key.WithKeys("/"),
+		key.WithHelp("/", "filter list"),
+	),
+	KeyGitStats: key.NewBinding(
+		key.WithKeys("ctrl+g"),
+		key.WithHelp("ctrl+g", "git stats"),
+	),
+	KeyImportBranches: key.NewBinding(
+		key.WithKeys("I"),
+		key.WithHelp("I", "import branches"),
+	),
+	KeyAdoptWorktree: key.NewBinding(
+		key.WithKeys("alt+a"),
+		key.WithHelp("alt+a", "adopt worktree"),
+	),
+	KeyShare: key.NewBinding(
+		key.WithKeys("S"),
+		key.WithHelp("S", "share diff"),
+	),
+	KeyBackups: key.NewBinding(
+		key.WithKeys("alt+r"),
+		key.WithHelp("alt+r", "restore backup"),
+	),
+	KeyBackupBranches: key.NewBinding(
+		key.WithKeys("alt+b"),
+		key.WithHelp("alt+b", "backup branches"),
+	),
+	KeyServices: key.NewBinding(
+		key.WithKeys("alt+s"),
+		key.WithHelp("alt+s", "services"),
+	),
+	KeyOpenBrowser: key.NewBinding(
+		key.WithKeys("alt+o"),
+		key.WithHelp("alt+o", "open in browser"),
+	),
+	KeyBroadcast: key.NewBinding(
+		key.WithKeys("alt+p"),
+		key.WithHelp("alt+p", "broadcast prompt"),
+	),
+	KeyArchives: key.NewBinding(
+		key.WithKeys("Z"),
+		key.WithHelp("Z", "archives"),
+	),
+	KeyReauth: key.NewBinding(
+		key.WithKeys("A"),
+		key.WithHelp("A", "re-auth"),
+	),
+	KeySuggestTests: key.NewBinding(
+		key.WithKeys("ctrl+t"),
+		key.WithHelp("ctrl+t", "suggest tests"),
+	),
+	KeyPromptQueue: key.NewBinding(
+		key.WithKeys("Q"),
+		key.WithHelp("Q", "prompt queue"),
+	),
+	KeyInstanceSettings: key.NewBinding(
+		key.WithKeys("E"),
+		key.WithHelp("E", "settings"),
+	),
+	KeyCoordinator: key.NewBinding(
+		key.WithKeys("alt+d"),
+		key.WithHelp("alt+d", "coordinator"),
+	),
+	KeyAutoYesPolicy: key.NewBinding(
+		key.WithKeys("Y"),
+		key.WithHelp("Y", "auto-yes policy"),
+	),
+	KeyOperations: key.NewBinding(
+		key.WithKeys("J"),
+		key.WithHelp("J", "operations"),
+	),
+	KeyExportMetrics: key.NewBinding(
+		key.WithKeys("M"),
+		key.WithHelp("M", "export metrics"),
+	),
+	KeyToggleWrap: key.NewBinding(
+		key.WithKeys("W"),
+		key.WithHelp("W", "toggle wrap"),
+	),
+	KeyToggleSideBySide: key.NewBinding(
+		key.WithKeys("V"),
+		key.WithHelp("V", "side-by-side diff"),
+	),
+	KeyPanLeft: key.NewBinding(
+		key.WithKeys("shift+left"),
+		key.WithHelp("shift+left", "pan left"),
+	),
+	KeyPanRight: key.NewBinding(
+		key.WithKeys("shift+right"),
+		key.WithHelp("shift+right", "pan right"),
+	),
+	KeyUndo: key.NewBinding(
+		key.WithKeys("ctrl+z"),
+		key.WithHelp("ctrl+z", "undo"),
+	),
 
 	// -- Special keybindings --
 
@@ -363,16 +675,24 @@ func DefaultKeyBindings() *KeyBindingsConfig {
 			{Command: "end", Keys: []string{"end", "ctrl+e", "ctrl+end"}, Help: "end/ctrl+e"},
 			{Command: "page_up", Keys: []string{"pgup"}, Help: "pgup"},
 			{Command: "page_down", Keys: []string{"pgdown"}, Help: "pgdn"},
+			{Command: "half_page_up", Keys: []string{"ctrl+u"}, Help: "ctrl+u"},
+			{Command: "half_page_down", Keys: []string{"ctrl+d"}, Help: "ctrl+d"},
+			{Command: "jump_up", Keys: []string{"ctrl+up"}, Help: "ctrl+↑"},
+			{Command: "jump_down", Keys: []string{"ctrl+down"}, Help: "ctrl+↓"},
 
 			// Instance management
 			{Command: "new", Keys: []string{"n"}, Help: "n"},
+			{Command: "new_from_changes", Keys: []string{"alt+n"}, Help: "alt+n"},
 			{Command: "new_with_prompt", Keys: []string{"N"}, Help: "N"},
+			{Command: "new_from_template", Keys: []string{"T"}, Help: "T"},
 			{Command: "existing_branch", Keys: []string{"e"}, Help: "e"},
 			{Command: "kill", Keys: []string{"D"}, Help: "D"},
 			{Command: "checkout", Keys: []string{"c"}, Help: "c"},
 			{Command: "resume", Keys: []string{"r"}, Help: "r"},
 			{Command: "push", Keys: []string{"p"}, Help: "p"},
 			{Command: "rebase", Keys: []string{"b"}, Help: "b"},
+			{Command: "rebase_log", Keys: []string{"alt+l"}, Help: "alt+l"},
+			{Command: "interactive_rebase", Keys: []string{"alt+i"}, Help: "alt+i"},
 
 			// Diff view
 			{Command: "scroll_up", Keys: []string{"shift+up"}, Help: "shift+↑"},
@@ -381,6 +701,12 @@ func DefaultKeyBindings() *KeyBindingsConfig {
 			{Command: "next_file", Keys: []string{"alt+down"}, Help: "alt+↓"},
 			{Command: "diff_all", Keys: []string{"a"}, Help: "a"},
 			{Command: "diff_last_commit", Keys: []string{"d"}, Help: "d"},
+			{Command: "diff_staging", Keys: []string{"u"}, Help: "u"},
+			{Command: "diff_base", Keys: []string{"alt+v"}, Help: "alt+v"},
+			{Command: "toggle_stage_hunk", Keys: []string{" "}, Help: "space"},
+			{Command: "toggle_stage_file", Keys: []string{"F"}, Help: "F"},
+			{Command: "discard_hunk", Keys: []string{"X"}, Help: "X"},
+			{Command: "discard_file", Keys: []string{"ctrl+x"}, Help: "ctrl+x"},
 			{Command: "prev_commit", Keys: []string{"left"}, Help: "←"},
 			{Command: "next_commit", Keys: []string{"right"}, Help: "→"},
 			{Command: "scroll_lock", Keys: []string{"s"}, Help: "s"},
@@ -399,6 +725,44 @@ func DefaultKeyBindings() *KeyBindingsConfig {
 			{Command: "git_status_bookmark", Keys: []string{"G"}, Help: "G"},
 			{Command: "check_update", Keys: []string{"U"}, Help: "U"},
 			{Command: "git_reset", Keys: []string{"h"}, Help: "h"},
+			{Command: "fix_branch", Keys: []string{"f"}, Help: "f"},
+			{Command: "move_to_group", Keys: []string{"m"}, Help: "m"},
+			{Command: "toggle_group", Keys: []string{"z"}, Help: "z"},
+			{Command: "group_actions", Keys: []string{"O"}, Help: "O"},
+			{Command: "patch", Keys: []string{"P"}, Help: "P"},
+			{Command: "clipboard", Keys: []string{"y"}, Help: "y"},
+			{Command: "repos", Keys: []string{"L"}, Help: "L"},
+			{Command: "move_worktree", Keys: []string{"ctrl+w"}, Help: "ctrl+w"},
+			{Command: "checkpoint", Keys: []string{"C"}, Help: "C"},
+			{Command: "details", Keys: []string{"v"}, Help: "v"},
+			{Command: "ci_checks", Keys: []string{"alt+c"}, Help: "alt+c"},
+			{Command: "work_on_pr", Keys: []string{"alt+w"}, Help: "alt+w"},
+			{Command: "search", Keys: []string{"ctrl+f"}, Help: "ctrl+f"},
+			{Command: "last_finished", Keys: []string{"alt+f"}, Help: "alt+f"},
+			{Command: "filter", Keys: []string{"/"}, Help: "/"},
+			{Command: "git_stats", Keys: []string{"ctrl+g"}, Help: "ctrl+g"},
+			{Command: "import_branches", Keys: []string{"I"}, Help: "I"},
+			{Command: "adopt_worktree", Keys: []string{"alt+a"}, Help: "alt+a"},
+			{Command: "share", Keys: []string{"S"}, Help: "S"},
+			{Command: "backups", Keys: []string{"alt+r"}, Help: "alt+r"},
+			{Command: "backup_branches", Keys: []string{"alt+b"}, Help: "alt+b"},
+			{Command: "services", Keys: []string{"alt+s"}, Help: "alt+s"},
+			{Command: "open_browser", Keys: []string{"alt+o"}, Help: "alt+o"},
+			{Command: "broadcast", Keys: []string{"alt+p"}, Help: "alt+p"},
+			{Command: "archives", Keys: []string{"Z"}, Help: "Z"},
+			{Command: "reauth", Keys: []string{"A"}, Help: "A"},
+			{Command: "suggest_tests", Keys: []string{"ctrl+t"}, Help: "ctrl+t"},
+			{Command: "prompt_queue", Keys: []string{"Q"}, Help: "Q"},
+			{Command: "instance_settings", Keys: []string{"E"}, Help: "E"},
+			{Command: "coordinator", Keys: []string{"alt+d"}, Help: "alt+d"},
+			{Command: "auto_yes_policy", Keys: []string{"Y"}, Help: "Y"},
+			{Command: "operations", Keys: []string{"J"}, Help: "J"},
+			{Command: "export_metrics", Keys: []string{"M"}, Help: "M"},
+			{Command: "toggle_wrap", Keys: []string{"W"}, Help: "W"},
+			{Command: "toggle_side_by_side", Keys: []string{"V"}, Help: "V"},
+			{Command: "pan_left", Keys: []string{"shift+left"}, Help: "shift+left"},
+			{Command: "pan_right", Keys: []string{"shift+right"}, Help: "shift+right"},
+			{Command: "undo", Keys: []string{"ctrl+z"}, Help: "ctrl+z"},
 		},
 	}
 }
@@ -529,7 +893,9 @@ func getCommandToKeyNameMap() map[string]KeyName {
 		"down":                KeyDown,
 		"enter":               KeyEnter,
 		"new":                 KeyNew,
+		"new_from_changes":    KeyNewFromChanges,
 		"new_with_prompt":     KeyPrompt,
+		"new_from_template":   KeyTemplate,
 		"existing_branch":     KeyExistingBranch,
 		"kill":                KeyKill,
 		"quit":                KeyQuit,
@@ -540,6 +906,8 @@ func getCommandToKeyNameMap() map[string]KeyName {
 		"error_log":           KeyErrorLog,
 		"open_ide":            KeyOpenIDE,
 		"rebase":              KeyRebase,
+		"rebase_log":          KeyRebaseLog,
+		"interactive_rebase":  KeyInteractiveRebase,
 		"tab":                 KeyTab,
 		"shift_tab":           KeyShiftTab,
 		"scroll_up":           KeyShiftUp,
@@ -548,10 +916,20 @@ func getCommandToKeyNameMap() map[string]KeyName {
 		"end":                 KeyEnd,
 		"page_up":             KeyPageUp,
 		"page_down":           KeyPageDown,
+		"half_page_up":        KeyHalfPageUp,
+		"half_page_down":      KeyHalfPageDown,
+		"jump_up":             KeyJumpUp,
+		"jump_down":           KeyJumpDown,
 		"prev_file":           KeyAltUp,
 		"next_file":           KeyAltDown,
 		"diff_all":            KeyDiffAll,
 		"diff_last_commit":    KeyDiffLastCommit,
+		"diff_staging":        KeyDiffStaging,
+		"diff_base":           KeyDiffBase,
+		"toggle_stage_hunk":   KeyToggleStageHunk,
+		"toggle_stage_file":   KeyToggleStageFile,
+		"discard_hunk":        KeyDiscardHunk,
+		"discard_file":        KeyDiscardFile,
 		"prev_commit":         KeyLeft,
 		"next_commit":         KeyRight,
 		"scroll_lock":         KeyScrollLock,
@@ -562,6 +940,44 @@ func getCommandToKeyNameMap() map[string]KeyName {
 		"git_status_bookmark": KeyGitStatusBookmark,
 		"check_update":        KeyCheckUpdate,
 		"git_reset":           KeyGitReset,
+		"fix_branch":          KeyFixBranch,
+		"move_to_group":       KeyMoveToGroup,
+		"toggle_group":        KeyToggleGroup,
+		"group_actions":       KeyGroupActions,
+		"patch":               KeyPatch,
+		"clipboard":           KeyClipboard,
+		"repos":               KeyRepos,
+		"move_worktree":       KeyMoveWorktree,
+		"checkpoint":          KeyCheckpoint,
+		"details":             KeyDetails,
+		"ci_checks":           KeyCIChecks,
+		"work_on_pr":          KeyWorkOnPR,
+		"search":              KeySearch,
+		"last_finished":       KeyLastFinished,
+		"filter":              KeyFilter,
+		"git_stats":           KeyGitStats,
+		"import_branches":     KeyImportBranches,
+		"adopt_worktree":      KeyAdoptWorktree,
+		"share":               KeyShare,
+		"backups":             KeyBackups,
+		"backup_branches":     KeyBackupBranches,
+		"services":            KeyServices,
+		"open_browser":        KeyOpenBrowser,
+		"broadcast":           KeyBroadcast,
+		"archives":            KeyArchives,
+		"reauth":              KeyReauth,
+		"suggest_tests":       KeySuggestTests,
+		"prompt_queue":        KeyPromptQueue,
+		"instance_settings":   KeyInstanceSettings,
+		"coordinator":         KeyCoordinator,
+		"auto_yes_policy":     KeyAutoYesPolicy,
+		"operations":          KeyOperations,
+		"export_metrics":      KeyExportMetrics,
+		"toggle_wrap":         KeyToggleWrap,
+		"toggle_side_by_side": KeyToggleSideBySide,
+		"pan_left":            KeyPanLeft,
+		"pan_right":           KeyPanRight,
+		"undo":                KeyUndo,
 	}
 }
 
@@ -589,7 +1005,9 @@ func getHelpText(command string) string {
 		"down":                "down",
 		"enter":               "open",
 		"new":                 "new",
+		"new_from_changes":    "new with changes",
 		"new_with_prompt":     "new with prompt",
+		"new_from_template":   "template",
 		"existing_branch":     "existing branch",
 		"kill":                "kill",
 		"quit":                "quit",
@@ -600,6 +1018,8 @@ func getHelpText(command string) string {
 		"error_log":           "error log",
 		"open_ide":            "open IDE",
 		"rebase":              "rebase",
+		"rebase_log":          "rebase log",
+		"interactive_rebase":  "rewrite commits",
 		"tab":                 "switch tab",
 		"shift_tab":           "switch tab (reverse)",
 		"scroll_up":           "scroll",
@@ -608,10 +1028,20 @@ func getHelpText(command string) string {
 		"end":                 "scroll to bottom",
 		"page_up":             "page up",
 		"page_down":           "page down",
+		"half_page_up":        "half page up",
+		"half_page_down":      "half page down",
+		"jump_up":             "jump up",
+		"jump_down":           "jump down",
 		"prev_file":           "prev file",
 		"next_file":           "next file",
 		"diff_all":            "all changes",
 		"diff_last_commit":    "last commit diff",
+		"diff_staging":        "staging view",
+		"diff_base":           "diff base",
+		"toggle_stage_hunk":   "stage/unstage hunk",
+		"toggle_stage_file":   "stage/unstage file",
+		"discard_hunk":        "discard hunk",
+		"discard_file":        "discard file",
 		"prev_commit":         "prev commit",
 		"next_commit":         "next commit",
 		"scroll_lock":         "toggle scroll lock",
@@ -621,6 +1051,44 @@ func getHelpText(command string) string {
 		"git_status_bookmark": "git status bookmarks",
 		"check_update":        "check for updates",
 		"git_reset":           "git reset --hard",
+		"fix_branch":          "fix renamed branch",
+		"move_to_group":       "move to group",
+		"toggle_group":        "fold group",
+		"group_actions":       "group actions",
+		"patch":               "export/apply patch",
+		"clipboard":           "clipboard",
+		"repos":               "repos",
+		"move_worktree":       "move worktree",
+		"checkpoint":          "checkpoint",
+		"details":             "details",
+		"ci_checks":           "CI checks",
+		"work_on_pr":          "work on PR",
+		"search":              "search sessions",
+		"last_finished":       "last finished",
+		"filter":              "filter list",
+		"git_stats":           "git stats",
+		"import_branches":     "import branches",
+		"adopt_worktree":      "adopt worktree",
+		"share":               "share diff",
+		"backups":             "restore backup",
+		"backup_branches":     "backup branches",
+		"services":            "services",
+		"open_browser":        "open in browser",
+		"broadcast":           "broadcast prompt",
+		"archives":            "browse archived sessions",
+		"reauth":              "re-auth",
+		"suggest_tests":       "suggest tests",
+		"prompt_queue":        "prompt queue",
+		"instance_settings":   "settings",
+		"coordinator":         "coordinator",
+		"auto_yes_policy":     "auto-yes policy",
+		"operations":          "operations",
+		"export_metrics":      "export metrics",
+		"toggle_wrap":         "toggle wrap",
+		"toggle_side_by_side": "side-by-side diff",
+		"pan_left":            "pan left",
+		"pan_right":           "pan right",
+		"undo":                "undo",
 	}
 
 	if text, ok := helpTexts[command]; ok {
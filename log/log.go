@@ -2,22 +2,30 @@ package log
 
 import (
 	"fmt"
+	"io"
 	"log"
 	"os"
 	"path/filepath"
 	"time"
 )
 
+// The loggers discard their output until Initialize or SetOutput is called, so the packages
+// using them also work when embedded in other programs.
 var (
-	WarningLog *log.Logger
-	InfoLog    *log.Logger
-	ErrorLog   *log.Logger
+	WarningLog = log.New(io.Discard, "WARNING:", log.Ldate|log.Ltime|log.Lshortfile)
+	InfoLog    = log.New(io.Discard, "INFO:", log.Ldate|log.Ltime|log.Lshortfile)
+	ErrorLog   = log.New(io.Discard, "ERROR:", log.Ldate|log.Ltime|log.Lshortfile)
 )
 
 var logFileName = filepath.Join(os.TempDir(), "claudesquad.log")
 
 var globalLogFile *os.File
 
+// FilePath returns the path of the log file.
+func FilePath() string {
+	return logFileName
+}
+
 // Initialize should be called once at the beginning of the program to set up logging.
 // defer Close() after calling this function. It sets the go log output to the file in
 // the os temp directory.
@@ -42,10 +50,21 @@ func Initialize(daemon bool) {
 	globalLogFile = f
 }
 
+// SetOutput sends the logs to w instead of the log file, for programs embedding the session
+// packages. Passing io.Discard silences them.
+func SetOutput(w io.Writer) {
+	InfoLog.SetOutput(w)
+	WarningLog.SetOutput(w)
+	ErrorLog.SetOutput(w)
+}
+
 func Close() {
+	if globalLogFile == nil {
+		return
+	}
 	_ = globalLogFile.Close()
-	// TODO: maybe only print if verbose flag is set?
-	fmt.Println("wrote logs to " + logFileName)
+	// Stderr keeps the output of commands printing JSON or CSV parseable
+	fmt.Fprintln(os.Stderr, "wrote logs to "+logFileName)
 }
 
 // Every is used to log at most once every timeout duration.
@@ -1,10 +1,12 @@
 package main
 
 import (
+	"bufio"
 	"claude-squad/app"
 	cmd2 "claude-squad/cmd"
 	"claude-squad/config"
 	"claude-squad/daemon"
+	"claude-squad/diagnostics"
 	"claude-squad/log"
 	"claude-squad/session"
 	"claude-squad/session/git"
@@ -12,11 +14,31 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
+	"os"
 	"path/filepath"
+	"strings"
+	"text/tabwriter"
+	"time"
 
 	"github.com/spf13/cobra"
 )
 
+var metricsFormatFlag string
+
+var (
+	dailyFormatFlag string
+	dailyDaysFlag   int
+)
+
+var resetYesFlag bool
+
+var (
+	diagnosticsNoRedactFlag bool
+	diagnosticsOutputFlag   string
+	diagnosticsLogLinesFlag int
+)
+
 var (
 	version     = "1.0.10"
 	programFlag string
@@ -54,9 +76,16 @@ var (
 			if programFlag != "" {
 				program = programFlag
 			}
-			// AutoYes flag overrides config
+			// AutoYes flag overrides config, but not a policy turning it off
 			autoYes := cfg.AutoYes
 			if autoYesFlag {
+				policy, err := config.LoadPolicy()
+				if err != nil {
+					return err
+				}
+				if err := policy.CheckAutoYes(true); err != nil {
+					return fmt.Errorf("-y/--autoyes is not allowed: %w", err)
+				}
 				autoYes = true
 			}
 			if autoYes {
@@ -79,14 +108,22 @@ var (
 		Use:   "reset",
 		Short: "Reset all stored instances",
 		RunE: func(cmd *cobra.Command, args []string) error {
+			if !resetYesFlag {
+				fmt.Println("This deletes all instances, their tmux sessions, worktrees and branches.")
+				if !confirmTyped(os.Stdin, os.Stdout, "reset") {
+					return fmt.Errorf("reset cancelled")
+				}
+			}
+
 			log.Initialize(false)
 			defer log.Close()
 
 			state := config.LoadState()
-			storage, err := session.NewStorage(state)
+			storage, err := session.OpenStorage(config.LoadConfig().StorageBackend, state)
 			if err != nil {
 				return fmt.Errorf("failed to initialize storage: %w", err)
 			}
+			defer storage.Close()
 			if err := storage.DeleteAllInstances(); err != nil {
 				return fmt.Errorf("failed to reset storage: %w", err)
 			}
@@ -126,10 +163,173 @@ var (
 
 			fmt.Printf("Config: %s\n%s\n", filepath.Join(configDir, config.ConfigFileName), configJson)
 
+			policy, err := config.LoadPolicy()
+			if err != nil {
+				return err
+			}
+			if !policy.Active() {
+				fmt.Printf("Policy: none (%s)\n", config.PolicyPath())
+				return nil
+			}
+			policyJson, _ := json.MarshalIndent(policy, "", "  ")
+			fmt.Printf("Policy: %s\n%s\n", policy.Source, policyJson)
+			if overridden := cfg.PolicyOverrides(); len(overridden) > 0 {
+				fmt.Printf("Settings enforced by the policy: %s\n", strings.Join(overridden, ", "))
+			}
+
 			return nil
 		},
 	}
 
+	diagnosticsCmd = &cobra.Command{
+		Use:   "diagnostics",
+		Short: "Write a zip of config, state, recent logs, versions and errors for bug reports",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			log.Initialize(false)
+			defer log.Close()
+
+			path, err := diagnostics.CreateBundle(diagnostics.Options{
+				Redact:    !diagnosticsNoRedactFlag,
+				OutputDir: diagnosticsOutputFlag,
+				LogLines:  diagnosticsLogLinesFlag,
+			})
+			if err != nil {
+				return err
+			}
+			fmt.Printf("Diagnostics bundle written to %s\n", path)
+			return nil
+		},
+	}
+
+	backupsCmd = &cobra.Command{
+		Use:   "backups",
+		Short: "List storage backups that can be restored",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			backups, err := config.ListBackups()
+			if err != nil {
+				return err
+			}
+			if len(backups) == 0 {
+				fmt.Println("No storage backups yet")
+				return nil
+			}
+			for _, backup := range backups {
+				fmt.Printf("%s  %-12s %s\n", backup.Name, backup.Reason, strings.Join(backup.Files, ", "))
+			}
+			return nil
+		},
+	}
+
+	restoreCmd = &cobra.Command{
+		Use:   "restore <backup>",
+		Short: "Restore config and state from a storage backup",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			log.Initialize(false)
+			defer log.Close()
+
+			// The daemon saves state periodically and would overwrite the restored files
+			if err := daemon.StopDaemon(); err != nil {
+				return err
+			}
+
+			cfg := config.LoadConfig()
+			if err := config.RestoreBackup(args[0], cfg.BackupCount); err != nil {
+				return err
+			}
+			fmt.Printf("Restored storage backup %s\n", args[0])
+			return nil
+		},
+	}
+
+	reportCmd = &cobra.Command{
+		Use:   "report",
+		Short: "Summarize the outcomes recorded when killing instances",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			records, err := session.LoadOutcomes()
+			if err != nil {
+				return err
+			}
+			if len(records) == 0 {
+				fmt.Println("No outcomes recorded yet. Rate a run when killing its instance to start collecting them.")
+				return nil
+			}
+
+			w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+			printOutcomeGroups(w, "Overall", records, func(session.OutcomeRecord) string { return "all" })
+			printOutcomeGroups(w, "By effort", records, func(r session.OutcomeRecord) string { return r.Effort })
+			printOutcomeGroups(w, "By program", records, func(r session.OutcomeRecord) string { return r.Program })
+			printOutcomeGroups(w, "By repository", records, func(r session.OutcomeRecord) string { return r.Repo })
+			printOutcomeGroups(w, "By branch disposition", records, func(r session.OutcomeRecord) string { return r.Disposition })
+			return w.Flush()
+		},
+	}
+
+	dailyReportCmd = &cobra.Command{
+		Use:   "daily",
+		Short: "Summarize instances created and killed, prompts, commits and tokens per day",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			log.Initialize(false)
+			defer log.Close()
+
+			write := session.WriteDailyReportTable
+			switch dailyFormatFlag {
+			case "table":
+			case "json":
+				write = session.WriteDailyReportJSON
+			default:
+				return fmt.Errorf("unknown format %q, expected table or json", dailyFormatFlag)
+			}
+			storage, err := session.OpenStorage(config.LoadConfig().StorageBackend, config.LoadState())
+			if err != nil {
+				return fmt.Errorf("failed to initialize storage: %w", err)
+			}
+			defer storage.Close()
+			activities, err := storage.Activity()
+			if err != nil {
+				return err
+			}
+			var since time.Time
+			if dailyDaysFlag > 0 {
+				now := time.Now()
+				since = time.Date(now.Year(), now.Month(), now.Day()-dailyDaysFlag+1, 0, 0, 0, 0, now.Location())
+			}
+			return write(os.Stdout, session.DailyReport(activities, since))
+		},
+	}
+
+	metricsCmd = &cobra.Command{
+		Use:   "metrics",
+		Short: "Print the metrics of all stored instances as CSV or JSON",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			log.Initialize(false)
+			defer log.Close()
+
+			write := session.WriteMetricsCSV
+			switch metricsFormatFlag {
+			case "csv":
+			case "json":
+				write = session.WriteMetricsJSON
+			default:
+				return fmt.Errorf("unknown format %q, expected csv or json", metricsFormatFlag)
+			}
+			manager, err := newInstanceManager()
+			if err != nil {
+				return err
+			}
+			instances, err := manager.List(cmd.Context())
+			if err != nil {
+				return err
+			}
+			now := time.Now()
+			snapshots := make([]session.MetricsSnapshot, len(instances))
+			for i, instance := range instances {
+				snapshots[i] = session.NewMetricsSnapshot(instance, now)
+			}
+			return write(os.Stdout, snapshots)
+		},
+	}
+
 	versionCmd = &cobra.Command{
 		Use:   "version",
 		Short: "Print the version number of claude-squad",
@@ -154,9 +354,62 @@ func init() {
 		panic(err)
 	}
 
+	resetCmd.Flags().BoolVarP(&resetYesFlag, "yes", "y", false, "Reset without typing reset to confirm")
+
+	metricsCmd.Flags().StringVar(&metricsFormatFlag, "format", "csv", "Output format: csv or json")
+
+	dailyReportCmd.Flags().StringVar(&dailyFormatFlag, "format", "table", "Output format: table or json")
+	dailyReportCmd.Flags().IntVar(&dailyDaysFlag, "days", 7, "Number of days to include, up to today (0 for all)")
+	reportCmd.AddCommand(dailyReportCmd)
+
+	diagnosticsCmd.Flags().BoolVar(&diagnosticsNoRedactFlag, "no-redact", false,
+		"Keep paths, emails and credentials instead of redacting them")
+	diagnosticsCmd.Flags().StringVarP(&diagnosticsOutputFlag, "output", "o", "",
+		"Directory to write the bundle to (default ~/.claude-squad/diagnostics)")
+	diagnosticsCmd.Flags().IntVar(&diagnosticsLogLinesFlag, "log-lines", diagnostics.DefaultLogLines,
+		"Number of trailing log lines to include")
+	diagnostics.Version = version
+
+	rootCmd.AddCommand(backupsCmd)
+	rootCmd.AddCommand(benchCmd)
+	rootCmd.AddCommand(createCmd)
 	rootCmd.AddCommand(debugCmd)
+	rootCmd.AddCommand(diagnosticsCmd)
+	rootCmd.AddCommand(historyCmd)
+	rootCmd.AddCommand(killCmd)
+	rootCmd.AddCommand(listCmd)
+	rootCmd.AddCommand(metricsCmd)
+	rootCmd.AddCommand(pauseCmd)
 	rootCmd.AddCommand(versionCmd)
+	rootCmd.AddCommand(reportCmd)
 	rootCmd.AddCommand(resetCmd)
+	rootCmd.AddCommand(restoreCmd)
+	rootCmd.AddCommand(resumeCmd)
+	rootCmd.AddCommand(sendCmd)
+}
+
+// confirmTyped asks to type phrase to confirm an action that can't be undone, and returns true
+// if it was.
+func confirmTyped(in io.Reader, out io.Writer, phrase string) bool {
+	fmt.Fprintf(out, "Type %s to confirm: ", phrase)
+	answer, err := bufio.NewReader(in).ReadString('\n')
+	if err != nil && answer == "" {
+		fmt.Fprintln(out)
+		return false
+	}
+	return strings.TrimSpace(answer) == phrase
+}
+
+// printOutcomeGroups writes one table section of the outcome report.
+func printOutcomeGroups(w io.Writer, title string, records []session.OutcomeRecord, key func(session.OutcomeRecord) string) {
+	fmt.Fprintf(w, "%s\n", title)
+	fmt.Fprintf(w, "  \truns\tsuccess\tpartial\tfailed\tsuccess rate\tavg duration\n")
+	for _, stats := range session.GroupOutcomes(records, key) {
+		fmt.Fprintf(w, "  %s\t%d\t%d\t%d\t%d\t%.0f%%\t%s\n", stats.Key, stats.Total,
+			stats.Results["success"], stats.Results["partial"], stats.Results["failed"],
+			stats.SuccessRate()*100, stats.AverageDuration().Round(time.Minute))
+	}
+	fmt.Fprintln(w)
 }
 
 func main() {
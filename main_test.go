@@ -0,0 +1,79 @@
+package main
+
+import (
+	"claude-squad/config"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// runCommandOutput runs the command and returns what it printed to stdout. Move the home
+// directory to a temporary one first.
+func runCommandOutput(t *testing.T, cmd *cobra.Command, args ...string) string {
+	t.Helper()
+	reader, writer, err := os.Pipe()
+	require.NoError(t, err)
+	stdout := os.Stdout
+	os.Stdout = writer
+	defer func() { os.Stdout = stdout }()
+
+	cmd.SetContext(context.Background())
+	runErr := cmd.RunE(cmd, args)
+	require.NoError(t, writer.Close())
+	output, err := io.ReadAll(reader)
+	require.NoError(t, err)
+	require.NoError(t, runErr)
+	return string(output)
+}
+
+func TestListJSON(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	listJSONFlag = true
+	t.Cleanup(func() { listJSONFlag = false })
+
+	output := runCommandOutput(t, listCmd)
+	var instances []map[string]any
+	require.NoError(t, json.Unmarshal([]byte(output), &instances), "stdout isn't only JSON:\n%s", output)
+	assert.Empty(t, instances)
+}
+
+func TestMetricsOutput(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Cleanup(func() { metricsFormatFlag = "csv" })
+
+	metricsFormatFlag = "json"
+	output := runCommandOutput(t, metricsCmd)
+	var snapshots []map[string]any
+	require.NoError(t, json.Unmarshal([]byte(output), &snapshots), "stdout isn't only JSON:\n%s", output)
+
+	metricsFormatFlag = "csv"
+	output = runCommandOutput(t, metricsCmd)
+	records, err := csv.NewReader(strings.NewReader(output)).ReadAll()
+	require.NoError(t, err, "stdout isn't only CSV:\n%s", output)
+	assert.Len(t, records, 1, "only the header is printed without instances")
+}
+
+func TestHistoryJSON(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	configDir, err := config.GetConfigDir()
+	require.NoError(t, err)
+	require.NoError(t, os.MkdirAll(configDir, 0755))
+	cfg := `{"storage_backend": "sqlite"}`
+	require.NoError(t, os.WriteFile(filepath.Join(configDir, config.ConfigFileName), []byte(cfg), 0644))
+	historyJSONFlag = true
+	t.Cleanup(func() { historyJSONFlag = false })
+
+	output := runCommandOutput(t, historyCmd)
+	var entries []map[string]any
+	require.NoError(t, json.Unmarshal([]byte(output), &entries), "stdout isn't only JSON:\n%s", output)
+	assert.Empty(t, entries)
+}
@@ -0,0 +1,105 @@
+// Package notify posts instance events to a webhook so long-running work can be followed from
+// chat tools such as Slack.
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+// EventType names what happened to an instance.
+type EventType string
+
+const (
+	// EventInstanceReady is sent when an instance's agent finishes working and waits for input.
+	EventInstanceReady EventType = "instance_ready"
+	// EventRebaseComplete is sent when an instance's branch was rebased onto main.
+	EventRebaseComplete EventType = "rebase_complete"
+	// EventTestsFailed is sent when a test run for an instance has failures.
+	EventTestsFailed EventType = "tests_failed"
+	// EventPRCommentsFetched is sent when the comments of an instance's pull request are loaded.
+	EventPRCommentsFetched EventType = "pr_comments_fetched"
+	// EventBudgetExceeded is sent when an instance's agent works past its time budget.
+	EventBudgetExceeded EventType = "budget_exceeded"
+	// EventRunFinished is sent with a summary of an agent's run once it finishes a prompt.
+	EventRunFinished EventType = "run_finished"
+	// EventApprovalRequested is sent when a confirmation is published to the approvals inbox.
+	EventApprovalRequested EventType = "approval_requested"
+)
+
+// sendTimeout bounds a single webhook request.
+const sendTimeout = 10 * time.Second
+
+// Event is the JSON body posted to the webhook. Text repeats the message so the body can be sent
+// to a Slack incoming webhook as is.
+type Event struct {
+	Type     EventType `json:"type"`
+	Instance string    `json:"instance"`
+	Branch   string    `json:"branch,omitempty"`
+	Message  string    `json:"message"`
+	Text     string    `json:"text"`
+	Time     time.Time `json:"time"`
+}
+
+// NewEvent creates an event for the instance, stamped with the current time.
+func NewEvent(eventType EventType, instance, branch, message string) Event {
+	return Event{
+		Type:     eventType,
+		Instance: instance,
+		Branch:   branch,
+		Message:  message,
+		Text:     fmt.Sprintf("[%s] %s", instance, message),
+		Time:     time.Now(),
+	}
+}
+
+// Notifier posts events to a webhook URL. A Notifier without a URL drops every event.
+type Notifier struct {
+	url    string
+	client *http.Client
+}
+
+// New creates a notifier posting to url. An empty url disables notifications.
+func New(url string) *Notifier {
+	return &Notifier{
+		url:    url,
+		client: &http.Client{Timeout: sendTimeout},
+	}
+}
+
+// Enabled returns true if events are sent anywhere.
+func (n *Notifier) Enabled() bool {
+	return n != nil && n.url != ""
+}
+
+// Send posts the event to the webhook. It does nothing if the notifier is disabled.
+func (n *Notifier) Send(ctx context.Context, event Event) error {
+	if !n.Enabled() {
+		return nil
+	}
+	body, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to encode %s event: %w", event.Type, err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, n.url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create webhook request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to post %s event: %w", event.Type, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		detail, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("webhook rejected %s event: %s: %s", event.Type, resp.Status, bytes.TrimSpace(detail))
+	}
+	return nil
+}
@@ -0,0 +1,48 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSendPostsEvent(t *testing.T) {
+	var received Event
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&received))
+	}))
+	defer server.Close()
+
+	event := NewEvent(EventInstanceReady, "fix-login", "me/fix-login", "agent is ready")
+	require.NoError(t, New(server.URL).Send(context.Background(), event))
+
+	assert.Equal(t, EventInstanceReady, received.Type)
+	assert.Equal(t, "fix-login", received.Instance)
+	assert.Equal(t, "me/fix-login", received.Branch)
+	assert.Equal(t, "[fix-login] agent is ready", received.Text)
+}
+
+func TestSendReportsRejection(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "invalid_token", http.StatusForbidden)
+	}))
+	defer server.Close()
+
+	err := New(server.URL).Send(context.Background(), NewEvent(EventTestsFailed, "x", "", "failed"))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid_token")
+}
+
+func TestDisabledNotifierSendsNothing(t *testing.T) {
+	var nilNotifier *Notifier
+	assert.False(t, nilNotifier.Enabled())
+	assert.False(t, New("").Enabled())
+	assert.NoError(t, New("").Send(context.Background(), NewEvent(EventRebaseComplete, "x", "", "done")))
+}
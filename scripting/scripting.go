@@ -0,0 +1,256 @@
+// Package scripting runs the user's Starlark scripts, which subscribe to events about instances
+// and act on them, to automate work without changing claude-squad itself.
+//
+// A script registers handlers with on(event, fn). Each handler gets the event as a struct and
+// may call send_prompt, create_instance and run_tests, which the app performs after the handler
+// returns:
+//
+//	def review(event):
+//	    if event.status == "ready" and "review" in event.tags:
+//	        send_prompt(event.title, "Review your changes and fix anything you find")
+//
+//	on("status_changed", review)
+package scripting
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"go.starlark.net/starlark"
+	"go.starlark.net/starlarkstruct"
+)
+
+// Events scripts can subscribe to.
+const (
+	// EventInstanceCreated is sent when a new instance has started.
+	EventInstanceCreated = "instance_created"
+	// EventStatusChanged is sent when an instance's agent starts working or becomes ready.
+	EventStatusChanged = "status_changed"
+	// EventPushComplete is sent when an instance's branch was pushed.
+	EventPushComplete = "push_complete"
+	// EventRebaseComplete is sent when an instance's branch was updated with main.
+	EventRebaseComplete = "rebase_complete"
+	// EventPRCommentsFetched is sent when the comments of an instance's pull request are loaded.
+	EventPRCommentsFetched = "pr_comments_fetched"
+	// EventBudgetExceeded is sent when an instance's agent works past its time budget.
+	EventBudgetExceeded = "budget_exceeded"
+	// EventRunFinished is sent with a summary of an agent's run once it finishes a prompt.
+	EventRunFinished = "run_finished"
+)
+
+// Events lists the events scripts can subscribe to.
+var Events = []string{EventInstanceCreated, EventStatusChanged, EventPushComplete, EventRebaseComplete,
+	EventPRCommentsFetched, EventBudgetExceeded, EventRunFinished}
+
+// maxSteps bounds the work of one handler call, so a script stuck in a loop can't hang the UI.
+const maxSteps = 1_000_000
+
+// actionsKey is the thread local collecting the actions a handler asks for.
+const actionsKey = "actions"
+
+// Event is what happened to an instance.
+type Event struct {
+	Type    string
+	Title   string
+	Branch  string
+	Program string
+	Tags    []string
+	// Status is the instance's status, e.g. "ready", and PreviousStatus what it was before a
+	// status change
+	Status         string
+	PreviousStatus string
+	// Message describes the event, if there is more to say
+	Message string
+}
+
+// value returns the event as the struct handlers get.
+func (e Event) value() starlark.Value {
+	tags := make([]starlark.Value, len(e.Tags))
+	for n, tag := range e.Tags {
+		tags[n] = starlark.String(tag)
+	}
+	return starlarkstruct.FromStringDict(starlarkstruct.Default, starlark.StringDict{
+		"type":            starlark.String(e.Type),
+		"title":           starlark.String(e.Title),
+		"branch":          starlark.String(e.Branch),
+		"program":         starlark.String(e.Program),
+		"tags":            starlark.NewList(tags),
+		"status":          starlark.String(e.Status),
+		"previous_status": starlark.String(e.PreviousStatus),
+		"message":         starlark.String(e.Message),
+	})
+}
+
+// ActionKind names an action scripts can ask for.
+type ActionKind string
+
+const (
+	// ActionSendPrompt sends Prompt to the instance titled Title, or queues it while its agent works.
+	ActionSendPrompt ActionKind = "send_prompt"
+	// ActionCreateInstance creates and starts an instance titled Title running Program, or the
+	// default program, and sends it Prompt.
+	ActionCreateInstance ActionKind = "create_instance"
+	// ActionRunTests runs the tests of the instance titled Title.
+	ActionRunTests ActionKind = "run_tests"
+)
+
+// Action is something a handler asked the app to do.
+type Action struct {
+	Kind    ActionKind
+	Title   string
+	Prompt  string
+	Program string
+	// Script is the file of the script that asked for it
+	Script string
+}
+
+// handler is a function a script registered for an event.
+type handler struct {
+	script string
+	fn     starlark.Callable
+}
+
+// Engine holds the handlers of the loaded scripts.
+type Engine struct {
+	handlers map[string][]handler
+	// Print receives what scripts print. Nil drops it.
+	Print func(script, msg string)
+}
+
+// Load runs every *.star file in dir, in name order, letting each register its handlers. A
+// script that fails to load is skipped and its error returned; a missing dir loads nothing.
+func Load(dir string) (*Engine, []error) {
+	engine := &Engine{handlers: make(map[string][]handler)}
+	paths, err := filepath.Glob(filepath.Join(dir, "*.star"))
+	if err != nil {
+		return engine, []error{fmt.Errorf("failed to list scripts: %w", err)}
+	}
+	sort.Strings(paths)
+
+	var errs []error
+	for _, path := range paths {
+		if err := engine.load(path); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return engine, errs
+}
+
+// load runs the script at path. Its handlers are only added if it runs without errors.
+func (e *Engine) load(path string) error {
+	src, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read script %s: %w", path, err)
+	}
+	name := filepath.Base(path)
+	registered := make(map[string][]handler)
+	on := starlark.NewBuiltin("on", func(thread *starlark.Thread, fn *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+		var event string
+		var callback starlark.Callable
+		if err := starlark.UnpackPositionalArgs(fn.Name(), args, kwargs, 2, &event, &callback); err != nil {
+			return nil, err
+		}
+		if !known(event) {
+			return nil, fmt.Errorf("%s: unknown event %q, expected one of %s", fn.Name(), event, strings.Join(Events, ", "))
+		}
+		registered[event] = append(registered[event], handler{script: name, fn: callback})
+		return starlark.None, nil
+	})
+
+	predeclared := actionBuiltins()
+	predeclared["on"] = on
+	if _, err := starlark.ExecFile(e.thread(name), path, src, predeclared); err != nil {
+		return fmt.Errorf("failed to load script %s: %w", name, err)
+	}
+	for event, handlers := range registered {
+		e.handlers[event] = append(e.handlers[event], handlers...)
+	}
+	return nil
+}
+
+// known returns true if scripts can subscribe to event.
+func known(event string) bool {
+	for _, name := range Events {
+		if name == event {
+			return true
+		}
+	}
+	return false
+}
+
+// thread returns a thread to run the script named name in.
+func (e *Engine) thread(name string) *starlark.Thread {
+	thread := &starlark.Thread{Name: name, Print: func(_ *starlark.Thread, msg string) {
+		if e.Print != nil {
+			e.Print(name, msg)
+		}
+	}}
+	thread.SetMaxExecutionSteps(maxSteps)
+	return thread
+}
+
+// actionBuiltins returns the functions handlers call to ask for actions. Calling them outside a
+// handler, while a script loads, is an error.
+func actionBuiltins() starlark.StringDict {
+	action := func(kind ActionKind, params ...string) *starlark.Builtin {
+		return starlark.NewBuiltin(string(kind), func(thread *starlark.Thread, fn *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+			actions, ok := thread.Local(actionsKey).(*[]Action)
+			if !ok {
+				return nil, fmt.Errorf("%s can only be called from an event handler", fn.Name())
+			}
+			values := make([]string, len(params))
+			pairs := make([]any, 0, 2*len(params))
+			for n, param := range params {
+				pairs = append(pairs, param, &values[n])
+			}
+			if err := starlark.UnpackArgs(fn.Name(), args, kwargs, pairs...); err != nil {
+				return nil, err
+			}
+			a := Action{Kind: kind, Script: thread.Name, Title: values[0]}
+			for n, param := range params {
+				switch strings.TrimSuffix(param, "?") {
+				case "prompt":
+					a.Prompt = values[n]
+				case "program":
+					a.Program = values[n]
+				}
+			}
+			*actions = append(*actions, a)
+			return starlark.None, nil
+		})
+	}
+	return starlark.StringDict{
+		string(ActionSendPrompt):     action(ActionSendPrompt, "title", "prompt"),
+		string(ActionCreateInstance): action(ActionCreateInstance, "title", "prompt?", "program?"),
+		string(ActionRunTests):       action(ActionRunTests, "title"),
+	}
+}
+
+// Subscribed returns true if any script handles event, so building events nobody handles can
+// be skipped.
+func (e *Engine) Subscribed(event string) bool {
+	return e != nil && len(e.handlers[event]) > 0
+}
+
+// Dispatch calls the handlers of the event and returns the actions they asked for, in order. A
+// handler that fails is reported in errs and its actions are dropped.
+func (e *Engine) Dispatch(event Event) (actions []Action, errs []error) {
+	if e == nil {
+		return nil, nil
+	}
+	value := event.value()
+	for _, h := range e.handlers[event.Type] {
+		var asked []Action
+		thread := e.thread(h.script)
+		thread.SetLocal(actionsKey, &asked)
+		if _, err := starlark.Call(thread, h.fn, starlark.Tuple{value}, nil); err != nil {
+			errs = append(errs, fmt.Errorf("script %s failed handling %s: %w", h.script, event.Type, err))
+			continue
+		}
+		actions = append(actions, asked...)
+	}
+	return actions, errs
+}
@@ -0,0 +1,92 @@
+package scripting
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeScript(t *testing.T, dir, name, src string) {
+	t.Helper()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(src), 0644))
+}
+
+func TestDispatch(t *testing.T) {
+	dir := t.TempDir()
+	writeScript(t, dir, "a_review.star", `
+def review(event):
+    if event.status == "ready" and "review" in event.tags:
+        print("reviewing", event.title)
+        send_prompt(event.title, "Review your changes")
+        run_tests(title = event.title)
+
+on("status_changed", review)
+`)
+	writeScript(t, dir, "b_followup.star", `
+def followup(event):
+    create_instance(event.title + "-docs", prompt = "Document " + event.branch)
+
+on("push_complete", followup)
+`)
+	writeScript(t, dir, "notes.txt", "not a script")
+
+	engine, errs := Load(dir)
+	require.Empty(t, errs)
+	var printed []string
+	engine.Print = func(script, msg string) { printed = append(printed, script+": "+msg) }
+
+	assert.True(t, engine.Subscribed(EventStatusChanged))
+	assert.False(t, engine.Subscribed(EventInstanceCreated))
+
+	actions, errs := engine.Dispatch(Event{Type: EventStatusChanged, Title: "api", Status: "ready", Tags: []string{"review"}})
+	require.Empty(t, errs)
+	assert.Equal(t, []Action{
+		{Kind: ActionSendPrompt, Title: "api", Prompt: "Review your changes", Script: "a_review.star"},
+		{Kind: ActionRunTests, Title: "api", Script: "a_review.star"},
+	}, actions)
+	assert.Equal(t, []string{"a_review.star: reviewing api"}, printed)
+
+	actions, errs = engine.Dispatch(Event{Type: EventStatusChanged, Title: "api", Status: "running", Tags: []string{"review"}})
+	assert.Empty(t, errs)
+	assert.Empty(t, actions)
+
+	actions, errs = engine.Dispatch(Event{Type: EventPushComplete, Title: "api", Branch: "me/api"})
+	require.Empty(t, errs)
+	assert.Equal(t, []Action{{Kind: ActionCreateInstance, Title: "api-docs", Prompt: "Document me/api", Script: "b_followup.star"}}, actions)
+}
+
+func TestLoadErrors(t *testing.T) {
+	dir := t.TempDir()
+	writeScript(t, dir, "syntax.star", "def broken(:\n")
+	writeScript(t, dir, "unknown.star", `on("lunch_time", print)`)
+	writeScript(t, dir, "eager.star", `send_prompt("api", "hi")`)
+	writeScript(t, dir, "loop.star", `
+def spin(event):
+    for i in range(100000000):
+        pass
+    send_prompt(event.title, "never sent")
+
+on("instance_created", spin)
+`)
+
+	engine, errs := Load(dir)
+	require.Len(t, errs, 3)
+	assert.Contains(t, errs[0].Error(), "eager.star")
+	assert.Contains(t, errs[0].Error(), "can only be called from an event handler")
+	assert.Contains(t, errs[1].Error(), "syntax.star")
+	assert.Contains(t, errs[2].Error(), `unknown event "lunch_time"`)
+
+	// A handler that runs too long is stopped and its actions dropped
+	actions, errs := engine.Dispatch(Event{Type: EventInstanceCreated, Title: "api"})
+	assert.Empty(t, actions)
+	require.Len(t, errs, 1)
+	assert.Contains(t, errs[0].Error(), "loop.star")
+
+	// No scripts directory loads nothing
+	engine, errs = Load(filepath.Join(dir, "missing"))
+	assert.Empty(t, errs)
+	assert.False(t, engine.Subscribed(EventInstanceCreated))
+}
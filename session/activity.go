@@ -0,0 +1,199 @@
+package session
+
+import (
+	"bufio"
+	"claude-squad/config"
+	"claude-squad/log"
+	"claude-squad/session/git"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"text/tabwriter"
+	"time"
+)
+
+// Activity is what the daily report knows about an instance's run, whether the instance still
+// exists or was killed.
+type Activity struct {
+	Title     string    `json:"title"`
+	Program   string    `json:"program"`
+	CreatedAt time.Time `json:"created_at"`
+	// EndedAt is when the instance was killed, zero while it exists
+	EndedAt     time.Time `json:"ended_at,omitempty"`
+	PromptsSent int       `json:"prompts_sent,omitempty"`
+	// CommitDays counts the commits on the instance's branch by the day they were made
+	CommitDays map[string]int `json:"commit_days,omitempty"`
+	Usage      Usage          `json:"usage"`
+}
+
+// newActivity captures the activity of a stored instance. Its commits are counted from its
+// branch, so capture it before a kill deletes the branch.
+func newActivity(data InstanceData) Activity {
+	activity := Activity{
+		Title:       data.Title,
+		Program:     data.Program,
+		CreatedAt:   data.CreatedAt,
+		PromptsSent: data.Metrics.PromptsSent,
+		Usage:       data.Metrics.Usage,
+	}
+	// The branches of instances on a remote host are in a repository on that host
+	if data.Host == "" {
+		days, err := git.CommitDays(data.Worktree.RepoPath, data.Worktree.BranchName, data.Worktree.BaseCommitSHA)
+		if err != nil {
+			log.WarningLog.Printf("could not count the commits of %s: %v", data.Title, err)
+		}
+		activity.CommitDays = days
+	}
+	return activity
+}
+
+func activityPath() (string, error) {
+	configDir, err := config.GetConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get config directory: %w", err)
+	}
+	return filepath.Join(configDir, config.ActivityFileName), nil
+}
+
+// recordEnded appends the activity of a killed instance to the activity file.
+func recordEnded(activity Activity) error {
+	path, err := activityPath()
+	if err != nil {
+		return err
+	}
+	data, err := json.Marshal(activity)
+	if err != nil {
+		return fmt.Errorf("failed to marshal activity: %w", err)
+	}
+
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open activity file: %w", err)
+	}
+	defer f.Close()
+	if _, err := f.Write(append(data, '\n')); err != nil {
+		return fmt.Errorf("failed to write activity: %w", err)
+	}
+	return nil
+}
+
+// loadEnded reads the activity of the killed instances, oldest first. Malformed lines are skipped.
+func loadEnded() ([]Activity, error) {
+	path, err := activityPath()
+	if err != nil {
+		return nil, err
+	}
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to open activity file: %w", err)
+	}
+	defer f.Close()
+
+	var activities []Activity
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		var activity Activity
+		if err := json.Unmarshal(scanner.Bytes(), &activity); err != nil {
+			continue
+		}
+		activities = append(activities, activity)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read activity file: %w", err)
+	}
+	return activities, nil
+}
+
+// recordKilled records the activity of an instance that was killed.
+func recordKilled(activity Activity) {
+	activity.EndedAt = time.Now()
+	if err := recordEnded(activity); err != nil {
+		log.WarningLog.Printf("failed to record the activity of %s: %v", activity.Title, err)
+	}
+}
+
+// DailyUsage sums up the activity of one day.
+type DailyUsage struct {
+	Day         string `json:"day"`
+	Created     int    `json:"created"`
+	Killed      int    `json:"killed"`
+	PromptsSent int    `json:"prompts_sent"`
+	Commits     int    `json:"commits"`
+	Usage
+}
+
+// DailyReport sums the activity up by day, oldest first, leaving out the days before since.
+// Prompts and tokens are only known in total per instance, so they count on the day it was
+// created.
+func DailyReport(activities []Activity, since time.Time) []DailyUsage {
+	byDay := make(map[string]*DailyUsage)
+	day := func(name string) *DailyUsage {
+		if byDay[name] == nil {
+			byDay[name] = &DailyUsage{Day: name}
+		}
+		return byDay[name]
+	}
+	first := since.Format(git.DayFormat)
+	for _, activity := range activities {
+		created := day(activity.CreatedAt.Local().Format(git.DayFormat))
+		created.Created++
+		created.PromptsSent += activity.PromptsSent
+		created.Usage = created.Usage.Add(activity.Usage)
+		if !activity.EndedAt.IsZero() {
+			day(activity.EndedAt.Local().Format(git.DayFormat)).Killed++
+		}
+		for name, commits := range activity.CommitDays {
+			day(name).Commits += commits
+		}
+	}
+
+	report := make([]DailyUsage, 0, len(byDay))
+	for name, usage := range byDay {
+		if since.IsZero() || name >= first {
+			report = append(report, *usage)
+		}
+	}
+	sort.Slice(report, func(i, j int) bool { return report[i].Day < report[j].Day })
+	return report
+}
+
+// WriteDailyReportTable writes the report as a table with a total row.
+func WriteDailyReportTable(w io.Writer, report []DailyUsage) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintf(tw, "DAY\tCREATED\tKILLED\tPROMPTS\tCOMMITS\tTOKENS\tCOST\t\n")
+	var total DailyUsage
+	row := func(usage DailyUsage) {
+		fmt.Fprintf(tw, "%s\t%d\t%d\t%d\t%d\t%s\t$%.2f\t\n", usage.Day, usage.Created, usage.Killed,
+			usage.PromptsSent, usage.Commits, FormatTokens(usage.Tokens()), usage.Cost)
+	}
+	for _, usage := range report {
+		row(usage)
+		total.Created += usage.Created
+		total.Killed += usage.Killed
+		total.PromptsSent += usage.PromptsSent
+		total.Commits += usage.Commits
+		total.Usage = total.Usage.Add(usage.Usage)
+	}
+	total.Day = "total"
+	row(total)
+	if err := tw.Flush(); err != nil {
+		return fmt.Errorf("failed to write report: %w", err)
+	}
+	return nil
+}
+
+// WriteDailyReportJSON writes the report as a JSON array.
+func WriteDailyReportJSON(w io.Writer, report []DailyUsage) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(report); err != nil {
+		return fmt.Errorf("failed to write report: %w", err)
+	}
+	return nil
+}
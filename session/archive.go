@@ -0,0 +1,290 @@
+package session
+
+import (
+	"archive/tar"
+	"claude-squad/config"
+	"claude-squad/log"
+	"claude-squad/session/git"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Files in a session archive.
+const (
+	ArchiveMetadataFile   = "metadata.json"
+	ArchiveScrollbackFile = "scrollback.txt"
+	ArchiveTerminalFile   = "terminal.txt"
+	ArchivePatchFile      = "changes.patch"
+	ArchiveCommitsFile    = "commits.txt"
+)
+
+// archiveNameRe matches the characters not kept from a title in an archive's file name.
+var archiveNameRe = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+// ArchiveMetadata describes an archived session.
+type ArchiveMetadata struct {
+	Instance   InstanceData `json:"instance"`
+	ArchivedAt time.Time    `json:"archived_at"`
+	// ArchivedBranch is the branch's name under archive/ after the session was killed
+	ArchivedBranch string `json:"archived_branch"`
+}
+
+// ArchiveEntry is a session archive on disk.
+type ArchiveEntry struct {
+	Path     string
+	Metadata ArchiveMetadata
+}
+
+// Archive terminates the instance like KillKeepBranch, renames its branch to archive/<branch>
+// and writes a tar.gz of its metadata, pane scrollback and changes to the archive directory, so
+// it can be inspected and restored later. Returns the archived branch name and the archive path.
+func (i *Instance) Archive() (string, string, error) {
+	if i.gitWorktree == nil {
+		return "", "", fmt.Errorf("instance '%s' has no git worktree to archive", i.Title)
+	}
+	// Commit first so the archived patch has every change
+	if i.started && i.Status != Paused {
+		if err := i.commitDirtyChanges("archived"); err != nil {
+			return "", "", err
+		}
+	}
+
+	files := map[string]string{}
+	if i.tmuxSession != nil && i.tmuxSession.DoesSessionExist() {
+		// The AI runs in pane 0 until the terminal pane is split off above it
+		aiPane := 0
+		if counts, err := i.tmuxSession.PaneLineCounts(); err == nil && len(counts) > 1 {
+			aiPane = 1
+			if terminal, err := i.tmuxSession.CapturePaneHistory(0); err == nil {
+				files[ArchiveTerminalFile] = terminal
+			}
+		}
+		if scrollback, err := i.tmuxSession.CapturePaneHistory(aiPane); err == nil {
+			files[ArchiveScrollbackFile] = scrollback
+		}
+	}
+	patch, commits, err := i.gitWorktree.BranchChanges()
+	if err != nil {
+		log.WarningLog.Printf("archiving '%s' without its changes: %v", i.Title, err)
+	}
+	files[ArchivePatchFile], files[ArchiveCommitsFile] = patch, commits
+
+	now := time.Now()
+	metadata := ArchiveMetadata{
+		Instance:       i.ToInstanceData(),
+		ArchivedAt:     now,
+		ArchivedBranch: "archive/" + i.gitWorktree.GetBranchName(),
+	}
+	path, err := writeArchive(metadata, files)
+	if err != nil {
+		return "", "", err
+	}
+
+	if err := i.KillKeepBranch(); err != nil {
+		_ = os.Remove(path)
+		return "", "", err
+	}
+	archived, err := i.gitWorktree.ArchiveBranch()
+	if err != nil {
+		_ = os.Remove(path)
+		return "", "", err
+	}
+	return archived, path, nil
+}
+
+// archiveDir returns the directory archives are written to.
+func archiveDir() (string, error) {
+	configDir, err := config.GetConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get config directory: %w", err)
+	}
+	return filepath.Join(configDir, config.ArchiveDirName), nil
+}
+
+// writeArchive writes the metadata and files to a new tar.gz in the archive directory, named
+// after the time and title, and returns its path.
+func writeArchive(metadata ArchiveMetadata, files map[string]string) (string, error) {
+	dir, err := archiveDir()
+	if err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", fmt.Errorf("failed to create archive directory: %w", err)
+	}
+	name := metadata.ArchivedAt.Format("20060102-150405") + "-" +
+		strings.Trim(archiveNameRe.ReplaceAllString(metadata.Instance.Title, "-"), "-") + ".tar.gz"
+	path := filepath.Join(dir, name)
+
+	encoded, err := json.MarshalIndent(metadata, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to encode archive metadata: %w", err)
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to create archive: %w", err)
+	}
+	err = writeTarGz(f, metadata.ArchivedAt, append([]archiveFile{{ArchiveMetadataFile, string(encoded)}}, sortedFiles(files)...))
+	if closeErr := f.Close(); err == nil && closeErr != nil {
+		err = closeErr
+	}
+	if err != nil {
+		_ = os.Remove(path)
+		return "", fmt.Errorf("failed to write archive: %w", err)
+	}
+	return path, nil
+}
+
+// archiveFile is a file to write to an archive.
+type archiveFile struct {
+	name, content string
+}
+
+// sortedFiles returns the non-empty files by name.
+func sortedFiles(files map[string]string) []archiveFile {
+	var sorted []archiveFile
+	for name, content := range files {
+		if content != "" {
+			sorted = append(sorted, archiveFile{name, content})
+		}
+	}
+	sort.Slice(sorted, func(a, b int) bool { return sorted[a].name < sorted[b].name })
+	return sorted
+}
+
+// writeTarGz writes the files to w as a gzipped tar.
+func writeTarGz(w io.Writer, modTime time.Time, files []archiveFile) error {
+	gz := gzip.NewWriter(w)
+	tw := tar.NewWriter(gz)
+	for _, file := range files {
+		header := &tar.Header{Name: file.name, Mode: 0644, Size: int64(len(file.content)), ModTime: modTime}
+		if err := tw.WriteHeader(header); err != nil {
+			return err
+		}
+		if _, err := io.WriteString(tw, file.content); err != nil {
+			return err
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	return gz.Close()
+}
+
+// ListArchives returns the session archives, newest first. Archives that can't be read are
+// skipped and logged.
+func ListArchives() ([]ArchiveEntry, error) {
+	dir, err := archiveDir()
+	if err != nil {
+		return nil, err
+	}
+	dirEntries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read archive directory: %w", err)
+	}
+
+	var entries []ArchiveEntry
+	for _, dirEntry := range dirEntries {
+		if dirEntry.IsDir() || !strings.HasSuffix(dirEntry.Name(), ".tar.gz") {
+			continue
+		}
+		entry := ArchiveEntry{Path: filepath.Join(dir, dirEntry.Name())}
+		data, err := ReadArchiveFile(entry.Path, ArchiveMetadataFile)
+		if err == nil {
+			err = json.Unmarshal([]byte(data), &entry.Metadata)
+		}
+		if err != nil {
+			log.WarningLog.Printf("skipping unreadable archive %s: %v", entry.Path, err)
+			continue
+		}
+		entries = append(entries, entry)
+	}
+	sort.Slice(entries, func(a, b int) bool {
+		return entries[a].Metadata.ArchivedAt.After(entries[b].Metadata.ArchivedAt)
+	})
+	return entries, nil
+}
+
+// ReadArchiveFile returns the content of a file in an archive, or "" if the archive doesn't
+// have it.
+func ReadArchiveFile(path, name string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to open archive: %w", err)
+	}
+	defer f.Close()
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		return "", fmt.Errorf("failed to read archive %s: %w", filepath.Base(path), err)
+	}
+	tr := tar.NewReader(gz)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			return "", nil
+		}
+		if err != nil {
+			return "", fmt.Errorf("failed to read archive %s: %w", filepath.Base(path), err)
+		}
+		if header.Name == name {
+			data, err := io.ReadAll(tr)
+			if err != nil {
+				return "", fmt.Errorf("failed to read %s from archive: %w", name, err)
+			}
+			return string(data), nil
+		}
+	}
+}
+
+// RestoreArchive recreates an archived session as a paused instance titled title, renaming its
+// branch back from archive/ and restoring its settings, checkpoints and metrics. The archive is
+// removed once the session is restored.
+func RestoreArchive(entry ArchiveEntry, title string) (*Instance, error) {
+	data := entry.Metadata.Instance
+	branch, err := git.RestoreArchivedBranch(data.Worktree.RepoPath, entry.Metadata.ArchivedBranch, data.Worktree.BranchName)
+	if err != nil {
+		return nil, err
+	}
+	instance, err := restoreInstance(data, title, branch)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := os.Remove(entry.Path); err != nil {
+		log.WarningLog.Printf("failed to remove restored archive %s: %v", entry.Path, err)
+	}
+	return instance, nil
+}
+
+// restoreInstance recreates a killed instance as a paused instance titled title on branch, with
+// its settings, checkpoints and metrics.
+func restoreInstance(data InstanceData, title, branch string) (*Instance, error) {
+	instance, err := NewImportedInstance(InstanceOptions{
+		Title:      title,
+		Path:       data.Path,
+		Program:    data.Program,
+		AutoYes:    data.AutoYes,
+		BranchName: branch,
+	})
+	if err != nil {
+		return nil, err
+	}
+	instance.Checkpoints = data.Checkpoints
+	instance.PromptQueue = data.PromptQueue
+	instance.Tags = data.Tags
+	instance.MuteNotifications = data.MuteNotifications
+	instance.TimeBudget = data.TimeBudget
+	instance.WorkTime = data.WorkTime
+	instance.metrics = data.Metrics
+	return instance, nil
+}
@@ -0,0 +1,41 @@
+package session
+
+import "fmt"
+
+// RenamedFrom returns the branch the instance was created for if it was checked out in another
+// worktree, so the instance works on a suffixed copy of it instead. It's empty otherwise.
+func (i *Instance) RenamedFrom() string {
+	if i.gitWorktree == nil {
+		return ""
+	}
+	return i.gitWorktree.RequestedBranch()
+}
+
+// PushBranch returns the remote branch the instance's pushes go to.
+func (i *Instance) PushBranch() string {
+	if i.gitWorktree == nil {
+		return i.Branch
+	}
+	return i.gitWorktree.PushBranch()
+}
+
+// RenameBranchBack renames the suffixed branch of the instance to the branch it was created for.
+func (i *Instance) RenameBranchBack() error {
+	if i.gitWorktree == nil {
+		return fmt.Errorf("instance has no git worktree")
+	}
+	if err := i.gitWorktree.RenameToRequestedBranch(); err != nil {
+		return err
+	}
+	i.Branch = i.gitWorktree.GetBranchName()
+	return nil
+}
+
+// PushToRequestedBranch makes the suffixed branch of the instance track and push to the remote
+// branch it was created for.
+func (i *Instance) PushToRequestedBranch() error {
+	if i.gitWorktree == nil {
+		return fmt.Errorf("instance has no git worktree")
+	}
+	return i.gitWorktree.PushToRequestedBranch()
+}
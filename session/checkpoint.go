@@ -0,0 +1,122 @@
+package session
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	checkpointBegin = "CHECKPOINT-BEGIN"
+	checkpointEnd   = "CHECKPOINT-END"
+
+	// checkpointPollInterval is how often the pane is checked for the agent's summary.
+	checkpointPollInterval = 2 * time.Second
+)
+
+// CheckpointPrompt is the canned prompt asking the agent to summarize its context. The
+// markers must be printed on their own lines so the echoed prompt itself never matches.
+var CheckpointPrompt = "Summarize your current context and progress so this work can be resumed later " +
+	"by someone with no memory of this session: the goal, what is done, what is in progress, key " +
+	"decisions, open questions and next steps. Do not change any files. Print a line containing only " +
+	checkpointBegin + " before the summary and a line containing only " + checkpointEnd + " after it."
+
+// Checkpoint is a timestamped summary of the agent's context, stored as a note on the instance.
+type Checkpoint struct {
+	Name      string    `json:"name"`
+	Summary   string    `json:"summary"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// isCheckpointMarker returns true if line consists only of the marker, ignoring indentation
+// and the bullet agents put in front of their replies.
+func isCheckpointMarker(line, marker string) bool {
+	line = strings.TrimSpace(line)
+	line = strings.TrimSpace(strings.TrimLeft(line, "⏺●•*-"))
+	return line == marker
+}
+
+// checkpointBlocks returns the text of every complete begin/end marker block in content.
+func checkpointBlocks(content string) []string {
+	var blocks []string
+	var current []string
+	inBlock := false
+	for _, line := range strings.Split(content, "\n") {
+		switch {
+		case isCheckpointMarker(line, checkpointBegin):
+			inBlock = true
+			current = nil
+		case inBlock && isCheckpointMarker(line, checkpointEnd):
+			inBlock = false
+			blocks = append(blocks, dedent(current))
+		case inBlock:
+			current = append(current, strings.TrimRight(line, " "))
+		}
+	}
+	return blocks
+}
+
+// dedent removes the indentation shared by all non-empty lines and trims surrounding blank lines.
+func dedent(lines []string) string {
+	indent := -1
+	for _, line := range lines {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		n := len(line) - len(strings.TrimLeft(line, " "))
+		if indent == -1 || n < indent {
+			indent = n
+		}
+	}
+	out := make([]string, len(lines))
+	for i, line := range lines {
+		if len(line) >= indent && indent > 0 {
+			line = line[indent:]
+		}
+		out[i] = line
+	}
+	return strings.Trim(strings.Join(out, "\n"), "\n")
+}
+
+// AddCheckpoint stores a checkpoint note on the instance. An empty name defaults to the timestamp.
+func (i *Instance) AddCheckpoint(name, summary string) Checkpoint {
+	now := time.Now()
+	if name == "" {
+		name = now.Format("2006-01-02 15:04")
+	}
+	checkpoint := Checkpoint{Name: name, Summary: summary, CreatedAt: now}
+	i.Checkpoints = append(i.Checkpoints, checkpoint)
+	return checkpoint
+}
+
+// CaptureCheckpoint sends CheckpointPrompt to the agent and waits up to timeout for it to
+// print a summary, which is returned. It does not store the checkpoint, so it is safe to
+// call from a background command; pass the result to AddCheckpoint.
+func (i *Instance) CaptureCheckpoint(timeout time.Duration) (string, error) {
+	if !i.started || i.Status == Paused {
+		return "", fmt.Errorf("cannot checkpoint instance '%s' that is not running", i.Title)
+	}
+
+	before, err := i.PreviewFullHistory()
+	if err != nil {
+		return "", fmt.Errorf("failed to capture pane: %w", err)
+	}
+	baseline := len(checkpointBlocks(before))
+
+	if err := i.SendPrompt(CheckpointPrompt); err != nil {
+		return "", fmt.Errorf("failed to send checkpoint prompt: %w", err)
+	}
+
+	deadline := time.Now().Add(timeout)
+	for time.Now().Before(deadline) {
+		time.Sleep(checkpointPollInterval)
+		content, err := i.PreviewFullHistory()
+		if err != nil {
+			return "", fmt.Errorf("failed to capture pane: %w", err)
+		}
+		if blocks := checkpointBlocks(content); len(blocks) > baseline {
+			return blocks[len(blocks)-1], nil
+		}
+	}
+	return "", fmt.Errorf("timed out waiting for '%s' to write a checkpoint summary", i.Title)
+}
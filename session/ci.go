@@ -0,0 +1,75 @@
+package session
+
+import (
+	"claude-squad/session/git"
+	"fmt"
+	"time"
+)
+
+// ciChecksCacheTTL defines how long the cached CI checks are valid
+const ciChecksCacheTTL = time.Minute
+
+// UpdateCIChecks refreshes the cached CI checks of the branch's commit on origin. It shells out
+// to gh or glab, so call it off the UI thread.
+func (i *Instance) UpdateCIChecks() error {
+	// The checkouts of instances on remote hosts aren't visible to the local CLI
+	if !i.started || i.Status == Paused || i.Host != "" {
+		return nil
+	}
+
+	i.prStatusMu.RLock()
+	fresh := time.Since(i.ciChecksTime) < ciChecksCacheTTL
+	i.prStatusMu.RUnlock()
+	if fresh || !i.gitWorktree.HasRemote() {
+		return nil
+	}
+	return i.fetchCIChecks()
+}
+
+// LoadCIChecks fetches the CI checks of the branch's commit on origin, however fresh the cached
+// ones are, and returns them. It shells out to gh or glab, so call it off the UI thread.
+func (i *Instance) LoadCIChecks() ([]git.CICheck, error) {
+	if !i.started || i.Status == Paused {
+		return nil, fmt.Errorf("instance '%s' is not running", i.Title)
+	}
+	if i.Host != "" {
+		return nil, fmt.Errorf("CI checks are not available for instances on remote hosts")
+	}
+	if err := i.fetchCIChecks(); err != nil {
+		return nil, err
+	}
+	return i.CIChecks(), nil
+}
+
+// fetchCIChecks fetches the CI checks into the cache.
+func (i *Instance) fetchCIChecks() error {
+	checks, err := i.gitWorktree.CIChecks()
+
+	i.prStatusMu.Lock()
+	defer i.prStatusMu.Unlock()
+	i.ciChecksTime = time.Now()
+	if err != nil {
+		i.ciChecks = nil
+		return err
+	}
+	i.ciChecks = checks
+	return nil
+}
+
+// CIChecks returns the cached CI checks of the branch's commit on origin.
+func (i *Instance) CIChecks() []git.CICheck {
+	i.prStatusMu.RLock()
+	defer i.prStatusMu.RUnlock()
+	return append([]git.CICheck(nil), i.ciChecks...)
+}
+
+// CIState returns the rolled-up state of the cached CI checks, one of the git.CIState constants.
+func (i *Instance) CIState() string {
+	i.prStatusMu.RLock()
+	defer i.prStatusMu.RUnlock()
+	states := make([]string, len(i.ciChecks))
+	for n, check := range i.ciChecks {
+		states[n] = check.State
+	}
+	return git.RollupCIState(states)
+}
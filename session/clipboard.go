@@ -0,0 +1,68 @@
+package session
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/atotto/clipboard"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// MaxClipboardHistory is how many copied snippets an instance remembers.
+const MaxClipboardHistory = 20
+
+// ClipboardEntry is a snippet copied to the system clipboard for an instance.
+type ClipboardEntry struct {
+	// Label describes what was copied, such as "branch" or "commit"
+	Label    string    `json:"label"`
+	Text     string    `json:"text"`
+	CopiedAt time.Time `json:"copied_at"`
+}
+
+// codeBlockRegex matches a fenced code block, capturing its content.
+var codeBlockRegex = regexp.MustCompile("(?m)^[ \t]*```[^\n]*\n((?s:.*?))\n?[ \t]*```[ \t]*$")
+
+// ClipboardHistory returns the snippets copied for the instance, most recent first.
+func (i *Instance) ClipboardHistory() []ClipboardEntry {
+	return i.Clipboard
+}
+
+// Copy writes text to the system clipboard and records it in the instance's clipboard history
+// under label. Copying a snippet already in the history moves it to the front.
+func (i *Instance) Copy(label, text string) error {
+	i.recordCopy(label, text)
+	if err := clipboard.WriteAll(text); err != nil {
+		return fmt.Errorf("failed to copy to clipboard: %w", err)
+	}
+	return nil
+}
+
+// recordCopy adds text to the front of the clipboard history, dropping the oldest snippets past
+// MaxClipboardHistory.
+func (i *Instance) recordCopy(label, text string) {
+	history := []ClipboardEntry{{Label: label, Text: text, CopiedAt: time.Now()}}
+	for _, entry := range i.Clipboard {
+		if entry.Text != text {
+			history = append(history, entry)
+		}
+	}
+	if len(history) > MaxClipboardHistory {
+		history = history[:MaxClipboardHistory]
+	}
+	i.Clipboard = history
+}
+
+// CodeBlocks returns the fenced code blocks in the agent's output, last first, without their
+// fences.
+func CodeBlocks(output string) []string {
+	matches := codeBlockRegex.FindAllStringSubmatch(ansi.Strip(output), -1)
+	blocks := make([]string, 0, len(matches))
+	for n := len(matches) - 1; n >= 0; n-- {
+		if block := strings.TrimRight(matches[n][1], " \t\n"); strings.TrimSpace(block) != "" {
+			blocks = append(blocks, block)
+		}
+	}
+	return blocks
+}
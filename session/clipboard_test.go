@@ -0,0 +1,58 @@
+package session
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecordCopy(t *testing.T) {
+	instance := &Instance{}
+	instance.recordCopy("branch", "me/feature")
+	instance.recordCopy("commit", "abc123")
+	require.Len(t, instance.ClipboardHistory(), 2)
+	assert.Equal(t, "abc123", instance.ClipboardHistory()[0].Text, "the latest copy comes first")
+
+	// Copying a snippet again moves it to the front rather than adding it twice
+	instance.recordCopy("branch", "me/feature")
+	history := instance.ClipboardHistory()
+	require.Len(t, history, 2)
+	assert.Equal(t, "me/feature", history[0].Text)
+	assert.Equal(t, "abc123", history[1].Text)
+
+	// The oldest snippets are dropped past the limit
+	for n := 0; n < MaxClipboardHistory+5; n++ {
+		instance.recordCopy("code block", fmt.Sprintf("snippet %d", n))
+	}
+	history = instance.ClipboardHistory()
+	require.Len(t, history, MaxClipboardHistory)
+	assert.Equal(t, fmt.Sprintf("snippet %d", MaxClipboardHistory+4), history[0].Text)
+	assert.Equal(t, "snippet 5", history[len(history)-1].Text)
+}
+
+func TestCodeBlocks(t *testing.T) {
+	tests := []struct {
+		name   string
+		output string
+		want   []string
+	}{
+		{"none", "no code here", []string{}},
+		{
+			"last first",
+			"first:\n```go\nfmt.Println(1)\n```\nthen:\n```\necho 2\n```\n",
+			[]string{"echo 2", "fmt.Println(1)"},
+		},
+		{"indented fences", "  ```sh\n  make test\n  ```", []string{"  make test"}},
+		{"multiple lines", "```\none\n\ntwo\n```", []string{"one\n\ntwo"}},
+		{"empty blocks are skipped", "```\n\n```\n```\nkept\n```", []string{"kept"}},
+		{"colored output", "\x1b[32m```\x1b[0m\ngreen\n```", []string{"green"}},
+		{"unclosed", "```\nstill typing", []string{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, CodeBlocks(tt.output))
+		})
+	}
+}
@@ -0,0 +1,98 @@
+package session
+
+import (
+	"claude-squad/config"
+	"claude-squad/session/container"
+	"path/filepath"
+	"time"
+)
+
+// containerStatusTTL is how long the cached status of an instance's container is shown before
+// docker is asked again.
+const containerStatusTTL = 5 * time.Second
+
+// dockerContainer returns the container the instance's program runs in, or nil if it runs
+// directly on its machine.
+func (i *Instance) dockerContainer() *container.Container {
+	if i.Container == "" {
+		return nil
+	}
+	return container.FromName(i.Container)
+}
+
+// startContainer makes the instance's container run, creating it with the worktree and the
+// repository's git directory mounted if it doesn't exist. Instances without one are left alone.
+// The program can change the worktree, its index and the object store, but not the repository's
+// refs or config, so it can't move or delete other branches; its changes are committed from
+// this machine.
+func (i *Instance) startContainer() error {
+	c := i.dockerContainer()
+	if c == nil {
+		return nil
+	}
+	var cfg config.ContainerConfig
+	if configured := i.settings().Container; configured != nil {
+		cfg = *configured
+	}
+	// The worktree's .git file points at the repository's git directory, which is mounted at
+	// the same path so it resolves in the container too
+	commonDir, err := i.gitWorktree.CommonGitDir()
+	if err != nil {
+		return err
+	}
+	gitDir, err := i.gitWorktree.GitDir()
+	if err != nil {
+		return err
+	}
+	mounts := []container.Mount{
+		{Path: commonDir, ReadOnly: true},
+		// Staging writes objects, and the worktree's own git directory holds its index
+		{Path: filepath.Join(commonDir, "objects")},
+		{Path: gitDir},
+	}
+	if err := c.Ensure(cfg, i.gitWorktree.GetWorktreePath(), mounts...); err != nil {
+		i.containerStatusTime = time.Time{}
+		return err
+	}
+	i.containerStatus, i.containerStatusTime = "running", time.Now()
+	return nil
+}
+
+// removeContainer stops and removes the instance's container. The worktree and branch stay, so
+// it's recreated when the instance next starts. Its mount of the worktree would go stale once
+// the worktree is removed or moved, so it can't be kept for that.
+func (i *Instance) removeContainer() error {
+	c := i.dockerContainer()
+	if c == nil {
+		return nil
+	}
+	if err := c.Remove(); err != nil {
+		i.containerStatusTime = time.Time{}
+		return err
+	}
+	i.containerStatus, i.containerStatusTime = "removed", time.Now()
+	return nil
+}
+
+// UpdateContainerStatus refreshes the cached status of the instance's container, at most every
+// containerStatusTTL.
+func (i *Instance) UpdateContainerStatus() {
+	c := i.dockerContainer()
+	if c == nil || time.Since(i.containerStatusTime) < containerStatusTTL {
+		return
+	}
+	status, err := c.Status()
+	if err != nil {
+		status = "unknown"
+	} else if status == "" {
+		status = "removed"
+	}
+	i.containerStatus = status
+	i.containerStatusTime = time.Now()
+}
+
+// ContainerStatus returns the last seen status of the instance's container, e.g. "running" or
+// "exited", and an empty string if the instance has no container or it hasn't been checked yet.
+func (i *Instance) ContainerStatus() string {
+	return i.containerStatus
+}
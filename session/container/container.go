@@ -0,0 +1,127 @@
+package container
+
+import (
+	"claude-squad/config"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"strings"
+)
+
+// NamePrefix starts the names of the containers claude-squad creates.
+const NamePrefix = "claude-squad-"
+
+// Container is the Docker container an instance's program runs in. It sleeps until removed, and
+// the program runs in it with docker exec, so the tmux session can be restarted without losing
+// the container.
+type Container struct {
+	name string
+}
+
+var invalidNameChars = regexp.MustCompile(`[^a-zA-Z0-9_.-]`)
+
+// New returns the container of the instance whose worktree is at worktreePath. Worktree paths
+// are unique, so the name is too.
+func New(worktreePath string) *Container {
+	return FromName(NamePrefix + invalidNameChars.ReplaceAllString(filepath.Base(worktreePath), "_"))
+}
+
+// FromName returns the container called name, for an instance restored from storage.
+func FromName(name string) *Container {
+	return &Container{name: name}
+}
+
+// Name returns the container's name.
+func (c *Container) Name() string {
+	return c.name
+}
+
+// Mount is a directory bind-mounted into the container at the same path as on this machine.
+type Mount struct {
+	Path string
+	// ReadOnly keeps the container from changing anything under Path
+	ReadOnly bool
+}
+
+// ExecCommand returns the command that runs program in the container, with a terminal.
+func (c *Container) ExecCommand(program string) string {
+	return fmt.Sprintf("docker exec -it %s %s", c.name, program)
+}
+
+// runArgs returns the docker run arguments creating the container from cfg, with workDir as
+// its working directory and it and mounts bind-mounted at the same paths as on this machine.
+// Docker mounts a directory nested in another mount over it, whatever their order.
+func (c *Container) runArgs(cfg config.ContainerConfig, workDir string, mounts []Mount) []string {
+	args := []string{"run", "-d", "--init", "--name", c.name}
+	// Files the program writes to the worktree must stay the user's
+	if runtime.GOOS == "linux" {
+		args = append(args, "--user", fmt.Sprintf("%d:%d", os.Getuid(), os.Getgid()))
+	}
+	for _, mount := range append([]Mount{{Path: workDir}}, mounts...) {
+		spec := mount.Path + ":" + mount.Path
+		if mount.ReadOnly {
+			spec += ":ro"
+		}
+		args = append(args, "-v", spec)
+	}
+	args = append(args, "-w", workDir)
+	args = append(args, cfg.RunArgs...)
+	return append(args, cfg.Image, "sleep", "infinity")
+}
+
+// Ensure makes the container run: it's created if it doesn't exist and started if it stopped,
+// e.g. after a reboot.
+func (c *Container) Ensure(cfg config.ContainerConfig, workDir string, mounts ...Mount) error {
+	status, err := c.Status()
+	if err != nil {
+		return err
+	}
+	switch status {
+	case "running":
+		return nil
+	case "":
+		if cfg.Image == "" {
+			return fmt.Errorf("failed to create container %s: no image configured", c.name)
+		}
+		if err := docker(c.runArgs(cfg, workDir, mounts)...); err != nil {
+			return fmt.Errorf("failed to create container %s: %w", c.name, err)
+		}
+	default:
+		if err := docker("start", c.name); err != nil {
+			return fmt.Errorf("failed to start container %s: %w", c.name, err)
+		}
+	}
+	return nil
+}
+
+// Remove stops and removes the container. A container that doesn't exist is already removed.
+func (c *Container) Remove() error {
+	if status, err := c.Status(); err != nil || status == "" {
+		return err
+	}
+	if err := docker("rm", "-f", c.name); err != nil {
+		return fmt.Errorf("failed to remove container %s: %w", c.name, err)
+	}
+	return nil
+}
+
+// Status returns the container's state as docker reports it, e.g. "running" or "exited", and
+// an empty string if it doesn't exist.
+func (c *Container) Status() (string, error) {
+	output, err := exec.Command("docker", "ps", "-a", "--filter", "name=^"+c.name+"$", "--format", "{{.State}}").Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to get status of container %s: %w", c.name, err)
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// docker runs docker with args, returning its output in the error if it fails.
+func docker(args ...string) error {
+	if output, err := exec.Command("docker", args...).CombinedOutput(); err != nil {
+		return fmt.Errorf("%s (%w)", strings.TrimSpace(string(output)), err)
+	}
+	return nil
+}
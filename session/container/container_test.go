@@ -0,0 +1,54 @@
+package container
+
+import (
+	"claude-squad/config"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestNew(t *testing.T) {
+	c := New(filepath.Join("/home/me/worktrees", "fix login:bug_18a2f"))
+	if c.Name() != "claude-squad-fix_login_bug_18a2f" {
+		t.Errorf("Name() = %q", c.Name())
+	}
+	if got := c.ExecCommand("claude --resume"); got != "docker exec -it claude-squad-fix_login_bug_18a2f claude --resume" {
+		t.Errorf("ExecCommand() = %q", got)
+	}
+}
+
+func TestRunArgs(t *testing.T) {
+	c := FromName("claude-squad-test")
+	cfg := config.ContainerConfig{Image: "agent:latest", RunArgs: []string{"-e", "ANTHROPIC_API_KEY"}}
+	got := strings.Join(c.runArgs(cfg, "/wt/test", []Mount{
+		{Path: "/repo/.git", ReadOnly: true},
+		{Path: "/repo/.git/worktrees/test"},
+	}), " ")
+
+	user := ""
+	if runtime.GOOS == "linux" {
+		user = fmt.Sprintf("--user %d:%d ", os.Getuid(), os.Getgid())
+	}
+	want := "run -d --init --name claude-squad-test " + user +
+		"-v /wt/test:/wt/test -v /repo/.git:/repo/.git:ro -v /repo/.git/worktrees/test:/repo/.git/worktrees/test -w /wt/test -e ANTHROPIC_API_KEY agent:latest sleep infinity"
+	if got != want {
+		t.Errorf("runArgs() = %q, want %q", got, want)
+	}
+}
+
+func TestEnsureWithoutImage(t *testing.T) {
+	if err := exec.Command("docker", "info").Run(); err != nil {
+		t.Skip("docker is not available")
+	}
+	c := FromName("claude-squad-test-missing-" + fmt.Sprint(os.Getpid()))
+	if err := c.Ensure(config.ContainerConfig{}, t.TempDir()); err == nil || !strings.Contains(err.Error(), "no image") {
+		t.Errorf("Ensure() without an image = %v", err)
+	}
+	if err := c.Remove(); err != nil {
+		t.Errorf("Remove() of a missing container = %v", err)
+	}
+}
@@ -0,0 +1,75 @@
+package session
+
+import (
+	"fmt"
+	"strings"
+)
+
+// digestStatuses names the statuses in a fleet digest.
+var digestStatuses = map[Status]string{
+	Running:  "working",
+	Ready:    "waiting for input",
+	Loading:  "starting",
+	Paused:   "paused",
+	Creating: "being created",
+	Deleting: "being deleted",
+}
+
+// maxDigestPromptLength is how much of an instance's last prompt a fleet digest quotes.
+const maxDigestPromptLength = 200
+
+// FleetDigest describes the instances other than the coordinator for its agent: each one's
+// status, branch, diff, pull request and last prompt with what the run on it changed. It is
+// built from cached state only, so the same fleet gives the same digest.
+func FleetDigest(coordinator *Instance, instances []*Instance) string {
+	var b strings.Builder
+	b.WriteString("Fleet digest from claude-squad: the state of the other sessions you are coordinating.\n")
+	others := 0
+	for _, instance := range instances {
+		if instance == coordinator {
+			continue
+		}
+		others++
+		fmt.Fprintf(&b, "\n## %s\n", instance.Title)
+		fmt.Fprintf(&b, "- status: %s\n", digestStatuses[instance.Status])
+		if instance.Branch != "" {
+			fmt.Fprintf(&b, "- branch: %s\n", instance.Branch)
+		}
+		if stats := instance.GetDiffStats(); stats != nil && stats.Error == nil {
+			fmt.Fprintf(&b, "- diff: +%d -%d\n", stats.Added, stats.Removed)
+		}
+		if behind := instance.BehindMain(); behind > 0 {
+			fmt.Fprintf(&b, "- behind main by %d commit(s)\n", behind)
+		}
+		if pr := instance.GetPRStatus(); pr != nil {
+			fmt.Fprintf(&b, "- pull request #%d: %s", pr.Number, strings.ToLower(pr.State))
+			if pr.ReviewDecision != "" {
+				fmt.Fprintf(&b, ", review %s", strings.ToLower(strings.ReplaceAll(pr.ReviewDecision, "_", " ")))
+			}
+			if pr.CIState != "" {
+				fmt.Fprintf(&b, ", checks %s", strings.ToLower(pr.CIState))
+			}
+			b.WriteString("\n")
+		}
+		if comments := instance.NewCommentCount(); comments > 0 {
+			fmt.Fprintf(&b, "- %d new review comment(s)\n", comments)
+		}
+		if runs := instance.PromptRuns(); len(runs) > 0 {
+			run := runs[len(runs)-1]
+			prompt := strings.Join(strings.Fields(run.Prompt), " ")
+			if len(prompt) > maxDigestPromptLength {
+				prompt = prompt[:maxDigestPromptLength-3] + "..."
+			}
+			fmt.Fprintf(&b, "- last prompt: %s\n", prompt)
+			if run.Summary != nil {
+				fmt.Fprintf(&b, "- last run: %s\n", run.Summary.String())
+			} else {
+				b.WriteString("- last run: still in progress\n")
+			}
+		}
+	}
+	if others == 0 {
+		b.WriteString("\nThere are no other sessions.\n")
+	}
+	return b.String()
+}
@@ -0,0 +1,40 @@
+package session
+
+import (
+	"claude-squad/session/tmux"
+	"fmt"
+)
+
+// MarkFinished records that something finished in the instance while nobody was looking at it.
+func (i *Instance) MarkFinished(what string) {
+	i.finished = what
+}
+
+// Finished describes what finished in the instance since it was last looked at, or "" if
+// nothing did.
+func (i *Instance) Finished() string {
+	return i.finished
+}
+
+// ClearFinished forgets what finished once the instance has been looked at.
+func (i *Instance) ClearFinished() {
+	i.finished = ""
+}
+
+// TerminalCommandFinished checks the command running in the terminal pane and returns a
+// description of the one that exited since the last check, back to the shell, or "" if none did.
+func (i *Instance) TerminalCommandFinished() (string, error) {
+	if !i.started || i.Status == Paused || i.tmuxSession == nil {
+		return "", nil
+	}
+	command, err := i.tmuxSession.TerminalPaneCommand()
+	if err != nil {
+		return "", err
+	}
+	previous := i.terminalCommand
+	i.terminalCommand = command
+	if previous == "" || tmux.IsShell(previous) || !tmux.IsShell(command) {
+		return "", nil
+	}
+	return fmt.Sprintf("'%s' exited in the terminal", previous), nil
+}
@@ -0,0 +1,85 @@
+package git
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// Worktree is a linked worktree of a repository, as git worktree list reports it.
+type Worktree struct {
+	// Path is the worktree's directory.
+	Path string
+	// Branch is the branch checked out in it, empty when its HEAD is detached.
+	Branch string
+	// Head is the commit checked out in it.
+	Head string
+}
+
+// parseWorktreeList parses the output of git worktree list --porcelain into the linked worktrees,
+// skipping the main one, which comes first, and those git considers prunable.
+func parseWorktreeList(output string) []Worktree {
+	var worktrees []Worktree
+	for n, block := range strings.Split(strings.TrimSpace(output), "\n\n") {
+		if n == 0 {
+			continue
+		}
+		var worktree Worktree
+		prunable := false
+		for _, line := range strings.Split(block, "\n") {
+			key, value, _ := strings.Cut(line, " ")
+			switch key {
+			case "worktree":
+				worktree.Path = value
+			case "HEAD":
+				worktree.Head = value
+			case "branch":
+				worktree.Branch = strings.TrimPrefix(value, "refs/heads/")
+			case "prunable", "bare":
+				prunable = true
+			}
+		}
+		if worktree.Path != "" && !prunable {
+			worktrees = append(worktrees, worktree)
+		}
+	}
+	return worktrees
+}
+
+// ListWorktrees returns the linked worktrees of the repository containing repoPath.
+func ListWorktrees(repoPath string) ([]Worktree, error) {
+	gitRoot, err := findGitRepoRoot(repoPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find git repository: %w", err)
+	}
+	g := &GitWorktree{repoPath: gitRoot, worktreePath: gitRoot}
+	output, err := g.runGitCommand(gitRoot, "worktree", "list", "--porcelain")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list worktrees: %w", err)
+	}
+	return parseWorktreeList(output), nil
+}
+
+// NewGitWorktreeForAdoption creates a GitWorktree for a worktree that was created outside
+// claude-squad, so an instance can run in it where it is. The base commit is the branch's merge
+// base with the main branch, as for imported branches.
+func NewGitWorktreeForAdoption(repoPath string, sessionName string, worktree Worktree) (*GitWorktree, error) {
+	if worktree.Branch == "" {
+		return nil, fmt.Errorf("the worktree at %s has a detached HEAD; check out a branch in it first", worktree.Path)
+	}
+	gitRoot, err := findGitRepoRoot(repoPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find git repository: %w", err)
+	}
+	tree := &GitWorktree{
+		repoPath:     gitRoot,
+		sessionName:  sessionName,
+		branchName:   worktree.Branch,
+		worktreePath: filepath.Clean(worktree.Path),
+		history:      &commitHistory{},
+	}
+	if err := tree.resolveBaseCommit(); err != nil {
+		return nil, err
+	}
+	return tree, nil
+}
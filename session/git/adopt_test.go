@@ -0,0 +1,72 @@
+package git
+
+import (
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestParseWorktreeList(t *testing.T) {
+	output := `worktree /src/api
+HEAD 1111111111111111111111111111111111111111
+branch refs/heads/main
+
+worktree /src/api-feature
+HEAD 2222222222222222222222222222222222222222
+branch refs/heads/feature/login
+
+worktree /tmp/bisect
+HEAD 3333333333333333333333333333333333333333
+detached
+
+worktree /gone
+HEAD 4444444444444444444444444444444444444444
+branch refs/heads/old
+prunable gitdir file points to non-existent location
+`
+	want := []Worktree{
+		{Path: "/src/api-feature", Branch: "feature/login", Head: "2222222222222222222222222222222222222222"},
+		{Path: "/tmp/bisect", Head: "3333333333333333333333333333333333333333"},
+	}
+	if got := parseWorktreeList(output); !reflect.DeepEqual(got, want) {
+		t.Errorf("parseWorktreeList() = %+v, want %+v", got, want)
+	}
+}
+
+func TestAdoptWorktree(t *testing.T) {
+	dir := t.TempDir()
+	repo := filepath.Join(dir, "repo")
+	initRepo(t, repo)
+	manual := filepath.Join(dir, "manual")
+	if output, err := exec.Command("git", "-C", repo, "worktree", "add", "-q", "-b", "manual-work", manual).CombinedOutput(); err != nil {
+		t.Fatalf("git worktree add: %v\n%s", err, output)
+	}
+
+	worktrees, err := ListWorktrees(repo)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(worktrees) != 1 || worktrees[0].Branch != "manual-work" {
+		t.Fatalf("ListWorktrees() = %+v", worktrees)
+	}
+	manualPath, _ := filepath.EvalSymlinks(manual)
+	if got, _ := filepath.EvalSymlinks(worktrees[0].Path); got != manualPath {
+		t.Errorf("worktree path = %s, want %s", worktrees[0].Path, manual)
+	}
+
+	tree, err := NewGitWorktreeForAdoption(repo, "manual", worktrees[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tree.GetBranchName() != "manual-work" || tree.GetWorktreePath() != worktrees[0].Path || tree.GetBaseCommitSHA() == "" {
+		t.Errorf("adopted worktree = branch %s, path %s, base %s", tree.GetBranchName(), tree.GetWorktreePath(), tree.GetBaseCommitSHA())
+	}
+	if !tree.WorktreeExists() {
+		t.Error("adopted worktree doesn't exist")
+	}
+
+	if _, err := NewGitWorktreeForAdoption(repo, "detached", Worktree{Path: manual}); err == nil {
+		t.Error("NewGitWorktreeForAdoption() adopted a detached worktree")
+	}
+}
@@ -0,0 +1,29 @@
+package git
+
+import (
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRestoreArchivedBranch(t *testing.T) {
+	repo := filepath.Join(t.TempDir(), "repo")
+	initRepo(t, repo)
+	for _, args := range [][]string{{"branch", "archive/feature"}, {"branch", "archive/taken"}, {"branch", "taken"}} {
+		if output, err := exec.Command("git", append([]string{"-C", repo}, args...)...).CombinedOutput(); err != nil {
+			t.Fatalf("git %s: %v\n%s", strings.Join(args, " "), err, output)
+		}
+	}
+
+	if branch, err := RestoreArchivedBranch(repo, "archive/feature", "feature"); err != nil || branch != "feature" {
+		t.Errorf("RestoreArchivedBranch() = %q, %v, want the original name back", branch, err)
+	}
+	// A new branch with the original name keeps the archived one under archive/
+	if branch, err := RestoreArchivedBranch(repo, "archive/taken", "taken"); err != nil || branch != "archive/taken" {
+		t.Errorf("RestoreArchivedBranch() with the name taken = %q, %v", branch, err)
+	}
+	if _, err := RestoreArchivedBranch(repo, "archive/missing", "missing"); err == nil {
+		t.Error("RestoreArchivedBranch() succeeded for a missing branch")
+	}
+}
@@ -0,0 +1,142 @@
+package git
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// authCheckTimeout bounds each credential probe so a hanging network call doesn't stall the check.
+const authCheckTimeout = 20 * time.Second
+
+// AuthIssue describes expired or missing credentials that will make gh or git operations fail.
+type AuthIssue struct {
+	// Source is the credential that failed, e.g. "gh" or "git (my-repo)".
+	Source string
+	// Message is a one-line description of the problem.
+	Message string
+	// ReauthCommand is an interactive command that fixes the problem. Empty if there is none.
+	ReauthCommand []string
+}
+
+// authFailurePatterns are lowercase fragments of gh/git/ssh output that indicate rejected or
+// expired credentials rather than e.g. a network outage.
+var authFailurePatterns = []string{
+	"authentication failed",
+	"could not read username",
+	"could not read password",
+	"permission denied (publickey",
+	"invalid username or password",
+	"bad credentials",
+	"token is invalid",
+	"token has expired",
+	"saml",
+	"single sign-on",
+	"http 401",
+	"error: 401",
+	"error: 403",
+	"returned error: 403",
+	"not logged into",
+	"failed to log in",
+	"gh auth login",
+}
+
+// isAuthFailure returns true if command output indicates an authentication problem.
+func isAuthFailure(output string) bool {
+	output = strings.ToLower(output)
+	for _, pattern := range authFailurePatterns {
+		if strings.Contains(output, pattern) {
+			return true
+		}
+	}
+	return false
+}
+
+// CheckAuth probes gh and the git credentials for the origin remote of each repo, returning the
+// problems found. Probes that fail for reasons other than authentication (no gh installed, no
+// remote, network errors) are not reported.
+func CheckAuth(repoPaths []string) []AuthIssue {
+	var issues []AuthIssue
+	if issue := checkGHAuth(); issue != nil {
+		issues = append(issues, *issue)
+	}
+	for _, repoPath := range repoPaths {
+		if issue := checkGitCredentials(repoPath); issue != nil {
+			issues = append(issues, *issue)
+		}
+	}
+	return issues
+}
+
+func checkGHAuth() *AuthIssue {
+	if _, err := exec.LookPath("gh"); err != nil {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), authCheckTimeout)
+	defer cancel()
+	output, err := exec.CommandContext(ctx, "gh", "auth", "status").CombinedOutput()
+	if err == nil || ctx.Err() != nil {
+		return nil
+	}
+
+	text := strings.ToLower(string(output))
+	if strings.Contains(text, "not logged into") {
+		return &AuthIssue{
+			Source:        "gh",
+			Message:       "GitHub CLI is not logged in",
+			ReauthCommand: []string{"gh", "auth", "login"},
+		}
+	}
+	if isAuthFailure(text) {
+		return &AuthIssue{
+			Source:        "gh",
+			Message:       "GitHub CLI token is invalid or expired",
+			ReauthCommand: []string{"gh", "auth", "refresh"},
+		}
+	}
+	return nil
+}
+
+// checkGitCredentials runs a non-interactive ls-remote against origin to verify the credential
+// helper or SSH key is still accepted.
+func checkGitCredentials(repoPath string) *AuthIssue {
+	ctx, cancel := context.WithTimeout(context.Background(), authCheckTimeout)
+	defer cancel()
+
+	remoteURL, err := exec.CommandContext(ctx, "git", "-C", repoPath, "remote", "get-url", "origin").Output()
+	if err != nil {
+		return nil
+	}
+
+	cmd := exec.CommandContext(ctx, "git", "-C", repoPath, "ls-remote", "--exit-code", "origin", "HEAD")
+	// Fail instead of prompting for credentials
+	cmd.Env = append(os.Environ(), "GIT_TERMINAL_PROMPT=0")
+	if os.Getenv("GIT_SSH_COMMAND") == "" {
+		cmd.Env = append(cmd.Env, "GIT_SSH_COMMAND=ssh -o BatchMode=yes")
+	}
+	output, err := cmd.CombinedOutput()
+	if err == nil || ctx.Err() != nil || !isAuthFailure(string(output)) {
+		return nil
+	}
+
+	issue := &AuthIssue{
+		Source:  fmt.Sprintf("git (%s)", filepath.Base(repoPath)),
+		Message: "Git credentials for origin were rejected",
+	}
+	url := strings.TrimSpace(string(remoteURL))
+	if strings.HasPrefix(url, "https://github.com/") {
+		if _, err := exec.LookPath("gh"); err == nil {
+			// gh can act as the credential helper; refreshing it also renews SSO authorization
+			issue.ReauthCommand = []string{"sh", "-c", "gh auth refresh && gh auth setup-git"}
+		}
+	} else if !strings.HasPrefix(url, "http") {
+		issue.Message = "SSH key for origin was rejected; load it with ssh-add"
+		issue.ReauthCommand = []string{"ssh-add"}
+	}
+	return issue
+}
@@ -0,0 +1,25 @@
+package git
+
+import "testing"
+
+func TestIsAuthFailure(t *testing.T) {
+	tests := []struct {
+		output   string
+		expected bool
+	}{
+		{"remote: Invalid username or password.\nfatal: Authentication failed for 'https://github.com/o/r.git/'", true},
+		{"fatal: could not read Username for 'https://github.com': terminal prompts disabled", true},
+		{"git@github.com: Permission denied (publickey).", true},
+		{"remote: The 'acme' organization has enabled or enforced SAML SSO.", true},
+		{"X Failed to log in to github.com account octo (default)\n- The token in default is invalid.", true},
+		{"You are not logged into any GitHub hosts. To log in, run: gh auth login", true},
+		{"fatal: unable to access 'https://github.com/o/r.git/': Could not resolve host: github.com", false},
+		{"ssh: connect to host github.com port 22: Connection timed out", false},
+		{"", false},
+	}
+	for _, tt := range tests {
+		if got := isAuthFailure(tt.output); got != tt.expected {
+			t.Errorf("isAuthFailure(%q) = %v, want %v", tt.output, got, tt.expected)
+		}
+	}
+}
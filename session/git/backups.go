@@ -0,0 +1,185 @@
+package git
+
+import (
+	"claude-squad/config"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// backupInfix separates a branch's name from the creation time in the names of its backups.
+const backupInfix = "-backup-"
+
+// Backup is a backup branch made of an instance's branch before it was updated with main or
+// reset to origin.
+type Backup struct {
+	// Name is the backup branch's name.
+	Name string
+	// CreatedAt is when the backup was made, from its name.
+	CreatedAt time.Time
+	// Commit is the backed up commit's short hash, and Subject its subject line.
+	Commit  string
+	Subject string
+	// Local and Remote tell whether the backup exists as a local branch and on origin.
+	Local  bool
+	Remote bool
+}
+
+// backupTime returns when name, a backup branch of branch, was created, and false if name isn't
+// one of branch's backups.
+func backupTime(branch, name string) (time.Time, bool) {
+	suffix, ok := strings.CutPrefix(name, branch+backupInfix)
+	if !ok {
+		return time.Time{}, false
+	}
+	nanos, err := strconv.ParseInt(suffix, 10, 64)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return time.Unix(0, nanos), true
+}
+
+// ListBackups returns the branch's backup branches, local and on origin, newest first.
+func (g *GitWorktree) ListBackups() ([]Backup, error) {
+	pattern := g.branchName + backupInfix + "*"
+	output, err := g.runGitCommand(g.repoPath, "for-each-ref", "--format=%(refname)%09%(objectname:short)%09%(subject)",
+		"refs/heads/"+pattern, "refs/remotes/origin/"+pattern)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list backup branches: %w", err)
+	}
+
+	byName := make(map[string]*Backup)
+	var backups []*Backup
+	for _, line := range strings.Split(strings.TrimSpace(output), "\n") {
+		fields := strings.SplitN(line, "\t", 3)
+		if len(fields) < 2 {
+			continue
+		}
+		name, local := strings.CutPrefix(fields[0], "refs/heads/")
+		if !local {
+			name = strings.TrimPrefix(fields[0], "refs/remotes/origin/")
+		}
+		createdAt, ok := backupTime(g.branchName, name)
+		if !ok {
+			continue
+		}
+		backup := byName[name]
+		if backup == nil {
+			backup = &Backup{Name: name, CreatedAt: createdAt, Commit: fields[1]}
+			if len(fields) == 3 {
+				backup.Subject = fields[2]
+			}
+			byName[name] = backup
+			backups = append(backups, backup)
+		}
+		if local {
+			backup.Local = true
+		} else {
+			backup.Remote = true
+		}
+	}
+
+	sort.Slice(backups, func(a, b int) bool {
+		return backups[a].CreatedAt.After(backups[b].CreatedAt)
+	})
+	result := make([]Backup, len(backups))
+	for n, backup := range backups {
+		result[n] = *backup
+	}
+	return result, nil
+}
+
+// backupsToPrune returns the backups, newest first, that retention doesn't keep at now: those
+// beyond the newest KeepLast and those older than MaxAgeDays. keep is never pruned.
+func backupsToPrune(backups []Backup, retention config.BackupBranchRetention, keep string, now time.Time) []Backup {
+	maxAge := time.Duration(retention.MaxAgeDays) * 24 * time.Hour
+	var pruned []Backup
+	for n, backup := range backups {
+		if backup.Name == keep {
+			continue
+		}
+		if (retention.KeepLast > 0 && n >= retention.KeepLast) || (maxAge > 0 && now.Sub(backup.CreatedAt) > maxAge) {
+			pruned = append(pruned, backup)
+		}
+	}
+	return pruned
+}
+
+// PruneBackups deletes the branch's backups that retention doesn't keep, except keep, and
+// returns their names.
+func (g *GitWorktree) PruneBackups(retention config.BackupBranchRetention, keep string) ([]string, error) {
+	backups, err := g.ListBackups()
+	if err != nil {
+		return nil, err
+	}
+	pruned := backupsToPrune(backups, retention, keep, time.Now())
+	if err := g.deleteBackups(pruned); err != nil {
+		return nil, err
+	}
+	names := make([]string, len(pruned))
+	for n, backup := range pruned {
+		names[n] = backup.Name
+	}
+	return names, nil
+}
+
+// DeleteBackup deletes a backup branch, locally and on origin.
+func (g *GitWorktree) DeleteBackup(backup Backup) error {
+	return g.deleteBackups([]Backup{backup})
+}
+
+// deleteBackups deletes backup branches wherever they exist, with one git command for the local
+// branches and one push for origin's.
+func (g *GitWorktree) deleteBackups(backups []Backup) error {
+	var local, remote []string
+	for _, backup := range backups {
+		if backup.Local {
+			local = append(local, backup.Name)
+		}
+		if backup.Remote {
+			remote = append(remote, backup.Name)
+		}
+	}
+
+	var errs []error
+	if len(local) > 0 {
+		if _, err := g.runGitCommand(g.repoPath, append([]string{"branch", "-D"}, local...)...); err != nil {
+			errs = append(errs, fmt.Errorf("failed to delete local backup branches: %w", err))
+		}
+	}
+	if len(remote) > 0 && g.HasRemote() {
+		args := append([]string{"push", "--no-verify", "--delete", "origin"}, remote...)
+		if _, err := g.runGitCommand(g.repoPath, args...); err != nil {
+			errs = append(errs, fmt.Errorf("failed to delete backup branches on origin: %w", err))
+		}
+	}
+	return g.combineErrors(errs)
+}
+
+// RestoreBackup resets the branch to a backup, backing up its current commit first so the restore
+// can be undone. It returns the name of that backup. Uncommitted changes must be committed or
+// discarded first, since the hard reset would lose them.
+func (g *GitWorktree) RestoreBackup(backup Backup) (string, error) {
+	dirty, err := g.IsDirty()
+	if err != nil {
+		return "", fmt.Errorf("failed to check for uncommitted changes: %w", err)
+	}
+	if dirty {
+		return "", fmt.Errorf("commit or discard the uncommitted changes before restoring a backup")
+	}
+
+	current, _, err := g.ensureBackupBranch()
+	if err != nil {
+		return "", err
+	}
+	ref := backup.Name
+	if !backup.Local {
+		ref = "origin/" + backup.Name
+	}
+	if _, err := g.runGitCommand(g.worktreePath, "reset", "--hard", ref); err != nil {
+		return "", fmt.Errorf("failed to restore backup %s. Backup branch created: %s. Error: %w", backup.Name, current, err)
+	}
+	return current, nil
+}
@@ -0,0 +1,161 @@
+package git
+
+import (
+	"claude-squad/config"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestBackupsToPrune(t *testing.T) {
+	now := time.Now()
+	backup := func(daysAgo int) Backup {
+		createdAt := now.Add(-time.Duration(daysAgo) * 24 * time.Hour)
+		return Backup{Name: fmt.Sprintf("feature%s%d", backupInfix, createdAt.UnixNano()), CreatedAt: createdAt}
+	}
+	backups := []Backup{backup(1), backup(2), backup(10), backup(40), backup(50)}
+	names := func(backups []Backup) []string {
+		var names []string
+		for _, backup := range backups {
+			names = append(names, backup.Name)
+		}
+		return names
+	}
+
+	tests := []struct {
+		name      string
+		retention config.BackupBranchRetention
+		keep      string
+		want      []Backup
+	}{
+		{"keep last", config.BackupBranchRetention{KeepLast: 2}, "", backups[2:]},
+		{"max age", config.BackupBranchRetention{MaxAgeDays: 30}, "", backups[3:]},
+		{"both", config.BackupBranchRetention{KeepLast: 4, MaxAgeDays: 5}, "", backups[2:]},
+		{"kept backup survives", config.BackupBranchRetention{KeepLast: 1}, backups[3].Name, []Backup{backups[1], backups[2], backups[4]}},
+		{"disabled", config.BackupBranchRetention{}, "", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := backupsToPrune(backups, tt.retention, tt.keep, now)
+			if !reflect.DeepEqual(names(got), names(tt.want)) {
+				t.Errorf("backupsToPrune() = %v, want %v", names(got), names(tt.want))
+			}
+		})
+	}
+
+	if _, ok := backupTime("feature", "feature-backup-notatime"); ok {
+		t.Error("backupTime() accepted a name without a timestamp")
+	}
+	if _, ok := backupTime("feature", "feature-two"+backupInfix+"1"); ok {
+		t.Error("backupTime() accepted another branch's backup")
+	}
+}
+
+// initFeatureWorktree creates a repository in dir, pushed to a bare origin next to it, with a
+// worktree on a new feature branch from main. git runs git with a test identity and returns its
+// trimmed output, failing the test if it fails.
+func initFeatureWorktree(t *testing.T, dir string) (repo string, g *GitWorktree, git func(args ...string) string) {
+	t.Helper()
+	for _, name := range []string{"GIT_AUTHOR_NAME", "GIT_COMMITTER_NAME"} {
+		t.Setenv(name, "test")
+	}
+	for _, name := range []string{"GIT_AUTHOR_EMAIL", "GIT_COMMITTER_EMAIL"} {
+		t.Setenv(name, "test@example.com")
+	}
+	git = func(args ...string) string {
+		t.Helper()
+		output, err := exec.Command("git", args...).CombinedOutput()
+		if err != nil {
+			t.Fatalf("git %s: %v\n%s", strings.Join(args, " "), err, output)
+		}
+		return strings.TrimSpace(string(output))
+	}
+	origin := filepath.Join(dir, "origin.git")
+	repo = filepath.Join(dir, "repo")
+	git("init", "-q", "--bare", "-b", "main", origin)
+	initRepo(t, repo)
+	git("-C", repo, "remote", "add", "origin", origin)
+	git("-C", repo, "push", "-q", "origin", "main")
+
+	g, _, err := NewGitWorktreeForBranchInDir(repo, "feature", "main", filepath.Join(dir, "worktrees"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	g.branchName = "feature"
+	git("-C", repo, "branch", "feature")
+	if err := g.SetupFromExistingBranch(); err != nil {
+		t.Fatal(err)
+	}
+	return repo, g, git
+}
+
+func TestBackupBranches(t *testing.T) {
+	dir := t.TempDir()
+	repo, g, git := initFeatureWorktree(t, dir)
+	origin := filepath.Join(dir, "origin.git")
+
+	// Backups of three commits, the oldest only on origin and the newest only local
+	var created []string
+	for n, daysAgo := range []int{60, 2, 1} {
+		if err := os.WriteFile(filepath.Join(g.GetWorktreePath(), "file.txt"), []byte(fmt.Sprint(n)), 0644); err != nil {
+			t.Fatal(err)
+		}
+		git("-C", g.GetWorktreePath(), "add", ".")
+		git("-C", g.GetWorktreePath(), "commit", "-q", "-m", fmt.Sprintf("commit %d", n))
+		name := fmt.Sprintf("feature%s%d", backupInfix, time.Now().Add(-time.Duration(daysAgo)*24*time.Hour).UnixNano())
+		git("-C", repo, "branch", name, "feature")
+		if n < 2 {
+			git("-C", repo, "push", "-q", "origin", name)
+		}
+		if n == 0 {
+			git("-C", repo, "branch", "-D", name)
+		}
+		created = append(created, name)
+	}
+
+	backups, err := g.ListBackups()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(backups) != 3 || backups[0].Name != created[2] || backups[2].Name != created[0] {
+		t.Fatalf("ListBackups() = %+v", backups)
+	}
+	if !backups[0].Local || backups[0].Remote || !backups[1].Local || !backups[1].Remote || backups[2].Local || !backups[2].Remote {
+		t.Errorf("backup locations = %+v", backups)
+	}
+	if backups[2].Subject != "commit 0" {
+		t.Errorf("oldest backup subject = %q", backups[2].Subject)
+	}
+
+	// Restoring the oldest backup backs up the current commit first
+	current, err := g.RestoreBackup(backups[2])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if git("-C", g.GetWorktreePath(), "log", "-1", "--format=%s") != "commit 0" {
+		t.Error("RestoreBackup() didn't reset the branch to the backup")
+	}
+	// The newest backup is only local, so the commit isn't known to be backed up
+	if _, ok := backupTime("feature", current); !ok || current == created[2] {
+		t.Errorf("RestoreBackup() backed up the current commit as %q", current)
+	}
+
+	pruned, err := g.PruneBackups(config.BackupBranchRetention{KeepLast: 1, MaxAgeDays: 30}, created[1])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{created[2], created[0]}; !reflect.DeepEqual(pruned, want) {
+		t.Errorf("PruneBackups() = %v, want %v", pruned, want)
+	}
+	if git("-C", origin, "branch", "--list", created[0]) != "" {
+		t.Error("pruned backup is still on origin")
+	}
+	if backups, _ := g.ListBackups(); len(backups) != 2 {
+		t.Errorf("backups after pruning = %+v", backups)
+	}
+}
@@ -0,0 +1,107 @@
+package git
+
+import (
+	"fmt"
+	"strings"
+)
+
+// RequestedBranch returns the branch the worktree was created for when that branch was already
+// checked out in another worktree, so a suffixed copy of it was created instead. It's empty if
+// the worktree is on the branch it was created for.
+func (g *GitWorktree) RequestedBranch() string {
+	return g.requestedBranch
+}
+
+// PushBranch returns the remote branch pushes go to, which is the local branch's own name unless
+// it was retargeted with PushToRequestedBranch.
+func (g *GitWorktree) PushBranch() string {
+	if g.pushBranch != "" {
+		return g.pushBranch
+	}
+	return g.branchName
+}
+
+// SetRenamedBranch restores the requested branch of a renamed worktree and the remote branch its
+// pushes were retargeted to, as loaded from storage.
+func (g *GitWorktree) SetRenamedBranch(requested, push string) {
+	g.requestedBranch = requested
+	g.pushBranch = push
+}
+
+// pushRefspec returns the refspec pushing the branch to its remote branch.
+func (g *GitWorktree) pushRefspec() string {
+	if g.pushBranch == "" || g.pushBranch == g.branchName {
+		return g.branchName
+	}
+	return g.branchName + ":" + g.pushBranch
+}
+
+// RenameToRequestedBranch renames a suffixed branch back to the branch it was created for. That
+// branch must no longer be checked out in another worktree, and any commits on it must already be
+// on this one, since it's replaced.
+func (g *GitWorktree) RenameToRequestedBranch() error {
+	requested := g.requestedBranch
+	if requested == "" {
+		return fmt.Errorf("branch %s was not renamed", g.branchName)
+	}
+
+	path, err := g.checkedOutAt(requested)
+	if err != nil {
+		return err
+	}
+	if path != "" {
+		return fmt.Errorf("branch %s is still checked out in %s", requested, path)
+	}
+
+	if _, err := g.runGitCommand(g.repoPath, "rev-parse", "--verify", "refs/heads/"+requested); err == nil {
+		if _, err := g.runGitCommand(g.repoPath, "merge-base", "--is-ancestor", requested, g.branchName); err != nil {
+			return fmt.Errorf("branch %s has commits that %s doesn't; merge them first", requested, g.branchName)
+		}
+		if _, err := g.runGitCommand(g.repoPath, "branch", "-D", requested); err != nil {
+			return fmt.Errorf("failed to delete branch %s: %w", requested, err)
+		}
+	}
+
+	if _, err := g.runGitCommand(g.repoPath, "branch", "-m", g.branchName, requested); err != nil {
+		return fmt.Errorf("failed to rename branch %s to %s: %w", g.branchName, requested, err)
+	}
+	g.branchName = requested
+	g.requestedBranch = ""
+	g.pushBranch = ""
+	return nil
+}
+
+// PushToRequestedBranch keeps the suffixed branch but makes it track and push to the remote
+// branch it was created for.
+func (g *GitWorktree) PushToRequestedBranch() error {
+	requested := g.requestedBranch
+	if requested == "" {
+		return fmt.Errorf("branch %s was not renamed", g.branchName)
+	}
+	section := "branch." + g.branchName
+	if _, err := g.runGitCommand(g.repoPath, "config", section+".remote", "origin"); err != nil {
+		return fmt.Errorf("failed to set the remote of branch %s: %w", g.branchName, err)
+	}
+	if _, err := g.runGitCommand(g.repoPath, "config", section+".merge", "refs/heads/"+requested); err != nil {
+		return fmt.Errorf("failed to set the upstream of branch %s: %w", g.branchName, err)
+	}
+	g.pushBranch = requested
+	return nil
+}
+
+// checkedOutAt returns the path of the worktree branch is checked out in, or "" if it isn't.
+func (g *GitWorktree) checkedOutAt(branch string) (string, error) {
+	output, err := g.runGitCommand(g.repoPath, "worktree", "list", "--porcelain")
+	if err != nil {
+		return "", fmt.Errorf("failed to list worktrees: %w", err)
+	}
+	path := ""
+	for _, line := range strings.Split(output, "\n") {
+		if p, ok := strings.CutPrefix(line, "worktree "); ok {
+			path = p
+		} else if strings.TrimSpace(line) == "branch refs/heads/"+branch {
+			return path, nil
+		}
+	}
+	return "", nil
+}
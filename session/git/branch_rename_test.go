@@ -0,0 +1,63 @@
+package git
+
+import (
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRenamedBranch(t *testing.T) {
+	dir := t.TempDir()
+	repo := filepath.Join(dir, "repo")
+	other := filepath.Join(dir, "other")
+	initRepo(t, repo)
+	git := func(args ...string) string {
+		t.Helper()
+		output, err := exec.Command("git", append([]string{"-C", repo}, args...)...).CombinedOutput()
+		if err != nil {
+			t.Fatalf("git %s: %v\n%s", strings.Join(args, " "), err, output)
+		}
+		return strings.TrimSpace(string(output))
+	}
+	git("worktree", "add", "-q", "-b", "feature", other)
+
+	g, _, err := NewGitWorktreeForBranchInDir(repo, "session", "feature", filepath.Join(dir, "worktrees"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := g.Setup(); err != nil {
+		t.Fatal(err)
+	}
+	suffixed := g.GetBranchName()
+	if g.RequestedBranch() != "feature" || !strings.HasPrefix(suffixed, "feature-worktree-") {
+		t.Fatalf("branch %q requested as %q, want a suffixed copy of feature", suffixed, g.RequestedBranch())
+	}
+
+	// The requested branch can't be taken back while another worktree has it
+	if err := g.RenameToRequestedBranch(); err == nil || !strings.Contains(err.Error(), other) {
+		t.Errorf("RenameToRequestedBranch() = %v, want it to name the worktree holding feature", err)
+	}
+
+	if err := g.PushToRequestedBranch(); err != nil {
+		t.Fatal(err)
+	}
+	if merge := git("config", "branch."+suffixed+".merge"); merge != "refs/heads/feature" {
+		t.Errorf("upstream = %q, want refs/heads/feature", merge)
+	}
+	if refspec := g.pushRefspec(); refspec != suffixed+":feature" {
+		t.Errorf("pushRefspec() = %q", refspec)
+	}
+
+	git("worktree", "remove", other)
+	commitEmpty(t, g.GetWorktreePath(), "work")
+	if err := g.RenameToRequestedBranch(); err != nil {
+		t.Fatal(err)
+	}
+	if g.GetBranchName() != "feature" || g.RequestedBranch() != "" || g.PushBranch() != "feature" {
+		t.Errorf("after renaming back: branch %q, requested %q, push %q", g.GetBranchName(), g.RequestedBranch(), g.PushBranch())
+	}
+	if subject := git("log", "-1", "--format=%s", "feature"); subject != "work" {
+		t.Errorf("feature is at %q, want the commit made on the suffixed branch", subject)
+	}
+}
@@ -14,6 +14,7 @@ type BranchInfo struct {
 	CommitHash    string
 	CommitMessage string
 	IsRemote      bool
+	IsTag         bool
 }
 
 // ListRemoteBranchesFromRepo returns a list of remote branches sorted by most recent commit from a given repo path
@@ -30,6 +31,68 @@ func ListRemoteBranchesFromRepo(repoPath string) ([]BranchInfo, error) {
 	return g.ListRemoteBranches()
 }
 
+// ListTagsFromRepo returns the tags of a repo sorted by most recent first, with the commit each
+// one points to.
+func ListTagsFromRepo(repoPath string) ([]BranchInfo, error) {
+	gitRoot, err := findGitRepoRoot(repoPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find git repository: %w", err)
+	}
+
+	g := &GitWorktree{repoPath: gitRoot}
+	// Annotated tags point to a tag object; *objectname is the commit it tags
+	output, err := g.runGitCommand(gitRoot, "for-each-ref", "--sort=-creatordate",
+		"--format=%(refname:short)|%(creatordate:iso8601)|%(if)%(*objectname)%(then)%(*objectname:short)%(else)%(objectname:short)%(end)|%(subject)",
+		"refs/tags")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tags: %w", err)
+	}
+
+	tags := parseRefList(output)
+	for i := range tags {
+		tags[i].IsTag = true
+	}
+	return tags, nil
+}
+
+// ListImportableBranchesFromRepo returns the local branches of a repo that can be imported as
+// paused instances: every local branch except the main branch and branches checked out in a worktree.
+func ListImportableBranchesFromRepo(repoPath string) ([]BranchInfo, error) {
+	gitRoot, err := findGitRepoRoot(repoPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find git repository: %w", err)
+	}
+
+	g := &GitWorktree{repoPath: gitRoot, worktreePath: gitRoot}
+	branches, err := g.ListLocalBranches()
+	if err != nil {
+		return nil, err
+	}
+
+	checkedOut := map[string]bool{g.getMainBranch(): true}
+	// A branch being rebased or bisected in the main checkout can't be checked out elsewhere
+	if state, err := GetRepoState(gitRoot); err == nil && state.OperationBranch != "" {
+		checkedOut[state.OperationBranch] = true
+	}
+	output, err := g.runGitCommand(gitRoot, "worktree", "list", "--porcelain")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list worktrees: %w", err)
+	}
+	for _, line := range strings.Split(output, "\n") {
+		if ref, ok := strings.CutPrefix(line, "branch refs/heads/"); ok {
+			checkedOut[strings.TrimSpace(ref)] = true
+		}
+	}
+
+	importable := make([]BranchInfo, 0, len(branches))
+	for _, branch := range branches {
+		if !checkedOut[branch.Name] {
+			importable = append(importable, branch)
+		}
+	}
+	return importable, nil
+}
+
 // ListRemoteBranches returns a list of remote branches sorted by most recent commit
 func (g *GitWorktree) ListRemoteBranches() ([]BranchInfo, error) {
 	// Fetch latest remote branches
@@ -95,8 +158,14 @@ func (g *GitWorktree) ListLocalBranches() ([]BranchInfo, error) {
 		return nil, fmt.Errorf("failed to list local branches: %w", err)
 	}
 
-	lines := strings.Split(strings.TrimSpace(string(output)), "\n")
-	branches := make([]BranchInfo, 0, len(lines))
+	return parseRefList(output), nil
+}
+
+// parseRefList parses for-each-ref output in the name|date|hash|subject format, sorted by
+// date, most recent first.
+func parseRefList(output string) []BranchInfo {
+	lines := strings.Split(strings.TrimSpace(output), "\n")
+	refs := make([]BranchInfo, 0, len(lines))
 
 	for _, line := range lines {
 		if line == "" {
@@ -114,19 +183,18 @@ func (g *GitWorktree) ListLocalBranches() ([]BranchInfo, error) {
 			continue
 		}
 
-		branches = append(branches, BranchInfo{
+		refs = append(refs, BranchInfo{
 			Name:          parts[0],
 			CommitTime:    commitTime,
 			CommitHash:    parts[2],
 			CommitMessage: parts[3],
-			IsRemote:      false,
 		})
 	}
 
 	// Sort by commit time (most recent first)
-	sort.Slice(branches, func(i, j int) bool {
-		return branches[i].CommitTime.After(branches[j].CommitTime)
+	sort.Slice(refs, func(i, j int) bool {
+		return refs[i].CommitTime.After(refs[j].CommitTime)
 	})
 
-	return branches, nil
+	return refs
 }
@@ -0,0 +1,42 @@
+package git
+
+import (
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestListTagsFromRepo(t *testing.T) {
+	repo := filepath.Join(t.TempDir(), "repo")
+	initRepo(t, repo)
+	for _, args := range [][]string{
+		{"tag", "v1.0.0"},
+		{"-c", "user.name=test", "-c", "user.email=test@example.com", "tag", "-a", "v1.1.0", "-m", "Release 1.1"},
+	} {
+		if output, err := exec.Command("git", append([]string{"-C", repo}, args...)...).CombinedOutput(); err != nil {
+			t.Fatalf("git %s: %v\n%s", strings.Join(args, " "), err, output)
+		}
+	}
+	head, err := exec.Command("git", "-C", repo, "rev-parse", "--short", "HEAD").Output()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tags, err := ListTagsFromRepo(repo)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tags) != 2 {
+		t.Fatalf("ListTagsFromRepo() returned %d tags, want 2", len(tags))
+	}
+	for _, tag := range tags {
+		if !tag.IsTag {
+			t.Errorf("tag %s has IsTag = false", tag.Name)
+		}
+		// Annotated tags report the commit they tag, not the tag object
+		if tag.CommitHash != strings.TrimSpace(string(head)) {
+			t.Errorf("tag %s commit = %s, want %s", tag.Name, tag.CommitHash, head)
+		}
+	}
+}
@@ -0,0 +1,83 @@
+package git
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// hunkHeaderRe matches the old-file range of a unified diff hunk header, e.g. "@@ -12,3 +12,5 @@".
+var hunkHeaderRe = regexp.MustCompile(`^@@ -(\d+)(?:,(\d+))? \+\d+(?:,\d+)? @@`)
+
+// LineRange is an inclusive range of line numbers.
+type LineRange struct {
+	Start int
+	End   int
+}
+
+// ChangedLines maps file paths to the line ranges, numbered as in the old version of the file,
+// that a diff modified, removed or inserted lines next to.
+type ChangedLines map[string][]LineRange
+
+// Touches returns true if a change lies within window lines of the given line of path. A line of
+// 0 matches any change to the file.
+func (c ChangedLines) Touches(path string, line int, window int) bool {
+	ranges, ok := c[path]
+	if !ok {
+		return false
+	}
+	if line <= 0 {
+		return true
+	}
+	for _, r := range ranges {
+		if line >= r.Start-window && line <= r.End+window {
+			return true
+		}
+	}
+	return false
+}
+
+// parseChangedLines extracts the changed old-side line ranges of each file from a diff generated
+// with zero context lines. New files have no old lines and are left out.
+func parseChangedLines(diff string) ChangedLines {
+	changed := make(ChangedLines)
+	path := ""
+	for _, line := range strings.Split(diff, "\n") {
+		switch {
+		case strings.HasPrefix(line, "--- "):
+			path = strings.TrimPrefix(strings.TrimPrefix(line, "--- "), "a/")
+			if path == "/dev/null" {
+				path = ""
+			}
+		case strings.HasPrefix(line, "@@ ") && path != "":
+			match := hunkHeaderRe.FindStringSubmatch(line)
+			if match == nil {
+				continue
+			}
+			start, _ := strconv.Atoi(match[1])
+			count := 1
+			if match[2] != "" {
+				count, _ = strconv.Atoi(match[2])
+			}
+			r := LineRange{Start: start, End: start + count - 1}
+			if count == 0 {
+				// A pure insertion after line start
+				r = LineRange{Start: start, End: start + 1}
+			}
+			changed[path] = append(changed[path], r)
+		}
+	}
+	return changed
+}
+
+// ChangedLinesSince returns the lines changed in the worktree, committed or not, since the given
+// commit.
+func (g *GitWorktree) ChangedLinesSince(commitSHA string) (ChangedLines, error) {
+	output, err := g.runGitCommand(g.worktreePath, "--no-pager", "diff", "--no-color", "--no-ext-diff",
+		"--no-renames", "-U0", "--src-prefix=a/", "--dst-prefix=b/", commitSHA)
+	if err != nil {
+		return nil, fmt.Errorf("failed to diff since %s: %w", commitSHA, err)
+	}
+	return parseChangedLines(output), nil
+}
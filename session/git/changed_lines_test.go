@@ -0,0 +1,57 @@
+package git
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseChangedLines(t *testing.T) {
+	diff := `diff --git a/main.go b/main.go
+index 1111111..2222222 100644
+--- a/main.go
++++ b/main.go
+@@ -10,2 +10,3 @@ func main() {
+-	old()
+-	older()
++	new()
++	newer()
++	newest()
+@@ -40,0 +42 @@ func helper() {
++	// added
+@@ -55 +57,0 @@ func other() {
+-	removed()
+diff --git a/added.go b/added.go
+new file mode 100644
+--- /dev/null
++++ b/added.go
+@@ -0,0 +1 @@
++package main
+`
+	changed := parseChangedLines(diff)
+	expected := ChangedLines{
+		"main.go": {{Start: 10, End: 11}, {Start: 40, End: 41}, {Start: 55, End: 55}},
+	}
+	if !reflect.DeepEqual(changed, expected) {
+		t.Fatalf("parseChangedLines() = %v, want %v", changed, expected)
+	}
+
+	tests := []struct {
+		path     string
+		line     int
+		window   int
+		expected bool
+	}{
+		{"main.go", 11, 0, true},
+		{"main.go", 14, 3, true},
+		{"main.go", 20, 3, false},
+		{"main.go", 41, 0, true},
+		{"main.go", 0, 0, true},
+		{"added.go", 1, 3, false},
+		{"other.go", 0, 0, false},
+	}
+	for _, tt := range tests {
+		if got := changed.Touches(tt.path, tt.line, tt.window); got != tt.expected {
+			t.Errorf("Touches(%q, %d, %d) = %v, want %v", tt.path, tt.line, tt.window, got, tt.expected)
+		}
+	}
+}
@@ -0,0 +1,148 @@
+package git
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os/exec"
+	"sort"
+	"strings"
+)
+
+// CICheck is one CI check run or commit status reported for a commit.
+type CICheck struct {
+	Name string
+	// State is one of the CIState constants other than CIStateNone
+	State string
+	// URL links to the check's details, if the CI reports one
+	URL string
+	// Summary is the short description the CI reported with the result, if any
+	Summary string
+}
+
+// CIChecks returns the CI checks reported for the branch's commit on origin, sorted by name,
+// or none if the branch isn't pushed.
+func (g *GitWorktree) CIChecks() ([]CICheck, error) {
+	sha, err := g.runGitCommand(g.worktreePath, "rev-parse", "--verify", "--quiet", "refs/remotes/origin/"+g.PushBranch())
+	if err != nil {
+		// Branches that were never pushed have no CI runs
+		return nil, nil
+	}
+	checks, err := ForgeFor(g.worktreePath).CIChecks(g.worktreePath, strings.TrimSpace(sha))
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(checks, func(i, j int) bool { return checks[i].Name < checks[j].Name })
+	return checks, nil
+}
+
+// ghAPI runs gh api in dir, where gh fills in the {owner} and {repo} placeholders of API paths
+// with the repository of the checkout's remote.
+func ghAPI(dir string, args ...string) ([]byte, error) {
+	cmd := exec.Command("gh", append([]string{"api"}, args...)...)
+	cmd.Dir = dir
+	output, err := cmd.Output()
+	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			return nil, fmt.Errorf("gh api failed (output: %s): %w", strings.TrimSpace(string(exitErr.Stderr)), err)
+		}
+		return nil, fmt.Errorf("gh api failed: %w", err)
+	}
+	return output, nil
+}
+
+// githubCIChecks returns the check runs and commit statuses GitHub has for the commit.
+func githubCIChecks(dir, sha string) ([]CICheck, error) {
+	checkRuns, err := ghAPI(dir, fmt.Sprintf("repos/{owner}/{repo}/commits/%s/check-runs?per_page=100", sha))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get check runs: %w", err)
+	}
+	statuses, err := ghAPI(dir, fmt.Sprintf("repos/{owner}/{repo}/commits/%s/status?per_page=100", sha))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get commit statuses: %w", err)
+	}
+	return parseGitHubChecks(checkRuns, statuses)
+}
+
+// parseGitHubChecks parses the check runs and combined commit status of a commit.
+func parseGitHubChecks(checkRuns, statuses []byte) ([]CICheck, error) {
+	var runData struct {
+		CheckRuns []struct {
+			Name       string `json:"name"`
+			Status     string `json:"status"`
+			Conclusion string `json:"conclusion"`
+			HTMLURL    string `json:"html_url"`
+			DetailsURL string `json:"details_url"`
+			Output     struct {
+				Title string `json:"title"`
+			} `json:"output"`
+		} `json:"check_runs"`
+	}
+	if err := json.Unmarshal(checkRuns, &runData); err != nil {
+		return nil, fmt.Errorf("failed to parse check runs: %w", err)
+	}
+	var statusData struct {
+		Statuses []struct {
+			Context     string `json:"context"`
+			State       string `json:"state"`
+			TargetURL   string `json:"target_url"`
+			Description string `json:"description"`
+		} `json:"statuses"`
+	}
+	if err := json.Unmarshal(statuses, &statusData); err != nil {
+		return nil, fmt.Errorf("failed to parse commit statuses: %w", err)
+	}
+
+	var checks []CICheck
+	for _, run := range runData.CheckRuns {
+		check := CICheck{Name: run.Name, State: CIStatePending, URL: run.HTMLURL, Summary: run.Output.Title}
+		if run.Status == "completed" {
+			check.State = githubCIState(run.Conclusion)
+		}
+		if check.URL == "" {
+			check.URL = run.DetailsURL
+		}
+		checks = append(checks, check)
+	}
+	for _, status := range statusData.Statuses {
+		checks = append(checks, CICheck{Name: status.Context, State: githubCIState(status.State), URL: status.TargetURL,
+			Summary: status.Description})
+	}
+	return checks, nil
+}
+
+// gitlabCIChecks returns the statuses of the jobs GitLab ran for the commit.
+func gitlabCIChecks(dir, sha string) ([]CICheck, error) {
+	statuses, err := glabAPI(dir, fmt.Sprintf("projects/:id/repository/commits/%s/statuses?per_page=100", sha))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get commit statuses: %w", err)
+	}
+	return parseGitLabChecks(statuses)
+}
+
+// parseGitLabChecks parses the job statuses of a commit.
+func parseGitLabChecks(statuses []byte) ([]CICheck, error) {
+	var statusData []struct {
+		Name        string `json:"name"`
+		Status      string `json:"status"`
+		TargetURL   string `json:"target_url"`
+		Description string `json:"description"`
+	}
+	if err := json.Unmarshal(statuses, &statusData); err != nil {
+		return nil, fmt.Errorf("failed to parse commit statuses: %w", err)
+	}
+	checks := make([]CICheck, 0, len(statusData))
+	for _, status := range statusData {
+		check := CICheck{Name: status.Name, State: CIStatePending, URL: status.TargetURL, Summary: status.Description}
+		switch status.Status {
+		// Manual jobs wait for someone to start them, so they don't hold the others up
+		case "success", "skipped", "manual":
+			check.State = CIStateSuccess
+		case "failed", "canceled":
+			check.State = CIStateFailure
+		}
+		checks = append(checks, check)
+	}
+	return checks, nil
+}
@@ -0,0 +1,52 @@
+package git
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseGitHubChecks(t *testing.T) {
+	checkRuns := `{"total_count":3,"check_runs":[
+		{"name":"build","status":"completed","conclusion":"success","html_url":"https://github.com/o/r/runs/1"},
+		{"name":"lint","status":"in_progress","conclusion":null,"html_url":"","details_url":"https://ci.example.com/2"},
+		{"name":"test","status":"completed","conclusion":"failure","html_url":"https://github.com/o/r/runs/3",
+		 "output":{"title":"2 tests failed"}}]}`
+	statuses := `{"state":"pending","statuses":[{"context":"deploy","state":"pending","target_url":"https://deploy.example.com"}]}`
+
+	checks, err := parseGitHubChecks([]byte(checkRuns), []byte(statuses))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []CICheck{
+		{Name: "build", State: CIStateSuccess, URL: "https://github.com/o/r/runs/1"},
+		{Name: "lint", State: CIStatePending, URL: "https://ci.example.com/2"},
+		{Name: "test", State: CIStateFailure, URL: "https://github.com/o/r/runs/3", Summary: "2 tests failed"},
+		{Name: "deploy", State: CIStatePending, URL: "https://deploy.example.com"},
+	}
+	if !reflect.DeepEqual(checks, want) {
+		t.Errorf("parseGitHubChecks() = %+v, want %+v", checks, want)
+	}
+	if state := RollupCIState([]string{checks[0].State, checks[1].State}); state != CIStatePending {
+		t.Errorf("RollupCIState() = %q, want pending", state)
+	}
+}
+
+func TestParseGitLabChecks(t *testing.T) {
+	checks, err := parseGitLabChecks([]byte(`[
+		{"name":"build","status":"success","target_url":"https://gitlab.com/o/r/-/jobs/1"},
+		{"name":"test","status":"failed","target_url":"https://gitlab.com/o/r/-/jobs/2","description":"exit code 1"},
+		{"name":"deploy","status":"manual"},
+		{"name":"e2e","status":"running"}]`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []CICheck{
+		{Name: "build", State: CIStateSuccess, URL: "https://gitlab.com/o/r/-/jobs/1"},
+		{Name: "test", State: CIStateFailure, URL: "https://gitlab.com/o/r/-/jobs/2", Summary: "exit code 1"},
+		{Name: "deploy", State: CIStateSuccess},
+		{Name: "e2e", State: CIStatePending},
+	}
+	if !reflect.DeepEqual(checks, want) {
+		t.Errorf("parseGitLabChecks() = %+v, want %+v", checks, want)
+	}
+}
@@ -0,0 +1,33 @@
+package git
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DayFormat is the layout of the days commits are counted by.
+const DayFormat = "2006-01-02"
+
+// CommitDays counts the commits on branch since baseCommitSHA by the local day they were made,
+// e.g. {"2025-06-02": 3}. Without a base commit nothing is counted, rather than all of history.
+func CommitDays(repoPath, branch, baseCommitSHA string) (map[string]int, error) {
+	if baseCommitSHA == "" || branch == "" {
+		return nil, nil
+	}
+	g := &GitWorktree{repoPath: repoPath}
+	output, err := g.runGitCommand(repoPath, "log", "--format=%ct", baseCommitSHA+".."+branch, "--")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list the commits of %s: %w", branch, err)
+	}
+	days := make(map[string]int)
+	for _, line := range strings.Fields(output) {
+		seconds, err := strconv.ParseInt(line, 10, 64)
+		if err != nil {
+			continue
+		}
+		days[time.Unix(seconds, 0).Format(DayFormat)]++
+	}
+	return days, nil
+}
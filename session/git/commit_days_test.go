@@ -0,0 +1,42 @@
+package git
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCommitDays(t *testing.T) {
+	repo := filepath.Join(t.TempDir(), "repo")
+	initRepo(t, repo)
+	base, err := exec.Command("git", "-C", repo, "rev-parse", "HEAD").Output()
+	if err != nil {
+		t.Fatalf("git rev-parse: %v", err)
+	}
+
+	monday := time.Date(2025, 6, 2, 12, 0, 0, 0, time.Local)
+	for _, at := range []time.Time{monday, monday.Add(time.Hour), monday.Add(24 * time.Hour)} {
+		cmd := exec.Command("git", "-C", repo, "-c", "user.name=test", "-c", "user.email=test@example.com",
+			"commit", "-q", "--allow-empty", "-m", "work")
+		cmd.Env = append(os.Environ(), "GIT_COMMITTER_DATE="+at.Format(time.RFC3339))
+		if output, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git commit: %v\n%s", err, output)
+		}
+	}
+
+	days, err := CommitDays(repo, "HEAD", strings.TrimSpace(string(base)))
+	if err != nil {
+		t.Fatalf("CommitDays: %v", err)
+	}
+	if want := map[string]int{"2025-06-02": 2, "2025-06-03": 1}; !reflect.DeepEqual(days, want) {
+		t.Errorf("CommitDays = %v, want %v", days, want)
+	}
+
+	if days, err := CommitDays(repo, "HEAD", ""); err != nil || days != nil {
+		t.Errorf("CommitDays without a base = %v, %v, want nothing", days, err)
+	}
+}
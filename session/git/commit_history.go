@@ -0,0 +1,168 @@
+package git
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// DefaultCommitHistoryDepth is how many commits are loaded at a time when browsing history.
+const DefaultCommitHistoryDepth = 20
+
+// commitHistoryDepth is the configured number of commits loaded at a time.
+var commitHistoryDepth atomic.Int64
+
+func init() {
+	commitHistoryDepth.Store(DefaultCommitHistoryDepth)
+}
+
+// SetCommitHistoryDepth sets how many commits are loaded at a time when browsing history.
+// Values below one are ignored.
+func SetCommitHistoryDepth(depth int) {
+	if depth > 0 {
+		commitHistoryDepth.Store(int64(depth))
+	}
+}
+
+// CommitInfo is a commit on the worktree's branch.
+type CommitInfo struct {
+	// Hash is the full commit hash and ShortHash its abbreviation
+	Hash      string
+	ShortHash string
+	// Subject is the first line of the commit message
+	Subject string
+}
+
+// commitHistory caches the first-parent history of HEAD, newest first, and the diffs of the
+// commits in it. Commits are loaded a page at a time as browsing reaches the end of those
+// loaded, and the cache is dropped when HEAD moves.
+type commitHistory struct {
+	mu      sync.Mutex
+	head    string
+	commits []CommitInfo
+	// complete is set once the root commit has been loaded
+	complete bool
+	// diffs holds the diffs of the commits viewed, by hash
+	diffs map[string]*DiffStats
+}
+
+// commitAt returns the commit offset commits before HEAD, loading older commits as needed.
+// The caller holds h.mu.
+func (h *commitHistory) commitAt(g *GitWorktree, offset int) (CommitInfo, error) {
+	head, err := g.runGitCommand(g.worktreePath, "rev-parse", "HEAD")
+	if err != nil {
+		return CommitInfo{}, fmt.Errorf("failed to resolve HEAD: %w", err)
+	}
+	if head = strings.TrimSpace(head); head != h.head {
+		h.head, h.commits, h.complete, h.diffs = head, nil, false, make(map[string]*DiffStats)
+	}
+
+	depth := int(commitHistoryDepth.Load())
+	for offset >= len(h.commits) && !h.complete {
+		// HEAD~n follows first parents, so the history does too
+		output, err := g.runGitCommand(g.worktreePath, "log", "--first-parent", "--format=%H%x00%h%x00%s",
+			fmt.Sprintf("--skip=%d", len(h.commits)), "-n", fmt.Sprint(depth), h.head)
+		if err != nil {
+			return CommitInfo{}, fmt.Errorf("failed to load commit history: %w", err)
+		}
+		page := parseCommitLog(output)
+		h.commits = append(h.commits, page...)
+		h.complete = len(page) < depth
+	}
+	if offset < 0 || offset >= len(h.commits) {
+		return CommitInfo{}, fmt.Errorf("no commit at HEAD~%d", offset)
+	}
+	return h.commits[offset], nil
+}
+
+// parseCommitLog parses git log output in the format "%H%x00%h%x00%s".
+func parseCommitLog(output string) []CommitInfo {
+	var commits []CommitInfo
+	for _, line := range strings.Split(output, "\n") {
+		fields := strings.SplitN(line, "\x00", 3)
+		if len(fields) != 3 {
+			continue
+		}
+		commits = append(commits, CommitInfo{Hash: fields[0], ShortHash: fields[1], Subject: fields[2]})
+	}
+	return commits
+}
+
+// historyCache returns the worktree's commit history cache, or an empty one that is discarded
+// after use for worktrees without a cache.
+func (g *GitWorktree) historyCache() *commitHistory {
+	if g.history == nil {
+		return &commitHistory{}
+	}
+	return g.history
+}
+
+// CommitAtOffset returns the commit offset commits before HEAD (0 = HEAD, 1 = HEAD~1, etc.),
+// or an error if the history is shorter.
+func (g *GitWorktree) CommitAtOffset(offset int) (CommitInfo, error) {
+	if g.worktreeMissing() {
+		return CommitInfo{}, fmt.Errorf("worktree does not exist")
+	}
+	h := g.historyCache()
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.commitAt(g, offset)
+}
+
+// GetCommitInfo returns the commit hash and message at the specified offset
+func (g *GitWorktree) GetCommitInfo(offset int) (hash string, message string, err error) {
+	commit, err := g.CommitAtOffset(offset)
+	if err != nil {
+		return "", "", err
+	}
+	return commit.ShortHash, commit.Subject, nil
+}
+
+// DiffCommitAtOffset returns the diff of a commit at the specified offset from HEAD
+// offset 0 = HEAD, offset 1 = HEAD~1, etc.
+func (g *GitWorktree) DiffCommitAtOffset(offset int) *DiffStats {
+	stats := &DiffStats{}
+
+	// Check if worktree path exists
+	if g.worktreeMissing() {
+		// Return empty stats for non-existent worktree
+		return stats
+	}
+
+	h := g.historyCache()
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	commit, err := h.commitAt(g, offset)
+	if err != nil {
+		stats.Error = err
+		return stats
+	}
+	// A commit's diff never changes, so it is only computed once
+	if cached, ok := h.diffs[commit.Hash]; ok {
+		copied := *cached
+		return &copied
+	}
+
+	content, err := g.runGitCommand(g.worktreePath, "--no-pager", "diff", commit.Hash+"^.."+commit.Hash)
+	if err != nil {
+		// The root commit has no parent, so show the commit itself
+		content, err = g.runGitCommand(g.worktreePath, "--no-pager", "show", "--format=", commit.Hash)
+		if err != nil {
+			stats.Error = err
+			return stats
+		}
+	}
+
+	for _, line := range strings.Split(content, "\n") {
+		if strings.HasPrefix(line, "+") && !strings.HasPrefix(line, "+++") {
+			stats.Added++
+		} else if strings.HasPrefix(line, "-") && !strings.HasPrefix(line, "---") {
+			stats.Removed++
+		}
+	}
+	stats.Content = content
+	copied := *stats
+	h.diffs[commit.Hash] = &copied
+	return stats
+}
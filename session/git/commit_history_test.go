@@ -0,0 +1,52 @@
+package git
+
+import (
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+func commitEmpty(t *testing.T, repo, message string) {
+	t.Helper()
+	output, err := exec.Command("git", "-C", repo, "-c", "user.name=test", "-c", "user.email=test@example.com",
+		"commit", "-q", "--allow-empty", "-m", message).CombinedOutput()
+	if err != nil {
+		t.Fatalf("git commit: %v\n%s", err, output)
+	}
+}
+
+func TestCommitAtOffset(t *testing.T) {
+	repo := filepath.Join(t.TempDir(), "repo")
+	initRepo(t, repo)
+	for n := 1; n <= 4; n++ {
+		commitEmpty(t, repo, fmt.Sprintf("commit %d", n))
+	}
+	SetCommitHistoryDepth(2)
+	defer SetCommitHistoryDepth(DefaultCommitHistoryDepth)
+	g := &GitWorktree{repoPath: repo, worktreePath: repo, history: &commitHistory{}}
+
+	if commit, err := g.CommitAtOffset(0); err != nil || commit.Subject != "commit 4" {
+		t.Fatalf("CommitAtOffset(0) = %+v, %v", commit, err)
+	}
+	if loaded := len(g.history.commits); loaded != 2 {
+		t.Errorf("loaded %d commits, want one page of 2", loaded)
+	}
+
+	// Browsing past the loaded commits loads older ones, up to the root
+	if commit, err := g.CommitAtOffset(4); err != nil || commit.Subject != "initial" {
+		t.Fatalf("CommitAtOffset(4) = %+v, %v", commit, err)
+	}
+	if _, err := g.CommitAtOffset(5); err == nil {
+		t.Error("CommitAtOffset(5) succeeded past the root commit")
+	}
+	if !g.history.complete {
+		t.Error("history not marked complete after loading the root commit")
+	}
+
+	// A new commit moves HEAD, which drops the cache
+	commitEmpty(t, repo, "commit 5")
+	if commit, err := g.CommitAtOffset(0); err != nil || commit.Subject != "commit 5" {
+		t.Fatalf("CommitAtOffset(0) after committing = %+v, %v", commit, err)
+	}
+}
@@ -0,0 +1,246 @@
+package git
+
+import (
+	"fmt"
+	"path"
+	"strconv"
+	"strings"
+)
+
+// maxNamedFiles is how many changed files a suggested commit message names before it only
+// counts them.
+const maxNamedFiles = 3
+
+// fileChange is one entry of git status: a path and its porcelain status letter, e.g. 'M' or
+// '?' for an untracked file.
+type fileChange struct {
+	path   string
+	status byte
+}
+
+// GenerateCommitMessage returns a conventional commit message, e.g. "feat(ui): add list.go",
+// summarising the changes the next commit would include: the staged changes if any are staged,
+// otherwise all changes. It returns an empty message if there is nothing to commit.
+func (g *GitWorktree) GenerateCommitMessage() (string, error) {
+	output, err := g.runGitCommand(g.worktreePath, "status", "--porcelain", "--untracked-files=all")
+	if err != nil {
+		return "", fmt.Errorf("failed to check worktree status: %w", err)
+	}
+	changes, staged := parseStatus(output)
+	if len(changes) == 0 {
+		return "", nil
+	}
+
+	args := []string{"diff", "--numstat", "HEAD"}
+	if staged {
+		args = []string{"diff", "--numstat", "--cached"}
+	}
+	numstat, err := g.runGitCommand(g.worktreePath, args...)
+	if err != nil {
+		return "", fmt.Errorf("failed to count changed lines: %w", err)
+	}
+	added, removed := parseNumstat(numstat)
+	return conventionalMessage(changes, added, removed), nil
+}
+
+// PendingChanges returns the diff of the changes the next commit would include, picked like
+// GenerateCommitMessage picks them, followed by the untracked files it would add. It returns ""
+// if there is nothing to commit.
+func (g *GitWorktree) PendingChanges() (string, error) {
+	output, err := g.runGitCommand(g.worktreePath, "status", "--porcelain", "--untracked-files=all")
+	if err != nil {
+		return "", fmt.Errorf("failed to check worktree status: %w", err)
+	}
+	changes, staged := parseStatus(output)
+	if len(changes) == 0 {
+		return "", nil
+	}
+
+	args := []string{"--no-pager", "diff", "--no-color", "HEAD"}
+	if staged {
+		args = []string{"--no-pager", "diff", "--no-color", "--cached"}
+	}
+	diff, err := g.runGitCommand(g.worktreePath, args...)
+	if err != nil {
+		return "", fmt.Errorf("failed to diff the changes: %w", err)
+	}
+	var untracked []string
+	for _, change := range changes {
+		if change.status == '?' {
+			untracked = append(untracked, change.path)
+		}
+	}
+	if len(untracked) > 0 {
+		diff += "\nNew files:\n" + strings.Join(untracked, "\n") + "\n"
+	}
+	return diff, nil
+}
+
+// BranchCommits returns the messages of the branch's commits since the base commit, oldest first.
+func (g *GitWorktree) BranchCommits() (string, error) {
+	output, err := g.runGitCommand(g.worktreePath, "log", "--reverse", "--format=%B", g.GetBaseCommitSHA()+"..HEAD")
+	if err != nil {
+		return "", fmt.Errorf("failed to list the branch's commits: %w", err)
+	}
+	return strings.TrimSpace(output), nil
+}
+
+// parseStatus parses git status --porcelain output. If any changes are staged, only those are
+// returned, along with true.
+func parseStatus(output string) ([]fileChange, bool) {
+	var all, staged []fileChange
+	for _, line := range strings.Split(output, "\n") {
+		if len(line) < 4 {
+			continue
+		}
+		// The staged status comes first, then the worktree's
+		change := fileChange{path: strings.Trim(line[3:], `"`), status: line[0]}
+		if change.status == ' ' {
+			change.status = line[1]
+		}
+		// Renames are listed as "old -> new"
+		if _, to, ok := strings.Cut(change.path, " -> "); ok {
+			change.path = strings.Trim(to, `"`)
+		}
+		all = append(all, change)
+		if line[0] != ' ' && line[0] != '?' {
+			staged = append(staged, change)
+		}
+	}
+	if len(staged) > 0 {
+		return staged, true
+	}
+	return all, false
+}
+
+// parseNumstat totals the added and removed lines in git diff --numstat output. Binary files,
+// listed as "-", count as no lines.
+func parseNumstat(output string) (added, removed int) {
+	for _, line := range strings.Split(output, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 3 {
+			continue
+		}
+		a, _ := strconv.Atoi(fields[0])
+		r, _ := strconv.Atoi(fields[1])
+		added += a
+		removed += r
+	}
+	return added, removed
+}
+
+// conventionalMessage builds a conventional commit message for the changes: a type guessed
+// from the kinds of files changed and how, a scope from the directory they share, and a
+// summary of the files.
+func conventionalMessage(changes []fileChange, added, removed int) string {
+	// The type and scope follow the source files, if any, rather than the docs and tests that
+	// accompany them
+	var sources []fileChange
+	for _, change := range changes {
+		if fileKind(change.path) == "" {
+			sources = append(sources, change)
+		}
+	}
+
+	commitType, scoped := "", changes
+	switch {
+	case len(sources) == 0:
+		commitType = fileKind(changes[0].path)
+		for _, change := range changes[1:] {
+			if fileKind(change.path) != commitType {
+				commitType = "chore"
+			}
+		}
+	case allStatus(sources, "A?") || added >= 2*removed+featureMinLines:
+		commitType, scoped = "feat", sources
+	default:
+		commitType, scoped = "fix", sources
+	}
+
+	scope := ""
+	if dir := commonDir(scoped); dir != "." && path.Base(dir) != commitType {
+		scope = "(" + path.Base(dir) + ")"
+	}
+	return commitType + scope + ": " + summarizeChanges(changes)
+}
+
+// featureMinLines is how many more lines than twice those removed a change to existing source
+// files must add to be guessed a feature rather than a fix.
+const featureMinLines = 10
+
+// choreFiles are build and tooling files changed by chores, by name.
+var choreFiles = map[string]bool{
+	"go.mod": true, "go.sum": true, "package.json": true, "package-lock.json": true, "yarn.lock": true,
+	"pnpm-lock.yaml": true, "Makefile": true, "Dockerfile": true, ".gitignore": true,
+}
+
+// fileKind returns the conventional commit type changes to a file that isn't source code have,
+// "docs", "test" or "chore", or "" for source files.
+func fileKind(file string) string {
+	name := path.Base(file)
+	switch {
+	case strings.HasSuffix(name, ".md") || strings.HasSuffix(name, ".txt") || strings.HasPrefix(file, "docs/"):
+		return "docs"
+	case strings.HasSuffix(name, "_test.go") || strings.Contains(name, ".test.") || strings.Contains(name, ".spec.") ||
+		strings.HasPrefix(file, "test/") || strings.HasPrefix(file, "tests/"):
+		return "test"
+	case choreFiles[name] || strings.HasPrefix(file, ".github/"):
+		return "chore"
+	}
+	return ""
+}
+
+// commonDir returns the deepest directory containing all the changed files, "." if it is the
+// repository root.
+func commonDir(changes []fileChange) string {
+	dir := path.Dir(changes[0].path)
+	for _, change := range changes[1:] {
+		for dir != "." && !strings.HasPrefix(change.path, dir+"/") {
+			dir = path.Dir(dir)
+		}
+	}
+	return dir
+}
+
+// summarizeChanges describes the changed files, e.g. "update app.go and README.md" or "add 5
+// files in ui/".
+func summarizeChanges(changes []fileChange) string {
+	if len(changes) == 0 {
+		return ""
+	}
+
+	verb := "update"
+	switch {
+	case allStatus(changes, "A?"):
+		verb = "add"
+	case allStatus(changes, "D"):
+		verb = "remove"
+	}
+
+	if len(changes) <= maxNamedFiles {
+		names := make([]string, len(changes))
+		for i, change := range changes {
+			names[i] = path.Base(change.path)
+		}
+		if len(names) == 1 {
+			return verb + " " + names[0]
+		}
+		return verb + " " + strings.Join(names[:len(names)-1], ", ") + " and " + names[len(names)-1]
+	}
+
+	dir := commonDir(changes)
+	if dir == "." {
+		return fmt.Sprintf("%s %d files", verb, len(changes))
+	}
+	return fmt.Sprintf("%s %d files in %s/", verb, len(changes), dir)
+}
+
+// allStatus returns true if every change has one of the status letters in codes.
+func allStatus(changes []fileChange, codes string) bool {
+	for _, change := range changes {
+		if !strings.ContainsRune(codes, rune(change.status)) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,119 @@
+package git
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSummarizeChanges(t *testing.T) {
+	tests := []struct {
+		name   string
+		status string
+		want   string
+	}{
+		{"nothing", "", ""},
+		{"one modified", " M app/app.go\n", "update app.go"},
+		{"new files", "?? a.go\nA  b.go\n", "add b.go"},
+		{"untracked only", "?? a.go\n?? docs/b.md\n", "add a.go and b.md"},
+		{"deleted", " D a.go\n D b.go\n D c.go\n", "remove a.go, b.go and c.go"},
+		{"renamed", "R  old.go -> new.go\n", "update new.go"},
+		{"many in one dir", " M ui/a.go\n M ui/b.go\n M ui/overlay/c.go\n?? ui/d.go\n", "update 4 files in ui/"},
+		{"many", " M a.go\n M ui/b.go\n M c.go\n M d.go\n", "update 4 files"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			changes, _ := parseStatus(tt.status)
+			if got := summarizeChanges(changes); got != tt.want {
+				t.Errorf("summarizeChanges(%q) = %q, want %q", tt.status, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestConventionalMessage(t *testing.T) {
+	tests := []struct {
+		name           string
+		status         string
+		added, removed int
+		want           string
+	}{
+		{"new source file", "?? ui/overlay/form.go\n M README.md\n", 80, 0, "feat(overlay): update form.go and README.md"},
+		{"small change", " M session/git/diff.go\n M session/git/diff_test.go\n", 6, 4, "fix(git): update diff.go and diff_test.go"},
+		{"large addition", " M app/app.go\n", 60, 5, "feat(app): update app.go"},
+		{"docs only", " M README.md\n M docs/usage.md\n", 10, 2, "docs: update README.md and usage.md"},
+		{"tests only", " M ui/list_test.go\n", 10, 2, "test(ui): update list_test.go"},
+		{"dependencies", " M go.mod\n M go.sum\n", 2, 2, "chore: update go.mod and go.sum"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			changes, _ := parseStatus(tt.status)
+			if got := conventionalMessage(changes, tt.added, tt.removed); got != tt.want {
+				t.Errorf("conventionalMessage(%q) = %q, want %q", tt.status, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGenerateCommitMessage(t *testing.T) {
+	repo := filepath.Join(t.TempDir(), "repo")
+	initRepo(t, repo)
+	g := &GitWorktree{repoPath: repo, worktreePath: repo}
+
+	if message, err := g.GenerateCommitMessage(); err != nil || message != "" {
+		t.Fatalf("GenerateCommitMessage() = %q, %v, want no message for a clean worktree", message, err)
+	}
+
+	if err := os.MkdirAll(filepath.Join(repo, "api"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"api/server.go", "notes.md"} {
+		if err := os.WriteFile(filepath.Join(repo, name), []byte("package api\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	message, err := g.GenerateCommitMessage()
+	if err != nil || message != "feat(api): add server.go and notes.md" {
+		t.Errorf("GenerateCommitMessage() = %q, %v", message, err)
+	}
+
+	// Only the staged file counts once something is staged
+	if output, err := exec.Command("git", "-C", repo, "add", "notes.md").CombinedOutput(); err != nil {
+		t.Fatalf("git add: %v\n%s", err, output)
+	}
+	message, err = g.GenerateCommitMessage()
+	if err != nil || message != "docs: add notes.md" {
+		t.Errorf("GenerateCommitMessage() with notes.md staged = %q, %v", message, err)
+	}
+}
+
+func TestPendingChanges(t *testing.T) {
+	repo := filepath.Join(t.TempDir(), "repo")
+	initRepo(t, repo)
+	g := &GitWorktree{repoPath: repo, worktreePath: repo}
+
+	if changes, err := g.PendingChanges(); err != nil || changes != "" {
+		t.Fatalf("PendingChanges() = %q, %v, want nothing for a clean worktree", changes, err)
+	}
+
+	for _, name := range []string{"server.go", "notes.md"} {
+		if err := os.WriteFile(filepath.Join(repo, name), []byte("package api\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	changes, err := g.PendingChanges()
+	if err != nil || !strings.Contains(changes, "New files:\nnotes.md\nserver.go\n") {
+		t.Errorf("PendingChanges() = %q, %v, want the untracked files listed", changes, err)
+	}
+
+	// Once something is staged, only its diff counts
+	if output, err := exec.Command("git", "-C", repo, "add", "notes.md").CombinedOutput(); err != nil {
+		t.Fatalf("git add: %v\n%s", err, output)
+	}
+	changes, err = g.PendingChanges()
+	if err != nil || !strings.Contains(changes, "+++ b/notes.md") || strings.Contains(changes, "server.go") {
+		t.Errorf("PendingChanges() with notes.md staged = %q, %v", changes, err)
+	}
+}
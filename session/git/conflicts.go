@@ -0,0 +1,231 @@
+package git
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	conflictStartMarker = "<<<<<<<"
+	conflictBaseMarker  = "|||||||"
+	conflictSplitMarker = "======="
+	conflictEndMarker   = ">>>>>>>"
+)
+
+// ConflictChoice is how a conflict hunk is resolved.
+type ConflictChoice int
+
+const (
+	// ConflictUnresolved keeps the hunk's conflict markers
+	ConflictUnresolved ConflictChoice = iota
+	// ConflictOurs keeps the lines of the side being rebased onto
+	ConflictOurs
+	// ConflictTheirs keeps the lines of the commit being replayed
+	ConflictTheirs
+	// ConflictBoth keeps our lines followed by theirs
+	ConflictBoth
+)
+
+// ConflictHunk is one conflicted region of a file.
+type ConflictHunk struct {
+	// Line is the 1-based line of the hunk's "<<<<<<<" marker
+	Line        int
+	OursLabel   string
+	TheirsLabel string
+	Ours        []string
+	// Base holds the common ancestor's lines when the diff3 conflict style is used
+	Base   []string
+	Theirs []string
+	// raw is the hunk including its markers, kept for unresolved hunks
+	raw []string
+}
+
+// FileConflict is a conflicted file of a worktree and its hunks.
+type FileConflict struct {
+	Path    string
+	Content string
+	Hunks   []ConflictHunk
+}
+
+// conflictSegment is either a run of plain lines or a conflict hunk of a file.
+type conflictSegment struct {
+	lines []string
+	hunk  *ConflictHunk
+}
+
+// splitConflicts splits content into plain lines and conflict hunks. An unterminated hunk is
+// treated as plain lines.
+func splitConflicts(content string) []conflictSegment {
+	var segments []conflictSegment
+	var plain []string
+	var hunk *ConflictHunk
+	section := 0 // 0 ours, 1 base, 2 theirs
+
+	for i, line := range strings.Split(content, "\n") {
+		trimmed := strings.TrimRight(line, "\r")
+		if hunk == nil {
+			if strings.HasPrefix(trimmed, conflictStartMarker) {
+				hunk = &ConflictHunk{
+					Line:      i + 1,
+					OursLabel: strings.TrimSpace(strings.TrimPrefix(trimmed, conflictStartMarker)),
+					raw:       []string{line},
+				}
+				section = 0
+				continue
+			}
+			plain = append(plain, line)
+			continue
+		}
+
+		hunk.raw = append(hunk.raw, line)
+		switch {
+		case section == 0 && strings.HasPrefix(trimmed, conflictBaseMarker):
+			section = 1
+		case section < 2 && trimmed == conflictSplitMarker:
+			section = 2
+		case section == 2 && strings.HasPrefix(trimmed, conflictEndMarker):
+			hunk.TheirsLabel = strings.TrimSpace(strings.TrimPrefix(trimmed, conflictEndMarker))
+			if len(plain) > 0 {
+				segments = append(segments, conflictSegment{lines: plain})
+				plain = nil
+			}
+			segments = append(segments, conflictSegment{hunk: hunk})
+			hunk = nil
+		case section == 0:
+			hunk.Ours = append(hunk.Ours, line)
+		case section == 1:
+			hunk.Base = append(hunk.Base, line)
+		default:
+			hunk.Theirs = append(hunk.Theirs, line)
+		}
+	}
+	if hunk != nil {
+		plain = append(plain, hunk.raw...)
+	}
+	if len(plain) > 0 {
+		segments = append(segments, conflictSegment{lines: plain})
+	}
+	return segments
+}
+
+// ParseConflicts returns the conflict hunks of content in order.
+func ParseConflicts(content string) []ConflictHunk {
+	var hunks []ConflictHunk
+	for _, segment := range splitConflicts(content) {
+		if segment.hunk != nil {
+			hunks = append(hunks, *segment.hunk)
+		}
+	}
+	return hunks
+}
+
+// ResolveConflicts replaces each hunk of content with the side chosen for it. Hunks without a
+// choice, or chosen as ConflictUnresolved, keep their markers.
+func ResolveConflicts(content string, choices []ConflictChoice) string {
+	var lines []string
+	idx := 0
+	for _, segment := range splitConflicts(content) {
+		if segment.hunk == nil {
+			lines = append(lines, segment.lines...)
+			continue
+		}
+		choice := ConflictUnresolved
+		if idx < len(choices) {
+			choice = choices[idx]
+		}
+		idx++
+		switch choice {
+		case ConflictOurs:
+			lines = append(lines, segment.hunk.Ours...)
+		case ConflictTheirs:
+			lines = append(lines, segment.hunk.Theirs...)
+		case ConflictBoth:
+			lines = append(lines, segment.hunk.Ours...)
+			lines = append(lines, segment.hunk.Theirs...)
+		default:
+			lines = append(lines, segment.hunk.raw...)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// ConflictedFiles returns the paths, relative to the worktree, of files with unresolved conflicts.
+func (g *GitWorktree) ConflictedFiles() ([]string, error) {
+	output, err := g.runGitCommand(g.worktreePath, "diff", "--name-only", "--diff-filter=U")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list conflicted files: %w", err)
+	}
+	var files []string
+	for _, line := range strings.Split(output, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			files = append(files, line)
+		}
+	}
+	return files, nil
+}
+
+// LoadConflicts reads the worktree's conflicted files and their hunks. Files conflicting without
+// markers, such as one side deleting the file, have no hunks.
+func (g *GitWorktree) LoadConflicts() ([]*FileConflict, error) {
+	if err := g.requireLocal("resolve conflicts in it"); err != nil {
+		return nil, err
+	}
+	paths, err := g.ConflictedFiles()
+	if err != nil {
+		return nil, err
+	}
+	conflicts := make([]*FileConflict, 0, len(paths))
+	for _, path := range paths {
+		conflict := &FileConflict{Path: path}
+		if data, err := os.ReadFile(filepath.Join(g.worktreePath, path)); err == nil {
+			conflict.Content = string(data)
+			conflict.Hunks = ParseConflicts(conflict.Content)
+		} else if !os.IsNotExist(err) {
+			return nil, fmt.Errorf("failed to read %s: %w", path, err)
+		}
+		conflicts = append(conflicts, conflict)
+	}
+	return conflicts, nil
+}
+
+// SaveConflictResolution writes the file with the chosen sides applied. Once no conflict markers
+// remain the file is staged as resolved; the return value reports whether it was.
+func (g *GitWorktree) SaveConflictResolution(conflict *FileConflict, choices []ConflictChoice) (bool, error) {
+	resolved := ResolveConflicts(conflict.Content, choices)
+	if err := os.WriteFile(filepath.Join(g.worktreePath, conflict.Path), []byte(resolved), 0644); err != nil {
+		return false, fmt.Errorf("failed to write %s: %w", conflict.Path, err)
+	}
+	if len(ParseConflicts(resolved)) > 0 {
+		return false, nil
+	}
+	if _, err := g.runGitCommand(g.worktreePath, "add", "--", conflict.Path); err != nil {
+		return false, fmt.Errorf("failed to stage %s: %w", conflict.Path, err)
+	}
+	return true, nil
+}
+
+// ResolveConflictWithSide resolves the whole file by taking one side's version and stages it. A
+// side that deleted the file resolves it by deleting it.
+func (g *GitWorktree) ResolveConflictWithSide(path string, choice ConflictChoice) error {
+	side := "--ours"
+	stage := ":2:"
+	if choice == ConflictTheirs {
+		side = "--theirs"
+		stage = ":3:"
+	}
+	if _, err := g.runGitCommand(g.worktreePath, "cat-file", "-e", stage+path); err != nil {
+		if _, err := g.runGitCommand(g.worktreePath, "rm", "--quiet", "--", path); err != nil {
+			return fmt.Errorf("failed to remove %s: %w", path, err)
+		}
+		return nil
+	}
+	if _, err := g.runGitCommand(g.worktreePath, "checkout", side, "--", path); err != nil {
+		return fmt.Errorf("failed to take %s version of %s: %w", strings.TrimPrefix(side, "--"), path, err)
+	}
+	if _, err := g.runGitCommand(g.worktreePath, "add", "--", path); err != nil {
+		return fmt.Errorf("failed to stage %s: %w", path, err)
+	}
+	return nil
+}
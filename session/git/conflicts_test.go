@@ -0,0 +1,70 @@
+package git
+
+import (
+	"reflect"
+	"testing"
+)
+
+const conflictedFile = `package main
+
+<<<<<<< HEAD
+func a() int { return 1 }
+=======
+func a() int { return 2 }
+>>>>>>> abc1234 (Change a)
+
+func b() {}
+<<<<<<< HEAD
+x := 1
+||||||| base
+x := 0
+=======
+x := 2
+>>>>>>> abc1234 (Change a)
+`
+
+func TestParseConflicts(t *testing.T) {
+	hunks := ParseConflicts(conflictedFile)
+	if len(hunks) != 2 {
+		t.Fatalf("got %d hunks, want 2", len(hunks))
+	}
+
+	first := hunks[0]
+	if first.Line != 3 || first.OursLabel != "HEAD" || first.TheirsLabel != "abc1234 (Change a)" {
+		t.Errorf("first hunk = line %d, %q/%q", first.Line, first.OursLabel, first.TheirsLabel)
+	}
+	if !reflect.DeepEqual(first.Ours, []string{"func a() int { return 1 }"}) ||
+		!reflect.DeepEqual(first.Theirs, []string{"func a() int { return 2 }"}) {
+		t.Errorf("first hunk sides = %q / %q", first.Ours, first.Theirs)
+	}
+
+	second := hunks[1]
+	if !reflect.DeepEqual(second.Base, []string{"x := 0"}) || !reflect.DeepEqual(second.Theirs, []string{"x := 2"}) {
+		t.Errorf("second hunk base/theirs = %q / %q", second.Base, second.Theirs)
+	}
+}
+
+func TestResolveConflicts(t *testing.T) {
+	got := ResolveConflicts(conflictedFile, []ConflictChoice{ConflictTheirs, ConflictBoth})
+	want := "package main\n\nfunc a() int { return 2 }\n\nfunc b() {}\nx := 1\nx := 2\n"
+	if got != want {
+		t.Errorf("ResolveConflicts() = %q, want %q", got, want)
+	}
+
+	// Hunks without a choice keep their markers
+	partial := ResolveConflicts(conflictedFile, []ConflictChoice{ConflictOurs})
+	hunks := ParseConflicts(partial)
+	if len(hunks) != 1 || hunks[0].Ours[0] != "x := 1" {
+		t.Errorf("partial resolution left %d hunks: %q", len(hunks), partial)
+	}
+}
+
+func TestParseConflictsIgnoresUnterminatedHunk(t *testing.T) {
+	content := "a\n<<<<<<< HEAD\nb\n"
+	if hunks := ParseConflicts(content); len(hunks) != 0 {
+		t.Errorf("got %d hunks, want 0", len(hunks))
+	}
+	if got := ResolveConflicts(content, nil); got != content {
+		t.Errorf("ResolveConflicts() = %q, want content unchanged", got)
+	}
+}
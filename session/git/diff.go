@@ -1,7 +1,6 @@
 package git
 
 import (
-	"os"
 	"strings"
 )
 
@@ -29,7 +28,7 @@ func (g *GitWorktree) Diff() *DiffStats {
 	stats := &DiffStats{}
 
 	// Check if worktree path exists
-	if _, err := os.Stat(g.worktreePath); os.IsNotExist(err) {
+	if g.worktreeMissing() {
 		// Return empty stats for non-existent worktree
 		return stats
 	}
@@ -59,12 +58,46 @@ func (g *GitWorktree) Diff() *DiffStats {
 	return stats
 }
 
+// GetDiffAgainst returns the diff between the worktree, uncommitted changes included, and ref: a
+// branch, preferring origin's, a tag or a commit, instead of the commit the branch started from.
+func (g *GitWorktree) GetDiffAgainst(ref string) *DiffStats {
+	stats := &DiffStats{}
+	if g.worktreeMissing() {
+		return stats
+	}
+	commit, err := g.resolveRef(ref)
+	if err != nil {
+		stats.Error = err
+		return stats
+	}
+
+	// -N stages untracked files (intent to add), including them in the diff
+	if _, err := g.runGitCommand(g.worktreePath, "add", "-N", "."); err != nil {
+		stats.Error = err
+		return stats
+	}
+	content, err := g.runGitCommand(g.worktreePath, "--no-pager", "diff", commit)
+	if err != nil {
+		stats.Error = err
+		return stats
+	}
+	for _, line := range strings.Split(content, "\n") {
+		if strings.HasPrefix(line, "+") && !strings.HasPrefix(line, "+++") {
+			stats.Added++
+		} else if strings.HasPrefix(line, "-") && !strings.HasPrefix(line, "---") {
+			stats.Removed++
+		}
+	}
+	stats.Content = content
+	return stats
+}
+
 // DiffUncommittedOrLastCommit returns uncommitted changes if they exist, otherwise the last commit diff
 func (g *GitWorktree) DiffUncommittedOrLastCommit() *DiffStats {
 	stats := &DiffStats{}
 
 	// Check if worktree path exists
-	if _, err := os.Stat(g.worktreePath); os.IsNotExist(err) {
+	if g.worktreeMissing() {
 		// Return empty stats for non-existent worktree
 		return stats
 	}
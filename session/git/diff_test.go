@@ -0,0 +1,61 @@
+package git
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestGetDiffAgainst(t *testing.T) {
+	for _, name := range []string{"GIT_AUTHOR_NAME", "GIT_COMMITTER_NAME"} {
+		t.Setenv(name, "test")
+	}
+	for _, name := range []string{"GIT_AUTHOR_EMAIL", "GIT_COMMITTER_EMAIL"} {
+		t.Setenv(name, "test@example.com")
+	}
+	repo := filepath.Join(t.TempDir(), "repo")
+	initRepo(t, repo)
+	git := func(args ...string) {
+		t.Helper()
+		if output, err := exec.Command("git", append([]string{"-C", repo}, args...)...).CombinedOutput(); err != nil {
+			t.Fatalf("git %s: %v\n%s", strings.Join(args, " "), err, output)
+		}
+	}
+	write := func(name, content string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(repo, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("a.txt", "one\n")
+	git("add", ".")
+	git("commit", "-q", "-m", "add a.txt")
+	git("tag", "v1")
+	write("b.txt", "two\n")
+	git("add", ".")
+	git("commit", "-q", "-m", "add b.txt")
+	write("c.txt", "three\n")
+
+	g := &GitWorktree{repoPath: repo, worktreePath: repo}
+
+	// Against the tag, both the later commit and the untracked file show
+	stats := g.GetDiffAgainst("refs/tags/v1")
+	if stats.Error != nil {
+		t.Fatalf("GetDiffAgainst(v1) error = %v", stats.Error)
+	}
+	if stats.Added != 2 || !strings.Contains(stats.Content, "b.txt") || !strings.Contains(stats.Content, "c.txt") {
+		t.Errorf("GetDiffAgainst(v1) = +%d\n%s", stats.Added, stats.Content)
+	}
+
+	// Against the branch itself, only the uncommitted file does
+	stats = g.GetDiffAgainst("main")
+	if stats.Error != nil || stats.Added != 1 || strings.Contains(stats.Content, "b.txt") {
+		t.Errorf("GetDiffAgainst(main) = +%d, %v\n%s", stats.Added, stats.Error, stats.Content)
+	}
+
+	if stats := g.GetDiffAgainst("no-such-ref"); stats.Error == nil {
+		t.Error("GetDiffAgainst(no-such-ref) succeeded")
+	}
+}
@@ -0,0 +1,123 @@
+package git
+
+import (
+	"os/exec"
+	"strings"
+	"sync"
+)
+
+// readOnlyGitCommands are git subcommands that don't change the repository, which still run
+// during a dry run since later steps depend on their output.
+var readOnlyGitCommands = map[string]bool{
+	"blame":        true,
+	"cat-file":     true,
+	"describe":     true,
+	"diff":         true,
+	"for-each-ref": true,
+	"log":          true,
+	"ls-files":     true,
+	"ls-remote":    true,
+	"merge-base":   true,
+	"rev-list":     true,
+	"rev-parse":    true,
+	"show":         true,
+	"show-ref":     true,
+	"status":       true,
+}
+
+// commandRecorder collects the commands a dry run would have run.
+type commandRecorder struct {
+	mu       sync.Mutex
+	commands []string
+}
+
+func (r *commandRecorder) record(name string, args ...string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.commands = append(r.commands, formatCommand(name, args...))
+}
+
+// DryRun runs fn against a copy of the worktree in dry-run mode and returns the commands it
+// would run. Commands that change the repository or the remote are recorded instead of run;
+// read-only ones still run, so fn takes the same decisions as it would for real. The error is
+// fn's, returned with the commands recorded up to it.
+func (g *GitWorktree) DryRun(fn func(*GitWorktree) error) ([]string, error) {
+	c := *g
+	c.dryRun = &commandRecorder{}
+	err := fn(&c)
+	return c.dryRun.commands, err
+}
+
+// dryRunGit records the git command to run in path if the worktree is in dry-run mode and the
+// command changes anything, returning true if it was recorded rather than to be run.
+func (g *GitWorktree) dryRunGit(path string, args []string) bool {
+	if g.dryRun == nil || !mutatesRepo(args) {
+		return false
+	}
+	if path != g.worktreePath {
+		args = append([]string{"-C", path}, args...)
+	}
+	g.dryRun.record("git", args...)
+	return true
+}
+
+// runCommand runs a command other than git, such as the forge's CLI, in dir on the machine the
+// worktree is on and returns its combined output. In dry-run mode it's recorded instead.
+func (g *GitWorktree) runCommand(dir string, name string, args ...string) ([]byte, error) {
+	if g.dryRun != nil {
+		g.dryRun.record(name, args...)
+		return nil, nil
+	}
+	if g.runner != nil {
+		// The runner's commands start in the home directory there
+		return g.command("sh", append([]string{"-c", `cd "$1" && shift && exec "$@"`, "sh", dir, name}, args...)...).CombinedOutput()
+	}
+	cmd := exec.Command(name, args...)
+	cmd.Dir = dir
+	return cmd.CombinedOutput()
+}
+
+// mutatesRepo returns true if the git command with args may change the repository, its
+// worktrees or the remote.
+func mutatesRepo(args []string) bool {
+	if len(args) == 0 {
+		return false
+	}
+	if readOnlyGitCommands[args[0]] {
+		return false
+	}
+	rest := args[1:]
+	switch args[0] {
+	case "branch":
+		// Listing branches, unless a branch is named to create
+		for _, arg := range rest {
+			switch arg {
+			case "--show-current", "--list", "-l", "-r", "-a", "--contains", "--merged", "--no-merged":
+				return false
+			}
+		}
+		return len(rest) > 0
+	case "worktree", "remote", "stash":
+		return len(rest) > 0 && rest[0] != "list" && rest[0] != "show" && rest[0] != "get-url"
+	case "config":
+		for _, arg := range rest {
+			if arg == "--get" || arg == "--get-all" || arg == "--list" || arg == "-l" {
+				return false
+			}
+		}
+		return true
+	}
+	return true
+}
+
+// formatCommand returns the command line for name and args, quoting arguments the shell would
+// split or interpret.
+func formatCommand(name string, args ...string) string {
+	parts := append([]string{name}, args...)
+	for i, part := range parts {
+		if part == "" || strings.ContainsAny(part, " \t\n'\"\\$`*?&|;<>()#~!{}[]") {
+			parts[i] = "'" + strings.ReplaceAll(part, "'", `'\''`) + "'"
+		}
+	}
+	return strings.Join(parts, " ")
+}
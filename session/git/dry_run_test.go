@@ -0,0 +1,70 @@
+package git
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestMutatesRepo(t *testing.T) {
+	for _, tt := range []struct {
+		args []string
+		want bool
+	}{
+		{[]string{"rev-parse", "HEAD"}, false},
+		{[]string{"status", "--porcelain"}, false},
+		{[]string{"branch", "--show-current"}, false},
+		{[]string{"branch", "-r", "--contains", "abc"}, false},
+		{[]string{"branch"}, false},
+		{[]string{"branch", "feature-backup-1"}, true},
+		{[]string{"branch", "-D", "feature"}, true},
+		{[]string{"worktree", "list", "--porcelain"}, false},
+		{[]string{"worktree", "remove", "-f", "/tmp/wt"}, true},
+		{[]string{"config", "--get", "branch.main.remote"}, false},
+		{[]string{"config", "branch.main.remote", "origin"}, true},
+		{[]string{"fetch", "origin"}, true},
+		{[]string{"push", "origin", "main"}, true},
+		{[]string{"reset", "--hard", "origin/main"}, true},
+	} {
+		if got := mutatesRepo(tt.args); got != tt.want {
+			t.Errorf("mutatesRepo(%q) = %v, want %v", tt.args, got, tt.want)
+		}
+	}
+}
+
+func TestFormatCommand(t *testing.T) {
+	got := formatCommand("git", "commit", "-m", "it's done", "--no-verify")
+	want := `git commit -m 'it'\''s done' --no-verify`
+	if got != want {
+		t.Errorf("formatCommand() = %s, want %s", got, want)
+	}
+}
+
+func TestDryRun(t *testing.T) {
+	repo := filepath.Join(t.TempDir(), "repo")
+	initRepo(t, repo)
+	if err := os.WriteFile(filepath.Join(repo, "file.txt"), []byte("change\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	g := &GitWorktree{repoPath: repo, worktreePath: repo, branchName: "main"}
+
+	commands, err := g.DryRun(func(wt *GitWorktree) error {
+		return wt.CommitChanges("feat: change")
+	})
+	if err != nil {
+		t.Fatalf("DryRun() failed: %v", err)
+	}
+	want := []string{"git add .", "git commit -m 'feat: change' --no-verify"}
+	if !reflect.DeepEqual(commands, want) {
+		t.Errorf("DryRun() = %q, want %q", commands, want)
+	}
+
+	// Nothing was committed, and the worktree itself isn't left in dry-run mode
+	if dirty, err := g.IsDirty(); err != nil || !dirty {
+		t.Fatalf("IsDirty() = %v, %v after a dry run, want the change left uncommitted", dirty, err)
+	}
+	if g.dryRun != nil {
+		t.Fatal("DryRun() left the worktree in dry-run mode")
+	}
+}
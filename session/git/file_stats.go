@@ -0,0 +1,95 @@
+package git
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// FileStat is how a file differs from the base commit: the lines added and removed, and a hash
+// of its diff to tell whether it changed between two looks even if the counts didn't.
+type FileStat struct {
+	Added   int
+	Removed int
+	Hash    [sha256.Size]byte
+}
+
+// FileStats maps the files that differ from the base commit to how they differ.
+type FileStats map[string]FileStat
+
+// Lines returns the lines added and removed in all files.
+func (s FileStats) Lines() (added, removed int) {
+	for _, stat := range s {
+		added += stat.Added
+		removed += stat.Removed
+	}
+	return added, removed
+}
+
+// TouchedSince returns the files that changed since the stats before were taken, sorted: those
+// whose diff differs, and those that differed before but no longer do.
+func (s FileStats) TouchedSince(before FileStats) []string {
+	var touched []string
+	for path, stat := range s {
+		if previous, ok := before[path]; !ok || previous.Hash != stat.Hash {
+			touched = append(touched, path)
+		}
+	}
+	for path := range before {
+		if _, ok := s[path]; !ok {
+			touched = append(touched, path)
+		}
+	}
+	sort.Strings(touched)
+	return touched
+}
+
+// FileStats returns how each file in the worktree, committed, uncommitted or untracked, differs
+// from the base commit.
+func (g *GitWorktree) FileStats() (FileStats, error) {
+	// -N stages untracked files (intent to add), including them in the diff
+	if _, err := g.runGitCommand(g.worktreePath, "add", "-N", "."); err != nil {
+		return nil, fmt.Errorf("failed to include untracked files: %w", err)
+	}
+	output, err := g.runGitCommand(g.worktreePath, "--no-pager", "diff", "--no-color", "--no-ext-diff",
+		"--no-renames", "--src-prefix=a/", "--dst-prefix=b/", g.GetBaseCommitSHA())
+	if err != nil {
+		return nil, fmt.Errorf("failed to diff the worktree: %w", err)
+	}
+	return parseFileStats(output), nil
+}
+
+// parseFileStats splits a diff into its files and counts the lines each adds and removes.
+func parseFileStats(diff string) FileStats {
+	stats := make(FileStats)
+	var path string
+	var stat FileStat
+	var section []string
+	flush := func() {
+		if path != "" {
+			stat.Hash = sha256.Sum256([]byte(strings.Join(section, "\n")))
+			stats[path] = stat
+		}
+	}
+	for _, line := range strings.Split(strings.TrimSuffix(diff, "\n"), "\n") {
+		if strings.HasPrefix(line, "diff --git ") {
+			flush()
+			path, stat, section = "", FileStat{}, nil
+			// The b/ side is the file's path now, the a/ side for deleted files is the same
+			if idx := strings.LastIndex(line, " b/"); idx >= 0 {
+				path = line[idx+len(" b/"):]
+			}
+		}
+		section = append(section, line)
+		switch {
+		case strings.HasPrefix(line, "+++ ") || strings.HasPrefix(line, "--- "):
+		case strings.HasPrefix(line, "+"):
+			stat.Added++
+		case strings.HasPrefix(line, "-"):
+			stat.Removed++
+		}
+	}
+	flush()
+	return stats
+}
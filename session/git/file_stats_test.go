@@ -0,0 +1,55 @@
+package git
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestFileStatsTouchedSince(t *testing.T) {
+	repo := filepath.Join(t.TempDir(), "repo")
+	initRepo(t, repo)
+	base, err := exec.Command("git", "-C", repo, "rev-parse", "HEAD").Output()
+	if err != nil {
+		t.Fatal(err)
+	}
+	write := func(name, content string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(repo, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	g := &GitWorktree{repoPath: repo, worktreePath: repo, baseCommitSHA: strings.TrimSpace(string(base))}
+
+	write("kept.txt", "one\n")
+	write("edited.txt", "one\ntwo\n")
+	write("removed.txt", "one\n")
+	before, err := g.FileStats()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if added, removed := before.Lines(); added != 4 || removed != 0 {
+		t.Errorf("Lines() = +%d -%d, want +4 -0", added, removed)
+	}
+
+	// Rewriting a line keeps the counts but still touches the file
+	write("edited.txt", "one\nthree\n")
+	write("added.txt", "one\ntwo\nthree\n")
+	if err := os.Remove(filepath.Join(repo, "removed.txt")); err != nil {
+		t.Fatal(err)
+	}
+	after, err := g.FileStats()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"added.txt", "edited.txt", "removed.txt"}
+	if touched := after.TouchedSince(before); !reflect.DeepEqual(touched, want) {
+		t.Errorf("TouchedSince() = %v, want %v", touched, want)
+	}
+	if added, removed := after.Lines(); added != 6 || removed != 0 {
+		t.Errorf("Lines() = +%d -%d, want +6 -0", added, removed)
+	}
+}
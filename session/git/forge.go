@@ -0,0 +1,164 @@
+package git
+
+import (
+	"claude-squad/config"
+	"claude-squad/log"
+	"fmt"
+	"net/url"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Forge names accepted in the "forge" repo config setting
+const (
+	ForgeGitHub = "github"
+	ForgeGitLab = "gitlab"
+)
+
+// Forge is the code host a repository's pull requests live on. Each implementation drives the
+// host's CLI (gh or glab) from the directory of a checkout.
+type Forge interface {
+	// Name returns the forge's config name, one of the Forge constants.
+	Name() string
+	// CheckCLI returns an error if the forge's CLI is missing or not logged in.
+	CheckCLI() error
+	// Push pushes the worktree's branch to the origin remote.
+	Push(g *GitWorktree) error
+	// OpenBranch opens the branch's page in the browser.
+	OpenBranch(dir, branch string) error
+	// CreatePullRequest opens the form for a new pull request from branch in the browser,
+	// prefilled with title and body, or from the branch's commits if title is empty.
+	CreatePullRequest(dir, branch, title, body string) error
+	// CurrentPullRequest returns the pull request for the branch checked out in dir.
+	CurrentPullRequest(dir string) (*PullRequest, error)
+	// PullRequestStatus returns a summary of the pull request for the branch checked out in dir.
+	PullRequestStatus(dir string) (*PRStatus, error)
+	// FetchComments fills pr.AllComments and pr.Reviews.
+	FetchComments(dir string, pr *PullRequest) error
+	// UnresolvedThreads returns the IDs of the pull request's unresolved review threads.
+	UnresolvedThreads(dir string, pr *PullRequest) ([]string, error)
+	// ResolveThread marks a review thread as resolved.
+	ResolveThread(dir string, pr *PullRequest, threadID string) error
+	// ReplyToComment posts a reply to comment and returns it.
+	ReplyToComment(dir string, pr *PullRequest, comment *PRComment, body string) (*PRComment, error)
+	// CIChecks returns the CI checks reported for the commit.
+	CIChecks(dir, sha string) ([]CICheck, error)
+	// Issue returns the issue with the given number.
+	Issue(dir string, number int) (*Issue, error)
+}
+
+// NewForge returns the forge with the given config name.
+func NewForge(name string) (Forge, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case ForgeGitHub:
+		return githubForge{}, nil
+	case ForgeGitLab:
+		return gitlabForge{}, nil
+	default:
+		return nil, fmt.Errorf("unknown forge %q: must be %q or %q", name, ForgeGitHub, ForgeGitLab)
+	}
+}
+
+type forgeCacheEntry struct {
+	forge     Forge
+	checkedAt time.Time
+}
+
+var (
+	forgeCacheMu sync.Mutex
+	forgeCache   = make(map[string]forgeCacheEntry)
+)
+
+// ForgeFor returns the forge for the repository containing dir. The "forge" repo config setting
+// wins; otherwise GitLab is used when the origin remote's host names it, and GitHub if not.
+func ForgeFor(dir string) Forge {
+	forgeCacheMu.Lock()
+	entry, ok := forgeCache[dir]
+	forgeCacheMu.Unlock()
+	if ok && time.Since(entry.checkedAt) < remoteCacheTTL {
+		return entry.forge
+	}
+
+	forge := detectForge(dir)
+
+	forgeCacheMu.Lock()
+	defer forgeCacheMu.Unlock()
+	forgeCache[dir] = forgeCacheEntry{forge: forge, checkedAt: time.Now()}
+	return forge
+}
+
+func detectForge(dir string) Forge {
+	for _, root := range configRoots(dir) {
+		name := config.LoadRepoConfig(root).Forge
+		if name == "" {
+			continue
+		}
+		forge, err := NewForge(name)
+		if err != nil {
+			log.WarningLog.Printf("repo config in %s: %v", root, err)
+			break
+		}
+		return forge
+	}
+
+	output, err := exec.Command("git", "-C", dir, "remote", "get-url", "origin").Output()
+	if err == nil && strings.Contains(strings.ToLower(remoteHost(strings.TrimSpace(string(output)))), ForgeGitLab) {
+		return gitlabForge{}
+	}
+	return githubForge{}
+}
+
+// configRoots returns the directories whose repo config applies to dir: the checkout containing
+// it, then the main repository if dir is in a linked worktree.
+func configRoots(dir string) []string {
+	output, err := exec.Command("git", "-C", dir, "rev-parse", "--show-toplevel", "--path-format=absolute", "--git-common-dir").Output()
+	if err != nil {
+		return []string{dir}
+	}
+	lines := strings.Split(strings.TrimSpace(string(output)), "\n")
+	roots := []string{lines[0]}
+	if len(lines) > 1 {
+		if main := filepath.Dir(lines[1]); main != lines[0] {
+			roots = append(roots, main)
+		}
+	}
+	return roots
+}
+
+// remoteHost returns the host of a remote URL in any of the forms git accepts:
+// https://host/path, ssh://user@host:port/path or user@host:path.
+func remoteHost(remoteURL string) string {
+	if strings.Contains(remoteURL, "://") {
+		parsed, err := url.Parse(remoteURL)
+		if err != nil {
+			return ""
+		}
+		return parsed.Hostname()
+	}
+	host, _, found := strings.Cut(remoteURL, ":")
+	if !found {
+		return ""
+	}
+	if i := strings.LastIndex(host, "@"); i >= 0 {
+		host = host[i+1:]
+	}
+	return host
+}
+
+// forgeFor returns the forge the pull request was loaded from, or the one for dir if it was
+// built elsewhere.
+func (pr *PullRequest) forgeFor(dir string) Forge {
+	if pr.forge != nil {
+		return pr.forge
+	}
+	return ForgeFor(dir)
+}
+
+// forge returns the forge for the worktree's repository. Its CLI runs on this machine, so it's
+// only for worktrees on this one.
+func (g *GitWorktree) forge() Forge {
+	return ForgeFor(g.repoPath)
+}
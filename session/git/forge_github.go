@@ -0,0 +1,95 @@
+package git
+
+import (
+	"claude-squad/log"
+	"fmt"
+	"os/exec"
+)
+
+// githubForge drives GitHub through the gh CLI.
+type githubForge struct{}
+
+func (githubForge) Name() string {
+	return ForgeGitHub
+}
+
+func (githubForge) CheckCLI() error {
+	return checkGHCLI()
+}
+
+func (githubForge) Push(g *GitWorktree) error {
+	// gh repo sync only syncs a branch with the remote branch of the same name
+	if g.pushBranch != "" {
+		return g.pushWithGit()
+	}
+
+	// First push the branch to remote to ensure it exists
+	if _, err := g.runCommand(g.worktreePath, "gh", "repo", "sync", "--source", "-b", g.branchName); err != nil {
+		// If sync fails, try creating the branch on remote first
+		if _, pushErr := g.runGitCommandWithProgress(g.worktreePath, "push", "-u", "origin", g.branchName); pushErr != nil {
+			log.ErrorLog.Print(pushErr)
+			return fmt.Errorf("failed to push branch: %w", pushErr)
+		}
+	}
+
+	// Now sync with remote
+	if output, err := g.runCommand(g.worktreePath, "gh", "repo", "sync", "-b", g.branchName); err != nil {
+		log.ErrorLog.Print(err)
+		return fmt.Errorf("failed to sync changes: %s (%w)", output, err)
+	}
+	return nil
+}
+
+func (githubForge) OpenBranch(dir, branch string) error {
+	cmd := exec.Command("gh", "browse", "--branch", branch)
+	cmd.Dir = dir
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("failed to open branch URL: %w", err)
+	}
+	return nil
+}
+
+func (githubForge) CreatePullRequest(dir, branch, title, body string) error {
+	args := []string{"pr", "create", "--fill", "--web", "--head", branch}
+	if title != "" {
+		args = []string{"pr", "create", "--title", title, "--body", body, "--web", "--head", branch}
+	}
+	cmd := exec.Command("gh", args...)
+	cmd.Dir = dir
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to create pull request: %s (%w)", output, err)
+	}
+	return nil
+}
+
+func (githubForge) CurrentPullRequest(dir string) (*PullRequest, error) {
+	return getGitHubPR(dir)
+}
+
+func (githubForge) PullRequestStatus(dir string) (*PRStatus, error) {
+	return getGitHubPRStatus(dir)
+}
+
+func (githubForge) FetchComments(dir string, pr *PullRequest) error {
+	return pr.fetchGitHubComments(dir)
+}
+
+func (githubForge) UnresolvedThreads(dir string, pr *PullRequest) ([]string, error) {
+	return pr.getGitHubUnresolvedThreads(dir)
+}
+
+func (githubForge) ResolveThread(dir string, pr *PullRequest, threadID string) error {
+	return pr.resolveGitHubThread(dir, threadID)
+}
+
+func (githubForge) ReplyToComment(dir string, pr *PullRequest, comment *PRComment, body string) (*PRComment, error) {
+	return pr.replyToGitHubComment(dir, comment, body)
+}
+
+func (githubForge) CIChecks(dir, sha string) ([]CICheck, error) {
+	return githubCIChecks(dir, sha)
+}
+
+func (githubForge) Issue(dir string, number int) (*Issue, error) {
+	return githubIssue(dir, number)
+}
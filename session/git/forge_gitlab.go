@@ -0,0 +1,434 @@
+package git
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// gitlabForge drives GitLab merge requests through the glab CLI. glab fills in the :id
+// placeholder of API paths with the project of the checkout's remote.
+type gitlabForge struct{}
+
+// gitlabNote is a note (comment) in a merge request discussion
+type gitlabNote struct {
+	ID     int    `json:"id"`
+	Body   string `json:"body"`
+	Author struct {
+		Username string `json:"username"`
+	} `json:"author"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+	System     bool      `json:"system"`
+	Resolvable bool      `json:"resolvable"`
+	Resolved   bool      `json:"resolved"`
+	// Position is set for notes on a line of the diff
+	Position *struct {
+		NewPath string `json:"new_path"`
+		OldPath string `json:"old_path"`
+		NewLine *int   `json:"new_line"`
+		OldLine *int   `json:"old_line"`
+		HeadSHA string `json:"head_sha"`
+	} `json:"position"`
+}
+
+// gitlabDiscussion is a thread of notes on a merge request
+type gitlabDiscussion struct {
+	ID    string       `json:"id"`
+	Notes []gitlabNote `json:"notes"`
+}
+
+func (gitlabForge) Name() string {
+	return ForgeGitLab
+}
+
+func (gitlabForge) CheckCLI() error {
+	if _, err := exec.LookPath("glab"); err != nil {
+		return fmt.Errorf("GitLab CLI (glab) is not installed. Please install it first")
+	}
+	if err := exec.Command("glab", "auth", "status").Run(); err != nil {
+		return fmt.Errorf("GitLab CLI is not configured. Please run 'glab auth login' first")
+	}
+	return nil
+}
+
+func (gitlabForge) Push(g *GitWorktree) error {
+	return g.pushWithGit()
+}
+
+func (gitlabForge) OpenBranch(dir, branch string) error {
+	cmd := exec.Command("glab", "repo", "view", "--web", "--branch", branch)
+	cmd.Dir = dir
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("failed to open branch URL: %w", err)
+	}
+	return nil
+}
+
+func (gitlabForge) CreatePullRequest(dir, branch, title, body string) error {
+	args := []string{"mr", "create", "--fill", "--web", "--yes", "--source-branch", branch}
+	if title != "" {
+		args = []string{"mr", "create", "--title", title, "--description", body, "--web", "--yes", "--source-branch", branch}
+	}
+	cmd := exec.Command("glab", args...)
+	cmd.Dir = dir
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to create merge request: %s (%w)", output, err)
+	}
+	return nil
+}
+
+func (gitlabForge) CurrentPullRequest(dir string) (*PullRequest, error) {
+	branch, err := exec.Command("git", "-C", dir, "rev-parse", "--abbrev-ref", "HEAD").Output()
+	if err != nil {
+		return nil, fmt.Errorf("not in a git repository: %s", dir)
+	}
+
+	// The most recently updated merge request from the branch, in any state, like gh pr view
+	endpoint := fmt.Sprintf("projects/:id/merge_requests?source_branch=%s&order_by=updated_at&per_page=1",
+		url.QueryEscape(strings.TrimSpace(string(branch))))
+	output, err := glabAPI(dir, endpoint)
+	if err != nil {
+		if strings.Contains(err.Error(), "401") || strings.Contains(err.Error(), "glab auth login") {
+			return nil, fmt.Errorf("GitLab CLI not authenticated. Run 'glab auth login' first")
+		}
+		return nil, fmt.Errorf("failed to get current merge request from %s: %w", dir, err)
+	}
+
+	pr, err := parseGitLabMergeRequests(output)
+	if err != nil {
+		return nil, err
+	}
+	if pr == nil {
+		return nil, fmt.Errorf("no merge request found for the current branch in %s", dir)
+	}
+	return pr, nil
+}
+
+func (f gitlabForge) PullRequestStatus(dir string) (*PRStatus, error) {
+	pr, err := f.CurrentPullRequest(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	// The head pipeline is only included when fetching a single merge request
+	mergeRequest, err := glabAPI(dir, fmt.Sprintf("projects/:id/merge_requests/%d", pr.Number))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get merge request status from %s: %w", dir, err)
+	}
+	// Approval rules are optional, so the status is still useful without them
+	approvals, err := glabAPI(dir, fmt.Sprintf("projects/:id/merge_requests/%d/approvals", pr.Number))
+	if err != nil {
+		approvals = nil
+	}
+
+	return parseGitLabStatus(mergeRequest, approvals)
+}
+
+func (gitlabForge) FetchComments(dir string, pr *PullRequest) error {
+	discussions, err := fetchGitLabDiscussions(dir, pr)
+	if err != nil {
+		return err
+	}
+	pr.AllComments = append(pr.AllComments, discussionComments(discussions)...)
+	return nil
+}
+
+func (gitlabForge) UnresolvedThreads(dir string, pr *PullRequest) ([]string, error) {
+	discussions, err := fetchGitLabDiscussions(dir, pr)
+	if err != nil {
+		return nil, err
+	}
+	var unresolved []string
+	for _, comment := range discussionComments(discussions) {
+		if comment.ThreadID != "" && !comment.IsResolved {
+			unresolved = append(unresolved, comment.ThreadID)
+		}
+	}
+	return unresolved, nil
+}
+
+func (gitlabForge) ResolveThread(dir string, pr *PullRequest, threadID string) error {
+	output, err := glabAPI(dir, "-X", "PUT",
+		fmt.Sprintf("projects/:id/merge_requests/%d/discussions/%s", pr.Number, threadID), "-f", "resolved=true")
+	if err != nil {
+		return fmt.Errorf("failed to resolve thread %s: %w", threadID, err)
+	}
+
+	var discussion gitlabDiscussion
+	if err := json.Unmarshal(output, &discussion); err != nil {
+		return fmt.Errorf("failed to parse resolve thread response: %w", err)
+	}
+	if !discussionResolved(discussion) {
+		return fmt.Errorf("thread %s was not resolved successfully", threadID)
+	}
+	return nil
+}
+
+func (gitlabForge) ReplyToComment(dir string, pr *PullRequest, comment *PRComment, body string) (*PRComment, error) {
+	if comment.discussionID == "" {
+		return nil, fmt.Errorf("comment %d is not part of a merge request discussion", comment.ID)
+	}
+
+	output, err := glabAPI(dir, "-X", "POST",
+		fmt.Sprintf("projects/:id/merge_requests/%d/discussions/%s/notes", pr.Number, comment.discussionID), "-f", "body="+body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to post reply: %w", err)
+	}
+
+	var note gitlabNote
+	if err := json.Unmarshal(output, &note); err != nil {
+		return nil, fmt.Errorf("failed to parse reply response: %w", err)
+	}
+
+	threadStart := comment.InReplyToID
+	if threadStart == 0 {
+		threadStart = comment.ID
+	}
+	return &PRComment{
+		ID:           note.ID,
+		Body:         note.Body,
+		Author:       note.Author.Username,
+		CreatedAt:    note.CreatedAt,
+		UpdatedAt:    note.UpdatedAt,
+		State:        "pending",
+		Type:         comment.Type,
+		Path:         comment.Path,
+		Line:         comment.Line,
+		CommitID:     comment.CommitID,
+		InReplyToID:  threadStart,
+		discussionID: comment.discussionID,
+	}, nil
+}
+
+func (gitlabForge) CIChecks(dir, sha string) ([]CICheck, error) {
+	return gitlabCIChecks(dir, sha)
+}
+
+func (gitlabForge) Issue(dir string, number int) (*Issue, error) {
+	return gitlabIssue(dir, number)
+}
+
+// glabAPI runs glab api in dir and returns the response body.
+func glabAPI(dir string, args ...string) ([]byte, error) {
+	cmd := exec.Command("glab", append([]string{"api"}, args...)...)
+	cmd.Dir = dir
+	output, err := cmd.Output()
+	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			return nil, fmt.Errorf("glab api failed (output: %s): %w", strings.TrimSpace(string(exitErr.Stderr)), err)
+		}
+		return nil, fmt.Errorf("glab api failed: %w", err)
+	}
+	return output, nil
+}
+
+func fetchGitLabDiscussions(dir string, pr *PullRequest) ([]gitlabDiscussion, error) {
+	output, err := glabAPI(dir, "--paginate", fmt.Sprintf("projects/:id/merge_requests/%d/discussions?per_page=100", pr.Number))
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch merge request discussions: %w", err)
+	}
+	discussions, err := parseGitLabDiscussions(output)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse merge request discussions: %w", err)
+	}
+	return discussions, nil
+}
+
+// parseGitLabDiscussions parses the output of a paginated discussions request, which is one JSON
+// array per page.
+func parseGitLabDiscussions(data []byte) ([]gitlabDiscussion, error) {
+	var discussions []gitlabDiscussion
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	for {
+		var page []gitlabDiscussion
+		if err := decoder.Decode(&page); err == io.EOF {
+			return discussions, nil
+		} else if err != nil {
+			return nil, err
+		}
+		discussions = append(discussions, page...)
+	}
+}
+
+// discussionComments converts merge request discussions to comments. Notes on the diff become
+// review comments and the rest general comments; system notes (pushes, label changes) are
+// skipped. The first note of a resolvable discussion carries its ID as the thread ID, and later
+// notes reply to the first.
+func discussionComments(discussions []gitlabDiscussion) []*PRComment {
+	var comments []*PRComment
+	for _, discussion := range discussions {
+		resolved := discussionResolved(discussion)
+		threadStart := 0
+		for _, note := range discussion.Notes {
+			if note.System || strings.TrimSpace(note.Body) == "" {
+				continue
+			}
+
+			comment := &PRComment{
+				ID:             note.ID,
+				Body:           note.Body,
+				Author:         note.Author.Username,
+				CreatedAt:      note.CreatedAt,
+				UpdatedAt:      note.UpdatedAt,
+				State:          "pending",
+				Type:           "issue_comment",
+				IsResolved:     resolved,
+				IsGeminiReview: strings.Contains(note.Body, GeminiReviewCommand),
+				discussionID:   discussion.ID,
+			}
+			if pos := note.Position; pos != nil {
+				comment.Type = "review_comment"
+				comment.Path = pos.NewPath
+				if comment.Path == "" {
+					comment.Path = pos.OldPath
+				}
+				if pos.NewLine != nil {
+					comment.Line = *pos.NewLine
+				} else if pos.OldLine != nil {
+					comment.Line = *pos.OldLine
+				}
+				comment.OriginalLine = comment.Line
+				comment.CommitID = pos.HeadSHA
+			}
+
+			if threadStart == 0 {
+				threadStart = note.ID
+				if note.Resolvable {
+					comment.ThreadID = discussion.ID
+				}
+			} else {
+				comment.InReplyToID = threadStart
+			}
+			comments = append(comments, comment)
+		}
+	}
+	return comments
+}
+
+// discussionResolved returns true if the discussion has resolvable notes and all are resolved.
+func discussionResolved(discussion gitlabDiscussion) bool {
+	resolvable := false
+	for _, note := range discussion.Notes {
+		if !note.Resolvable {
+			continue
+		}
+		if !note.Resolved {
+			return false
+		}
+		resolvable = true
+	}
+	return resolvable
+}
+
+// gitlabStates maps merge request states to the pull request states PRStatus uses
+var gitlabStates = map[string]string{
+	"opened": "OPEN",
+	"merged": "MERGED",
+	"closed": "CLOSED",
+	"locked": "CLOSED",
+}
+
+// parseGitLabMergeRequests returns the first merge request in a list response, or nil if the
+// list is empty.
+func parseGitLabMergeRequests(data []byte) (*PullRequest, error) {
+	var mergeRequests []struct {
+		IID          int    `json:"iid"`
+		Title        string `json:"title"`
+		Description  string `json:"description"`
+		State        string `json:"state"`
+		SourceBranch string `json:"source_branch"`
+		TargetBranch string `json:"target_branch"`
+		WebURL       string `json:"web_url"`
+		SHA          string `json:"sha"`
+	}
+	if err := json.Unmarshal(data, &mergeRequests); err != nil {
+		return nil, fmt.Errorf("failed to parse merge request data: %w", err)
+	}
+	if len(mergeRequests) == 0 {
+		return nil, nil
+	}
+
+	mr := mergeRequests[0]
+	return &PullRequest{
+		Number:  mr.IID,
+		Title:   mr.Title,
+		Body:    mr.Description,
+		State:   gitlabStates[mr.State],
+		HeadRef: mr.SourceBranch,
+		BaseRef: mr.TargetBranch,
+		URL:     mr.WebURL,
+		HeadSHA: mr.SHA,
+	}, nil
+}
+
+// parseGitLabStatus builds a PRStatus from a single merge request response and its approvals
+// response, which may be nil.
+func parseGitLabStatus(mergeRequest, approvals []byte) (*PRStatus, error) {
+	var mrData struct {
+		IID                 int    `json:"iid"`
+		State               string `json:"state"`
+		WebURL              string `json:"web_url"`
+		DetailedMergeStatus string `json:"detailed_merge_status"`
+		HeadPipeline        *struct {
+			Status string `json:"status"`
+		} `json:"head_pipeline"`
+	}
+	if err := json.Unmarshal(mergeRequest, &mrData); err != nil {
+		return nil, fmt.Errorf("failed to parse merge request status: %w", err)
+	}
+
+	status := &PRStatus{
+		Number: mrData.IID,
+		State:  gitlabStates[mrData.State],
+		URL:    mrData.WebURL,
+	}
+
+	if approvals != nil {
+		var approvalData struct {
+			Approved      bool `json:"approved"`
+			ApprovalsLeft int  `json:"approvals_left"`
+			ApprovedBy    []struct {
+				User struct {
+					Username string `json:"username"`
+				} `json:"user"`
+			} `json:"approved_by"`
+		}
+		if err := json.Unmarshal(approvals, &approvalData); err != nil {
+			return nil, fmt.Errorf("failed to parse merge request approvals: %w", err)
+		}
+		status.Approvals = len(approvalData.ApprovedBy)
+		switch {
+		case approvalData.ApprovalsLeft > 0:
+			status.ReviewDecision = "REVIEW_REQUIRED"
+		case approvalData.Approved && status.Approvals > 0:
+			status.ReviewDecision = "APPROVED"
+		}
+	}
+	if mrData.DetailedMergeStatus == "requested_changes" {
+		status.ChangesRequested = 1
+		status.ReviewDecision = "CHANGES_REQUESTED"
+	}
+
+	if mrData.HeadPipeline == nil {
+		status.CIState = CIStateNone
+		return status, nil
+	}
+	switch mrData.HeadPipeline.Status {
+	case "success", "skipped":
+		status.CIState = CIStateSuccess
+	case "failed", "canceled":
+		status.CIState = CIStateFailure
+	default:
+		// created, pending, running, manual, scheduled and the like haven't finished
+		status.CIState = CIStatePending
+	}
+	return status, nil
+}
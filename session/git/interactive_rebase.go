@@ -0,0 +1,191 @@
+package git
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// RebaseAction is what an interactive rebase does with a commit.
+type RebaseAction string
+
+const (
+	// RebasePick keeps the commit as it is.
+	RebasePick RebaseAction = "pick"
+	// RebaseReword keeps the commit with a new message.
+	RebaseReword RebaseAction = "reword"
+	// RebaseSquash melds the commit into the one before it, joining their messages.
+	RebaseSquash RebaseAction = "squash"
+	// RebaseDrop removes the commit.
+	RebaseDrop RebaseAction = "drop"
+)
+
+// RebaseStep is a commit of an interactive rebase and what to do with it.
+type RebaseStep struct {
+	Commit CommitInfo
+	Action RebaseAction
+	// Message is the commit's full message, the new one when rewording
+	Message string
+}
+
+// rebaseBase returns the commit the branch forked from main at, which an interactive rebase
+// replays the branch's commits onto.
+func (g *GitWorktree) rebaseBase() (string, error) {
+	base, err := g.runGitCommand(g.worktreePath, "merge-base", g.mainRef(), "HEAD")
+	if err != nil {
+		return "", fmt.Errorf("failed to find where the branch forked from %s: %w", g.mainRef(), err)
+	}
+	return strings.TrimSpace(base), nil
+}
+
+// RebaseSteps returns the branch's commits since it forked from main, oldest first and all
+// picked, to edit and pass to RewriteCommits. Branches with merge commits can't be rewritten this
+// way.
+func (g *GitWorktree) RebaseSteps() ([]RebaseStep, error) {
+	if err := g.requireLocal("rewrite its commits"); err != nil {
+		return nil, err
+	}
+	base, err := g.rebaseBase()
+	if err != nil {
+		return nil, err
+	}
+	merges, err := g.runGitCommand(g.worktreePath, "rev-list", "--merges", base+"..HEAD")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list the branch's merge commits: %w", err)
+	}
+	if strings.TrimSpace(merges) != "" {
+		return nil, fmt.Errorf("the branch has merge commits, which an interactive rebase would flatten")
+	}
+	output, err := g.runGitCommand(g.worktreePath, "log", "--reverse", "--format=%H%x00%h%x00%s%x00%B%x1e", base+"..HEAD")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list the branch's commits: %w", err)
+	}
+	return parseRebaseLog(output), nil
+}
+
+// parseRebaseLog parses git log output in the format "%H%x00%h%x00%s%x00%B%x1e" into picked steps.
+func parseRebaseLog(output string) []RebaseStep {
+	var steps []RebaseStep
+	for _, record := range strings.Split(output, "\x1e") {
+		fields := strings.SplitN(strings.TrimLeft(record, "\n"), "\x00", 4)
+		if len(fields) != 4 {
+			continue
+		}
+		steps = append(steps, RebaseStep{
+			Commit:  CommitInfo{Hash: fields[0], ShortHash: fields[1], Subject: fields[2]},
+			Action:  RebasePick,
+			Message: strings.TrimSpace(fields[3]),
+		})
+	}
+	return steps
+}
+
+// RewriteCommits rebases the branch's commits onto where it forked from main in the order of
+// steps, doing each step's action. Uncommitted changes are stashed and reapplied. A rebase that
+// stops, e.g. on conflicts from reordering, is aborted, leaving the branch as it was.
+func (g *GitWorktree) RewriteCommits(steps []RebaseStep) error {
+	if g.IsRebaseInProgress() {
+		return fmt.Errorf("a rebase is already in progress in the worktree")
+	}
+	current, err := g.RebaseSteps()
+	if err != nil {
+		return err
+	}
+	if err := validateRebaseSteps(current, steps); err != nil {
+		return err
+	}
+	base, err := g.rebaseBase()
+	if err != nil {
+		return err
+	}
+
+	dir, err := os.MkdirTemp("", "claudesquad-rebase-")
+	if err != nil {
+		return fmt.Errorf("failed to create the rebase todo directory: %w", err)
+	}
+	defer os.RemoveAll(dir)
+	todo, err := writeRebaseTodo(dir, steps)
+	if err != nil {
+		return err
+	}
+
+	// Git hands the todo it generated to the sequence editor, which replaces it with ours, and
+	// keeps the joined message of squashed commits as the editor does nothing
+	if _, err := g.runGitCommand(g.worktreePath,
+		"-c", "sequence.editor="+formatCommand("cp", todo),
+		"-c", "core.editor=true",
+		"rebase", "-i", "--autostash", base); err != nil {
+		if g.IsRebaseInProgress() {
+			g.runGitCommand(g.worktreePath, "rebase", "--abort")
+		}
+		return fmt.Errorf("failed to rewrite the branch's commits: %w", err)
+	}
+	return nil
+}
+
+// validateRebaseSteps checks that the steps list each of the branch's commits once, keep at
+// least one, and only squash into a kept commit.
+func validateRebaseSteps(current, steps []RebaseStep) error {
+	listed := make(map[string]bool, len(current))
+	for _, step := range current {
+		listed[step.Commit.Hash] = true
+	}
+	if len(steps) != len(current) {
+		return fmt.Errorf("the branch's commits changed since they were listed")
+	}
+	kept := false
+	for _, step := range steps {
+		if !listed[step.Commit.Hash] {
+			return fmt.Errorf("the branch's commits changed since they were listed")
+		}
+		delete(listed, step.Commit.Hash)
+		switch step.Action {
+		case RebaseDrop:
+			continue
+		case RebaseSquash:
+			if !kept {
+				return fmt.Errorf("cannot squash %s: no kept commit comes before it", step.Commit.ShortHash)
+			}
+		case RebaseReword:
+			if strings.TrimSpace(step.Message) == "" {
+				return fmt.Errorf("cannot reword %s with an empty message", step.Commit.ShortHash)
+			}
+		case RebasePick:
+		default:
+			return fmt.Errorf("unknown rebase action %q", step.Action)
+		}
+		kept = true
+	}
+	if !kept {
+		return fmt.Errorf("cannot drop every commit of the branch")
+	}
+	return nil
+}
+
+// writeRebaseTodo writes the todo list of the steps, and the messages of the commits reworded,
+// to dir, returning the todo's path. Rewording amends the commit with its message file after
+// picking it, rather than asking an editor.
+func writeRebaseTodo(dir string, steps []RebaseStep) (string, error) {
+	var todo strings.Builder
+	for i, step := range steps {
+		action := step.Action
+		if action == RebaseReword {
+			action = RebasePick
+		}
+		fmt.Fprintf(&todo, "%s %s %s\n", action, step.Commit.Hash, step.Commit.Subject)
+		if step.Action != RebaseReword {
+			continue
+		}
+		message := filepath.Join(dir, fmt.Sprintf("message-%d", i))
+		if err := os.WriteFile(message, []byte(step.Message), 0644); err != nil {
+			return "", fmt.Errorf("failed to write the message of %s: %w", step.Commit.ShortHash, err)
+		}
+		fmt.Fprintf(&todo, "exec %s\n", formatCommand("git", "commit", "-q", "--amend", "--allow-empty", "--no-verify", "-F", message))
+	}
+	path := filepath.Join(dir, "todo")
+	if err := os.WriteFile(path, []byte(todo.String()), 0644); err != nil {
+		return "", fmt.Errorf("failed to write the rebase todo: %w", err)
+	}
+	return path, nil
+}
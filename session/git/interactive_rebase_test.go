@@ -0,0 +1,72 @@
+package git
+
+import (
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRewriteCommits(t *testing.T) {
+	for _, name := range []string{"GIT_AUTHOR_NAME", "GIT_COMMITTER_NAME"} {
+		t.Setenv(name, "test")
+	}
+	for _, name := range []string{"GIT_AUTHOR_EMAIL", "GIT_COMMITTER_EMAIL"} {
+		t.Setenv(name, "test@example.com")
+	}
+	repo := filepath.Join(t.TempDir(), "repo")
+	initRepo(t, repo)
+	if output, err := exec.Command("git", "-C", repo, "checkout", "-q", "-b", "feature").CombinedOutput(); err != nil {
+		t.Fatalf("git checkout: %v\n%s", err, output)
+	}
+	for _, message := range []string{"one", "two", "three", "four"} {
+		commitEmpty(t, repo, message)
+	}
+	g := &GitWorktree{repoPath: repo, worktreePath: repo, branchName: "feature"}
+
+	commits, err := g.RebaseSteps()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(commits) != 4 || commits[0].Message != "one" || commits[3].Commit.Subject != "four" {
+		t.Fatalf("RebaseSteps() = %+v, want one to four oldest first", commits)
+	}
+
+	// Squashing the first commit has nothing to squash into
+	invalid := []RebaseStep{
+		{Commit: commits[0].Commit, Action: RebaseSquash},
+		{Commit: commits[1].Commit, Action: RebasePick},
+		{Commit: commits[2].Commit, Action: RebasePick},
+		{Commit: commits[3].Commit, Action: RebasePick},
+	}
+	if err := g.RewriteCommits(invalid); err == nil {
+		t.Fatal("RewriteCommits() squashed the first commit")
+	}
+	if err := g.RewriteCommits(invalid[1:]); err == nil {
+		t.Fatal("RewriteCommits() accepted steps missing a commit")
+	}
+
+	steps := []RebaseStep{
+		{Commit: commits[2].Commit, Action: RebaseReword, Message: "three, reworded"},
+		{Commit: commits[0].Commit, Action: RebasePick},
+		{Commit: commits[3].Commit, Action: RebaseSquash},
+		{Commit: commits[1].Commit, Action: RebaseDrop},
+	}
+	if err := g.RewriteCommits(steps); err != nil {
+		t.Fatal(err)
+	}
+	output, err := exec.Command("git", "-C", repo, "log", "--reverse", "--format=%B%x00", "main..HEAD").Output()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var messages []string
+	for _, message := range strings.Split(string(output), "\x00") {
+		if message = strings.TrimSpace(message); message != "" {
+			messages = append(messages, message)
+		}
+	}
+	if len(messages) != 2 || messages[0] != "three, reworded" || !strings.HasPrefix(messages[1], "one") ||
+		!strings.Contains(messages[1], "four") {
+		t.Fatalf("messages after the rebase = %q, want the reworded three then one squashed with four", messages)
+	}
+}
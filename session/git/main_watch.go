@@ -0,0 +1,57 @@
+package git
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// FetchMain fetches the main branch from origin. The remote-tracking branch is shared by all
+// worktrees of the repository, so one fetch updates them all.
+func (g *GitWorktree) FetchMain() error {
+	if err := g.requireRemote("fetch main"); err != nil {
+		return err
+	}
+	mainBranch := g.getMainBranch()
+	refspec := fmt.Sprintf("+refs/heads/%s:refs/remotes/origin/%s", mainBranch, mainBranch)
+	if _, err := g.runGitCommand(g.worktreePath, "fetch", "--quiet", "origin", refspec); err != nil {
+		return fmt.Errorf("failed to fetch %s from origin: %w", mainBranch, err)
+	}
+	return nil
+}
+
+// BehindMain returns how many commits of origin's main branch, as of the last fetch, the branch
+// doesn't have.
+func (g *GitWorktree) BehindMain() (int, error) {
+	output, err := g.runGitCommand(g.worktreePath, "rev-list", "--count", "HEAD..origin/"+g.getMainBranch())
+	if err != nil {
+		return 0, fmt.Errorf("failed to count the commits behind main: %w", err)
+	}
+	count, err := strconv.Atoi(strings.TrimSpace(output))
+	if err != nil {
+		return 0, fmt.Errorf("failed to parse the commits behind main: %w", err)
+	}
+	return count, nil
+}
+
+// AutoRebaseWithMain rebases the branch onto origin's main branch as of the last fetch, for
+// rebases nobody is watching: unlike RebaseWithMain it gives up on conflicts, aborting the rebase
+// so the branch is left as it was to be updated by hand.
+func (g *GitWorktree) AutoRebaseWithMain() error {
+	if err := g.requireRemote("update with main"); err != nil {
+		return err
+	}
+	backupBranch, _, err := g.ensureBackupBranch()
+	if err != nil {
+		return err
+	}
+
+	mainBranch := g.getMainBranch()
+	if _, err := g.runGitCommand(g.worktreePath, "rebase", "origin/"+mainBranch); err != nil {
+		g.runGitCommand(g.worktreePath, "rebase", "--abort")
+		return fmt.Errorf("rebase onto origin/%s stopped on conflicts and was aborted. Backup branch created: %s. Error: %w",
+			mainBranch, backupBranch, err)
+	}
+	g.pruneBackupsAfterUpdate(backupBranch)
+	return nil
+}
@@ -0,0 +1,54 @@
+package git
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestAutoRebaseWithMain(t *testing.T) {
+	repo, g, git := initFeatureWorktree(t, t.TempDir())
+	commit := func(dir, file, content string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(dir, file), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		git("-C", dir, "add", ".")
+		git("-C", dir, "commit", "-q", "-m", "change "+file)
+	}
+	commit(g.GetWorktreePath(), "feature.txt", "feature")
+
+	// main moves on with a change that doesn't touch the branch's files
+	commit(repo, "main.txt", "main")
+	git("-C", repo, "push", "-q", "origin", "main")
+	if err := g.FetchMain(); err != nil {
+		t.Fatal(err)
+	}
+	if behind, err := g.BehindMain(); err != nil || behind != 1 {
+		t.Fatalf("BehindMain() = %d, %v; want 1", behind, err)
+	}
+	if err := g.AutoRebaseWithMain(); err != nil {
+		t.Fatal(err)
+	}
+	if behind, err := g.BehindMain(); err != nil || behind != 0 {
+		t.Fatalf("BehindMain() after the rebase = %d, %v; want 0", behind, err)
+	}
+
+	// A conflicting change aborts the rebase, leaving the branch as it was
+	commit(g.GetWorktreePath(), "main.txt", "feature's version")
+	head := git("-C", g.GetWorktreePath(), "rev-parse", "HEAD")
+	commit(repo, "main.txt", "main's version")
+	git("-C", repo, "push", "-q", "origin", "main")
+	if err := g.FetchMain(); err != nil {
+		t.Fatal(err)
+	}
+	if err := g.AutoRebaseWithMain(); err == nil {
+		t.Fatal("AutoRebaseWithMain() succeeded despite conflicts")
+	}
+	if g.IsRebaseInProgress() || git("-C", g.GetWorktreePath(), "rev-parse", "HEAD") != head {
+		t.Error("AutoRebaseWithMain() didn't leave the branch as it was")
+	}
+	if behind, err := g.BehindMain(); err != nil || behind != 1 {
+		t.Errorf("BehindMain() after the failed rebase = %d, %v; want 1", behind, err)
+	}
+}
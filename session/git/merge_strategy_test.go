@@ -0,0 +1,64 @@
+package git
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestParseMergeStrategy(t *testing.T) {
+	tests := []struct {
+		name    string
+		want    MergeStrategy
+		wantErr bool
+	}{
+		{"", MergeStrategyRebase, false},
+		{"rebase", MergeStrategyRebase, false},
+		{"merge", MergeStrategyMerge, false},
+		{" Squash ", MergeStrategySquash, false},
+		{"octopus", "", true},
+	}
+	for _, tt := range tests {
+		got, err := ParseMergeStrategy(tt.name)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseMergeStrategy(%q) error = %v, wantErr %v", tt.name, err, tt.wantErr)
+		}
+		if got != tt.want {
+			t.Errorf("ParseMergeStrategy(%q) = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestSquashWithMain(t *testing.T) {
+	dir := t.TempDir()
+	repo, g, git := initFeatureWorktree(t, dir)
+	worktree := g.GetWorktreePath()
+	commit := func(dir, name, message string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(name+"\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		git("-C", dir, "add", name)
+		git("-C", dir, "commit", "-q", "-m", message)
+	}
+	commit(worktree, "one.txt", "add one")
+	commit(worktree, "two.txt", "add two")
+	commit(repo, "main.txt", "add main")
+	git("-C", repo, "push", "-q", "origin", "main")
+
+	if err := g.MergeWithMain(MergeStrategySquash); err != nil {
+		t.Fatalf("MergeWithMain(squash) failed: %v", err)
+	}
+
+	// One commit with the branch's changes, on top of main so it's recorded as merged
+	if parent, main := git("-C", worktree, "rev-parse", "HEAD^"), git("-C", worktree, "rev-parse", "origin/main"); parent != main {
+		t.Errorf("the squashed commit's parent is %s, want origin/main at %s", parent, main)
+	}
+	if files := git("-C", worktree, "ls-files"); !strings.Contains(files, "main.txt") || !strings.Contains(files, "two.txt") {
+		t.Errorf("files after the squash:\n%s", files)
+	}
+	if message := git("-C", worktree, "log", "-1", "--format=%B"); !strings.Contains(message, "- add one\n- add two") {
+		t.Errorf("squashed commit message:\n%s", message)
+	}
+}
@@ -0,0 +1,98 @@
+package git
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// MergeFiles are the files a merge tool resolves one conflicted file with.
+type MergeFiles struct {
+	// Base, Local and Remote are temporary copies of the common ancestor, our version (the side
+	// being rebased onto) and theirs (the commit being replayed). A version missing because one
+	// side added or deleted the file is an empty file.
+	Base, Local, Remote string
+	// Merged is the conflicted file in the worktree, which the tool writes the result to
+	Merged string
+	// dir holds the temporary copies
+	dir string
+}
+
+// Cleanup removes the temporary copies.
+func (f *MergeFiles) Cleanup() error {
+	if err := os.RemoveAll(f.dir); err != nil {
+		return fmt.Errorf("failed to remove merge files: %w", err)
+	}
+	return nil
+}
+
+// ExtractMergeFiles writes the base, our and their versions of a conflicted file to temporary
+// files for a merge tool. Call Cleanup on the result once the tool is done.
+func (g *GitWorktree) ExtractMergeFiles(path string) (*MergeFiles, error) {
+	if err := g.requireLocal("open a merge tool on it"); err != nil {
+		return nil, err
+	}
+	dir, err := os.MkdirTemp("", "claude-squad-merge-")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create merge directory: %w", err)
+	}
+	files := &MergeFiles{Merged: filepath.Join(g.worktreePath, path), dir: dir}
+
+	// Name the copies after the file, keeping its extension for the tool's syntax highlighting
+	ext := filepath.Ext(path)
+	name := strings.TrimSuffix(filepath.Base(path), ext)
+	for _, version := range []struct {
+		stage, label string
+		dest         *string
+	}{
+		{"1", "BASE", &files.Base},
+		{"2", "LOCAL", &files.Local},
+		{"3", "REMOTE", &files.Remote},
+	} {
+		*version.dest = filepath.Join(dir, name+"."+version.label+ext)
+		content, err := g.runGitCommand(g.worktreePath, "show", ":"+version.stage+":"+path)
+		if err != nil {
+			content = ""
+		}
+		if err := os.WriteFile(*version.dest, []byte(content), 0644); err != nil {
+			_ = files.Cleanup()
+			return nil, fmt.Errorf("failed to write %s version of %s: %w", strings.ToLower(version.label), path, err)
+		}
+	}
+	return files, nil
+}
+
+// MergeToolArgs expands the merge command for the files, replacing $BASE, $LOCAL, $REMOTE and
+// $MERGED as git mergetool does, and splits it into the program and its arguments.
+func MergeToolArgs(command string, files *MergeFiles) ([]string, error) {
+	parts := strings.Fields(command)
+	if len(parts) == 0 {
+		return nil, fmt.Errorf("empty merge command")
+	}
+	if !strings.Contains(command, "$MERGED") {
+		return nil, fmt.Errorf("merge command %q must pass $MERGED, the file to write the result to", command)
+	}
+	replacer := strings.NewReplacer("$BASE", files.Base, "$LOCAL", files.Local, "$REMOTE", files.Remote,
+		"$MERGED", files.Merged)
+	for i, part := range parts {
+		parts[i] = replacer.Replace(part)
+	}
+	return parts, nil
+}
+
+// StageMergedFile stages a file a merge tool resolved, unless conflict markers remain in it. It
+// returns whether the file was staged.
+func (g *GitWorktree) StageMergedFile(path string) (bool, error) {
+	data, err := os.ReadFile(filepath.Join(g.worktreePath, path))
+	if err != nil {
+		return false, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	if len(ParseConflicts(string(data))) > 0 {
+		return false, nil
+	}
+	if _, err := g.runGitCommand(g.worktreePath, "add", "--", path); err != nil {
+		return false, fmt.Errorf("failed to stage %s: %w", path, err)
+	}
+	return true, nil
+}
@@ -0,0 +1,73 @@
+package git
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestMergeToolArgs(t *testing.T) {
+	files := &MergeFiles{Base: "/tmp/a.BASE.go", Local: "/tmp/a.LOCAL.go", Remote: "/tmp/a.REMOTE.go", Merged: "/repo/my file.go"}
+
+	args, err := MergeToolArgs("meld $LOCAL $BASE $REMOTE --output=$MERGED", files)
+	want := []string{"meld", "/tmp/a.LOCAL.go", "/tmp/a.BASE.go", "/tmp/a.REMOTE.go", "--output=/repo/my file.go"}
+	if err != nil || !reflect.DeepEqual(args, want) {
+		t.Errorf("MergeToolArgs() = %q, %v, want %q", args, err, want)
+	}
+
+	if _, err := MergeToolArgs("meld $LOCAL $REMOTE", files); err == nil {
+		t.Error("MergeToolArgs() accepted a command without $MERGED")
+	}
+}
+
+func TestExtractMergeFiles(t *testing.T) {
+	repo := filepath.Join(t.TempDir(), "repo")
+	initRepo(t, repo)
+	git := func(args ...string) {
+		t.Helper()
+		args = append([]string{"-C", repo, "-c", "user.name=test", "-c", "user.email=test@example.com"}, args...)
+		if output, err := exec.Command("git", args...).CombinedOutput(); err != nil {
+			t.Fatalf("git %s: %v\n%s", strings.Join(args, " "), err, output)
+		}
+	}
+	write := func(content string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(repo, "notes.txt"), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("base\n")
+	git("add", "notes.txt")
+	git("commit", "-q", "-m", "base")
+	git("checkout", "-q", "-b", "other")
+	write("theirs\n")
+	git("commit", "-q", "-am", "theirs")
+	git("checkout", "-q", "main")
+	write("ours\n")
+	git("commit", "-q", "-am", "ours")
+	// The merge stops on the conflict, which is what the test needs
+	_ = exec.Command("git", "-C", repo, "-c", "user.name=test", "-c", "user.email=test@example.com", "merge", "-q", "other").Run()
+
+	g := &GitWorktree{repoPath: repo, worktreePath: repo}
+	files, err := g.ExtractMergeFiles("notes.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer files.Cleanup()
+	for path, want := range map[string]string{files.Base: "base\n", files.Local: "ours\n", files.Remote: "theirs\n"} {
+		if data, err := os.ReadFile(path); err != nil || string(data) != want {
+			t.Errorf("%s = %q, %v, want %q", filepath.Base(path), data, err, want)
+		}
+	}
+
+	if staged, err := g.StageMergedFile("notes.txt"); err != nil || staged {
+		t.Errorf("StageMergedFile() with markers left = %v, %v, want it left unstaged", staged, err)
+	}
+	write("merged\n")
+	if staged, err := g.StageMergedFile("notes.txt"); err != nil || !staged {
+		t.Errorf("StageMergedFile() = %v, %v, want it staged", staged, err)
+	}
+}
@@ -0,0 +1,169 @@
+package git
+
+import (
+	"claude-squad/config"
+	"fmt"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// PatchApplyResult describes the outcome of applying a patch to the main repository
+type PatchApplyResult struct {
+	// Conflicts lists files that were applied with conflict markers
+	Conflicts []string
+}
+
+// Patch returns the branch's changes since the base commit, including uncommitted and
+// untracked files, as a patch that can be applied with git apply.
+func (g *GitWorktree) Patch() (string, error) {
+	if g.worktreeMissing() {
+		return "", fmt.Errorf("worktree does not exist: %s", g.worktreePath)
+	}
+
+	// Untracked files are staged in a copy of the index, so the worktree's own index, and what
+	// the user has staged, is left alone
+	index, err := g.copyIndex()
+	if err != nil {
+		return "", err
+	}
+	defer g.removeFile(index)
+
+	// -N stages untracked files (intent to add), including them in the diff
+	if output, err := g.indexCommand(index, "add", "-N", ".").CombinedOutput(); err != nil {
+		return "", fmt.Errorf("failed to stage untracked files: %s (%w)", strings.TrimSpace(string(output)), err)
+	}
+
+	// Use Output rather than CombinedOutput so stderr noise can't end up in the patch
+	output, err := g.indexCommand(index, "--no-pager", "diff", "--binary", g.GetBaseCommitSHA()).Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to generate patch: %w", err)
+	}
+	return string(output), nil
+}
+
+// copyIndex copies the worktree's index next to it, on the machine the worktree is on, and
+// returns the copy's path.
+func (g *GitWorktree) copyIndex() (string, error) {
+	output, err := g.runGitCommand(g.worktreePath, "rev-parse", "--git-path", "index")
+	if err != nil {
+		return "", err
+	}
+	index := strings.TrimSpace(output)
+	if g.runner != nil {
+		if !path.IsAbs(index) {
+			index = path.Join(g.worktreePath, index)
+		}
+		copied := fmt.Sprintf("%s.patch-%x", index, time.Now().UnixNano())
+		if output, err := g.command("cp", index, copied).CombinedOutput(); err != nil {
+			return "", fmt.Errorf("failed to copy the index: %s (%w)", strings.TrimSpace(string(output)), err)
+		}
+		return copied, nil
+	}
+
+	if !filepath.IsAbs(index) {
+		index = filepath.Join(g.worktreePath, index)
+	}
+	data, err := os.ReadFile(index)
+	if err != nil {
+		return "", fmt.Errorf("failed to copy the index: %w", err)
+	}
+	copied := fmt.Sprintf("%s.patch-%x", index, time.Now().UnixNano())
+	if err := os.WriteFile(copied, data, 0644); err != nil {
+		return "", fmt.Errorf("failed to copy the index: %w", err)
+	}
+	return copied, nil
+}
+
+// indexCommand returns a git command run in the worktree with index as its index file.
+func (g *GitWorktree) indexCommand(index string, args ...string) *exec.Cmd {
+	args = append([]string{"-C", g.worktreePath}, args...)
+	if g.runner != nil {
+		// The environment doesn't reach the other machine, so it's set there
+		return g.command("env", append([]string{"GIT_INDEX_FILE=" + index, "git"}, args...)...)
+	}
+	cmd := exec.Command("git", args...)
+	cmd.Env = append(os.Environ(), "GIT_INDEX_FILE="+index)
+	return cmd
+}
+
+// ExportPatch writes the branch's changes to a .patch file in the patches directory and
+// returns its path.
+func (g *GitWorktree) ExportPatch() (string, error) {
+	patch, err := g.Patch()
+	if err != nil {
+		return "", err
+	}
+	if patch == "" {
+		return "", fmt.Errorf("no changes to export on branch %s", g.branchName)
+	}
+
+	configDir, err := config.GetConfigDir()
+	if err != nil {
+		return "", err
+	}
+	patchDir := filepath.Join(configDir, "patches")
+	if err := os.MkdirAll(patchDir, 0755); err != nil {
+		return "", fmt.Errorf("failed to create patches directory: %w", err)
+	}
+
+	name := fmt.Sprintf("%s-%s.patch", strings.ReplaceAll(g.branchName, "/", "-"), time.Now().Format("20060102-150405"))
+	patchPath := filepath.Join(patchDir, name)
+	if err := os.WriteFile(patchPath, []byte(patch), 0644); err != nil {
+		return "", fmt.Errorf("failed to write patch file: %w", err)
+	}
+	return patchPath, nil
+}
+
+// ApplyPatchToRepo applies the branch's changes onto the main repository checkout with
+// git apply --3way, without merging the branch. Files that could not be applied cleanly are
+// left with conflict markers and reported in the result.
+func (g *GitWorktree) ApplyPatchToRepo() (*PatchApplyResult, error) {
+	if err := g.requireLocal("apply its patch to the repository"); err != nil {
+		return nil, err
+	}
+	patch, err := g.Patch()
+	if err != nil {
+		return nil, err
+	}
+	if patch == "" {
+		return nil, fmt.Errorf("no changes to apply on branch %s", g.branchName)
+	}
+
+	patchFile, err := os.CreateTemp("", "claude-squad-*.patch")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create temp patch file: %w", err)
+	}
+	defer os.Remove(patchFile.Name())
+
+	if _, err := patchFile.WriteString(patch); err != nil {
+		patchFile.Close()
+		return nil, fmt.Errorf("failed to write temp patch file: %w", err)
+	}
+	patchFile.Close()
+
+	// Run git directly rather than through runGitCommand, which truncates the output we need
+	// to find every conflicted file
+	cmd := exec.Command("git", "-C", g.repoPath, "apply", "--3way", patchFile.Name())
+	output, err := cmd.CombinedOutput()
+	result := &PatchApplyResult{Conflicts: parseApplyConflicts(string(output))}
+	if err != nil && len(result.Conflicts) == 0 {
+		return nil, fmt.Errorf("failed to apply patch to %s: %s (%w)", g.repoPath, strings.TrimSpace(string(output)), err)
+	}
+	return result, nil
+}
+
+// parseApplyConflicts extracts the conflicted files ("U <path>" lines) from git apply --3way output
+func parseApplyConflicts(output string) []string {
+	var conflicts []string
+	for _, line := range strings.Split(output, "\n") {
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(line, "U ") {
+			conflicts = append(conflicts, strings.TrimSpace(strings.TrimPrefix(line, "U ")))
+		}
+	}
+	return conflicts
+}
@@ -0,0 +1,87 @@
+package git
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestPatch(t *testing.T) {
+	repo := filepath.Join(t.TempDir(), "repo")
+	initRepo(t, repo)
+	git := func(args ...string) string {
+		t.Helper()
+		output, err := exec.Command("git", append([]string{"-C", repo, "-c", "user.name=test", "-c", "user.email=test@example.com"}, args...)...).CombinedOutput()
+		if err != nil {
+			t.Fatalf("git %s: %v\n%s", strings.Join(args, " "), err, output)
+		}
+		return string(output)
+	}
+	write := func(name, content string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(repo, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("tracked.txt", "one\n")
+	git("add", ".")
+	git("commit", "-q", "-m", "add tracked.txt")
+	base := strings.TrimSpace(git("rev-parse", "HEAD"))
+
+	write("tracked.txt", "two\n")
+	write("staged.txt", "staged\n")
+	git("add", "staged.txt")
+	write("untracked.txt", "untracked\n")
+	status := git("status", "--porcelain")
+
+	for _, tree := range []*GitWorktree{
+		{repoPath: repo, worktreePath: repo, baseCommitSHA: base},
+		{repoPath: repo, worktreePath: repo, baseCommitSHA: base, runner: &recordingRunner{}},
+	} {
+		patch, err := tree.Patch()
+		if err != nil {
+			t.Fatalf("Patch() (remote %v) failed: %v", tree.IsRemote(), err)
+		}
+		for _, name := range []string{"tracked.txt", "staged.txt", "untracked.txt"} {
+			if !strings.Contains(patch, "b/"+name) {
+				t.Errorf("Patch() (remote %v) is missing %s:\n%s", tree.IsRemote(), name, patch)
+			}
+		}
+
+		// The index, and so what's staged, is left as it was, with no copy of it left behind
+		if after := git("status", "--porcelain"); after != status {
+			t.Errorf("Patch() (remote %v) changed the status from\n%s\nto\n%s", tree.IsRemote(), status, after)
+		}
+		copies, err := filepath.Glob(filepath.Join(repo, ".git", "index.patch-*"))
+		if err != nil || len(copies) != 0 {
+			t.Errorf("Patch() (remote %v) left index copies %v (%v)", tree.IsRemote(), copies, err)
+		}
+	}
+}
+
+func TestParseApplyConflicts(t *testing.T) {
+	tests := []struct {
+		name   string
+		output string
+		want   []string
+	}{
+		{"clean", "Applied patch to 'a.txt' cleanly.\n", nil},
+		{"empty", "", nil},
+		{
+			"conflicts",
+			"Applied patch to 'a.txt' with conflicts.\nU a.txt\nApplied patch to 'dir/b c.txt' with conflicts.\nU dir/b c.txt\n",
+			[]string{"a.txt", "dir/b c.txt"},
+		},
+		{"indented", "  U a.txt  \r\n", []string{"a.txt"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parseApplyConflicts(tt.output); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseApplyConflicts() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
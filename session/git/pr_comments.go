@@ -39,6 +39,21 @@ type PRComment struct {
 	PlainBody    string         `json:"-"`
 	SplitPieces  []CommentPiece `json:"-"`
 	IsSplit      bool           `json:"-"`
+	// Set for comments already sent to an agent; LikelyAddressed once later changes touch their lines
+	Sent            bool `json:"-"`
+	LikelyAddressed bool `json:"-"`
+	// ThreadID is the GraphQL ID of the review thread a review comment starts, empty otherwise
+	ThreadID string `json:"-"`
+	// InReplyToID is the ID of the review comment starting the thread this one replies to
+	InReplyToID int `json:"in_reply_to_id,omitempty"`
+	// discussionID is the GitLab discussion the comment belongs to, which replies are posted to
+	discussionID string
+}
+
+// reviewThread is the review thread started by a review comment.
+type reviewThread struct {
+	ID       string
+	Resolved bool
 }
 
 type CommentPiece struct {
@@ -61,6 +76,7 @@ type PRReview struct {
 type PullRequest struct {
 	Number      int          `json:"number"`
 	Title       string       `json:"title"`
+	Body        string       `json:"body"`
 	State       string       `json:"state"`
 	HeadRef     string       `json:"headRef"`
 	BaseRef     string       `json:"baseRef"`
@@ -69,10 +85,26 @@ type PullRequest struct {
 	Comments    []*PRComment // Filtered comments (default view)
 	AllComments []*PRComment // All comments including outdated/resolved
 	Reviews     []PRReview
+
+	// forge is the code host the pull request was loaded from
+	forge Forge
 }
 
+// GetCurrentPR returns the pull request for the branch checked out in workingDir from the
+// repository's forge.
 func GetCurrentPR(workingDir string) (*PullRequest, error) {
-	cmd := exec.Command("gh", "pr", "view", "--json", "number,title,state,headRefName,baseRefName,url,headRefOid")
+	forge := ForgeFor(workingDir)
+	pr, err := forge.CurrentPullRequest(workingDir)
+	if err != nil {
+		return nil, err
+	}
+	pr.forge = forge
+	return pr, nil
+}
+
+// getGitHubPR returns the GitHub pull request for the branch checked out in workingDir.
+func getGitHubPR(workingDir string) (*PullRequest, error) {
+	cmd := exec.Command("gh", "pr", "view", "--json", "number,title,body,state,headRefName,baseRefName,url,headRefOid")
 	cmd.Dir = workingDir
 	output, err := cmd.CombinedOutput()
 	if err != nil {
@@ -94,6 +126,7 @@ func GetCurrentPR(workingDir string) (*PullRequest, error) {
 	var prData struct {
 		Number      int    `json:"number"`
 		Title       string `json:"title"`
+		Body        string `json:"body"`
 		State       string `json:"state"`
 		HeadRefName string `json:"headRefName"`
 		BaseRefName string `json:"baseRefName"`
@@ -108,6 +141,7 @@ func GetCurrentPR(workingDir string) (*PullRequest, error) {
 	pr := &PullRequest{
 		Number:  prData.Number,
 		Title:   prData.Title,
+		Body:    prData.Body,
 		State:   prData.State,
 		HeadRef: prData.HeadRefName,
 		BaseRef: prData.BaseRefName,
@@ -124,12 +158,32 @@ func (pr *PullRequest) FetchComments(workingDir string) error {
 	pr.AllComments = []*PRComment{}
 	pr.Reviews = []PRReview{}
 
+	if err := pr.forgeFor(workingDir).FetchComments(workingDir, pr); err != nil {
+		return err
+	}
+
+	// After fetching all comments, separate filtered from all
+	filteredComments := make([]*PRComment, 0, len(pr.AllComments))
+	for _, comment := range pr.AllComments {
+		// Filter out outdated, resolved, and gemini review comments
+		if !comment.IsOutdated && !comment.IsResolved && !comment.IsGeminiReview {
+			filteredComments = append(filteredComments, comment)
+		}
+	}
+	pr.Comments = filteredComments
+
+	return nil
+}
+
+// fetchGitHubComments fetches the reviews, review comments and issue comments of a GitHub pull
+// request into pr.AllComments and pr.Reviews.
+func (pr *PullRequest) fetchGitHubComments(workingDir string) error {
 	// First fetch resolved status for review threads
 	resolvedMap, err := pr.fetchResolvedStatus(workingDir)
 	if err != nil {
 		// Log but don't fail - resolved status is optional
 		fmt.Printf("Warning: Could not fetch resolved status: %v\n", err)
-		resolvedMap = make(map[int]bool)
+		resolvedMap = make(map[int]reviewThread)
 	}
 
 	// Fetch PR reviews first
@@ -143,21 +197,7 @@ func (pr *PullRequest) FetchComments(workingDir string) error {
 	}
 
 	// Fetch general issue comments
-	if err := pr.fetchIssueComments(workingDir); err != nil {
-		return err
-	}
-
-	// After fetching all comments, separate filtered from all
-	filteredComments := make([]*PRComment, 0, len(pr.AllComments))
-	for _, comment := range pr.AllComments {
-		// Filter out outdated, resolved, and gemini review comments
-		if !comment.IsOutdated && !comment.IsResolved && !comment.IsGeminiReview {
-			filteredComments = append(filteredComments, comment)
-		}
-	}
-	pr.Comments = filteredComments
-
-	return nil
+	return pr.fetchIssueComments(workingDir)
 }
 
 // paginatedGraphQLQuery executes a GraphQL query with pagination support
@@ -194,7 +234,8 @@ func paginatedGraphQLQuery(workingDir string, buildQuery func(cursor *string) st
 	return nil
 }
 
-func (pr *PullRequest) fetchResolvedStatus(workingDir string) (map[int]bool, error) {
+// fetchResolvedStatus returns the review threads keyed by the ID of the comment starting them.
+func (pr *PullRequest) fetchResolvedStatus(workingDir string) (map[int]reviewThread, error) {
 	// Get repository info first
 	repoCmd := exec.Command("gh", "repo", "view", "--json", "owner,name")
 	repoCmd.Dir = workingDir
@@ -214,8 +255,8 @@ func (pr *PullRequest) fetchResolvedStatus(workingDir string) (map[int]bool, err
 		return nil, fmt.Errorf("failed to parse repository info: %w", err)
 	}
 
-	// Build map of comment ID to resolved status
-	resolvedMap := make(map[int]bool)
+	// Build map of comment ID to its thread
+	resolvedMap := make(map[int]reviewThread)
 	pageSize := 100
 
 	// Build query function
@@ -283,7 +324,7 @@ func (pr *PullRequest) fetchResolvedStatus(workingDir string) (map[int]bool, err
 		for _, thread := range response.Data.Repository.PullRequest.ReviewThreads.Nodes {
 			if len(thread.Comments.Nodes) > 0 {
 				commentID := thread.Comments.Nodes[0].DatabaseID
-				resolvedMap[commentID] = thread.IsResolved
+				resolvedMap[commentID] = reviewThread{ID: thread.ID, Resolved: thread.IsResolved}
 			}
 		}
 
@@ -369,7 +410,7 @@ func (pr *PullRequest) fetchReviews(workingDir string) error {
 	return nil
 }
 
-func (pr *PullRequest) fetchReviewComments(workingDir string, resolvedMap map[int]bool) error {
+func (pr *PullRequest) fetchReviewComments(workingDir string, resolvedMap map[int]reviewThread) error {
 	cmd := exec.Command("gh", "api", fmt.Sprintf("repos/{owner}/{repo}/pulls/%d/comments", pr.Number))
 	cmd.Dir = workingDir
 	output, err := cmd.Output()
@@ -393,6 +434,7 @@ func (pr *PullRequest) fetchReviewComments(workingDir string, resolvedMap map[in
 		CommitID            string `json:"commit_id"`
 		OriginalCommitID    string `json:"original_commit_id"`
 		PullRequestReviewID int    `json:"pull_request_review_id"`
+		InReplyToID         int    `json:"in_reply_to_id"`
 	}
 
 	if err := json.Unmarshal(output, &reviewComments); err != nil {
@@ -418,7 +460,7 @@ func (pr *PullRequest) fetchReviewComments(workingDir string, resolvedMap map[in
 		isOutdated := rc.Position == nil || rc.CommitID != pr.HeadSHA
 
 		// Check if comment is resolved using the resolved map
-		isResolved := resolvedMap[rc.ID]
+		thread := resolvedMap[rc.ID]
 
 		line := 0
 		if rc.Line != nil {
@@ -442,9 +484,11 @@ func (pr *PullRequest) fetchReviewComments(workingDir string, resolvedMap map[in
 			OriginalPosition:    rc.OriginalPosition,
 			PullRequestReviewID: rc.PullRequestReviewID,
 			IsOutdated:          isOutdated,
-			IsResolved:          isResolved,
+			IsResolved:          thread.Resolved,
 			IsGeminiReview:      strings.Contains(rc.Body, GeminiReviewCommand),
 			Accepted:            false,
+			ThreadID:            thread.ID,
+			InReplyToID:         rc.InReplyToID,
 		}
 		pr.AllComments = append(pr.AllComments, comment)
 	}
@@ -612,10 +656,18 @@ func (pr *PullRequest) GetStatsForAllCommentsWithGemini() (total, reviews, revie
 
 // GetAllCommentStats returns statistics about all comments including filtered ones
 func (pr *PullRequest) GetAllCommentStats(workingDir string) (total, shown, outdated, resolved int, err error) {
+	if pr.forgeFor(workingDir).Name() != ForgeGitHub {
+		if err := pr.FetchComments(workingDir); err != nil {
+			return 0, 0, 0, 0, err
+		}
+		total, _, _, _, outdated, resolved = pr.GetStatsForAllComments()
+		return total, len(pr.Comments), outdated, resolved, nil
+	}
+
 	// We need to refetch to get complete stats including filtered comments
 	resolvedMap, err := pr.fetchResolvedStatus(workingDir)
 	if err != nil {
-		resolvedMap = make(map[int]bool)
+		resolvedMap = make(map[int]reviewThread)
 	}
 
 	// Count all review comments including filtered ones
@@ -639,7 +691,7 @@ func (pr *PullRequest) GetAllCommentStats(workingDir string) (total, shown, outd
 	for _, rc := range reviewComments {
 		total++
 		isOutdated := rc.Position == nil || rc.CommitID != pr.HeadSHA
-		isResolved := resolvedMap[rc.ID]
+		isResolved := resolvedMap[rc.ID].Resolved
 
 		if isOutdated {
 			outdated++
@@ -869,6 +921,11 @@ func matchesNumberedList(line string) bool {
 
 // GetUnresolvedThreads returns all unresolved review thread IDs
 func (pr *PullRequest) GetUnresolvedThreads(workingDir string) ([]string, error) {
+	return pr.forgeFor(workingDir).UnresolvedThreads(workingDir, pr)
+}
+
+// getGitHubUnresolvedThreads returns the IDs of the GitHub pull request's unresolved review threads.
+func (pr *PullRequest) getGitHubUnresolvedThreads(workingDir string) ([]string, error) {
 	// Get repository info first
 	repoCmd := exec.Command("gh", "repo", "view", "--json", "owner,name")
 	repoCmd.Dir = workingDir
@@ -982,6 +1039,11 @@ func (pr *PullRequest) GetUnresolvedThreads(workingDir string) ([]string, error)
 
 // ResolveThread resolves a specific review thread
 func (pr *PullRequest) ResolveThread(workingDir string, threadID string) error {
+	return pr.forgeFor(workingDir).ResolveThread(workingDir, pr, threadID)
+}
+
+// resolveGitHubThread resolves a GitHub review thread.
+func (pr *PullRequest) resolveGitHubThread(workingDir string, threadID string) error {
 	// Use GraphQL mutation to resolve the thread
 	mutation := `
 mutation($threadId: ID!) {
@@ -1030,3 +1092,109 @@ mutation($threadId: ID!) {
 
 	return nil
 }
+
+// ReplyToComment posts a reply to a comment and returns it.
+func (pr *PullRequest) ReplyToComment(workingDir string, comment *PRComment, body string) (*PRComment, error) {
+	if strings.TrimSpace(body) == "" {
+		return nil, fmt.Errorf("reply is empty")
+	}
+	return pr.forgeFor(workingDir).ReplyToComment(workingDir, pr, comment, body)
+}
+
+// replyToGitHubComment posts a reply on GitHub. Review comments get a reply in their thread;
+// reviews and general comments can't be replied to directly, so a general comment quoting the
+// original is posted instead.
+func (pr *PullRequest) replyToGitHubComment(workingDir string, comment *PRComment, body string) (*PRComment, error) {
+	endpoint := fmt.Sprintf("repos/{owner}/{repo}/issues/%d/comments", pr.Number)
+	replyType := "issue_comment"
+	if comment.Type == "review_comment" {
+		endpoint = fmt.Sprintf("repos/{owner}/{repo}/pulls/%d/comments/%d/replies", pr.Number, comment.ID)
+		replyType = "review_comment"
+	} else {
+		body = quoteReply(comment, body)
+	}
+
+	cmd := exec.Command("gh", "api", endpoint, "-X", "POST", "-f", "body="+body)
+	cmd.Dir = workingDir
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return nil, fmt.Errorf("failed to post reply (output: %s): %w", strings.TrimSpace(string(output)), err)
+	}
+
+	var posted struct {
+		ID   int    `json:"id"`
+		Body string `json:"body"`
+		User struct {
+			Login string `json:"login"`
+		} `json:"user"`
+		CreatedAt   time.Time `json:"created_at"`
+		UpdatedAt   time.Time `json:"updated_at"`
+		InReplyToID int       `json:"in_reply_to_id"`
+	}
+	if err := json.Unmarshal(output, &posted); err != nil {
+		return nil, fmt.Errorf("failed to parse reply response: %w", err)
+	}
+
+	reply := &PRComment{
+		ID:          posted.ID,
+		Body:        posted.Body,
+		Author:      posted.User.Login,
+		CreatedAt:   posted.CreatedAt,
+		UpdatedAt:   posted.UpdatedAt,
+		State:       "pending",
+		Type:        replyType,
+		InReplyToID: posted.InReplyToID,
+	}
+	if replyType == "review_comment" {
+		reply.Path = comment.Path
+		reply.Line = comment.Line
+		reply.CommitID = comment.CommitID
+		reply.Position = comment.Position
+		reply.ThreadID = comment.ThreadID
+	}
+	return reply, nil
+}
+
+// quoteReply prefixes a reply with the first lines of the comment it answers and mentions its
+// author, so the reply reads in context on the PR's conversation tab.
+func quoteReply(comment *PRComment, body string) string {
+	const quotedLines = 3
+	lines := strings.Split(strings.TrimSpace(comment.Body), "\n")
+	if len(lines) > quotedLines {
+		lines = append(lines[:quotedLines], "...")
+	}
+	var quoted strings.Builder
+	for _, line := range lines {
+		quoted.WriteString("> " + line + "\n")
+	}
+	return fmt.Sprintf("%s\n@%s %s", quoted.String(), comment.Author, body)
+}
+
+// AddReply inserts a posted reply after the comment it answers and any earlier replies in the
+// same thread, in both the filtered and the full comment lists.
+func (pr *PullRequest) AddReply(parent, reply *PRComment) {
+	pr.Comments = insertReply(pr.Comments, parent, reply)
+	pr.AllComments = insertReply(pr.AllComments, parent, reply)
+}
+
+func insertReply(comments []*PRComment, parent, reply *PRComment) []*PRComment {
+	root := parent.ID
+	if parent.InReplyToID != 0 {
+		root = parent.InReplyToID
+	}
+	at := -1
+	for i, comment := range comments {
+		if comment == parent {
+			at = i + 1
+		} else if at == i && comment.Type == parent.Type && comment.InReplyToID == root && root != 0 {
+			at = i + 1
+		}
+	}
+	if at < 0 {
+		return comments
+	}
+	comments = append(comments, nil)
+	copy(comments[at+1:], comments[at:])
+	comments[at] = reply
+	return comments
+}
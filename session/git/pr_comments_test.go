@@ -0,0 +1,42 @@
+package git
+
+import (
+	"testing"
+)
+
+func TestAddReply(t *testing.T) {
+	root := &PRComment{ID: 1, Type: "review_comment"}
+	earlier := &PRComment{ID: 2, Type: "review_comment", InReplyToID: 1}
+	other := &PRComment{ID: 3, Type: "review_comment"}
+	general := &PRComment{ID: 1, Type: "issue_comment"}
+	pr := &PullRequest{
+		Comments:    []*PRComment{root, earlier, other, general},
+		AllComments: []*PRComment{root, earlier, other, general},
+	}
+
+	reply := &PRComment{ID: 4, Type: "review_comment", InReplyToID: 1}
+	pr.AddReply(root, reply)
+	answer := &PRComment{ID: 5, Type: "issue_comment"}
+	pr.AddReply(general, answer)
+
+	want := []*PRComment{root, earlier, reply, other, general, answer}
+	for _, comments := range [][]*PRComment{pr.Comments, pr.AllComments} {
+		if len(comments) != len(want) {
+			t.Fatalf("got %d comments, want %d", len(comments), len(want))
+		}
+		for i := range want {
+			if comments[i] != want[i] {
+				t.Errorf("comment %d has ID %d, want %d", i, comments[i].ID, want[i].ID)
+			}
+		}
+	}
+}
+
+func TestQuoteReply(t *testing.T) {
+	comment := &PRComment{Author: "alice", Body: "one\ntwo\nthree\nfour\n"}
+	got := quoteReply(comment, "Done.")
+	want := "> one\n> two\n> three\n> ...\n\n@alice Done."
+	if got != want {
+		t.Errorf("quoteReply() = %q, want %q", got, want)
+	}
+}
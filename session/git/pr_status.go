@@ -0,0 +1,140 @@
+package git
+
+import (
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// CI states reported by PRStatus.CIState
+const (
+	CIStateNone    = ""
+	CIStatePending = "pending"
+	CIStateSuccess = "success"
+	CIStateFailure = "failure"
+)
+
+// PRStatus is a compact summary of the pull request for a branch
+type PRStatus struct {
+	Number int
+	// State is the PR state in GitHub's terms (OPEN, CLOSED, MERGED), whichever forge hosts it
+	State string
+	// ReviewDecision is the overall review decision in GitHub's terms (APPROVED, CHANGES_REQUESTED,
+	// REVIEW_REQUIRED)
+	ReviewDecision string
+	// Approvals and ChangesRequested count reviewers whose latest review has that state
+	Approvals        int
+	ChangesRequested int
+	// CIState is the rolled-up state of all status checks, one of the CIState constants
+	CIState string
+	URL     string
+}
+
+// GetPRStatus returns the status of the pull request for the branch checked out in workingDir
+func GetPRStatus(workingDir string) (*PRStatus, error) {
+	return ForgeFor(workingDir).PullRequestStatus(workingDir)
+}
+
+// getGitHubPRStatus returns the status of the GitHub pull request for the branch checked out in workingDir
+func getGitHubPRStatus(workingDir string) (*PRStatus, error) {
+	cmd := exec.Command("gh", "pr", "view", "--json", "number,state,url,reviewDecision,latestReviews,statusCheckRollup")
+	cmd.Dir = workingDir
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		outputStr := string(output)
+		if strings.Contains(outputStr, "no pull requests found") || strings.Contains(outputStr, "no open pull requests") {
+			return nil, fmt.Errorf("no pull request found for the current branch in %s", workingDir)
+		}
+		return nil, fmt.Errorf("failed to get PR status from %s (output: %s): %w", workingDir, strings.TrimSpace(outputStr), err)
+	}
+
+	return parsePRStatus(output)
+}
+
+// parsePRStatus parses the JSON output of gh pr view used by GetPRStatus
+func parsePRStatus(data []byte) (*PRStatus, error) {
+	var prData struct {
+		Number         int    `json:"number"`
+		State          string `json:"state"`
+		URL            string `json:"url"`
+		ReviewDecision string `json:"reviewDecision"`
+		LatestReviews  []struct {
+			State string `json:"state"`
+		} `json:"latestReviews"`
+		StatusCheckRollup []struct {
+			// CheckRun entries report status and conclusion
+			Status     string `json:"status"`
+			Conclusion string `json:"conclusion"`
+			// StatusContext entries report state
+			State string `json:"state"`
+		} `json:"statusCheckRollup"`
+	}
+
+	if err := json.Unmarshal(data, &prData); err != nil {
+		return nil, fmt.Errorf("failed to parse PR status: %w", err)
+	}
+
+	status := &PRStatus{
+		Number:         prData.Number,
+		State:          prData.State,
+		URL:            prData.URL,
+		ReviewDecision: prData.ReviewDecision,
+	}
+
+	for _, review := range prData.LatestReviews {
+		switch review.State {
+		case "APPROVED":
+			status.Approvals++
+		case "CHANGES_REQUESTED":
+			status.ChangesRequested++
+		}
+	}
+
+	states := make([]string, len(prData.StatusCheckRollup))
+	for n, check := range prData.StatusCheckRollup {
+		result := check.Conclusion
+		if result == "" {
+			result = check.State
+		}
+		states[n] = githubCIState(result)
+	}
+	status.CIState = RollupCIState(states)
+
+	return status, nil
+}
+
+// githubCIState maps the conclusion of a GitHub check run, or the state of a commit status, to
+// one of the CIState constants.
+func githubCIState(result string) string {
+	switch strings.ToUpper(result) {
+	case "SUCCESS", "NEUTRAL", "SKIPPED":
+		return CIStateSuccess
+	case "FAILURE", "ERROR", "CANCELLED", "TIMED_OUT", "ACTION_REQUIRED", "STARTUP_FAILURE":
+		return CIStateFailure
+	default:
+		// Checks that haven't completed have no conclusion yet
+		return CIStatePending
+	}
+}
+
+// RollupCIState combines the states of several checks: failed if any failed, else pending if
+// any is, else successful. No checks roll up to CIStateNone.
+func RollupCIState(states []string) string {
+	if len(states) == 0 {
+		return CIStateNone
+	}
+	pending := false
+	for _, state := range states {
+		switch state {
+		case CIStateFailure:
+			return CIStateFailure
+		case CIStatePending:
+			pending = true
+		}
+	}
+	if pending {
+		return CIStatePending
+	}
+	return CIStateSuccess
+}
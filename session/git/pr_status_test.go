@@ -0,0 +1,49 @@
+package git
+
+import (
+	"testing"
+)
+
+func TestParsePRStatus(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected PRStatus
+	}{
+		{
+			name: "approved with passing checks",
+			input: `{"number":123,"state":"OPEN","url":"u","reviewDecision":"APPROVED",
+				"latestReviews":[{"state":"APPROVED"},{"state":"APPROVED"},{"state":"COMMENTED"}],
+				"statusCheckRollup":[{"status":"COMPLETED","conclusion":"SUCCESS"},{"state":"SUCCESS"}]}`,
+			expected: PRStatus{Number: 123, State: "OPEN", URL: "u", ReviewDecision: "APPROVED", Approvals: 2, CIState: CIStateSuccess},
+		},
+		{
+			name: "changes requested with a failing check",
+			input: `{"number":7,"state":"OPEN","latestReviews":[{"state":"CHANGES_REQUESTED"}],
+				"statusCheckRollup":[{"status":"IN_PROGRESS","conclusion":""},{"status":"COMPLETED","conclusion":"FAILURE"}]}`,
+			expected: PRStatus{Number: 7, State: "OPEN", ChangesRequested: 1, CIState: CIStateFailure},
+		},
+		{
+			name:     "pending checks",
+			input:    `{"number":8,"state":"OPEN","statusCheckRollup":[{"state":"PENDING"}]}`,
+			expected: PRStatus{Number: 8, State: "OPEN", CIState: CIStatePending},
+		},
+		{
+			name:     "no checks",
+			input:    `{"number":9,"state":"MERGED"}`,
+			expected: PRStatus{Number: 9, State: "MERGED", CIState: CIStateNone},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parsePRStatus([]byte(tt.input))
+			if err != nil {
+				t.Fatalf("parsePRStatus() error = %v", err)
+			}
+			if *got != tt.expected {
+				t.Errorf("parsePRStatus() = %+v, want %+v", *got, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,148 @@
+package git
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// progressLineRe matches git's sideband progress lines, e.g.
+// "Receiving objects:  45% (450/1000), 1.20 MiB | 1.10 MiB/s" or "remote: Counting objects: 100% (5/5), done."
+var progressLineRe = regexp.MustCompile(`^(?:remote:\s*)?([A-Za-z][A-Za-z ]*):\s+(\d+)%\s+\((\d+)/(\d+)\)`)
+
+// GitProgress is a single progress update from a long-running git command.
+type GitProgress struct {
+	// Operation is the git subcommand being run (push, fetch, clone).
+	Operation string
+	// Phase is the stage git reports, e.g. "Receiving objects" or "Resolving deltas".
+	Phase string
+	// Percent is the completion percentage of the current phase.
+	Percent int
+	// Current and Total are the object counts of the current phase.
+	Current int
+	Total   int
+}
+
+// ProgressFunc is called with each progress update parsed from git's output.
+type ProgressFunc func(GitProgress)
+
+// WithProgress returns a copy of the worktree that reports progress of fetch, clone and push
+// operations to fn. The copy shares all state with the original apart from the callback.
+func (g *GitWorktree) WithProgress(fn ProgressFunc) *GitWorktree {
+	c := *g
+	c.progress = fn
+	return &c
+}
+
+// WithContext returns a copy of the worktree whose fetch, clone and push operations reporting
+// progress are stopped once ctx is done, failing as they would on a network error. Other
+// commands still run, so an operation can clean up after a stopped transfer.
+func (g *GitWorktree) WithContext(ctx context.Context) *GitWorktree {
+	c := *g
+	c.ctx = ctx
+	return &c
+}
+
+// parseGitProgress parses a single line of git's progress output.
+func parseGitProgress(line string) (GitProgress, bool) {
+	matches := progressLineRe.FindStringSubmatch(strings.TrimSpace(line))
+	if matches == nil {
+		return GitProgress{}, false
+	}
+
+	percent, _ := strconv.Atoi(matches[2])
+	current, _ := strconv.Atoi(matches[3])
+	total, _ := strconv.Atoi(matches[4])
+	return GitProgress{
+		Phase:   strings.TrimSpace(matches[1]),
+		Percent: percent,
+		Current: current,
+		Total:   total,
+	}, true
+}
+
+// scanProgressLines is a bufio.SplitFunc that splits on both '\r' and '\n', since git
+// redraws progress lines in place using carriage returns.
+func scanProgressLines(data []byte, atEOF bool) (advance int, token []byte, err error) {
+	if atEOF && len(data) == 0 {
+		return 0, nil, nil
+	}
+	if i := bytes.IndexAny(data, "\r\n"); i >= 0 {
+		return i + 1, data[:i], nil
+	}
+	if atEOF {
+		return len(data), data, nil
+	}
+	return 0, nil, nil
+}
+
+// runGitCommandWithProgress runs a git network command (push, fetch, clone) with --progress
+// and streams the parsed progress to the worktree's progress callback. Without a callback it
+// behaves like runGitCommand.
+func (g *GitWorktree) runGitCommandWithProgress(path string, args ...string) (string, error) {
+	if g.progress == nil || len(args) == 0 || g.dryRun != nil || g.runner != nil {
+		return g.runGitCommand(path, args...)
+	}
+
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		return "", fmt.Errorf("directory does not exist: %s", path)
+	}
+
+	operation := args[0]
+	gitArgs := append([]string{"-C", path, operation, "--progress"}, args[1:]...)
+	ctx := g.ctx
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	cmd := exec.CommandContext(ctx, "git", gitArgs...)
+
+	// stdout is written by exec's copier goroutine, so keep git's other stderr chatter in a
+	// separate buffer and join them once the command has finished.
+	var stdout, messages bytes.Buffer
+	cmd.Stdout = &stdout
+	stderr, err := cmd.StderrPipe()
+	if err != nil {
+		return "", fmt.Errorf("failed to open stderr for git %s: %w", operation, err)
+	}
+
+	start := time.Now()
+	if err := cmd.Start(); err != nil {
+		return "", fmt.Errorf("failed to start git %s: %w", operation, err)
+	}
+
+	scanner := bufio.NewScanner(stderr)
+	scanner.Split(scanProgressLines)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if p, ok := parseGitProgress(line); ok {
+			p.Operation = operation
+			g.progress(p)
+			continue
+		}
+		if line != "" {
+			messages.WriteString(line)
+			messages.WriteString("\n")
+		}
+	}
+
+	err = cmd.Wait()
+	commandStats.recordCommand(path, args, time.Since(start), err != nil)
+	output := stdout.String() + messages.String()
+	if err != nil {
+		fullCmd := fmt.Sprintf("git %s", strings.Join(gitArgs, " "))
+		outputStr := output
+		if len(outputStr) > 500 {
+			outputStr = outputStr[:500] + "... (truncated)"
+		}
+		return "", fmt.Errorf("git command failed: %s\nCommand: %s\nError: %w", outputStr, fullCmd, err)
+	}
+
+	return output, nil
+}
@@ -0,0 +1,74 @@
+package git
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+)
+
+func TestParseGitProgress(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected GitProgress
+		ok       bool
+	}{
+		{
+			name:     "receiving objects",
+			input:    "Receiving objects:  45% (450/1000), 1.20 MiB | 1.10 MiB/s",
+			expected: GitProgress{Phase: "Receiving objects", Percent: 45, Current: 450, Total: 1000},
+			ok:       true,
+		},
+		{
+			name:     "remote counting objects",
+			input:    "remote: Counting objects: 100% (5/5), done.",
+			expected: GitProgress{Phase: "Counting objects", Percent: 100, Current: 5, Total: 5},
+			ok:       true,
+		},
+		{
+			name:     "writing objects",
+			input:    "Writing objects:   7% (3/42)",
+			expected: GitProgress{Phase: "Writing objects", Percent: 7, Current: 3, Total: 42},
+			ok:       true,
+		},
+		{
+			name:  "non-progress line",
+			input: "To github.com:owner/repo.git",
+			ok:    false,
+		},
+		{
+			name:  "enumerating without percentage",
+			input: "Enumerating objects: 5, done.",
+			ok:    false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := parseGitProgress(tt.input)
+			if ok != tt.ok {
+				t.Fatalf("parseGitProgress(%q) ok = %v, want %v", tt.input, ok, tt.ok)
+			}
+			if ok && got != tt.expected {
+				t.Errorf("parseGitProgress(%q) = %+v, want %+v", tt.input, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestProgressCommandStopsWithContext(t *testing.T) {
+	dir := t.TempDir()
+	repo := filepath.Join(dir, "repo")
+	initRepo(t, repo)
+
+	g := (&GitWorktree{}).WithProgress(func(GitProgress) {})
+	if _, err := g.runGitCommandWithProgress(dir, "clone", repo, filepath.Join(dir, "first")); err != nil {
+		t.Fatalf("clone failed: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := g.WithContext(ctx).runGitCommandWithProgress(dir, "clone", repo, filepath.Join(dir, "second")); err == nil {
+		t.Fatal("clone with a cancelled context succeeded")
+	}
+}
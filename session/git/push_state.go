@@ -0,0 +1,200 @@
+package git
+
+import (
+	"claude-squad/log"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// pushStateFile is the file in a worktree's git directory recording an interrupted push.
+const pushStateFile = "claude-squad-push.json"
+
+// PushStep is a step of PushChanges. A push that fails is resumed from the step that failed.
+type PushStep string
+
+const (
+	// PushStepFetch fetches the remote branch before anything is committed.
+	PushStepFetch PushStep = "fetch"
+	// PushStepCommit commits the worktree's changes.
+	PushStepCommit PushStep = "commit"
+	// PushStepPush pushes the committed branch.
+	PushStepPush PushStep = "push"
+)
+
+// pushAttempts is how many times a step failing with a network error is tried before the push
+// is left to be resumed, waiting pushRetryDelay, doubled each time, in between.
+const pushAttempts = 3
+
+var pushRetryDelay = 2 * time.Second
+
+// transientPushErrors are fragments of git's messages for network failures worth retrying.
+var transientPushErrors = []string{
+	"could not resolve host",
+	"connection reset",
+	"connection refused",
+	"connection timed out",
+	"operation timed out",
+	"network is unreachable",
+	"the remote end hung up unexpectedly",
+	"early eof",
+	"rpc failed",
+	"unexpected disconnect",
+	"broken pipe",
+	"tls connection",
+}
+
+// pushState records how far an interrupted push got.
+type pushState struct {
+	Step   PushStep `json:"step"`
+	Branch string   `json:"branch"`
+	// Message is the commit message the push was started with
+	Message string `json:"message,omitempty"`
+	// Commit is the commit to push, once the changes are committed
+	Commit    string    `json:"commit,omitempty"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// isTransientPushError returns true if err looks like a network failure that may succeed when
+// retried, as opposed to e.g. a rejected push or missing credentials.
+func isTransientPushError(err error) bool {
+	msg := strings.ToLower(err.Error())
+	for _, fragment := range transientPushErrors {
+		if strings.Contains(msg, fragment) {
+			return true
+		}
+	}
+	return false
+}
+
+// retryNetwork runs fn, retrying it with a growing delay while it fails with a network error.
+func retryNetwork(operation string, fn func() error) error {
+	delay := pushRetryDelay
+	var err error
+	for attempt := 1; attempt <= pushAttempts; attempt++ {
+		if err = fn(); err == nil || !isTransientPushError(err) {
+			return err
+		}
+		if attempt < pushAttempts {
+			log.WarningLog.Printf("%s failed (attempt %d of %d), retrying in %s: %v", operation, attempt, pushAttempts, delay, err)
+			time.Sleep(delay)
+			delay *= 2
+		}
+	}
+	return err
+}
+
+// pushStatePath returns the path of the worktree's push state file.
+func (g *GitWorktree) pushStatePath() (string, error) {
+	gitDir, err := g.GitDir()
+	if err != nil {
+		return "", err
+	}
+	if g.runner != nil {
+		return path.Join(gitDir, pushStateFile), nil
+	}
+	return filepath.Join(gitDir, pushStateFile), nil
+}
+
+// loadPushState returns the state of the worktree's interrupted push, or nil if there is none
+// or it can no longer be resumed because the branch moved on since.
+func (g *GitWorktree) loadPushState() *pushState {
+	statePath, err := g.pushStatePath()
+	if err != nil {
+		return nil
+	}
+	data, err := g.readFile(statePath)
+	if err != nil {
+		if !errors.Is(err, os.ErrNotExist) {
+			log.WarningLog.Printf("failed to read push state: %v", err)
+		}
+		return nil
+	}
+	var state pushState
+	if err := json.Unmarshal(data, &state); err != nil {
+		log.WarningLog.Printf("discarding unreadable push state %s: %v", statePath, err)
+		g.clearPushState()
+		return nil
+	}
+	stale := state.Branch != g.branchName
+	if state.Commit != "" {
+		head, err := g.runGitCommand(g.worktreePath, "rev-parse", "HEAD")
+		stale = stale || err != nil || strings.TrimSpace(head) != state.Commit
+	}
+	if stale {
+		log.InfoLog.Printf("discarding push state of %s, the branch changed since", state.Branch)
+		g.clearPushState()
+		return nil
+	}
+	return &state
+}
+
+// savePushState records the state of a push so it can be resumed if it fails.
+func (g *GitWorktree) savePushState(state *pushState) error {
+	if g.dryRun != nil {
+		return nil
+	}
+	statePath, err := g.pushStatePath()
+	if err != nil {
+		return err
+	}
+	state.UpdatedAt = time.Now()
+	data, err := json.Marshal(state)
+	if err != nil {
+		return fmt.Errorf("failed to encode push state: %w", err)
+	}
+	if err := g.writeFile(statePath, data); err != nil {
+		return fmt.Errorf("failed to write push state: %w", err)
+	}
+	return nil
+}
+
+// clearPushState removes the record of a push once it's done.
+func (g *GitWorktree) clearPushState() {
+	if g.dryRun != nil {
+		return
+	}
+	statePath, err := g.pushStatePath()
+	if err != nil {
+		return
+	}
+	if err := g.removeFile(statePath); err != nil {
+		log.WarningLog.Printf("failed to remove push state: %v", err)
+	}
+}
+
+// InterruptedPush returns the step an interrupted push of the branch failed at, which pushing
+// again resumes from, and the commit message the push was started with.
+func (g *GitWorktree) InterruptedPush() (step PushStep, message string, ok bool) {
+	state := g.loadPushState()
+	if state == nil {
+		return "", "", false
+	}
+	return state.Step, state.Message, true
+}
+
+// fetchPushBranch fetches the remote branch pushes go to. A branch that doesn't exist on the
+// remote yet isn't an error.
+func (g *GitWorktree) fetchPushBranch() error {
+	_, err := g.runGitCommandWithProgress(g.worktreePath, "fetch", "origin", g.PushBranch())
+	if err != nil && strings.Contains(strings.ToLower(err.Error()), "couldn't find remote ref") {
+		return nil
+	}
+	return err
+}
+
+// remoteHasCommit returns true if the remote branch is at commit, which is the case when a push
+// reported as failed went through before the connection dropped.
+func (g *GitWorktree) remoteHasCommit(commit string) bool {
+	output, err := g.runGitCommand(g.worktreePath, "ls-remote", "origin", "refs/heads/"+g.PushBranch())
+	if err != nil {
+		return false
+	}
+	fields := strings.Fields(output)
+	return len(fields) > 0 && fields[0] == commit
+}
@@ -0,0 +1,67 @@
+package git
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRetryNetwork(t *testing.T) {
+	saved := pushRetryDelay
+	pushRetryDelay = 0
+	defer func() { pushRetryDelay = saved }()
+
+	for _, tt := range []struct {
+		err      error
+		attempts int
+	}{
+		{errors.New("fatal: unable to access 'https://github.com/o/r/': Could not resolve host: github.com"), pushAttempts},
+		{errors.New("error: RPC failed; curl 92 HTTP/2 stream 0 was not closed cleanly"), pushAttempts},
+		{errors.New("! [rejected] main -> main (non-fast-forward)"), 1},
+		{errors.New("remote: Permission to o/r.git denied"), 1},
+	} {
+		attempts := 0
+		err := retryNetwork("push", func() error {
+			attempts++
+			return tt.err
+		})
+		if err != tt.err || attempts != tt.attempts {
+			t.Errorf("retryNetwork(%q) tried %d times and returned %v, want %d tries", tt.err, attempts, err, tt.attempts)
+		}
+	}
+}
+
+func TestInterruptedPush(t *testing.T) {
+	repo := filepath.Join(t.TempDir(), "repo")
+	initRepo(t, repo)
+	g := &GitWorktree{repoPath: repo, worktreePath: repo, branchName: "main"}
+
+	if _, _, ok := g.InterruptedPush(); ok {
+		t.Fatal("InterruptedPush() found a push before any was recorded")
+	}
+
+	head, err := exec.Command("git", "-C", repo, "rev-parse", "HEAD").Output()
+	if err != nil {
+		t.Fatal(err)
+	}
+	state := &pushState{Step: PushStepPush, Branch: "main", Message: "feat: work", Commit: strings.TrimSpace(string(head))}
+	if err := g.savePushState(state); err != nil {
+		t.Fatal(err)
+	}
+	if step, message, ok := g.InterruptedPush(); !ok || step != PushStepPush || message != "feat: work" {
+		t.Errorf("InterruptedPush() = %q, %q, %v, want the push step and the recorded message", step, message, ok)
+	}
+
+	// Once the branch moves on, the recorded commit isn't the one to push anymore
+	commitEmpty(t, repo, "more work")
+	if _, _, ok := g.InterruptedPush(); ok {
+		t.Error("InterruptedPush() resumed a push of a commit the branch moved on from")
+	}
+	path, _ := g.pushStatePath()
+	if _, err := os.Stat(path); !errors.Is(err, os.ErrNotExist) {
+		t.Error("stale push state was not removed")
+	}
+}
@@ -0,0 +1,77 @@
+package git
+
+import (
+	"errors"
+	"fmt"
+	"os/exec"
+	"sync"
+	"time"
+)
+
+// ErrNoRemote is returned by operations that need the origin remote in a repository without one.
+var ErrNoRemote = errors.New("repository has no origin remote")
+
+// remoteCacheTTL is how long whether a repository has a remote is remembered, so a remote added
+// while the program runs is picked up.
+const remoteCacheTTL = 30 * time.Second
+
+type remoteCacheEntry struct {
+	hasRemote bool
+	checkedAt time.Time
+}
+
+var (
+	remoteCacheMu sync.Mutex
+	remoteCache   = make(map[string]remoteCacheEntry)
+)
+
+// HasRemote reports whether the repository at repoPath has an origin remote. The answer is
+// cached briefly since menus ask for it on every render.
+func HasRemote(repoPath string) bool {
+	return hasRemote(repoPath, exec.Command("git", "-C", repoPath, "remote", "get-url", "origin"))
+}
+
+// hasRemote runs cmd, a git remote get-url origin, unless its answer for key is cached.
+func hasRemote(key string, cmd *exec.Cmd) bool {
+	remoteCacheMu.Lock()
+	entry, ok := remoteCache[key]
+	remoteCacheMu.Unlock()
+	if ok && time.Since(entry.checkedAt) < remoteCacheTTL {
+		return entry.hasRemote
+	}
+
+	start := time.Now()
+	err := cmd.Run()
+	commandStats.recordCommand(key, []string{"remote", "get-url", "origin"}, time.Since(start), err != nil)
+
+	remoteCacheMu.Lock()
+	defer remoteCacheMu.Unlock()
+	remoteCache[key] = remoteCacheEntry{hasRemote: err == nil, checkedAt: time.Now()}
+	return err == nil
+}
+
+// HasRemote reports whether the worktree's repository has an origin remote.
+func (g *GitWorktree) HasRemote() bool {
+	if g.runner != nil {
+		return hasRemote(g.runner.String()+":"+g.repoPath, g.runner.Command("git", "-C", g.repoPath, "remote", "get-url", "origin"))
+	}
+	return HasRemote(g.repoPath)
+}
+
+// requireRemote returns an error wrapping ErrNoRemote if the repository has no origin remote.
+func (g *GitWorktree) requireRemote(action string) error {
+	if !g.HasRemote() {
+		return fmt.Errorf("cannot %s: %w; it is only available once a remote is added", action, ErrNoRemote)
+	}
+	return nil
+}
+
+// mainRef returns the ref the main branch is compared against: origin's copy when there is a
+// remote, the local branch otherwise.
+func (g *GitWorktree) mainRef() string {
+	mainBranch := g.getMainBranch()
+	if g.HasRemote() {
+		return "origin/" + mainBranch
+	}
+	return mainBranch
+}
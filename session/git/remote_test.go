@@ -0,0 +1,54 @@
+package git
+
+import (
+	"errors"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// initRepo creates a repository with one commit on main in dir.
+func initRepo(t *testing.T, dir string) {
+	t.Helper()
+	for _, args := range [][]string{
+		{"init", "-q", "-b", "main", dir},
+		{"-C", dir, "-c", "user.name=test", "-c", "user.email=test@example.com", "commit", "-q", "--allow-empty", "-m", "initial"},
+	} {
+		if output, err := exec.Command("git", args...).CombinedOutput(); err != nil {
+			t.Fatalf("git %s: %v\n%s", strings.Join(args, " "), err, output)
+		}
+	}
+}
+
+func TestSetupWithoutRemote(t *testing.T) {
+	repo := filepath.Join(t.TempDir(), "repo")
+	initRepo(t, repo)
+
+	tree, _, err := NewGitWorktreeWithOptions(repo, "local", WorktreeOptions{Dir: t.TempDir()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tree.HasRemote() {
+		t.Fatal("HasRemote() = true for a repository without remotes")
+	}
+	if err := tree.SetupNewWorktree(); err != nil {
+		t.Fatalf("SetupNewWorktree() failed: %v", err)
+	}
+	head, err := tree.localHead()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tree.GetBaseCommitSHA() != head {
+		t.Errorf("base commit = %s, want local HEAD %s", tree.GetBaseCommitSHA(), head)
+	}
+	if got := tree.mainRef(); got != "main" {
+		t.Errorf("mainRef() = %q, want the local main branch", got)
+	}
+	if _, err := tree.GetChangedFilesForBranch(); err != nil {
+		t.Errorf("GetChangedFilesForBranch() failed: %v", err)
+	}
+	if err := tree.RebaseWithMain(); !errors.Is(err, ErrNoRemote) {
+		t.Errorf("RebaseWithMain() error = %v, want ErrNoRemote", err)
+	}
+}
@@ -0,0 +1,106 @@
+package git
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// RepoState describes what is checked out in a repository and any operation in progress there.
+type RepoState struct {
+	// Branch is the checked out branch, empty when HEAD is detached
+	Branch string
+	// Head is the abbreviated checked out commit, empty in a repository without commits
+	Head string
+	// Operation is the operation in progress: "rebase", "am", "merge", "cherry-pick", "revert" or
+	// "bisect". Empty if there is none.
+	Operation string
+	// OperationBranch is the branch the operation was started on, if known
+	OperationBranch string
+}
+
+// operationMarkers maps files git keeps in the git dir during an operation to the operation,
+// checked in order.
+var operationMarkers = []struct {
+	path      string
+	operation string
+	// headFile holds the branch the operation was started on
+	headFile string
+}{
+	{"rebase-merge", "rebase", "rebase-merge/head-name"},
+	{"rebase-apply/applying", "am", ""},
+	{"rebase-apply", "rebase", "rebase-apply/head-name"},
+	{"MERGE_HEAD", "merge", ""},
+	{"CHERRY_PICK_HEAD", "cherry-pick", ""},
+	{"REVERT_HEAD", "revert", ""},
+	{"BISECT_LOG", "bisect", "BISECT_START"},
+}
+
+// GetRepoState returns the state of the checkout at path.
+func GetRepoState(path string) (*RepoState, error) {
+	g := &GitWorktree{repoPath: path}
+	gitDir, err := g.runGitCommand(path, "rev-parse", "--absolute-git-dir")
+	if err != nil {
+		return nil, fmt.Errorf("failed to find git directory: %w", err)
+	}
+	gitDir = strings.TrimSpace(gitDir)
+
+	state := &RepoState{}
+	// symbolic-ref fails when HEAD is detached
+	if branch, err := g.runGitCommand(path, "symbolic-ref", "-q", "--short", "HEAD"); err == nil {
+		state.Branch = strings.TrimSpace(branch)
+	}
+	if head, err := g.runGitCommand(path, "rev-parse", "--short", "HEAD"); err == nil {
+		state.Head = strings.TrimSpace(head)
+	}
+
+	for _, marker := range operationMarkers {
+		if _, err := os.Stat(filepath.Join(gitDir, marker.path)); err != nil {
+			continue
+		}
+		state.Operation = marker.operation
+		if marker.headFile != "" {
+			if data, err := os.ReadFile(filepath.Join(gitDir, marker.headFile)); err == nil {
+				state.OperationBranch = strings.TrimPrefix(strings.TrimSpace(string(data)), "refs/heads/")
+			}
+		}
+		break
+	}
+	return state, nil
+}
+
+// Detached returns true if HEAD is not on a branch.
+func (s *RepoState) Detached() bool {
+	return s.Branch == ""
+}
+
+// Normal returns true if a branch is checked out and no operation is in progress.
+func (s *RepoState) Normal() bool {
+	return !s.Detached() && s.Operation == ""
+}
+
+// Describe returns a sentence explaining the state.
+func (s *RepoState) Describe() string {
+	head := s.Head
+	if head == "" {
+		head = "no commit"
+	}
+	switch {
+	case s.Operation != "" && s.OperationBranch != "" && s.OperationBranch != s.Branch:
+		return fmt.Sprintf("The checkout is in the middle of a %s of %s (HEAD at %s).", s.Operation, s.OperationBranch, head)
+	case s.Operation != "":
+		return fmt.Sprintf("The checkout is in the middle of a %s (HEAD at %s).", s.Operation, head)
+	case s.Detached():
+		return fmt.Sprintf("HEAD is detached at %s.", head)
+	default:
+		return fmt.Sprintf("On branch %s at %s.", s.Branch, head)
+	}
+}
+
+// ResolveCommit returns the full hash of the commit a branch, tag or commit names in the
+// repository at path, preferring origin's copy of a branch.
+func ResolveCommit(path, ref string) (string, error) {
+	g := &GitWorktree{repoPath: path, worktreePath: path, baseRef: ref}
+	return g.resolveBaseRef()
+}
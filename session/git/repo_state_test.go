@@ -0,0 +1,74 @@
+package git
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestGetRepoState(t *testing.T) {
+	repo := filepath.Join(t.TempDir(), "repo")
+	initRepo(t, repo)
+	git := func(args ...string) string {
+		t.Helper()
+		output, err := exec.Command("git", append([]string{"-C", repo}, args...)...).CombinedOutput()
+		if err != nil {
+			t.Fatalf("git %s: %v\n%s", strings.Join(args, " "), err, output)
+		}
+		return strings.TrimSpace(string(output))
+	}
+	head := git("rev-parse", "--short", "HEAD")
+
+	state, err := GetRepoState(repo)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !state.Normal() || state.Branch != "main" || state.Head != head {
+		t.Errorf("GetRepoState() on main = %+v, want a normal state on main at %s", *state, head)
+	}
+
+	git("tag", "v1")
+	git("checkout", "-q", "--detach")
+	state, err = GetRepoState(repo)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if state.Normal() || !state.Detached() || state.Operation != "" {
+		t.Errorf("GetRepoState() detached = %+v, want detached without an operation", *state)
+	}
+	if want := "HEAD is detached at " + head + "."; state.Describe() != want {
+		t.Errorf("Describe() = %q, want %q", state.Describe(), want)
+	}
+
+	// Git keeps the rebased branch in the rebase state directory
+	gitDir := git("rev-parse", "--absolute-git-dir")
+	if err := os.MkdirAll(filepath.Join(gitDir, "rebase-merge"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(gitDir, "rebase-merge", "head-name"), []byte("refs/heads/feature\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	state, err = GetRepoState(repo)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if state.Operation != "rebase" || state.OperationBranch != "feature" {
+		t.Errorf("GetRepoState() mid-rebase = %+v, want a rebase of feature", *state)
+	}
+	if !strings.Contains(state.Describe(), "rebase of feature") {
+		t.Errorf("Describe() = %q, want it to name the rebased branch", state.Describe())
+	}
+
+	sha, err := ResolveCommit(repo, "v1")
+	if err != nil {
+		t.Fatalf("ResolveCommit(v1) failed: %v", err)
+	}
+	if !strings.HasPrefix(sha, head) {
+		t.Errorf("ResolveCommit(v1) = %s, want the commit %s", sha, head)
+	}
+	if _, err := ResolveCommit(repo, "missing"); err == nil {
+		t.Error("ResolveCommit(missing) succeeded, want an error")
+	}
+}
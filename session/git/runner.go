@@ -0,0 +1,154 @@
+package git
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path"
+	"strings"
+	"time"
+)
+
+// Runner runs a worktree's commands on another machine, such as over SSH. Worktrees on this
+// machine have none.
+type Runner interface {
+	// Command returns a command running name with args on the machine.
+	Command(name string, args ...string) *exec.Cmd
+	// String names the machine, e.g. "me@build-box".
+	String() string
+}
+
+// NewRemoteGitWorktree creates a GitWorktree on a new branch in the repository at repoPath on
+// the machine runner runs commands on, with its worktree in dir there. Both paths are absolute
+// paths on that machine.
+func NewRemoteGitWorktree(runner Runner, repoPath string, sessionName string, opts WorktreeOptions) (tree *GitWorktree, branchname string, err error) {
+	branchName := opts.BranchPrefix + sanitizeBranchName(sessionName)
+	return newRemoteGitWorktree(runner, repoPath, sessionName, branchName, opts.Dir), branchName, nil
+}
+
+// NewRemoteGitWorktreeForBranch is like NewRemoteGitWorktree for an existing branch.
+func NewRemoteGitWorktreeForBranch(runner Runner, repoPath string, sessionName string, branchName string, dir string) *GitWorktree {
+	return newRemoteGitWorktree(runner, repoPath, sessionName, branchName, dir)
+}
+
+func newRemoteGitWorktree(runner Runner, repoPath, sessionName, branchName, dir string) *GitWorktree {
+	// Remote paths are joined with slashes whatever this machine uses
+	worktreePath := path.Join(dir, sanitizeBranchName(sessionName)) + "_" + fmt.Sprintf("%x", time.Now().UnixNano())
+	return &GitWorktree{
+		repoPath:     repoPath,
+		sessionName:  sessionName,
+		branchName:   branchName,
+		worktreePath: worktreePath,
+		history:      &commitHistory{},
+		runner:       runner,
+	}
+}
+
+// SetRunner makes the worktree's commands run through runner, for a worktree restored from
+// storage that lives on another machine.
+func (g *GitWorktree) SetRunner(runner Runner) {
+	g.runner = runner
+}
+
+// IsRemote returns true if the worktree is on another machine.
+func (g *GitWorktree) IsRemote() bool {
+	return g.runner != nil
+}
+
+// requireLocal returns an error if the worktree is on another machine, for operations that read
+// or write its files directly.
+func (g *GitWorktree) requireLocal(action string) error {
+	if g.runner != nil {
+		return fmt.Errorf("cannot %s: the worktree is on %s", action, g.runner)
+	}
+	return nil
+}
+
+// command returns a command running name with args on the machine the worktree is on.
+func (g *GitWorktree) command(name string, args ...string) *exec.Cmd {
+	if g.runner != nil {
+		return g.runner.Command(name, args...)
+	}
+	return exec.Command(name, args...)
+}
+
+// WorktreeExists returns true if the worktree directory exists.
+func (g *GitWorktree) WorktreeExists() bool {
+	if g.runner != nil {
+		return g.command("test", "-d", g.worktreePath).Run() == nil
+	}
+	_, err := os.Stat(g.worktreePath)
+	return err == nil
+}
+
+// worktreeMissing returns true if the worktree directory is known not to exist. Remote worktrees
+// aren't checked, to save a round trip; the git command run in them fails instead.
+func (g *GitWorktree) worktreeMissing() bool {
+	if g.runner != nil {
+		return false
+	}
+	_, err := os.Stat(g.worktreePath)
+	return os.IsNotExist(err)
+}
+
+// readFile reads a file on the machine the worktree is on. A missing file is an
+// os.ErrNotExist error wherever it is.
+func (g *GitWorktree) readFile(name string) ([]byte, error) {
+	if g.runner == nil {
+		return os.ReadFile(name)
+	}
+	if g.command("test", "-e", name).Run() != nil {
+		return nil, &fs.PathError{Op: "read", Path: name, Err: fs.ErrNotExist}
+	}
+	var stderr bytes.Buffer
+	cmd := g.command("cat", name)
+	cmd.Stderr = &stderr
+	data, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("%s (%w)", strings.TrimSpace(stderr.String()), err)
+	}
+	return data, nil
+}
+
+// writeFile writes data to a file on the machine the worktree is on.
+func (g *GitWorktree) writeFile(name string, data []byte) error {
+	if g.runner == nil {
+		return os.WriteFile(name, data, 0644)
+	}
+	var stderr bytes.Buffer
+	cmd := g.command("tee", name)
+	cmd.Stdin = bytes.NewReader(data)
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("%s (%w)", strings.TrimSpace(stderr.String()), err)
+	}
+	return nil
+}
+
+// removeFile removes a file on the machine the worktree is on. A missing file isn't an error.
+func (g *GitWorktree) removeFile(name string) error {
+	if g.runner == nil {
+		if err := os.Remove(name); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+		return nil
+	}
+	if output, err := g.command("rm", "-f", name).CombinedOutput(); err != nil {
+		return fmt.Errorf("%s (%w)", strings.TrimSpace(string(output)), err)
+	}
+	return nil
+}
+
+// mkdirAll creates dir and its parents on the machine the worktree is on.
+func (g *GitWorktree) mkdirAll(dir string) error {
+	if g.runner == nil {
+		return os.MkdirAll(dir, 0755)
+	}
+	if output, err := g.command("mkdir", "-p", dir).CombinedOutput(); err != nil {
+		return fmt.Errorf("%s (%w)", strings.TrimSpace(string(output)), err)
+	}
+	return nil
+}
@@ -0,0 +1,130 @@
+package git
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// recordingRunner runs commands on this machine, as an SSH runner would on its host, and records
+// them.
+type recordingRunner struct {
+	commands []string
+}
+
+func (r *recordingRunner) Command(name string, args ...string) *exec.Cmd {
+	r.commands = append(r.commands, strings.Join(append([]string{name}, args...), " "))
+	return exec.Command(name, args...)
+}
+
+func (r *recordingRunner) String() string {
+	return "test-host"
+}
+
+func TestRemoteWorktree(t *testing.T) {
+	dir := t.TempDir()
+	repo := filepath.Join(dir, "repo")
+	initRepo(t, repo)
+	runner := &recordingRunner{}
+
+	tree, branch, err := NewRemoteGitWorktree(runner, repo, "remote session", WorktreeOptions{Dir: filepath.Join(dir, "worktrees"), BranchPrefix: "cs/"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if branch != "cs/remote-session" || !tree.IsRemote() {
+		t.Fatalf("branch = %q, IsRemote() = %v", branch, tree.IsRemote())
+	}
+	if err := tree.Setup(); err != nil {
+		t.Fatalf("Setup() failed: %v", err)
+	}
+	if !tree.WorktreeExists() {
+		t.Fatal("worktree wasn't created")
+	}
+	if err := os.WriteFile(filepath.Join(tree.GetWorktreePath(), "new.txt"), []byte("hello\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if stats := tree.Diff(); stats.Error != nil || stats.Added != 1 {
+		t.Errorf("Diff() = %+v", stats)
+	}
+
+	// An interrupted push is recorded in the git directory on the host
+	if err := tree.savePushState(&pushState{Step: PushStepPush, Branch: branch}); err != nil {
+		t.Fatalf("savePushState() failed: %v", err)
+	}
+	if step, _, ok := tree.InterruptedPush(); !ok || step != PushStepPush {
+		t.Errorf("InterruptedPush() = %q, %v", step, ok)
+	}
+	tree.clearPushState()
+	if _, _, ok := tree.InterruptedPush(); ok {
+		t.Error("InterruptedPush() after clearPushState() still reports a push")
+	}
+	for _, prefix := range []string{"tee ", "cat ", "rm -f "} {
+		found := false
+		for _, command := range runner.commands {
+			found = found || (strings.HasPrefix(command, prefix) && strings.HasSuffix(command, pushStateFile))
+		}
+		if !found {
+			t.Errorf("the push state wasn't accessed with %q through the runner", prefix)
+		}
+	}
+
+	// Nothing may reach the repository without going through the runner
+	for _, command := range runner.commands {
+		if strings.HasPrefix(command, "git ") && !strings.HasPrefix(command, "git -C ") {
+			t.Errorf("git ran without a directory: %s", command)
+		}
+	}
+	if len(runner.commands) == 0 {
+		t.Fatal("no commands went through the runner")
+	}
+
+	if err := tree.Move(filepath.Join(dir, "moved")); err == nil || !strings.Contains(err.Error(), "test-host") {
+		t.Errorf("Move() of a remote worktree = %v", err)
+	}
+
+	if err := tree.Cleanup(); err != nil {
+		t.Fatalf("Cleanup() failed: %v", err)
+	}
+	if tree.WorktreeExists() {
+		t.Error("worktree still exists after Cleanup()")
+	}
+	if err := exec.Command("git", "-C", repo, "rev-parse", "--verify", "refs/heads/"+branch).Run(); err == nil {
+		t.Error("branch still exists after Cleanup()")
+	}
+}
+
+func TestRemoteWorktreePush(t *testing.T) {
+	dir := t.TempDir()
+	_, g, git := initFeatureWorktree(t, dir)
+	runner := &recordingRunner{}
+	g.SetRunner(runner)
+
+	// Commands other than git run in the directory given on the host
+	output, err := g.runCommand(g.GetWorktreePath(), "pwd")
+	if got := strings.TrimSpace(string(output)); err != nil || got != g.GetWorktreePath() {
+		t.Errorf("runCommand(pwd) = %q, %v, want %q", got, err, g.GetWorktreePath())
+	}
+
+	// Pushing needs only git there, not the forge's CLI
+	if err := os.WriteFile(filepath.Join(g.GetWorktreePath(), "new.txt"), []byte("hello\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := g.PushChanges("add new.txt", true); err != nil {
+		t.Fatalf("PushChanges() failed: %v", err)
+	}
+	if got := git("-C", filepath.Join(dir, "origin.git"), "log", "-1", "--format=%s", "feature"); got != "add new.txt" {
+		t.Errorf("origin's feature branch is at %q", got)
+	}
+	for _, command := range runner.commands {
+		if strings.HasPrefix(command, "gh ") || strings.HasPrefix(command, "glab ") {
+			t.Errorf("the forge's CLI ran for a remote push: %s", command)
+		}
+	}
+
+	// The browser opens on this machine, where the checkout isn't
+	if err := g.CreatePullRequest("", ""); err == nil || !strings.Contains(err.Error(), "test-host") {
+		t.Errorf("CreatePullRequest() of a remote worktree = %v", err)
+	}
+}
@@ -0,0 +1,84 @@
+package git
+
+import (
+	"fmt"
+	"strings"
+)
+
+// stashTop returns the commit of the newest stash entry in the checkout at path, or "" if the
+// stash is empty.
+func (g *GitWorktree) stashTop(path string) string {
+	top, err := g.runGitCommand(path, "rev-parse", "-q", "--verify", "refs/stash")
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(top)
+}
+
+// CheckoutChanges returns how many files have uncommitted changes in the checkout at repoPath,
+// untracked files included.
+func CheckoutChanges(repoPath string) (int, error) {
+	g := &GitWorktree{repoPath: repoPath, worktreePath: repoPath}
+	output, err := g.runGitCommand(repoPath, "status", "--porcelain")
+	if err != nil {
+		return 0, fmt.Errorf("failed to check the status of %s: %w", repoPath, err)
+	}
+	if output = strings.TrimSpace(output); output == "" {
+		return 0, nil
+	}
+	return len(strings.Split(output, "\n")), nil
+}
+
+// StashCheckoutChanges stashes the uncommitted changes of the checkout at repoPath, untracked
+// files included, with message, and returns the stash commit, or "" if there were none. The
+// entry stays in the stash until dropped with DropStash, so the changes aren't lost if applying
+// them elsewhere fails. With keep, the changes are put back in the checkout and the entry dropped
+// right away, leaving only the commit to apply.
+func StashCheckoutChanges(repoPath, message string, keep bool) (string, error) {
+	g := &GitWorktree{repoPath: repoPath, worktreePath: repoPath}
+	before := g.stashTop(repoPath)
+	if _, err := g.runGitCommand(repoPath, "stash", "push", "--include-untracked", "-m", message); err != nil {
+		return "", fmt.Errorf("failed to stash the changes of %s: %w", repoPath, err)
+	}
+	stash := g.stashTop(repoPath)
+	if stash == before {
+		// Nothing was stashed
+		return "", nil
+	}
+	if !keep {
+		return stash, nil
+	}
+	if _, err := g.runGitCommand(repoPath, "stash", "apply", "--index", stash); err != nil {
+		return "", fmt.Errorf("failed to put the changes back in %s, they're kept in its stash: %w", repoPath, err)
+	}
+	return stash, DropStash(repoPath, stash)
+}
+
+// DropStash removes the stash commit from the stash of the checkout at repoPath, if it's still
+// there.
+func DropStash(repoPath, stash string) error {
+	g := &GitWorktree{repoPath: repoPath, worktreePath: repoPath}
+	output, err := g.runGitCommand(repoPath, "stash", "list", "--format=%H")
+	if err != nil {
+		return fmt.Errorf("failed to list the stash of %s: %w", repoPath, err)
+	}
+	for idx, commit := range strings.Split(strings.TrimSpace(output), "\n") {
+		if commit != stash {
+			continue
+		}
+		if _, err := g.runGitCommand(repoPath, "stash", "drop", fmt.Sprintf("stash@{%d}", idx)); err != nil {
+			return fmt.Errorf("failed to drop %s from the stash: %w", shortSHA(stash), err)
+		}
+		return nil
+	}
+	return nil
+}
+
+// ApplyStash applies the changes of the stash commit, untracked files and what was staged
+// included, to the worktree.
+func (g *GitWorktree) ApplyStash(stash string) error {
+	if _, err := g.runGitCommand(g.worktreePath, "stash", "apply", "--index", stash); err != nil {
+		return fmt.Errorf("failed to apply the changes of stash %s: %w", shortSHA(stash), err)
+	}
+	return nil
+}
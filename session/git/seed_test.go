@@ -0,0 +1,92 @@
+package git
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSeedChanges(t *testing.T) {
+	for _, name := range []string{"GIT_AUTHOR_NAME", "GIT_COMMITTER_NAME"} {
+		t.Setenv(name, "test")
+	}
+	for _, name := range []string{"GIT_AUTHOR_EMAIL", "GIT_COMMITTER_EMAIL"} {
+		t.Setenv(name, "test@example.com")
+	}
+	dir := t.TempDir()
+	repo := filepath.Join(dir, "repo")
+	initRepo(t, repo)
+	git := func(args ...string) string {
+		t.Helper()
+		output, err := exec.Command("git", append([]string{"-C", repo}, args...)...).CombinedOutput()
+		if err != nil {
+			t.Fatalf("git %s: %v\n%s", strings.Join(args, " "), err, output)
+		}
+		return strings.TrimSpace(string(output))
+	}
+	write := func(path, content string) {
+		t.Helper()
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write(filepath.Join(repo, "tracked.txt"), "v1")
+	git("add", ".")
+	git("commit", "-q", "-m", "add tracked.txt")
+	head := git("rev-parse", "HEAD")
+
+	if changes, err := CheckoutChanges(repo); err != nil || changes != 0 {
+		t.Fatalf("CheckoutChanges() of a clean checkout = %d, %v", changes, err)
+	}
+	if stash, err := StashCheckoutChanges(repo, "seed", false); err != nil || stash != "" {
+		t.Fatalf("StashCheckoutChanges() of a clean checkout = %q, %v", stash, err)
+	}
+
+	write(filepath.Join(repo, "tracked.txt"), "v2")
+	write(filepath.Join(repo, "new.txt"), "new")
+	if changes, err := CheckoutChanges(repo); err != nil || changes != 2 {
+		t.Fatalf("CheckoutChanges() = %d, %v, want 2", changes, err)
+	}
+
+	// Copying leaves the checkout as it is and nothing in its stash
+	copied, err := StashCheckoutChanges(repo, "seed", true)
+	if err != nil || copied == "" {
+		t.Fatalf("StashCheckoutChanges(keep) = %q, %v", copied, err)
+	}
+	if changes, _ := CheckoutChanges(repo); changes != 2 {
+		t.Errorf("checkout has %d changed files after copying, want 2", changes)
+	}
+	if list := git("stash", "list"); list != "" {
+		t.Errorf("stash after copying = %q, want it empty", list)
+	}
+
+	// Moving cleans the checkout, keeping the changes in its stash until dropped
+	moved, err := StashCheckoutChanges(repo, "seed", false)
+	if err != nil || moved == "" {
+		t.Fatalf("StashCheckoutChanges() = %q, %v", moved, err)
+	}
+	if changes, _ := CheckoutChanges(repo); changes != 0 {
+		t.Errorf("checkout has %d changed files after moving, want none", changes)
+	}
+
+	worktree := filepath.Join(dir, "worktree")
+	git("worktree", "add", "-q", "-b", "seeded", worktree, head)
+	g := &GitWorktree{repoPath: repo, worktreePath: worktree}
+	if err := g.ApplyStash(moved); err != nil {
+		t.Fatal(err)
+	}
+	for name, want := range map[string]string{"tracked.txt": "v2", "new.txt": "new"} {
+		if got, err := os.ReadFile(filepath.Join(worktree, name)); err != nil || string(got) != want {
+			t.Errorf("%s in the worktree = %q, %v, want %q", name, got, err, want)
+		}
+	}
+
+	if err := DropStash(repo, moved); err != nil {
+		t.Fatal(err)
+	}
+	if list := git("stash", "list"); list != "" {
+		t.Errorf("stash after dropping = %q, want it empty", list)
+	}
+}
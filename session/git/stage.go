@@ -0,0 +1,191 @@
+package git
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// DiffHunk is one hunk of a file's diff.
+type DiffHunk struct {
+	// Path is the file the hunk changes
+	Path string
+	// Header is the file's diff header, from "diff --git" through "+++"
+	Header string
+	// Body is the hunk, from its "@@" line
+	Body string
+	// NewFile is true if the diff creates the file
+	NewFile bool
+	// Line is the line of the diff the hunk's "@@" line is on
+	Line int
+}
+
+// Patch returns the hunk as a patch git apply accepts.
+func (h DiffHunk) Patch() string {
+	return h.Header + h.Body
+}
+
+// ParseHunks splits a diff into its hunks, in order. Files without hunks, such as binary files
+// and mode changes, are skipped.
+func ParseHunks(diff string) []DiffHunk {
+	var hunks []DiffHunk
+	var header strings.Builder
+	var current *DiffHunk
+	var body strings.Builder
+	path, newFile := "", false
+
+	finish := func() {
+		if current != nil {
+			current.Body = body.String()
+			hunks = append(hunks, *current)
+			current = nil
+		}
+		body.Reset()
+	}
+
+	lines := strings.SplitAfter(diff, "\n")
+	for i, line := range lines {
+		switch {
+		case strings.HasPrefix(line, "diff --git "):
+			finish()
+			header.Reset()
+			header.WriteString(line)
+			path, newFile = "", false
+			if parts := strings.Fields(line); len(parts) >= 4 {
+				path = strings.TrimPrefix(parts[3], "b/")
+			}
+		case strings.HasPrefix(line, "@@"):
+			finish()
+			current = &DiffHunk{Path: path, Header: header.String(), NewFile: newFile, Line: i}
+			body.WriteString(line)
+		case current != nil:
+			body.WriteString(line)
+		default:
+			if strings.HasPrefix(line, "new file mode") {
+				newFile = true
+			}
+			header.WriteString(line)
+		}
+	}
+	finish()
+	return hunks
+}
+
+// StagingDiff returns the changes staged in the index and the unstaged changes in the worktree,
+// which include untracked files.
+func (g *GitWorktree) StagingDiff() (staged string, unstaged string, err error) {
+	// -N stages untracked files (intent to add), including them in the unstaged diff
+	if _, err := g.runGitCommand(g.worktreePath, "add", "-N", "."); err != nil {
+		return "", "", err
+	}
+	if staged, err = g.runGitCommand(g.worktreePath, "--no-pager", "diff", "--cached"); err != nil {
+		return "", "", fmt.Errorf("failed to get staged changes: %w", err)
+	}
+	if unstaged, err = g.runGitCommand(g.worktreePath, "--no-pager", "diff"); err != nil {
+		return "", "", fmt.Errorf("failed to get unstaged changes: %w", err)
+	}
+	return staged, unstaged, nil
+}
+
+// HasStagedChanges returns true if changes are staged in the index.
+func (g *GitWorktree) HasStagedChanges() (bool, error) {
+	output, err := g.runGitCommand(g.worktreePath, "diff", "--cached", "--name-only")
+	if err != nil {
+		return false, fmt.Errorf("failed to check for staged changes: %w", err)
+	}
+	return strings.TrimSpace(output) != "", nil
+}
+
+// StageHunk adds a hunk of the unstaged diff to the index.
+func (g *GitWorktree) StageHunk(hunk DiffHunk) error {
+	if err := g.applyHunk(hunk, "--cached"); err != nil {
+		return fmt.Errorf("failed to stage hunk in %s: %w", hunk.Path, err)
+	}
+	return nil
+}
+
+// UnstageHunk removes a hunk of the staged diff from the index, keeping it in the worktree.
+func (g *GitWorktree) UnstageHunk(hunk DiffHunk) error {
+	if err := g.applyHunk(hunk, "--cached", "--reverse"); err != nil {
+		return fmt.Errorf("failed to unstage hunk in %s: %w", hunk.Path, err)
+	}
+	return nil
+}
+
+// DiscardHunk reverts a hunk of the unstaged diff in the worktree. Discarding the only hunk of a
+// new file deletes it.
+func (g *GitWorktree) DiscardHunk(hunk DiffHunk) error {
+	if hunk.NewFile {
+		return g.DiscardFile(hunk.Path)
+	}
+	if err := g.applyHunk(hunk, "--reverse"); err != nil {
+		return fmt.Errorf("failed to discard hunk in %s: %w", hunk.Path, err)
+	}
+	return nil
+}
+
+// StageFile adds all of a file's changes to the index.
+func (g *GitWorktree) StageFile(path string) error {
+	if _, err := g.runGitCommand(g.worktreePath, "add", "--", path); err != nil {
+		return fmt.Errorf("failed to stage %s: %w", path, err)
+	}
+	return nil
+}
+
+// UnstageFile removes a file's staged changes from the index, keeping them in the worktree.
+func (g *GitWorktree) UnstageFile(path string) error {
+	if _, err := g.runGitCommand(g.worktreePath, "reset", "-q", "--", path); err != nil {
+		return fmt.Errorf("failed to unstage %s: %w", path, err)
+	}
+	return nil
+}
+
+// DiscardFile reverts a file's unstaged changes in the worktree, deleting it if it isn't in the
+// index yet.
+func (g *GitWorktree) DiscardFile(path string) error {
+	// Untracked files are only in the index as intent-to-add entries, so the unstaged diff adds them
+	added, err := g.runGitCommand(g.worktreePath, "diff", "--name-only", "--diff-filter=A", "--", path)
+	if err != nil {
+		return fmt.Errorf("failed to look up %s: %w", path, err)
+	}
+	if strings.TrimSpace(added) != "" {
+		if err := g.requireLocal("delete untracked files"); err != nil {
+			return err
+		}
+		if _, err := g.runGitCommand(g.worktreePath, "rm", "-q", "--cached", "--ignore-unmatch", "--", path); err != nil {
+			return fmt.Errorf("failed to remove %s from the index: %w", path, err)
+		}
+		if err := os.Remove(filepath.Join(g.worktreePath, path)); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to delete %s: %w", path, err)
+		}
+		return nil
+	}
+	if _, err := g.runGitCommand(g.worktreePath, "checkout", "--", path); err != nil {
+		return fmt.Errorf("failed to discard changes to %s: %w", path, err)
+	}
+	return nil
+}
+
+// applyHunk applies a single hunk with git apply and the given flags.
+func (g *GitWorktree) applyHunk(hunk DiffHunk, flags ...string) error {
+	// The patch is written to a file on this machine
+	if err := g.requireLocal("stage single hunks"); err != nil {
+		return err
+	}
+	patchFile, err := os.CreateTemp("", "claude-squad-hunk-*.patch")
+	if err != nil {
+		return fmt.Errorf("failed to create temp patch file: %w", err)
+	}
+	defer os.Remove(patchFile.Name())
+
+	if _, err := patchFile.WriteString(hunk.Patch()); err != nil {
+		patchFile.Close()
+		return fmt.Errorf("failed to write temp patch file: %w", err)
+	}
+	patchFile.Close()
+
+	args := append([]string{"apply"}, flags...)
+	_, err = g.runGitCommand(g.worktreePath, append(args, patchFile.Name())...)
+	return err
+}
@@ -0,0 +1,100 @@
+package git
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestStageAndDiscardHunks(t *testing.T) {
+	repo := filepath.Join(t.TempDir(), "repo")
+	initRepo(t, repo)
+	git := func(args ...string) string {
+		t.Helper()
+		output, err := exec.Command("git", append([]string{"-C", repo, "-c", "user.name=test", "-c", "user.email=test@example.com"}, args...)...).CombinedOutput()
+		if err != nil {
+			t.Fatalf("git %s: %v\n%s", strings.Join(args, " "), err, output)
+		}
+		return string(output)
+	}
+	write := func(name, content string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(repo, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Two hunks far enough apart that git keeps them separate
+	var lines []string
+	for i := 0; i < 20; i++ {
+		lines = append(lines, "line")
+	}
+	write("a.txt", strings.Join(lines, "\n")+"\n")
+	git("add", "a.txt")
+	git("commit", "-q", "-m", "add a")
+	lines[1], lines[18] = "first", "second"
+	write("a.txt", strings.Join(lines, "\n")+"\n")
+	write("new.txt", "new\n")
+
+	g := &GitWorktree{repoPath: repo, worktreePath: repo}
+	_, unstaged, err := g.StagingDiff()
+	if err != nil {
+		t.Fatal(err)
+	}
+	hunks := ParseHunks(unstaged)
+	if len(hunks) != 3 || hunks[0].Path != "a.txt" || hunks[2].Path != "new.txt" || !hunks[2].NewFile {
+		t.Fatalf("ParseHunks() = %+v, want two hunks of a.txt and one of new.txt", hunks)
+	}
+	if staged, _ := g.HasStagedChanges(); staged {
+		t.Error("HasStagedChanges() = true before staging anything")
+	}
+
+	if err := g.StageHunk(hunks[1]); err != nil {
+		t.Fatal(err)
+	}
+	if cached := git("diff", "--cached"); !strings.Contains(cached, "+second") || strings.Contains(cached, "+first") {
+		t.Errorf("staged diff after staging the second hunk:\n%s", cached)
+	}
+	if staged, _ := g.HasStagedChanges(); !staged {
+		t.Error("HasStagedChanges() = false after staging a hunk")
+	}
+
+	staged, _, err := g.StagingDiff()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := g.UnstageHunk(ParseHunks(staged)[0]); err != nil {
+		t.Fatal(err)
+	}
+	if cached := git("diff", "--cached"); cached != "" {
+		t.Errorf("staged diff after unstaging = %q, want it empty", cached)
+	}
+
+	if err := g.DiscardHunk(hunks[0]); err != nil {
+		t.Fatal(err)
+	}
+	if err := g.DiscardHunk(hunks[2]); err != nil {
+		t.Fatal(err)
+	}
+	if diff := git("diff"); strings.Contains(diff, "+first") || !strings.Contains(diff, "+second") {
+		t.Errorf("unstaged diff after discarding the first hunk:\n%s", diff)
+	}
+	if _, err := os.Stat(filepath.Join(repo, "new.txt")); !os.IsNotExist(err) {
+		t.Error("discarding the hunk of a new file kept the file")
+	}
+
+	if err := g.StageFile("a.txt"); err != nil {
+		t.Fatal(err)
+	}
+	if err := g.UnstageFile("a.txt"); err != nil {
+		t.Fatal(err)
+	}
+	if err := g.DiscardFile("a.txt"); err != nil {
+		t.Fatal(err)
+	}
+	if status := git("status", "--porcelain"); status != "" {
+		t.Errorf("status after discarding every change = %q, want a clean tree", status)
+	}
+}
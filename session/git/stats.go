@@ -0,0 +1,182 @@
+package git
+
+import (
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// slowestKept is how many of the slowest commands are remembered.
+const slowestKept = 15
+
+// CommandStat aggregates the runs of one git subcommand.
+type CommandStat struct {
+	Name     string
+	Count    int
+	Failures int
+	Total    time.Duration
+	Max      time.Duration
+}
+
+// Mean returns the average duration of a run.
+func (s CommandStat) Mean() time.Duration {
+	if s.Count == 0 {
+		return 0
+	}
+	return s.Total / time.Duration(s.Count)
+}
+
+// CommandRun is a single timed git command.
+type CommandRun struct {
+	Args     string
+	Dir      string
+	Duration time.Duration
+	Failed   bool
+	At       time.Time
+}
+
+// CacheStat counts the lookups of a cache in front of git commands.
+type CacheStat struct {
+	Name   string
+	Hits   int
+	Misses int
+}
+
+// HitRate returns the fraction of lookups served from the cache.
+func (s CacheStat) HitRate() float64 {
+	if s.Hits+s.Misses == 0 {
+		return 0
+	}
+	return float64(s.Hits) / float64(s.Hits+s.Misses)
+}
+
+// Stats is a snapshot of the git command timings since Since.
+type Stats struct {
+	Since time.Time
+	// Commands is sorted by total time, highest first.
+	Commands []CommandStat
+	// Slowest holds the slowest runs, slowest first.
+	Slowest []CommandRun
+	// Caches is sorted by name.
+	Caches []CacheStat
+}
+
+// statsRecorder collects timings from every goroutine running git commands.
+type statsRecorder struct {
+	mu       sync.Mutex
+	since    time.Time
+	commands map[string]*CommandStat
+	slowest  []CommandRun
+	caches   map[string]*CacheStat
+}
+
+var commandStats = newStatsRecorder()
+
+func newStatsRecorder() *statsRecorder {
+	return &statsRecorder{
+		since:    time.Now(),
+		commands: make(map[string]*CommandStat),
+		caches:   make(map[string]*CacheStat),
+	}
+}
+
+func (r *statsRecorder) recordCommand(dir string, args []string, duration time.Duration, failed bool) {
+	name := subcommand(args)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stat, ok := r.commands[name]
+	if !ok {
+		stat = &CommandStat{Name: name}
+		r.commands[name] = stat
+	}
+	stat.Count++
+	stat.Total += duration
+	stat.Max = max(stat.Max, duration)
+	if failed {
+		stat.Failures++
+	}
+
+	if len(r.slowest) == slowestKept && duration <= r.slowest[len(r.slowest)-1].Duration {
+		return
+	}
+	run := CommandRun{Args: strings.Join(args, " "), Dir: dir, Duration: duration, Failed: failed, At: time.Now()}
+	i := sort.Search(len(r.slowest), func(i int) bool { return r.slowest[i].Duration < duration })
+	r.slowest = append(r.slowest, CommandRun{})
+	copy(r.slowest[i+1:], r.slowest[i:])
+	r.slowest[i] = run
+	if len(r.slowest) > slowestKept {
+		r.slowest = r.slowest[:slowestKept]
+	}
+}
+
+func (r *statsRecorder) recordCacheLookup(cache string, hit bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	stat, ok := r.caches[cache]
+	if !ok {
+		stat = &CacheStat{Name: cache}
+		r.caches[cache] = stat
+	}
+	if hit {
+		stat.Hits++
+	} else {
+		stat.Misses++
+	}
+}
+
+func (r *statsRecorder) snapshot() Stats {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	stats := Stats{Since: r.since, Slowest: append([]CommandRun(nil), r.slowest...)}
+	for _, stat := range r.commands {
+		stats.Commands = append(stats.Commands, *stat)
+	}
+	sort.Slice(stats.Commands, func(i, j int) bool {
+		if stats.Commands[i].Total != stats.Commands[j].Total {
+			return stats.Commands[i].Total > stats.Commands[j].Total
+		}
+		return stats.Commands[i].Name < stats.Commands[j].Name
+	})
+	for _, stat := range r.caches {
+		stats.Caches = append(stats.Caches, *stat)
+	}
+	sort.Slice(stats.Caches, func(i, j int) bool { return stats.Caches[i].Name < stats.Caches[j].Name })
+	return stats
+}
+
+// RecordCacheLookup counts a lookup of the named cache, which saves a git command on a hit.
+func RecordCacheLookup(cache string, hit bool) {
+	commandStats.recordCacheLookup(cache, hit)
+}
+
+// CommandStats returns the git command timings recorded since the program started or the last
+// ResetCommandStats.
+func CommandStats() Stats {
+	return commandStats.snapshot()
+}
+
+// ResetCommandStats discards the recorded timings.
+func ResetCommandStats() {
+	commandStats.mu.Lock()
+	defer commandStats.mu.Unlock()
+	commandStats.since = time.Now()
+	commandStats.commands = make(map[string]*CommandStat)
+	commandStats.slowest = nil
+	commandStats.caches = make(map[string]*CacheStat)
+}
+
+// subcommand returns the git subcommand of args, skipping global options such as "-c key=value".
+func subcommand(args []string) string {
+	for i := 0; i < len(args); i++ {
+		switch {
+		case args[i] == "-c" || args[i] == "-C":
+			i++
+		case strings.HasPrefix(args[i], "-"):
+		default:
+			return args[i]
+		}
+	}
+	return "git"
+}
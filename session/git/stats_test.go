@@ -0,0 +1,48 @@
+package git
+
+import (
+	"testing"
+	"time"
+)
+
+func TestStatsRecorder(t *testing.T) {
+	r := newStatsRecorder()
+	r.recordCommand("/repo", []string{"diff", "--stat"}, 30*time.Millisecond, false)
+	r.recordCommand("/repo", []string{"diff", "--stat"}, 10*time.Millisecond, true)
+	r.recordCommand("/repo", []string{"-c", "core.editor=true", "rebase", "--continue"}, 50*time.Millisecond, false)
+	r.recordCacheLookup("diff stats", true)
+	r.recordCacheLookup("diff stats", true)
+	r.recordCacheLookup("diff stats", false)
+
+	stats := r.snapshot()
+	if len(stats.Commands) != 2 {
+		t.Fatalf("got %d commands, want 2", len(stats.Commands))
+	}
+	diff := stats.Commands[1]
+	if stats.Commands[0].Name != "rebase" || diff.Name != "diff" {
+		t.Errorf("commands = %q, %q, want rebase then diff", stats.Commands[0].Name, diff.Name)
+	}
+	if diff.Count != 2 || diff.Failures != 1 || diff.Max != 30*time.Millisecond || diff.Mean() != 20*time.Millisecond {
+		t.Errorf("diff stat = %+v", diff)
+	}
+	if len(stats.Slowest) != 3 || stats.Slowest[0].Duration != 50*time.Millisecond || stats.Slowest[2].Duration != 10*time.Millisecond {
+		t.Errorf("slowest = %+v", stats.Slowest)
+	}
+	if len(stats.Caches) != 1 || stats.Caches[0].Hits != 2 || stats.Caches[0].Misses != 1 {
+		t.Errorf("caches = %+v", stats.Caches)
+	}
+}
+
+func TestStatsRecorderKeepsSlowest(t *testing.T) {
+	r := newStatsRecorder()
+	for i := 1; i <= slowestKept+5; i++ {
+		r.recordCommand("/repo", []string{"status"}, time.Duration(i)*time.Millisecond, false)
+	}
+	slowest := r.snapshot().Slowest
+	if len(slowest) != slowestKept {
+		t.Fatalf("kept %d runs, want %d", len(slowest), slowestKept)
+	}
+	if slowest[0].Duration != time.Duration(slowestKept+5)*time.Millisecond || slowest[slowestKept-1].Duration != 6*time.Millisecond {
+		t.Errorf("slowest runs range from %s to %s", slowest[0].Duration, slowest[slowestKept-1].Duration)
+	}
+}
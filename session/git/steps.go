@@ -0,0 +1,50 @@
+package git
+
+import (
+	"claude-squad/log"
+	"errors"
+	"fmt"
+)
+
+// StepFunc is called as each step of a multi-step operation, such as updating a branch with
+// main, starts.
+type StepFunc func(step string)
+
+// ErrAborted is returned by a multi-step operation stopped between steps because its context
+// was done.
+var ErrAborted = errors.New("aborted")
+
+// WithSteps returns a copy of the worktree that reports the steps of multi-step operations to
+// fn. The copy shares all state with the original apart from the callback.
+func (g *GitWorktree) WithSteps(fn StepFunc) *GitWorktree {
+	c := *g
+	c.steps = fn
+	return &c
+}
+
+// step logs the start of a step and reports it to the steps callback, if any.
+func (g *GitWorktree) step(format string, args ...any) {
+	step := fmt.Sprintf(format, args...)
+	log.InfoLog.Printf("%s: %s", g.branchName, step)
+	if g.steps != nil {
+		g.steps(step)
+	}
+}
+
+// checkAborted returns ErrAborted once the worktree's context is done, so an operation can stop
+// before its next step.
+func (g *GitWorktree) checkAborted() error {
+	if g.ctx != nil && g.ctx.Err() != nil {
+		return ErrAborted
+	}
+	return nil
+}
+
+// abortedOr returns ErrAborted if the worktree's context is done, as a command failing then was
+// most likely killed by the abort, and err otherwise.
+func (g *GitWorktree) abortedOr(err error) error {
+	if aborted := g.checkAborted(); aborted != nil {
+		return aborted
+	}
+	return err
+}
@@ -0,0 +1,47 @@
+package git
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRebaseWithMainSteps(t *testing.T) {
+	repo, g, git := initFeatureWorktree(t, t.TempDir())
+	if err := os.WriteFile(filepath.Join(repo, "main.txt"), []byte("main"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	git("-C", repo, "add", ".")
+	git("-C", repo, "commit", "-q", "-m", "change main.txt")
+	git("-C", repo, "push", "-q", "origin", "main")
+
+	var steps []string
+	record := func(step string) { steps = append(steps, step) }
+
+	// An aborted operation stops before its next step
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := g.WithContext(ctx).WithSteps(record).RebaseWithMain(); !errors.Is(err, ErrAborted) {
+		t.Fatalf("RebaseWithMain() after an abort = %v, want ErrAborted", err)
+	}
+	if len(steps) != 2 || steps[0] != "Backing up the branch" || !strings.HasPrefix(steps[1], "Created and pushed backup branch") {
+		t.Fatalf("steps before the abort = %q", steps)
+	}
+
+	steps = nil
+	if err := g.WithSteps(record).RebaseWithMain(); err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"Backing up the branch", "Commit already backed up in branch", "Fetching origin", "Rebasing onto origin/main"}
+	if len(steps) != len(want) {
+		t.Fatalf("steps = %q, want %q", steps, want)
+	}
+	for i, step := range steps {
+		if !strings.HasPrefix(step, want[i]) {
+			t.Errorf("step %d = %q, want %q", i, step, want[i])
+		}
+	}
+}
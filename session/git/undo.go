@@ -0,0 +1,79 @@
+package git
+
+import (
+	"fmt"
+	"strings"
+)
+
+// WorktreeState is a commit of a worktree and its uncommitted changes, to go back to after an
+// operation like a reset discarded them.
+type WorktreeState struct {
+	Commit string
+	// Changes is a stash commit of the uncommitted changes to tracked files, or "" if there
+	// were none
+	Changes string
+}
+
+// SaveState records the worktree's commit and uncommitted changes without touching it.
+func (g *GitWorktree) SaveState() (WorktreeState, error) {
+	commit, err := g.GetCurrentCommitSHA()
+	if err != nil {
+		return WorktreeState{}, err
+	}
+	changes, err := g.runGitCommand(g.worktreePath, "stash", "create")
+	if err != nil {
+		return WorktreeState{}, fmt.Errorf("failed to record uncommitted changes: %w", err)
+	}
+	return WorktreeState{Commit: commit, Changes: strings.TrimSpace(changes)}, nil
+}
+
+// RestoreState resets the worktree's branch to the state's commit and reapplies its
+// uncommitted changes. What the worktree holds now is discarded.
+func (g *GitWorktree) RestoreState(state WorktreeState) error {
+	if _, err := g.runGitCommand(g.worktreePath, "reset", "--hard", state.Commit); err != nil {
+		return fmt.Errorf("failed to reset to %s: %w", shortSHA(state.Commit), err)
+	}
+	if state.Changes == "" {
+		return nil
+	}
+	if _, err := g.runGitCommand(g.worktreePath, "stash", "apply", state.Changes); err != nil {
+		return fmt.Errorf("reset to %s but failed to reapply the uncommitted changes (stash %s): %w",
+			shortSHA(state.Commit), shortSHA(state.Changes), err)
+	}
+	return nil
+}
+
+// BranchCommit returns the commit the worktree's branch points at. It works without the
+// worktree, e.g. for a paused instance.
+func (g *GitWorktree) BranchCommit() (string, error) {
+	commit, err := g.runGitCommand(g.repoPath, "rev-parse", "--verify", "refs/heads/"+g.branchName)
+	if err != nil {
+		return "", fmt.Errorf("failed to get the commit of branch %s: %w", g.branchName, err)
+	}
+	return strings.TrimSpace(commit), nil
+}
+
+// RestoreDeletedBranch recreates a deleted branch at commit, under restored/ if a branch of that
+// name exists again. It returns the name of the recreated branch.
+func RestoreDeletedBranch(repoPath, branch, commit string) (string, error) {
+	g := &GitWorktree{repoPath: repoPath}
+	if _, err := g.runGitCommand(repoPath, "cat-file", "-e", commit+"^{commit}"); err != nil {
+		return "", fmt.Errorf("commit %s of branch %s no longer exists", shortSHA(commit), branch)
+	}
+	name := branch
+	if _, err := g.runGitCommand(repoPath, "rev-parse", "--verify", "refs/heads/"+branch); err == nil {
+		name = "restored/" + branch
+	}
+	if _, err := g.runGitCommand(repoPath, "branch", name, commit); err != nil {
+		return "", fmt.Errorf("failed to recreate branch %s: %w", name, err)
+	}
+	return name, nil
+}
+
+// shortSHA abbreviates a commit SHA for messages.
+func shortSHA(sha string) string {
+	if len(sha) > 8 {
+		return sha[:8]
+	}
+	return sha
+}
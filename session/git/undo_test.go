@@ -0,0 +1,79 @@
+package git
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRestoreState(t *testing.T) {
+	t.Setenv("GIT_AUTHOR_NAME", "test")
+	t.Setenv("GIT_AUTHOR_EMAIL", "test@example.com")
+	t.Setenv("GIT_COMMITTER_NAME", "test")
+	t.Setenv("GIT_COMMITTER_EMAIL", "test@example.com")
+	repo := filepath.Join(t.TempDir(), "repo")
+	initRepo(t, repo)
+	git := func(args ...string) string {
+		t.Helper()
+		output, err := exec.Command("git", append([]string{"-C", repo}, args...)...).CombinedOutput()
+		if err != nil {
+			t.Fatalf("git %s: %v\n%s", strings.Join(args, " "), err, output)
+		}
+		return strings.TrimSpace(string(output))
+	}
+	file := filepath.Join(repo, "file.txt")
+	write := func(content string) {
+		t.Helper()
+		if err := os.WriteFile(file, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("committed")
+	git("add", ".")
+	git("commit", "-q", "-m", "work")
+	write("uncommitted")
+
+	g := &GitWorktree{repoPath: repo, worktreePath: repo}
+	state, err := g.SaveState()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if state.Changes == "" {
+		t.Fatal("SaveState() didn't record the uncommitted changes")
+	}
+
+	// A hard reset to an older commit loses both
+	git("reset", "-q", "--hard", "HEAD~1")
+	if err := g.RestoreState(state); err != nil {
+		t.Fatal(err)
+	}
+	if head := git("rev-parse", "HEAD"); head != state.Commit {
+		t.Errorf("HEAD = %s after RestoreState(), want %s", head, state.Commit)
+	}
+	if content, _ := os.ReadFile(file); string(content) != "uncommitted" {
+		t.Errorf("file.txt = %q after RestoreState(), want the uncommitted changes back", content)
+	}
+}
+
+func TestRestoreDeletedBranch(t *testing.T) {
+	repo := filepath.Join(t.TempDir(), "repo")
+	initRepo(t, repo)
+	output, err := exec.Command("git", "-C", repo, "rev-parse", "HEAD").Output()
+	if err != nil {
+		t.Fatal(err)
+	}
+	commit := strings.TrimSpace(string(output))
+
+	if branch, err := RestoreDeletedBranch(repo, "feature", commit); err != nil || branch != "feature" {
+		t.Errorf("RestoreDeletedBranch() = %q, %v, want the branch back", branch, err)
+	}
+	// A branch of the same name is left alone
+	if branch, err := RestoreDeletedBranch(repo, "feature", commit); err != nil || branch != "restored/feature" {
+		t.Errorf("RestoreDeletedBranch() with the name taken = %q, %v", branch, err)
+	}
+	if _, err := RestoreDeletedBranch(repo, "gone", strings.Repeat("0", 40)); err == nil {
+		t.Error("RestoreDeletedBranch() succeeded for a missing commit")
+	}
+}
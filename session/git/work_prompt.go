@@ -0,0 +1,153 @@
+package git
+
+import (
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// maxLinkedIssues is how many of the issues a pull request closes are fetched for its work prompt.
+const maxLinkedIssues = 5
+
+// maxPromptSection is how many characters of a pull request or issue description go into a work
+// prompt.
+const maxPromptSection = 4000
+
+// Issue is an issue in the repository's tracker.
+type Issue struct {
+	Number int
+	Title  string
+	Body   string
+	URL    string
+}
+
+// closingReferencePattern matches the keywords GitHub and GitLab use to link a pull request to
+// the issues it closes, e.g. "Fixes #12".
+var closingReferencePattern = regexp.MustCompile(`(?i)\b(?:close[sd]?|fix(?:e[sd])?|resolve[sd]?|implement(?:s|ed)?):?\s+#(\d+)\b`)
+
+// LinkedIssueNumbers returns the numbers of the issues a pull request description says it
+// closes, in order of mention and without duplicates.
+func LinkedIssueNumbers(body string) []int {
+	var numbers []int
+	seen := make(map[int]bool)
+	for _, match := range closingReferencePattern.FindAllStringSubmatch(body, -1) {
+		number, err := strconv.Atoi(match[1])
+		if err != nil || seen[number] {
+			continue
+		}
+		seen[number] = true
+		numbers = append(numbers, number)
+	}
+	return numbers
+}
+
+// FetchLinkedIssues returns the issues the pull request's description says it closes.
+func (pr *PullRequest) FetchLinkedIssues(workingDir string) ([]*Issue, error) {
+	numbers := LinkedIssueNumbers(pr.Body)
+	if len(numbers) > maxLinkedIssues {
+		numbers = numbers[:maxLinkedIssues]
+	}
+	forge := pr.forgeFor(workingDir)
+	issues := make([]*Issue, 0, len(numbers))
+	for _, number := range numbers {
+		issue, err := forge.Issue(workingDir, number)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get issue #%d: %w", number, err)
+		}
+		issues = append(issues, issue)
+	}
+	return issues, nil
+}
+
+// githubIssue returns a GitHub issue through gh.
+func githubIssue(dir string, number int) (*Issue, error) {
+	cmd := exec.Command("gh", "issue", "view", strconv.Itoa(number), "--json", "number,title,body,url")
+	cmd.Dir = dir
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return nil, fmt.Errorf("gh issue view failed (output: %s): %w", strings.TrimSpace(string(output)), err)
+	}
+	var issue Issue
+	if err := json.Unmarshal(output, &issue); err != nil {
+		return nil, fmt.Errorf("failed to parse issue: %w", err)
+	}
+	return &issue, nil
+}
+
+// gitlabIssue returns a GitLab issue through glab.
+func gitlabIssue(dir string, number int) (*Issue, error) {
+	output, err := glabAPI(dir, fmt.Sprintf("projects/:id/issues/%d", number))
+	if err != nil {
+		return nil, err
+	}
+	return parseGitLabIssue(output)
+}
+
+// parseGitLabIssue parses a single issue response.
+func parseGitLabIssue(data []byte) (*Issue, error) {
+	var issue struct {
+		IID         int    `json:"iid"`
+		Title       string `json:"title"`
+		Description string `json:"description"`
+		WebURL      string `json:"web_url"`
+	}
+	if err := json.Unmarshal(data, &issue); err != nil {
+		return nil, fmt.Errorf("failed to parse issue: %w", err)
+	}
+	return &Issue{Number: issue.IID, Title: issue.Title, Body: issue.Description, URL: issue.WebURL}, nil
+}
+
+// WorkPrompt composes a prompt asking the agent to resume work on the pull request: its title
+// and description, the issues it closes and the CI checks that failed on it.
+func WorkPrompt(pr *PullRequest, issues []*Issue, checks []CICheck) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Continue working on pull request #%d: %s\n%s\n", pr.Number, pr.Title, pr.URL)
+	if body := promptSection(pr.Body); body != "" {
+		fmt.Fprintf(&b, "\n## Description\n\n%s\n", body)
+	}
+	for _, issue := range issues {
+		fmt.Fprintf(&b, "\n## Issue #%d: %s\n%s\n", issue.Number, issue.Title, issue.URL)
+		if body := promptSection(issue.Body); body != "" {
+			fmt.Fprintf(&b, "\n%s\n", body)
+		}
+	}
+
+	var failed []CICheck
+	for _, check := range checks {
+		if check.State == CIStateFailure {
+			failed = append(failed, check)
+		}
+	}
+	if len(failed) > 0 {
+		b.WriteString("\n## Failing CI checks\n\n")
+		for _, check := range failed {
+			b.WriteString("- " + check.Name)
+			if check.Summary != "" {
+				b.WriteString(": " + check.Summary)
+			}
+			if check.URL != "" {
+				b.WriteString(" (" + check.URL + ")")
+			}
+			b.WriteString("\n")
+		}
+	}
+
+	b.WriteString("\nReview what the branch already changed, then finish the work described above")
+	if len(failed) > 0 {
+		b.WriteString(", starting with the failing checks")
+	}
+	b.WriteString(".")
+	return b.String()
+}
+
+// promptSection trims a description and cuts it to maxPromptSection characters.
+func promptSection(text string) string {
+	text = strings.TrimSpace(strings.ReplaceAll(text, "\r\n", "\n"))
+	if runes := []rune(text); len(runes) > maxPromptSection {
+		text = strings.TrimSpace(string(runes[:maxPromptSection])) + "\n[...]"
+	}
+	return text
+}
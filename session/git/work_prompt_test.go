@@ -0,0 +1,58 @@
+package git
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestLinkedIssueNumbers(t *testing.T) {
+	body := "Fixes #12 and closes: #7.\nRelated to #99, resolves #12 again. Implements #3"
+	if got, want := LinkedIssueNumbers(body), []int{12, 7, 3}; !reflect.DeepEqual(got, want) {
+		t.Errorf("LinkedIssueNumbers() = %v, want %v", got, want)
+	}
+	if got := LinkedIssueNumbers("No issue here, see #4"); got != nil {
+		t.Errorf("LinkedIssueNumbers() = %v, want none", got)
+	}
+}
+
+func TestParseGitLabIssue(t *testing.T) {
+	issue, err := parseGitLabIssue([]byte(`{"iid":4,"title":"Crash","description":"Steps","web_url":"https://gitlab.com/o/r/-/issues/4"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := Issue{Number: 4, Title: "Crash", Body: "Steps", URL: "https://gitlab.com/o/r/-/issues/4"}
+	if *issue != want {
+		t.Errorf("parseGitLabIssue() = %+v, want %+v", *issue, want)
+	}
+}
+
+func TestWorkPrompt(t *testing.T) {
+	pr := &PullRequest{Number: 5, Title: "Add export", Body: "Adds CSV export.\r\n\r\nFixes #4", URL: "https://github.com/o/r/pull/5"}
+	issues := []*Issue{{Number: 4, Title: "Export data", Body: "Users want CSV.", URL: "https://github.com/o/r/issues/4"}}
+	checks := []CICheck{
+		{Name: "build", State: CIStateSuccess},
+		{Name: "test", State: CIStateFailure, Summary: "2 tests failed", URL: "https://ci/3"},
+	}
+
+	got := WorkPrompt(pr, issues, checks)
+	for _, want := range []string{
+		"Continue working on pull request #5: Add export\nhttps://github.com/o/r/pull/5\n",
+		"## Description\n\nAdds CSV export.\n\nFixes #4\n",
+		"## Issue #4: Export data\nhttps://github.com/o/r/issues/4\n\nUsers want CSV.\n",
+		"## Failing CI checks\n\n- test: 2 tests failed (https://ci/3)\n",
+		"starting with the failing checks.",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("WorkPrompt() = %q, want it to contain %q", got, want)
+		}
+	}
+	if strings.Contains(got, "build") {
+		t.Errorf("WorkPrompt() = %q, want passing checks left out", got)
+	}
+
+	got = WorkPrompt(&PullRequest{Number: 6, Title: "Tidy", Body: strings.Repeat("x", maxPromptSection+10)}, nil, nil)
+	if strings.Contains(got, "Failing CI checks") || !strings.Contains(got, "[...]") {
+		t.Errorf("WorkPrompt() = %q, want a cut description and no checks", got)
+	}
+}
@@ -3,8 +3,10 @@ package git
 import (
 	"claude-squad/config"
 	"claude-squad/log"
+	"context"
 	"fmt"
 	"path/filepath"
+	"strings"
 	"time"
 )
 
@@ -17,6 +19,40 @@ func getWorktreeDirectory() (string, error) {
 	return filepath.Join(configDir, "worktrees"), nil
 }
 
+// WorktreeOptions configures where a new worktree is created and how its branch is named,
+// without reading the user's config.
+type WorktreeOptions struct {
+	// Dir is the directory worktrees are created in. Empty uses the worktrees directory in the
+	// config directory.
+	Dir string
+	// BranchPrefix starts the name of a new branch.
+	BranchPrefix string
+}
+
+// newWorktreePath returns a unique path for a session's worktree in dir, or in the default
+// worktree directory if dir is empty.
+func newWorktreePath(dir, sessionName string) (string, error) {
+	if dir == "" {
+		var err error
+		if dir, err = getWorktreeDirectory(); err != nil {
+			return "", err
+		}
+	}
+	return filepath.Join(dir, sanitizeBranchName(sessionName)) + "_" + fmt.Sprintf("%x", time.Now().UnixNano()), nil
+}
+
+// FindRepoRoot returns the root of the repository containing repoPath.
+func FindRepoRoot(repoPath string) (string, error) {
+	// Convert repoPath to absolute path
+	absPath, err := filepath.Abs(repoPath)
+	if err != nil {
+		log.ErrorLog.Printf("git worktree path abs error, falling back to repoPath %s: %s", repoPath, err)
+		// If we can't get absolute path, use original path as fallback
+		absPath = repoPath
+	}
+	return findGitRepoRoot(absPath)
+}
+
 // GitWorktree manages git worktree operations for a session
 type GitWorktree struct {
 	// Path to the repository
@@ -29,6 +65,55 @@ type GitWorktree struct {
 	branchName string
 	// Base commit hash for the worktree
 	baseCommitSHA string
+	// progress receives progress updates from long-running network operations. May be nil.
+	progress ProgressFunc
+	// ctx stops the network operations reporting progress once done. May be nil.
+	ctx context.Context
+	// steps receives the steps of multi-step operations as they start. May be nil.
+	steps StepFunc
+	// baseRef is the branch, tag or commit new worktrees are created from. Empty means the remote
+	// default branch.
+	baseRef string
+	// history caches the branch's commits for browsing them. Nil for worktrees only used for a
+	// single command, which don't cache.
+	history *commitHistory
+	// requestedBranch is the branch the worktree was created for if it was checked out elsewhere
+	// and branchName is a suffixed copy of it
+	requestedBranch string
+	// pushBranch is the remote branch pushes go to if it isn't branchName
+	pushBranch string
+	// dryRun records the commands that would change anything instead of running them. Nil
+	// outside DryRun.
+	dryRun *commandRecorder
+	// runner runs the worktree's commands on the machine it's on. Nil for this machine.
+	runner Runner
+	// cfg and policy are the config and policy the worktree's rebases and pushes follow. Nil
+	// reads the user's.
+	cfg    *config.Config
+	policy *config.Policy
+}
+
+// SetConfig makes the worktree's rebases and pushes follow cfg and policy instead of the user's
+// config and policy.
+func (g *GitWorktree) SetConfig(cfg *config.Config, policy *config.Policy) {
+	g.cfg = cfg
+	g.policy = policy
+}
+
+// settings returns the config the worktree follows.
+func (g *GitWorktree) settings() *config.Config {
+	if g.cfg != nil {
+		return g.cfg
+	}
+	return config.LoadConfig()
+}
+
+// loadPolicy returns the policy the worktree follows.
+func (g *GitWorktree) loadPolicy() (*config.Policy, error) {
+	if g.policy != nil {
+		return g.policy, nil
+	}
+	return config.LoadPolicy()
 }
 
 func NewGitWorktreeFromStorage(repoPath string, worktreePath string, sessionName string, branchName string, baseCommitSHA string) *GitWorktree {
@@ -38,76 +123,103 @@ func NewGitWorktreeFromStorage(repoPath string, worktreePath string, sessionName
 		sessionName:   sessionName,
 		branchName:    branchName,
 		baseCommitSHA: baseCommitSHA,
+		history:       &commitHistory{},
 	}
 }
 
-// NewGitWorktree creates a new GitWorktree instance
-func NewGitWorktree(repoPath string, sessionName string) (tree *GitWorktree, branchname string, err error) {
-	cfg := config.LoadConfig()
-	sanitizedName := sanitizeBranchName(sessionName)
-	branchName := fmt.Sprintf("%s%s", cfg.BranchPrefix, sanitizedName)
+// NewGitWorktreeWithOptions creates a new GitWorktree instance on a new branch, configured by opts
+// rather than the user's config.
+func NewGitWorktreeWithOptions(repoPath string, sessionName string, opts WorktreeOptions) (tree *GitWorktree, branchname string, err error) {
+	branchName := fmt.Sprintf("%s%s", opts.BranchPrefix, sanitizeBranchName(sessionName))
 
-	// Convert repoPath to absolute path
-	absPath, err := filepath.Abs(repoPath)
-	if err != nil {
-		log.ErrorLog.Printf("git worktree path abs error, falling back to repoPath %s: %s", repoPath, err)
-		// If we can't get absolute path, use original path as fallback
-		absPath = repoPath
-	}
-
-	repoPath, err = findGitRepoRoot(absPath)
+	repoPath, err = FindRepoRoot(repoPath)
 	if err != nil {
 		return nil, "", err
 	}
 
-	worktreeDir, err := getWorktreeDirectory()
+	worktreePath, err := newWorktreePath(opts.Dir, sessionName)
 	if err != nil {
 		return nil, "", err
 	}
 
-	worktreePath := filepath.Join(worktreeDir, sanitizedName)
-	worktreePath = worktreePath + "_" + fmt.Sprintf("%x", time.Now().UnixNano())
-
 	return &GitWorktree{
 		repoPath:     repoPath,
 		sessionName:  sessionName,
 		branchName:   branchName,
 		worktreePath: worktreePath,
+		history:      &commitHistory{},
 	}, branchName, nil
 }
 
 // NewGitWorktreeForBranch creates a new GitWorktree instance for an existing branch
 func NewGitWorktreeForBranch(repoPath string, sessionName string, branchName string) (tree *GitWorktree, branchname string, err error) {
-	// Convert repoPath to absolute path
-	absPath, err := filepath.Abs(repoPath)
-	if err != nil {
-		log.ErrorLog.Printf("git worktree path abs error, falling back to repoPath %s: %s", repoPath, err)
-		// If we can't get absolute path, use original path as fallback
-		absPath = repoPath
-	}
+	return NewGitWorktreeForBranchInDir(repoPath, sessionName, branchName, "")
+}
 
-	repoPath, err = findGitRepoRoot(absPath)
+// NewGitWorktreeForBranchInDir creates a new GitWorktree instance for an existing branch with its
+// worktree in dir. Empty dir uses the worktrees directory in the config directory.
+func NewGitWorktreeForBranchInDir(repoPath string, sessionName string, branchName string, dir string) (tree *GitWorktree, branchname string, err error) {
+	repoPath, err = FindRepoRoot(repoPath)
 	if err != nil {
 		return nil, "", err
 	}
 
-	worktreeDir, err := getWorktreeDirectory()
+	worktreePath, err := newWorktreePath(dir, sessionName)
 	if err != nil {
 		return nil, "", err
 	}
 
-	sanitizedName := sanitizeBranchName(sessionName)
-	worktreePath := filepath.Join(worktreeDir, sanitizedName)
-	worktreePath = worktreePath + "_" + fmt.Sprintf("%x", time.Now().UnixNano())
-
 	return &GitWorktree{
 		repoPath:     repoPath,
 		sessionName:  sessionName,
 		branchName:   branchName,
 		worktreePath: worktreePath,
+		history:      &commitHistory{},
 	}, branchName, nil
 }
 
+// NewGitWorktreeForImport creates a GitWorktree for an existing local branch without creating
+// the worktree on disk, for importing the branch as a paused instance. The base commit is the
+// branch's merge base with the main branch so the diff only shows the branch's own changes.
+func NewGitWorktreeForImport(repoPath string, sessionName string, branchName string) (*GitWorktree, error) {
+	tree, _, err := NewGitWorktreeForBranch(repoPath, sessionName, branchName)
+	if err != nil {
+		return nil, err
+	}
+	if err := tree.resolveBaseCommit(); err != nil {
+		return nil, err
+	}
+	return tree, nil
+}
+
+// resolveBaseCommit sets the base commit of a branch that wasn't created by claude-squad to its
+// merge base with the main branch, or its tip if they share no history.
+func (g *GitWorktree) resolveBaseCommit() error {
+	// The worktree may not exist, so resolve the main branch from the repo itself
+	probe := &GitWorktree{repoPath: g.repoPath, worktreePath: g.repoPath}
+	mainBranch := probe.getMainBranch()
+	for _, base := range []string{"origin/" + mainBranch, mainBranch} {
+		if sha, err := g.runGitCommand(g.repoPath, "merge-base", g.branchName, base); err == nil {
+			g.baseCommitSHA = strings.TrimSpace(sha)
+			return nil
+		}
+	}
+
+	// No common history with the main branch; fall back to the branch tip
+	sha, err := g.runGitCommand(g.repoPath, "rev-parse", g.branchName)
+	if err != nil {
+		return fmt.Errorf("failed to resolve branch %s: %w", g.branchName, err)
+	}
+	g.baseCommitSHA = strings.TrimSpace(sha)
+	return nil
+}
+
+// SetBaseRef sets the branch, tag or commit a new worktree is created from instead of the remote
+// default branch.
+func (g *GitWorktree) SetBaseRef(ref string) {
+	g.baseRef = ref
+}
+
 // GetWorktreePath returns the path to the worktree
 func (g *GitWorktree) GetWorktreePath() string {
 	return g.worktreePath
@@ -3,15 +3,19 @@ package git
 import (
 	"claude-squad/config"
 	"claude-squad/log"
+	"context"
 	"fmt"
 	"os"
 	"os/exec"
+	"path/filepath"
 	"sort"
 	"strings"
 	"time"
 )
 
-// RebaseConflictError is returned when rebase conflicts occur and polling is needed
+// RebaseConflictError is returned when a rebase stops on conflicts. With an empty TempDir the
+// rebase is left in progress in the worktree itself; otherwise it is in a temporary clone and
+// the remote branch is polled for the result.
 type RebaseConflictError struct {
 	TempDir    string
 	MainBranch string
@@ -25,15 +29,20 @@ func (e *RebaseConflictError) Error() string {
 
 // runGitCommand executes a git command and returns any error
 func (g *GitWorktree) runGitCommand(path string, args ...string) (string, error) {
-	// Check if the path exists before running git command
-	if _, err := os.Stat(path); os.IsNotExist(err) {
+	// Check if the path exists before running git command. Remote paths are left to git.
+	if _, err := os.Stat(path); g.runner == nil && os.IsNotExist(err) {
 		return "", fmt.Errorf("directory does not exist: %s", path)
 	}
+	if g.dryRunGit(path, args) {
+		return "", nil
+	}
 
 	baseArgs := []string{"-C", path}
-	cmd := exec.Command("git", append(baseArgs, args...)...)
+	cmd := g.command("git", append(baseArgs, args...)...)
 
+	start := time.Now()
 	output, err := cmd.CombinedOutput()
+	commandStats.recordCommand(path, args, time.Since(start), err != nil)
 	if err != nil {
 		// Include the full command in the error for debugging
 		fullCmd := fmt.Sprintf("git %s", strings.Join(append(baseArgs, args...), " "))
@@ -48,55 +57,77 @@ func (g *GitWorktree) runGitCommand(path string, args ...string) (string, error)
 	return string(output), nil
 }
 
-// PushChanges commits and pushes changes in the worktree to the remote branch
+// PushChanges commits and pushes changes in the worktree to the remote branch. It runs in
+// steps, fetching, committing and pushing, and records the step it's at in the worktree's git
+// directory. Network failures are retried; if the push still fails, pushing again resumes from
+// the failed step, so changes already committed aren't committed again. An empty commitMessage
+// uses the message the interrupted push was started with.
 func (g *GitWorktree) PushChanges(commitMessage string, open bool) error {
-	if err := checkGHCLI(); err != nil {
+	if err := g.requireRemote("push"); err != nil {
 		return err
 	}
-
-	// Check if there are any changes to commit
-	isDirty, err := g.IsDirty()
-	if err != nil {
-		return fmt.Errorf("failed to check for changes: %w", err)
+	// The forge's CLI runs on this machine, so worktrees on another one are pushed with git alone
+	push := g.pushWithGit
+	if g.runner == nil {
+		forge := g.forge()
+		if err := forge.CheckCLI(); err != nil {
+			return err
+		}
+		push = func() error { return forge.Push(g) }
 	}
 
-	if isDirty {
-		// Stage all changes
-		if _, err := g.runGitCommand(g.worktreePath, "add", "."); err != nil {
-			log.ErrorLog.Print(err)
-			return fmt.Errorf("failed to stage changes: %w", err)
+	state := g.loadPushState()
+	resumed := state != nil
+	if resumed {
+		log.InfoLog.Printf("resuming interrupted push of %s from the %s step", g.branchName, state.Step)
+		if commitMessage == "" {
+			commitMessage = state.Message
+		}
+	} else {
+		state = &pushState{Step: PushStepFetch, Branch: g.branchName}
+	}
+	state.Message = commitMessage
+	// fail records the step that failed so pushing again resumes from it
+	fail := func(err error) error {
+		if saveErr := g.savePushState(state); saveErr != nil {
+			log.ErrorLog.Print(saveErr)
+			return err
 		}
+		return fmt.Errorf("%w\nPush again to resume from the %s step", err, state.Step)
+	}
 
-		// Create commit
-		if _, err := g.runGitCommand(g.worktreePath, "commit", "-m", commitMessage, "--no-verify"); err != nil {
-			log.ErrorLog.Print(err)
-			return fmt.Errorf("failed to commit changes: %w", err)
+	if state.Step == PushStepFetch {
+		if err := retryNetwork("fetch", g.fetchPushBranch); err != nil {
+			return fail(fmt.Errorf("failed to fetch origin/%s: %w", g.PushBranch(), err))
 		}
+		state.Step = PushStepCommit
 	}
 
-	// First push the branch to remote to ensure it exists
-	pushCmd := exec.Command("gh", "repo", "sync", "--source", "-b", g.branchName)
-	pushCmd.Dir = g.worktreePath
-	if err := pushCmd.Run(); err != nil {
-		// If sync fails, try creating the branch on remote first
-		gitPushCmd := exec.Command("git", "push", "-u", "origin", g.branchName)
-		gitPushCmd.Dir = g.worktreePath
-		if pushOutput, pushErr := gitPushCmd.CombinedOutput(); pushErr != nil {
-			log.ErrorLog.Print(pushErr)
-			return fmt.Errorf("failed to push branch: %s (%w)", pushOutput, pushErr)
+	if state.Step == PushStepCommit {
+		if err := g.commitForPush(commitMessage); err != nil {
+			return fail(err)
 		}
+		head, err := g.runGitCommand(g.worktreePath, "rev-parse", "HEAD")
+		if err != nil {
+			return fail(fmt.Errorf("failed to get the commit to push: %w", err))
+		}
+		state.Commit = strings.TrimSpace(head)
+		state.Step = PushStepPush
 	}
 
-	// Now sync with remote
-	syncCmd := exec.Command("gh", "repo", "sync", "-b", g.branchName)
-	syncCmd.Dir = g.worktreePath
-	if output, err := syncCmd.CombinedOutput(); err != nil {
-		log.ErrorLog.Print(err)
-		return fmt.Errorf("failed to sync changes: %s (%w)", output, err)
+	if err := g.checkPushPolicy(g.worktreePath, false); err != nil {
+		return fail(err)
 	}
+	// A push reported as failed may have gone through before the connection dropped
+	if !resumed || !g.remoteHasCommit(state.Commit) {
+		if err := retryNetwork("push", push); err != nil {
+			return fail(err)
+		}
+	}
+	g.clearPushState()
 
 	// Open the branch in the browser
-	if open {
+	if open && g.runner == nil {
 		if err := g.OpenBranchURL(); err != nil {
 			// Just log the error but don't fail the push operation
 			log.ErrorLog.Printf("failed to open branch URL: %v", err)
@@ -106,6 +137,67 @@ func (g *GitWorktree) PushChanges(commitMessage string, open bool) error {
 	return nil
 }
 
+// checkPushPolicy enforces the policy before the instance's branch is pushed from dir: force
+// pushes are refused when the policy disables them, and its pre-push checks must pass.
+func (g *GitWorktree) checkPushPolicy(dir string, force bool) error {
+	policy, err := g.loadPolicy()
+	if err != nil {
+		return err
+	}
+	if force {
+		if err := policy.CheckForcePush(); err != nil {
+			return err
+		}
+	}
+	for _, check := range policy.PrePushChecks {
+		if g.dryRun != nil {
+			g.dryRun.record("sh", "-c", check)
+			continue
+		}
+		cmd := exec.Command("sh", "-c", check)
+		cmd.Dir = dir
+		if output, err := cmd.CombinedOutput(); err != nil {
+			outputStr := strings.TrimSpace(string(output))
+			if len(outputStr) > 500 {
+				outputStr = "... " + outputStr[len(outputStr)-500:]
+			}
+			return fmt.Errorf("pre-push check %q failed (policy %s): %w\n%s", check, policy.Source, err, outputStr)
+		}
+	}
+	return nil
+}
+
+// commitForPush commits the worktree's changes with commitMessage, only committing what's staged
+// if anything is. It does nothing if there are no changes.
+func (g *GitWorktree) commitForPush(commitMessage string) error {
+	isDirty, err := g.IsDirty()
+	if err != nil {
+		return fmt.Errorf("failed to check for changes: %w", err)
+	}
+	if !isDirty {
+		return nil
+	}
+
+	// Changes picked in the diff tab's staging view are committed on their own; otherwise
+	// everything is
+	staged, err := g.HasStagedChanges()
+	if err != nil {
+		return err
+	}
+	if !staged {
+		if _, err := g.runGitCommand(g.worktreePath, "add", "."); err != nil {
+			log.ErrorLog.Print(err)
+			return fmt.Errorf("failed to stage changes: %w", err)
+		}
+	}
+
+	if _, err := g.runGitCommand(g.worktreePath, "commit", "-m", commitMessage, "--no-verify"); err != nil {
+		log.ErrorLog.Print(err)
+		return fmt.Errorf("failed to commit changes: %w", err)
+	}
+	return nil
+}
+
 // CommitChanges commits changes locally without pushing to remote
 func (g *GitWorktree) CommitChanges(commitMessage string) error {
 	// Check if there are any changes to commit
@@ -143,7 +235,7 @@ func (g *GitWorktree) IsDirty() (bool, error) {
 // IsBranchCheckedOut checks if the instance branch is currently checked out
 func (g *GitWorktree) IsBranchCheckedOut() (bool, error) {
 	// If worktree doesn't exist, the branch can't be checked out there
-	if _, err := os.Stat(g.worktreePath); os.IsNotExist(err) {
+	if !g.WorktreeExists() {
 		// Check in the main repo instead
 		output, err := g.runGitCommand(g.repoPath, "branch", "--show-current")
 		if err != nil {
@@ -168,19 +260,38 @@ func (g *GitWorktree) IsBranchCheckedOut() (bool, error) {
 	return strings.TrimSpace(string(output)) == g.branchName, nil
 }
 
+// pushWithGit pushes the branch to origin, under the name it's pushed as.
+func (g *GitWorktree) pushWithGit() error {
+	if _, err := g.runGitCommandWithProgress(g.worktreePath, "push", "-u", "origin", g.pushRefspec()); err != nil {
+		log.ErrorLog.Print(err)
+		return fmt.Errorf("failed to push branch: %w", err)
+	}
+	return nil
+}
+
 // OpenBranchURL opens the branch URL in the default browser
 func (g *GitWorktree) OpenBranchURL() error {
-	// Check if GitHub CLI is available
-	if err := checkGHCLI(); err != nil {
+	if err := g.requireLocal("open its branch in the browser"); err != nil {
 		return err
 	}
+	forge := g.forge()
+	if err := forge.CheckCLI(); err != nil {
+		return err
+	}
+	return forge.OpenBranch(g.worktreePath, g.PushBranch())
+}
 
-	cmd := exec.Command("gh", "browse", "--branch", g.branchName)
-	cmd.Dir = g.worktreePath
-	if err := cmd.Run(); err != nil {
-		return fmt.Errorf("failed to open branch URL: %w", err)
+// CreatePullRequest opens the forge's pull request form for the branch in the browser,
+// prefilled with title and body, or from the branch's commits if title is empty
+func (g *GitWorktree) CreatePullRequest(title, body string) error {
+	if err := g.requireLocal("open a pull request for it"); err != nil {
+		return err
 	}
-	return nil
+	forge := g.forge()
+	if err := forge.CheckCLI(); err != nil {
+		return err
+	}
+	return forge.CreatePullRequest(g.worktreePath, g.PushBranch(), title, body)
 }
 
 // isCommitBackedUp checks if the given commit is already backed up on any remote branch
@@ -196,7 +307,7 @@ func (g *GitWorktree) isCommitBackedUp(commitHash string) (bool, string, error)
 
 	// Parse the output to find backup branches
 	branches := strings.Split(strings.TrimSpace(output), "\n")
-	currentRemoteBranch := fmt.Sprintf("origin/%s", g.branchName)
+	currentRemoteBranch := fmt.Sprintf("origin/%s", g.PushBranch())
 
 	for _, branch := range branches {
 		branch = strings.TrimSpace(branch)
@@ -248,7 +359,7 @@ func (g *GitWorktree) ensureBackupBranch() (backupBranchName string, isNew bool,
 	}
 
 	if isBackedUp {
-		log.InfoLog.Printf("Current commit already backed up in branch: %s", existingBackup)
+		g.step("Commit already backed up in branch %s", existingBackup)
 		return existingBackup, false, nil
 	}
 
@@ -262,8 +373,9 @@ func (g *GitWorktree) ensureBackupBranch() (backupBranchName string, isNew bool,
 	if _, err := g.runGitCommand(g.worktreePath, "push", "origin", backupBranch, "--no-verify"); err != nil {
 		// If push fails, just log it but continue as the local backup exists.
 		log.WarningLog.Printf("failed to push backup branch %s: %v", backupBranch, err)
+		g.step("Created backup branch %s (push failed, kept locally)", backupBranch)
 	} else {
-		log.InfoLog.Printf("Created and pushed new backup branch: %s", backupBranch)
+		g.step("Created and pushed backup branch %s", backupBranch)
 	}
 
 	return backupBranch, true, nil
@@ -271,54 +383,234 @@ func (g *GitWorktree) ensureBackupBranch() (backupBranchName string, isNew bool,
 
 // RebaseWithMain rebases the current branch with the main branch
 func (g *GitWorktree) RebaseWithMain() error {
+	_, err := g.rebaseWithMain()
+	return err
+}
+
+// rebaseWithMain is RebaseWithMain, also returning the backup branch of the branch's commit
+// before the rebase.
+func (g *GitWorktree) rebaseWithMain() (string, error) {
+	if err := g.requireRemote("update with main"); err != nil {
+		return "", err
+	}
+
 	// Ensure we have a backup branch
+	g.step("Backing up the branch")
 	backupBranch, _, err := g.ensureBackupBranch()
 	if err != nil {
-		return err
+		return "", err
+	}
+	if err := g.checkAborted(); err != nil {
+		return backupBranch, err
 	}
 
 	// Fetch the latest from origin
-	if _, err := g.runGitCommand(g.worktreePath, "fetch", "origin"); err != nil {
-		return fmt.Errorf("failed to fetch from origin: %w", err)
+	g.step("Fetching origin")
+	if _, err := g.runGitCommandWithProgress(g.worktreePath, "fetch", "origin"); err != nil {
+		return backupBranch, g.abortedOr(fmt.Errorf("failed to fetch from origin: %w", err))
 	}
-
-	// Determine the main branch name using git remote show origin
-	mainBranch := "main"
-	cmd := exec.Command("sh", "-c", "git remote show origin | sed -n '/HEAD branch/s/.*: //p'")
-	cmd.Dir = g.worktreePath
-	output, err := cmd.Output()
-	if err == nil && len(output) > 0 {
-		mainBranch = strings.TrimSpace(string(output))
-	} else {
-		// Fallback: Try common defaults if the command fails
-		if _, err := g.runGitCommand(g.worktreePath, "rev-parse", "origin/main"); err != nil {
-			if _, err := g.runGitCommand(g.worktreePath, "rev-parse", "origin/master"); err == nil {
-				mainBranch = "master"
-			} else if _, err := g.runGitCommand(g.worktreePath, "rev-parse", "origin/dev"); err == nil {
-				mainBranch = "dev"
-			}
-		}
+	if err := g.checkAborted(); err != nil {
+		return backupBranch, err
 	}
 
+	mainBranch := g.getMainBranch()
+
 	// Perform the rebase
+	g.step("Rebasing onto origin/%s", mainBranch)
 	if _, err := g.runGitCommand(g.worktreePath, "rebase", fmt.Sprintf("origin/%s", mainBranch)); err != nil {
+		// Leave a rebase stopped on conflicts in progress so they can be resolved in place
+		if g.IsRebaseInProgress() && g.hasMergeConflicts() {
+			g.step("Rebase stopped on conflicts, left in progress to resolve them")
+			return backupBranch, g.rebaseConflictError(mainBranch, fmt.Sprintf(
+				"rebase onto origin/%s stopped on conflicts. Backup branch created: %s", mainBranch, backupBranch))
+		}
+
 		// Abort the rebase in worktree
+		g.step("Rebase failed, aborting it")
 		g.runGitCommand(g.worktreePath, "rebase", "--abort")
+		if err := g.checkAborted(); err != nil {
+			return backupBranch, err
+		}
 
 		// Always use clone approach for any rebase failure (including conflicts)
-		log.InfoLog.Printf("Rebase failed in worktree, using clone approach")
+		g.step("Retrying the rebase in a fresh clone")
 		if cloneErr := g.rebaseWithClone(mainBranch, backupBranch); cloneErr != nil {
-			return fmt.Errorf("rebase failed with origin/%s. Backup branch created: %s. Error: %w", mainBranch, backupBranch, cloneErr)
+			return backupBranch, fmt.Errorf("rebase failed with origin/%s. Backup branch created: %s. Error: %w", mainBranch, backupBranch, cloneErr)
 		}
 
-		return nil
+		return backupBranch, nil
 	}
 
+	return backupBranch, nil
+}
+
+// MergeStrategy is how the main branch's changes are brought into an instance's branch.
+type MergeStrategy string
+
+const (
+	// MergeStrategyRebase replays the branch's commits onto main.
+	MergeStrategyRebase MergeStrategy = "rebase"
+	// MergeStrategyMerge merges main into the branch with a merge commit.
+	MergeStrategyMerge MergeStrategy = "merge"
+	// MergeStrategySquash merges main into the branch and squashes the branch's own commits into
+	// one on top of main.
+	MergeStrategySquash MergeStrategy = "squash"
+)
+
+// MergeStrategies lists the supported strategies.
+var MergeStrategies = []MergeStrategy{MergeStrategyRebase, MergeStrategyMerge, MergeStrategySquash}
+
+// ParseMergeStrategy returns the strategy with the given name. An empty name means rebase.
+func ParseMergeStrategy(name string) (MergeStrategy, error) {
+	if name == "" {
+		return MergeStrategyRebase, nil
+	}
+	for _, strategy := range MergeStrategies {
+		if string(strategy) == strings.ToLower(strings.TrimSpace(name)) {
+			return strategy, nil
+		}
+	}
+	return "", fmt.Errorf("unknown merge strategy %q (expected rebase, merge or squash)", name)
+}
+
+// MergeWithMain brings the main branch's changes into the current branch using the strategy.
+// A rebase that stops on conflicts is left in progress; merges that conflict are aborted.
+func (g *GitWorktree) MergeWithMain(strategy MergeStrategy) error {
+	var backupBranch string
+	var err error
+	switch strategy {
+	case MergeStrategyRebase:
+		backupBranch, err = g.rebaseWithMain()
+	case MergeStrategyMerge, MergeStrategySquash:
+		backupBranch, err = g.mergeWithMain(strategy == MergeStrategySquash)
+	default:
+		return fmt.Errorf("unknown merge strategy: %s", strategy)
+	}
+	if err != nil {
+		return err
+	}
+	g.pruneBackupsAfterUpdate(backupBranch)
 	return nil
 }
 
+// pruneBackupsAfterUpdate removes the backups older than the retention once the branch is updated
+// with main, keeping backupBranch, the one just used.
+func (g *GitWorktree) pruneBackupsAfterUpdate(backupBranch string) {
+	g.step("Pruning old backup branches")
+	if pruned, err := g.PruneBackups(g.settings().GetBackupBranchRetention(), backupBranch); err != nil {
+		log.WarningLog.Printf("failed to prune backup branches of %s: %v", g.branchName, err)
+	} else if len(pruned) > 0 {
+		log.InfoLog.Printf("pruned backup branches of %s: %s", g.branchName, strings.Join(pruned, ", "))
+	}
+}
+
+// mergeWithMain merges the main branch into the current branch. With squash, the merged branch's
+// changes are then committed as one commit on top of main, replacing the branch's own commits. It
+// returns the backup branch of the branch's commit before the merge.
+func (g *GitWorktree) mergeWithMain(squash bool) (string, error) {
+	if err := g.requireRemote("update with main"); err != nil {
+		return "", err
+	}
+
+	// Ensure we have a backup branch
+	g.step("Backing up the branch")
+	backupBranch, _, err := g.ensureBackupBranch()
+	if err != nil {
+		return "", err
+	}
+	if err := g.checkAborted(); err != nil {
+		return backupBranch, err
+	}
+
+	// Fetch the latest from origin
+	g.step("Fetching origin")
+	if _, err := g.runGitCommandWithProgress(g.worktreePath, "fetch", "origin"); err != nil {
+		return backupBranch, g.abortedOr(fmt.Errorf("failed to fetch from origin: %w", err))
+	}
+	if err := g.checkAborted(); err != nil {
+		return backupBranch, err
+	}
+
+	mainBranch := g.getMainBranch()
+	target := fmt.Sprintf("origin/%s", mainBranch)
+
+	// The squashed commit's message lists the branch's own commits, read before the merge adds
+	// main's
+	var before, subjects string
+	if squash {
+		if before, err = g.runGitCommand(g.worktreePath, "rev-parse", "HEAD"); err != nil {
+			return backupBranch, fmt.Errorf("failed to get current commit: %w", err)
+		}
+		subjects, err = g.runGitCommand(g.worktreePath, "log", "--reverse", "--format=- %s", target+"..HEAD")
+		if err != nil {
+			return backupBranch, fmt.Errorf("failed to list the commits of %s: %w", g.branchName, err)
+		}
+	}
+
+	g.step("Merging %s", target)
+	if _, err := g.runGitCommand(g.worktreePath, "merge", "--no-edit", target); err != nil {
+		// Leave the branch as it was so the instance stays usable
+		g.step("Merge failed, aborting")
+		g.runGitCommand(g.worktreePath, "merge", "--abort")
+		return backupBranch, fmt.Errorf("merge with %s failed. Backup branch created: %s. Error: %w", target, backupBranch, err)
+	}
+
+	if squash {
+		// Moving onto main keeps the merged files staged, so they're committed on top of it
+		g.step("Squashing %s onto %s", g.branchName, target)
+		if _, err := g.runGitCommand(g.worktreePath, "reset", "--soft", target); err != nil {
+			return backupBranch, fmt.Errorf("failed to squash onto %s. Backup branch created: %s. Error: %w", target, backupBranch, err)
+		}
+		// Nothing is staged when the branch has no changes of its own
+		if _, err := g.runGitCommand(g.worktreePath, "diff", "--cached", "--quiet"); err == nil {
+			return backupBranch, nil
+		}
+		commitMessage := fmt.Sprintf("Squash %s onto %s\n\n%s", g.branchName, target, strings.TrimSpace(subjects))
+		if _, err := g.runGitCommand(g.worktreePath, "commit", "-m", commitMessage, "--no-verify"); err != nil {
+			g.runGitCommand(g.worktreePath, "reset", "--hard", strings.TrimSpace(before))
+			return backupBranch, fmt.Errorf("failed to commit the squashed changes. Backup branch created: %s. Error: %w", backupBranch, err)
+		}
+	}
+
+	return backupBranch, nil
+}
+
+// getMainBranch determines the main branch name using git remote show origin, falling back
+// to common defaults. Without a remote the local branches are checked instead.
+func (g *GitWorktree) getMainBranch() string {
+	mainBranch := "main"
+	if !g.HasRemote() {
+		for _, candidate := range []string{"main", "master", "dev"} {
+			if _, err := g.runGitCommand(g.worktreePath, "rev-parse", "--verify", "refs/heads/"+candidate); err == nil {
+				return candidate
+			}
+		}
+		return mainBranch
+	}
+	cmd := exec.Command("sh", "-c", "git remote show origin | sed -n '/HEAD branch/s/.*: //p'")
+	cmd.Dir = g.worktreePath
+	output, err := cmd.Output()
+	if err == nil && len(output) > 0 {
+		return strings.TrimSpace(string(output))
+	}
+
+	// Fallback: Try common defaults if the command fails
+	if _, err := g.runGitCommand(g.worktreePath, "rev-parse", "origin/main"); err != nil {
+		if _, err := g.runGitCommand(g.worktreePath, "rev-parse", "origin/master"); err == nil {
+			mainBranch = "master"
+		} else if _, err := g.runGitCommand(g.worktreePath, "rev-parse", "origin/dev"); err == nil {
+			mainBranch = "dev"
+		}
+	}
+	return mainBranch
+}
+
 // ResetToOrigin performs git fetch origin and git reset --hard origin/branch
 func (g *GitWorktree) ResetToOrigin() error {
+	if err := g.requireRemote("reset to origin"); err != nil {
+		return err
+	}
+
 	// Ensure we have a backup branch
 	backupBranch, isNew, err := g.ensureBackupBranch()
 	if err != nil {
@@ -326,19 +618,20 @@ func (g *GitWorktree) ResetToOrigin() error {
 	}
 
 	// Fetch the latest from origin
-	if _, err := g.runGitCommand(g.worktreePath, "fetch", "origin"); err != nil {
+	if _, err := g.runGitCommandWithProgress(g.worktreePath, "fetch", "origin"); err != nil {
 		return fmt.Errorf("failed to fetch from origin: %w", err)
 	}
 
 	// Perform the reset to origin
-	if _, err := g.runGitCommand(g.worktreePath, "reset", "--hard", fmt.Sprintf("origin/%s", g.branchName)); err != nil {
-		return fmt.Errorf("failed to reset to origin/%s. Backup branch created: %s. Error: %w", g.branchName, backupBranch, err)
+	remoteBranch := g.PushBranch()
+	if _, err := g.runGitCommand(g.worktreePath, "reset", "--hard", fmt.Sprintf("origin/%s", remoteBranch)); err != nil {
+		return fmt.Errorf("failed to reset to origin/%s. Backup branch created: %s. Error: %w", remoteBranch, backupBranch, err)
 	}
 
 	if isNew {
-		log.InfoLog.Printf("Successfully reset branch %s to origin/%s. New backup branch: %s", g.branchName, g.branchName, backupBranch)
+		log.InfoLog.Printf("Successfully reset branch %s to origin/%s. New backup branch: %s", g.branchName, remoteBranch, backupBranch)
 	} else {
-		log.InfoLog.Printf("Successfully reset branch %s to origin/%s. Using existing backup: %s", g.branchName, g.branchName, backupBranch)
+		log.InfoLog.Printf("Successfully reset branch %s to origin/%s. Using existing backup: %s", g.branchName, remoteBranch, backupBranch)
 	}
 	return nil
 }
@@ -367,9 +660,12 @@ func (g *GitWorktree) hasMergeConflicts() bool {
 }
 
 // openIdeForConflicts opens the configured IDE at the worktree path for conflict resolution
-func (g *GitWorktree) openIdeForConflicts(globalConfig *config.Config) error {
+func (g *GitWorktree) openIdeForConflicts() error {
+	if err := g.requireLocal("open an IDE on it"); err != nil {
+		return err
+	}
 	// Get the IDE command from configuration
-	ideCommand := config.GetEffectiveIdeCommand(g.repoPath, globalConfig)
+	ideCommand := config.GetEffectiveIdeCommand(g.repoPath, g.settings())
 
 	// Open IDE at the worktree path
 	cmd := exec.Command(ideCommand, g.worktreePath)
@@ -383,6 +679,15 @@ func (g *GitWorktree) openIdeForConflicts(globalConfig *config.Config) error {
 
 // rebaseWithClone attempts to perform a rebase in a fresh clone of the repository
 func (g *GitWorktree) rebaseWithClone(mainBranch, backupBranch string) error {
+	// The rebased branch is force pushed from the clone, so don't start if that's forbidden
+	policy, err := g.loadPolicy()
+	if err != nil {
+		return err
+	}
+	if err := policy.CheckForcePush(); err != nil {
+		return fmt.Errorf("cannot rebase in a clone: %w", err)
+	}
+
 	// Sanitize branch name for use in temp directory name (replace path separators)
 	sanitizedBranch := strings.ReplaceAll(g.branchName, "/", "-")
 
@@ -403,26 +708,31 @@ func (g *GitWorktree) rebaseWithClone(mainBranch, backupBranch string) error {
 	remoteURL = strings.TrimSpace(remoteURL)
 
 	// Clone the repository
-	log.InfoLog.Printf("Cloning repository to temp directory...")
-	cloneCmd := exec.Command("git", "clone", remoteURL, tempDir)
-	if output, err := cloneCmd.CombinedOutput(); err != nil {
+	g.step("Cloning origin into %s", tempDir)
+	if _, err := g.runGitCommandWithProgress(g.worktreePath, "clone", remoteURL, tempDir); err != nil {
+		os.RemoveAll(tempDir)
+		return g.abortedOr(fmt.Errorf("failed to clone repository: %w", err))
+	}
+	if err := g.checkAborted(); err != nil {
 		os.RemoveAll(tempDir)
-		return fmt.Errorf("failed to clone repository: %s (%w)", output, err)
+		return err
 	}
 
-	// Checkout the branch in the clone
-	if _, err := g.runGitCommand(tempDir, "checkout", g.branchName); err != nil {
+	// Checkout the branch in the clone, which is named after the remote branch
+	g.step("Checking out %s in the clone", g.PushBranch())
+	if _, err := g.runGitCommand(tempDir, "checkout", g.PushBranch()); err != nil {
 		os.RemoveAll(tempDir)
-		return fmt.Errorf("failed to checkout branch %s in clone: %w", g.branchName, err)
+		return fmt.Errorf("failed to checkout branch %s in clone: %w", g.PushBranch(), err)
 	}
 
 	// Attempt rebase in the clone
+	g.step("Rebasing onto origin/%s in the clone", mainBranch)
 	if _, err := g.runGitCommand(tempDir, "rebase", fmt.Sprintf("origin/%s", mainBranch)); err != nil {
 		// Check if this is a merge conflict
 		if g.hasMergeConflictsInPath(tempDir) {
+			g.step("Rebase in the clone stopped on conflicts, opening the IDE to resolve them")
 			// Open IDE with the conflicted files in temp directory
-			globalConfig := config.LoadConfig()
-			ideCommand := config.GetEffectiveIdeCommand(g.repoPath, globalConfig)
+			ideCommand := config.GetEffectiveIdeCommand(g.repoPath, g.settings())
 
 			cmd := exec.Command(ideCommand, tempDir)
 			if ideErr := cmd.Start(); ideErr != nil {
@@ -441,13 +751,14 @@ func (g *GitWorktree) rebaseWithClone(mainBranch, backupBranch string) error {
 		}
 
 		// If it's not a merge conflict, abort and clean up
+		g.step("Rebase failed in the clone as well, removing it")
 		g.runGitCommand(tempDir, "rebase", "--abort")
 		os.RemoveAll(tempDir)
 		return fmt.Errorf("rebase failed in clone as well")
 	}
 
 	// Rebase succeeded in clone - now we need to copy the changes back
-	log.InfoLog.Printf("Rebase succeeded in clone, copying changes back to worktree...")
+	g.step("Rebase succeeded in the clone, copying it back to the worktree")
 
 	// Get the new commit SHA after rebase
 	newSHA, err := g.runGitCommand(tempDir, "rev-parse", "HEAD")
@@ -458,29 +769,44 @@ func (g *GitWorktree) rebaseWithClone(mainBranch, backupBranch string) error {
 	newSHA = strings.TrimSpace(newSHA)
 
 	// Force update the branch in the worktree to match the rebased state
-	if _, err := g.runGitCommand(g.worktreePath, "fetch", "origin"); err != nil {
+	g.step("Fetching origin")
+	if _, err := g.runGitCommandWithProgress(g.worktreePath, "fetch", "origin"); err != nil {
 		os.RemoveAll(tempDir)
-		return fmt.Errorf("failed to fetch after clone rebase: %w", err)
+		return g.abortedOr(fmt.Errorf("failed to fetch after clone rebase: %w", err))
 	}
 
 	// First push the rebased branch from the clone
-	if _, err := g.runGitCommand(tempDir, "push", "--force-with-lease", "origin", g.branchName); err != nil {
+	if err := g.checkAborted(); err != nil {
+		os.RemoveAll(tempDir)
+		return err
+	}
+	if err := g.checkPushPolicy(tempDir, true); err != nil {
+		os.RemoveAll(tempDir)
+		return err
+	}
+	g.step("Force pushing the rebased branch from the clone")
+	if _, err := g.runGitCommandWithProgress(tempDir, "push", "--force-with-lease", "origin", g.PushBranch()); err != nil {
 		os.RemoveAll(tempDir)
-		return fmt.Errorf("failed to push rebased branch from clone: %w", err)
+		return g.abortedOr(fmt.Errorf("failed to push rebased branch from clone: %w", err))
 	}
 
-	// Now reset the worktree to the rebased state
-	if _, err := g.runGitCommand(g.worktreePath, "fetch", "origin", g.branchName); err != nil {
+	// Now reset the worktree to the rebased state. The remote branch is rewritten already, so
+	// this isn't stopped by an abort.
+	sync := g.WithContext(context.Background())
+	g.step("Fetching the rebased branch")
+	if _, err := sync.runGitCommandWithProgress(g.worktreePath, "fetch", "origin", g.PushBranch()); err != nil {
 		os.RemoveAll(tempDir)
 		return fmt.Errorf("failed to fetch rebased branch: %w", err)
 	}
 
-	if _, err := g.runGitCommand(g.worktreePath, "reset", "--hard", fmt.Sprintf("origin/%s", g.branchName)); err != nil {
+	g.step("Syncing the worktree to origin/%s", g.PushBranch())
+	if _, err := g.runGitCommand(g.worktreePath, "reset", "--hard", fmt.Sprintf("origin/%s", g.PushBranch())); err != nil {
 		os.RemoveAll(tempDir)
 		return fmt.Errorf("failed to reset worktree to rebased state: %w", err)
 	}
 
 	// Clean up temp directory
+	g.step("Removing the clone")
 	os.RemoveAll(tempDir)
 	log.InfoLog.Printf("Successfully completed rebase using clone approach")
 
@@ -533,20 +859,34 @@ func (g *GitWorktree) isRebaseInProgressAtPath(path string) bool {
 
 // IsRebaseInProgress checks if a rebase is currently in progress
 func (g *GitWorktree) IsRebaseInProgress() bool {
-	// Check if .git/rebase-merge or .git/rebase-apply directories exist
-	rebaseMergePath := fmt.Sprintf("%s/.git/rebase-merge", g.worktreePath)
-	rebaseApplyPath := fmt.Sprintf("%s/.git/rebase-apply", g.worktreePath)
-
-	if _, err := os.Stat(rebaseMergePath); err == nil {
-		return true
-	}
-	if _, err := os.Stat(rebaseApplyPath); err == nil {
-		return true
+	// A worktree's .git is a file, so ask git where its rebase-merge and rebase-apply
+	// directories would be
+	for _, name := range []string{"rebase-merge", "rebase-apply"} {
+		path, err := g.runGitCommand(g.worktreePath, "rev-parse", "--git-path", name)
+		if err != nil {
+			continue
+		}
+		path = strings.TrimSpace(path)
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(g.worktreePath, path)
+		}
+		if _, err := os.Stat(path); err == nil {
+			return true
+		}
 	}
 
 	return false
 }
 
+// rebaseConflictError describes a rebase of the worktree that stopped on conflicts.
+func (g *GitWorktree) rebaseConflictError(mainBranch, message string) *RebaseConflictError {
+	return &RebaseConflictError{
+		MainBranch: mainBranch,
+		Message:    message,
+		Worktree:   g,
+	}
+}
+
 // ContinueRebase continues a rebase after conflicts have been resolved
 func (g *GitWorktree) ContinueRebase() error {
 	// Check if rebase is in progress
@@ -564,8 +904,12 @@ func (g *GitWorktree) ContinueRebase() error {
 		return fmt.Errorf("failed to stage resolved files: %w", err)
 	}
 
-	// Continue the rebase
-	if _, err := g.runGitCommand(g.worktreePath, "rebase", "--continue"); err != nil {
+	// Continue the rebase, keeping each commit's message rather than opening an editor
+	if _, err := g.runGitCommand(g.worktreePath, "-c", "core.editor=true", "rebase", "--continue"); err != nil {
+		// A later commit can conflict as well
+		if g.IsRebaseInProgress() && g.hasMergeConflicts() {
+			return g.rebaseConflictError("", "rebase stopped on conflicts in a later commit")
+		}
 		return fmt.Errorf("failed to continue rebase: %w", err)
 	}
 
@@ -599,9 +943,32 @@ func (g *GitWorktree) GetCurrentCommitSHA() (string, error) {
 	return strings.TrimSpace(sha), nil
 }
 
+// CommonGitDir returns the absolute path of the git directory the worktree shares with the
+// repository, which isn't the repository's .git when it's a separate git dir or a bare clone.
+func (g *GitWorktree) CommonGitDir() (string, error) {
+	dir, err := g.runGitCommand(g.worktreePath, "rev-parse", "--path-format=absolute", "--git-common-dir")
+	if err != nil {
+		return "", fmt.Errorf("failed to find the git directory: %w", err)
+	}
+	return strings.TrimSpace(dir), nil
+}
+
+// GitDir returns the absolute path of the worktree's own git directory, holding its index and
+// HEAD, inside the common git directory for a linked worktree.
+func (g *GitWorktree) GitDir() (string, error) {
+	dir, err := g.runGitCommand(g.worktreePath, "rev-parse", "--absolute-git-dir")
+	if err != nil {
+		return "", fmt.Errorf("failed to find git directory: %w", err)
+	}
+	return strings.TrimSpace(dir), nil
+}
+
 // FetchBranch fetches a specific branch from remote
 func (g *GitWorktree) FetchBranch(branchName string) (string, error) {
-	output, err := g.runGitCommand(g.worktreePath, "fetch", "origin", branchName)
+	if err := g.requireRemote("fetch"); err != nil {
+		return "", err
+	}
+	output, err := g.runGitCommandWithProgress(g.worktreePath, "fetch", "origin", branchName)
 	if err != nil {
 		return "", err
 	}
@@ -619,6 +986,10 @@ func (g *GitWorktree) GetRemoteBranchSHA(branchName string) (string, error) {
 
 // ResetToRemote resets the current branch to match the remote
 func (g *GitWorktree) ResetToRemote(branchName string) error {
+	if err := g.requireRemote("reset to the remote branch"); err != nil {
+		return err
+	}
+
 	// First ensure we're on the right branch
 	currentBranch, err := g.runGitCommand(g.worktreePath, "rev-parse", "--abbrev-ref", "HEAD")
 	if err != nil {
@@ -709,24 +1080,8 @@ type GitFileStatus struct {
 
 // GetChangedFilesForBranch gets all files changed in the current branch compared to main branch
 func (g *GitWorktree) GetChangedFilesForBranch() ([]GitFileStatus, error) {
-	// Determine the main branch name
-	mainBranch := "main"
-	cmd := exec.Command("sh", "-c", "git remote show origin | sed -n '/HEAD branch/s/.*: //p'")
-	cmd.Dir = g.worktreePath
-	output, err := cmd.Output()
-	if err == nil && len(output) > 0 {
-		mainBranch = strings.TrimSpace(string(output))
-	} else {
-		// Fallback: Try common defaults if the command fails
-		if _, err := g.runGitCommand(g.worktreePath, "rev-parse", "origin/main"); err != nil {
-			if _, err := g.runGitCommand(g.worktreePath, "rev-parse", "origin/master"); err == nil {
-				mainBranch = "master"
-			}
-		}
-	}
-
 	// Get the merge base between current branch and main
-	mergeBase, err := g.runGitCommand(g.worktreePath, "merge-base", fmt.Sprintf("origin/%s", mainBranch), "HEAD")
+	mergeBase, err := g.runGitCommand(g.worktreePath, "merge-base", g.mainRef(), "HEAD")
 	if err != nil {
 		return nil, fmt.Errorf("failed to find merge base: %w", err)
 	}
@@ -918,3 +1273,18 @@ func (g *GitWorktree) GetChangedFilesSinceCommit(fromCommit string) ([]GitFileSt
 
 	return files, nil
 }
+
+// ListTrackedFiles returns the paths of the files tracked in the worktree, relative to its root.
+func (g *GitWorktree) ListTrackedFiles() ([]string, error) {
+	output, err := g.runGitCommand(g.worktreePath, "ls-files")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tracked files: %w", err)
+	}
+	var files []string
+	for _, line := range strings.Split(output, "\n") {
+		if line != "" {
+			files = append(files, line)
+		}
+	}
+	return files, nil
+}
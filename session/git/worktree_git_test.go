@@ -0,0 +1,59 @@
+package git
+
+import (
+	"claude-squad/config"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCommonGitDir(t *testing.T) {
+	dir, err := filepath.EvalSymlinks(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	repo := filepath.Join(dir, "repo")
+	gitDir := filepath.Join(dir, "repo.git")
+	worktree := filepath.Join(dir, "worktree")
+	for _, args := range [][]string{
+		{"init", "-q", "-b", "main", "--separate-git-dir", gitDir, repo},
+		{"-C", repo, "-c", "user.name=test", "-c", "user.email=test@example.com", "commit", "-q", "--allow-empty", "-m", "initial"},
+		{"-C", repo, "worktree", "add", "-q", "-b", "feature", worktree},
+	} {
+		if output, err := exec.Command("git", args...).CombinedOutput(); err != nil {
+			t.Fatalf("git %s: %v\n%s", strings.Join(args, " "), err, output)
+		}
+	}
+
+	// The repository's git directory isn't at repo/.git, which is a file pointing at it
+	g := &GitWorktree{repoPath: repo, worktreePath: worktree}
+	common, err := g.CommonGitDir()
+	if err != nil {
+		t.Fatalf("CommonGitDir() failed: %v", err)
+	}
+	if common != gitDir {
+		t.Errorf("CommonGitDir() = %q, want %q", common, gitDir)
+	}
+}
+
+func TestCheckPushPolicy(t *testing.T) {
+	dir := t.TempDir()
+	g := &GitWorktree{repoPath: dir, worktreePath: dir}
+	g.SetConfig(&config.Config{}, &config.Policy{DisableForcePush: true, PrePushChecks: []string{"test -f checked"}})
+
+	// The worktree follows the policy it was given
+	if err := g.checkPushPolicy(dir, true); err == nil {
+		t.Error("checkPushPolicy() allowed a force push the policy disables")
+	}
+	if err := g.checkPushPolicy(dir, false); err == nil {
+		t.Error("checkPushPolicy() passed with a failing pre-push check")
+	}
+	if err := os.WriteFile(filepath.Join(dir, "checked"), nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := g.checkPushPolicy(dir, false); err != nil {
+		t.Errorf("checkPushPolicy() failed once the pre-push check passed: %v", err)
+	}
+}
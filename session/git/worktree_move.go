@@ -0,0 +1,61 @@
+package git
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// Move relocates the worktree to path with git worktree move, e.g. when the disk it's on runs out
+// of space. git can't rename a worktree onto another filesystem, so there it's copied and its
+// links to the repository are repaired instead.
+func (g *GitWorktree) Move(path string) error {
+	if err := g.requireLocal("move it"); err != nil {
+		return err
+	}
+	path, err := filepath.Abs(path)
+	if err != nil {
+		return fmt.Errorf("failed to resolve worktree path: %w", err)
+	}
+	if path == g.worktreePath {
+		return fmt.Errorf("the worktree is already at %s", path)
+	}
+	if _, err := os.Stat(path); err == nil {
+		return fmt.Errorf("%s already exists", path)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create worktree parent directory: %w", err)
+	}
+
+	if _, err := g.runGitCommand(g.repoPath, "worktree", "move", g.worktreePath, path); err != nil {
+		if !strings.Contains(err.Error(), "cross-device") {
+			return fmt.Errorf("failed to move worktree: %w", err)
+		}
+		if err := g.copyWorktree(path); err != nil {
+			return err
+		}
+	}
+	g.worktreePath = path
+	return nil
+}
+
+// copyWorktree moves the worktree to path on another filesystem: it copies it, repairs the links
+// between the copy and the repository, then removes the original.
+func (g *GitWorktree) copyWorktree(path string) error {
+	if output, err := exec.Command("cp", "-a", g.worktreePath, path).CombinedOutput(); err != nil {
+		_ = os.RemoveAll(path)
+		return fmt.Errorf("failed to copy worktree to %s: %s (%w)", path, strings.TrimSpace(string(output)), err)
+	}
+	if _, err := g.runGitCommand(path, "worktree", "repair"); err != nil {
+		// Point the repository back at the original, in case the copy was partly linked
+		_ = os.RemoveAll(path)
+		_, _ = g.runGitCommand(g.worktreePath, "worktree", "repair")
+		return fmt.Errorf("failed to repair moved worktree: %w", err)
+	}
+	if err := os.RemoveAll(g.worktreePath); err != nil {
+		return fmt.Errorf("failed to remove worktree after copying it to %s: %w", path, err)
+	}
+	return nil
+}
@@ -0,0 +1,88 @@
+package git
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestMoveWorktree(t *testing.T) {
+	dir := t.TempDir()
+	repo := filepath.Join(dir, "repo")
+	initRepo(t, repo)
+
+	setup := func(t *testing.T, name string) *GitWorktree {
+		t.Helper()
+		g, _, err := NewGitWorktreeForBranchInDir(repo, name, name, filepath.Join(dir, "worktrees"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := g.Setup(); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(g.GetWorktreePath(), "work.txt"), []byte("uncommitted"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		return g
+	}
+	check := func(t *testing.T, g *GitWorktree, old, target string) {
+		t.Helper()
+		if g.GetWorktreePath() != target {
+			t.Errorf("worktree path = %q, want %q", g.GetWorktreePath(), target)
+		}
+		if _, err := os.Stat(old); !os.IsNotExist(err) {
+			t.Errorf("old worktree %s still exists", old)
+		}
+		if data, err := os.ReadFile(filepath.Join(target, "work.txt")); err != nil || string(data) != "uncommitted" {
+			t.Errorf("uncommitted file wasn't moved: %q, %v", data, err)
+		}
+		list, err := exec.Command("git", "-C", repo, "worktree", "list", "--porcelain").Output()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !strings.Contains(string(list), "worktree "+target+"\n") {
+			t.Errorf("repository doesn't list the moved worktree:\n%s", list)
+		}
+		if status, err := g.runGitCommand(target, "status", "--porcelain"); err != nil || !strings.Contains(status, "work.txt") {
+			t.Errorf("git status in the moved worktree = %q, %v", status, err)
+		}
+	}
+
+	t.Run("move", func(t *testing.T) {
+		g := setup(t, "move")
+		old := g.GetWorktreePath()
+		target := filepath.Join(dir, "elsewhere", "move")
+		if err := g.Move(target); err != nil {
+			t.Fatal(err)
+		}
+		check(t, g, old, target)
+
+		if err := g.Move(target); err == nil {
+			t.Error("Move() to the current path succeeded")
+		}
+	})
+
+	// Moves across filesystems copy the worktree and repair its links
+	t.Run("copy", func(t *testing.T) {
+		g := setup(t, "copy")
+		old := g.GetWorktreePath()
+		target := filepath.Join(dir, "other-disk", "copy")
+		if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := g.copyWorktree(target); err != nil {
+			t.Fatal(err)
+		}
+		g.worktreePath = target
+		check(t, g, old, target)
+	})
+
+	t.Run("existing target", func(t *testing.T) {
+		g := setup(t, "existing")
+		if err := g.Move(repo); err == nil || !strings.Contains(err.Error(), "already exists") {
+			t.Errorf("Move() onto an existing directory = %v", err)
+		}
+	})
+}
@@ -15,6 +15,10 @@ import (
 
 // Setup creates a new worktree for the session
 func (g *GitWorktree) Setup() error {
+	if g.runner != nil {
+		return g.setupRemote()
+	}
+
 	// Check if branch exists first
 	repo, err := git.PlainOpen(g.repoPath)
 	if err != nil {
@@ -70,11 +74,27 @@ func (g *GitWorktree) Setup() error {
 	return g.SetupNewWorktree()
 }
 
+// setupRemote is Setup for a worktree on another machine, where go-git can't open the
+// repository, so branches are only looked up with git.
+func (g *GitWorktree) setupRemote() error {
+	if _, err := g.runGitCommand(g.repoPath, "rev-parse", "--verify", "refs/heads/"+g.branchName); err == nil {
+		return g.SetupFromExistingBranch()
+	}
+	if !strings.Contains(g.branchName, "/") {
+		if _, err := g.runGitCommand(g.repoPath, "rev-parse", "--verify", "refs/remotes/origin/"+g.branchName); err == nil {
+			g.branchName = "origin/" + g.branchName
+			return g.SetupFromExistingBranch()
+		}
+	}
+	return g.SetupNewWorktree()
+}
+
 // SetupFromExistingBranch creates a worktree from an existing branch
 func (g *GitWorktree) SetupFromExistingBranch() error {
-	// Ensure worktrees directory exists
-	worktreesDir := filepath.Join(g.repoPath, "worktrees")
-	if err := os.MkdirAll(worktreesDir, 0755); err != nil {
+	// Ensure the worktrees directory exists. It is outside the repository, so nothing is created in
+	// the checkout or, for bare repositories, in git's own worktrees directory.
+	worktreesDir := filepath.Dir(g.worktreePath)
+	if err := g.mkdirAll(worktreesDir); err != nil {
 		return fmt.Errorf("failed to create worktrees directory: %w", err)
 	}
 
@@ -150,7 +170,9 @@ func (g *GitWorktree) SetupFromExistingBranch() error {
 					}
 				}
 
-				// Update branch name to the new one
+				// Update branch name to the new one, remembering the one asked for so the rename
+				// can be shown and undone
+				g.requestedBranch = g.branchName
 				g.branchName = newBranchName
 				log.InfoLog.Printf("Branch was already checked out, created new branch: %s", newBranchName)
 
@@ -215,9 +237,10 @@ func (g *GitWorktree) SetupFromExistingBranch() error {
 
 // SetupNewWorktree creates a new worktree from HEAD
 func (g *GitWorktree) SetupNewWorktree() error {
-	// Ensure worktrees directory exists
-	worktreesDir := filepath.Join(g.repoPath, "worktrees")
-	if err := os.MkdirAll(worktreesDir, 0755); err != nil {
+	// Ensure the worktrees directory exists. It is outside the repository, so nothing is created in
+	// the checkout or, for bare repositories, in git's own worktrees directory.
+	worktreesDir := filepath.Dir(g.worktreePath)
+	if err := g.mkdirAll(worktreesDir); err != nil {
 		return fmt.Errorf("failed to create worktrees directory: %w", err)
 	}
 
@@ -227,30 +250,53 @@ func (g *GitWorktree) SetupNewWorktree() error {
 	// Prune any stale worktree references
 	_, _ = g.runGitCommand(g.repoPath, "worktree", "prune")
 
-	// Open the repository
-	repo, err := git.PlainOpen(g.repoPath)
-	if err != nil {
-		return fmt.Errorf("failed to open repository: %w", err)
-	}
+	if g.runner != nil {
+		// go-git can't open a repository on another machine; delete a stale branch with git
+		_, _ = g.runGitCommand(g.repoPath, "branch", "-D", g.branchName)
+	} else {
+		// Open the repository
+		repo, err := git.PlainOpen(g.repoPath)
+		if err != nil {
+			return fmt.Errorf("failed to open repository: %w", err)
+		}
 
-	// Clean up any existing branch or reference
-	if err := g.cleanupExistingBranch(repo); err != nil {
-		// If we can't clean up the branch, it might be checked out elsewhere
-		// Try to list worktrees to provide more context
-		worktreeListOutput, _ := g.runGitCommand(g.repoPath, "worktree", "list")
-		return fmt.Errorf("failed to cleanup existing branch '%s': %w\nCurrent worktrees:\n%s", g.branchName, err, worktreeListOutput)
+		// Clean up any existing branch or reference
+		if err := g.cleanupExistingBranch(repo); err != nil {
+			// If we can't clean up the branch, it might be checked out elsewhere
+			// Try to list worktrees to provide more context
+			worktreeListOutput, _ := g.runGitCommand(g.repoPath, "worktree", "list")
+			return fmt.Errorf("failed to cleanup existing branch '%s': %w\nCurrent worktrees:\n%s", g.branchName, err, worktreeListOutput)
+		}
 	}
 
-	// First, fetch the latest from origin to ensure we have the most recent remote state
-	if _, err := g.runGitCommand(g.repoPath, "fetch", "origin"); err != nil {
-		// If fetch fails, log it but continue - we might be offline
-		fmt.Printf("Warning: Could not fetch from origin: %v\n", err)
+	// Local-only repositories have nothing to fetch; new worktrees start from local HEAD
+	hasRemote := g.HasRemote()
+	if hasRemote {
+		// First, fetch the latest from origin to ensure we have the most recent remote state
+		if _, err := g.runGitCommand(g.repoPath, "fetch", "origin"); err != nil {
+			// If fetch fails, log it but continue - we might be offline
+			fmt.Printf("Warning: Could not fetch from origin: %v\n", err)
+		}
 	}
 
 	// Get the remote HEAD reference to determine the default branch
-	remoteHeadOutput, err := g.runGitCommand(g.repoPath, "symbolic-ref", "refs/remotes/origin/HEAD")
+	var remoteHeadOutput string
+	err := ErrNoRemote
+	if hasRemote {
+		remoteHeadOutput, err = g.runGitCommand(g.repoPath, "symbolic-ref", "refs/remotes/origin/HEAD")
+	}
 	var targetCommit string
-	if err != nil {
+	if g.baseRef != "" {
+		// An explicit base branch wins; prefer its freshly fetched remote state
+		targetCommit, err = g.resolveBaseRef()
+		if err != nil {
+			return err
+		}
+	} else if !hasRemote {
+		if targetCommit, err = g.localHead(); err != nil {
+			return err
+		}
+	} else if err != nil {
 		// If we can't get the remote HEAD, fall back to trying origin/main or origin/master
 		mainOutput, mainErr := g.runGitCommand(g.repoPath, "rev-parse", "origin/main")
 		masterOutput, masterErr := g.runGitCommand(g.repoPath, "rev-parse", "origin/master")
@@ -261,16 +307,9 @@ func (g *GitWorktree) SetupNewWorktree() error {
 			targetCommit = strings.TrimSpace(string(masterOutput))
 		} else {
 			// Final fallback to local HEAD if we can't find remote default branch
-			output, err := g.runGitCommand(g.repoPath, "rev-parse", "HEAD")
-			if err != nil {
-				if strings.Contains(err.Error(), "fatal: ambiguous argument 'HEAD'") ||
-					strings.Contains(err.Error(), "fatal: not a valid object name") ||
-					strings.Contains(err.Error(), "fatal: HEAD: not a valid object name") {
-					return fmt.Errorf("this appears to be a brand new repository: please create an initial commit before creating an instance")
-				}
-				return fmt.Errorf("failed to get HEAD commit hash: %w", err)
+			if targetCommit, err = g.localHead(); err != nil {
+				return err
 			}
-			targetCommit = strings.TrimSpace(string(output))
 			fmt.Println("Warning: Could not determine remote default branch, using local HEAD")
 		}
 	} else {
@@ -302,8 +341,50 @@ func (g *GitWorktree) SetupNewWorktree() error {
 	return nil
 }
 
+// localHead returns the commit of the repository's local HEAD.
+func (g *GitWorktree) localHead() (string, error) {
+	output, err := g.runGitCommand(g.repoPath, "rev-parse", "HEAD")
+	if err != nil {
+		if strings.Contains(err.Error(), "fatal: ambiguous argument 'HEAD'") ||
+			strings.Contains(err.Error(), "fatal: not a valid object name") ||
+			strings.Contains(err.Error(), "fatal: HEAD: not a valid object name") {
+			return "", fmt.Errorf("this appears to be a brand new repository: please create an initial commit before creating an instance")
+		}
+		return "", fmt.Errorf("failed to get HEAD commit hash: %w", err)
+	}
+	return strings.TrimSpace(output), nil
+}
+
+// resolveBaseRef returns the commit of the configured base branch, tag or commit, preferring
+// origin's copy of a branch.
+func (g *GitWorktree) resolveBaseRef() (string, error) {
+	sha, err := g.resolveRef(g.baseRef)
+	if err != nil {
+		return "", fmt.Errorf("base %w", err)
+	}
+	return sha, nil
+}
+
+// resolveRef returns the commit SHA of a branch, preferring origin's, a tag or a commit.
+func (g *GitWorktree) resolveRef(name string) (string, error) {
+	refs := []string{name}
+	if g.HasRemote() {
+		refs = append([]string{"origin/" + name}, refs...)
+	}
+	for _, ref := range refs {
+		if output, err := g.runGitCommand(g.repoPath, "rev-parse", "--verify", ref+"^{commit}"); err == nil {
+			return strings.TrimSpace(output), nil
+		}
+	}
+	return "", fmt.Errorf("%s is not a branch, tag or commit found locally or on origin", name)
+}
+
 // Cleanup removes the worktree and associated branch
 func (g *GitWorktree) Cleanup() error {
+	if g.runner != nil {
+		return g.cleanupRemote()
+	}
+
 	var errs []error
 
 	// Check if worktree path exists before attempting removal
@@ -339,7 +420,9 @@ func (g *GitWorktree) Cleanup() error {
 	if _, err := repo.Reference(branchRef, false); err == nil {
 		// Check if branch is checked out in main repo
 		isCheckedOut, _ := g.IsBranchCheckedOut()
-		if !isCheckedOut {
+		if !isCheckedOut && g.dryRun != nil {
+			g.dryRunGit(g.repoPath, []string{"branch", "-D", g.branchName})
+		} else if !isCheckedOut {
 			// First try normal deletion
 			if err := repo.Storer.RemoveReference(branchRef); err != nil {
 				// If that fails, try command line force delete
@@ -367,6 +450,28 @@ func (g *GitWorktree) Cleanup() error {
 	return nil
 }
 
+// cleanupRemote is Cleanup for a worktree on another machine, where go-git can't open the
+// repository.
+func (g *GitWorktree) cleanupRemote() error {
+	var errs []error
+	if g.WorktreeExists() {
+		if _, err := g.runGitCommand(g.repoPath, "worktree", "remove", "-f", g.worktreePath); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if _, err := g.runGitCommand(g.repoPath, "rev-parse", "--verify", "refs/heads/"+g.branchName); err == nil {
+		if isCheckedOut, _ := g.IsBranchCheckedOut(); isCheckedOut {
+			log.WarningLog.Printf("branch %s is checked out in main repository, skipping branch deletion", g.branchName)
+		} else if _, err := g.runGitCommand(g.repoPath, "branch", "-D", g.branchName); err != nil {
+			errs = append(errs, fmt.Errorf("failed to remove branch %s: %w", g.branchName, err))
+		}
+	}
+	if err := g.Prune(); err != nil {
+		errs = append(errs, err)
+	}
+	return g.combineErrors(errs)
+}
+
 // ForceCleanup performs aggressive cleanup of the worktree and branch
 // This method attempts multiple fallback strategies to ensure cleanup succeeds
 func (g *GitWorktree) ForceCleanup() error {
@@ -402,22 +507,25 @@ func (g *GitWorktree) ForceCleanup() error {
 		if _, err := g.runGitCommand(g.repoPath, "branch", "-D", g.branchName); err != nil {
 			errs = append(errs, fmt.Errorf("failed to force delete branch %s: %w", g.branchName, err))
 
-			// If branch delete fails, try to remove the ref directly
-			repo, openErr := git.PlainOpen(g.repoPath)
-			if openErr == nil {
-				branchRef := plumbing.NewBranchReferenceName(g.branchName)
-				if err := repo.Storer.RemoveReference(branchRef); err != nil {
-					errs = append(errs, fmt.Errorf("failed to remove branch ref directly: %w", err))
+			if g.runner == nil {
+				// If branch delete fails, try to remove the ref directly, which go-git can only do on
+				// this machine
+				repo, openErr := git.PlainOpen(g.repoPath)
+				if openErr == nil {
+					branchRef := plumbing.NewBranchReferenceName(g.branchName)
+					if err := repo.Storer.RemoveReference(branchRef); err != nil {
+						errs = append(errs, fmt.Errorf("failed to remove branch ref directly: %w", err))
+					}
+				} else if !os.IsNotExist(openErr) && !strings.Contains(openErr.Error(), "repository does not exist") {
+					// Only log error if it's not just a missing repo
+					errs = append(errs, fmt.Errorf("failed to open repo for ref cleanup: %w", openErr))
 				}
-			} else if !os.IsNotExist(openErr) && !strings.Contains(openErr.Error(), "repository does not exist") {
-				// Only log error if it's not just a missing repo
-				errs = append(errs, fmt.Errorf("failed to open repo for ref cleanup: %w", openErr))
 			}
 		}
 	}
 
-	// 3. Manual filesystem cleanup as last resort
-	if g.worktreePath != "" && g.worktreePath != "/" && strings.Contains(g.worktreePath, "worktrees") {
+	// 3. Manual filesystem cleanup as last resort, for worktrees on this machine
+	if g.runner == nil && g.worktreePath != "" && g.worktreePath != "/" && strings.Contains(g.worktreePath, "worktrees") {
 		if err := os.RemoveAll(g.worktreePath); err != nil {
 			if !os.IsNotExist(err) {
 				errs = append(errs, fmt.Errorf("failed to manually remove worktree directory: %w", err))
@@ -447,6 +555,50 @@ func (g *GitWorktree) Remove() error {
 	return nil
 }
 
+// ArchiveBranch renames the branch to archive/<branch> so it is kept out of the way but not
+// lost. The worktree must already be removed. Returns the new branch name.
+func (g *GitWorktree) ArchiveBranch() (string, error) {
+	archived := "archive/" + g.branchName
+	if _, err := g.runGitCommand(g.repoPath, "branch", "-m", g.branchName, archived); err != nil {
+		return "", fmt.Errorf("failed to archive branch %s: %w", g.branchName, err)
+	}
+	g.branchName = archived
+	return archived, nil
+}
+
+// BranchChanges returns what the branch changed since its base commit: a binary patch and its
+// commits, one "<hash> <subject>" per line. Both are empty if the base commit is unknown.
+func (g *GitWorktree) BranchChanges() (patch string, commits string, err error) {
+	if g.baseCommitSHA == "" {
+		return "", "", nil
+	}
+	patch, err = g.runGitCommand(g.repoPath, "diff", "--binary", g.baseCommitSHA, g.branchName)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to diff branch %s: %w", g.branchName, err)
+	}
+	commits, err = g.runGitCommand(g.repoPath, "log", "--oneline", g.baseCommitSHA+".."+g.branchName)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to list commits of branch %s: %w", g.branchName, err)
+	}
+	return patch, commits, nil
+}
+
+// RestoreArchivedBranch renames an archived branch back to its original name, unless a branch
+// of that name exists again. It returns the name the branch has afterwards.
+func RestoreArchivedBranch(repoPath, archived, original string) (string, error) {
+	g := &GitWorktree{repoPath: repoPath}
+	if _, err := g.runGitCommand(repoPath, "rev-parse", "--verify", "refs/heads/"+archived); err != nil {
+		return "", fmt.Errorf("archived branch %s no longer exists", archived)
+	}
+	if _, err := g.runGitCommand(repoPath, "rev-parse", "--verify", "refs/heads/"+original); err == nil {
+		return archived, nil
+	}
+	if _, err := g.runGitCommand(repoPath, "branch", "-m", archived, original); err != nil {
+		return "", fmt.Errorf("failed to restore branch %s: %w", original, err)
+	}
+	return original, nil
+}
+
 // Prune removes all working tree administrative files and directories
 func (g *GitWorktree) Prune() error {
 	if _, err := g.runGitCommand(g.repoPath, "worktree", "prune"); err != nil {
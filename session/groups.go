@@ -0,0 +1,58 @@
+package session
+
+import (
+	"claude-squad/config"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// Group is a named group instances are organized in, such as a project or an epic.
+type Group struct {
+	Name string `json:"name"`
+	// Collapsed hides the group's instances in the list
+	Collapsed bool `json:"collapsed,omitempty"`
+}
+
+// ValidateGroupName checks that name can name a group and returns it trimmed.
+func ValidateGroupName(name string) (string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return "", fmt.Errorf("group name cannot be empty")
+	}
+	if len(name) > 32 {
+		return "", fmt.Errorf("group name cannot be longer than 32 characters")
+	}
+	return name, nil
+}
+
+// LoadGroups loads the instance groups, in the order they are listed. Storage without groups
+// has none.
+func (s *Storage) LoadGroups() ([]Group, error) {
+	groupState, ok := s.state.(config.GroupStorage)
+	if !ok {
+		return nil, nil
+	}
+	data := groupState.GetGroups()
+	if len(data) == 0 {
+		return nil, nil
+	}
+	var groups []Group
+	if err := json.Unmarshal(data, &groups); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal groups: %w", err)
+	}
+	return groups, nil
+}
+
+// SaveGroups saves the instance groups. It does nothing if the storage has no groups.
+func (s *Storage) SaveGroups(groups []Group) error {
+	groupState, ok := s.state.(config.GroupStorage)
+	if !ok {
+		return nil
+	}
+	data, err := json.Marshal(groups)
+	if err != nil {
+		return fmt.Errorf("failed to marshal groups: %w", err)
+	}
+	return groupState.SaveGroups(data)
+}
@@ -0,0 +1,87 @@
+package session
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+// ExportAIHistory writes the AI pane's scrollback, stripped of ANSI codes, to a markdown file in
+// dir named after the instance and now, with the instance's metadata as a header. It returns the
+// path of the file.
+func (i *Instance) ExportAIHistory(dir string, now time.Time) (string, error) {
+	history, err := i.GetAIFullHistory()
+	if err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", fmt.Errorf("failed to create history directory: %w", err)
+	}
+
+	path := filepath.Join(dir, fmt.Sprintf("%s-%s.md", exportFileName(i.Title), now.Format("20060102-150405")))
+	if err := os.WriteFile(path, []byte(i.historyMarkdown(history, now)), 0644); err != nil {
+		return "", fmt.Errorf("failed to write history file: %w", err)
+	}
+	return path, nil
+}
+
+// historyMarkdown formats the AI pane's history as a markdown document headed by the instance's
+// metadata.
+func (i *Instance) historyMarkdown(history string, now time.Time) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# %s\n\n", i.Title)
+	field := func(name, value string) {
+		if value != "" {
+			fmt.Fprintf(&b, "- **%s:** %s\n", name, value)
+		}
+	}
+	field("Branch", "`"+i.Branch+"`")
+	if i.gitWorktree != nil {
+		field("Repository", i.gitWorktree.GetRepoPath())
+	}
+	field("Program", "`"+i.Program+"`")
+	field("Tags", strings.Join(i.Tags, ", "))
+	field("Created", i.CreatedAt.Format(time.RFC3339))
+	field("Exported", now.Format(time.RFC3339))
+
+	lines := strings.Split(ansi.Strip(history), "\n")
+	for idx, line := range lines {
+		lines[idx] = strings.TrimRight(line, " \t\r")
+	}
+	text := strings.Trim(strings.Join(lines, "\n"), "\n")
+
+	fence := codeFence(text)
+	fmt.Fprintf(&b, "\n## AI history\n\n%stext\n%s\n%s\n", fence, text, fence)
+	return b.String()
+}
+
+// codeFence returns a backtick fence longer than any backtick run in text, so the text can't
+// close it.
+func codeFence(text string) string {
+	longest, run := 0, 0
+	for _, r := range text {
+		if r == '`' {
+			run++
+			longest = max(longest, run)
+		} else {
+			run = 0
+		}
+	}
+	return strings.Repeat("`", max(3, longest+1))
+}
+
+// exportFileName makes a title safe to use in a file name.
+func exportFileName(title string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '-', r == '_', r == '.':
+			return r
+		default:
+			return '-'
+		}
+	}, title)
+}
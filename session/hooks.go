@@ -0,0 +1,74 @@
+package session
+
+import (
+	"claude-squad/log"
+	"claude-squad/session/hooks"
+)
+
+// hooksDir returns where the status hook files of the instance's agent are kept, or "" for an
+// instance on a remote host, whose hooks would write on that host.
+func (i *Instance) hooksDir() string {
+	if i.Host != "" || i.gitWorktree == nil {
+		return ""
+	}
+	return hooks.Dir(i.gitWorktree.GetRepoPath(), i.gitWorktree.GetWorktreePath())
+}
+
+// setProgramCommand sets how the tmux session runs the program: in the instance's container if it
+// has one, and for Claude Code with status hooks when they are enabled. Hooks left from a run
+// with them enabled are removed when they no longer are.
+func (i *Instance) setProgramCommand() {
+	program := i.Program
+	i.hooked = false
+	if dir := i.hooksDir(); dir != "" {
+		if i.settings().StatusHooks && hooks.IsClaude(i.Program) {
+			if settings, err := hooks.Install(dir); err != nil {
+				log.WarningLog.Printf("status hooks of %s not installed, detecting its status from the pane: %v", i.Title, err)
+			} else {
+				program += " " + hooks.SettingsFlag(settings)
+				i.hooked = true
+			}
+		} else if hooks.Installed(dir) {
+			i.removeHooks()
+		}
+	}
+
+	command := ""
+	if c := i.dockerContainer(); c != nil {
+		command = c.ExecCommand(program)
+	} else if program != i.Program {
+		command = program
+	}
+	i.tmuxSession.SetCommand(command)
+}
+
+// removeHooks deletes the status hook files of the instance's agent.
+func (i *Instance) removeHooks() {
+	i.hooked = false
+	if dir := i.hooksDir(); dir != "" {
+		if err := hooks.Remove(dir); err != nil {
+			log.WarningLog.Printf("failed to remove status hooks of %s: %v", i.Title, err)
+		}
+	}
+}
+
+// hookState returns the agent's state from its last hook event. ok is false when the instance
+// has no status hooks or no event arrived yet, and the state must be detected from the pane.
+func (i *Instance) hookState() (state hooks.State, ok bool) {
+	if !i.hooked {
+		return hooks.Unknown, false
+	}
+	state, err := hooks.Read(i.hooksDir())
+	if err != nil {
+		log.WarningLog.Printf("failed to read status hooks of %s: %v", i.Title, err)
+		return hooks.Unknown, false
+	}
+	i.permissionWait = state == hooks.WaitingForPermission
+	return state, state != hooks.Unknown
+}
+
+// WaitingForPermission returns true if the agent's status hooks report that it asked for
+// permission to use a tool and is waiting for an answer.
+func (i *Instance) WaitingForPermission() bool {
+	return i.hooked && i.permissionWait
+}
@@ -0,0 +1,162 @@
+// Package hooks reports the exact state of a Claude Code agent from its hook events, instead of
+// guessing it from the pane's content. Claude Code runs the hooks in a settings file given with
+// --settings, and each writes the event it receives to a status file this package reads.
+package hooks
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+const (
+	// SettingsFile is the Claude Code settings file declaring the hooks.
+	SettingsFile = "settings.json"
+	// StatusFile holds the last hook event the agent sent.
+	StatusFile = "status.json"
+)
+
+// State is what the agent is doing according to its last hook event.
+type State int
+
+const (
+	// Unknown is the state before the first event, or when no hooks are installed.
+	Unknown State = iota
+	// Running is when the agent works on a prompt.
+	Running
+	// Ready is when the agent finished and waits for the next prompt.
+	Ready
+	// WaitingForPermission is when the agent asks to be allowed to use a tool.
+	WaitingForPermission
+)
+
+// Event is the part of a hook's JSON input that decides the state.
+type Event struct {
+	Name string `json:"hook_event_name"`
+	// Message is the text of a Notification event
+	Message string `json:"message,omitempty"`
+}
+
+// events are the hook events that are written to the status file.
+var events = []string{"SessionStart", "UserPromptSubmit", "PreToolUse", "PostToolUse", "Notification", "PermissionRequest", "Stop"}
+
+// toolEvents take a matcher selecting the tools they fire for.
+var toolEvents = map[string]bool{"PreToolUse": true, "PostToolUse": true, "PermissionRequest": true}
+
+var invalidDirChars = regexp.MustCompile(`[^a-zA-Z0-9_.-]`)
+
+// Dir returns the directory holding the hook files of the instance whose worktree is at
+// worktreePath. It is inside the repository's git directory, which containers mount too.
+func Dir(repoPath, worktreePath string) string {
+	return filepath.Join(repoPath, ".git", "claude-squad", "hooks", invalidDirChars.ReplaceAllString(filepath.Base(worktreePath), "_"))
+}
+
+// IsClaude returns true if program runs Claude Code, the agent hooks are supported for.
+func IsClaude(program string) bool {
+	fields := strings.Fields(program)
+	return len(fields) > 0 && filepath.Base(fields[0]) == "claude"
+}
+
+// quote quotes s for a POSIX shell.
+func quote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// Settings returns the Claude Code settings that write every event to statusPath. Each write
+// goes to a temporary file that is renamed, so a read never sees half an event.
+func Settings(statusPath string) ([]byte, error) {
+	command := fmt.Sprintf(`cat > %s.$$ && mv %s.$$ %s`, quote(statusPath), quote(statusPath), quote(statusPath))
+	type hook struct {
+		Type    string `json:"type"`
+		Command string `json:"command"`
+	}
+	type matcher struct {
+		Matcher string `json:"matcher,omitempty"`
+		Hooks   []hook `json:"hooks"`
+	}
+	all := make(map[string][]matcher)
+	for _, event := range events {
+		entry := matcher{Hooks: []hook{{Type: "command", Command: command}}}
+		if toolEvents[event] {
+			entry.Matcher = "*"
+		}
+		all[event] = []matcher{entry}
+	}
+	return json.MarshalIndent(map[string]any{"hooks": all}, "", "  ")
+}
+
+// Install writes the settings declaring the hooks to dir and returns the path of the settings
+// file to pass to claude --settings. The last status is kept, since the agent may still be
+// running; a newly started one replaces it with its SessionStart event.
+func Install(dir string) (string, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", fmt.Errorf("failed to create hooks directory: %w", err)
+	}
+	settings, err := Settings(filepath.Join(dir, StatusFile))
+	if err != nil {
+		return "", fmt.Errorf("failed to encode hook settings: %w", err)
+	}
+	path := filepath.Join(dir, SettingsFile)
+	if err := os.WriteFile(path, settings, 0644); err != nil {
+		return "", fmt.Errorf("failed to write hook settings: %w", err)
+	}
+	return path, nil
+}
+
+// SettingsFlag returns the claude flag loading the settings file at path.
+func SettingsFlag(path string) string {
+	return "--settings " + quote(path)
+}
+
+// Installed returns true if dir holds hook settings.
+func Installed(dir string) bool {
+	_, err := os.Stat(filepath.Join(dir, SettingsFile))
+	return err == nil
+}
+
+// Remove deletes the hook files in dir.
+func Remove(dir string) error {
+	if err := os.RemoveAll(dir); err != nil {
+		return fmt.Errorf("failed to remove hooks directory: %w", err)
+	}
+	return nil
+}
+
+// StateOf returns the agent's state after event.
+func StateOf(event Event) State {
+	switch event.Name {
+	case "SessionStart", "Stop":
+		return Ready
+	case "UserPromptSubmit", "PreToolUse", "PostToolUse":
+		return Running
+	case "PermissionRequest":
+		return WaitingForPermission
+	case "Notification":
+		// Claude Code notifies both when it needs permission and when it has been idle
+		if strings.Contains(strings.ToLower(event.Message), "permission") {
+			return WaitingForPermission
+		}
+		return Ready
+	}
+	return Unknown
+}
+
+// Read returns the agent's state from the last event written to dir. It is Unknown until the
+// first event arrives.
+func Read(dir string) (State, error) {
+	data, err := os.ReadFile(filepath.Join(dir, StatusFile))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return Unknown, nil
+		}
+		return Unknown, fmt.Errorf("failed to read hook status: %w", err)
+	}
+	var event Event
+	if err := json.Unmarshal(data, &event); err != nil {
+		return Unknown, fmt.Errorf("failed to parse hook status: %w", err)
+	}
+	return StateOf(event), nil
+}
@@ -0,0 +1,105 @@
+package hooks
+
+import (
+	"encoding/json"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestStateOf(t *testing.T) {
+	for _, tc := range []struct {
+		event Event
+		want  State
+	}{
+		{Event{Name: "SessionStart"}, Ready},
+		{Event{Name: "UserPromptSubmit"}, Running},
+		{Event{Name: "PreToolUse"}, Running},
+		{Event{Name: "PostToolUse"}, Running},
+		{Event{Name: "Notification", Message: "Claude needs your permission to use Bash"}, WaitingForPermission},
+		{Event{Name: "Notification", Message: "Claude is waiting for your input"}, Ready},
+		{Event{Name: "PermissionRequest"}, WaitingForPermission},
+		{Event{Name: "Stop"}, Ready},
+		{Event{Name: "PreCompact"}, Unknown},
+	} {
+		if got := StateOf(tc.event); got != tc.want {
+			t.Errorf("StateOf(%+v) = %v, want %v", tc.event, got, tc.want)
+		}
+	}
+}
+
+func TestIsClaude(t *testing.T) {
+	for program, want := range map[string]bool{
+		"claude":                         true,
+		"/usr/local/bin/claude --resume": true,
+		"aider --model sonnet":           false,
+		"":                               false,
+	} {
+		if got := IsClaude(program); got != want {
+			t.Errorf("IsClaude(%q) = %v, want %v", program, got, want)
+		}
+	}
+}
+
+func TestInstallAndRead(t *testing.T) {
+	dir := Dir(t.TempDir(), "/worktrees/it's a test_18a2f")
+	if filepath.Base(dir) != "it_s_a_test_18a2f" {
+		t.Errorf("Dir() = %q", dir)
+	}
+	if Installed(dir) {
+		t.Fatal("Installed() before Install")
+	}
+	path, err := Install(dir)
+	if err != nil {
+		t.Fatalf("Install() error = %v", err)
+	}
+	if !Installed(dir) {
+		t.Fatal("Installed() after Install = false")
+	}
+	if state, err := Read(dir); err != nil || state != Unknown {
+		t.Fatalf("Read() before any event = %v, %v", state, err)
+	}
+
+	var settings struct {
+		Hooks map[string][]struct {
+			Matcher string `json:"matcher"`
+			Hooks   []struct {
+				Command string `json:"command"`
+			} `json:"hooks"`
+		} `json:"hooks"`
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal(data, &settings); err != nil {
+		t.Fatalf("settings aren't JSON: %v", err)
+	}
+	if settings.Hooks["PreToolUse"][0].Matcher != "*" || settings.Hooks["Stop"][0].Matcher != "" {
+		t.Errorf("unexpected matchers: %+v", settings.Hooks)
+	}
+
+	// Run the hook command as Claude Code would, with the event on stdin
+	command := settings.Hooks["Notification"][0].Hooks[0].Command
+	cmd := exec.Command("sh", "-c", command)
+	cmd.Stdin = strings.NewReader(`{"session_id":"abc","hook_event_name":"Notification","message":"Claude needs your permission to use Bash"}`)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("hook command failed: %v: %s", err, out)
+	}
+	if state, err := Read(dir); err != nil || state != WaitingForPermission {
+		t.Errorf("Read() = %v, %v, want WaitingForPermission", state, err)
+	}
+
+	// Installing again when the app restarts keeps the state of the agent that is still running
+	if _, err := Install(dir); err != nil {
+		t.Fatal(err)
+	}
+	if state, _ := Read(dir); state != WaitingForPermission {
+		t.Errorf("Read() after reinstall = %v", state)
+	}
+	if err := Remove(dir); err != nil || Installed(dir) {
+		t.Errorf("Remove() = %v, installed %v", err, Installed(dir))
+	}
+}
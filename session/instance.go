@@ -1,18 +1,24 @@
 package session
 
 import (
+	"claude-squad/config"
 	"claude-squad/log"
+	"claude-squad/session/container"
 	"claude-squad/session/git"
+	"claude-squad/session/hooks"
 	"claude-squad/session/tmux"
+	"crypto/rand"
+	"encoding/hex"
 	"path/filepath"
 
 	"fmt"
 	"os"
-	"os/exec"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
-	"github.com/atotto/clipboard"
+	"github.com/charmbracelet/x/ansi"
 )
 
 type Status int
@@ -34,10 +40,18 @@ const (
 
 // Instance is a running instance of claude code.
 type Instance struct {
+	// ID identifies the instance in storage. Unlike the title, it never changes.
+	ID string
 	// Title is the title of the instance.
 	Title string
 	// Path is the path to the workspace.
 	Path string
+	// Host is the name of the remote host, from the config's remote hosts, the instance runs on.
+	// Empty means this machine.
+	Host string
+	// Container is the name of the Docker container the program runs in. Empty means it runs
+	// directly on the instance's machine.
+	Container string
 	// Branch is the branch of the instance.
 	Branch string
 	// Status is the status of the instance.
@@ -56,11 +70,87 @@ type Instance struct {
 	AutoYes bool
 	// Prompt is the initial prompt to pass to the instance on startup
 	Prompt string
+	// Checkpoints are agent-written context summaries saved as notes, oldest first.
+	Checkpoints []Checkpoint
+	// PromptQueue holds prompts that are sent one at a time, oldest first, whenever the agent is ready.
+	PromptQueue []string
+	// Tags are free-form labels for the instance.
+	Tags []string
+	// Group is the name of the group the instance is organized in. Empty means ungrouped.
+	Group string
+	// Clipboard holds the snippets copied for the instance, most recent first.
+	Clipboard []ClipboardEntry
+	// MuteNotifications stops webhook events about the instance.
+	MuteNotifications bool
+	// Coordinator marks the instance whose agent is sent digests of the other instances, to help
+	// orchestrate them.
+	Coordinator bool
+	// Tab names the tab shown when the instance is selected: "ai", "diff", "terminal" or "tests".
+	// Empty shows the configured default tab.
+	Tab string
+	// TimeBudget is how long the agent may work before it is reported as over budget. Zero means no budget.
+	TimeBudget time.Duration
+	// WorkTime is how long the agent has spent working, measured while the instance is Running.
+	WorkTime time.Duration
+	// Services are the dev servers the instance has run, with their ports.
+	Services []Service
 
 	// In-memory cache for diff stats to avoid expensive git operations on every UI update
 	diffStatsCache     *git.DiffStats
 	diffStatsCacheTime time.Time
 
+	// Cached status of the branch's pull request. Refreshed in the background, so guarded by prStatusMu.
+	prStatusMu   sync.RWMutex
+	prStatus     *git.PRStatus
+	prStatusTime time.Time
+	// newComments counts the PR's unresolved comments posted after commentsReviewedAt, when
+	// they were last reviewed. Also guarded by prStatusMu.
+	newComments        int
+	commentsReviewedAt time.Time
+	// ciChecks are the CI checks of the branch's commit on origin, also guarded by prStatusMu
+	ciChecks     []git.CICheck
+	ciChecksTime time.Time
+	// behindMain counts the commits of origin's main branch the branch doesn't have, as of the
+	// last fetch of main. Also guarded by prStatusMu.
+	behindMain int
+
+	// promptRuns are the prompts sent to the agent, oldest first. While runPending, the last one's
+	// run isn't summarized yet and runStart is the worktree's state when it was sent. Prompts are
+	// sent from background commands, so guarded by runsMu.
+	runsMu     sync.Mutex
+	promptRuns []PromptRun
+	runStart   git.FileStats
+	runPending bool
+
+	// aiTimeline and terminalTimeline estimate when each pane line appeared, for the combined
+	// history view
+	aiTimeline       tmux.PaneTimeline
+	terminalTimeline tmux.PaneTimeline
+
+	// readySince is when the instance last became Ready, used to pace the prompt queue
+	readySince time.Time
+	// finished describes what finished in the instance since it was last looked at, e.g. "agent
+	// is ready"; terminalCommand is the terminal pane's foreground command at the last check
+	finished        string
+	terminalCommand string
+	// sessionLost is set when the tmux session was gone at startup and had to be recreated;
+	// recoveredAI and recoveredTerminal hold the panes' scrollback saved before it was lost
+	sessionLost       bool
+	recoveredAI       string
+	recoveredTerminal string
+	// lastWorkSample is when WorkTime was last updated
+	lastWorkSample time.Time
+
+	// broken is set when the worktree directory is found deleted, from the UI and background
+	// goroutines
+	broken atomic.Bool
+
+	// metrics are counted from the UI and test goroutines, so guarded by metricsMu
+	metricsMu sync.Mutex
+	metrics   Metrics
+	// usageReadAt is when the agent's usage was last read from its pane history
+	usageReadAt time.Time
+
 	// The below fields are initialized upon calling Start().
 
 	started bool
@@ -70,13 +160,44 @@ type Instance struct {
 	gitWorktree *git.GitWorktree
 	// existingBranch indicates if this instance is using an existing branch
 	existingBranch bool
+	// adopted is set for an instance taking over a worktree created outside claude-squad, which
+	// Start runs in as it is instead of creating one
+	adopted bool
+	// baseBranch and branchPrefix override where a new branch starts and how it is named
+	baseBranch   string
+	branchPrefix string
+	// worktreeDir overrides the directory the worktree is created in
+	worktreeDir string
+	// seed is how the new worktree takes the uncommitted changes of the checkout at Path
+	seed SeedMode
+	// cfg configures the instance's container, status hooks, remote host, auto-yes policy and
+	// branch prefix. Nil uses the user's config.
+	cfg *config.Config
+	// policy is enforced on the worktree's rebases and pushes. Nil uses the user's policy.
+	policy *config.Policy
+
+	// Cached status of the container, refreshed by UpdateContainerStatus
+	containerStatus     string
+	containerStatusTime time.Time
+	// Cached states of the services by name, refreshed by UpdateServiceStates
+	serviceStates map[string]string
+	// hooked is set when the agent runs with status hooks reporting its state, and
+	// permissionWait when the last one said it waits for permission
+	hooked         bool
+	permissionWait bool
+	// heldPrompt is the last prompt the auto-yes policy kept from being confirmed, so it is
+	// logged once rather than on every tick
+	heldPrompt string
 }
 
 // ToInstanceData converts an Instance to its serializable form
 func (i *Instance) ToInstanceData() InstanceData {
 	data := InstanceData{
+		ID:        i.ID,
 		Title:     i.Title,
 		Path:      i.Path,
+		Host:      i.Host,
+		Container: i.Container,
 		Branch:    i.Branch,
 		Status:    i.Status,
 		Height:    i.Height,
@@ -86,6 +207,20 @@ func (i *Instance) ToInstanceData() InstanceData {
 		Program:   i.Program,
 		AutoYes:   i.AutoYes,
 	}
+	data.Checkpoints = i.Checkpoints
+	data.PromptQueue = i.PromptQueue
+	data.Tags = i.Tags
+	data.Group = i.Group
+	data.Clipboard = i.Clipboard
+	data.MuteNotifications = i.MuteNotifications
+	data.Coordinator = i.Coordinator
+	data.Tab = i.Tab
+	data.TimeBudget = i.TimeBudget
+	data.WorkTime = i.WorkTime
+	data.Services = i.Services
+	data.Metrics = i.GetMetrics()
+	data.CommentsReviewedAt = i.CommentsReviewedAt()
+	data.PromptRuns = i.PromptRuns()
 
 	// Only include worktree data if gitWorktree is initialized
 	if i.gitWorktree != nil {
@@ -96,6 +231,12 @@ func (i *Instance) ToInstanceData() InstanceData {
 			BranchName:    i.gitWorktree.GetBranchName(),
 			BaseCommitSHA: i.gitWorktree.GetBaseCommitSHA(),
 		}
+		if requested := i.gitWorktree.RequestedBranch(); requested != "" {
+			data.Worktree.RequestedBranch = requested
+		}
+		if push := i.gitWorktree.PushBranch(); push != i.gitWorktree.GetBranchName() {
+			data.Worktree.PushBranch = push
+		}
 	}
 
 	return data
@@ -103,9 +244,18 @@ func (i *Instance) ToInstanceData() InstanceData {
 
 // FromInstanceData creates a new Instance from serialized data
 func FromInstanceData(data InstanceData) (*Instance, error) {
+	return fromInstanceData(data, nil, nil)
+}
+
+// fromInstanceData creates a new Instance from serialized data, configured by cfg and policy, or
+// the user's config and policy if nil.
+func fromInstanceData(data InstanceData, cfg *config.Config, policy *config.Policy) (*Instance, error) {
 	instance := &Instance{
+		ID:        data.ID,
 		Title:     data.Title,
 		Path:      data.Path,
+		Host:      data.Host,
+		Container: data.Container,
 		Branch:    data.Branch,
 		Status:    data.Status,
 		Height:    data.Height,
@@ -113,6 +263,7 @@ func FromInstanceData(data InstanceData) (*Instance, error) {
 		CreatedAt: data.CreatedAt,
 		UpdatedAt: data.UpdatedAt,
 		Program:   data.Program,
+		AutoYes:   data.AutoYes,
 		gitWorktree: git.NewGitWorktreeFromStorage(
 			data.Worktree.RepoPath,
 			data.Worktree.WorktreePath,
@@ -120,11 +271,45 @@ func FromInstanceData(data InstanceData) (*Instance, error) {
 			data.Worktree.BranchName,
 			data.Worktree.BaseCommitSHA,
 		),
+		cfg:    cfg,
+		policy: policy,
 	}
+	// Instances saved before they had IDs get one now
+	if instance.ID == "" {
+		instance.ID = newInstanceID()
+	}
+	instance.gitWorktree.SetRenamedBranch(data.Worktree.RequestedBranch, data.Worktree.PushBranch)
+	instance.gitWorktree.SetConfig(cfg, policy)
+	if instance.Host != "" {
+		backend, _, err := instance.remoteBackend()
+		if err != nil {
+			return nil, err
+		}
+		instance.gitWorktree.SetRunner(backend)
+	}
+
+	instance.Checkpoints = data.Checkpoints
+	instance.PromptQueue = data.PromptQueue
+	instance.Tags = data.Tags
+	instance.Group = data.Group
+	instance.Clipboard = data.Clipboard
+	instance.MuteNotifications = data.MuteNotifications
+	instance.Coordinator = data.Coordinator
+	instance.Tab = data.Tab
+	instance.TimeBudget = data.TimeBudget
+	instance.WorkTime = data.WorkTime
+	instance.Services = data.Services
+	instance.metrics = data.Metrics
+	instance.commentsReviewedAt = data.CommentsReviewedAt
+	instance.promptRuns = data.PromptRuns
 
 	if instance.Paused() {
 		instance.started = true
-		instance.tmuxSession = tmux.NewTmuxSession(instance.Title, instance.Program)
+		tmuxSession, err := instance.newTmuxSession()
+		if err != nil {
+			return nil, err
+		}
+		instance.tmuxSession = tmuxSession
 	} else {
 		if err := instance.Start(false); err != nil {
 			return nil, err
@@ -134,6 +319,23 @@ func FromInstanceData(data InstanceData) (*Instance, error) {
 	return instance, nil
 }
 
+// settings returns the config the instance runs with.
+func (i *Instance) settings() *config.Config {
+	if i.cfg != nil {
+		return i.cfg
+	}
+	return config.LoadConfig()
+}
+
+// newBranchPrefix returns the prefix of a new branch's name. With a worktree directory of its
+// own, an instance without a prefix gets none rather than the configured one.
+func (i *Instance) newBranchPrefix() string {
+	if i.branchPrefix != "" || i.worktreeDir != "" {
+		return i.branchPrefix
+	}
+	return i.settings().BranchPrefix
+}
+
 // Options for creating a new instance
 type InstanceOptions struct {
 	// Title is the title of the instance.
@@ -146,30 +348,70 @@ type InstanceOptions struct {
 	AutoYes bool
 	// BranchName is the name of an existing branch to checkout (optional)
 	BranchName string
+	// BaseBranch is the branch, tag or commit a new branch is created from (optional, defaults to
+	// the remote default branch)
+	BaseBranch string
+	// BranchPrefix overrides the configured prefix of the new branch's name (optional)
+	BranchPrefix string
+	// WorktreeDir overrides the directory the worktree is created in (optional). When set, an
+	// empty BranchPrefix means no prefix rather than the configured one.
+	WorktreeDir string
+	// Host is the name of the remote host to run the instance on (optional). Path is then the
+	// path of the repository on that host.
+	Host string
+	// SeedChanges is how the new worktree takes the uncommitted changes of the checkout at Path
+	// (optional). Give the checkout's HEAD as BaseBranch so they apply cleanly.
+	SeedChanges SeedMode
+	// Config configures the instance's container, status hooks, remote host, auto-yes policy and
+	// branch prefix (optional, defaults to the user's config)
+	Config *config.Config
+	// Policy is enforced on the instance's rebases and pushes (optional, defaults to the user's
+	// policy)
+	Policy *config.Policy
 }
 
 func NewInstance(opts InstanceOptions) (*Instance, error) {
 	t := time.Now()
 
-	// Convert path to absolute
-	absPath, err := filepath.Abs(opts.Path)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get absolute path: %w", err)
+	// Convert path to absolute. Paths on a remote host are absolute already.
+	absPath := opts.Path
+	if opts.Host == "" {
+		var err error
+		if absPath, err = filepath.Abs(opts.Path); err != nil {
+			return nil, fmt.Errorf("failed to get absolute path: %w", err)
+		}
 	}
 
 	return &Instance{
-		Title:     opts.Title,
-		Status:    Ready,
-		Path:      absPath,
-		Program:   opts.Program,
-		Height:    0,
-		Width:     0,
-		CreatedAt: t,
-		UpdatedAt: t,
-		AutoYes:   false,
+		ID:           newInstanceID(),
+		Title:        opts.Title,
+		Status:       Ready,
+		Path:         absPath,
+		Host:         opts.Host,
+		Program:      opts.Program,
+		Height:       0,
+		Width:        0,
+		CreatedAt:    t,
+		UpdatedAt:    t,
+		AutoYes:      opts.AutoYes,
+		baseBranch:   opts.BaseBranch,
+		branchPrefix: opts.BranchPrefix,
+		worktreeDir:  opts.WorktreeDir,
+		seed:         opts.SeedChanges,
+		cfg:          opts.Config,
+		policy:       opts.Policy,
 	}, nil
 }
 
+// newInstanceID returns a random ID for a new instance.
+func newInstanceID() string {
+	raw := make([]byte, 8)
+	if _, err := rand.Read(raw); err != nil {
+		return fmt.Sprintf("%x", time.Now().UnixNano())
+	}
+	return hex.EncodeToString(raw)
+}
+
 // NewInstanceWithBranch creates a new instance that will use an existing branch
 func NewInstanceWithBranch(opts InstanceOptions) (*Instance, error) {
 	t := time.Now()
@@ -187,6 +429,7 @@ func NewInstanceWithBranch(opts InstanceOptions) (*Instance, error) {
 	}
 
 	instance := &Instance{
+		ID:             newInstanceID(),
 		Title:          title,
 		Status:         Ready,
 		Path:           absPath,
@@ -198,11 +441,89 @@ func NewInstanceWithBranch(opts InstanceOptions) (*Instance, error) {
 		UpdatedAt:      t,
 		AutoYes:        opts.AutoYes,
 		existingBranch: true, // Mark this as using an existing branch
+		worktreeDir:    opts.WorktreeDir,
+		cfg:            opts.Config,
+		policy:         opts.Policy,
 	}
 
 	return instance, nil
 }
 
+// NewImportedInstance creates a paused instance for an existing local branch without creating a
+// worktree, so the branch can be managed and resumed later. If the title is empty, the branch
+// name is used.
+func NewImportedInstance(opts InstanceOptions) (*Instance, error) {
+	t := time.Now()
+
+	absPath, err := filepath.Abs(opts.Path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get absolute path: %w", err)
+	}
+
+	title := opts.Title
+	if title == "" {
+		title = opts.BranchName
+	}
+
+	worktree, err := git.NewGitWorktreeForImport(absPath, title, opts.BranchName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to import branch %s: %w", opts.BranchName, err)
+	}
+
+	return &Instance{
+		ID:             newInstanceID(),
+		Title:          title,
+		Status:         Paused,
+		Path:           absPath,
+		Program:        opts.Program,
+		Branch:         opts.BranchName,
+		CreatedAt:      t,
+		UpdatedAt:      t,
+		AutoYes:        opts.AutoYes,
+		started:        true,
+		tmuxSession:    tmux.NewTmuxSession(title, opts.Program),
+		gitWorktree:    worktree,
+		existingBranch: true,
+	}, nil
+}
+
+// NewAdoptedInstance creates an instance for a worktree created outside claude-squad. Starting
+// it runs the program in the worktree where it is, on its branch, after which the instance is
+// managed like any other. If the title is empty, the branch name is used.
+func NewAdoptedInstance(opts InstanceOptions, worktree git.Worktree) (*Instance, error) {
+	t := time.Now()
+
+	absPath, err := filepath.Abs(opts.Path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get absolute path: %w", err)
+	}
+
+	title := opts.Title
+	if title == "" {
+		title = worktree.Branch
+	}
+
+	gitWorktree, err := git.NewGitWorktreeForAdoption(absPath, title, worktree)
+	if err != nil {
+		return nil, fmt.Errorf("failed to adopt worktree %s: %w", worktree.Path, err)
+	}
+
+	return &Instance{
+		ID:             newInstanceID(),
+		Title:          title,
+		Status:         Ready,
+		Path:           absPath,
+		Program:        opts.Program,
+		Branch:         worktree.Branch,
+		CreatedAt:      t,
+		UpdatedAt:      t,
+		AutoYes:        opts.AutoYes,
+		gitWorktree:    gitWorktree,
+		existingBranch: true,
+		adopted:        true,
+	}, nil
+}
+
 func (i *Instance) RepoName() (string, error) {
 	if !i.started {
 		return "", fmt.Errorf("cannot get repo name for instance that has not been started")
@@ -211,9 +532,26 @@ func (i *Instance) RepoName() (string, error) {
 }
 
 func (i *Instance) SetStatus(status Status) {
+	if status == Ready && i.Status != Ready {
+		i.readySince = time.Now()
+	}
 	i.Status = status
 }
 
+// ResourceUsage returns the CPU and memory used by the instance's processes, and false if they
+// haven't been sampled.
+func (i *Instance) ResourceUsage() (tmux.ResourceUsage, bool) {
+	if !i.started || i.Status == Paused || i.tmuxSession == nil {
+		return tmux.ResourceUsage{}, false
+	}
+	return i.tmuxSession.ResourceUsage()
+}
+
+// ReadySince returns when the instance last became Ready.
+func (i *Instance) ReadySince() time.Time {
+	return i.readySince
+}
+
 // StartAsync starts the instance asynchronously, returning immediately.
 // The onComplete callback is called when the operation completes (with error if failed).
 func (i *Instance) StartAsync(firstTimeSetup bool, onComplete func(error)) {
@@ -245,28 +583,46 @@ func (i *Instance) Start(firstTimeSetup bool) error {
 		tmuxSession = i.tmuxSession
 	} else {
 		// Create new tmux session
-		tmuxSession = tmux.NewTmuxSession(i.Title, i.Program)
+		var err error
+		if tmuxSession, err = i.newTmuxSession(); err != nil {
+			return err
+		}
 	}
 	i.tmuxSession = tmuxSession
 
-	if firstTimeSetup {
-		if i.existingBranch && i.Branch != "" {
+	if firstTimeSetup && !i.adopted {
+		if i.Host != "" {
+			gitWorktree, err := i.newRemoteWorktree()
+			if err != nil {
+				return fmt.Errorf("failed to create git worktree on %s: %w", i.Host, err)
+			}
+			i.gitWorktree = gitWorktree
+		} else if i.existingBranch && i.Branch != "" {
 			// Create worktree for existing branch
-			gitWorktree, _, err := git.NewGitWorktreeForBranch(i.Path, i.Title, i.Branch)
+			gitWorktree, _, err := git.NewGitWorktreeForBranchInDir(i.Path, i.Title, i.Branch, i.worktreeDir)
 			if err != nil {
 				return fmt.Errorf("failed to create git worktree for branch %s: %w", i.Branch, err)
 			}
 			i.gitWorktree = gitWorktree
 		} else {
 			// Create new worktree with auto-generated branch
-			gitWorktree, branchName, err := git.NewGitWorktree(i.Path, i.Title)
+			gitWorktree, branchName, err := git.NewGitWorktreeWithOptions(i.Path, i.Title, git.WorktreeOptions{
+				Dir:          i.worktreeDir,
+				BranchPrefix: i.newBranchPrefix(),
+			})
 			if err != nil {
 				return fmt.Errorf("failed to create git worktree: %w", err)
 			}
+			gitWorktree.SetBaseRef(i.baseBranch)
 			i.gitWorktree = gitWorktree
 			i.Branch = branchName
 		}
+		i.gitWorktree.SetConfig(i.cfg, i.policy)
+		if i.Host == "" && i.settings().ContainersEnabled() {
+			i.Container = container.New(i.gitWorktree.GetWorktreePath()).Name()
+		}
 	}
+	i.setProgramCommand()
 
 	// Setup error handler to cleanup resources on any error
 	var setupErr error
@@ -281,25 +637,58 @@ func (i *Instance) Start(firstTimeSetup bool) error {
 	}()
 
 	if !firstTimeSetup {
-		// Reuse existing session
-		if err := tmuxSession.Restore(); err != nil {
-			setupErr = fmt.Errorf("failed to restore existing session: %w", err)
-			return setupErr
+		// A container stopped since, e.g. by a reboot, is started again for the program's next run
+		if err := i.startContainer(); err != nil {
+			log.WarningLog.Printf("failed to start container of %s: %v", i.Title, err)
+		}
+		if tmuxSession.DoesSessionExist() {
+			// Reattach to the session that outlived the previous run
+			if err := tmuxSession.Restore(); err != nil {
+				setupErr = fmt.Errorf("failed to restore existing session: %w", err)
+				return setupErr
+			}
+		} else {
+			// The session was lost, e.g. to a reboot, so recreate it with the saved scrollback. A
+			// missing worktree is left for the status checks to report.
+			i.sessionLost = true
+			log.WarningLog.Printf("tmux session %s of %s no longer exists, recreating it", tmuxSession.GetSessionName(), i.Title)
+			if i.gitWorktree.WorktreeExists() {
+				if err := i.recreateTmuxSession(); err != nil {
+					log.WarningLog.Printf("failed to recreate tmux session of %s: %v", i.Title, err)
+				}
+			}
 		}
 	} else {
-		// Setup git worktree first
-		if err := i.gitWorktree.Setup(); err != nil {
+		// Setup git worktree first. An adopted one exists already.
+		if i.adopted {
+			if !i.gitWorktree.WorktreeExists() {
+				setupErr = fmt.Errorf("worktree %s no longer exists", i.gitWorktree.GetWorktreePath())
+				return setupErr
+			}
+		} else if err := i.gitWorktree.Setup(); err != nil {
 			setupErr = fmt.Errorf("failed to setup git worktree: %w", err)
 			return setupErr
 		}
+		// Setup renames an existing branch that's checked out elsewhere
+		i.Branch = i.gitWorktree.GetBranchName()
+
+		if err := i.seedChanges(); err != nil {
+			setupErr = i.cleanupNewWorktree(fmt.Errorf("failed to bring the checkout's changes: %w", err))
+			return setupErr
+		}
+
+		if err := i.startContainer(); err != nil {
+			setupErr = i.cleanupNewWorktree(err)
+			return setupErr
+		}
 
 		// Create new session
 		if err := i.tmuxSession.Start(i.gitWorktree.GetWorktreePath()); err != nil {
-			// Cleanup git worktree if tmux session creation fails
-			if cleanupErr := i.gitWorktree.Cleanup(); cleanupErr != nil {
+			// Cleanup the container and git worktree if tmux session creation fails
+			if cleanupErr := i.removeContainer(); cleanupErr != nil {
 				err = fmt.Errorf("%v (cleanup error: %v)", err, cleanupErr)
 			}
-			setupErr = fmt.Errorf("failed to start new session: %w", err)
+			setupErr = fmt.Errorf("failed to start new session: %w", i.cleanupNewWorktree(err))
 			return setupErr
 		}
 	}
@@ -309,6 +698,18 @@ func (i *Instance) Start(firstTimeSetup bool) error {
 	return nil
 }
 
+// cleanupNewWorktree removes the worktree Start created after setting it up failed with err,
+// returning err with any cleanup error. Adopted worktrees aren't Start's to remove.
+func (i *Instance) cleanupNewWorktree(err error) error {
+	if i.adopted {
+		return err
+	}
+	if cleanupErr := i.gitWorktree.Cleanup(); cleanupErr != nil {
+		return fmt.Errorf("%v (cleanup error: %v)", err, cleanupErr)
+	}
+	return err
+}
+
 // KillAsync terminates the instance asynchronously, returning immediately.
 // The onComplete callback is called when the operation completes (with error if failed).
 func (i *Instance) KillAsync(onComplete func(error)) {
@@ -356,10 +757,18 @@ func (i *Instance) Kill() error {
 	// Always try to cleanup both resources, even if one fails
 	// Clean up tmux session first since it's using the git worktree
 	if i.tmuxSession != nil {
+		i.stopServices()
 		if err := i.tmuxSession.Close(); err != nil {
 			errs = append(errs, fmt.Errorf("failed to close tmux session: %w", err))
 		}
+		i.removeScrollback()
+	}
+
+	// Then the container the program ran in
+	if err := i.removeContainer(); err != nil {
+		errs = append(errs, err)
 	}
+	i.removeHooks()
 
 	// Then clean up git worktree
 	if i.gitWorktree != nil {
@@ -397,10 +806,10 @@ func (i *Instance) ForceKill() error {
 		sessionName := i.tmuxSession.GetSessionName()
 		if sessionName != "" {
 			// Check if session exists before trying to kill it
-			checkCmd := exec.Command("tmux", "has-session", "-t", sessionName)
+			checkCmd := i.tmuxSession.TmuxCommand("has-session", "-t", sessionName)
 			if checkErr := checkCmd.Run(); checkErr == nil {
 				// Session exists, try to kill it
-				killCmd := exec.Command("tmux", "kill-session", "-t", sessionName)
+				killCmd := i.tmuxSession.TmuxCommand("kill-session", "-t", sessionName)
 				if err := killCmd.Run(); err != nil {
 					errs = append(errs, fmt.Errorf("failed to force kill tmux session: %w", err))
 				}
@@ -408,6 +817,11 @@ func (i *Instance) ForceKill() error {
 		}
 	}
 
+	if err := i.removeContainer(); err != nil {
+		errs = append(errs, err)
+	}
+	i.removeHooks()
+
 	// Force cleanup git worktree
 	if i.gitWorktree != nil {
 		// Try force cleanup
@@ -416,7 +830,7 @@ func (i *Instance) ForceKill() error {
 
 			// If git cleanup failed, try manual filesystem cleanup
 			worktreePath := i.gitWorktree.GetWorktreePath()
-			if worktreePath != "" && worktreePath != "/" && strings.Contains(worktreePath, "worktrees") {
+			if i.Host == "" && worktreePath != "" && worktreePath != "/" && strings.Contains(worktreePath, "worktrees") {
 				if err := os.RemoveAll(worktreePath); err != nil {
 					errs = append(errs, fmt.Errorf("failed to manually remove worktree directory: %w", err))
 				}
@@ -436,6 +850,110 @@ func (i *Instance) ForceKill() error {
 	return nil
 }
 
+// KillKeepBranch terminates the instance and removes its worktree but keeps the branch.
+// Uncommitted changes are committed to the branch first so no work is lost.
+func (i *Instance) KillKeepBranch() error {
+	if !i.started {
+		return nil
+	}
+
+	// Paused instances have no worktree, and so nothing to commit
+	if i.Status != Paused {
+		if err := i.commitDirtyChanges("killed"); err != nil {
+			return err
+		}
+	}
+
+	var errs []error
+
+	if i.tmuxSession != nil {
+		if err := i.tmuxSession.Close(); err != nil {
+			errs = append(errs, fmt.Errorf("failed to close tmux session: %w", err))
+		}
+		i.removeScrollback()
+	}
+
+	if err := i.removeContainer(); err != nil {
+		errs = append(errs, err)
+	}
+	i.removeHooks()
+
+	if i.gitWorktree != nil {
+		if i.gitWorktree.WorktreeExists() {
+			if err := i.gitWorktree.Remove(); err != nil {
+				errs = append(errs, fmt.Errorf("failed to remove git worktree: %w", err))
+			} else if err := i.gitWorktree.Prune(); err != nil {
+				errs = append(errs, fmt.Errorf("failed to prune git worktrees: %w", err))
+			}
+		}
+	}
+
+	if err := i.combineErrors(errs); err != nil {
+		return err
+	}
+
+	i.started = false
+	return nil
+}
+
+// commitDirtyChanges commits any uncommitted changes in the worktree locally (without
+// pushing to GitHub). reason is appended to the commit message.
+func (i *Instance) commitDirtyChanges(reason string) error {
+	dirty, err := i.gitWorktree.IsDirty()
+	if err != nil {
+		err = fmt.Errorf("failed to check if worktree is dirty: %w", err)
+		log.ErrorLog.Print(err)
+		return err
+	}
+	if !dirty {
+		return nil
+	}
+
+	if err := i.gitWorktree.CommitChanges(i.dirtyCommitMessage(reason)); err != nil {
+		err = fmt.Errorf("failed to commit changes: %w", err)
+		log.ErrorLog.Print(err)
+		return err
+	}
+	return nil
+}
+
+// dirtyCommitMessage is the message uncommitted changes are committed with before the worktree
+// goes away. reason is appended to it.
+func (i *Instance) dirtyCommitMessage(reason string) string {
+	return fmt.Sprintf("[claudesquad] update from '%s' on %s (%s)", i.Title, time.Now().Format(time.RFC822), reason)
+}
+
+// KillGitCommands returns the git commands killing the instance would run: Kill's when the
+// branch is deleted, KillKeepBranch's when it's kept.
+func (i *Instance) KillGitCommands(keepBranch bool) ([]string, error) {
+	if !i.started || i.gitWorktree == nil {
+		return nil, nil
+	}
+	return i.gitWorktree.DryRun(func(wt *git.GitWorktree) error {
+		if !keepBranch {
+			return wt.Cleanup()
+		}
+		if i.Status != Paused {
+			dirty, err := wt.IsDirty()
+			if err != nil {
+				return err
+			}
+			if dirty {
+				if err := wt.CommitChanges(i.dirtyCommitMessage("killed")); err != nil {
+					return err
+				}
+			}
+		}
+		if !wt.WorktreeExists() {
+			return nil
+		}
+		if err := wt.Remove(); err != nil {
+			return err
+		}
+		return wt.Prune()
+	})
+}
+
 // combineErrors combines multiple errors into a single error
 func (i *Instance) combineErrors(errs []error) error {
 	if len(errs) == 0 {
@@ -478,19 +996,58 @@ func (i *Instance) HasUpdated() (updated bool, hasPrompt bool) {
 		return false, false
 	}
 
+	// Status hooks report exactly what the agent does; the pane is only guessed from
+	if state, ok := i.hookState(); ok {
+		return state == hooks.Running, state == hooks.WaitingForPermission
+	}
 	return i.tmuxSession.HasUpdated()
 }
 
-// TapEnter sends an enter key press to the tmux session if AutoYes is enabled.
+// TapEnter sends an enter key press to the tmux session if AutoYes is enabled and the auto-yes
+// policy confirms the prompt shown in the pane.
 func (i *Instance) TapEnter() {
 	if !i.started || !i.AutoYes {
 		return
 	}
+	if policy := i.settings().AutoYesPolicy; policy != nil {
+		content, err := i.tmuxSession.CapturePaneContent()
+		if err != nil {
+			log.ErrorLog.Printf("error capturing prompt for auto-yes: %v", err)
+			return
+		}
+		prompt := promptText(content)
+		if confirmed, reason := policy.Confirms(prompt); !confirmed {
+			if prompt != i.heldPrompt {
+				log.InfoLog.Printf("auto-yes left a prompt of '%s' for the user (%s): %s", i.Title, reason, prompt)
+				i.heldPrompt = prompt
+			}
+			return
+		}
+	}
+	i.heldPrompt = ""
 	if err := i.tmuxSession.TapEnter(); err != nil {
 		log.ErrorLog.Printf("error tapping enter: %v", err)
 	}
 }
 
+// promptLines is how many lines at the bottom of the pane are taken as the prompt text.
+const promptLines = 20
+
+// promptText returns the text of a prompt shown at the bottom of the pane content, without
+// colors or blank lines.
+func promptText(content string) string {
+	var lines []string
+	for _, line := range strings.Split(ansi.Strip(content), "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	if len(lines) > promptLines {
+		lines = lines[len(lines)-promptLines:]
+	}
+	return strings.Join(lines, "\n")
+}
+
 func (i *Instance) Attach() (chan struct{}, error) {
 	if !i.started {
 		return nil, fmt.Errorf("cannot attach instance that has not been started")
@@ -564,12 +1121,13 @@ func (i *Instance) GetTerminalFullHistory() (string, error) {
 
 	// Terminal is in pane 0, capture with full history (-S - means from start of history)
 	// We need to specify the target pane explicitly
-	cmd := exec.Command("tmux", "capture-pane", "-p", "-e", "-J", "-S", "-", "-t", i.tmuxSession.GetSessionName()+".0")
+	cmd := i.tmuxSession.TmuxCommand("capture-pane", "-p", "-e", "-J", "-S", "-", "-t", i.tmuxSession.GetSessionName()+".0")
 	output, err := cmd.Output()
 	if err != nil {
 		return "", fmt.Errorf("failed to capture terminal full history: %v", err)
 	}
-	return string(output), nil
+	// Scrollback from before the session was lost comes first
+	return i.recoveredTerminal + string(output), nil
 }
 
 // GetAIFullHistory captures the entire AI pane output including full scrollback history
@@ -590,12 +1148,58 @@ func (i *Instance) GetAIFullHistory() (string, error) {
 
 	// AI is in pane 1, capture with full history (-S - means from start of history)
 	// We need to specify the target pane explicitly
-	cmd := exec.Command("tmux", "capture-pane", "-p", "-e", "-J", "-S", "-", "-t", i.tmuxSession.GetSessionName()+".1")
+	cmd := i.tmuxSession.TmuxCommand("capture-pane", "-p", "-e", "-J", "-S", "-", "-t", i.tmuxSession.GetSessionName()+".1")
 	output, err := cmd.Output()
 	if err != nil {
 		return "", fmt.Errorf("failed to capture AI full history: %v", err)
 	}
-	return string(output), nil
+	// Scrollback from before the session was lost comes first
+	return i.recoveredAI + string(output), nil
+}
+
+// SampleActivity records the line counts of the AI and terminal panes so their histories can be
+// interleaved by time.
+func (i *Instance) SampleActivity() {
+	if !i.started || i.Status == Paused || i.tmuxSession == nil {
+		return
+	}
+	counts, err := i.tmuxSession.PaneLineCounts()
+	if err != nil {
+		return
+	}
+	now := time.Now()
+	if len(counts) == 1 {
+		// The AI runs in pane 0 until the terminal pane is split off above it
+		i.aiTimeline.Record(counts[0], now)
+		return
+	}
+	i.terminalTimeline.Record(counts[0], now)
+	i.aiTimeline.Record(counts[1], now)
+}
+
+// GetCombinedHistory captures the full history of both panes and interleaves their lines by the
+// time they appeared.
+func (i *Instance) GetCombinedHistory() ([]tmux.TimedLine, error) {
+	if !i.started || i.Status == Paused {
+		return nil, fmt.Errorf("instance not available")
+	}
+	if err := i.ensureTmuxSession(); err != nil {
+		return nil, err
+	}
+	if err := i.tmuxSession.CreateTerminalPane(i.gitWorktree.GetWorktreePath()); err != nil {
+		return nil, fmt.Errorf("failed to create terminal pane: %v", err)
+	}
+
+	terminalLines, err := i.tmuxSession.CapturePaneLines(0)
+	if err != nil {
+		return nil, err
+	}
+	aiLines, err := i.tmuxSession.CapturePaneLines(1)
+	if err != nil {
+		return nil, err
+	}
+
+	return tmux.Interleave(i.aiTimeline.Stamp(aiLines, "ai"), i.terminalTimeline.Stamp(terminalLines, "terminal")), nil
 }
 
 func (i *Instance) SetPreviewSize(width, height int) error {
@@ -611,9 +1215,18 @@ func (i *Instance) GetGitWorktree() (*git.GitWorktree, error) {
 	if !i.started {
 		return nil, fmt.Errorf("cannot get git worktree for instance that has not been started")
 	}
+	if err := i.checkWorktree(); err != nil {
+		return nil, err
+	}
 	return i.gitWorktree, nil
 }
 
+// HasRemote reports whether the instance's repository has an origin remote. Instances whose
+// worktree isn't known yet are assumed to have one.
+func (i *Instance) HasRemote() bool {
+	return i.gitWorktree == nil || i.gitWorktree.HasRemote()
+}
+
 func (i *Instance) Started() bool {
 	return i.started
 }
@@ -639,12 +1252,13 @@ func (i *Instance) TmuxAlive() bool {
 
 // ensureTmuxSession checks if the tmux session exists and recreates it if needed
 func (i *Instance) ensureTmuxSession() error {
+	// Recreating the session in a deleted worktree would only fail again on the next tick
+	if err := i.checkWorktree(); err != nil {
+		return err
+	}
 	if !i.tmuxSession.DoesSessionExist() {
 		log.InfoLog.Printf("tmux session %s was killed, recreating in %s...", i.tmuxSession.GetSessionName(), i.gitWorktree.GetWorktreePath())
-		// Recreate the session in the correct worktree directory
-		if err := i.tmuxSession.Start(i.gitWorktree.GetWorktreePath()); err != nil {
-			return fmt.Errorf("failed to recreate tmux session: %v", err)
-		}
+		return i.recreateTmuxSession()
 	}
 	return nil
 }
@@ -708,6 +1322,9 @@ func (i *Instance) Pause() error {
 		}
 	}
 
+	// Services would keep running in the removed worktree
+	i.stopServices()
+
 	// Detach from tmux session instead of closing to preserve session output
 	if err := i.tmuxSession.DetachSafely(); err != nil {
 		errs = append(errs, fmt.Errorf("failed to detach tmux session: %w", err))
@@ -715,8 +1332,14 @@ func (i *Instance) Pause() error {
 		// Continue with pause process even if detach fails
 	}
 
+	// The container goes with the worktree it mounts and is recreated on resume
+	if err := i.removeContainer(); err != nil {
+		errs = append(errs, err)
+		log.ErrorLog.Print(err)
+	}
+
 	// Check if worktree exists before trying to remove it
-	if _, err := os.Stat(i.gitWorktree.GetWorktreePath()); err == nil {
+	if i.gitWorktree.WorktreeExists() {
 		// Remove worktree but keep branch
 		if err := i.gitWorktree.Remove(); err != nil {
 			errs = append(errs, fmt.Errorf("failed to remove git worktree: %w", err))
@@ -738,10 +1361,11 @@ func (i *Instance) Pause() error {
 	}
 
 	i.SetStatus(Paused)
+	i.removeScrollback()
 	// Invalidate cache when pausing
 	i.diffStatsCache = nil
 	i.diffStatsCacheTime = time.Time{}
-	_ = clipboard.WriteAll(i.gitWorktree.GetBranchName())
+	_ = i.Copy("branch", i.gitWorktree.GetBranchName())
 	return nil
 }
 
@@ -768,6 +1392,15 @@ func (i *Instance) Resume() error {
 		return fmt.Errorf("failed to setup git worktree: %w", err)
 	}
 
+	if err := i.startContainer(); err != nil {
+		log.ErrorLog.Print(err)
+		// Leave the instance paused, without a worktree, to resume again
+		if removeErr := i.gitWorktree.Remove(); removeErr != nil {
+			err = fmt.Errorf("%v (cleanup error: %v)", err, removeErr)
+		}
+		return err
+	}
+
 	// Check if tmux session still exists from pause, otherwise create new one
 	if i.tmuxSession.DoesSessionExist() {
 		// Session exists, just restore PTY connection to it
@@ -818,8 +1451,10 @@ func (i *Instance) UpdateDiffStats() error {
 
 	// Check if cache is still fresh
 	if i.diffStatsCache != nil && time.Since(i.diffStatsCacheTime) < diffStatsCacheTTL {
+		git.RecordCacheLookup("diff stats", true)
 		return nil
 	}
+	git.RecordCacheLookup("diff stats", false)
 
 	stats := i.gitWorktree.Diff()
 	if stats.Error != nil {
@@ -834,9 +1469,17 @@ func (i *Instance) UpdateDiffStats() error {
 
 	i.diffStatsCache = stats
 	i.diffStatsCacheTime = time.Now()
+	i.recordDiffSample(stats.Added, stats.Removed, i.diffStatsCacheTime)
 	return nil
 }
 
+// SetDiffStats replaces the cached git diff statistics, e.g. with ones computed in the background
+// after the worktree was recreated. Like UpdateDiffStats, it must be called from the UI goroutine.
+func (i *Instance) SetDiffStats(stats *git.DiffStats) {
+	i.diffStatsCache = stats
+	i.diffStatsCacheTime = time.Now()
+}
+
 // GetDiffStats returns the cached git diff statistics
 func (i *Instance) GetDiffStats() *git.DiffStats {
 	if !i.started {
@@ -845,6 +1488,52 @@ func (i *Instance) GetDiffStats() *git.DiffStats {
 	return i.diffStatsCache
 }
 
+// prStatusCacheTTL defines how long the cached pull request status is valid
+const prStatusCacheTTL = time.Minute
+
+// UpdatePRStatus refreshes the cached pull request status for the instance's branch. It shells
+// out to gh, so call it off the UI thread. Branches without a PR clear the cache.
+func (i *Instance) UpdatePRStatus() error {
+	// The checkouts of instances on remote hosts aren't visible to the local CLI
+	if !i.started || i.Status == Paused || i.Host != "" {
+		return nil
+	}
+
+	i.prStatusMu.RLock()
+	fresh := time.Since(i.prStatusTime) < prStatusCacheTTL
+	i.prStatusMu.RUnlock()
+	if fresh {
+		return nil
+	}
+
+	// Local-only repositories can't have pull requests
+	if !i.gitWorktree.HasRemote() {
+		return nil
+	}
+
+	status, err := git.GetPRStatus(i.gitWorktree.GetWorktreePath())
+
+	i.prStatusMu.Lock()
+	defer i.prStatusMu.Unlock()
+	i.prStatusTime = time.Now()
+	if err != nil {
+		i.prStatus = nil
+		if strings.Contains(err.Error(), "no pull request found") {
+			return nil
+		}
+		return err
+	}
+	i.prStatus = status
+	return nil
+}
+
+// GetPRStatus returns the cached pull request status, or nil if the branch has no known PR
+func (i *Instance) GetPRStatus() *git.PRStatus {
+	i.prStatusMu.RLock()
+	defer i.prStatusMu.RUnlock()
+	return i.prStatus
+}
+
 // GetLastCommitDiffStats returns the diff statistics for uncommitted changes if they exist, otherwise the last commit
 func (i *Instance) GetLastCommitDiffStats() *git.DiffStats {
 	if !i.started {
@@ -885,6 +1574,23 @@ func (i *Instance) GetCommitDiffAtOffset(offset int) *git.DiffStats {
 	return i.gitWorktree.DiffCommitAtOffset(offset)
 }
 
+// GetDiffAgainst returns the diff of the worktree against ref, a branch, tag or commit, instead of
+// the commit the branch started from. It shells out to git.
+func (i *Instance) GetDiffAgainst(ref string) *git.DiffStats {
+	if !i.started {
+		return nil
+	}
+	return i.gitWorktree.GetDiffAgainst(ref)
+}
+
+// GetStagingDiff returns the changes staged in the instance's worktree and the unstaged ones.
+func (i *Instance) GetStagingDiff() (staged string, unstaged string, err error) {
+	if !i.started {
+		return "", "", fmt.Errorf("instance not started")
+	}
+	return i.gitWorktree.StagingDiff()
+}
+
 // GetCommitInfo returns the commit hash and message at the specified offset
 func (i *Instance) GetCommitInfo(offset int) (hash string, message string, err error) {
 	if !i.started {
@@ -905,12 +1611,68 @@ func (i *Instance) SendPrompt(prompt string) error {
 	if err := i.tmuxSession.SendKeys(prompt); err != nil {
 		return fmt.Errorf("error sending keys to tmux session: %w", err)
 	}
+	return i.submitPrompt(prompt)
+}
+
+// StartupPromptTimeout is how long SendStartupPrompt waits for the program to accept input.
+const StartupPromptTimeout = 30 * time.Second
+
+const (
+	// startupPromptAttempts is how many times SendStartupPrompt types the prompt before giving up
+	// on seeing it arrive.
+	startupPromptAttempts = 3
+	// promptEchoTimeout is how long typed keystrokes may take to show in the pane.
+	promptEchoTimeout = time.Second
+)
+
+// SendStartupPrompt sends the first prompt of a program that may still be starting. It waits until
+// the program accepts input and types the prompt again if the keystrokes don't show in the pane,
+// clearing whatever arrived of them first.
+func (i *Instance) SendStartupPrompt(prompt string) error {
+	if !i.started {
+		return fmt.Errorf("instance not started")
+	}
+	if i.tmuxSession == nil {
+		return fmt.Errorf("tmux session not initialized")
+	}
+
+	for attempt := 1; ; attempt++ {
+		if err := i.tmuxSession.WaitForReady(StartupPromptTimeout); err != nil {
+			// The program's input prompt may just not be recognized, so send the prompt regardless
+			log.WarningLog.Printf("instance %s: %v; sending the prompt anyway", i.Title, err)
+		}
+		before, err := i.tmuxSession.CapturePaneContent()
+		if err != nil {
+			return fmt.Errorf("error capturing pane content: %w", err)
+		}
+		if err := i.tmuxSession.SendKeys(prompt); err != nil {
+			return fmt.Errorf("error sending keys to tmux session: %w", err)
+		}
+		if i.tmuxSession.WaitForChange(before, promptEchoTimeout) {
+			break
+		}
+		if attempt == startupPromptAttempts {
+			return fmt.Errorf("the prompt didn't reach %s after %d attempts", i.Program, attempt)
+		}
+		log.WarningLog.Printf("instance %s: the prompt didn't show up, typing it again", i.Title)
+		// Ctrl+U clears the input line of any keystrokes that did arrive
+		if err := i.tmuxSession.SendKeys("\x15"); err != nil {
+			return fmt.Errorf("error clearing the input line: %w", err)
+		}
+	}
+
+	return i.submitPrompt(prompt)
+}
 
+// submitPrompt presses enter on a prompt typed into the program.
+func (i *Instance) submitPrompt(prompt string) error {
 	// Brief pause to prevent carriage return from being interpreted as newline
 	time.Sleep(100 * time.Millisecond)
 	if err := i.tmuxSession.TapEnter(); err != nil {
 		return fmt.Errorf("error tapping enter: %w", err)
 	}
+	i.recordPromptSent()
+	i.startPromptRun(prompt)
 
 	// Invalidate cache when sending a prompt as git state might change
 	i.diffStatsCache = nil
@@ -957,6 +1719,8 @@ func (i *Instance) SendPromptToAI(prompt string) error {
 	}
 
 	log.WarningLog.Printf("Successfully sent prompt and enter to AI pane")
+	i.recordPromptSent()
+	i.startPromptRun(prompt)
 	return nil
 }
 
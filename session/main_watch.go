@@ -0,0 +1,58 @@
+package session
+
+import (
+	"fmt"
+)
+
+// WatchesMain returns whether the instance's branch can be compared with origin's main branch:
+// it is running locally in a repository with a remote.
+func (i *Instance) WatchesMain() bool {
+	return i.started && i.Status != Paused && i.Host == "" && i.gitWorktree.HasRemote()
+}
+
+// FetchMain fetches origin's main branch into the instance's repository. It shells out to git,
+// so call it off the UI thread.
+func (i *Instance) FetchMain() error {
+	return i.gitWorktree.FetchMain()
+}
+
+// UpdateBehindMain counts the commits of origin's main branch, as of the last fetch, the branch
+// doesn't have. It shells out to git, so call it off the UI thread.
+func (i *Instance) UpdateBehindMain() (int, error) {
+	behind, err := i.gitWorktree.BehindMain()
+	if err != nil {
+		return 0, err
+	}
+	i.prStatusMu.Lock()
+	defer i.prStatusMu.Unlock()
+	i.behindMain = behind
+	return behind, nil
+}
+
+// BehindMain returns how many commits of origin's main branch the branch doesn't have, as of the
+// last update.
+func (i *Instance) BehindMain() int {
+	i.prStatusMu.RLock()
+	defer i.prStatusMu.RUnlock()
+	return i.behindMain
+}
+
+// AutoRebaseWithMain rebases the branch onto origin's main branch, as of the last fetch, if its
+// worktree is clean. A rebase that conflicts is aborted, leaving the branch as it was.
+func (i *Instance) AutoRebaseWithMain() error {
+	if i.gitWorktree.IsRebaseInProgress() {
+		return fmt.Errorf("a rebase is already in progress")
+	}
+	dirty, err := i.gitWorktree.IsDirty()
+	if err != nil {
+		return err
+	}
+	if dirty {
+		return fmt.Errorf("the worktree has uncommitted changes")
+	}
+	if err := i.gitWorktree.AutoRebaseWithMain(); err != nil {
+		return err
+	}
+	_, err = i.UpdateBehindMain()
+	return err
+}
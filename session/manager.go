@@ -0,0 +1,316 @@
+package session
+
+import (
+	"claude-squad/config"
+	"claude-squad/log"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ManagerOptions configures a Manager. The user's claude-squad config is never read: pass it as
+// Config to apply it.
+type ManagerOptions struct {
+	// WorktreeDir is the directory worktrees are created in. Empty uses the config directory.
+	WorktreeDir string
+	// BranchPrefix starts the names of new branches. Empty uses Config's prefix unless
+	// WorktreeDir is set.
+	BranchPrefix string
+	// DefaultProgram is run in instances created without a program.
+	DefaultProgram string
+	// MaxInstances limits how many instances can exist at once. Zero means no limit.
+	MaxInstances int
+	// Policy restricts the programs instances run, lowers MaxInstances and is enforced on the
+	// instances' rebases and pushes. Nil applies none.
+	Policy *config.Policy
+	// Config sets up the instances' containers, status hooks, remote hosts, auto-yes policy and
+	// branch prefix. Nil runs them without any of those.
+	Config *config.Config
+}
+
+// Manager runs the lifecycle of the instances kept in a Storage: creating, pausing, resuming and
+// killing them and sending them prompts. The TUI and the headless commands are built on the same
+// Instance methods; Manager bundles them for programs embedding claude-squad without its UI.
+//
+// Every call reloads the instances from storage and saves them back, so several processes can
+// share a storage as long as they don't change it at the same time. The context is checked
+// before each step; a step that has started, such as creating a worktree, is not interrupted.
+type Manager struct {
+	storage *Storage
+	opts    ManagerOptions
+}
+
+// NewManager creates a manager for the instances in storage.
+func NewManager(storage *Storage, opts ManagerOptions) *Manager {
+	if opts.Config == nil {
+		opts.Config = &config.Config{}
+	}
+	if opts.Policy == nil {
+		opts.Policy = &config.Policy{}
+	}
+	return &Manager{storage: storage, opts: opts}
+}
+
+// List returns the stored instances. Running instances are reattached to their tmux sessions.
+func (m *Manager) List(ctx context.Context) ([]*Instance, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	instances, err := m.storage.loadInstances(m.opts.Config, m.opts.Policy)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load instances: %w", err)
+	}
+	return instances, nil
+}
+
+// Get returns the stored instance with the given title.
+func (m *Manager) Get(ctx context.Context, title string) (*Instance, error) {
+	instances, err := m.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return findByTitle(instances, title)
+}
+
+// Create creates and starts an instance in the repository at opts.Path and saves it. The
+// manager's worktree directory, branch prefix, program and config fill in options left empty.
+func (m *Manager) Create(ctx context.Context, opts InstanceOptions) (*Instance, error) {
+	if strings.TrimSpace(opts.Title) == "" {
+		return nil, fmt.Errorf("instance title cannot be empty")
+	}
+	instances, err := m.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := findByTitle(instances, opts.Title); err == nil {
+		return nil, fmt.Errorf("instance %s already exists", opts.Title)
+	}
+	if limit := m.opts.Policy.InstanceLimit(m.opts.MaxInstances); limit > 0 && len(instances) >= limit {
+		return nil, fmt.Errorf("you can't create more than %d instances", limit)
+	}
+
+	if opts.Program == "" {
+		opts.Program = m.opts.DefaultProgram
+	}
+	if err := m.opts.Policy.CheckProgram(opts.Program); err != nil {
+		return nil, err
+	}
+	if opts.WorktreeDir == "" {
+		opts.WorktreeDir = m.opts.WorktreeDir
+	}
+	if opts.BranchPrefix == "" {
+		opts.BranchPrefix = m.opts.BranchPrefix
+	}
+	if opts.Config == nil {
+		opts.Config = m.opts.Config
+	}
+	if opts.Policy == nil {
+		opts.Policy = m.opts.Policy
+	}
+	instance, err := NewInstance(opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create instance: %w", err)
+	}
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if err := instance.Start(true); err != nil {
+		return nil, fmt.Errorf("failed to start instance: %w", err)
+	}
+	if err := m.storage.SaveInstances(append(instances, instance)); err != nil {
+		return instance, fmt.Errorf("failed to save instances: %w", err)
+	}
+	return instance, nil
+}
+
+// Kill stops the instance, removes its worktree and tmux session and forgets it. With
+// keepBranch its uncommitted changes are committed and the branch is kept.
+func (m *Manager) Kill(ctx context.Context, title string, keepBranch bool) error {
+	instances, err := m.List(ctx)
+	if err != nil {
+		return err
+	}
+	instance, err := findByTitle(instances, title)
+	if err != nil {
+		return err
+	}
+
+	// The commits are counted from the branch, which the kill may delete
+	activity := newActivity(instance.ToInstanceData())
+	if keepBranch {
+		err = instance.KillKeepBranch()
+	} else {
+		err = instance.Kill()
+	}
+	if err != nil {
+		return fmt.Errorf("failed to kill instance %s: %w", instance.Title, err)
+	}
+
+	remaining := make([]*Instance, 0, len(instances)-1)
+	for _, other := range instances {
+		if other != instance {
+			remaining = append(remaining, other)
+		}
+	}
+	if err := m.storage.SaveInstances(remaining); err != nil {
+		return fmt.Errorf("failed to save instances: %w", err)
+	}
+	recordKilled(activity)
+	return nil
+}
+
+// Pause commits the instance's changes and removes its worktree, keeping the branch.
+func (m *Manager) Pause(ctx context.Context, title string) (*Instance, error) {
+	return m.update(ctx, title, (*Instance).Pause)
+}
+
+// Resume recreates a paused instance's worktree and restarts its program.
+func (m *Manager) Resume(ctx context.Context, title string) (*Instance, error) {
+	return m.update(ctx, title, func(instance *Instance) error {
+		if err := m.opts.Policy.CheckProgram(instance.Program); err != nil {
+			return err
+		}
+		return instance.Resume()
+	})
+}
+
+// Send sends a prompt to the instance's program.
+func (m *Manager) Send(ctx context.Context, title, prompt string) error {
+	if strings.TrimSpace(prompt) == "" {
+		return fmt.Errorf("prompt cannot be empty")
+	}
+	instance, err := m.Get(ctx, title)
+	if err != nil {
+		return err
+	}
+	if instance.Paused() {
+		return fmt.Errorf("instance %s is paused; resume it first", instance.Title)
+	}
+	if err := instance.SendPrompt(prompt); err != nil {
+		return fmt.Errorf("failed to send prompt to %s: %w", instance.Title, err)
+	}
+	return nil
+}
+
+// update applies a state change to a stored instance and saves it.
+func (m *Manager) update(ctx context.Context, title string, change func(*Instance) error) (*Instance, error) {
+	instances, err := m.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	instance, err := findByTitle(instances, title)
+	if err != nil {
+		return nil, err
+	}
+	if err := change(instance); err != nil {
+		return nil, fmt.Errorf("failed to update instance %s: %w", instance.Title, err)
+	}
+	if err := m.storage.SaveInstances(instances); err != nil {
+		return nil, fmt.Errorf("failed to save instances: %w", err)
+	}
+	return instance, nil
+}
+
+func findByTitle(instances []*Instance, title string) (*Instance, error) {
+	for _, instance := range instances {
+		if instance.Title == title {
+			return instance, nil
+		}
+	}
+	return nil, fmt.Errorf("instance not found: %s", title)
+}
+
+// FileStore keeps instances in a JSON file of their own. It implements config.InstanceStorage
+// for programs that shouldn't share the TUI's state file.
+type FileStore struct {
+	path string
+}
+
+// NewFileStore creates a store backed by the file at path, which is created on the first save.
+func NewFileStore(path string) *FileStore {
+	return &FileStore{path: path}
+}
+
+// SaveInstances writes the instance data, replacing the file atomically.
+func (f *FileStore) SaveInstances(instancesJSON json.RawMessage) error {
+	return f.UpdateInstances(func(json.RawMessage) (json.RawMessage, error) {
+		return instancesJSON, nil
+	})
+}
+
+// UpdateInstances writes what update returns for the stored instance data, holding the file's
+// lock in between.
+func (f *FileStore) UpdateInstances(update func(stored json.RawMessage) (json.RawMessage, error)) error {
+	lock, err := config.LockFile(f.path + ".lock")
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := lock.Unlock(); err != nil {
+			log.WarningLog.Printf("failed to unlock %s: %v", f.path, err)
+		}
+	}()
+
+	stored, err := f.ReloadInstances()
+	if err != nil {
+		return err
+	}
+	instancesJSON, err := update(stored)
+	if err != nil {
+		return err
+	}
+	return f.write(instancesJSON)
+}
+
+// write replaces the file with the instance data atomically.
+func (f *FileStore) write(instancesJSON json.RawMessage) error {
+	if err := os.MkdirAll(filepath.Dir(f.path), 0755); err != nil {
+		return fmt.Errorf("failed to create directory for %s: %w", f.path, err)
+	}
+	tmp := f.path + ".tmp"
+	if err := os.WriteFile(tmp, instancesJSON, 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", tmp, err)
+	}
+	if err := os.Rename(tmp, f.path); err != nil {
+		return fmt.Errorf("failed to replace %s: %w", f.path, err)
+	}
+	return nil
+}
+
+// GetInstances returns the stored instance data, or an empty list if there is none yet.
+func (f *FileStore) GetInstances() json.RawMessage {
+	data, err := f.ReloadInstances()
+	if err != nil {
+		log.ErrorLog.Printf("failed to read %s: %v", f.path, err)
+		return json.RawMessage("[]")
+	}
+	return data
+}
+
+// ReloadInstances returns the stored instance data, or an empty list if there is none yet.
+func (f *FileStore) ReloadInstances() (json.RawMessage, error) {
+	data, err := os.ReadFile(f.path)
+	if os.IsNotExist(err) {
+		return json.RawMessage("[]"), nil
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", f.path, err)
+	}
+	return data, nil
+}
+
+// WatchPath returns the path of the file.
+func (f *FileStore) WatchPath() string {
+	return f.path
+}
+
+// DeleteAllInstances removes the file.
+func (f *FileStore) DeleteAllInstances() error {
+	if err := os.Remove(f.path); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to remove %s: %w", f.path, err)
+	}
+	return nil
+}
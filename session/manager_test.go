@@ -0,0 +1,145 @@
+package session
+
+import (
+	"claude-squad/config"
+	"claude-squad/log"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMain(m *testing.M) {
+	log.Initialize(false)
+	defer log.Close()
+	os.Exit(m.Run())
+}
+
+// newTestManager returns a manager keeping its instances and worktrees in a temporary directory,
+// with the home directory moved there too so nothing of the user's is read or written.
+func newTestManager(t *testing.T, opts ManagerOptions) (*Manager, string) {
+	t.Helper()
+	dir := t.TempDir()
+	t.Setenv("HOME", filepath.Join(dir, "home"))
+	storage, err := NewStorage(NewFileStore(filepath.Join(dir, "instances.json")))
+	require.NoError(t, err)
+	opts.WorktreeDir = filepath.Join(dir, "worktrees")
+	return NewManager(storage, opts), dir
+}
+
+// initTestRepo creates a repository with one commit at dir.
+func initTestRepo(t *testing.T, dir string) {
+	t.Helper()
+	for _, args := range [][]string{
+		{"init", "-q", "-b", "main", dir},
+		{"-C", dir, "-c", "user.name=test", "-c", "user.email=test@example.com", "commit", "-q", "--allow-empty", "-m", "initial"},
+	} {
+		output, err := exec.Command("git", args...).CombinedOutput()
+		require.NoError(t, err, "git %v: %s", args, output)
+	}
+}
+
+func TestManagerCreateChecks(t *testing.T) {
+	ctx := context.Background()
+	manager, dir := newTestManager(t, ManagerOptions{
+		DefaultProgram: "aider",
+		Policy:         &config.Policy{AllowedPrograms: []string{"claude"}, Source: "test"},
+	})
+
+	_, err := manager.Create(ctx, InstanceOptions{Title: " ", Path: dir})
+	assert.ErrorContains(t, err, "title cannot be empty")
+	_, err = manager.Create(ctx, InstanceOptions{Title: "web", Path: dir})
+	assert.ErrorContains(t, err, "not allowed", "the default program is checked against the policy")
+
+	_, err = manager.Get(ctx, "web")
+	assert.ErrorContains(t, err, "not found")
+	assert.ErrorContains(t, manager.Send(ctx, "web", "hello"), "not found")
+	assert.ErrorContains(t, manager.Send(ctx, "web", " "), "prompt cannot be empty")
+	assert.ErrorContains(t, manager.Kill(ctx, "web", false), "not found")
+
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+	_, err = manager.List(cancelled)
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func TestManagerIgnoresUserConfig(t *testing.T) {
+	ctx := context.Background()
+	manager, dir := newTestManager(t, ManagerOptions{DefaultProgram: "claude"})
+
+	// A remote host in the user's config isn't known to a manager not given that config
+	configDir, err := config.GetConfigDir()
+	require.NoError(t, err)
+	require.NoError(t, os.MkdirAll(configDir, 0755))
+	userConfig, err := json.Marshal(map[string]any{
+		"remote_hosts": map[string]config.RemoteHost{"box": {Host: "box.example.com", RepoPath: "/srv/repo"}},
+	})
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(configDir, config.ConfigFileName), userConfig, 0644))
+
+	_, err = manager.Create(ctx, InstanceOptions{Title: "web", Path: "/srv/repo", Host: "box"})
+	assert.ErrorContains(t, err, "remote host box is not configured")
+
+	// The manager's own config is used instead
+	storage, err := NewStorage(NewFileStore(filepath.Join(dir, "other.json")))
+	require.NoError(t, err)
+	manager = NewManager(storage, ManagerOptions{DefaultProgram: "claude", Config: &config.Config{
+		RemoteHosts: map[string]config.RemoteHost{"box": {Host: "box.example.com", RepoPath: "srv/repo"}},
+	}})
+	_, err = manager.Create(ctx, InstanceOptions{Title: "web", Path: "/srv/repo", Host: "box"})
+	assert.ErrorContains(t, err, "needs the absolute repo_path")
+}
+
+func TestManagerLifecycle(t *testing.T) {
+	if _, err := exec.LookPath("tmux"); err != nil {
+		t.Skip("tmux is not installed")
+	}
+	ctx := context.Background()
+	manager, dir := newTestManager(t, ManagerOptions{DefaultProgram: "sh", BranchPrefix: "bot/", MaxInstances: 1})
+	repo := filepath.Join(dir, "repo")
+	initTestRepo(t, repo)
+	title := fmt.Sprintf("manager-test-%d", time.Now().UnixNano())
+
+	instance, err := manager.Create(ctx, InstanceOptions{Title: title, Path: repo})
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		if _, err := manager.Get(ctx, title); err == nil {
+			manager.Kill(ctx, title, false)
+		}
+	})
+	assert.Equal(t, "bot/"+title, instance.Branch)
+	assert.Equal(t, "sh", instance.Program)
+
+	_, err = manager.Create(ctx, InstanceOptions{Title: title, Path: repo})
+	assert.ErrorContains(t, err, "already exists")
+	_, err = manager.Create(ctx, InstanceOptions{Title: "second", Path: repo})
+	assert.ErrorContains(t, err, "more than 1 instances")
+
+	instances, err := manager.List(ctx)
+	require.NoError(t, err)
+	require.Len(t, instances, 1)
+	assert.Equal(t, title, instances[0].Title)
+	require.NoError(t, manager.Send(ctx, title, "echo hello"))
+
+	paused, err := manager.Pause(ctx, title)
+	require.NoError(t, err)
+	assert.True(t, paused.Paused())
+	assert.ErrorContains(t, manager.Send(ctx, title, "echo again"), "is paused")
+	resumed, err := manager.Resume(ctx, title)
+	require.NoError(t, err)
+	assert.False(t, resumed.Paused())
+
+	require.NoError(t, manager.Kill(ctx, title, false))
+	instances, err = manager.List(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, instances)
+	assert.Error(t, exec.Command("git", "-C", repo, "rev-parse", "--verify", "refs/heads/bot/"+title).Run(),
+		"the branch is deleted with the instance")
+}
@@ -0,0 +1,271 @@
+package session
+
+import (
+	"claude-squad/config"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// diffSampleInterval is the least time between two samples of an instance's diff stats.
+	diffSampleInterval = 10 * time.Minute
+	// maxDiffSamples caps the diff history kept per instance, dropping the oldest samples.
+	maxDiffSamples = 1000
+)
+
+// costRe matches the session cost agents such as Claude Code print ("Total cost: $0.4213").
+var costRe = regexp.MustCompile(`(?i)cost:?\s*\$([0-9]+(?:\.[0-9]+)?)`)
+
+// DiffSample is an instance's diff stats at a point in time.
+type DiffSample struct {
+	At      time.Time `json:"at"`
+	Added   int       `json:"added"`
+	Removed int       `json:"removed"`
+}
+
+// Metrics are counters kept about an instance's run for exporting.
+type Metrics struct {
+	PromptsSent    int `json:"prompts_sent,omitempty"`
+	TestRuns       int `json:"test_runs,omitempty"`
+	FailedTestRuns int `json:"failed_test_runs,omitempty"`
+	// LastTestRunAt and LastFailedTestFiles describe the most recent test run
+	LastTestRunAt       time.Time    `json:"last_test_run_at,omitempty"`
+	LastFailedTestFiles int          `json:"last_failed_test_files,omitempty"`
+	DiffHistory         []DiffSample `json:"diff_history,omitempty"`
+	// Usage is the tokens the agent used and their cost
+	Usage Usage `json:"usage"`
+}
+
+// GetMetrics returns a copy of the instance's metrics.
+func (i *Instance) GetMetrics() Metrics {
+	i.metricsMu.Lock()
+	defer i.metricsMu.Unlock()
+	metrics := i.metrics
+	metrics.DiffHistory = append([]DiffSample(nil), i.metrics.DiffHistory...)
+	return metrics
+}
+
+// RecordTestRun counts a finished test run and how many test files failed in it.
+func (i *Instance) RecordTestRun(failedFiles int) {
+	i.metricsMu.Lock()
+	defer i.metricsMu.Unlock()
+	i.metrics.TestRuns++
+	if failedFiles > 0 {
+		i.metrics.FailedTestRuns++
+	}
+	i.metrics.LastTestRunAt = time.Now()
+	i.metrics.LastFailedTestFiles = failedFiles
+}
+
+// recordPromptSent counts a prompt sent to the agent.
+func (i *Instance) recordPromptSent() {
+	i.metricsMu.Lock()
+	defer i.metricsMu.Unlock()
+	i.metrics.PromptsSent++
+}
+
+// recordDiffSample adds the diff stats to the history if they changed and the last sample is
+// old enough.
+func (i *Instance) recordDiffSample(added, removed int, now time.Time) {
+	i.metricsMu.Lock()
+	defer i.metricsMu.Unlock()
+	history := i.metrics.DiffHistory
+	if n := len(history); n > 0 {
+		last := history[n-1]
+		if (last.Added == added && last.Removed == removed) || now.Sub(last.At) < diffSampleInterval {
+			return
+		}
+	}
+	history = append(history, DiffSample{At: now, Added: added, Removed: removed})
+	if len(history) > maxDiffSamples {
+		history = history[len(history)-maxDiffSamples:]
+	}
+	i.metrics.DiffHistory = history
+}
+
+// MetricsSnapshot is what is known about an instance's run at one point in time.
+type MetricsSnapshot struct {
+	Title      string    `json:"title"`
+	Repo       string    `json:"repo,omitempty"`
+	Branch     string    `json:"branch"`
+	Program    string    `json:"program"`
+	Status     string    `json:"status"`
+	Tags       []string  `json:"tags,omitempty"`
+	CreatedAt  time.Time `json:"created_at"`
+	SnapshotAt time.Time `json:"snapshot_at"`
+	// Age, WorkTime and TimeBudget are exported in seconds
+	Age           Duration `json:"age"`
+	WorkTime      Duration `json:"work_time"`
+	TimeBudget    Duration `json:"time_budget,omitempty"`
+	QueuedPrompts int      `json:"queued_prompts"`
+	Checkpoints   int      `json:"checkpoints"`
+	Added         int      `json:"added"`
+	Removed       int      `json:"removed"`
+	// Cost is the last session cost the agent printed, if any
+	Cost *float64 `json:"cost,omitempty"`
+	Metrics
+}
+
+// Duration is a time.Duration that is encoded in JSON as seconds.
+type Duration time.Duration
+
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return []byte(strconv.FormatFloat(time.Duration(d).Seconds(), 'f', 0, 64)), nil
+}
+
+// statusNames are the names of statuses in exported metrics.
+var statusNames = map[Status]string{
+	Running:  "running",
+	Ready:    "ready",
+	Loading:  "loading",
+	Paused:   "paused",
+	Creating: "creating",
+	Deleting: "deleting",
+}
+
+// NewMetricsSnapshot captures the instance's metrics. The cost is read from the agent's pane
+// history, so the agent must have printed it, e.g. in reply to /cost.
+func NewMetricsSnapshot(instance *Instance, now time.Time) MetricsSnapshot {
+	snapshot := MetricsSnapshot{
+		Title:         instance.Title,
+		Branch:        instance.Branch,
+		Program:       instance.Program,
+		Status:        statusNames[instance.Status],
+		Tags:          instance.Tags,
+		CreatedAt:     instance.CreatedAt,
+		SnapshotAt:    now,
+		Age:           Duration(now.Sub(instance.CreatedAt)),
+		WorkTime:      Duration(instance.WorkTime),
+		TimeBudget:    Duration(instance.TimeBudget),
+		QueuedPrompts: len(instance.PromptQueue),
+		Checkpoints:   len(instance.Checkpoints),
+		Metrics:       instance.GetMetrics(),
+	}
+	if stats := instance.GetDiffStats(); stats != nil {
+		snapshot.Added = stats.Added
+		snapshot.Removed = stats.Removed
+	}
+	if worktree, err := instance.GetGitWorktree(); err == nil && worktree != nil {
+		snapshot.Repo = worktree.GetRepoName()
+	}
+	if history, err := instance.PreviewFullHistory(); err == nil {
+		snapshot.Cost = lastCost(history)
+	}
+	return snapshot
+}
+
+// lastCost returns the last cost printed in the pane content, or nil if there is none.
+func lastCost(content string) *float64 {
+	matches := costRe.FindAllStringSubmatch(content, -1)
+	if len(matches) == 0 {
+		return nil
+	}
+	cost, err := strconv.ParseFloat(matches[len(matches)-1][1], 64)
+	if err != nil {
+		return nil
+	}
+	return &cost
+}
+
+// ExportMetrics snapshots the instances' metrics and writes them as CSV and JSON files named
+// after the time of the snapshot to the metrics directory in the config directory, returning
+// the path of the CSV file.
+func ExportMetrics(instances []*Instance, now time.Time) (string, error) {
+	configDir, err := config.GetConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get config directory: %w", err)
+	}
+	dir := filepath.Join(configDir, config.MetricsDirName)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", fmt.Errorf("failed to create metrics directory: %w", err)
+	}
+
+	snapshots := make([]MetricsSnapshot, len(instances))
+	for idx, instance := range instances {
+		snapshots[idx] = NewMetricsSnapshot(instance, now)
+	}
+	base := filepath.Join(dir, "metrics-"+now.Format("20060102-150405"))
+	for ext, write := range map[string]func(io.Writer, []MetricsSnapshot) error{
+		".csv":  WriteMetricsCSV,
+		".json": WriteMetricsJSON,
+	} {
+		f, err := os.Create(base + ext)
+		if err != nil {
+			return "", fmt.Errorf("failed to create metrics file: %w", err)
+		}
+		err = write(f, snapshots)
+		if closeErr := f.Close(); err == nil && closeErr != nil {
+			err = fmt.Errorf("failed to write metrics file: %w", closeErr)
+		}
+		if err != nil {
+			return "", err
+		}
+	}
+	return base + ".csv", nil
+}
+
+// WriteMetricsJSON writes the snapshots as an indented JSON array, diff history included.
+func WriteMetricsJSON(w io.Writer, snapshots []MetricsSnapshot) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(snapshots); err != nil {
+		return fmt.Errorf("failed to write metrics: %w", err)
+	}
+	return nil
+}
+
+// metricsCSVHeader names the columns WriteMetricsCSV writes.
+var metricsCSVHeader = []string{
+	"snapshot_at", "title", "repo", "branch", "program", "status", "tags", "created_at", "age_minutes",
+	"work_minutes", "time_budget_minutes", "prompts_sent", "queued_prompts", "checkpoints", "added",
+	"removed", "diff_samples", "test_runs", "failed_test_runs", "last_test_run_at",
+	"last_failed_test_files", "cost_usd",
+}
+
+// WriteMetricsCSV writes the snapshots as CSV, one row per instance. The diff history is only
+// counted; it is in the JSON export.
+func WriteMetricsCSV(w io.Writer, snapshots []MetricsSnapshot) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(metricsCSVHeader); err != nil {
+		return fmt.Errorf("failed to write metrics: %w", err)
+	}
+	minutes := func(d Duration) string {
+		return strconv.FormatFloat(time.Duration(d).Minutes(), 'f', 1, 64)
+	}
+	timestamp := func(t time.Time) string {
+		if t.IsZero() {
+			return ""
+		}
+		return t.Format(time.RFC3339)
+	}
+	for _, s := range snapshots {
+		cost := ""
+		if s.Cost != nil {
+			cost = strconv.FormatFloat(*s.Cost, 'f', 4, 64)
+		}
+		row := []string{
+			timestamp(s.SnapshotAt), s.Title, s.Repo, s.Branch, s.Program, s.Status, strings.Join(s.Tags, ";"),
+			timestamp(s.CreatedAt),
+			minutes(s.Age), minutes(s.WorkTime), minutes(s.TimeBudget), strconv.Itoa(s.PromptsSent),
+			strconv.Itoa(s.QueuedPrompts), strconv.Itoa(s.Checkpoints), strconv.Itoa(s.Added),
+			strconv.Itoa(s.Removed), strconv.Itoa(len(s.DiffHistory)), strconv.Itoa(s.TestRuns),
+			strconv.Itoa(s.FailedTestRuns), timestamp(s.LastTestRunAt), strconv.Itoa(s.LastFailedTestFiles), cost,
+		}
+		if err := writer.Write(row); err != nil {
+			return fmt.Errorf("failed to write metrics: %w", err)
+		}
+	}
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return fmt.Errorf("failed to write metrics: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,191 @@
+package session
+
+import (
+	"bufio"
+	"claude-squad/config"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// Outcome results and effort estimates offered when rating an agent run.
+var (
+	OutcomeResults = []string{"success", "partial", "failed"}
+	OutcomeEfforts = []string{"small", "medium", "large"}
+)
+
+// Dispositions record what happened to the instance's branch when it was killed.
+const (
+	DispositionDeleted  = "deleted"
+	DispositionKept     = "kept"
+	DispositionArchived = "archived"
+)
+
+// Outcome is the user's structured rating of an agent run.
+type Outcome struct {
+	Result string `json:"result"`
+	Effort string `json:"effort,omitempty"`
+	Notes  string `json:"notes,omitempty"`
+}
+
+// OutcomeRecord is an outcome together with what is known about the run it rates.
+type OutcomeRecord struct {
+	Outcome
+	Title          string    `json:"title"`
+	Program        string    `json:"program"`
+	Prompt         string    `json:"prompt,omitempty"`
+	Branch         string    `json:"branch"`
+	ArchivedBranch string    `json:"archived_branch,omitempty"`
+	Disposition    string    `json:"disposition"`
+	Repo           string    `json:"repo,omitempty"`
+	CreatedAt      time.Time `json:"created_at"`
+	EndedAt        time.Time `json:"ended_at"`
+	Checkpoints    int       `json:"checkpoints"`
+	Added          int       `json:"added"`
+	Removed        int       `json:"removed"`
+}
+
+// NewOutcomeRecord captures the instance's metadata alongside the outcome. Call it before the
+// instance is torn down, while its worktree and diff stats are still available.
+func NewOutcomeRecord(instance *Instance, disposition string, outcome Outcome) OutcomeRecord {
+	record := OutcomeRecord{
+		Outcome:     outcome,
+		Title:       instance.Title,
+		Program:     instance.Program,
+		Prompt:      instance.Prompt,
+		Branch:      instance.Branch,
+		Disposition: disposition,
+		CreatedAt:   instance.CreatedAt,
+		EndedAt:     time.Now(),
+		Checkpoints: len(instance.Checkpoints),
+	}
+	if stats := instance.GetDiffStats(); stats != nil {
+		record.Added = stats.Added
+		record.Removed = stats.Removed
+	}
+	if worktree, err := instance.GetGitWorktree(); err == nil && worktree != nil {
+		record.Repo = worktree.GetRepoName()
+	}
+	return record
+}
+
+func outcomesPath() (string, error) {
+	configDir, err := config.GetConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get config directory: %w", err)
+	}
+	return filepath.Join(configDir, config.OutcomesFileName), nil
+}
+
+// SaveOutcome appends the record to the outcomes file.
+func SaveOutcome(record OutcomeRecord) error {
+	path, err := outcomesPath()
+	if err != nil {
+		return err
+	}
+	data, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("failed to marshal outcome: %w", err)
+	}
+
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open outcomes file: %w", err)
+	}
+	defer f.Close()
+	if _, err := f.Write(append(data, '\n')); err != nil {
+		return fmt.Errorf("failed to write outcome: %w", err)
+	}
+	return nil
+}
+
+// LoadOutcomes reads all recorded outcomes, oldest first. Malformed lines are skipped.
+func LoadOutcomes() ([]OutcomeRecord, error) {
+	path, err := outcomesPath()
+	if err != nil {
+		return nil, err
+	}
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to open outcomes file: %w", err)
+	}
+	defer f.Close()
+
+	var records []OutcomeRecord
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		var record OutcomeRecord
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			continue
+		}
+		records = append(records, record)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read outcomes file: %w", err)
+	}
+	return records, nil
+}
+
+// OutcomeStats aggregates a group of outcome records.
+type OutcomeStats struct {
+	Key      string
+	Total    int
+	Results  map[string]int
+	Duration time.Duration
+}
+
+// SuccessRate returns the fraction of runs rated a success.
+func (s *OutcomeStats) SuccessRate() float64 {
+	if s.Total == 0 {
+		return 0
+	}
+	return float64(s.Results["success"]) / float64(s.Total)
+}
+
+// AverageDuration returns the mean time from instance creation to rating.
+func (s *OutcomeStats) AverageDuration() time.Duration {
+	if s.Total == 0 {
+		return 0
+	}
+	return s.Duration / time.Duration(s.Total)
+}
+
+// GroupOutcomes aggregates records by the value key returns for each, sorted by descending count.
+// Records with an empty key are grouped under "unknown".
+func GroupOutcomes(records []OutcomeRecord, key func(OutcomeRecord) string) []*OutcomeStats {
+	groups := make(map[string]*OutcomeStats)
+	for _, record := range records {
+		k := key(record)
+		if k == "" {
+			k = "unknown"
+		}
+		stats, ok := groups[k]
+		if !ok {
+			stats = &OutcomeStats{Key: k, Results: make(map[string]int)}
+			groups[k] = stats
+		}
+		stats.Total++
+		stats.Results[record.Result]++
+		if !record.CreatedAt.IsZero() && record.EndedAt.After(record.CreatedAt) {
+			stats.Duration += record.EndedAt.Sub(record.CreatedAt)
+		}
+	}
+
+	result := make([]*OutcomeStats, 0, len(groups))
+	for _, stats := range groups {
+		result = append(result, stats)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Total != result[j].Total {
+			return result[i].Total > result[j].Total
+		}
+		return result[i].Key < result[j].Key
+	})
+	return result
+}
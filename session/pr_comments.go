@@ -0,0 +1,72 @@
+package session
+
+import (
+	"claude-squad/session/git"
+	"time"
+)
+
+// UpdatePRComments fetches the comments on the branch's open pull request and counts the
+// unresolved ones posted since they were last reviewed. It shells out to gh, so call it off the
+// UI thread. It returns how many new comments arrived since the last update.
+func (i *Instance) UpdatePRComments() (int, error) {
+	if !i.started || i.Status == Paused {
+		return 0, nil
+	}
+	// Only open pull requests get comments worth polling for
+	status := i.GetPRStatus()
+	if status == nil || status.State != "OPEN" {
+		i.setNewComments(0)
+		return 0, nil
+	}
+
+	worktreePath := i.gitWorktree.GetWorktreePath()
+	pr, err := git.GetCurrentPR(worktreePath)
+	if err != nil {
+		return 0, err
+	}
+	if err := pr.FetchComments(worktreePath); err != nil {
+		return 0, err
+	}
+
+	reviewedAt := i.CommentsReviewedAt()
+	count := 0
+	for _, comment := range pr.Comments {
+		if comment.CreatedAt.After(reviewedAt) {
+			count++
+		}
+	}
+	return i.setNewComments(count), nil
+}
+
+// setNewComments sets the count of new comments and returns how much it grew.
+func (i *Instance) setNewComments(count int) int {
+	i.prStatusMu.Lock()
+	defer i.prStatusMu.Unlock()
+	arrived := count - i.newComments
+	i.newComments = count
+	return max(arrived, 0)
+}
+
+// NewCommentCount returns how many unresolved comments were posted on the branch's PR since
+// they were last reviewed, as of the last update.
+func (i *Instance) NewCommentCount() int {
+	i.prStatusMu.RLock()
+	defer i.prStatusMu.RUnlock()
+	return i.newComments
+}
+
+// CommentsReviewedAt returns when the comments on the branch's PR were last reviewed.
+func (i *Instance) CommentsReviewedAt() time.Time {
+	i.prStatusMu.RLock()
+	defer i.prStatusMu.RUnlock()
+	return i.commentsReviewedAt
+}
+
+// MarkCommentsReviewed records that the comments on the branch's PR were just reviewed, so only
+// later ones count as new.
+func (i *Instance) MarkCommentsReviewed() {
+	i.prStatusMu.Lock()
+	defer i.prStatusMu.Unlock()
+	i.commentsReviewedAt = time.Now()
+	i.newComments = 0
+}
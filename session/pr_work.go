@@ -0,0 +1,35 @@
+package session
+
+import (
+	"claude-squad/log"
+	"claude-squad/session/git"
+	"fmt"
+)
+
+// PRWorkPrompt composes a prompt asking the agent to resume work on the branch's pull request,
+// from its description, the issues it closes and its failing CI checks. It shells out to gh or
+// glab, so call it off the UI thread.
+func (i *Instance) PRWorkPrompt() (string, error) {
+	if !i.started || i.Status == Paused {
+		return "", fmt.Errorf("instance '%s' is not running", i.Title)
+	}
+	if i.Host != "" {
+		return "", fmt.Errorf("pull requests are not available for instances on remote hosts")
+	}
+
+	worktreePath := i.gitWorktree.GetWorktreePath()
+	pr, err := git.GetCurrentPR(worktreePath)
+	if err != nil {
+		return "", err
+	}
+	// The description alone is still worth a prompt, so missing issues and checks only warn
+	issues, err := pr.FetchLinkedIssues(worktreePath)
+	if err != nil {
+		log.WarningLog.Printf("instance %s: could not fetch the issues linked to PR #%d: %v", i.Title, pr.Number, err)
+	}
+	checks, err := i.LoadCIChecks()
+	if err != nil {
+		log.WarningLog.Printf("instance %s: could not fetch the CI checks of PR #%d: %v", i.Title, pr.Number, err)
+	}
+	return git.WorkPrompt(pr, issues, checks), nil
+}
@@ -0,0 +1,142 @@
+package session
+
+import (
+	"claude-squad/log"
+	"claude-squad/session/git"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// maxPromptRuns is how many of the prompts sent to an instance are kept in its history.
+const maxPromptRuns = 50
+
+// maxSummaryFiles is how many touched files a run summary lists by name.
+const maxSummaryFiles = 5
+
+// PromptRun is a prompt sent to the agent, with a summary of what the agent did once it finished.
+type PromptRun struct {
+	Prompt string    `json:"prompt"`
+	SentAt time.Time `json:"sent_at"`
+	// Summary is nil until the agent finished working on the prompt
+	Summary *RunSummary `json:"summary,omitempty"`
+}
+
+// RunSummary is what an agent's run on a prompt changed.
+type RunSummary struct {
+	FinishedAt time.Time `json:"finished_at"`
+	// Added and Removed are how much the lines added and removed in the branch's diff changed
+	Added   int `json:"added"`
+	Removed int `json:"removed"`
+	// Files are the files the run changed
+	Files []string `json:"files,omitempty"`
+	// TestsRun is true if tests ran during the run, FailedTestFiles counts the failed test files
+	// of the last of them
+	TestsRun        bool `json:"tests_run,omitempty"`
+	FailedTestFiles int  `json:"failed_test_files,omitempty"`
+}
+
+// String summarizes the run on one line, e.g. "2 files (+12 -3): app.go, app_test.go; tests
+// passed".
+func (s RunSummary) String() string {
+	var b strings.Builder
+	if len(s.Files) == 0 {
+		b.WriteString("no changes")
+	} else {
+		fmt.Fprintf(&b, "%d file(s) (%+d %+d): ", len(s.Files), s.Added, -s.Removed)
+		files := s.Files
+		if len(files) > maxSummaryFiles {
+			files = files[:maxSummaryFiles]
+		}
+		b.WriteString(strings.Join(files, ", "))
+		if more := len(s.Files) - len(files); more > 0 {
+			fmt.Fprintf(&b, " and %d more", more)
+		}
+	}
+	if s.TestsRun {
+		if s.FailedTestFiles > 0 {
+			fmt.Fprintf(&b, "; tests failed in %d file(s)", s.FailedTestFiles)
+		} else {
+			b.WriteString("; tests passed")
+		}
+	}
+	return b.String()
+}
+
+// startPromptRun records a prompt sent to the agent, and the state of the worktree to tell what
+// the run changes.
+func (i *Instance) startPromptRun(prompt string) {
+	var files git.FileStats
+	if i.gitWorktree != nil {
+		var err error
+		if files, err = i.gitWorktree.FileStats(); err != nil {
+			log.WarningLog.Printf("instance %s: could not record the worktree before the prompt: %v", i.Title, err)
+		}
+	}
+
+	i.runsMu.Lock()
+	defer i.runsMu.Unlock()
+	i.promptRuns = append(i.promptRuns, PromptRun{Prompt: prompt, SentAt: time.Now()})
+	if len(i.promptRuns) > maxPromptRuns {
+		i.promptRuns = i.promptRuns[len(i.promptRuns)-maxPromptRuns:]
+	}
+	i.runStart = files
+	i.runPending = true
+}
+
+// PendingPromptRun returns the last prompt sent to the agent if its run isn't summarized yet.
+func (i *Instance) PendingPromptRun() (PromptRun, bool) {
+	i.runsMu.Lock()
+	defer i.runsMu.Unlock()
+	if !i.runPending || len(i.promptRuns) == 0 {
+		return PromptRun{}, false
+	}
+	return i.promptRuns[len(i.promptRuns)-1], true
+}
+
+// FinishPromptRun summarizes what the run of the last prompt sent to the agent changed, once the
+// agent finished it, and attaches the summary to the prompt's history entry.
+func (i *Instance) FinishPromptRun() (*RunSummary, error) {
+	i.runsMu.Lock()
+	if !i.runPending || len(i.promptRuns) == 0 {
+		i.runsMu.Unlock()
+		return nil, fmt.Errorf("no prompt is running")
+	}
+	i.runPending = false
+	start := i.runStart
+	i.runStart = nil
+	sentAt := i.promptRuns[len(i.promptRuns)-1].SentAt
+	i.runsMu.Unlock()
+
+	files, err := i.gitWorktree.FileStats()
+	if err != nil {
+		return nil, err
+	}
+	summary := &RunSummary{FinishedAt: time.Now(), Files: files.TouchedSince(start)}
+	addedBefore, removedBefore := start.Lines()
+	addedAfter, removedAfter := files.Lines()
+	summary.Added, summary.Removed = addedAfter-addedBefore, removedAfter-removedBefore
+	if metrics := i.GetMetrics(); metrics.LastTestRunAt.After(sentAt) {
+		summary.TestsRun = true
+		summary.FailedTestFiles = metrics.LastFailedTestFiles
+	}
+
+	i.runsMu.Lock()
+	defer i.runsMu.Unlock()
+	// Another prompt may have been sent meanwhile, so find the run again
+	for n := len(i.promptRuns) - 1; n >= 0; n-- {
+		if i.promptRuns[n].SentAt.Equal(sentAt) {
+			i.promptRuns[n].Summary = summary
+			break
+		}
+	}
+	return summary, nil
+}
+
+// PromptRuns returns the prompts sent to the agent, oldest first, with the summaries of their
+// finished runs.
+func (i *Instance) PromptRuns() []PromptRun {
+	i.runsMu.Lock()
+	defer i.runsMu.Unlock()
+	return append([]PromptRun(nil), i.promptRuns...)
+}
@@ -0,0 +1,58 @@
+package session
+
+import (
+	"fmt"
+	"time"
+)
+
+// QueueDispatchIdle is how long an instance must have been Ready before its next queued prompt
+// is sent, so a short pause in the agent's output isn't mistaken for it having finished.
+const QueueDispatchIdle = 3 * time.Second
+
+// QueuedPrompts returns the prompts waiting to be sent, next first.
+func (i *Instance) QueuedPrompts() []string {
+	return i.PromptQueue
+}
+
+// EnqueuePrompt adds a prompt to the end of the instance's queue.
+func (i *Instance) EnqueuePrompt(prompt string) {
+	i.PromptQueue = append(i.PromptQueue, prompt)
+}
+
+// RemoveQueuedPrompt deletes the queued prompt at idx.
+func (i *Instance) RemoveQueuedPrompt(idx int) {
+	if idx < 0 || idx >= len(i.PromptQueue) {
+		return
+	}
+	i.PromptQueue = append(i.PromptQueue[:idx], i.PromptQueue[idx+1:]...)
+}
+
+// MoveQueuedPrompt swaps the queued prompt at idx with its neighbour delta places away and returns
+// the prompt's new index. Moves past either end of the queue are ignored.
+func (i *Instance) MoveQueuedPrompt(idx, delta int) int {
+	target := idx + delta
+	if idx < 0 || idx >= len(i.PromptQueue) || target < 0 || target >= len(i.PromptQueue) {
+		return idx
+	}
+	i.PromptQueue[idx], i.PromptQueue[target] = i.PromptQueue[target], i.PromptQueue[idx]
+	return target
+}
+
+// DispatchQueuedPrompt sends the next queued prompt if the instance has been Ready for at least
+// idle. It returns the prompt sent, or "" if nothing was due. A prompt that fails to send stays
+// at the front of the queue and is retried after another idle period.
+func (i *Instance) DispatchQueuedPrompt(idle time.Duration) (string, error) {
+	if len(i.PromptQueue) == 0 || i.Status != Ready || i.readySince.IsZero() || time.Since(i.readySince) < idle {
+		return "", nil
+	}
+
+	prompt := i.PromptQueue[0]
+	if err := i.SendPrompt(prompt); err != nil {
+		i.readySince = time.Now()
+		return "", fmt.Errorf("failed to send queued prompt to %s: %w", i.Title, err)
+	}
+	i.PromptQueue = i.PromptQueue[1:]
+	// The agent is working on the prompt now; wait for it to become Ready again
+	i.SetStatus(Running)
+	return prompt, nil
+}
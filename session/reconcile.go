@@ -0,0 +1,153 @@
+package session
+
+import (
+	"claude-squad/cmd"
+	"claude-squad/config"
+	"claude-squad/log"
+	"claude-squad/session/tmux"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// Suffixes of the files an instance's pane scrollback is saved to.
+const (
+	scrollbackAISuffix       = ".ai.txt"
+	scrollbackTerminalSuffix = ".terminal.txt"
+)
+
+// recoveredScrollbackMarker separates the scrollback saved before a tmux session was lost from
+// the recreated session's.
+const recoveredScrollbackMarker = "──── session recreated at %s; output above is from before ────\n"
+
+// scrollbackPath returns the path of the file the instance's scrollback is saved to with the
+// suffix.
+func (i *Instance) scrollbackPath(suffix string) (string, error) {
+	dir, err := config.GetConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "scrollback", archiveNameRe.ReplaceAllString(i.Title, "-")+suffix), nil
+}
+
+// capturePaneHistories returns the scrollback of the AI pane and, once it's split off, of the
+// terminal pane. The AI runs in pane 0 until the terminal pane is split off above it.
+func (i *Instance) capturePaneHistories() (ai, terminal string, err error) {
+	aiPane := 0
+	if counts, err := i.tmuxSession.PaneLineCounts(); err == nil && len(counts) > 1 {
+		aiPane = 1
+		if terminal, err = i.tmuxSession.CapturePaneHistory(0); err != nil {
+			return "", "", err
+		}
+	}
+	if ai, err = i.tmuxSession.CapturePaneHistory(aiPane); err != nil {
+		return "", "", err
+	}
+	return ai, terminal, nil
+}
+
+// SaveScrollback saves the scrollback of the instance's panes, so it can be recovered if the tmux
+// session is lost, e.g. to a reboot. It shells out to tmux, so call it off the UI thread.
+func (i *Instance) SaveScrollback() error {
+	if !i.started || i.Status == Paused || i.tmuxSession == nil || !i.tmuxSession.DoesSessionExist() {
+		return nil
+	}
+	ai, terminal, err := i.capturePaneHistories()
+	if err != nil {
+		return fmt.Errorf("failed to capture the scrollback of %s: %w", i.Title, err)
+	}
+	for suffix, content := range map[string]string{
+		scrollbackAISuffix:       i.recoveredAI + ai,
+		scrollbackTerminalSuffix: i.recoveredTerminal + terminal,
+	} {
+		path, err := i.scrollbackPath(suffix)
+		if err != nil {
+			return err
+		}
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return fmt.Errorf("failed to create the scrollback directory: %w", err)
+		}
+		// Write then rename so a crash mid-save keeps the previous scrollback
+		tmp := path + ".tmp"
+		if err := os.WriteFile(tmp, []byte(content), 0644); err != nil {
+			return fmt.Errorf("failed to save the scrollback of %s: %w", i.Title, err)
+		}
+		if err := os.Rename(tmp, path); err != nil {
+			return fmt.Errorf("failed to save the scrollback of %s: %w", i.Title, err)
+		}
+	}
+	return nil
+}
+
+// recoverScrollback loads the scrollback saved before the instance's tmux session was lost, to
+// show it above the recreated session's.
+func (i *Instance) recoverScrollback() {
+	marker := fmt.Sprintf(recoveredScrollbackMarker, time.Now().Format("2006-01-02 15:04"))
+	for suffix, recovered := range map[string]*string{
+		scrollbackAISuffix:       &i.recoveredAI,
+		scrollbackTerminalSuffix: &i.recoveredTerminal,
+	} {
+		path, err := i.scrollbackPath(suffix)
+		if err != nil {
+			continue
+		}
+		content, err := os.ReadFile(path)
+		if err != nil || len(content) == 0 {
+			continue
+		}
+		*recovered = string(content) + marker
+	}
+	if i.recoveredAI != "" || i.recoveredTerminal != "" {
+		log.InfoLog.Printf("recovered the saved scrollback of %s", i.Title)
+	}
+}
+
+// recreateTmuxSession starts the instance's lost tmux session again in its worktree, recovering
+// the scrollback saved before it was lost.
+func (i *Instance) recreateTmuxSession() error {
+	i.recoverScrollback()
+	if err := i.tmuxSession.Start(i.gitWorktree.GetWorktreePath()); err != nil {
+		return fmt.Errorf("failed to recreate tmux session: %w", err)
+	}
+	return nil
+}
+
+// removeScrollback deletes the saved scrollback once the instance's session is intentionally
+// stopped.
+func (i *Instance) removeScrollback() {
+	i.recoveredAI, i.recoveredTerminal = "", ""
+	for _, suffix := range []string{scrollbackAISuffix, scrollbackTerminalSuffix} {
+		if path, err := i.scrollbackPath(suffix); err == nil {
+			os.Remove(path)
+		}
+	}
+}
+
+// SessionLost returns true if the instance's tmux session was gone when it was loaded, so it is
+// recreated, with its saved scrollback, when next needed.
+func (i *Instance) SessionLost() bool {
+	return i.sessionLost
+}
+
+// OrphanedSessions returns the claude-squad tmux sessions on this machine that belong to none of
+// the instances, e.g. left behind by an instance deleted from storage by hand.
+func OrphanedSessions(instances []*Instance) ([]string, error) {
+	live, err := tmux.LiveSessions(cmd.MakeExecutor())
+	if err != nil {
+		return nil, err
+	}
+	known := make(map[string]bool, len(instances))
+	for _, instance := range instances {
+		if instance.tmuxSession != nil && instance.Host == "" {
+			known[instance.tmuxSession.GetSessionName()] = true
+		}
+	}
+	var orphans []string
+	for _, name := range live {
+		if !known[name] {
+			orphans = append(orphans, name)
+		}
+	}
+	return orphans, nil
+}
@@ -0,0 +1,56 @@
+package session
+
+import (
+	"claude-squad/config"
+	"claude-squad/session/git"
+	"claude-squad/session/tmux"
+	"fmt"
+	"path"
+)
+
+// remoteBackend returns the backend running commands on the instance's remote host, as
+// configured.
+func (i *Instance) remoteBackend() (*tmux.SSHBackend, config.RemoteHost, error) {
+	host, ok := i.settings().RemoteHosts[i.Host]
+	if !ok {
+		return nil, host, fmt.Errorf("remote host %s is not configured", i.Host)
+	}
+	if !path.IsAbs(host.RepoPath) {
+		return nil, host, fmt.Errorf("remote host %s needs the absolute repo_path of its clone", i.Host)
+	}
+	return &tmux.SSHBackend{Host: host.Host, User: host.User, KeyFile: host.Key(), Port: host.Port}, host, nil
+}
+
+// newTmuxSession creates the instance's tmux session, on its remote host if it has one. Start
+// sets how a local one runs the program.
+func (i *Instance) newTmuxSession() (*tmux.TmuxSession, error) {
+	if i.Host == "" {
+		return tmux.NewTmuxSession(i.Title, i.Program), nil
+	}
+	backend, _, err := i.remoteBackend()
+	if err != nil {
+		return nil, err
+	}
+	return tmux.NewTmuxSessionWithBackend(i.Title, i.Program, backend), nil
+}
+
+// newRemoteWorktree creates the worktree of a new instance in the clone on its remote host.
+func (i *Instance) newRemoteWorktree() (*git.GitWorktree, error) {
+	backend, host, err := i.remoteBackend()
+	if err != nil {
+		return nil, err
+	}
+	if i.existingBranch && i.Branch != "" {
+		return git.NewRemoteGitWorktreeForBranch(backend, i.Path, i.Title, i.Branch, host.Worktrees()), nil
+	}
+	worktree, branchName, err := git.NewRemoteGitWorktree(backend, i.Path, i.Title, git.WorktreeOptions{
+		Dir:          host.Worktrees(),
+		BranchPrefix: i.newBranchPrefix(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	worktree.SetBaseRef(i.baseBranch)
+	i.Branch = branchName
+	return worktree, nil
+}
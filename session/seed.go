@@ -0,0 +1,49 @@
+package session
+
+import (
+	"claude-squad/log"
+	"claude-squad/session/git"
+	"fmt"
+)
+
+// SeedMode is how a new instance takes the uncommitted changes of the checkout it's created
+// from, to hand work started by hand to an agent.
+type SeedMode int
+
+const (
+	// SeedNone starts the instance without the checkout's changes.
+	SeedNone SeedMode = iota
+	// SeedMove moves the changes into the new worktree, leaving the checkout clean.
+	SeedMove
+	// SeedCopy copies the changes into the new worktree, leaving the checkout as it is.
+	SeedCopy
+)
+
+// seedChanges brings the uncommitted changes of the checkout at Path into the new worktree. When
+// moving them fails to apply, they're left in the checkout's stash.
+func (i *Instance) seedChanges() error {
+	if i.seed == SeedNone {
+		return nil
+	}
+	message := fmt.Sprintf("claude-squad: changes handed to '%s'", i.Title)
+	stash, err := git.StashCheckoutChanges(i.Path, message, i.seed == SeedCopy)
+	if err != nil {
+		return err
+	}
+	if stash == "" {
+		log.InfoLog.Printf("%s had no changes to hand to %s", i.Path, i.Title)
+		return nil
+	}
+	if err := i.gitWorktree.ApplyStash(stash); err != nil {
+		if i.seed == SeedMove {
+			return fmt.Errorf("%w; they're kept in the stash of %s as %q", err, i.Path, message)
+		}
+		return err
+	}
+	if i.seed == SeedMove {
+		if err := git.DropStash(i.Path, stash); err != nil {
+			log.WarningLog.Printf("%v", err)
+		}
+	}
+	return nil
+}
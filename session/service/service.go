@@ -0,0 +1,48 @@
+package service
+
+import (
+	"fmt"
+	"net"
+	"strings"
+)
+
+// portAttempts is how many ports from a service's preferred one are tried.
+const portAttempts = 100
+
+// WindowPrefix starts the names of the tmux windows services run in.
+const WindowPrefix = "service-"
+
+// WindowName returns the name of the tmux window the service called name runs in.
+func WindowName(name string) string {
+	return WindowPrefix + strings.NewReplacer(".", "_", ":", "_", " ", "_").Replace(name)
+}
+
+// FreePort returns the first port from base that isn't in taken, the ports assigned to other
+// instances, and that nothing on this machine listens on.
+func FreePort(base int, taken map[int]bool) (int, error) {
+	if base <= 0 {
+		return 0, fmt.Errorf("invalid port %d", base)
+	}
+	for port := base; port < base+portAttempts && port <= 65535; port++ {
+		if taken[port] || !available(port) {
+			continue
+		}
+		return port, nil
+	}
+	return 0, fmt.Errorf("no free port in %d-%d", base, base+portAttempts-1)
+}
+
+// available returns true if port can be listened on.
+func available(port int) bool {
+	listener, err := net.Listen("tcp", fmt.Sprintf(":%d", port))
+	if err != nil {
+		return false
+	}
+	listener.Close()
+	return true
+}
+
+// Command returns the shell command running command with $PORT set to port.
+func Command(command string, port int) string {
+	return fmt.Sprintf("export PORT=%d; %s", port, command)
+}
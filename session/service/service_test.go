@@ -0,0 +1,46 @@
+package service
+
+import (
+	"net"
+	"testing"
+)
+
+func TestFreePort(t *testing.T) {
+	// Occupy a port, then ask for ports from it
+	listener, err := net.Listen("tcp", ":0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+	busy := listener.Addr().(*net.TCPAddr).Port
+
+	port, err := FreePort(busy, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if port <= busy {
+		t.Errorf("FreePort(%d) = %d, want a port after the busy one", busy, port)
+	}
+
+	taken := map[int]bool{port: true}
+	next, err := FreePort(busy, taken)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if next <= port {
+		t.Errorf("FreePort() = %d, want a port after the taken %d", next, port)
+	}
+
+	if _, err := FreePort(0, nil); err == nil {
+		t.Error("FreePort(0) succeeded")
+	}
+}
+
+func TestCommand(t *testing.T) {
+	if got := Command("npm run dev -- --port $PORT", 3001); got != "export PORT=3001; npm run dev -- --port $PORT" {
+		t.Errorf("Command() = %q", got)
+	}
+	if got := WindowName("web api.v2"); got != "service-web_api_v2" {
+		t.Errorf("WindowName() = %q", got)
+	}
+}
@@ -0,0 +1,155 @@
+package session
+
+import (
+	"claude-squad/config"
+	"claude-squad/log"
+	"claude-squad/session/service"
+	"fmt"
+	"path/filepath"
+)
+
+// Service is a dev server of the instance's repository, with the port assigned to the instance.
+type Service struct {
+	Name string `json:"name"`
+	Port int    `json:"port"`
+}
+
+// Service states reported by ServiceStates.
+const (
+	ServiceRunning = "running"
+	ServiceExited  = "exited"
+	ServiceStopped = "stopped"
+)
+
+// ServicePort returns the port assigned to the instance for the service called name, and zero if
+// it has never run.
+func (i *Instance) ServicePort(name string) int {
+	for _, svc := range i.Services {
+		if svc.Name == name {
+			return svc.Port
+		}
+	}
+	return 0
+}
+
+// StartService runs svc in its own window of the instance's tmux session, with $PORT set to the
+// port assigned to the instance for it. The first time, that's the first free port from the
+// service's preferred one that isn't in taken, the ports of other instances' services. It
+// returns the port.
+func (i *Instance) StartService(svc config.ServiceConfig, taken map[int]bool) (int, error) {
+	if !i.started || i.Status == Paused {
+		return 0, fmt.Errorf("only a running instance can start services")
+	}
+	port := i.ServicePort(svc.Name)
+	if port == 0 {
+		var err error
+		if port, err = service.FreePort(svc.Port, taken); err != nil {
+			return 0, fmt.Errorf("failed to assign a port to %s: %w", svc.Name, err)
+		}
+		i.Services = append(i.Services, Service{Name: svc.Name, Port: port})
+	}
+
+	window := service.WindowName(svc.Name)
+	// A service that exited is restarted in a new window
+	if windows, err := i.tmuxSession.Windows(); err == nil {
+		if _, ok := windows[window]; ok {
+			if err := i.tmuxSession.StopWindow(window); err != nil {
+				return 0, err
+			}
+		}
+	}
+	dir := i.gitWorktree.GetWorktreePath()
+	if svc.Dir != "" {
+		dir = filepath.Join(dir, svc.Dir)
+	}
+	if err := i.tmuxSession.StartWindow(window, dir, service.Command(svc.Command, port)); err != nil {
+		return 0, fmt.Errorf("failed to start %s: %w", svc.Name, err)
+	}
+	if err := i.tmuxSession.ForwardPort(port); err != nil {
+		log.WarningLog.Printf("service %s of %s runs but isn't reachable here: %v", svc.Name, i.Title, err)
+	}
+	if i.serviceStates == nil {
+		i.serviceStates = make(map[string]string)
+	}
+	i.serviceStates[svc.Name] = ServiceRunning
+	return port, nil
+}
+
+// StopService stops the service called name. Its port stays assigned to the instance.
+func (i *Instance) StopService(name string) error {
+	if err := i.tmuxSession.StopWindow(service.WindowName(name)); err != nil {
+		return err
+	}
+	delete(i.serviceStates, name)
+	if port := i.ServicePort(name); port != 0 {
+		if err := i.tmuxSession.CancelPortForward(port); err != nil {
+			log.WarningLog.Printf("failed to stop forwarding port %d of %s: %v", port, i.Title, err)
+		}
+	}
+	return nil
+}
+
+// ServiceStates returns the state of each of the instance's services that has a window, by
+// name: ServiceRunning or ServiceExited. Services without one are ServiceStopped.
+func (i *Instance) ServiceStates() (map[string]string, error) {
+	states := make(map[string]string)
+	if !i.started || i.Status == Paused || len(i.Services) == 0 {
+		return states, nil
+	}
+	windows, err := i.tmuxSession.Windows()
+	if err != nil {
+		return nil, err
+	}
+	for _, svc := range i.Services {
+		if running, ok := windows[service.WindowName(svc.Name)]; ok {
+			states[svc.Name] = ServiceExited
+			if running {
+				states[svc.Name] = ServiceRunning
+			}
+		}
+	}
+	return states, nil
+}
+
+// ServiceOutput returns the output of the service called name.
+func (i *Instance) ServiceOutput(name string) (string, error) {
+	return i.tmuxSession.CaptureWindow(service.WindowName(name))
+}
+
+// UpdateServiceStates refreshes the cached states of the instance's services shown in the list.
+func (i *Instance) UpdateServiceStates() {
+	if len(i.Services) == 0 {
+		return
+	}
+	states, err := i.ServiceStates()
+	if err != nil {
+		return
+	}
+	i.serviceStates = states
+}
+
+// RunningServicePorts returns the ports of the services last seen running, in the order they
+// were first started.
+func (i *Instance) RunningServicePorts() []int {
+	var ports []int
+	for _, svc := range i.Services {
+		if i.serviceStates[svc.Name] == ServiceRunning {
+			ports = append(ports, svc.Port)
+		}
+	}
+	return ports
+}
+
+// stopServices stops the instance's services, e.g. before its worktree is removed.
+func (i *Instance) stopServices() {
+	states, err := i.ServiceStates()
+	if err != nil {
+		return
+	}
+	for name := range states {
+		if err := i.StopService(name); err != nil {
+			log.WarningLog.Printf("failed to stop service %s of %s: %v", name, i.Title, err)
+		}
+	}
+	i.serviceStates = nil
+}
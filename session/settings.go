@@ -0,0 +1,43 @@
+package session
+
+import (
+	"strings"
+	"time"
+)
+
+// maxWorkSample caps the time one sample adds to WorkTime, so time the app wasn't running or
+// checking the instance isn't counted as work.
+const maxWorkSample = 5 * time.Second
+
+// RecordWorkTime adds the time since the previous call to WorkTime if the agent is working. It
+// returns true when this takes WorkTime past the time budget.
+func (i *Instance) RecordWorkTime(now time.Time) bool {
+	last := i.lastWorkSample
+	i.lastWorkSample = now
+	if i.Status != Running || last.IsZero() {
+		return false
+	}
+	before := i.WorkTime
+	i.WorkTime += min(now.Sub(last), maxWorkSample)
+	return i.TimeBudget > 0 && before < i.TimeBudget && i.WorkTime >= i.TimeBudget
+}
+
+// OverBudget returns true if the agent has worked longer than its time budget.
+func (i *Instance) OverBudget() bool {
+	return i.TimeBudget > 0 && i.WorkTime >= i.TimeBudget
+}
+
+// ParseTags splits comma-separated tags, dropping blanks and duplicates.
+func ParseTags(text string) []string {
+	var tags []string
+	seen := make(map[string]bool)
+	for _, tag := range strings.Split(text, ",") {
+		tag = strings.TrimSpace(tag)
+		if tag == "" || seen[tag] {
+			continue
+		}
+		seen[tag] = true
+		tags = append(tags, tag)
+	}
+	return tags
+}
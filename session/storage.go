@@ -2,15 +2,22 @@ package session
 
 import (
 	"claude-squad/config"
+	"crypto/sha256"
 	"encoding/json"
 	"fmt"
+	"io"
+	"path/filepath"
+	"sync"
 	"time"
 )
 
 // InstanceData represents the serializable data of an Instance
 type InstanceData struct {
+	ID        string    `json:"id,omitempty"`
 	Title     string    `json:"title"`
 	Path      string    `json:"path"`
+	Host      string    `json:"host,omitempty"`
+	Container string    `json:"container,omitempty"`
 	Branch    string    `json:"branch"`
 	Status    Status    `json:"status"`
 	Height    int       `json:"height"`
@@ -19,8 +26,25 @@ type InstanceData struct {
 	UpdatedAt time.Time `json:"updated_at"`
 	AutoYes   bool      `json:"auto_yes"`
 
-	Program  string          `json:"program"`
-	Worktree GitWorktreeData `json:"worktree"`
+	Program     string          `json:"program"`
+	Worktree    GitWorktreeData `json:"worktree"`
+	Checkpoints []Checkpoint    `json:"checkpoints,omitempty"`
+	PromptQueue []string        `json:"prompt_queue,omitempty"`
+
+	Tags              []string         `json:"tags,omitempty"`
+	Group             string           `json:"group,omitempty"`
+	Clipboard         []ClipboardEntry `json:"clipboard,omitempty"`
+	MuteNotifications bool             `json:"mute_notifications,omitempty"`
+	Coordinator       bool             `json:"coordinator,omitempty"`
+	Tab               string           `json:"tab,omitempty"`
+	TimeBudget        time.Duration    `json:"time_budget,omitempty"`
+	WorkTime          time.Duration    `json:"work_time,omitempty"`
+	Services          []Service        `json:"services,omitempty"`
+	Metrics           Metrics          `json:"metrics"`
+	// CommentsReviewedAt is when the comments on the branch's PR were last reviewed
+	CommentsReviewedAt time.Time `json:"comments_reviewed_at,omitempty"`
+	// PromptRuns are the prompts sent to the agent, with summaries of their runs
+	PromptRuns []PromptRun `json:"prompt_runs,omitempty"`
 }
 
 // GitWorktreeData represents the serializable data of a GitWorktree
@@ -30,11 +54,22 @@ type GitWorktreeData struct {
 	SessionName   string `json:"session_name"`
 	BranchName    string `json:"branch_name"`
 	BaseCommitSHA string `json:"base_commit_sha"`
+	// RequestedBranch is the branch the worktree was created for if BranchName is a suffixed
+	// copy of it, and PushBranch the remote branch pushes go to if it isn't BranchName
+	RequestedBranch string `json:"requested_branch,omitempty"`
+	PushBranch      string `json:"push_branch,omitempty"`
 }
 
 // Storage handles saving and loading instances using the state interface
 type Storage struct {
 	state config.InstanceStorage
+
+	// With storage other processes use too, seen are the titles of the instances this process
+	// held when it last loaded or saved, and revision is a hash of the instance data then, to
+	// tell the changes of other processes from its own
+	mu       sync.Mutex
+	seen     map[string]bool
+	revision [sha256.Size]byte
 }
 
 // NewStorage creates a new storage instance
@@ -44,6 +79,44 @@ func NewStorage(state config.InstanceStorage) (*Storage, error) {
 	}, nil
 }
 
+// OpenStorage opens the storage of the configured backend. The sqlite backend imports the
+// instances in state the first time it is used.
+func OpenStorage(backend string, state *config.State) (*Storage, error) {
+	switch backend {
+	case "", config.StorageBackendJSON:
+		return NewStorage(state)
+	case config.StorageBackendSQLite:
+		configDir, err := config.GetConfigDir()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get config directory: %w", err)
+		}
+		store, err := OpenSQLiteStore(filepath.Join(configDir, config.DatabaseFileName), state)
+		if err != nil {
+			return nil, err
+		}
+		return NewStorage(store)
+	}
+	return nil, fmt.Errorf("unknown storage backend %q, expected %s or %s", backend,
+		config.StorageBackendJSON, config.StorageBackendSQLite)
+}
+
+// History returns the killed instances matching query. Only the sqlite backend keeps them.
+func (s *Storage) History(query HistoryQuery) ([]HistoryEntry, error) {
+	store, ok := s.state.(*SQLiteStore)
+	if !ok {
+		return nil, fmt.Errorf("the history of killed instances needs the %q storage backend", config.StorageBackendSQLite)
+	}
+	return store.History(query)
+}
+
+// Close releases the storage's backend, if it holds on to anything.
+func (s *Storage) Close() error {
+	if closer, ok := s.state.(io.Closer); ok {
+		return closer.Close()
+	}
+	return nil
+}
+
 // SaveInstances saves the list of instances to disk
 func (s *Storage) SaveInstances(instances []*Instance) error {
 	// Convert instances to InstanceData
@@ -54,6 +127,10 @@ func (s *Storage) SaveInstances(instances []*Instance) error {
 		}
 	}
 
+	if shared, ok := s.state.(config.SharedStorage); ok {
+		return s.saveShared(shared, data)
+	}
+
 	// Marshal to JSON
 	jsonData, err := json.Marshal(data)
 	if err != nil {
@@ -65,16 +142,30 @@ func (s *Storage) SaveInstances(instances []*Instance) error {
 
 // LoadInstances loads the list of instances from disk
 func (s *Storage) LoadInstances() ([]*Instance, error) {
+	return s.loadInstances(nil, nil)
+}
+
+// loadInstances loads the list of instances from disk, configured by cfg and policy, or the
+// user's config and policy if nil.
+func (s *Storage) loadInstances(cfg *config.Config, policy *config.Policy) ([]*Instance, error) {
 	jsonData := s.state.GetInstances()
+	if shared, ok := s.state.(config.SharedStorage); ok {
+		// Other processes may have changed them since the state was read
+		var err error
+		if jsonData, err = shared.ReloadInstances(); err != nil {
+			return nil, fmt.Errorf("failed to reload instances: %w", err)
+		}
+	}
 
 	var instancesData []InstanceData
 	if err := json.Unmarshal(jsonData, &instancesData); err != nil {
 		return nil, fmt.Errorf("failed to unmarshal instances: %w", err)
 	}
+	s.observeLoaded(jsonData, instancesData)
 
 	instances := make([]*Instance, len(instancesData))
 	for i, data := range instancesData {
-		instance, err := FromInstanceData(data)
+		instance, err := fromInstanceData(data, cfg, policy)
 		if err != nil {
 			return nil, fmt.Errorf("failed to create instance %s: %w", data.Title, err)
 		}
@@ -86,27 +177,79 @@ func (s *Storage) LoadInstances() ([]*Instance, error) {
 
 // DeleteInstance removes an instance from storage
 func (s *Storage) DeleteInstance(title string) error {
-	instances, err := s.LoadInstances()
-	if err != nil {
-		return fmt.Errorf("failed to load instances: %w", err)
+	var deleted *InstanceData
+	remove := func(stored json.RawMessage) (json.RawMessage, error) {
+		var remaining json.RawMessage
+		var err error
+		remaining, deleted, err = removeInstance(stored, title)
+		return remaining, err
 	}
 
-	found := false
-	newInstances := make([]*Instance, 0)
-	for _, instance := range instances {
-		data := instance.ToInstanceData()
-		if data.Title != title {
-			newInstances = append(newInstances, instance)
-		} else {
-			found = true
+	if shared, ok := s.state.(config.SharedStorage); ok {
+		if err := s.updateShared(shared, remove); err != nil {
+			return err
+		}
+	} else {
+		remaining, err := remove(s.state.GetInstances())
+		if err != nil {
+			return err
+		}
+		if err := s.state.SaveInstances(remaining); err != nil {
+			return err
 		}
 	}
 
-	if !found {
-		return fmt.Errorf("instance not found: %s", title)
+	// Instances are deleted from storage before they are killed, while their branch still exists
+	recordKilled(newActivity(*deleted))
+	return nil
+}
+
+// removeInstance returns the instance data without the instance titled title, and that
+// instance's data.
+func removeInstance(stored json.RawMessage, title string) (json.RawMessage, *InstanceData, error) {
+	var instances []json.RawMessage
+	if err := json.Unmarshal(stored, &instances); err != nil {
+		return nil, nil, fmt.Errorf("failed to unmarshal instances: %w", err)
 	}
+	var deleted *InstanceData
+	remaining := make([]json.RawMessage, 0, len(instances))
+	for _, raw := range instances {
+		var data InstanceData
+		if err := json.Unmarshal(raw, &data); err != nil {
+			return nil, nil, fmt.Errorf("failed to unmarshal instance: %w", err)
+		}
+		if data.Title == title {
+			deleted = &data
+			continue
+		}
+		remaining = append(remaining, raw)
+	}
+	if deleted == nil {
+		return nil, nil, fmt.Errorf("instance not found: %s", title)
+	}
+	data, err := json.Marshal(remaining)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to marshal instances: %w", err)
+	}
+	return data, deleted, nil
+}
 
-	return s.SaveInstances(newInstances)
+// Activity returns the activity of the stored instances followed by that of the killed ones,
+// for the daily report.
+func (s *Storage) Activity() ([]Activity, error) {
+	var instancesData []InstanceData
+	if err := json.Unmarshal(s.state.GetInstances(), &instancesData); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal instances: %w", err)
+	}
+	activities := make([]Activity, 0, len(instancesData))
+	for _, data := range instancesData {
+		activities = append(activities, newActivity(data))
+	}
+	ended, err := loadEnded()
+	if err != nil {
+		return nil, err
+	}
+	return append(activities, ended...), nil
 }
 
 // UpdateInstance updates an existing instance in storage
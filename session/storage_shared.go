@@ -0,0 +1,230 @@
+package session
+
+import (
+	"bytes"
+	"claude-squad/config"
+	"claude-squad/log"
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"sort"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// mergeInstances merges the instances this process saves, ours, into the ones stored now. seen
+// are the instances this process held when it last loaded or saved, so an instance stored now
+// but not in ours was either removed here, if seen, or added by another process, which is kept.
+// Likewise an instance in ours that was seen but isn't stored anymore was removed by another
+// process and stays removed.
+func mergeInstances(stored json.RawMessage, ours []InstanceData, seen map[string]bool) (json.RawMessage, error) {
+	var storedInstances []json.RawMessage
+	if len(bytes.TrimSpace(stored)) > 0 {
+		if err := json.Unmarshal(stored, &storedInstances); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal stored instances: %w", err)
+		}
+	}
+	storedTitles := make(map[string]bool, len(storedInstances))
+	titles := make([]string, len(storedInstances))
+	for n, data := range storedInstances {
+		var instance storedInstance
+		if err := json.Unmarshal(data, &instance); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal stored instance: %w", err)
+		}
+		titles[n] = instance.Title
+		storedTitles[instance.Title] = true
+	}
+
+	merged := make([]json.RawMessage, 0, len(ours)+len(storedInstances))
+	held := make(map[string]bool, len(ours))
+	for _, data := range ours {
+		held[data.Title] = true
+		if seen[data.Title] && !storedTitles[data.Title] {
+			continue
+		}
+		instance, err := json.Marshal(data)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal instance %s: %w", data.Title, err)
+		}
+		merged = append(merged, instance)
+	}
+	for n, data := range storedInstances {
+		if !held[titles[n]] && !seen[titles[n]] {
+			merged = append(merged, data)
+		}
+	}
+	return json.Marshal(merged)
+}
+
+// instancesRevision hashes instance data, ignoring how it is formatted, to tell whether it
+// changed.
+func instancesRevision(data json.RawMessage) [sha256.Size]byte {
+	var compact bytes.Buffer
+	if err := json.Compact(&compact, data); err != nil {
+		return sha256.Sum256(data)
+	}
+	return sha256.Sum256(compact.Bytes())
+}
+
+// saveShared saves the instance data to storage other processes use too, merging it with
+// their changes since this process last loaded or saved.
+func (s *Storage) saveShared(shared config.SharedStorage, data []InstanceData) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var merged json.RawMessage
+	err := shared.UpdateInstances(func(stored json.RawMessage) (json.RawMessage, error) {
+		var err error
+		merged, err = mergeInstances(stored, data, s.seen)
+		return merged, err
+	})
+	if err != nil {
+		return err
+	}
+	s.seen = make(map[string]bool, len(data))
+	for _, instance := range data {
+		s.seen[instance.Title] = true
+	}
+	s.revision = instancesRevision(merged)
+	return nil
+}
+
+// updateShared applies update to the instance data of storage other processes use too. The
+// instances it removes are no longer held by this process.
+func (s *Storage) updateShared(shared config.SharedStorage, update func(stored json.RawMessage) (json.RawMessage, error)) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var updated json.RawMessage
+	err := shared.UpdateInstances(func(stored json.RawMessage) (json.RawMessage, error) {
+		var err error
+		updated, err = update(stored)
+		return updated, err
+	})
+	if err != nil {
+		return err
+	}
+	var instances []storedInstance
+	if err := json.Unmarshal(updated, &instances); err != nil {
+		return fmt.Errorf("failed to unmarshal instances: %w", err)
+	}
+	stored := make(map[string]bool, len(instances))
+	for _, instance := range instances {
+		stored[instance.Title] = true
+	}
+	for title := range s.seen {
+		if !stored[title] {
+			delete(s.seen, title)
+		}
+	}
+	s.revision = instancesRevision(updated)
+	return nil
+}
+
+// observeLoaded records the instances this process loaded, as held by it.
+func (s *Storage) observeLoaded(data json.RawMessage, instances []InstanceData) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.seen = make(map[string]bool, len(instances))
+	for _, instance := range instances {
+		s.seen[instance.Title] = true
+	}
+	s.revision = instancesRevision(data)
+}
+
+// Changed returns true if another process changed the stored instances since this one last
+// loaded or saved them. Storage only this process uses never changes.
+func (s *Storage) Changed() (bool, error) {
+	shared, ok := s.state.(config.SharedStorage)
+	if !ok {
+		return false, nil
+	}
+	data, err := shared.ReloadInstances()
+	if err != nil {
+		return false, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return instancesRevision(data) != s.revision, nil
+}
+
+// ReloadInstanceData returns the instance data stored now, with the changes of other
+// processes, for a process to bring the instances it holds up to date, and the titles of the
+// instances it held that other processes removed. Those are no longer considered held; the
+// instances other processes added are once they are saved.
+func (s *Storage) ReloadInstanceData() (instances []InstanceData, removed []string, err error) {
+	data := s.state.GetInstances()
+	if shared, ok := s.state.(config.SharedStorage); ok {
+		if data, err = shared.ReloadInstances(); err != nil {
+			return nil, nil, err
+		}
+	}
+	if err := json.Unmarshal(data, &instances); err != nil {
+		return nil, nil, fmt.Errorf("failed to unmarshal instances: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	stored := make(map[string]bool, len(instances))
+	for _, instance := range instances {
+		stored[instance.Title] = true
+	}
+	for title := range s.seen {
+		if !stored[title] {
+			delete(s.seen, title)
+			removed = append(removed, title)
+		}
+	}
+	sort.Strings(removed)
+	s.revision = instancesRevision(data)
+	return instances, removed, nil
+}
+
+// Watch returns a channel that receives when the file of the stored instances changes, which
+// may be this process's own saves, until ctx is done. It returns a nil channel for storage
+// only this process uses.
+func (s *Storage) Watch(ctx context.Context) (<-chan struct{}, error) {
+	shared, ok := s.state.(config.SharedStorage)
+	if !ok || shared.WatchPath() == "" {
+		return nil, nil
+	}
+	path := filepath.Clean(shared.WatchPath())
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, fmt.Errorf("failed to watch storage: %w", err)
+	}
+	// Files are replaced by renaming over them, so watch the directory
+	if err := watcher.Add(filepath.Dir(path)); err != nil {
+		watcher.Close()
+		return nil, fmt.Errorf("failed to watch %s: %w", filepath.Dir(path), err)
+	}
+
+	changes := make(chan struct{}, 1)
+	go func() {
+		defer watcher.Close()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case event, ok := <-watcher.Events:
+				if !ok {
+					return
+				}
+				if filepath.Clean(event.Name) != path || !event.Has(fsnotify.Write|fsnotify.Create|fsnotify.Rename) {
+					continue
+				}
+				// Changes that come faster than they are handled are coalesced
+				select {
+				case changes <- struct{}{}:
+				default:
+				}
+			case err, ok := <-watcher.Errors:
+				if !ok {
+					return
+				}
+				log.WarningLog.Printf("storage watch error: %v", err)
+			}
+		}
+	}()
+	return changes, nil
+}
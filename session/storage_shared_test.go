@@ -0,0 +1,90 @@
+package session
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMergeInstances(t *testing.T) {
+	instance := func(title, program string) InstanceData {
+		return InstanceData{Title: title, Program: program}
+	}
+	tests := []struct {
+		name   string
+		stored []InstanceData
+		ours   []InstanceData
+		seen   []string
+		// want lists the merged instances as title=program
+		want []string
+	}{
+		{
+			name: "nothing stored yet",
+			ours: []InstanceData{instance("a", "claude")},
+			want: []string{"a=claude"},
+		},
+		{
+			name:   "removed here",
+			stored: []InstanceData{instance("a", "claude"), instance("b", "claude")},
+			ours:   []InstanceData{instance("a", "claude")},
+			seen:   []string{"a", "b"},
+			want:   []string{"a=claude"},
+		},
+		{
+			name:   "added elsewhere",
+			stored: []InstanceData{instance("a", "claude"), instance("c", "aider")},
+			ours:   []InstanceData{instance("a", "claude")},
+			seen:   []string{"a"},
+			want:   []string{"a=claude", "c=aider"},
+		},
+		{
+			name:   "removed elsewhere",
+			stored: []InstanceData{instance("b", "claude")},
+			ours:   []InstanceData{instance("a", "claude"), instance("b", "claude")},
+			seen:   []string{"a", "b"},
+			want:   []string{"b=claude"},
+		},
+		{
+			name:   "held by both",
+			stored: []InstanceData{instance("a", "claude")},
+			ours:   []InstanceData{instance("a", "aider")},
+			seen:   []string{"a"},
+			want:   []string{"a=aider"},
+		},
+		{
+			name:   "added here and elsewhere",
+			stored: []InstanceData{instance("c", "aider")},
+			ours:   []InstanceData{instance("a", "claude")},
+			want:   []string{"a=claude", "c=aider"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stored json.RawMessage
+			if tt.stored != nil {
+				var err error
+				stored, err = json.Marshal(tt.stored)
+				require.NoError(t, err)
+			}
+			seen := make(map[string]bool, len(tt.seen))
+			for _, title := range tt.seen {
+				seen[title] = true
+			}
+
+			merged, err := mergeInstances(stored, tt.ours, seen)
+			require.NoError(t, err)
+			var instances []storedInstance
+			require.NoError(t, json.Unmarshal(merged, &instances))
+			got := make([]string, 0, len(instances))
+			for _, instance := range instances {
+				got = append(got, instance.Title+"="+instance.Program)
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+
+	_, err := mergeInstances(json.RawMessage("{"), nil, nil)
+	assert.Error(t, err, "unreadable stored instances aren't overwritten")
+}
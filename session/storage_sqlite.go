@@ -0,0 +1,411 @@
+package session
+
+import (
+	"bytes"
+	"claude-squad/config"
+	"claude-squad/log"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	_ "modernc.org/sqlite"
+)
+
+// sqliteSchema creates the tables of a SQLite store. Instances are kept a row each, keyed on
+// their ID, so a save only writes the instances that changed, and killed instances move to
+// history.
+const sqliteSchema = `
+CREATE TABLE IF NOT EXISTS instances (
+	key        TEXT PRIMARY KEY,
+	position   INTEGER NOT NULL,
+	data       TEXT NOT NULL,
+	updated_at INTEGER NOT NULL
+);
+CREATE TABLE IF NOT EXISTS history (
+	id         INTEGER PRIMARY KEY AUTOINCREMENT,
+	title      TEXT NOT NULL,
+	program    TEXT NOT NULL,
+	branch     TEXT NOT NULL,
+	created_at INTEGER NOT NULL,
+	ended_at   INTEGER NOT NULL,
+	data       TEXT NOT NULL
+);
+CREATE INDEX IF NOT EXISTS history_ended_at ON history (ended_at);
+CREATE TABLE IF NOT EXISTS meta (
+	key   TEXT PRIMARY KEY,
+	value TEXT NOT NULL
+);`
+
+// Keys of the meta table.
+const (
+	metaMigrated = "migrated"
+	metaGroups   = "groups"
+)
+
+// SQLiteStore keeps instances in a SQLite database. It implements config.InstanceStorage and
+// config.GroupStorage, writes only the instances that changed and keeps the instances that
+// were removed as history that can be queried.
+type SQLiteStore struct {
+	db   *sql.DB
+	path string
+}
+
+// OpenSQLiteStore opens the database at path, creating it if needed. The first time, the
+// instances and groups of legacy, if given, are imported; legacy itself is left as it was.
+func OpenSQLiteStore(path string, legacy config.InstanceStorage) (*SQLiteStore, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, fmt.Errorf("failed to create directory for %s: %w", path, err)
+	}
+	// The TUI and the daemon share the database, so wait on each other's writes, and take the
+	// write lock when a save starts, since it reads what it updates. The default rollback
+	// journal keeps committed data in the one file, which backups copy.
+	db, err := sql.Open("sqlite", "file:"+path+"?_pragma=busy_timeout(5000)&_txlock=immediate")
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %s: %w", path, err)
+	}
+	db.SetMaxOpenConns(1)
+	if _, err := db.Exec(sqliteSchema); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to create tables in %s: %w", path, err)
+	}
+	// Databases created before instances had IDs key their rows on the title column
+	var titleKeyed int
+	if err := db.QueryRow(`SELECT COUNT(*) FROM pragma_table_info('instances') WHERE name = 'title'`).Scan(&titleKeyed); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to read the tables of %s: %w", path, err)
+	}
+	if titleKeyed > 0 {
+		if _, err := db.Exec(`ALTER TABLE instances RENAME COLUMN title TO key`); err != nil {
+			db.Close()
+			return nil, fmt.Errorf("failed to update the tables of %s: %w", path, err)
+		}
+	}
+
+	s := &SQLiteStore{db: db, path: path}
+	if err := s.migrate(legacy); err != nil {
+		db.Close()
+		return nil, err
+	}
+	return s, nil
+}
+
+// migrate imports the instances and groups of legacy unless the database was migrated before.
+func (s *SQLiteStore) migrate(legacy config.InstanceStorage) error {
+	var migrated string
+	err := s.db.QueryRow(`SELECT value FROM meta WHERE key = ?`, metaMigrated).Scan(&migrated)
+	if err == nil {
+		return nil
+	} else if err != sql.ErrNoRows {
+		return fmt.Errorf("failed to read %s: %w", s.path, err)
+	}
+
+	if legacy != nil {
+		if data := legacy.GetInstances(); len(bytes.TrimSpace(data)) > 0 {
+			if err := s.SaveInstances(data); err != nil {
+				return fmt.Errorf("failed to import instances: %w", err)
+			}
+		}
+		if groupState, ok := legacy.(config.GroupStorage); ok {
+			if data := groupState.GetGroups(); len(data) > 0 {
+				if err := s.SaveGroups(data); err != nil {
+					return fmt.Errorf("failed to import groups: %w", err)
+				}
+			}
+		}
+	}
+	return s.setMeta(metaMigrated, time.Now().Format(time.RFC3339))
+}
+
+// storedInstance is the part of an instance's data the store indexes.
+type storedInstance struct {
+	ID        string    `json:"id"`
+	Title     string    `json:"title"`
+	Program   string    `json:"program"`
+	Branch    string    `json:"branch"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// key returns the key of the instance's row: its ID, or its title if it was saved before
+// instances had IDs.
+func (i storedInstance) key() string {
+	if i.ID == "" {
+		return i.Title
+	}
+	return i.ID
+}
+
+// instanceRow is an instance as stored in its row.
+type instanceRow struct {
+	position int
+	data     string
+}
+
+// SaveInstances stores the instance data, writing only the instances that changed or moved.
+// Instances that are no longer in the data are moved to history.
+func (s *SQLiteStore) SaveInstances(instancesJSON json.RawMessage) error {
+	return s.UpdateInstances(func(json.RawMessage) (json.RawMessage, error) {
+		return instancesJSON, nil
+	})
+}
+
+// UpdateInstances stores what update returns for the instance data stored now, in one
+// transaction, writing only the instances that changed or moved like SaveInstances.
+func (s *SQLiteStore) UpdateInstances(update func(stored json.RawMessage) (json.RawMessage, error)) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	stored := make(map[string]instanceRow)
+	rows, err := tx.Query(`SELECT key, position, data FROM instances ORDER BY position`)
+	if err != nil {
+		return fmt.Errorf("failed to read instances: %w", err)
+	}
+	var current []string
+	for rows.Next() {
+		var key string
+		var r instanceRow
+		if err := rows.Scan(&key, &r.position, &r.data); err != nil {
+			rows.Close()
+			return fmt.Errorf("failed to read instances: %w", err)
+		}
+		stored[key] = r
+		current = append(current, r.data)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to read instances: %w", err)
+	}
+
+	instancesJSON, err := update(json.RawMessage("[" + strings.Join(current, ",") + "]"))
+	if err != nil {
+		return err
+	}
+	var instances []json.RawMessage
+	if err := json.Unmarshal(instancesJSON, &instances); err != nil {
+		return fmt.Errorf("failed to unmarshal instances: %w", err)
+	}
+
+	now := time.Now()
+	kept := make(map[string]bool, len(instances))
+	for position, data := range instances {
+		var instance storedInstance
+		if err := json.Unmarshal(data, &instance); err != nil {
+			return fmt.Errorf("failed to unmarshal instance: %w", err)
+		}
+		key := instance.key()
+		if _, ok := stored[key]; !ok && key != instance.Title {
+			if err := rekeyInstance(tx, stored, instance.Title, key); err != nil {
+				return err
+			}
+		}
+		kept[key] = true
+		if r, ok := stored[key]; ok && r.position == position && r.data == string(data) {
+			continue
+		}
+		if _, err := tx.Exec(`INSERT INTO instances (key, position, data, updated_at) VALUES (?, ?, ?, ?)
+			ON CONFLICT (key) DO UPDATE SET position = excluded.position, data = excluded.data, updated_at = excluded.updated_at`,
+			key, position, string(data), now.Unix()); err != nil {
+			return fmt.Errorf("failed to save instance %s: %w", instance.Title, err)
+		}
+	}
+	for key, r := range stored {
+		if kept[key] {
+			continue
+		}
+		if err := endInstance(tx, key, r.data, now); err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit instances: %w", err)
+	}
+	return nil
+}
+
+// rekeyInstance moves the row of an instance saved before it had an ID, keyed on its title, to
+// its ID, so the first save with the ID updates the row rather than ending the instance.
+func rekeyInstance(tx *sql.Tx, stored map[string]instanceRow, title, key string) error {
+	r, ok := stored[title]
+	if !ok {
+		return nil
+	}
+	var instance storedInstance
+	if err := json.Unmarshal([]byte(r.data), &instance); err != nil || instance.ID != "" {
+		return nil
+	}
+	if _, err := tx.Exec(`UPDATE instances SET key = ? WHERE key = ?`, key, title); err != nil {
+		return fmt.Errorf("failed to save instance %s: %w", title, err)
+	}
+	stored[key] = r
+	delete(stored, title)
+	return nil
+}
+
+// endInstance moves the instance with the row key that was removed from the instances to history.
+func endInstance(tx *sql.Tx, key, data string, now time.Time) error {
+	var instance storedInstance
+	if err := json.Unmarshal([]byte(data), &instance); err != nil {
+		return fmt.Errorf("failed to unmarshal instance %s: %w", key, err)
+	}
+	if _, err := tx.Exec(`INSERT INTO history (title, program, branch, created_at, ended_at, data) VALUES (?, ?, ?, ?, ?, ?)`,
+		instance.Title, instance.Program, instance.Branch, instance.CreatedAt.Unix(), now.Unix(), data); err != nil {
+		return fmt.Errorf("failed to add %s to history: %w", instance.Title, err)
+	}
+	if _, err := tx.Exec(`DELETE FROM instances WHERE key = ?`, key); err != nil {
+		return fmt.Errorf("failed to delete instance %s: %w", instance.Title, err)
+	}
+	return nil
+}
+
+// GetInstances returns the stored instance data in order, or an empty list if it can't be read.
+func (s *SQLiteStore) GetInstances() json.RawMessage {
+	instances, err := s.ReloadInstances()
+	if err != nil {
+		log.ErrorLog.Printf("failed to read instances from %s: %v", s.path, err)
+		return json.RawMessage("[]")
+	}
+	return instances
+}
+
+// ReloadInstances returns the stored instance data in order.
+func (s *SQLiteStore) ReloadInstances() (json.RawMessage, error) {
+	rows, err := s.db.Query(`SELECT data FROM instances ORDER BY position`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read instances: %w", err)
+	}
+	defer rows.Close()
+
+	var instances []string
+	for rows.Next() {
+		var data string
+		if err := rows.Scan(&data); err != nil {
+			return nil, fmt.Errorf("failed to read instances: %w", err)
+		}
+		instances = append(instances, data)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read instances: %w", err)
+	}
+	return json.RawMessage("[" + strings.Join(instances, ",") + "]"), nil
+}
+
+// WatchPath returns the path of the database.
+func (s *SQLiteStore) WatchPath() string {
+	return s.path
+}
+
+// DeleteAllInstances moves all instances to history.
+func (s *SQLiteStore) DeleteAllInstances() error {
+	return s.SaveInstances(json.RawMessage("[]"))
+}
+
+// SaveGroups saves the raw group data
+func (s *SQLiteStore) SaveGroups(groupsJSON json.RawMessage) error {
+	return s.setMeta(metaGroups, string(groupsJSON))
+}
+
+// GetGroups returns the raw group data, or nothing if there are no groups.
+func (s *SQLiteStore) GetGroups() json.RawMessage {
+	var groups string
+	err := s.db.QueryRow(`SELECT value FROM meta WHERE key = ?`, metaGroups).Scan(&groups)
+	if err != nil {
+		if err != sql.ErrNoRows {
+			log.ErrorLog.Printf("failed to read groups from %s: %v", s.path, err)
+		}
+		return nil
+	}
+	return json.RawMessage(groups)
+}
+
+func (s *SQLiteStore) setMeta(key, value string) error {
+	if _, err := s.db.Exec(`INSERT INTO meta (key, value) VALUES (?, ?)
+		ON CONFLICT (key) DO UPDATE SET value = excluded.value`, key, value); err != nil {
+		return fmt.Errorf("failed to save %s: %w", key, err)
+	}
+	return nil
+}
+
+// HistoryEntry is an instance that was killed.
+type HistoryEntry struct {
+	Title     string    `json:"title"`
+	Program   string    `json:"program"`
+	Branch    string    `json:"branch"`
+	CreatedAt time.Time `json:"created_at"`
+	EndedAt   time.Time `json:"ended_at"`
+	// Data is the instance's data as it was last saved
+	Data InstanceData `json:"data"`
+}
+
+// HistoryQuery selects the history entries to return. Zero fields match everything.
+type HistoryQuery struct {
+	// Search matches titles and branches containing it, ignoring case
+	Search string
+	// Program matches the instances running a program containing it
+	Program string
+	// Since leaves out the instances that ended before it
+	Since time.Time
+	// Limit is the most entries to return
+	Limit int
+}
+
+// History returns the killed instances matching query, the last ended first.
+func (s *SQLiteStore) History(query HistoryQuery) ([]HistoryEntry, error) {
+	where := []string{"1 = 1"}
+	var args []any
+	if query.Search != "" {
+		where = append(where, "(instr(lower(title), lower(?)) > 0 OR instr(lower(branch), lower(?)) > 0)")
+		args = append(args, query.Search, query.Search)
+	}
+	if query.Program != "" {
+		where = append(where, "instr(lower(program), lower(?)) > 0")
+		args = append(args, query.Program)
+	}
+	if !query.Since.IsZero() {
+		where = append(where, "ended_at >= ?")
+		args = append(args, query.Since.Unix())
+	}
+	statement := `SELECT title, program, branch, created_at, ended_at, data FROM history WHERE ` +
+		strings.Join(where, " AND ") + ` ORDER BY ended_at DESC, id DESC`
+	if query.Limit > 0 {
+		statement += ` LIMIT ?`
+		args = append(args, query.Limit)
+	}
+
+	rows, err := s.db.Query(statement, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query history: %w", err)
+	}
+	defer rows.Close()
+
+	var entries []HistoryEntry
+	for rows.Next() {
+		var entry HistoryEntry
+		var created, ended int64
+		var data string
+		if err := rows.Scan(&entry.Title, &entry.Program, &entry.Branch, &created, &ended, &data); err != nil {
+			return nil, fmt.Errorf("failed to read history: %w", err)
+		}
+		entry.CreatedAt = time.Unix(created, 0)
+		entry.EndedAt = time.Unix(ended, 0)
+		if err := json.Unmarshal([]byte(data), &entry.Data); err != nil {
+			log.WarningLog.Printf("failed to unmarshal the history of %s: %v", entry.Title, err)
+		}
+		entries = append(entries, entry)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read history: %w", err)
+	}
+	return entries, nil
+}
+
+// Close closes the database.
+func (s *SQLiteStore) Close() error {
+	return s.db.Close()
+}
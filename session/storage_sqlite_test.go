@@ -0,0 +1,169 @@
+package session
+
+import (
+	"claude-squad/config"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// openTestSQLiteStore opens a store in a temporary directory, importing legacy if given.
+func openTestSQLiteStore(t *testing.T, legacy config.InstanceStorage) *SQLiteStore {
+	t.Helper()
+	store, err := OpenSQLiteStore(filepath.Join(t.TempDir(), "instances.db"), legacy)
+	require.NoError(t, err)
+	t.Cleanup(func() { store.Close() })
+	return store
+}
+
+// saveTestInstances saves the instances to the store in order.
+func saveTestInstances(t *testing.T, store *SQLiteStore, instances ...InstanceData) {
+	t.Helper()
+	data, err := json.Marshal(instances)
+	require.NoError(t, err)
+	require.NoError(t, store.SaveInstances(data))
+}
+
+// storedTitles returns the titles of the stored instances in order.
+func storedTitles(t *testing.T, store *SQLiteStore) []string {
+	t.Helper()
+	var instances []InstanceData
+	require.NoError(t, json.Unmarshal(store.GetInstances(), &instances))
+	titles := make([]string, 0, len(instances))
+	for _, instance := range instances {
+		titles = append(titles, instance.Title)
+	}
+	return titles
+}
+
+func TestSQLiteStoreSaveInstances(t *testing.T) {
+	store := openTestSQLiteStore(t, nil)
+	a := InstanceData{ID: "a1", Title: "a", Program: "claude"}
+	b := InstanceData{ID: "b1", Title: "b", Program: "aider"}
+	saveTestInstances(t, store, a, b)
+	assert.Equal(t, []string{"a", "b"}, storedTitles(t, store))
+
+	// Rows that didn't change or move aren't written again
+	_, err := store.db.Exec(`UPDATE instances SET updated_at = 0`)
+	require.NoError(t, err)
+	b.Program = "claude"
+	saveTestInstances(t, store, a, b)
+	updated := make(map[string]int64)
+	rows, err := store.db.Query(`SELECT key, updated_at FROM instances`)
+	require.NoError(t, err)
+	for rows.Next() {
+		var key string
+		var at int64
+		require.NoError(t, rows.Scan(&key, &at))
+		updated[key] = at
+	}
+	require.NoError(t, rows.Err())
+	rows.Close()
+	assert.Zero(t, updated["a1"], "an unchanged instance was written")
+	assert.NotZero(t, updated["b1"], "a changed instance wasn't written")
+
+	// Renaming an instance updates its row rather than ending it
+	a.Title = "renamed"
+	saveTestInstances(t, store, b, a)
+	assert.Equal(t, []string{"b", "renamed"}, storedTitles(t, store))
+	history, err := store.History(HistoryQuery{})
+	require.NoError(t, err)
+	assert.Empty(t, history)
+}
+
+func TestSQLiteStoreHistory(t *testing.T) {
+	store := openTestSQLiteStore(t, nil)
+	created := time.Now().Add(-time.Hour).Truncate(time.Second)
+	saveTestInstances(t, store,
+		InstanceData{ID: "1", Title: "fix-login", Program: "claude", Branch: "cs/fix-login", CreatedAt: created},
+		InstanceData{ID: "2", Title: "docs", Program: "aider --model x", Branch: "cs/readme", CreatedAt: created},
+		InstanceData{ID: "3", Title: "kept", Program: "claude"},
+	)
+	// Removed instances move to history, the last removed first
+	saveTestInstances(t, store,
+		InstanceData{ID: "2", Title: "docs", Program: "aider --model x", Branch: "cs/readme", CreatedAt: created},
+		InstanceData{ID: "3", Title: "kept", Program: "claude"},
+	)
+	saveTestInstances(t, store, InstanceData{ID: "3", Title: "kept", Program: "claude"})
+	assert.Equal(t, []string{"kept"}, storedTitles(t, store))
+
+	titles := func(query HistoryQuery) []string {
+		t.Helper()
+		entries, err := store.History(query)
+		require.NoError(t, err)
+		titles := make([]string, 0, len(entries))
+		for _, entry := range entries {
+			titles = append(titles, entry.Title)
+		}
+		return titles
+	}
+	assert.Equal(t, []string{"docs", "fix-login"}, titles(HistoryQuery{}))
+	assert.Equal(t, []string{"docs"}, titles(HistoryQuery{Limit: 1}))
+	assert.Equal(t, []string{"fix-login"}, titles(HistoryQuery{Search: "LOGIN"}))
+	assert.Equal(t, []string{"docs"}, titles(HistoryQuery{Search: "readme"}), "branches are searched too")
+	assert.Equal(t, []string{"docs"}, titles(HistoryQuery{Program: "aider"}))
+	assert.Empty(t, titles(HistoryQuery{Since: time.Now().Add(time.Hour)}))
+
+	entries, err := store.History(HistoryQuery{Search: "fix-login"})
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, "cs/fix-login", entries[0].Branch)
+	assert.True(t, entries[0].CreatedAt.Equal(created))
+	assert.Equal(t, "claude", entries[0].Data.Program, "the instance's last saved data is kept")
+}
+
+func TestSQLiteStoreMigration(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	configDir, err := config.GetConfigDir()
+	require.NoError(t, err)
+	require.NoError(t, os.MkdirAll(configDir, 0755))
+	legacy := `{"instances": [{"title": "old", "program": "claude"}], "groups": [{"name": "backend"}]}`
+	require.NoError(t, os.WriteFile(filepath.Join(configDir, config.StateFileName), []byte(legacy), 0644))
+
+	path := filepath.Join(t.TempDir(), "instances.db")
+	store, err := OpenSQLiteStore(path, config.LoadState())
+	require.NoError(t, err)
+	assert.Equal(t, []string{"old"}, storedTitles(t, store))
+	assert.JSONEq(t, `[{"name": "backend"}]`, string(store.GetGroups()))
+
+	// The instance saved without an ID keeps its row once it gets one
+	saveTestInstances(t, store, InstanceData{ID: "new-id", Title: "old", Program: "claude"})
+	var key string
+	require.NoError(t, store.db.QueryRow(`SELECT key FROM instances`).Scan(&key))
+	assert.Equal(t, "new-id", key)
+	history, err := store.History(HistoryQuery{})
+	require.NoError(t, err)
+	assert.Empty(t, history)
+
+	// Instances are imported only once, so ones removed since don't come back
+	saveTestInstances(t, store)
+	require.NoError(t, store.Close())
+	reopened, err := OpenSQLiteStore(path, config.LoadState())
+	require.NoError(t, err)
+	defer reopened.Close()
+	assert.Empty(t, storedTitles(t, reopened))
+}
+
+func TestSQLiteStoreTitleKeyedDatabase(t *testing.T) {
+	// Databases created before instances had IDs are keyed on the title column
+	path := filepath.Join(t.TempDir(), "instances.db")
+	store, err := OpenSQLiteStore(path, nil)
+	require.NoError(t, err)
+	_, err = store.db.Exec(`DROP TABLE instances;
+		CREATE TABLE instances (title TEXT PRIMARY KEY, position INTEGER NOT NULL, data TEXT NOT NULL, updated_at INTEGER NOT NULL);
+		INSERT INTO instances VALUES ('old', 0, '{"title":"old","program":"claude"}', 0)`)
+	require.NoError(t, err)
+	require.NoError(t, store.Close())
+
+	store, err = OpenSQLiteStore(path, nil)
+	require.NoError(t, err)
+	defer store.Close()
+	assert.Equal(t, []string{"old"}, storedTitles(t, store))
+	saveTestInstances(t, store, InstanceData{ID: "id", Title: "old", Program: "claude"})
+	assert.Equal(t, []string{"old"}, storedTitles(t, store))
+}
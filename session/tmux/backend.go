@@ -0,0 +1,131 @@
+package tmux
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Backend runs the commands driving a tmux server, on this machine or on a remote one.
+type Backend interface {
+	// Command returns a command running name with args.
+	Command(name string, args ...string) *exec.Cmd
+	// TerminalCommand is like Command, for commands run in a PTY such as attaching to a session.
+	TerminalCommand(name string, args ...string) *exec.Cmd
+}
+
+// PortForwarder is a Backend on another machine that can make its ports reachable on this one.
+type PortForwarder interface {
+	// ForwardPort forwards port on this machine to the same port on the other one.
+	ForwardPort(port int) error
+	// CancelPortForward stops forwarding port.
+	CancelPortForward(port int) error
+}
+
+// LocalBackend runs commands on this machine.
+type LocalBackend struct{}
+
+func (LocalBackend) Command(name string, args ...string) *exec.Cmd {
+	return exec.Command(name, args...)
+}
+
+func (LocalBackend) TerminalCommand(name string, args ...string) *exec.Cmd {
+	return exec.Command(name, args...)
+}
+
+// SSHBackend runs commands on a remote machine over SSH. Connections are multiplexed, so the
+// frequent tmux commands don't each pay for a handshake. Authentication must not prompt: use a
+// key or an agent.
+type SSHBackend struct {
+	// Host is the name or address of the machine.
+	Host string
+	// User logs in to the machine. Empty uses ssh's default.
+	User string
+	// KeyFile is the private key to authenticate with. Empty uses ssh's default.
+	KeyFile string
+	// Port is the SSH port. Zero uses ssh's default.
+	Port int
+}
+
+func (b SSHBackend) Command(name string, args ...string) *exec.Cmd {
+	return exec.Command("ssh", append(b.sshArgs(false), shellJoin(name, args...))...)
+}
+
+func (b SSHBackend) TerminalCommand(name string, args ...string) *exec.Cmd {
+	return exec.Command("ssh", append(b.sshArgs(true), shellJoin(name, args...))...)
+}
+
+// String returns the destination commands run on, e.g. "me@build-box".
+func (b SSHBackend) String() string {
+	if b.User == "" {
+		return b.Host
+	}
+	return b.User + "@" + b.Host
+}
+
+// ForwardPort forwards port through the shared connection, which stays open while commands run.
+func (b SSHBackend) ForwardPort(port int) error {
+	return b.control("forward", port)
+}
+
+func (b SSHBackend) CancelPortForward(port int) error {
+	return b.control("cancel", port)
+}
+
+// control asks the shared connection to start or stop forwarding port.
+func (b SSHBackend) control(operation string, port int) error {
+	spec := fmt.Sprintf("%d:localhost:%d", port, port)
+	args := append(b.options(false), "-O", operation, "-L", spec, b.String())
+	if output, err := exec.Command("ssh", args...).CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to %s port %d to %s: %s (%w)", operation, port, b, strings.TrimSpace(string(output)), err)
+	}
+	return nil
+}
+
+// sshArgs returns the arguments of ssh before the remote command.
+func (b SSHBackend) sshArgs(terminal bool) []string {
+	return append(b.options(terminal), b.String(), "--")
+}
+
+// options returns the options of ssh. terminal allocates a remote PTY, which ssh only does from a
+// terminal unless forced.
+func (b SSHBackend) options(terminal bool) []string {
+	args := []string{
+		"-o", "BatchMode=yes",
+		"-o", "ControlMaster=auto",
+		"-o", "ControlPath=" + filepath.Join(os.TempDir(), "claude-squad-ssh-%C"),
+		"-o", "ControlPersist=10m",
+	}
+	if terminal {
+		args = append(args, "-tt")
+	} else {
+		args = append(args, "-T")
+	}
+	if b.Port != 0 {
+		args = append(args, "-p", strconv.Itoa(b.Port))
+	}
+	if b.KeyFile != "" {
+		args = append(args, "-i", b.KeyFile)
+	}
+	return args
+}
+
+var shellSafeRegex = regexp.MustCompile(`^[A-Za-z0-9_./=:@%+,-]+$`)
+
+// shellJoin returns name and args as a command line for the remote shell, quoting each word so
+// it arrives unchanged.
+func shellJoin(name string, args ...string) string {
+	words := make([]string, 0, len(args)+1)
+	for _, word := range append([]string{name}, args...) {
+		if shellSafeRegex.MatchString(word) {
+			words = append(words, word)
+			continue
+		}
+		words = append(words, "'"+strings.ReplaceAll(word, "'", `'\''`)+"'")
+	}
+	return strings.Join(words, " ")
+}
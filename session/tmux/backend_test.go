@@ -0,0 +1,38 @@
+package tmux
+
+import (
+	"os/exec"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSSHBackend(t *testing.T) {
+	backend := SSHBackend{Host: "build-box", User: "me", KeyFile: "/keys/id", Port: 2222}
+	assert.Equal(t, "me@build-box", backend.String())
+	assert.Equal(t, "build-box", SSHBackend{Host: "build-box"}.String())
+
+	cmd := backend.Command("tmux", "capture-pane", "-p", "-t", "claudesquad_x.0")
+	args := cmd.Args[1:]
+	assert.Equal(t, "ssh", cmd.Args[0])
+	assert.Contains(t, args, "-T")
+	assert.NotContains(t, args, "-tt")
+	assert.Subset(t, args, []string{"-p", "2222", "-i", "/keys/id"})
+	require.GreaterOrEqual(t, len(args), 3)
+	assert.Equal(t, []string{"me@build-box", "--", "tmux capture-pane -p -t claudesquad_x.0"}, args[len(args)-3:])
+
+	// Attaching needs a remote terminal
+	attach := backend.TerminalCommand("tmux", "attach-session", "-t", "claudesquad_x")
+	assert.Contains(t, attach.Args, "-tt")
+	assert.NotContains(t, attach.Args, "-T")
+}
+
+func TestShellJoin(t *testing.T) {
+	words := []string{"plain", "two words", "it's", "#{pane_index} #{history_size}", "$HOME", ""}
+	line := shellJoin("printf", append([]string{"%s|"}, words...)...)
+
+	output, err := exec.Command("sh", "-c", line).Output()
+	require.NoError(t, err)
+	assert.Equal(t, "plain|two words|it's|#{pane_index} #{history_size}|$HOME||", string(output))
+}
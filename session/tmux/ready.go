@@ -0,0 +1,85 @@
+package tmux
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// readyPatterns match the input prompt a program shows once it accepts typed input, keyed by the
+// program's executable name.
+var readyPatterns = map[string]*regexp.Regexp{
+	// The input box, e.g. "│ > Try ..." or "> " above "? for shortcuts"
+	ProgramClaude: regexp.MustCompile(`(?m)\? for shortcuts|^[│ ]*> `),
+	// The last line is the input prompt, e.g. "> " or "architect> "
+	ProgramAider:  regexp.MustCompile(`(?m)^\S*> ?\s*\z`),
+	ProgramGemini: regexp.MustCompile(`Type your message`),
+}
+
+// readyPollInterval is how often the pane is checked while waiting for the program.
+var readyPollInterval = 200 * time.Millisecond
+
+const (
+	// readySettlePolls is how many polls in a row the pane must stay unchanged once a known
+	// program shows its input prompt, so keystrokes don't land while it is still drawing.
+	readySettlePolls = 2
+	// unknownSettlePolls is how many polls in a row the pane of a program without a known input
+	// prompt must stay unchanged before it is considered ready.
+	unknownSettlePolls = 5
+)
+
+// paneEscapeRe matches the escape sequences kept by capturing the pane with colors.
+var paneEscapeRe = regexp.MustCompile(`\x1b\[[0-9;?]*[A-Za-z]`)
+
+// WaitForReady waits until the program accepts typed input: it shows its input prompt and the
+// pane has stopped changing. For programs without a known input prompt, it waits until the pane
+// has stopped changing. It returns an error if the program isn't ready within timeout.
+func (t *TmuxSession) WaitForReady(timeout time.Duration) error {
+	var pattern *regexp.Regexp
+	if fields := strings.Fields(t.program); len(fields) > 0 {
+		pattern = readyPatterns[filepath.Base(fields[0])]
+	}
+	settle := readySettlePolls
+	if pattern == nil {
+		settle = unknownSettlePolls
+	}
+
+	deadline := time.Now().Add(timeout)
+	previous, unchanged := "", 0
+	for {
+		content, err := t.CapturePaneContent()
+		if err != nil {
+			return err
+		}
+		content = paneEscapeRe.ReplaceAllString(content, "")
+		if content == previous && strings.TrimSpace(content) != "" {
+			unchanged++
+		} else {
+			previous, unchanged = content, 0
+		}
+		if unchanged >= settle && (pattern == nil || pattern.MatchString(strings.TrimRight(content, "\n"))) {
+			return nil
+		}
+		if time.Now().After(deadline) {
+			return fmt.Errorf("timed out after %s waiting for %s to accept input", timeout, t.program)
+		}
+		time.Sleep(readyPollInterval)
+	}
+}
+
+// WaitForChange waits until the pane content differs from previous, a capture taken with
+// CapturePaneContent, returning false if it is unchanged after timeout.
+func (t *TmuxSession) WaitForChange(previous string, timeout time.Duration) bool {
+	deadline := time.Now().Add(timeout)
+	for {
+		if content, err := t.CapturePaneContent(); err == nil && content != previous {
+			return true
+		}
+		if time.Now().After(deadline) {
+			return false
+		}
+		time.Sleep(readyPollInterval)
+	}
+}
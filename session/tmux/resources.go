@@ -0,0 +1,194 @@
+package tmux
+
+import (
+	"claude-squad/log"
+	"context"
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ResourceUsage is the CPU and memory used by the processes running in a tmux session's panes.
+type ResourceUsage struct {
+	// CPU is the percentage of one core used since the previous sample, so it can exceed 100
+	CPU float64
+	// Memory is the resident memory in bytes
+	Memory int64
+}
+
+// process is one line of ps output.
+type process struct {
+	pid, ppid int
+	// cpuTime is the CPU time the process has used, in seconds
+	cpuTime float64
+	// rss is the resident memory in kilobytes
+	rss int64
+}
+
+// resourceMonitor holds the latest usage of each claude-squad tmux session, keyed by session name.
+var resourceMonitor = struct {
+	sync.RWMutex
+	usage map[string]ResourceUsage
+}{usage: make(map[string]ResourceUsage)}
+
+// StartResourceMonitor samples the resource usage of all claude-squad tmux sessions every
+// interval in a background goroutine until ctx is done. Each sample runs tmux and ps once,
+// however many sessions there are.
+func StartResourceMonitor(ctx context.Context, interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		// previous is each session's total CPU time at the last sample
+		previous := make(map[string]float64)
+		lastSample := time.Now()
+		loggedErr := false
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case now := <-ticker.C:
+				usage, cpuTimes, err := sampleResources(previous, now.Sub(lastSample))
+				if err != nil {
+					// ps or tmux being unavailable won't fix itself, so only log it once
+					if !loggedErr {
+						log.WarningLog.Printf("resource monitor: %v", err)
+						loggedErr = true
+					}
+					continue
+				}
+				previous, lastSample = cpuTimes, now
+				resourceMonitor.Lock()
+				resourceMonitor.usage = usage
+				resourceMonitor.Unlock()
+			}
+		}
+	}()
+}
+
+// ResourceUsage returns the latest sampled resource usage of the session, and false if the
+// session hasn't been sampled yet. Only sessions on this machine are sampled.
+func (t *TmuxSession) ResourceUsage() (ResourceUsage, bool) {
+	resourceMonitor.RLock()
+	defer resourceMonitor.RUnlock()
+	usage, ok := resourceMonitor.usage[t.sanitizedName]
+	return usage, ok
+}
+
+// sampleResources measures the usage of every claude-squad session. CPU is measured against
+// previous, the sessions' CPU times elapsed ago, which it returns updated.
+func sampleResources(previous map[string]float64, elapsed time.Duration) (map[string]ResourceUsage, map[string]float64, error) {
+	panes, err := exec.Command("tmux", "list-panes", "-a", "-F", "#{session_name} #{pane_pid}").Output()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to list tmux panes: %w", err)
+	}
+	ps, err := exec.Command("ps", "-A", "-o", "pid=,ppid=,time=,rss=").Output()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to list processes: %w", err)
+	}
+	usage, cpuTimes := sessionResources(parsePanePIDs(string(panes)), parseProcesses(string(ps)), previous, elapsed)
+	return usage, cpuTimes, nil
+}
+
+// parsePanePIDs parses "session pid" lines into the pane process IDs of each claude-squad
+// session.
+func parsePanePIDs(output string) map[string][]int {
+	pids := make(map[string][]int)
+	for _, line := range strings.Split(output, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) != 2 || !strings.HasPrefix(fields[0], TmuxPrefix) {
+			continue
+		}
+		if pid, err := strconv.Atoi(fields[1]); err == nil {
+			pids[fields[0]] = append(pids[fields[0]], pid)
+		}
+	}
+	return pids
+}
+
+// parseProcesses parses ps output with the columns pid, ppid, time and rss.
+func parseProcesses(output string) []process {
+	var processes []process
+	for _, line := range strings.Split(output, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) != 4 {
+			continue
+		}
+		pid, err1 := strconv.Atoi(fields[0])
+		ppid, err2 := strconv.Atoi(fields[1])
+		cpuTime, err3 := parseCPUTime(fields[2])
+		rss, err4 := strconv.ParseInt(fields[3], 10, 64)
+		if err1 != nil || err2 != nil || err3 != nil || err4 != nil {
+			continue
+		}
+		processes = append(processes, process{pid: pid, ppid: ppid, cpuTime: cpuTime, rss: rss})
+	}
+	return processes
+}
+
+// parseCPUTime parses the CPU time ps prints, "[[dd-]hh:]mm:ss" on Linux and "mm:ss.cc" on
+// macOS, into seconds.
+func parseCPUTime(value string) (float64, error) {
+	days := 0.0
+	if d, rest, ok := strings.Cut(value, "-"); ok {
+		n, err := strconv.Atoi(d)
+		if err != nil {
+			return 0, fmt.Errorf("invalid cpu time %q", value)
+		}
+		days, value = float64(n), rest
+	}
+	parts := strings.Split(value, ":")
+	seconds := 0.0
+	for _, part := range parts {
+		n, err := strconv.ParseFloat(part, 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid cpu time %q", value)
+		}
+		seconds = seconds*60 + n
+	}
+	return days*24*60*60 + seconds, nil
+}
+
+// sessionResources sums the usage of each session's pane processes and all their descendants.
+// It returns the usage and each session's total CPU time for the next sample.
+func sessionResources(panes map[string][]int, processes []process, previous map[string]float64, elapsed time.Duration) (map[string]ResourceUsage, map[string]float64) {
+	byPID := make(map[int]process, len(processes))
+	children := make(map[int][]int)
+	for _, p := range processes {
+		byPID[p.pid] = p
+		children[p.ppid] = append(children[p.ppid], p.pid)
+	}
+
+	usage := make(map[string]ResourceUsage, len(panes))
+	cpuTimes := make(map[string]float64, len(panes))
+	for name, pids := range panes {
+		var cpuTime float64
+		var rss int64
+		stack := append([]int(nil), pids...)
+		seen := make(map[int]bool)
+		for len(stack) > 0 {
+			pid := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			if seen[pid] {
+				continue
+			}
+			seen[pid] = true
+			if p, ok := byPID[pid]; ok {
+				cpuTime += p.cpuTime
+				rss += p.rss
+			}
+			stack = append(stack, children[pid]...)
+		}
+
+		cpuTimes[name] = cpuTime
+		result := ResourceUsage{Memory: rss * 1024}
+		// Processes that exit take their CPU time with them, so the total can drop
+		if before, ok := previous[name]; ok && elapsed > 0 && cpuTime > before {
+			result.CPU = (cpuTime - before) / elapsed.Seconds() * 100
+		}
+		usage[name] = result
+	}
+	return usage, cpuTimes
+}
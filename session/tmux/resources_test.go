@@ -0,0 +1,44 @@
+package tmux
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseCPUTime(t *testing.T) {
+	for value, want := range map[string]float64{
+		"00:01:02":   62,
+		"1-02:00:00": 26 * 60 * 60,
+		"3:04.50":    184.5,
+	} {
+		got, err := parseCPUTime(value)
+		require.NoError(t, err, value)
+		require.InDelta(t, want, got, 0.001, value)
+	}
+	_, err := parseCPUTime("soon")
+	require.Error(t, err)
+}
+
+func TestSessionResources(t *testing.T) {
+	panes := parsePanePIDs("claudesquad_one 100\nclaudesquad_one 200\nother 300\nclaudesquad_two 400\n")
+	require.Equal(t, map[string][]int{"claudesquad_one": {100, 200}, "claudesquad_two": {400}}, panes)
+
+	processes := parseProcesses(`  100     1 00:00:10  1000
+  101   100 00:00:20  2000
+  102   101 00:00:30  3000
+  200     1 00:00:00   500
+  300     1 00:10:00  9000
+  400     1 00:00:05   100
+`)
+	require.Len(t, processes, 6)
+
+	usage, cpuTimes := sessionResources(panes, processes, map[string]float64{"claudesquad_one": 57}, 2*time.Second)
+	require.Equal(t, 60.0, cpuTimes["claudesquad_one"])
+	// The pane processes and their descendants, but not other sessions' processes
+	require.Equal(t, int64(6500*1024), usage["claudesquad_one"].Memory)
+	require.InDelta(t, 150.0, usage["claudesquad_one"].CPU, 0.001)
+	// A session without a previous sample has no CPU usage yet
+	require.Equal(t, ResourceUsage{Memory: 100 * 1024}, usage["claudesquad_two"])
+}
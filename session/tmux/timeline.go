@@ -0,0 +1,124 @@
+package tmux
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// maxTimelineSamples bounds the memory of a PaneTimeline. When reached, every other sample is
+// dropped, trading timestamp precision of old lines for a fixed footprint.
+const maxTimelineSamples = 4096
+
+type lineSample struct {
+	lines int
+	at    time.Time
+}
+
+// PaneTimeline estimates when each line of a pane appeared by sampling the pane's line count
+// over time. tmux doesn't timestamp output, so a line is stamped with the time of the first
+// sample that saw it. Lines that existed before the first sample have a zero time.
+type PaneTimeline struct {
+	mu      sync.Mutex
+	samples []lineSample
+}
+
+// Record adds a sample of the pane's total line count. Samples that don't add lines are ignored;
+// a smaller count (e.g. after the history was cleared) drops the samples for lines that no
+// longer exist.
+func (p *PaneTimeline) Record(lines int, at time.Time) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if n := len(p.samples); n > 0 && lines == p.samples[n-1].lines {
+		return
+	}
+	keep := sort.Search(len(p.samples), func(i int) bool { return p.samples[i].lines >= lines })
+	p.samples = append(p.samples[:keep], lineSample{lines: lines, at: at})
+
+	if len(p.samples) > maxTimelineSamples {
+		thinned := p.samples[:0]
+		for i, sample := range p.samples {
+			// Always keep the first sample, which marks the lines that predate the timeline
+			if i == 0 || i%2 == 1 || i == len(p.samples)-1 {
+				thinned = append(thinned, sample)
+			}
+		}
+		p.samples = thinned
+	}
+}
+
+// LineTime returns the estimated time line n (0-based) appeared, or the zero time if it was
+// already there when sampling started.
+func (p *PaneTimeline) LineTime(n int) time.Time {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if len(p.samples) == 0 || n < p.samples[0].lines {
+		return time.Time{}
+	}
+	i := sort.Search(len(p.samples), func(i int) bool { return p.samples[i].lines > n })
+	if i == len(p.samples) {
+		// Newer than the latest sample
+		return p.samples[len(p.samples)-1].at
+	}
+	return p.samples[i].at
+}
+
+// TimedLine is a captured line with the pane it came from and its estimated time.
+type TimedLine struct {
+	Text   string
+	Source string
+	At     time.Time
+}
+
+// Stamp pairs each captured line with its estimated time.
+func (p *PaneTimeline) Stamp(lines []string, source string) []TimedLine {
+	timed := make([]TimedLine, len(lines))
+	for i, line := range lines {
+		timed[i] = TimedLine{Text: line, Source: source, At: p.LineTime(i)}
+	}
+	return timed
+}
+
+// Interleave merges the lines of several panes by time. Lines keep their order within a pane,
+// and lines with equal times keep the order of the arguments.
+func Interleave(panes ...[]TimedLine) []TimedLine {
+	var merged []TimedLine
+	for _, lines := range panes {
+		merged = append(merged, lines...)
+	}
+	sort.SliceStable(merged, func(i, j int) bool {
+		return merged[i].At.Before(merged[j].At)
+	})
+	return merged
+}
+
+// PaneLineCounts returns the number of lines (scrollback plus lines up to the cursor) of each
+// pane in the session, keyed by pane index.
+func (t *TmuxSession) PaneLineCounts() (map[int]int, error) {
+	cmd := t.backend.Command("tmux", "list-panes", "-t", t.sanitizedName, "-F", "#{pane_index} #{history_size} #{cursor_y}")
+	output, err := t.cmdExec.Output(cmd)
+	if err != nil {
+		return nil, fmt.Errorf("error listing panes: %v", err)
+	}
+
+	counts := make(map[int]int)
+	for _, line := range strings.Split(strings.TrimSpace(string(output)), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) != 3 {
+			continue
+		}
+		pane, err1 := strconv.Atoi(fields[0])
+		history, err2 := strconv.Atoi(fields[1])
+		cursor, err3 := strconv.Atoi(fields[2])
+		if err1 != nil || err2 != nil || err3 != nil {
+			continue
+		}
+		counts[pane] = history + cursor + 1
+	}
+	return counts, nil
+}
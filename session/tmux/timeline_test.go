@@ -0,0 +1,48 @@
+package tmux
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPaneTimeline(t *testing.T) {
+	start := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	var timeline PaneTimeline
+	timeline.Record(3, start)
+	timeline.Record(3, start.Add(time.Second))
+	timeline.Record(5, start.Add(2*time.Second))
+	timeline.Record(6, start.Add(3*time.Second))
+
+	// Lines present at the first sample have no known time
+	assert.True(t, timeline.LineTime(2).IsZero())
+	assert.Equal(t, start.Add(2*time.Second), timeline.LineTime(3))
+	assert.Equal(t, start.Add(2*time.Second), timeline.LineTime(4))
+	assert.Equal(t, start.Add(3*time.Second), timeline.LineTime(5))
+	assert.Equal(t, start.Add(3*time.Second), timeline.LineTime(9))
+
+	// Shrinking drops the samples for lines that no longer exist
+	timeline.Record(4, start.Add(4*time.Second))
+	assert.Equal(t, start.Add(4*time.Second), timeline.LineTime(3))
+	assert.True(t, timeline.LineTime(2).IsZero())
+}
+
+func TestInterleave(t *testing.T) {
+	start := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	var ai, term PaneTimeline
+	ai.Record(1, start)
+	ai.Record(2, start.Add(2*time.Second))
+	term.Record(0, start)
+	term.Record(1, start.Add(time.Second))
+	term.Record(2, start.Add(3*time.Second))
+
+	merged := Interleave(ai.Stamp([]string{"a0", "a1"}, "ai"), term.Stamp([]string{"t0", "t1"}, "term"))
+	require.Len(t, merged, 4)
+	var texts []string
+	for _, line := range merged {
+		texts = append(texts, line.Text)
+	}
+	assert.Equal(t, []string{"a0", "t0", "a1", "t1"}, texts)
+}
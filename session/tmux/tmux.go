@@ -35,6 +35,11 @@ type TmuxSession struct {
 	ptyFactory PtyFactory
 	// cmdExec is used to execute commands in the tmux session.
 	cmdExec cmd.Executor
+	// backend runs the tmux commands, on the machine the tmux server is on.
+	backend Backend
+	// command is the command the session runs, when it wraps the program, e.g. in docker exec.
+	// Empty runs the program itself.
+	command string
 
 	// Initialized by Start or Restore
 	//
@@ -83,15 +88,36 @@ func NewTmuxSessionWithDeps(name string, program string, ptyFactory PtyFactory,
 	return newTmuxSession(name, program, ptyFactory, cmdExec)
 }
 
+// NewTmuxSessionWithBackend creates a new TmuxSession whose tmux server runs where backend runs
+// commands, e.g. on a remote machine.
+func NewTmuxSessionWithBackend(name string, program string, backend Backend) *TmuxSession {
+	t := newTmuxSession(name, program, MakePtyFactory(), cmd.MakeExecutor())
+	t.backend = backend
+	return t
+}
+
 func newTmuxSession(name string, program string, ptyFactory PtyFactory, cmdExec cmd.Executor) *TmuxSession {
 	return &TmuxSession{
 		sanitizedName: toClaudeSquadTmuxName(name),
 		program:       program,
 		ptyFactory:    ptyFactory,
 		cmdExec:       cmdExec,
+		backend:       LocalBackend{},
 	}
 }
 
+// TmuxCommand returns a tmux command run on the machine the session's tmux server is on.
+func (t *TmuxSession) TmuxCommand(args ...string) *exec.Cmd {
+	return t.backend.Command("tmux", args...)
+}
+
+// SetCommand makes the session run command instead of the program, for a command that runs the
+// program somewhere else, such as in a container. The program still decides how the session's
+// prompts are detected.
+func (t *TmuxSession) SetCommand(command string) {
+	t.command = command
+}
+
 // Start creates and starts a new tmux session, then attaches to it. Program is the command to run in
 // the session (ex. claude). workdir is the git worktree directory.
 func (t *TmuxSession) Start(workDir string) error {
@@ -101,13 +127,17 @@ func (t *TmuxSession) Start(workDir string) error {
 	}
 
 	// Create a new detached tmux session and start claude in it
-	cmd := exec.Command("tmux", "new-session", "-d", "-s", t.sanitizedName, "-c", workDir, t.program)
+	command := t.program
+	if t.command != "" {
+		command = t.command
+	}
+	cmd := t.backend.TerminalCommand("tmux", "new-session", "-d", "-s", t.sanitizedName, "-c", workDir, command)
 
 	ptmx, err := t.ptyFactory.Start(cmd)
 	if err != nil {
 		// Cleanup any partially created session if any exists.
 		if t.DoesSessionExist() {
-			cleanupCmd := exec.Command("tmux", "kill-session", "-t", t.sanitizedName)
+			cleanupCmd := t.backend.Command("tmux", "kill-session", "-t", t.sanitizedName)
 			if cleanupErr := t.cmdExec.Run(cleanupCmd); cleanupErr != nil {
 				err = fmt.Errorf("%v (cleanup error: %v)", err, cleanupErr)
 			}
@@ -133,13 +163,13 @@ func (t *TmuxSession) Start(workDir string) error {
 	ptmx.Close()
 
 	// Set history limit to enable scrollback (default is 2000, we'll use 10000 for more history)
-	historyCmd := exec.Command("tmux", "set-option", "-t", t.sanitizedName, "history-limit", "10000")
+	historyCmd := t.backend.Command("tmux", "set-option", "-t", t.sanitizedName, "history-limit", "10000")
 	if err := t.cmdExec.Run(historyCmd); err != nil {
 		log.InfoLog.Printf("Warning: failed to set history-limit for session %s: %v", t.sanitizedName, err)
 	}
 
 	// Set status-position to top
-	statusCmd := exec.Command("tmux", "set-option", "-t", t.sanitizedName, "status-position", "top")
+	statusCmd := t.backend.Command("tmux", "set-option", "-t", t.sanitizedName, "status-position", "top")
 	if err := t.cmdExec.Run(statusCmd); err != nil {
 		// Non-fatal error, just log it
 		// The session will still work without this setting
@@ -180,14 +210,19 @@ func (t *TmuxSession) Start(workDir string) error {
 	return nil
 }
 
-// Restore attaches to an existing session and restores the window size
+// Restore attaches to an existing session and restores the window size. The status monitor
+// starts from the pane's current content, so output from before the restart isn't taken for new
+// activity.
 func (t *TmuxSession) Restore() error {
-	ptmx, err := t.ptyFactory.Start(exec.Command("tmux", "attach-session", "-t", t.sanitizedName))
+	ptmx, err := t.ptyFactory.Start(t.backend.TerminalCommand("tmux", "attach-session", "-t", t.sanitizedName))
 	if err != nil {
 		return fmt.Errorf("error opening PTY: %w", err)
 	}
 	t.ptmx = ptmx
 	t.monitor = newStatusMonitor()
+	if content, err := t.CapturePaneContent(); err == nil {
+		t.monitor.prevOutputHash = t.monitor.hash(content)
+	}
 	return nil
 }
 
@@ -271,17 +306,17 @@ func (t *TmuxSession) AttachToPane(paneIndex int) (chan struct{}, error) {
 	t.attachCh = make(chan struct{})
 
 	// First, ensure we're on the correct window (window 0)
-	selectWindowCmd := exec.Command("tmux", "select-window", "-t", t.sanitizedName+":0")
+	selectWindowCmd := t.backend.Command("tmux", "select-window", "-t", t.sanitizedName+":0")
 	t.cmdExec.Run(selectWindowCmd)
 
 	// Select and zoom the specified pane
 	targetPane := fmt.Sprintf("%s.%d", t.sanitizedName, paneIndex)
 
 	// Select the pane
-	selectCmd := exec.Command("tmux", "select-pane", "-t", targetPane)
+	selectCmd := t.backend.Command("tmux", "select-pane", "-t", targetPane)
 	if err := t.cmdExec.Run(selectCmd); err == nil {
 		// Zoom the pane to fill the window
-		zoomCmd := exec.Command("tmux", "resize-pane", "-Z", "-t", targetPane)
+		zoomCmd := t.backend.Command("tmux", "resize-pane", "-Z", "-t", targetPane)
 		t.cmdExec.Run(zoomCmd)
 	}
 
@@ -430,7 +465,7 @@ func (t *TmuxSession) Detach() {
 	// I'm not sure if we get into a bad state. Needs testing.
 
 	// Unzoom any zoomed pane before detaching
-	unzoomCmd := exec.Command("tmux", "resize-pane", "-Z", "-t", t.sanitizedName)
+	unzoomCmd := t.backend.Command("tmux", "resize-pane", "-Z", "-t", t.sanitizedName)
 	t.cmdExec.Run(unzoomCmd)
 
 	defer func() {
@@ -478,7 +513,7 @@ func (t *TmuxSession) Close() error {
 
 	// Only try to kill session if it exists
 	if t.DoesSessionExist() {
-		cmd := exec.Command("tmux", "kill-session", "-t", t.sanitizedName)
+		cmd := t.backend.Command("tmux", "kill-session", "-t", t.sanitizedName)
 		if err := t.cmdExec.Run(cmd); err != nil {
 			errs = append(errs, fmt.Errorf("error killing tmux session: %w", err))
 		}
@@ -516,7 +551,7 @@ func (t *TmuxSession) updateWindowSize(cols, rows int) error {
 
 func (t *TmuxSession) DoesSessionExist() bool {
 	// Using "-t name" does a prefix match, which is wrong. `-t=` does an exact match.
-	existsCmd := exec.Command("tmux", "has-session", fmt.Sprintf("-t=%s", t.sanitizedName))
+	existsCmd := t.backend.Command("tmux", "has-session", fmt.Sprintf("-t=%s", t.sanitizedName))
 	return t.cmdExec.Run(existsCmd) == nil
 }
 
@@ -528,7 +563,7 @@ func (t *TmuxSession) CapturePaneContent() (string, error) {
 	}
 
 	// Add -e flag to preserve escape sequences (ANSI color codes)
-	cmd := exec.Command("tmux", "capture-pane", "-p", "-e", "-J", "-t", t.sanitizedName+".0")
+	cmd := t.backend.Command("tmux", "capture-pane", "-p", "-e", "-J", "-t", t.sanitizedName+".0")
 	output, err := t.cmdExec.Output(cmd)
 	if err != nil {
 		return "", fmt.Errorf("error capturing pane content: %v", err)
@@ -545,7 +580,7 @@ func (t *TmuxSession) CapturePaneContentWithOptions(start, end string) (string,
 	}
 
 	// Add -e flag to preserve escape sequences (ANSI color codes)
-	cmd := exec.Command("tmux", "capture-pane", "-p", "-e", "-J", "-S", start, "-E", end, "-t", t.sanitizedName)
+	cmd := t.backend.Command("tmux", "capture-pane", "-p", "-e", "-J", "-S", start, "-E", end, "-t", t.sanitizedName)
 	output, err := t.cmdExec.Output(cmd)
 	if err != nil {
 		return "", fmt.Errorf("failed to capture tmux pane content with options: %v", err)
@@ -553,6 +588,27 @@ func (t *TmuxSession) CapturePaneContentWithOptions(start, end string) (string,
 	return string(output), nil
 }
 
+// CapturePaneHistory captures the full scrollback of one of the session's panes as plain text.
+func (t *TmuxSession) CapturePaneHistory(pane int) (string, error) {
+	cmd := t.backend.Command("tmux", "capture-pane", "-p", "-J", "-S", "-", "-t", fmt.Sprintf("%s.%d", t.sanitizedName, pane))
+	output, err := t.cmdExec.Output(cmd)
+	if err != nil {
+		return "", fmt.Errorf("failed to capture history of pane %d: %v", pane, err)
+	}
+	return string(output), nil
+}
+
+// CapturePaneLines captures the full scrollback of one of the session's panes with its colors, a
+// line per screen row. Wrapped lines aren't joined, so they line up with PaneLineCounts.
+func (t *TmuxSession) CapturePaneLines(pane int) ([]string, error) {
+	cmd := t.backend.Command("tmux", "capture-pane", "-p", "-e", "-S", "-", "-t", fmt.Sprintf("%s.%d", t.sanitizedName, pane))
+	output, err := t.cmdExec.Output(cmd)
+	if err != nil {
+		return nil, fmt.Errorf("failed to capture history of pane %d: %v", pane, err)
+	}
+	return strings.Split(strings.TrimRight(string(output), "\n "), "\n"), nil
+}
+
 // GetSessionName returns the sanitized tmux session name
 func (t *TmuxSession) GetSessionName() string {
 	return t.sanitizedName
@@ -580,7 +636,7 @@ func (t *TmuxSession) ReloadSession(workDir string) error {
 
 	// Kill existing session if it exists
 	if t.DoesSessionExist() {
-		cmd := exec.Command("tmux", "kill-session", "-t", t.sanitizedName)
+		cmd := t.backend.Command("tmux", "kill-session", "-t", t.sanitizedName)
 		t.cmdExec.Run(cmd) // Ignore error as session might not exist
 	}
 
@@ -604,7 +660,7 @@ func (t *TmuxSession) CreateTerminalPane(workDir string) error {
 	}
 
 	// Check if we already have a second pane
-	listCmd := exec.Command("tmux", "list-panes", "-t", t.sanitizedName, "-F", "#{pane_index}")
+	listCmd := t.backend.Command("tmux", "list-panes", "-t", t.sanitizedName, "-F", "#{pane_index}")
 	output, err := t.cmdExec.Output(listCmd)
 	if err != nil {
 		return fmt.Errorf("error listing panes: %v", err)
@@ -618,13 +674,13 @@ func (t *TmuxSession) CreateTerminalPane(workDir string) error {
 
 	// Create a vertical split for the terminal
 	// Using -b flag to create new pane to the left/above and keep AI in pane 1
-	cmd := exec.Command("tmux", "split-window", "-t", t.sanitizedName, "-v", "-b", "-d", "-c", workDir)
+	cmd := t.backend.Command("tmux", "split-window", "-t", t.sanitizedName, "-v", "-b", "-d", "-c", workDir)
 	if err := t.cmdExec.Run(cmd); err != nil {
 		return fmt.Errorf("error creating terminal pane: %v", err)
 	}
 
 	// Clear the terminal pane (now pane 0)
-	clearCmd := exec.Command("tmux", "send-keys", "-t", t.sanitizedName+".0", "clear", "Enter")
+	clearCmd := t.backend.Command("tmux", "send-keys", "-t", t.sanitizedName+".0", "clear", "Enter")
 	t.cmdExec.Run(clearCmd)
 
 	return nil
@@ -638,7 +694,7 @@ func (t *TmuxSession) CaptureTerminalContent() (string, error) {
 	}
 
 	// Capture from pane index 1 (AI pane after split)
-	cmd := exec.Command("tmux", "capture-pane", "-p", "-e", "-J", "-t", t.sanitizedName+".1")
+	cmd := t.backend.Command("tmux", "capture-pane", "-p", "-e", "-J", "-t", t.sanitizedName+".1")
 	output, err := t.cmdExec.Output(cmd)
 	if err != nil {
 		return "", fmt.Errorf("error capturing terminal content: %v", err)
@@ -646,6 +702,43 @@ func (t *TmuxSession) CaptureTerminalContent() (string, error) {
 	return string(output), nil
 }
 
+// TerminalPaneCommand returns the name of the command running in the foreground of the terminal
+// pane (pane 0), e.g. "zsh" while the shell waits for input or "make" while it runs. Empty if the
+// terminal pane hasn't been created yet.
+func (t *TmuxSession) TerminalPaneCommand() (string, error) {
+	cmd := t.backend.Command("tmux", "list-panes", "-t", t.sanitizedName, "-F", "#{pane_index} #{pane_current_command}")
+	output, err := t.cmdExec.Output(cmd)
+	if err != nil {
+		return "", fmt.Errorf("error listing panes: %v", err)
+	}
+	return terminalPaneCommand(string(output)), nil
+}
+
+// terminalPaneCommand returns the command of pane 0 from list-panes output, if the session has
+// the terminal pane besides the agent's.
+func terminalPaneCommand(output string) string {
+	lines := strings.Split(strings.TrimSpace(output), "\n")
+	if len(lines) < 2 {
+		return ""
+	}
+	for _, line := range lines {
+		if index, command, ok := strings.Cut(line, " "); ok && index == "0" {
+			return strings.TrimSpace(command)
+		}
+	}
+	return ""
+}
+
+// IsShell returns true if command is the name of an interactive shell, meaning a pane running it
+// waits for input rather than running a command.
+func IsShell(command string) bool {
+	switch strings.TrimPrefix(command, "-") {
+	case "bash", "zsh", "fish", "sh", "dash", "ksh", "tcsh", "csh", "nu", "pwsh", "elvish", "xonsh":
+		return true
+	}
+	return false
+}
+
 // SendKeysToTerminal sends keystrokes to the terminal pane
 func (t *TmuxSession) SendKeysToTerminal(keys string) error {
 	// First check if the session exists
@@ -653,10 +746,29 @@ func (t *TmuxSession) SendKeysToTerminal(keys string) error {
 		return fmt.Errorf("tmux session %s does not exist", t.sanitizedName)
 	}
 
-	cmd := exec.Command("tmux", "send-keys", "-t", t.sanitizedName+".1", keys)
+	cmd := t.backend.Command("tmux", "send-keys", "-t", t.sanitizedName+".1", keys)
 	return t.cmdExec.Run(cmd)
 }
 
+// LiveSessions returns the names of the claude-squad tmux sessions running on this machine.
+func LiveSessions(cmdExec cmd.Executor) ([]string, error) {
+	output, err := cmdExec.Output(exec.Command("tmux", "list-sessions", "-F", "#{session_name}"))
+	if err != nil {
+		// Exit code 1 means no server is running, so there are no sessions
+		if exitErr, ok := err.(*exec.ExitError); ok && exitErr.ExitCode() == 1 {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to list tmux sessions: %v", err)
+	}
+	var sessions []string
+	for _, name := range strings.Split(string(output), "\n") {
+		if name = strings.TrimSpace(name); strings.HasPrefix(name, TmuxPrefix) {
+			sessions = append(sessions, name)
+		}
+	}
+	return sessions, nil
+}
+
 // CleanupSessions kills all tmux sessions that start with "session-"
 func CleanupSessions(cmdExec cmd.Executor) error {
 	// First try to list sessions
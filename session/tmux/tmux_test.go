@@ -9,6 +9,7 @@ import (
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
 	"claude-squad/cmd/cmd_test"
 
@@ -86,3 +87,106 @@ func TestStartTmuxSession(t *testing.T) {
 	_, err = ptyFactory.files[1].Stat()
 	require.NoError(t, err)
 }
+
+func TestStartTmuxSessionWithCommand(t *testing.T) {
+	ptyFactory := NewMockPtyFactory(t)
+	created := false
+	cmdExec := cmd_test.MockCmdExec{
+		RunFunc: func(cmd *exec.Cmd) error {
+			if strings.Contains(cmd.String(), "has-session") && !created {
+				created = true
+				return fmt.Errorf("session already exists")
+			}
+			return nil
+		},
+		OutputFunc: func(cmd *exec.Cmd) ([]byte, error) {
+			return []byte("output"), nil
+		},
+	}
+
+	workdir := t.TempDir()
+	session := newTmuxSession("test-session", "claude", ptyFactory, cmdExec)
+	session.SetCommand("docker exec -it claude-squad-test claude")
+
+	require.NoError(t, session.Start(workdir))
+	require.Equal(t, fmt.Sprintf("tmux new-session -d -s claudesquad_test-session -c %s docker exec -it claude-squad-test claude", workdir),
+		cmd2.ToString(ptyFactory.cmds[0]))
+}
+
+func TestWaitForReady(t *testing.T) {
+	interval := readyPollInterval
+	readyPollInterval = time.Millisecond
+	defer func() { readyPollInterval = interval }()
+
+	// paneWith returns an executor whose captures step through screens, repeating the last one
+	paneWith := func(screens ...string) cmd_test.MockCmdExec {
+		captures := 0
+		return cmd_test.MockCmdExec{
+			RunFunc: func(cmd *exec.Cmd) error { return nil },
+			OutputFunc: func(cmd *exec.Cmd) ([]byte, error) {
+				screen := screens[min(captures, len(screens)-1)]
+				captures++
+				return []byte(screen), nil
+			},
+		}
+	}
+
+	starting := "Welcome to Claude Code\n"
+	ready := "╭──────╮\n│ > Try \"fix lint errors\" │\n╰──────╯\n  ? for shortcuts\n"
+	session := newTmuxSession("ready", "/usr/local/bin/claude", NewMockPtyFactory(t), paneWith(starting, starting, starting, ready))
+	require.NoError(t, session.WaitForReady(time.Second))
+
+	session = newTmuxSession("starting", "claude", NewMockPtyFactory(t), paneWith(starting))
+	require.Error(t, session.WaitForReady(20*time.Millisecond), "a settled pane without the input prompt isn't ready")
+
+	session = newTmuxSession("aider", "aider --model sonnet", NewMockPtyFactory(t), paneWith("Aider v0.80\n", "Aider v0.80\narchitect> \n"))
+	require.NoError(t, session.WaitForReady(time.Second))
+
+	session = newTmuxSession("unknown", "my-agent", NewMockPtyFactory(t), paneWith("", "loading", "$ "))
+	require.NoError(t, session.WaitForReady(time.Second), "an unknown program is ready once its pane settles")
+
+	session = newTmuxSession("empty", " ", NewMockPtyFactory(t), paneWith("$ "))
+	require.NoError(t, session.WaitForReady(time.Second), "a session without a program is ready once its pane settles")
+}
+
+func TestCapturePaneLines(t *testing.T) {
+	var captured string
+	session := newTmuxSession("history", "claude", NewMockPtyFactory(t), cmd_test.MockCmdExec{
+		RunFunc: func(cmd *exec.Cmd) error { return nil },
+		OutputFunc: func(cmd *exec.Cmd) ([]byte, error) {
+			captured = cmd2.ToString(cmd)
+			return []byte("one\n\x1b[32mtwo\x1b[0m\n\n"), nil
+		},
+	})
+	lines, err := session.CapturePaneLines(1)
+	require.NoError(t, err)
+	require.Equal(t, []string{"one", "\x1b[32mtwo\x1b[0m"}, lines)
+	require.Equal(t, "tmux capture-pane -p -e -S - -t claudesquad_history.1", captured)
+}
+
+func TestTerminalPaneCommand(t *testing.T) {
+	require.Equal(t, "make", terminalPaneCommand("0 make\n1 claude\n"))
+	require.Equal(t, "", terminalPaneCommand("0 claude\n"), "the agent's pane alone isn't the terminal")
+	require.True(t, IsShell("zsh"))
+	require.True(t, IsShell("-bash"))
+	require.False(t, IsShell("make"))
+}
+
+func TestLiveSessions(t *testing.T) {
+	cmdExec := cmd_test.MockCmdExec{
+		RunFunc: func(cmd *exec.Cmd) error { return nil },
+		OutputFunc: func(cmd *exec.Cmd) ([]byte, error) {
+			require.Contains(t, cmd.String(), "list-sessions")
+			return []byte("claudesquad_feature\nwork\nclaudesquad_fix-bug\n"), nil
+		},
+	}
+	sessions, err := LiveSessions(cmdExec)
+	require.NoError(t, err)
+	require.Equal(t, []string{"claudesquad_feature", "claudesquad_fix-bug"}, sessions)
+
+	cmdExec.OutputFunc = func(cmd *exec.Cmd) ([]byte, error) {
+		return nil, fmt.Errorf("tmux not found")
+	}
+	_, err = LiveSessions(cmdExec)
+	require.Error(t, err)
+}
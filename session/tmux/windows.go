@@ -0,0 +1,81 @@
+package tmux
+
+import (
+	"fmt"
+	"strings"
+)
+
+// windowTarget returns the tmux target of the session's window called name.
+func (t *TmuxSession) windowTarget(name string) string {
+	return t.sanitizedName + ":=" + name
+}
+
+// StartWindow runs command in a new background window of the session called name, starting in
+// workDir. The window stays open after the command exits, so its output can still be read.
+func (t *TmuxSession) StartWindow(name, workDir, command string) error {
+	cmd := t.backend.Command("tmux", "new-window", "-d", "-t", t.sanitizedName+":", "-n", name, "-c", workDir, command,
+		";", "set-option", "-w", "-t", t.windowTarget(name), "remain-on-exit", "on")
+	if err := t.cmdExec.Run(cmd); err != nil {
+		return fmt.Errorf("failed to start window %s: %v", name, err)
+	}
+	return nil
+}
+
+// StopWindow kills the session's window called name and the command running in it.
+func (t *TmuxSession) StopWindow(name string) error {
+	cmd := t.backend.Command("tmux", "kill-window", "-t", t.windowTarget(name))
+	if err := t.cmdExec.Run(cmd); err != nil {
+		return fmt.Errorf("failed to stop window %s: %v", name, err)
+	}
+	return nil
+}
+
+// Windows returns the session's windows by name, with whether the command in each still runs.
+func (t *TmuxSession) Windows() (map[string]bool, error) {
+	cmd := t.backend.Command("tmux", "list-windows", "-t", t.sanitizedName, "-F", "#{pane_dead} #{window_name}")
+	output, err := t.cmdExec.Output(cmd)
+	if err != nil {
+		return nil, fmt.Errorf("error listing windows: %v", err)
+	}
+	return parseWindows(string(output)), nil
+}
+
+// parseWindows parses the output of list-windows in the format Windows asks for.
+func parseWindows(output string) map[string]bool {
+	windows := make(map[string]bool)
+	for _, line := range strings.Split(strings.TrimSpace(output), "\n") {
+		dead, name, ok := strings.Cut(line, " ")
+		if !ok {
+			continue
+		}
+		windows[name] = dead != "1"
+	}
+	return windows
+}
+
+// CaptureWindow captures the scrollback of the session's window called name as plain text.
+func (t *TmuxSession) CaptureWindow(name string) (string, error) {
+	cmd := t.backend.Command("tmux", "capture-pane", "-p", "-J", "-S", "-", "-t", t.windowTarget(name))
+	output, err := t.cmdExec.Output(cmd)
+	if err != nil {
+		return "", fmt.Errorf("failed to capture window %s: %v", name, err)
+	}
+	return string(output), nil
+}
+
+// ForwardPort makes port of the session's machine reachable on this one, if it's another
+// machine.
+func (t *TmuxSession) ForwardPort(port int) error {
+	if forwarder, ok := t.backend.(PortForwarder); ok {
+		return forwarder.ForwardPort(port)
+	}
+	return nil
+}
+
+// CancelPortForward stops forwarding port, if ForwardPort forwarded it.
+func (t *TmuxSession) CancelPortForward(port int) error {
+	if forwarder, ok := t.backend.(PortForwarder); ok {
+		return forwarder.CancelPortForward(port)
+	}
+	return nil
+}
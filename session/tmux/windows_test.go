@@ -0,0 +1,42 @@
+package tmux
+
+import (
+	cmd2 "claude-squad/cmd"
+	"os/exec"
+	"testing"
+
+	"claude-squad/cmd/cmd_test"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseWindows(t *testing.T) {
+	windows := parseWindows("0 claude\n0 service-web\n1 service-api\n")
+	assert.Equal(t, map[string]bool{"claude": true, "service-web": true, "service-api": false}, windows)
+	assert.Empty(t, parseWindows(""))
+}
+
+func TestStartWindow(t *testing.T) {
+	var ran []string
+	cmdExec := cmd_test.MockCmdExec{
+		RunFunc: func(cmd *exec.Cmd) error {
+			ran = append(ran, cmd2.ToString(cmd))
+			return nil
+		},
+		OutputFunc: func(cmd *exec.Cmd) ([]byte, error) {
+			return nil, nil
+		},
+	}
+	session := newTmuxSession("test-session", "claude", NewMockPtyFactory(t), cmdExec)
+
+	require.NoError(t, session.StartWindow("service-web", "/wt", "npm run dev"))
+	require.NoError(t, session.StopWindow("service-web"))
+	assert.Equal(t, []string{
+		"tmux new-window -d -t claudesquad_test-session: -n service-web -c /wt npm run dev ; set-option -w -t claudesquad_test-session:=service-web remain-on-exit on",
+		"tmux kill-window -t claudesquad_test-session:=service-web",
+	}, ran)
+
+	// Local sessions have nothing to forward
+	assert.NoError(t, session.ForwardPort(3000))
+}
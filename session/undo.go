@@ -0,0 +1,61 @@
+package session
+
+import (
+	"claude-squad/session/git"
+	"fmt"
+)
+
+// KilledInstance is what it takes to bring back a killed instance.
+type KilledInstance struct {
+	Data InstanceData
+	// Commit is the commit the instance's branch pointed at when the kill deleted it, to
+	// recreate it; it's empty if the branch was kept
+	Commit string
+	// Archive is the archive the instance was killed to, if it was archived
+	Archive *ArchiveEntry
+}
+
+// PrepareKill commits the instance's uncommitted changes and records what it takes to bring it
+// back after Kill deletes its branch. Instances on remote hosts can't be brought back.
+func (i *Instance) PrepareKill() (KilledInstance, error) {
+	killed := KilledInstance{Data: i.ToInstanceData()}
+	if !i.started || i.gitWorktree == nil {
+		return killed, fmt.Errorf("instance '%s' has no branch to restore", i.Title)
+	}
+	if i.Host != "" {
+		return killed, fmt.Errorf("killing instance '%s' on %s can't be undone", i.Title, i.Host)
+	}
+	if i.Status != Paused {
+		if err := i.commitDirtyChanges("killed"); err != nil {
+			return killed, err
+		}
+	}
+	commit, err := i.gitWorktree.BranchCommit()
+	if err != nil {
+		return killed, err
+	}
+	killed.Commit = commit
+	return killed, nil
+}
+
+// KeptBranch records what it takes to bring back the instance after KillKeepBranch, which keeps
+// its branch.
+func (i *Instance) KeptBranch() KilledInstance {
+	return KilledInstance{Data: i.ToInstanceData()}
+}
+
+// Restore recreates the killed instance as a paused instance titled title: from its archive,
+// on its kept branch, or on its branch recreated where it was.
+func (k KilledInstance) Restore(title string) (*Instance, error) {
+	if k.Archive != nil {
+		return RestoreArchive(*k.Archive, title)
+	}
+	branch := k.Data.Worktree.BranchName
+	if k.Commit != "" {
+		var err error
+		if branch, err = git.RestoreDeletedBranch(k.Data.Worktree.RepoPath, branch, k.Commit); err != nil {
+			return nil, err
+		}
+	}
+	return restoreInstance(k.Data, title, branch)
+}
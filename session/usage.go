@@ -0,0 +1,153 @@
+package session
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+// usageInterval is the least time between two reads of an agent's usage from its pane history,
+// which is expensive to capture.
+const usageInterval = 30 * time.Second
+
+// tokenCount matches a token count as agents print it: "1234", "12,345", "1.2k" or "3.4M".
+const tokenCount = `([0-9][0-9,]*(?:\.[0-9]+)?[kKmM]?)`
+
+var (
+	// aiderTokensRe matches the usage aider prints after each message ("Tokens: 2.4k sent, 1.2k
+	// cache write, 89 received. Cost: $0.0086 message, $0.0412 session.").
+	aiderTokensRe = regexp.MustCompile(`Tokens:\s*` + tokenCount + `\s*sent\b.*?` + tokenCount + `\s*received\b(?:.*?\$([0-9]+(?:\.[0-9]+)?)\s*session)?`)
+	// claudeCostRe matches the total Claude Code prints in reply to /cost and on exit
+	// ("Total cost: $0.0342"), which is followed by the tokens used per model.
+	claudeCostRe = regexp.MustCompile(`Total cost:\s*\$([0-9]+(?:\.[0-9]+)?)`)
+	// claudeTokensRe matches the tokens used by one model ("claude-sonnet: 12.3k input, 1.5k
+	// output, 45.6k cache read, 0 cache write").
+	claudeTokensRe = regexp.MustCompile(tokenCount + `\s*input,\s*` + tokenCount + `\s*output\b`)
+)
+
+// Usage is the tokens an agent used and what they cost, as far as the agent printed them.
+type Usage struct {
+	InputTokens  int `json:"input_tokens,omitempty"`
+	OutputTokens int `json:"output_tokens,omitempty"`
+	// Cost is the estimated cost in dollars
+	Cost float64 `json:"cost,omitempty"`
+}
+
+// Empty returns true if nothing is known about the usage.
+func (u Usage) Empty() bool {
+	return u.InputTokens == 0 && u.OutputTokens == 0 && u.Cost == 0
+}
+
+// Tokens returns the tokens used in total.
+func (u Usage) Tokens() int {
+	return u.InputTokens + u.OutputTokens
+}
+
+// Add returns the sum of two usages.
+func (u Usage) Add(other Usage) Usage {
+	return Usage{
+		InputTokens:  u.InputTokens + other.InputTokens,
+		OutputTokens: u.OutputTokens + other.OutputTokens,
+		Cost:         u.Cost + other.Cost,
+	}
+}
+
+// String describes the usage compactly, e.g. "13.7k tok $0.42".
+func (u Usage) String() string {
+	var parts []string
+	if tokens := u.Tokens(); tokens > 0 {
+		parts = append(parts, FormatTokens(tokens)+" tok")
+	}
+	if u.Cost > 0 {
+		parts = append(parts, fmt.Sprintf("$%.2f", u.Cost))
+	}
+	return strings.Join(parts, " ")
+}
+
+// FormatTokens formats a token count the way agents print them, e.g. "950", "13.7k" or "1.2M".
+func FormatTokens(tokens int) string {
+	switch {
+	case tokens >= 1_000_000:
+		return fmt.Sprintf("%.1fM", float64(tokens)/1_000_000)
+	case tokens >= 1_000:
+		return fmt.Sprintf("%.1fk", float64(tokens)/1_000)
+	}
+	return strconv.Itoa(tokens)
+}
+
+// parseTokenCount parses a count matched by tokenCount, or returns 0 if it isn't one.
+func parseTokenCount(s string) int {
+	multiplier := 1.0
+	switch s[len(s)-1] {
+	case 'k', 'K':
+		multiplier, s = 1_000, s[:len(s)-1]
+	case 'm', 'M':
+		multiplier, s = 1_000_000, s[:len(s)-1]
+	}
+	n, err := strconv.ParseFloat(strings.ReplaceAll(s, ",", ""), 64)
+	if err != nil {
+		return 0
+	}
+	return int(n * multiplier)
+}
+
+// ParseUsage returns the usage printed in an agent's pane content. aider prints the tokens of
+// each message, which are summed, and the session's cost so far; Claude Code prints totals, so
+// only its last report counts.
+func ParseUsage(content string) Usage {
+	var aider, claude Usage
+	for _, line := range strings.Split(ansi.Strip(content), "\n") {
+		if m := aiderTokensRe.FindStringSubmatch(line); m != nil {
+			aider.InputTokens += parseTokenCount(m[1])
+			aider.OutputTokens += parseTokenCount(m[2])
+			if cost, err := strconv.ParseFloat(m[3], 64); err == nil {
+				aider.Cost = cost
+			}
+			continue
+		}
+		if m := claudeCostRe.FindStringSubmatch(line); m != nil {
+			// A new report replaces the last one, tokens included
+			claude = Usage{}
+			claude.Cost, _ = strconv.ParseFloat(m[1], 64)
+			continue
+		}
+		if m := claudeTokensRe.FindStringSubmatch(line); m != nil {
+			claude.InputTokens += parseTokenCount(m[1])
+			claude.OutputTokens += parseTokenCount(m[2])
+		}
+	}
+	return aider.Add(claude)
+}
+
+// GetUsage returns the tokens the instance's agent used and their cost, as far as it printed
+// them.
+func (i *Instance) GetUsage() Usage {
+	i.metricsMu.Lock()
+	defer i.metricsMu.Unlock()
+	return i.metrics.Usage
+}
+
+// UpdateUsage reads the agent's usage from its pane history, at most every usageInterval. The
+// usage never goes down, so it survives the history being trimmed; a restarted agent that
+// counts from zero again only shows once it used more than before.
+func (i *Instance) UpdateUsage() {
+	if !i.started || i.Status == Paused || i.tmuxSession == nil || time.Since(i.usageReadAt) < usageInterval {
+		return
+	}
+	i.usageReadAt = time.Now()
+	history, err := i.PreviewFullHistory()
+	if err != nil {
+		return
+	}
+	parsed := ParseUsage(history)
+	i.metricsMu.Lock()
+	defer i.metricsMu.Unlock()
+	usage := &i.metrics.Usage
+	usage.InputTokens = max(usage.InputTokens, parsed.InputTokens)
+	usage.OutputTokens = max(usage.OutputTokens, parsed.OutputTokens)
+	usage.Cost = max(usage.Cost, parsed.Cost)
+}
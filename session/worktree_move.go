@@ -0,0 +1,49 @@
+package session
+
+import (
+	"claude-squad/log"
+	"fmt"
+	"time"
+)
+
+// MoveWorktree relocates a running instance's worktree to path with git worktree move, e.g. when
+// its disk runs out of space. The tmux session is stopped while the worktree moves and restarted
+// in its new location, since the processes in it were started in the old one, and so is its
+// container.
+func (i *Instance) MoveWorktree(path string) error {
+	if !i.started || i.Status == Paused {
+		return fmt.Errorf("only a running instance's worktree can be moved")
+	}
+	if i.Broken() {
+		return fmt.Errorf("the worktree of %s was deleted; repair it instead", i.Title)
+	}
+	if i.Host != "" {
+		return fmt.Errorf("the worktree of %s is on %s and can't be moved", i.Title, i.Host)
+	}
+
+	// Nothing may write to the worktree while it's copied to another disk
+	if err := i.tmuxSession.Close(); err != nil {
+		log.ErrorLog.Printf("failed to close tmux session before moving worktree: %v", err)
+	}
+	// A container keeps mounting the old path, so it's recreated with the new one
+	if err := i.removeContainer(); err != nil {
+		return err
+	}
+	// The hooks directory is named after the worktree, so it's installed again for the new path
+	i.removeHooks()
+	moveErr := i.gitWorktree.Move(path)
+	if err := i.startContainer(); err != nil {
+		return err
+	}
+	i.setProgramCommand()
+	if err := i.tmuxSession.ReloadSession(i.gitWorktree.GetWorktreePath()); err != nil {
+		return fmt.Errorf("failed to restart tmux session: %w", err)
+	}
+	i.SetStatus(Running)
+	if moveErr != nil {
+		return moveErr
+	}
+	i.diffStatsCache = nil
+	i.diffStatsCacheTime = time.Time{}
+	return nil
+}
@@ -0,0 +1,94 @@
+package session
+
+import (
+	"claude-squad/log"
+	"errors"
+	"fmt"
+	"os"
+)
+
+// ErrWorktreeMissing is returned for a running instance whose worktree directory was deleted
+// from outside claude-squad.
+var ErrWorktreeMissing = errors.New("worktree directory does not exist")
+
+// Broken returns true if the instance's worktree directory was found missing and hasn't been
+// repaired yet. Broken instances are skipped by status updates until they are repaired.
+func (i *Instance) Broken() bool {
+	return i.broken.Load()
+}
+
+// CheckWorktree checks that the worktree directory of a running instance still exists, marking
+// the instance broken if it doesn't. It returns true only when the instance has just broken, so
+// the caller can report it once.
+func (i *Instance) CheckWorktree() bool {
+	if i.Broken() {
+		return false
+	}
+	return i.checkWorktree() != nil
+}
+
+// checkWorktree returns an error wrapping ErrWorktreeMissing if the instance is broken or its
+// worktree directory has gone, marking it broken.
+func (i *Instance) checkWorktree() error {
+	// Worktrees on remote hosts aren't checked, to keep status updates off the network
+	if !i.started || i.Status == Paused || i.gitWorktree == nil || i.Host != "" {
+		return nil
+	}
+	path := i.gitWorktree.GetWorktreePath()
+	if i.Broken() {
+		return fmt.Errorf("%w: %s", ErrWorktreeMissing, path)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		return nil
+	}
+	if !i.broken.Swap(true) {
+		log.WarningLog.Printf("worktree of instance %s was deleted: %s", i.Title, path)
+	}
+	return fmt.Errorf("%w: %s", ErrWorktreeMissing, path)
+}
+
+// RepairWorktree recreates a broken instance's worktree from its branch and restarts its tmux
+// session there, since the processes in the old one were left in a deleted directory.
+func (i *Instance) RepairWorktree() error {
+	if !i.Broken() {
+		return fmt.Errorf("instance is not broken")
+	}
+	// Setup prunes the stale worktree entry before adding the worktree again
+	if err := i.gitWorktree.Setup(); err != nil {
+		return fmt.Errorf("failed to recreate git worktree: %w", err)
+	}
+	// The container mounted the deleted directory
+	if err := i.removeContainer(); err != nil {
+		return err
+	}
+	if err := i.startContainer(); err != nil {
+		return err
+	}
+	if err := i.tmuxSession.ReloadSession(i.gitWorktree.GetWorktreePath()); err != nil {
+		return fmt.Errorf("failed to restart tmux session: %w", err)
+	}
+	i.broken.Store(false)
+	i.SetStatus(Running)
+	return nil
+}
+
+// PauseBroken pauses a broken instance. Unlike Pause, it can't commit the lost changes; it
+// closes the tmux session and prunes the stale worktree entry, keeping the branch so the
+// instance can be resumed from what was last committed.
+func (i *Instance) PauseBroken() error {
+	if !i.Broken() {
+		return fmt.Errorf("instance is not broken")
+	}
+	if err := i.tmuxSession.Close(); err != nil {
+		log.ErrorLog.Printf("failed to close tmux session of broken instance: %v", err)
+	}
+	if err := i.removeContainer(); err != nil {
+		log.ErrorLog.Printf("failed to remove container of broken instance: %v", err)
+	}
+	if err := i.gitWorktree.Prune(); err != nil {
+		return fmt.Errorf("failed to prune git worktrees: %w", err)
+	}
+	i.broken.Store(false)
+	i.SetStatus(Paused)
+	return nil
+}
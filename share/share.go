@@ -0,0 +1,291 @@
+package share
+
+import (
+	"claude-squad/config"
+	"claude-squad/log"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"html/template"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"rsc.io/qr"
+)
+
+// Page is a snapshot of an instance's work published as a static HTML page.
+type Page struct {
+	Title   string
+	Branch  string
+	Repo    string
+	Added   int
+	Removed int
+	// Summary is an optional AI-written summary shown above the diff.
+	Summary string
+	// Diff is the raw unified diff.
+	Diff string
+}
+
+// Dir returns the directory published pages are written to.
+func Dir() (string, error) {
+	configDir, err := config.GetConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get config directory: %w", err)
+	}
+	return filepath.Join(configDir, "share"), nil
+}
+
+var unsafeNameChars = regexp.MustCompile(`[^a-zA-Z0-9_-]+`)
+
+// Publish renders the page to an HTML file in Dir and returns its file name. The name
+// includes a random token so links cannot be guessed from the instance title.
+func Publish(page Page) (string, error) {
+	dir, err := Dir()
+	if err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", fmt.Errorf("failed to create share directory: %w", err)
+	}
+
+	token := make([]byte, 8)
+	if _, err := rand.Read(token); err != nil {
+		return "", fmt.Errorf("failed to generate share token: %w", err)
+	}
+	name := fmt.Sprintf("%s-%s.html", unsafeNameChars.ReplaceAllString(page.Title, "-"), hex.EncodeToString(token))
+
+	f, err := os.Create(filepath.Join(dir, name))
+	if err != nil {
+		return "", fmt.Errorf("failed to create share page: %w", err)
+	}
+	defer f.Close()
+
+	if err := pageTemplate.Execute(f, templateData{Page: page, Generated: time.Now(), Lines: diffLines(page.Diff)}); err != nil {
+		return "", fmt.Errorf("failed to render share page: %w", err)
+	}
+	return name, nil
+}
+
+var (
+	serverMu   sync.Mutex
+	serverBase string
+)
+
+// probePath is answered with probeReply by every share server, to tell one from another program
+// listening on the same address.
+const (
+	probePath  = "/.claude-squad-share"
+	probeReply = "claude-squad share server"
+)
+
+// EnsureServer starts serving Dir on addr in the background if it is not already served and
+// returns the base URL of the server. An address already in use is only reused if another
+// claude-squad process (e.g. the daemon) is serving the pages there.
+func EnsureServer(addr string) (string, error) {
+	serverMu.Lock()
+	defer serverMu.Unlock()
+	if serverBase != "" {
+		return serverBase, nil
+	}
+
+	listener, err := net.Listen("tcp", addr)
+	if err != nil && !errors.Is(err, syscall.EADDRINUSE) {
+		return "", fmt.Errorf("failed to start share server on %s: %w", addr, err)
+	}
+	if listener == nil {
+		if !isShareServer(addr) {
+			return "", fmt.Errorf("failed to start share server: %s is in use by another program", addr)
+		}
+	} else {
+		handler, err := handler()
+		if err != nil {
+			listener.Close()
+			return "", err
+		}
+		go func() {
+			err := http.Serve(listener, handler)
+			log.ErrorLog.Printf("share server on %s stopped: %v", addr, err)
+			// The next share starts it again
+			serverMu.Lock()
+			serverBase = ""
+			serverMu.Unlock()
+		}()
+	}
+
+	serverBase = baseURL(addr)
+	return serverBase, nil
+}
+
+// isShareServer returns true if a share server answers on addr.
+func isShareServer(addr string) bool {
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		return false
+	}
+	if host == "" || host == "0.0.0.0" || host == "::" {
+		host = "localhost"
+	}
+	client := http.Client{Timeout: 2 * time.Second}
+	response, err := client.Get("http://" + net.JoinHostPort(host, port) + probePath)
+	if err != nil {
+		return false
+	}
+	defer response.Body.Close()
+	body, err := io.ReadAll(io.LimitReader(response.Body, 256))
+	return err == nil && response.StatusCode == http.StatusOK && string(body) == probeReply
+}
+
+// Serve serves Dir on addr until the listener fails. Used by the daemon to keep links alive
+// while the TUI is closed.
+func Serve(addr string) error {
+	h, err := handler()
+	if err != nil {
+		return err
+	}
+	return http.ListenAndServe(addr, h)
+}
+
+func handler() (http.Handler, error) {
+	dir, err := Dir()
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create share directory: %w", err)
+	}
+	fileServer := http.FileServer(http.Dir(dir))
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == probePath {
+			io.WriteString(w, probeReply)
+			return
+		}
+		// Only serve pages by name; never list the directory
+		if strings.HasSuffix(r.URL.Path, "/") {
+			http.NotFound(w, r)
+			return
+		}
+		fileServer.ServeHTTP(w, r)
+	}), nil
+}
+
+// baseURL returns the URL other machines can use to reach a server listening on addr.
+func baseURL(addr string) string {
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		return "http://" + addr
+	}
+	if host == "" || host == "0.0.0.0" || host == "::" {
+		if hostname, err := os.Hostname(); err == nil {
+			host = hostname
+		} else {
+			host = "localhost"
+		}
+	}
+	return "http://" + net.JoinHostPort(host, port)
+}
+
+// QRCode renders text as a QR code using half-block characters, two modules per row.
+func QRCode(text string) (string, error) {
+	code, err := qr.Encode(text, qr.L)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode QR code: %w", err)
+	}
+
+	// Include the quiet zone so phone cameras can find the code
+	const quiet = 2
+	var b strings.Builder
+	for y := -quiet; y < code.Size+quiet; y += 2 {
+		for x := -quiet; x < code.Size+quiet; x++ {
+			top, bottom := code.Black(x, y), code.Black(x, y+1)
+			switch {
+			case top && bottom:
+				b.WriteRune(' ')
+			case top:
+				b.WriteRune('▄')
+			case bottom:
+				b.WriteRune('▀')
+			default:
+				b.WriteRune('█')
+			}
+		}
+		b.WriteRune('\n')
+	}
+	return b.String(), nil
+}
+
+// diffLine is a line of the diff with the CSS class used to color it.
+type diffLine struct {
+	Class string
+	Text  string
+}
+
+func diffLines(diff string) []diffLine {
+	lines := strings.Split(strings.TrimRight(diff, "\n"), "\n")
+	result := make([]diffLine, 0, len(lines))
+	for _, line := range lines {
+		class := ""
+		switch {
+		case strings.HasPrefix(line, "diff --git"):
+			class = "file"
+		case strings.HasPrefix(line, "+++"), strings.HasPrefix(line, "---"), strings.HasPrefix(line, "index "):
+			class = "meta"
+		case strings.HasPrefix(line, "@@"):
+			class = "hunk"
+		case strings.HasPrefix(line, "+"):
+			class = "add"
+		case strings.HasPrefix(line, "-"):
+			class = "del"
+		}
+		result = append(result, diffLine{Class: class, Text: line})
+	}
+	return result
+}
+
+type templateData struct {
+	Page
+	Generated time.Time
+	Lines     []diffLine
+}
+
+var pageTemplate = template.Must(template.New("share").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>{{.Title}} - claude-squad</title>
+<style>
+body { font-family: -apple-system, sans-serif; margin: 2em; background: #fafafa; color: #1a1a1a; }
+h1 { margin-bottom: 0.2em; }
+.meta-info { color: #666; margin-bottom: 1.5em; }
+.add-count { color: #22863a; } .del-count { color: #cb2431; }
+.summary { background: #fff; border: 1px solid #ddd; border-radius: 6px; padding: 1em; white-space: pre-wrap; }
+pre.diff { background: #fff; border: 1px solid #ddd; border-radius: 6px; padding: 1em; overflow-x: auto; font-size: 13px; }
+pre.diff span { display: block; min-height: 1.2em; }
+.file { font-weight: bold; background: #f1f8ff; margin-top: 1em; }
+.meta { color: #666; } .hunk { color: #0ea5e9; }
+.add { background: #e6ffed; } .del { background: #ffeef0; }
+</style>
+</head>
+<body>
+<h1>{{.Title}}</h1>
+<div class="meta-info">
+{{.Repo}} &middot; <code>{{.Branch}}</code> &middot;
+<span class="add-count">+{{.Added}}</span> <span class="del-count">-{{.Removed}}</span> &middot;
+snapshot {{.Generated.Format "2006-01-02 15:04 MST"}}
+</div>
+{{if .Summary}}<h2>Summary</h2>
+<div class="summary">{{.Summary}}</div>
+{{end}}<h2>Diff</h2>
+{{if .Lines}}<pre class="diff">{{range .Lines}}<span class="{{.Class}}">{{.Text}}</span>{{end}}</pre>{{else}}<p>No changes.</p>{{end}}
+</body>
+</html>
+`))
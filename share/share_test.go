@@ -0,0 +1,78 @@
+package share
+
+import (
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiffLines(t *testing.T) {
+	diff := "diff --git a/x b/x\n--- a/x\n+++ b/x\n@@ -1 +1 @@\n-old\n+new\n ctx\n"
+	var classes []string
+	for _, line := range diffLines(diff) {
+		classes = append(classes, line.Class)
+	}
+	assert.Equal(t, []string{"file", "meta", "meta", "hunk", "del", "add", ""}, classes)
+}
+
+func TestBaseURL(t *testing.T) {
+	assert.Equal(t, "http://localhost:7433", baseURL("localhost:7433"))
+	assert.NotContains(t, baseURL("0.0.0.0:7433"), "0.0.0.0")
+}
+
+func TestEnsureServer(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	reset := func() {
+		serverMu.Lock()
+		serverBase = ""
+		serverMu.Unlock()
+	}
+	t.Cleanup(reset)
+
+	// Another program on the address isn't taken for a share server
+	other := httptest.NewServer(http.NotFoundHandler())
+	defer other.Close()
+	_, err := EnsureServer(other.Listener.Addr().String())
+	assert.ErrorContains(t, err, "in use by another program")
+
+	// Another claude-squad process serving the pages is reused
+	h, err := handler()
+	require.NoError(t, err)
+	shared := httptest.NewServer(h)
+	defer shared.Close()
+	base, err := EnsureServer(shared.Listener.Addr().String())
+	require.NoError(t, err)
+	assert.Equal(t, shared.URL, base)
+
+	// A free address is served from here
+	reset()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	addr := listener.Addr().String()
+	require.NoError(t, listener.Close())
+	base, err = EnsureServer(addr)
+	require.NoError(t, err)
+	response, err := http.Get(base + probePath)
+	require.NoError(t, err)
+	defer response.Body.Close()
+	body, err := io.ReadAll(response.Body)
+	require.NoError(t, err)
+	assert.Equal(t, probeReply, string(body))
+}
+
+func TestQRCode(t *testing.T) {
+	code, err := QRCode("http://localhost:7433/page.html")
+	require.NoError(t, err)
+	lines := strings.Split(strings.TrimRight(code, "\n"), "\n")
+	assert.Greater(t, len(lines), 10)
+	// Every row has the same width
+	for _, line := range lines {
+		assert.Equal(t, len([]rune(lines[0])), len([]rune(line)))
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"claude-squad/session/git"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/charmbracelet/bubbles/viewport"
 	"github.com/charmbracelet/lipgloss"
@@ -16,13 +17,27 @@ var (
 	HunkStyle     = lipgloss.NewStyle().Foreground(lipgloss.Color("#0ea5e9"))
 )
 
+// baseDiffRefresh is how long the diff against a chosen base is shown before it's run again.
+const baseDiffRefresh = 2 * time.Second
+
 type DiffMode int
 
 const (
 	DiffModeAll DiffMode = iota
 	DiffModeLastCommit
+	// DiffModeStaging shows staged and unstaged changes separately, to stage, unstage and discard
+	// them hunk by hunk
+	DiffModeStaging
 )
 
+// stagingHunk is a hunk shown in the staging view.
+type stagingHunk struct {
+	hunk   git.DiffHunk
+	staged bool
+	// start and end are the first line of the hunk and the line after it in the content
+	start, end int
+}
+
 type DiffPane struct {
 	viewport      viewport.Model
 	diff          string
@@ -30,15 +45,38 @@ type DiffPane struct {
 	width         int
 	height        int
 	filePositions []int // Line numbers where each file starts
+	fileNames     []string
+	hunkPositions []int // Line numbers of the first hunk of each file, -1 if it has none
 	mode          DiffMode
 	instance      *session.Instance
 	commitOffset  int // Offset from HEAD when viewing commits (0 = HEAD, 1 = HEAD~1, etc.)
+	// fileOffsets remembers the scroll offset within each file, relative to its header, so
+	// returning to a file restores where it was left
+	fileOffsets map[string]int
+	// layout wraps or pans lines wider than the pane
+	layout LineLayout
+	// sideBySide shows old and new lines in two columns instead of a unified diff
+	sideBySide bool
+	// content is the uncolored diff, which the side-by-side layout is built from
+	content string
+	// stagingHunks are the hunks of the staging view, in order
+	stagingHunks []stagingHunk
+	// lineRows is the row each line of the content is laid out on
+	lineRows []int
+	// pendingOffset is the line to scroll to once the diff is loaded
+	pendingOffset int
+	// baseRef, when set, is the branch, tag or commit the all-changes view diffs against instead
+	// of the branch's base; baseStats is its last diff, run at baseStatsAt
+	baseRef     string
+	baseStats   *git.DiffStats
+	baseStatsAt time.Time
 }
 
 func NewDiffPane() *DiffPane {
 	return &DiffPane{
-		viewport: viewport.New(0, 0),
-		mode:     DiffModeAll,
+		viewport:    viewport.New(0, 0),
+		mode:        DiffModeAll,
+		fileOffsets: make(map[string]int),
 	}
 }
 
@@ -49,11 +87,79 @@ func (d *DiffPane) SetSize(width, height int) {
 	d.viewport.Height = height
 	// Update viewport content if diff exists
 	if d.diff != "" || d.stats != "" {
-		d.viewport.SetContent(lipgloss.JoinVertical(lipgloss.Left, d.stats, d.diff))
+		d.applyLayout()
+	}
+}
+
+// applyLayout lays the diff out for the pane's width, keeping the scroll position when lines
+// are only panned.
+func (d *DiffPane) applyLayout() {
+	raw := lipgloss.JoinVertical(lipgloss.Left, d.stats, d.diff)
+	content, rows := d.layoutContent(raw)
+	offset := d.viewport.YOffset
+	if d.pendingOffset > 0 {
+		offset, d.pendingOffset = d.pendingOffset, 0
+	}
+	d.viewport.SetContent(content)
+	d.viewport.SetYOffset(offset)
+
+	// Headers are found on the original lines, since panning can cut off their prefix
+	d.parseFilePositions(raw)
+	d.lineRows = rows
+	d.filePositions = MapRows(d.filePositions, rows)
+	d.hunkPositions = MapRows(d.hunkPositions, rows)
+}
+
+// layoutContent lays out the stats line and the diff, side by side if asked and the pane is
+// wide enough. Both columns are rows of one viewport, so they scroll together.
+func (d *DiffPane) layoutContent(raw string) (string, []int) {
+	if d.sideBySide {
+		if split, splitRows, ok := sideBySideDiff(d.content, d.width, &d.layout); ok {
+			// The stats line comes first
+			rows := make([]int, 0, len(splitRows)+1)
+			rows = append(rows, 0)
+			for _, row := range splitRows {
+				rows = append(rows, row+1)
+			}
+			return d.stats + "\n" + split, rows
+		}
+	}
+	return d.layout.Apply(raw, d.width)
+}
+
+// ToggleWrap switches between soft-wrapping long lines and panning them horizontally.
+func (d *DiffPane) ToggleWrap() bool {
+	d.layout.ToggleWrap()
+	if d.diff != "" || d.stats != "" {
+		d.applyLayout()
+	}
+	return d.layout.Wrap
+}
+
+// ToggleSideBySide switches between a unified diff and old and new columns side by side.
+func (d *DiffPane) ToggleSideBySide() bool {
+	d.sideBySide = !d.sideBySide
+	d.layout.XOffset = 0
+	if d.diff != "" || d.stats != "" {
+		d.applyLayout()
+	}
+	return d.sideBySide
+}
+
+// Pan scrolls long lines horizontally by columns (negative pans left) when not wrapping.
+func (d *DiffPane) Pan(columns int) {
+	d.layout.Pan(columns)
+	if d.diff != "" || d.stats != "" {
+		d.applyLayout()
 	}
 }
 
 func (d *DiffPane) SetDiff(instance *session.Instance) {
+	if instance != d.instance {
+		d.fileOffsets = make(map[string]int)
+		d.pendingOffset = 0
+		d.baseRef, d.baseStats = "", nil
+	}
 	d.instance = instance
 	d.refreshDiff()
 }
@@ -71,12 +177,21 @@ func (d *DiffPane) refreshDiff() {
 		d.viewport.SetContent(centeredFallbackMessage)
 		return
 	}
+	if d.mode == DiffModeStaging {
+		d.refreshStaging(centeredFallbackMessage)
+		return
+	}
 
 	var stats *git.DiffStats
 	var modeLabel string
 
 	switch d.mode {
 	case DiffModeAll:
+		if d.baseRef != "" {
+			stats = d.diffAgainstBase()
+			modeLabel = fmt.Sprintf("[Against %s] ", strings.TrimPrefix(d.baseRef, "refs/tags/"))
+			break
+		}
 		stats = d.instance.GetDiffStats()
 		modeLabel = "[All Changes] "
 	case DiffModeLastCommit:
@@ -140,18 +255,121 @@ func (d *DiffPane) refreshDiff() {
 	if stats.IsEmpty() {
 		d.stats = ""
 		d.diff = ""
+		d.content = ""
 		d.viewport.SetContent(centeredFallbackMessage)
 	} else {
-		additions := AdditionStyle.Render(fmt.Sprintf("%d additions(+)", stats.Added))
-		deletions := DeletionStyle.Render(fmt.Sprintf("%d deletions(-)", stats.Removed))
-		d.stats = lipgloss.JoinHorizontal(lipgloss.Center, modeLabel, additions, " ", deletions)
-		d.diff = colorizeDiff(stats.Content)
-		content := lipgloss.JoinVertical(lipgloss.Left, d.stats, d.diff)
-		d.viewport.SetContent(content)
+		d.showStats(modeLabel, stats)
+	}
+}
+
+// diffAgainstBase returns the diff against the chosen base, running it again once it's older
+// than baseDiffRefresh.
+func (d *DiffPane) diffAgainstBase() *git.DiffStats {
+	if d.baseStats == nil || time.Since(d.baseStatsAt) > baseDiffRefresh {
+		d.baseStats = d.instance.GetDiffAgainst(d.baseRef)
+		d.baseStatsAt = time.Now()
+	}
+	return d.baseStats
+}
+
+// SetBaseRef shows all changes against ref, a branch, tag or commit, instead of the branch's
+// base. An empty ref goes back to the branch's base.
+func (d *DiffPane) SetBaseRef(ref string) {
+	d.baseRef, d.baseStats = ref, nil
+	d.fileOffsets = make(map[string]int)
+	if d.mode != DiffModeAll {
+		d.SetDiffMode(DiffModeAll)
+		return
+	}
+	d.refreshDiff()
+}
+
+// BaseRef returns the ref the all-changes view diffs against, or "" for the branch's base.
+func (d *DiffPane) BaseRef() string {
+	return d.baseRef
+}
+
+// showStats lays out the non-empty diff of stats under a line labeled modeLabel summarizing it.
+func (d *DiffPane) showStats(modeLabel string, stats *git.DiffStats) {
+	additions := AdditionStyle.Render(fmt.Sprintf("%d additions(+)", stats.Added))
+	deletions := DeletionStyle.Render(fmt.Sprintf("%d deletions(-)", stats.Removed))
+	d.stats = lipgloss.JoinHorizontal(lipgloss.Center, modeLabel, additions, " ", deletions)
+	d.diff = colorizeDiff(stats.Content)
+	d.content = stats.Content
+	d.applyLayout()
+}
+
+// refreshStaging shows the staged changes above the unstaged ones.
+func (d *DiffPane) refreshStaging(fallback string) {
+	staged, unstaged, err := d.instance.GetStagingDiff()
+	if err != nil {
+		d.viewport.SetContent(lipgloss.Place(d.width, d.height, lipgloss.Center, lipgloss.Center, fmt.Sprintf("Error: %v", err)))
+		return
+	}
+	d.stagingHunks = nil
+	if staged == "" && unstaged == "" {
+		d.stats = ""
+		d.diff = ""
+		d.content = ""
+		d.viewport.SetContent(fallback)
+		return
+	}
+
+	content, hunks := stagingContent(staged, unstaged)
+	d.stagingHunks = hunks
+
+	var stagedCount int
+	for _, hunk := range d.stagingHunks {
+		if hunk.staged {
+			stagedCount++
+		}
+	}
+	d.stats = lipgloss.JoinHorizontal(lipgloss.Center, "[Staging] ",
+		AdditionStyle.Render(fmt.Sprintf("%d staged", stagedCount)), " ",
+		DeletionStyle.Render(fmt.Sprintf("%d unstaged", len(d.stagingHunks)-stagedCount)), " hunks")
+	d.content = content
+	d.diff = colorizeDiff(d.content)
+	d.applyLayout()
+}
 
-		// Parse file positions after setting content
-		d.parseFilePositions(content)
+// stagingContent lists the staged changes above the unstaged ones under section titles, along with
+// the hunks of both. Hunk lines count the stats line shown above the content.
+func stagingContent(staged, unstaged string) (string, []stagingHunk) {
+	var content strings.Builder
+	var hunks []stagingHunk
+	addSection := func(title, diff string, isStaged bool) {
+		sectionHunks := git.ParseHunks(diff)
+		fmt.Fprintf(&content, "── %s (%d hunks) ──\n", title, len(sectionHunks))
+		offset := strings.Count(content.String(), "\n") + 1
+		for _, hunk := range sectionHunks {
+			start := offset + hunk.Line
+			hunks = append(hunks, stagingHunk{
+				hunk:   hunk,
+				staged: isStaged,
+				start:  start,
+				end:    start + strings.Count(hunk.Body, "\n"),
+			})
+		}
+		content.WriteString(diff)
 	}
+	addSection("Staged", staged, true)
+	addSection("Unstaged", unstaged, false)
+	return content.String(), hunks
+}
+
+// SelectedHunk returns the hunk at the top of the staging view and whether it is staged. ok is
+// false outside the staging view or when it has no hunks.
+func (d *DiffPane) SelectedHunk() (hunk git.DiffHunk, staged bool, ok bool) {
+	if d.mode != DiffModeStaging {
+		return git.DiffHunk{}, false, false
+	}
+	for _, h := range d.stagingHunks {
+		// The first hunk that hasn't scrolled out of view entirely
+		if MapRows([]int{h.end}, d.lineRows)[0] > d.viewport.YOffset {
+			return h.hunk, h.staged, true
+		}
+	}
+	return git.DiffHunk{}, false, false
 }
 
 func (d *DiffPane) String() string {
@@ -168,6 +386,25 @@ func (d *DiffPane) ScrollDown() {
 	d.viewport.LineDown(1)
 }
 
+// ScrollBy scrolls the viewport by the given number of lines (negative scrolls up)
+func (d *DiffPane) ScrollBy(lines int) {
+	scrollViewport(&d.viewport, lines)
+}
+
+// ScrollOffset returns the line the diff is scrolled to.
+func (d *DiffPane) ScrollOffset() int {
+	return d.viewport.YOffset
+}
+
+// SetScrollOffset scrolls the diff to line offset, or once it is loaded if it isn't yet.
+func (d *DiffPane) SetScrollOffset(offset int) {
+	if d.diff == "" && d.stats == "" {
+		d.pendingOffset = offset
+		return
+	}
+	d.viewport.SetYOffset(offset)
+}
+
 // ScrollToTop scrolls the viewport to the top
 func (d *DiffPane) ScrollToTop() {
 	d.viewport.GotoTop()
@@ -188,17 +425,63 @@ func (d *DiffPane) PageDown() {
 	d.viewport.ViewDown()
 }
 
-// parseFilePositions identifies line numbers where each file starts in the diff
+// parseFilePositions identifies line numbers where each file and its first hunk start in the diff
 func (d *DiffPane) parseFilePositions(content string) {
 	d.filePositions = []int{}
+	d.fileNames = []string{}
+	d.hunkPositions = []int{}
 	lines := strings.Split(content, "\n")
 
 	for i, line := range lines {
 		// Look for diff headers that indicate a new file
 		if strings.HasPrefix(line, "diff --git") {
+			name := ""
+			if parts := strings.Fields(line); len(parts) >= 4 {
+				name = strings.TrimPrefix(parts[3], "b/")
+			}
 			d.filePositions = append(d.filePositions, i)
+			d.fileNames = append(d.fileNames, name)
+			d.hunkPositions = append(d.hunkPositions, -1)
+		} else if n := len(d.hunkPositions); n > 0 && d.hunkPositions[n-1] < 0 && strings.HasPrefix(ansiEscape.ReplaceAllString(line, ""), "@@") {
+			d.hunkPositions[n-1] = i
+		}
+	}
+}
+
+// currentFileIndex returns the index of the file at the top of the viewport, or -1 if the
+// viewport is above the first file.
+func (d *DiffPane) currentFileIndex() int {
+	current := -1
+	for i, pos := range d.filePositions {
+		if pos > d.viewport.YOffset {
+			break
 		}
+		current = i
+	}
+	return current
+}
+
+// jumpToFile scrolls to file idx, remembering the position in the file being left. A file that
+// was visited before is restored to where it was left; otherwise the view lands on its first
+// hunk, keeping the "+++" line above it so the file name stays visible.
+func (d *DiffPane) jumpToFile(idx int) {
+	if current := d.currentFileIndex(); current >= 0 {
+		d.fileOffsets[d.fileNames[current]] = d.viewport.YOffset - d.filePositions[current]
 	}
+
+	start := d.filePositions[idx]
+	end := d.viewport.TotalLineCount()
+	if idx+1 < len(d.filePositions) {
+		end = d.filePositions[idx+1]
+	}
+
+	target := start
+	if offset, ok := d.fileOffsets[d.fileNames[idx]]; ok && start+offset < end {
+		target = start + offset
+	} else if hunk := d.hunkPositions[idx]; hunk > start {
+		target = hunk - 1
+	}
+	d.viewport.SetYOffset(target)
 }
 
 // JumpToNextFile jumps to the next file in the diff
@@ -207,17 +490,10 @@ func (d *DiffPane) JumpToNextFile() {
 		return
 	}
 
-	currentOffset := d.viewport.YOffset
-
-	// Find the next file position after the current offset
-	for _, pos := range d.filePositions {
-		if pos > currentOffset {
-			d.viewport.SetYOffset(pos)
-			return
-		}
-	}
-
 	// If no next file, stay at current position
+	if next := d.currentFileIndex() + 1; next < len(d.filePositions) {
+		d.jumpToFile(next)
+	}
 }
 
 // JumpToPrevFile jumps to the previous file in the diff
@@ -226,19 +502,12 @@ func (d *DiffPane) JumpToPrevFile() {
 		return
 	}
 
-	currentOffset := d.viewport.YOffset
-
-	// Find the previous file position before the current offset
-	for i := len(d.filePositions) - 1; i >= 0; i-- {
-		if d.filePositions[i] < currentOffset {
-			d.viewport.SetYOffset(d.filePositions[i])
-			return
-		}
-	}
-
-	// If no previous file, jump to the first file
-	if currentOffset > d.filePositions[0] {
-		d.viewport.SetYOffset(d.filePositions[0])
+	// Past the first file there is nothing to go back to; above it, jump to the first file
+	current := d.currentFileIndex()
+	if current > 0 {
+		d.jumpToFile(current - 1)
+	} else if current < 0 {
+		d.jumpToFile(0)
 	}
 }
 
@@ -251,6 +520,7 @@ func (d *DiffPane) SetDiffMode(mode DiffMode) {
 		} else {
 			d.commitOffset = 0
 		}
+		d.fileOffsets = make(map[string]int)
 		d.refreshDiff()
 	}
 }
@@ -260,10 +530,18 @@ func (d *DiffPane) GetDiffMode() DiffMode {
 	return d.mode
 }
 
-// NavigateToPrevCommit moves to the previous (older) commit
+// NavigateToPrevCommit moves to the previous (older) commit, stopping at the first commit.
+// Older commits are loaded as they are reached.
 func (d *DiffPane) NavigateToPrevCommit() {
 	if d.mode == DiffModeLastCommit {
+		if d.instance == nil {
+			return
+		}
+		if _, _, err := d.instance.GetCommitInfo(d.commitOffset + 1); err != nil {
+			return
+		}
 		d.commitOffset++
+		d.fileOffsets = make(map[string]int)
 		d.refreshDiff()
 	}
 }
@@ -274,35 +552,10 @@ func (d *DiffPane) GetCurrentFile() string {
 		return ""
 	}
 
-	// Get the current line we're looking at in the viewport
-	currentLine := d.viewport.YOffset
-	lines := strings.Split(d.diff, "\n")
-
-	// Search backwards from current position to find the most recent file header
-	for i := currentLine; i >= 0; i-- {
-		if i < len(lines) {
-			line := lines[i]
-			// Look for diff header
-			if strings.HasPrefix(line, "diff --git") {
-				parts := strings.Fields(line)
-				if len(parts) >= 4 {
-					// Remove the "a/" prefix from the file path
-					filePath := strings.TrimPrefix(parts[2], "a/")
-					return filePath
-				}
-			}
-			// Also check for +++ header which is more reliable
-			if strings.HasPrefix(line, "+++") && !strings.HasPrefix(line, "+++ /dev/null") {
-				// Format is "+++ b/path/to/file"
-				parts := strings.Fields(line)
-				if len(parts) >= 2 {
-					filePath := strings.TrimPrefix(parts[1], "b/")
-					return filePath
-				}
-			}
-		}
+	// File headers are tracked on the laid-out lines, so this holds while wrapping too
+	if current := d.currentFileIndex(); current >= 0 {
+		return d.fileNames[current]
 	}
-
 	return ""
 }
 
@@ -315,12 +568,14 @@ func (d *DiffPane) NavigateToNextCommit() {
 			stats := d.instance.GetCommitDiffAtOffset(-1)
 			if stats != nil && stats.IsUncommitted && !stats.IsEmpty() {
 				d.commitOffset = -1
+				d.fileOffsets = make(map[string]int)
 				d.refreshDiff()
 			}
 			// Otherwise stay at 0 (HEAD)
 		} else if d.commitOffset > 0 {
 			// Normal navigation to newer commits
 			d.commitOffset--
+			d.fileOffsets = make(map[string]int)
 			d.refreshDiff()
 		}
 	}
@@ -0,0 +1,212 @@
+package ui
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+)
+
+var (
+	// DeletionHighlightStyle marks the part of a deleted line that changed
+	DeletionHighlightStyle = DeletionStyle.Background(lipgloss.Color("#4c1d1d"))
+	// AdditionHighlightStyle marks the part of an added line that changed
+	AdditionHighlightStyle = AdditionStyle.Background(lipgloss.Color("#14361f"))
+	lineNumberStyle        = lipgloss.NewStyle().Foreground(lipgloss.Color("#6b7280"))
+	// splitHunkRe matches both ranges of a hunk header, e.g. "@@ -12,3 +12,5 @@"
+	splitHunkRe = regexp.MustCompile(`^@@ -(\d+)(?:,(\d+))? \+(\d+)(?:,(\d+))? @@`)
+)
+
+// splitSeparator divides the old and new columns.
+const splitSeparator = " │ "
+
+// minSplitTextWidth is the narrowest column text the side-by-side layout is used for.
+const minSplitTextWidth = 12
+
+// splitLine is one side of a row: a line of the old or new file.
+type splitLine struct {
+	number int
+	text   string
+	style  lipgloss.Style
+	// highlight is the style of the changed runes from start to end
+	highlight  lipgloss.Style
+	start, end int
+}
+
+// sideBySideDiff lays a unified diff out as old and new columns in a pane width columns wide,
+// pairing each run of deleted lines with the added lines that follow it. Long lines are wrapped
+// or panned within their column as layout asks. It also returns the row each line of diff
+// starts on, like LineLayout.Apply, and false if the pane is too narrow for two columns.
+func sideBySideDiff(diff string, width int, layout *LineLayout) (string, []int, bool) {
+	lines := strings.Split(diff, "\n")
+	numWidth := lineNumberWidth(lines)
+	colWidth := (width - ansi.StringWidth(splitSeparator)) / 2
+	textWidth := colWidth - numWidth - 1
+	if textWidth < minSplitTextWidth {
+		return "", nil, false
+	}
+
+	if !layout.Wrap {
+		widest := 0
+		for _, line := range lines {
+			widest = max(widest, ansi.StringWidth(expandTabs(line))-1)
+		}
+		layout.XOffset = max(0, min(layout.XOffset, widest-textWidth))
+	}
+
+	var out []string
+	rows := make([]int, len(lines))
+	var deleted, added []int
+	oldNum, newNum := 0, 0
+	inHunk := false
+
+	cell := func(line *splitLine) []string {
+		blank := strings.Repeat(" ", colWidth)
+		if line == nil {
+			return []string{blank}
+		}
+		text := expandTabs(line.text)
+		runes := []rune(text)
+		start, end := min(line.start, len(runes)), min(line.end, len(runes))
+		styled := line.style.Render(string(runes[:start])) + line.highlight.Render(string(runes[start:end])) +
+			line.style.Render(string(runes[end:]))
+		var parts []string
+		if layout.Wrap {
+			parts = strings.Split(ansi.Wrap(styled, textWidth, ""), "\n")
+		} else {
+			parts = []string{ansi.Cut(styled, layout.XOffset, layout.XOffset+textWidth)}
+		}
+		cells := make([]string, len(parts))
+		for i, part := range parts {
+			gutter := strings.Repeat(" ", numWidth)
+			if i == 0 {
+				gutter = lineNumberStyle.Render(fmt.Sprintf("%*d", numWidth, line.number))
+			}
+			cells[i] = gutter + " " + part + strings.Repeat(" ", max(0, textWidth-ansi.StringWidth(part)))
+		}
+		return cells
+	}
+	emit := func(left, right *splitLine) {
+		leftCells, rightCells := cell(left), cell(right)
+		for i := 0; i < max(len(leftCells), len(rightCells)); i++ {
+			l, r := strings.Repeat(" ", colWidth), strings.Repeat(" ", colWidth)
+			if i < len(leftCells) {
+				l = leftCells[i]
+			}
+			if i < len(rightCells) {
+				r = rightCells[i]
+			}
+			out = append(out, l+splitSeparator+r)
+		}
+	}
+	flush := func() {
+		for k := 0; k < max(len(deleted), len(added)); k++ {
+			var left, right *splitLine
+			if k < len(deleted) {
+				left = &splitLine{number: oldNum, text: lines[deleted[k]][1:], style: DeletionStyle, highlight: DeletionHighlightStyle}
+				oldNum++
+				rows[deleted[k]] = len(out)
+			}
+			if k < len(added) {
+				right = &splitLine{number: newNum, text: lines[added[k]][1:], style: AdditionStyle, highlight: AdditionHighlightStyle}
+				newNum++
+				rows[added[k]] = len(out)
+			}
+			// Only lines replacing each other have a changed part worth highlighting
+			if left != nil && right != nil {
+				left.start, left.end, right.start, right.end = changedRange(expandTabs(left.text), expandTabs(right.text))
+			}
+			emit(left, right)
+		}
+		deleted, added = nil, nil
+	}
+	fullRow := func(i int, line string, style lipgloss.Style) {
+		rows[i] = len(out)
+		if layout.Wrap && ansi.StringWidth(line) > width {
+			for _, part := range strings.Split(ansi.Wrap(line, width, ""), "\n") {
+				out = append(out, style.Render(part))
+			}
+			return
+		}
+		out = append(out, style.Render(ansi.Truncate(line, width, "")))
+	}
+
+	for i, line := range lines {
+		switch {
+		case inHunk && strings.HasPrefix(line, "-"):
+			if len(added) > 0 {
+				flush()
+			}
+			deleted = append(deleted, i)
+		case inHunk && strings.HasPrefix(line, "+"):
+			added = append(added, i)
+		case inHunk && strings.HasPrefix(line, " "):
+			flush()
+			rows[i] = len(out)
+			context := lipgloss.NewStyle()
+			emit(&splitLine{number: oldNum, text: line[1:], style: context, highlight: context},
+				&splitLine{number: newNum, text: line[1:], style: context, highlight: context})
+			oldNum++
+			newNum++
+		case strings.HasPrefix(line, "@@"):
+			flush()
+			if match := splitHunkRe.FindStringSubmatch(line); match != nil {
+				oldNum, _ = strconv.Atoi(match[1])
+				newNum, _ = strconv.Atoi(match[3])
+				inHunk = true
+			}
+			fullRow(i, line, HunkStyle)
+		default:
+			flush()
+			if strings.HasPrefix(line, "diff --git") {
+				inHunk = false
+			}
+			fullRow(i, line, lipgloss.NewStyle())
+		}
+	}
+	flush()
+	return strings.Join(out, "\n"), rows, true
+}
+
+// lineNumberWidth returns the digits needed for the largest line number in a diff's hunks.
+func lineNumberWidth(lines []string) int {
+	largest := 0
+	for _, line := range lines {
+		match := splitHunkRe.FindStringSubmatch(line)
+		if match == nil {
+			continue
+		}
+		for _, pair := range [][2]string{{match[1], match[2]}, {match[3], match[4]}} {
+			start, _ := strconv.Atoi(pair[0])
+			count := 1
+			if pair[1] != "" {
+				count, _ = strconv.Atoi(pair[1])
+			}
+			largest = max(largest, start+count)
+		}
+	}
+	return max(3, len(strconv.Itoa(largest)))
+}
+
+// changedRange returns the rune ranges of a deleted line and the added line replacing it that
+// differ, between their common prefix and common suffix.
+func changedRange(deleted, added string) (delStart, delEnd, addStart, addEnd int) {
+	a, b := []rune(deleted), []rune(added)
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+	return prefix, len(a) - suffix, prefix, len(b) - suffix
+}
+
+// expandTabs replaces tabs with spaces so column widths can be measured.
+func expandTabs(line string) string {
+	return strings.ReplaceAll(line, "\t", "    ")
+}
@@ -0,0 +1,160 @@
+package ui
+
+import (
+	"claude-squad/session"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// newTestDiffPane returns a diff pane showing three files of 30 changed lines each.
+func newTestDiffPane() *DiffPane {
+	var lines []string
+	lines = append(lines, "stats")
+	for _, name := range []string{"a.go", "b.go", "c.go"} {
+		lines = append(lines,
+			fmt.Sprintf("diff --git a/%s b/%s", name, name),
+			"index 123..456 100644",
+			"--- a/"+name,
+			"+++ b/"+name,
+			HunkStyle.Render("@@ -1,3 +1,30 @@"),
+		)
+		for i := 0; i < 30; i++ {
+			lines = append(lines, AdditionStyle.Render(fmt.Sprintf("+line %d", i)))
+		}
+	}
+	content := strings.Join(lines, "\n")
+
+	d := NewDiffPane()
+	d.SetSize(80, 10)
+	d.viewport.SetContent(content)
+	d.parseFilePositions(content)
+	return d
+}
+
+func TestDiffPaneJumpToFirstHunk(t *testing.T) {
+	d := newTestDiffPane()
+	assert.Equal(t, []int{1, 36, 71}, d.filePositions)
+	assert.Equal(t, []int{5, 40, 75}, d.hunkPositions)
+
+	// Lands on the "+++" line right above the first hunk
+	d.JumpToNextFile()
+	assert.Equal(t, 4, d.viewport.YOffset)
+	d.JumpToNextFile()
+	assert.Equal(t, 39, d.viewport.YOffset)
+}
+
+func TestDiffPaneRemembersFilePosition(t *testing.T) {
+	d := newTestDiffPane()
+	d.JumpToNextFile()
+	d.ScrollBy(12)
+	assert.Equal(t, 16, d.viewport.YOffset)
+
+	d.JumpToNextFile()
+	d.ScrollBy(3)
+	assert.Equal(t, 42, d.viewport.YOffset)
+
+	// Returning restores each file's position
+	d.JumpToPrevFile()
+	assert.Equal(t, 16, d.viewport.YOffset)
+	d.JumpToNextFile()
+	assert.Equal(t, 42, d.viewport.YOffset)
+}
+
+func TestSideBySideDiff(t *testing.T) {
+	diff := strings.Join([]string{
+		"diff --git a/a.go b/a.go",
+		"--- a/a.go",
+		"+++ b/a.go",
+		"@@ -8,3 +8,3 @@ func f() {",
+		" \tx := 1",
+		"-\treturn x + 1",
+		"+\treturn x + 2",
+		"+\t// added",
+		"",
+	}, "\n")
+
+	layout := LineLayout{}
+	content, rows, ok := sideBySideDiff(diff, 80, &layout)
+	assert.True(t, ok)
+	lines := strings.Split(ansiEscape.ReplaceAllString(content, ""), "\n")
+	// The replaced line and its replacement share a row; the extra addition gets its own
+	assert.Equal(t, []int{0, 1, 2, 3, 4, 5, 5, 6, 7}, rows)
+	assert.Equal(t, "  8     x := 1", strings.TrimRight(lines[4][:38], " "))
+	assert.Contains(t, lines[5], "  9     return x + 1")
+	assert.Contains(t, lines[5], "│   9     return x + 2")
+	assert.True(t, strings.HasPrefix(strings.TrimSpace(lines[6]), "│  10     // added"))
+
+	_, _, ok = sideBySideDiff(diff, 30, &layout)
+	assert.False(t, ok, "too narrow for two columns")
+
+	start, end, _, _ := changedRange("return x + 1", "return x + 2")
+	assert.Equal(t, []int{11, 12}, []int{start, end})
+}
+
+func TestDiffPaneSelectedHunk(t *testing.T) {
+	diff := "diff --git a/a.go b/a.go\n--- a/a.go\n+++ b/a.go\n@@ -1 +1 @@\n-x\n+y\n"
+	content, hunks := stagingContent(diff, strings.ReplaceAll(diff, "a.go", "b.go"))
+	assert.Len(t, hunks, 2)
+	assert.Equal(t, 5, hunks[0].start, "after the stats line, section title and file header")
+	assert.Equal(t, 12, hunks[1].start)
+
+	d := NewDiffPane()
+	d.SetSize(80, 3)
+	d.mode = DiffModeStaging
+	d.stagingHunks = hunks
+	d.stats = "[Staging]"
+	d.content = content
+	d.diff = colorizeDiff(content)
+	d.applyLayout()
+
+	hunk, staged, ok := d.SelectedHunk()
+	assert.True(t, ok)
+	assert.True(t, staged, "the staged hunk is first")
+	assert.Equal(t, "a.go", hunk.Path)
+
+	// Once the staged hunk has scrolled past the top, the unstaged one is selected
+	d.viewport.SetYOffset(8)
+	hunk, staged, ok = d.SelectedHunk()
+	assert.True(t, ok)
+	assert.False(t, staged)
+	assert.Equal(t, "b.go", hunk.Path)
+}
+
+func TestDiffPaneScrollOffset(t *testing.T) {
+	lines := make([]string, 50)
+	for i := range lines {
+		lines[i] = fmt.Sprintf("+line %d", i)
+	}
+	d := NewDiffPane()
+	d.SetSize(80, 10)
+
+	// An offset set before the diff is loaded is applied once it is
+	d.SetScrollOffset(20)
+	assert.Equal(t, 0, d.ScrollOffset())
+	d.diff = strings.Join(lines, "\n")
+	d.applyLayout()
+	assert.Equal(t, 20, d.ScrollOffset())
+
+	d.SetScrollOffset(5)
+	assert.Equal(t, 5, d.ScrollOffset())
+	d.applyLayout()
+	assert.Equal(t, 5, d.ScrollOffset(), "the offset is only restored once")
+}
+
+func TestDiffPaneBaseRef(t *testing.T) {
+	d := NewDiffPane()
+	d.SetSize(80, 10)
+	d.SetDiffMode(DiffModeLastCommit)
+
+	// Choosing a base shows all changes against it
+	d.SetBaseRef("refs/tags/v1")
+	assert.Equal(t, DiffModeAll, d.GetDiffMode())
+	assert.Equal(t, "refs/tags/v1", d.BaseRef())
+
+	// Another instance is diffed against its own branch's base
+	d.SetDiff(&session.Instance{Title: "other"})
+	assert.Empty(t, d.BaseRef())
+}
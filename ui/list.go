@@ -3,6 +3,7 @@ package ui
 import (
 	"claude-squad/log"
 	"claude-squad/session"
+	"claude-squad/session/git"
 	"errors"
 	"fmt"
 	"strings"
@@ -13,6 +14,9 @@ import (
 
 const readyIcon = "● "
 const pausedIcon = "⏸ "
+const brokenIcon = "✗ "
+const renamedIcon = "⚠ "
+const finishedIcon = "✔ "
 
 var readyStyle = lipgloss.NewStyle().
 	Foreground(lipgloss.AdaptiveColor{Light: "#51bd73", Dark: "#51bd73"})
@@ -26,6 +30,17 @@ var removedLinesStyle = lipgloss.NewStyle().
 var pausedStyle = lipgloss.NewStyle().
 	Foreground(lipgloss.AdaptiveColor{Light: "#888888", Dark: "#888888"})
 
+var brokenStyle = lipgloss.NewStyle().
+	Foreground(lipgloss.Color("#de613e"))
+
+var renamedStyle = lipgloss.NewStyle().
+	Bold(true).
+	Foreground(lipgloss.AdaptiveColor{Light: "#d19a00", Dark: "#ffcc00"})
+
+var finishedStyle = lipgloss.NewStyle().
+	Bold(true).
+	Foreground(lipgloss.AdaptiveColor{Light: "#d19a00", Dark: "#ffcc00"})
+
 var titleStyle = lipgloss.NewStyle().
 	Padding(1, 1, 0, 1).
 	Foreground(lipgloss.AdaptiveColor{Light: "#1a1a1a", Dark: "#dddddd"})
@@ -62,6 +77,16 @@ type List struct {
 	// map of repo name to number of instances using it. Used to display the repo name only if there are
 	// multiple repos in play.
 	repos map[string]int
+
+	// groups the instances are organized in, in the order they're shown. Instances are kept
+	// sorted by group, ungrouped ones last.
+	groups []session.Group
+	// selectedHeader is the index of the group whose header is selected, or -1 if an instance is
+	selectedHeader int
+
+	// filter narrows the list to the instances matching it; filterEditing is true while it's typed
+	filter        string
+	filterEditing bool
 }
 
 func NewList(spinner *spinner.Model, autoYes bool) *List {
@@ -70,6 +95,8 @@ func NewList(spinner *spinner.Model, autoYes bool) *List {
 		renderer: &InstanceRenderer{spinner: spinner},
 		repos:    make(map[string]int),
 		autoyes:  autoYes,
+
+		selectedHeader: -1,
 	}
 }
 
@@ -127,29 +154,42 @@ func (r *InstanceRenderer) Render(i *session.Instance, idx int, selected bool, h
 
 	// add spinner next to title if it's running
 	var join string
-	switch i.Status {
-	case session.Running:
+	switch {
+	case i.Broken():
+		// The worktree was deleted, so the status is stale
+		join = brokenStyle.Render(brokenIcon)
+	case i.Finished() != "" && i.Status != session.Paused:
+		// Something finished that hasn't been looked at yet
+		join = finishedStyle.Render(finishedIcon)
+	case i.Status == session.Running:
 		join = fmt.Sprintf("%s ", r.spinner.View())
-	case session.Ready:
+	case i.Status == session.Ready:
 		join = readyStyle.Render(readyIcon)
-	case session.Paused:
+	case i.Status == session.Paused:
 		join = pausedStyle.Render(pausedIcon)
-	case session.Creating:
+	case i.Status == session.Creating:
 		join = fmt.Sprintf("%s ", r.spinner.View())
-	case session.Deleting:
+	case i.Status == session.Deleting:
 		join = fmt.Sprintf("%s ", r.spinner.View())
 	default:
 	}
 
+	usage, usageWidth := renderResourceUsage(i, titleS)
+	// The usage gives way to the title on narrow lists
+	if usageWidth > r.width/2 {
+		usage, usageWidth = "", 0
+	}
+
 	// Cut the title if it's too long
 	titleText := i.Title
-	widthAvail := r.width - 3 - len(prefix) - 1
+	widthAvail := r.width - 3 - len(prefix) - 1 - usageWidth
 	if widthAvail > 0 && widthAvail < len(titleText) && len(titleText) >= widthAvail-3 {
 		titleText = titleText[:widthAvail-3] + "..."
 	}
 	title := titleS.Render(lipgloss.JoinHorizontal(
 		lipgloss.Left,
-		lipgloss.Place(r.width-3, 1, lipgloss.Left, lipgloss.Center, fmt.Sprintf("%s %s", prefix, titleText)),
+		lipgloss.Place(r.width-3-usageWidth, 1, lipgloss.Left, lipgloss.Center, fmt.Sprintf("%s %s", prefix, titleText)),
+		usage,
 		" ",
 		join,
 	))
@@ -174,6 +214,8 @@ func (r *InstanceRenderer) Render(i *session.Instance, idx int, selected bool, h
 		)
 	}
 
+	prBadge, prBadgeWidth := renderPRBadge(i.GetPRStatus(), i.CIState(), i.NewCommentCount(), i.BehindMain(), descS)
+
 	remainingWidth := r.width
 	remainingWidth -= len(prefix)
 	remainingWidth -= len(branchIcon)
@@ -186,7 +228,24 @@ func (r *InstanceRenderer) Render(i *session.Instance, idx int, selected bool, h
 	// Use fixed width for diff stats to avoid layout issues
 	remainingWidth -= diffWidth
 
+	// The PR badge gives way to the diff stats if there isn't room for both
+	if prBadgeWidth > remainingWidth {
+		prBadge, prBadgeWidth = "", 0
+	}
+	remainingWidth -= prBadgeWidth
+
 	branch := i.Branch
+	// A branch renamed because the one asked for was checked out elsewhere gets a warning until
+	// its pushes are retargeted, after which the refspec shows where they go
+	renamed := ""
+	if i.RenamedFrom() != "" {
+		if push := i.PushBranch(); push != i.Branch {
+			branch += ":" + push
+		} else {
+			renamed = renamedStyle.Background(descS.GetBackground()).Render(renamedIcon)
+			remainingWidth -= lipgloss.Width(renamedIcon)
+		}
+	}
 	if i.Started() && hasMultipleRepos {
 		repoName, err := i.RepoName()
 		if err != nil {
@@ -195,6 +254,21 @@ func (r *InstanceRenderer) Render(i *session.Instance, idx int, selected bool, h
 			branch += fmt.Sprintf(" (%s)", repoName)
 		}
 	}
+	if i.Host != "" {
+		branch += " @" + i.Host
+	}
+	for _, port := range i.RunningServicePorts() {
+		branch += fmt.Sprintf(" :%d", port)
+	}
+	if status := i.ContainerStatus(); status != "" {
+		branch += " [docker: " + status + "]"
+	}
+	if i.WaitingForPermission() {
+		branch += " [needs permission]"
+	}
+	if usage := i.GetUsage(); !usage.Empty() {
+		branch += " [" + usage.String() + "]"
+	}
 	// Don't show branch if there's no space for it. Or show ellipsis if it's too long.
 	if remainingWidth < 0 {
 		branch = ""
@@ -214,7 +288,7 @@ func (r *InstanceRenderer) Render(i *session.Instance, idx int, selected bool, h
 		spaces = strings.Repeat(" ", remainingWidth)
 	}
 
-	branchLine := fmt.Sprintf("%s %s-%s%s%s", strings.Repeat(" ", len(prefix)), branchIcon, branch, spaces, diff)
+	branchLine := fmt.Sprintf("%s %s-%s%s%s%s%s", strings.Repeat(" ", len(prefix)), branchIcon, renamed, branch, spaces, prBadge, diff)
 
 	// join title and subtitle
 	text := lipgloss.JoinVertical(
@@ -226,6 +300,116 @@ func (r *InstanceRenderer) Render(i *session.Instance, idx int, selected bool, h
 	return text
 }
 
+var prApprovedStyle = lipgloss.NewStyle().
+	Foreground(lipgloss.AdaptiveColor{Light: "#51bd73", Dark: "#51bd73"})
+
+var prFailedStyle = lipgloss.NewStyle().
+	Foreground(lipgloss.Color("#de613e"))
+
+var prPendingStyle = lipgloss.NewStyle().
+	Foreground(lipgloss.AdaptiveColor{Light: "#d19a00", Dark: "#ffcc00"})
+
+// renderPRBadge renders a compact pull request and CI summary such as "#123 ✓2 ✗CI ✉3 ↓4 " for
+// the branch line, with the count of new unresolved comments and of the commits the branch is
+// behind main, and returns it along with its display width. ciState is the state of the branch's
+// CI checks, falling back to the PR's if none are known; branches without a PR show it alone.
+// Returns "" if there is nothing to show.
+func renderPRBadge(pr *git.PRStatus, ciState string, newComments, behindMain int, descS lipgloss.Style) (string, int) {
+	hasPR := pr != nil && pr.Number != 0
+	if ciState == git.CIStateNone && hasPR {
+		ciState = pr.CIState
+	}
+	if !hasPR && ciState == git.CIStateNone && behindMain == 0 {
+		return "", 0
+	}
+
+	bg := descS.GetBackground()
+	plain := lipgloss.Style{}.Background(bg).Foreground(descS.GetForeground())
+
+	var parts []string
+	width := 0
+	add := func(style lipgloss.Style, text string) {
+		if len(parts) > 0 {
+			parts = append(parts, plain.Render(" "))
+			width++
+		}
+		parts = append(parts, style.Background(bg).Render(text))
+		width += lipgloss.Width(text)
+	}
+
+	open := true
+	if hasPR {
+		add(plain, fmt.Sprintf("#%d", pr.Number))
+		switch pr.State {
+		case "MERGED":
+			add(prApprovedStyle, "merged")
+			open = false
+		case "CLOSED":
+			add(pausedStyle, "closed")
+			open = false
+		default:
+			if pr.Approvals > 0 {
+				add(prApprovedStyle, fmt.Sprintf("✓%d", pr.Approvals))
+			}
+			if pr.ChangesRequested > 0 {
+				add(prFailedStyle, fmt.Sprintf("✗%d", pr.ChangesRequested))
+			}
+		}
+	}
+	if open {
+		switch ciState {
+		case git.CIStateSuccess:
+			add(prApprovedStyle, "✓CI")
+		case git.CIStateFailure:
+			add(prFailedStyle, "✗CI")
+		case git.CIStatePending:
+			add(prPendingStyle, "…CI")
+		}
+		if hasPR && newComments > 0 {
+			add(prPendingStyle, fmt.Sprintf("✉%d", newComments))
+		}
+		if behindMain > 0 {
+			add(prPendingStyle, fmt.Sprintf("↓%d", behindMain))
+		}
+	}
+
+	parts = append(parts, plain.Render(" "))
+	width++
+	return strings.Join(parts, ""), width
+}
+
+// highCPU is the CPU percentage from which an instance's usage is highlighted.
+const highCPU = 80
+
+// renderResourceUsage renders the CPU and memory used by the instance's processes, such as
+// "12% 340M", for the title line, and returns it along with its display width. Returns "" if
+// the usage hasn't been sampled.
+func renderResourceUsage(i *session.Instance, titleS lipgloss.Style) (string, int) {
+	usage, ok := i.ResourceUsage()
+	if !ok {
+		return "", 0
+	}
+	text := fmt.Sprintf("%.0f%% %s", usage.CPU, formatMemory(usage.Memory))
+	style := pausedStyle
+	if usage.CPU >= highCPU {
+		style = prFailedStyle
+	}
+	return style.Background(titleS.GetBackground()).Render(text), lipgloss.Width(text)
+}
+
+// formatMemory formats a byte count compactly, e.g. "340M" or "1.2G".
+func formatMemory(bytes int64) string {
+	const mb = 1024 * 1024
+	switch {
+	case bytes >= 1024*mb:
+		return fmt.Sprintf("%.1fG", float64(bytes)/(1024*mb))
+	case bytes >= mb:
+		return fmt.Sprintf("%dM", bytes/mb)
+	default:
+		return fmt.Sprintf("%dK", bytes/1024)
+	}
+}
+
 func (l *List) String() string {
 	const titleText = " Instances "
 	const autoYesText = " auto-yes "
@@ -250,32 +434,45 @@ func (l *List) String() string {
 			lipgloss.Top, title, autoYes))
 	}
 
+	// The summary takes the place of the blank line under the title
 	b.WriteString("\n")
+	if len(l.items) > 0 {
+		b.WriteString(" " + collectFleetStats(l.items).render(titleWidth-1))
+	}
 	b.WriteString("\n")
+	if l.filter != "" || l.filterEditing {
+		b.WriteString(" " + l.renderFilter(titleWidth-1) + "\n")
+	}
 
-	// Render the list.
-	for i, item := range l.items {
-		b.WriteString(l.renderer.Render(item, i+1, i == l.selectedIdx, len(l.repos) > 1))
-		if i != len(l.items)-1 {
+	// Render the list, ungrouped instances under a label of their own once there are groups
+	labeled := false
+	for n, row := range l.rows() {
+		if n > 0 {
 			b.WriteString("\n\n")
 		}
+		if row.group >= 0 {
+			b.WriteString(l.renderGroupHeader(l.groups[row.group], row.group == l.selectedHeader))
+			continue
+		}
+		item := l.items[row.instance]
+		if len(l.groups) > 0 && !labeled && l.groupIndex(item.Group) < 0 {
+			b.WriteString(ungroupedStyle.Render("Ungrouped") + "\n")
+			labeled = true
+		}
+		selected := l.selectedHeader < 0 && row.instance == l.selectedIdx
+		b.WriteString(l.renderer.Render(item, row.instance+1, selected, len(l.repos) > 1))
 	}
 	return lipgloss.Place(l.width, l.height, lipgloss.Left, lipgloss.Top, b.String())
 }
 
 // Down selects the next item in the list.
 func (l *List) Down() {
-	if len(l.items) == 0 {
-		return
-	}
-	if l.selectedIdx < len(l.items)-1 {
-		l.selectedIdx++
-	}
+	l.moveSelection(1)
 }
 
 // Kill selects the next item in the list.
 func (l *List) Kill() {
-	if len(l.items) == 0 {
+	if len(l.items) == 0 || l.selectedHeader >= 0 {
 		return
 	}
 	targetInstance := l.items[l.selectedIdx]
@@ -290,10 +487,19 @@ func (l *List) Kill() {
 		}
 	}
 
-	// If you delete the last one in the list, select the previous one.
-	if l.selectedIdx == len(l.items)-1 {
-		defer l.Up()
+	l.Remove()
+}
+
+// Remove removes the selected instance from the list without killing it. Use this when the
+// instance has already been torn down some other way.
+func (l *List) Remove() {
+	if len(l.items) == 0 || l.selectedHeader >= 0 {
+		return
 	}
+	targetInstance := l.items[l.selectedIdx]
+
+	// If you delete the last one in the list, the previous one ends up selected.
+	defer l.fixSelection()
 
 	// Unregister the reponame.
 	repoName, err := targetInstance.RepoName()
@@ -307,6 +513,32 @@ func (l *List) Kill() {
 	l.items = append(l.items[:l.selectedIdx], l.items[l.selectedIdx+1:]...)
 }
 
+// RemoveInstance removes the instance from the list without killing it, keeping the selection
+// on the instance selected before if it isn't the one removed.
+func (l *List) RemoveInstance(instance *session.Instance) {
+	idx := -1
+	for i, item := range l.items {
+		if item == instance {
+			idx = i
+			break
+		}
+	}
+	if idx < 0 {
+		return
+	}
+	defer l.fixSelection()
+
+	if repoName, err := instance.RepoName(); err != nil {
+		log.ErrorLog.Printf("could not get repo name: %v", err)
+	} else {
+		l.rmRepo(repoName)
+	}
+	l.items = append(l.items[:idx], l.items[idx+1:]...)
+	if idx < l.selectedIdx {
+		l.selectedIdx--
+	}
+}
+
 func (l *List) Attach() (chan struct{}, error) {
 	targetInstance := l.items[l.selectedIdx]
 	return targetInstance.Attach()
@@ -320,12 +552,7 @@ func (l *List) AttachToPane(paneIndex int) (chan struct{}, error) {
 
 // Up selects the prev item in the list.
 func (l *List) Up() {
-	if len(l.items) == 0 {
-		return
-	}
-	if l.selectedIdx > 0 {
-		l.selectedIdx--
-	}
+	l.moveSelection(-1)
 }
 
 func (l *List) addRepo(repo string) {
@@ -351,6 +578,10 @@ func (l *List) rmRepo(repo string) {
 // When creating a new one and entering the name, you want to call the finalizer once the name is done.
 func (l *List) AddInstance(instance *session.Instance) (finalize func()) {
 	l.items = append(l.items, instance)
+	if instance.Group != "" {
+		l.ensureGroup(instance.Group)
+		l.sortItems()
+	}
 	// The finalizer registers the repo name once the instance is started.
 	return func() {
 		repoName, err := instance.RepoName()
@@ -363,20 +594,30 @@ func (l *List) AddInstance(instance *session.Instance) (finalize func()) {
 	}
 }
 
-// GetSelectedInstance returns the currently selected instance
+// GetSelectedInstance returns the currently selected instance, or nil if a group's header is
+// selected or the filter matches nothing
 func (l *List) GetSelectedInstance() *session.Instance {
-	if len(l.items) == 0 {
+	if len(l.items) == 0 || l.selectedHeader >= 0 || !l.matchesFilter(l.items[l.selectedIdx]) {
 		return nil
 	}
 	return l.items[l.selectedIdx]
 }
 
-// SetSelectedInstance sets the selected index. Noop if the index is out of bounds.
+// SetSelectedInstance sets the selected index, expanding the instance's group and clearing a
+// filter hiding it. Noop if the index is out of bounds.
 func (l *List) SetSelectedInstance(idx int) {
-	if idx >= len(l.items) {
+	if idx < 0 || idx >= len(l.items) {
 		return
 	}
 	l.selectedIdx = idx
+	l.selectedHeader = -1
+	// An instance the filter hides is shown by clearing the filter
+	if !l.matchesFilter(l.items[idx]) {
+		l.filter = ""
+	}
+	if g := l.groupIndex(l.items[idx].Group); g >= 0 {
+		l.groups[g].Collapsed = false
+	}
 }
 
 // GetInstances returns all instances in the list
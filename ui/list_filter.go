@@ -0,0 +1,105 @@
+package ui
+
+import (
+	"claude-squad/session"
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+var filterStyle = lipgloss.NewStyle().
+	Foreground(lipgloss.AdaptiveColor{Light: "#5a4fcf", Dark: "#a99cff"})
+
+var filterCountStyle = lipgloss.NewStyle().
+	Foreground(lipgloss.AdaptiveColor{Light: "#A49FA5", Dark: "#777777"})
+
+// SetFilter narrows the list to the instances matching query: space-separated words that must
+// each be part of the instance's title, branch, repository or one of its tags, ignoring case. An
+// empty query shows every instance. The selection moves to the first match if it's filtered out.
+func (l *List) SetFilter(query string) {
+	l.filter = query
+	if item := l.selectedItem(); l.selectedHeader < 0 && item != nil && l.matchesFilter(item) {
+		return
+	}
+	if l.selectedHeader >= 0 && l.headerVisible(l.selectedHeader) {
+		return
+	}
+	if rows := l.rows(); len(rows) > 0 {
+		l.selectedHeader = rows[0].group
+		for _, row := range rows {
+			if row.instance >= 0 {
+				l.selectedHeader = -1
+				l.selectedIdx = row.instance
+				break
+			}
+		}
+	}
+}
+
+// Filter returns the query the list is narrowed to, or "" if it isn't.
+func (l *List) Filter() string {
+	return l.filter
+}
+
+// SetFilterEditing shows whether the filter is being typed.
+func (l *List) SetFilterEditing(editing bool) {
+	l.filterEditing = editing
+}
+
+// matchesFilter returns true if the instance matches every word of the filter.
+func (l *List) matchesFilter(instance *session.Instance) bool {
+	words := strings.Fields(strings.ToLower(l.filter))
+	if len(words) == 0 {
+		return true
+	}
+	values := []string{instance.Title, instance.Branch}
+	values = append(values, instance.Tags...)
+	if repo, err := instance.RepoName(); err == nil {
+		values = append(values, repo)
+	}
+	for _, word := range words {
+		matched := false
+		for _, value := range values {
+			if strings.Contains(strings.ToLower(value), word) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+	return true
+}
+
+// headerVisible returns true if the group's header is shown: always without a filter, and with
+// one only if some of its instances match.
+func (l *List) headerVisible(group int) bool {
+	if l.filter == "" {
+		return true
+	}
+	for _, instance := range l.GroupInstances(l.groups[group].Name) {
+		if l.matchesFilter(instance) {
+			return true
+		}
+	}
+	return false
+}
+
+// renderFilter renders the line showing the filter and how many instances match it.
+func (l *List) renderFilter(width int) string {
+	matching := 0
+	for _, item := range l.items {
+		if l.matchesFilter(item) {
+			matching++
+		}
+	}
+	query := l.filter
+	if l.filterEditing {
+		query += "█"
+	}
+	count := fmt.Sprintf(" %d of %d", matching, len(l.items))
+	line := filterStyle.Render("/"+query) + filterCountStyle.Render(count)
+	return lipgloss.NewStyle().MaxWidth(width).Render(line)
+}
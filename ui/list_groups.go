@@ -0,0 +1,276 @@
+package ui
+
+import (
+	"claude-squad/session"
+	"fmt"
+	"sort"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+var groupHeaderStyle = lipgloss.NewStyle().
+	Padding(1, 1, 0, 1).
+	Bold(true).
+	Foreground(lipgloss.AdaptiveColor{Light: "#5a4fcf", Dark: "#a99cff"})
+
+var selectedGroupHeaderStyle = lipgloss.NewStyle().
+	Padding(1, 1, 0, 1).
+	Bold(true).
+	Background(lipgloss.Color("#dde4f0")).
+	Foreground(lipgloss.Color("#1a1a1a"))
+
+var ungroupedStyle = lipgloss.NewStyle().
+	Padding(1, 1, 0, 1).
+	Foreground(lipgloss.AdaptiveColor{Light: "#A49FA5", Dark: "#777777"})
+
+// listRow is a selectable row of the list: a group's header or an instance.
+type listRow struct {
+	// group is the index in groups of the header, or -1 for an instance
+	group int
+	// instance is the index in items of the instance, or -1 for a header
+	instance int
+}
+
+// rows returns the selectable rows of the list in the order they are shown: each group's header
+// followed by its instances unless it's collapsed, then the ungrouped instances. With a filter,
+// only the matching instances are shown, with their groups expanded.
+func (l *List) rows() []listRow {
+	rows := make([]listRow, 0, len(l.groups)+len(l.items))
+	for g, group := range l.groups {
+		if !l.headerVisible(g) {
+			continue
+		}
+		rows = append(rows, listRow{group: g, instance: -1})
+		if group.Collapsed && l.filter == "" {
+			continue
+		}
+		for i, item := range l.items {
+			if item.Group == group.Name && l.matchesFilter(item) {
+				rows = append(rows, listRow{group: -1, instance: i})
+			}
+		}
+	}
+	for i, item := range l.items {
+		if l.groupIndex(item.Group) < 0 && l.matchesFilter(item) {
+			rows = append(rows, listRow{group: -1, instance: i})
+		}
+	}
+	return rows
+}
+
+// groupIndex returns the index of the named group, or -1 if there is no such group.
+func (l *List) groupIndex(name string) int {
+	if name == "" {
+		return -1
+	}
+	for g, group := range l.groups {
+		if group.Name == name {
+			return g
+		}
+	}
+	return -1
+}
+
+// ensureGroup adds the named group if it doesn't exist yet.
+func (l *List) ensureGroup(name string) {
+	if name != "" && l.groupIndex(name) < 0 {
+		l.groups = append(l.groups, session.Group{Name: name})
+	}
+}
+
+// sortItems orders the instances the way they're shown, by group, keeping the selection.
+func (l *List) sortItems() {
+	selected := l.selectedItem()
+	rank := func(i int) int {
+		if g := l.groupIndex(l.items[i].Group); g >= 0 {
+			return g
+		}
+		return len(l.groups)
+	}
+	sort.SliceStable(l.items, func(i, j int) bool { return rank(i) < rank(j) })
+	for i, item := range l.items {
+		if item == selected {
+			l.selectedIdx = i
+		}
+	}
+}
+
+// selectedItem returns the instance at the selected index, whether or not a header is selected.
+func (l *List) selectedItem() *session.Instance {
+	if l.selectedIdx < 0 || l.selectedIdx >= len(l.items) {
+		return nil
+	}
+	return l.items[l.selectedIdx]
+}
+
+// fixSelection keeps the selection on a visible row: the header of a collapsed group stands in
+// for its selected instance.
+func (l *List) fixSelection() {
+	if l.selectedHeader >= len(l.groups) {
+		l.selectedHeader = -1
+	}
+	if l.selectedIdx >= len(l.items) {
+		l.selectedIdx = max(0, len(l.items)-1)
+	}
+	if l.selectedHeader >= 0 {
+		return
+	}
+	if item := l.selectedItem(); item != nil {
+		if g := l.groupIndex(item.Group); g >= 0 && l.groups[g].Collapsed {
+			l.selectedHeader = g
+		}
+	} else if len(l.groups) > 0 {
+		l.selectedHeader = 0
+	}
+}
+
+// moveSelection moves the selection by delta rows.
+func (l *List) moveSelection(delta int) {
+	rows := l.rows()
+	if len(rows) == 0 {
+		return
+	}
+	pos := 0
+	for i, row := range rows {
+		if (l.selectedHeader >= 0 && row.group == l.selectedHeader) ||
+			(l.selectedHeader < 0 && row.instance == l.selectedIdx) {
+			pos = i
+			break
+		}
+	}
+	pos = max(0, min(len(rows)-1, pos+delta))
+	l.selectedHeader = rows[pos].group
+	if rows[pos].instance >= 0 {
+		l.selectedIdx = rows[pos].instance
+	}
+}
+
+// SetGroups replaces the groups the instances are organized in. Groups instances are in that
+// aren't listed are added.
+func (l *List) SetGroups(groups []session.Group) {
+	l.groups = append([]session.Group{}, groups...)
+	for _, item := range l.items {
+		l.ensureGroup(item.Group)
+	}
+	l.sortItems()
+	l.fixSelection()
+}
+
+// Groups returns the groups in the order they are shown.
+func (l *List) Groups() []session.Group {
+	return append([]session.Group{}, l.groups...)
+}
+
+// SelectedGroup returns the name of the selected group header, or of the selected instance's
+// group. It's empty if the selected instance is ungrouped.
+func (l *List) SelectedGroup() string {
+	if l.selectedHeader >= 0 {
+		return l.groups[l.selectedHeader].Name
+	}
+	if item := l.selectedItem(); item != nil && l.groupIndex(item.Group) >= 0 {
+		return item.Group
+	}
+	return ""
+}
+
+// ToggleSelectedGroup collapses or expands the selected group. It returns false if nothing in a
+// group is selected.
+func (l *List) ToggleSelectedGroup() bool {
+	g := l.groupIndex(l.SelectedGroup())
+	if g < 0 {
+		return false
+	}
+	l.groups[g].Collapsed = !l.groups[g].Collapsed
+	if l.groups[g].Collapsed {
+		l.selectedHeader = g
+	}
+	return true
+}
+
+// GroupInstances returns the instances in the named group.
+func (l *List) GroupInstances(name string) []*session.Instance {
+	var instances []*session.Instance
+	for _, item := range l.items {
+		if name != "" && item.Group == name {
+			instances = append(instances, item)
+		}
+	}
+	return instances
+}
+
+// MoveToGroup moves the instance into the named group, creating the group if needed, and selects
+// it. An empty name removes it from its group.
+func (l *List) MoveToGroup(instance *session.Instance, name string) {
+	instance.Group = name
+	l.ensureGroup(name)
+	if g := l.groupIndex(name); g >= 0 {
+		l.groups[g].Collapsed = false
+	}
+	l.sortItems()
+	for i, item := range l.items {
+		if item == instance {
+			l.selectedIdx = i
+			l.selectedHeader = -1
+		}
+	}
+}
+
+// RenameGroup renames a group and moves its instances along.
+func (l *List) RenameGroup(oldName, newName string) error {
+	g := l.groupIndex(oldName)
+	if g < 0 {
+		return fmt.Errorf("group %s does not exist", oldName)
+	}
+	if oldName != newName && l.groupIndex(newName) >= 0 {
+		return fmt.Errorf("group %s already exists", newName)
+	}
+	l.groups[g].Name = newName
+	for _, item := range l.items {
+		if item.Group == oldName {
+			item.Group = newName
+		}
+	}
+	return nil
+}
+
+// RemoveGroup removes a group, leaving its instances ungrouped.
+func (l *List) RemoveGroup(name string) {
+	g := l.groupIndex(name)
+	if g < 0 {
+		return
+	}
+	for _, item := range l.items {
+		if item.Group == name {
+			item.Group = ""
+		}
+	}
+	l.groups = append(l.groups[:g], l.groups[g+1:]...)
+	l.selectedHeader = -1
+	l.sortItems()
+	l.fixSelection()
+}
+
+// renderGroupHeader renders the header of a group. A collapsed group shows how many of its
+// hidden instances are ready.
+func (l *List) renderGroupHeader(group session.Group, selected bool) string {
+	instances := l.GroupInstances(group.Name)
+	icon := "▾"
+	summary := fmt.Sprintf("(%d)", len(instances))
+	if group.Collapsed {
+		icon = "▸"
+		ready := 0
+		for _, instance := range instances {
+			if instance.Status == session.Ready {
+				ready++
+			}
+		}
+		if ready > 0 {
+			summary = fmt.Sprintf("(%d, %d ready)", len(instances), ready)
+		}
+	}
+	style := groupHeaderStyle
+	if selected {
+		style = selectedGroupHeaderStyle
+	}
+	return style.Width(l.renderer.width).Render(fmt.Sprintf("%s %s %s", icon, group.Name, summary))
+}
@@ -0,0 +1,79 @@
+package ui
+
+import (
+	"claude-squad/session"
+	"testing"
+
+	"github.com/charmbracelet/bubbles/spinner"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListGroups(t *testing.T) {
+	s := spinner.New()
+	l := NewList(&s, false)
+	instances := map[string]*session.Instance{}
+	for _, title := range []string{"one", "two", "three"} {
+		instance, err := session.NewInstance(session.InstanceOptions{Title: title, Path: ".", Program: "claude"})
+		require.NoError(t, err)
+		instances[title] = instance
+		l.AddInstance(instance)
+	}
+	titles := func() []string {
+		var titles []string
+		for _, instance := range l.GetInstances() {
+			titles = append(titles, instance.Title)
+		}
+		return titles
+	}
+
+	// Grouped instances are listed first, under their group's header
+	l.SetSelectedInstance(2)
+	l.MoveToGroup(instances["three"], "api")
+	assert.Equal(t, []string{"three", "one", "two"}, titles())
+	assert.Equal(t, instances["three"], l.GetSelectedInstance())
+	assert.Equal(t, "api", l.SelectedGroup())
+	assert.Equal(t, []session.Group{{Name: "api"}}, l.Groups())
+
+	// Collapsing selects the header, which isn't an instance, and hides the group's instances
+	require.True(t, l.ToggleSelectedGroup())
+	assert.Nil(t, l.GetSelectedInstance())
+	assert.Equal(t, "api", l.SelectedGroup())
+	l.Down()
+	assert.Equal(t, instances["one"], l.GetSelectedInstance())
+	l.Up()
+	assert.Nil(t, l.GetSelectedInstance())
+	l.Up()
+	assert.Nil(t, l.GetSelectedInstance())
+
+	// Selecting a hidden instance expands its group
+	l.SetSelectedInstance(0)
+	assert.Equal(t, instances["three"], l.GetSelectedInstance())
+	assert.False(t, l.Groups()[0].Collapsed)
+
+	require.NoError(t, l.RenameGroup("api", "backend"))
+	assert.Equal(t, "backend", instances["three"].Group)
+	assert.Error(t, l.RenameGroup("missing", "other"))
+
+	l.RemoveGroup("backend")
+	assert.Empty(t, l.Groups())
+	assert.Equal(t, "", instances["three"].Group)
+	assert.Equal(t, instances["three"], l.GetSelectedInstance())
+	assert.Equal(t, "", l.SelectedGroup())
+	assert.False(t, l.ToggleSelectedGroup())
+}
+
+func TestListSetGroupsAddsMissingGroups(t *testing.T) {
+	s := spinner.New()
+	l := NewList(&s, false)
+	instance, err := session.NewInstance(session.InstanceOptions{Title: "one", Path: ".", Program: "claude"})
+	require.NoError(t, err)
+	instance.Group = "web"
+	l.AddInstance(instance)
+
+	l.SetGroups([]session.Group{{Name: "empty", Collapsed: true}})
+	assert.Equal(t, []session.Group{{Name: "empty", Collapsed: true}, {Name: "web"}}, l.Groups())
+	assert.Equal(t, instance, l.GetSelectedInstance())
+	assert.Len(t, l.GroupInstances("web"), 1)
+	assert.Empty(t, l.GroupInstances("empty"))
+}
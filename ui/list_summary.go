@@ -0,0 +1,90 @@
+package ui
+
+import (
+	"claude-squad/session"
+	"claude-squad/session/git"
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+)
+
+var summaryStyle = lipgloss.NewStyle().
+	Foreground(lipgloss.AdaptiveColor{Light: "#A49FA5", Dark: "#777777"})
+
+// fleetStats sums up the instances in the list.
+type fleetStats struct {
+	total, running, ready, paused int
+	added, removed                int
+	// usage sums the tokens and cost the agents printed
+	usage session.Usage
+	// attention counts instances whose pull request has failing checks or requested changes,
+	// whose agent has worked past its time budget, or whose worktree was deleted
+	attention int
+}
+
+// collectFleetStats sums up the instances from their cached diff stats and PR status, so it is
+// cheap enough to run on every render.
+func collectFleetStats(items []*session.Instance) fleetStats {
+	stats := fleetStats{total: len(items)}
+	for _, item := range items {
+		switch item.Status {
+		case session.Running:
+			stats.running++
+		case session.Ready:
+			stats.ready++
+		case session.Paused:
+			stats.paused++
+		}
+		if diff := item.GetDiffStats(); diff != nil && diff.Error == nil {
+			stats.added += diff.Added
+			stats.removed += diff.Removed
+		}
+		stats.usage = stats.usage.Add(item.GetUsage())
+		if needsAttention(item) {
+			stats.attention++
+		}
+	}
+	return stats
+}
+
+// needsAttention returns true if the instance's pull request is blocked on the author, its
+// agent has worked past its time budget or its worktree was deleted.
+func needsAttention(item *session.Instance) bool {
+	if item.OverBudget() || item.Broken() {
+		return true
+	}
+	pr := item.GetPRStatus()
+	return pr != nil && pr.State == "OPEN" && (pr.ChangesRequested > 0 || pr.CIState == git.CIStateFailure)
+}
+
+// render renders the stats as one line at most width wide, e.g.
+// "4 sessions • 2 running • 1 ready • 1 paused • +120 -34 • 1.2M tok $3.40 • 1 needs attention".
+// Counts of zero are left out.
+func (s fleetStats) render(width int) string {
+	noun := "sessions"
+	if s.total == 1 {
+		noun = "session"
+	}
+	parts := []string{summaryStyle.Render(fmt.Sprintf("%d %s", s.total, noun))}
+	for _, count := range []struct {
+		n     int
+		label string
+	}{{s.running, "running"}, {s.ready, "ready"}, {s.paused, "paused"}} {
+		if count.n > 0 {
+			parts = append(parts, summaryStyle.Render(fmt.Sprintf("%d %s", count.n, count.label)))
+		}
+	}
+	if s.added > 0 || s.removed > 0 {
+		parts = append(parts, addedLinesStyle.Render(fmt.Sprintf("+%d", s.added))+" "+
+			removedLinesStyle.Render(fmt.Sprintf("-%d", s.removed)))
+	}
+	if !s.usage.Empty() {
+		parts = append(parts, summaryStyle.Render(s.usage.String()))
+	}
+	if s.attention > 0 {
+		parts = append(parts, prFailedStyle.Render(fmt.Sprintf("%d needs attention", s.attention)))
+	}
+	return ansi.Truncate(strings.Join(parts, summaryStyle.Render(" • ")), width, "…")
+}
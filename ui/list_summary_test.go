@@ -0,0 +1,39 @@
+package ui
+
+import (
+	"claude-squad/session"
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/x/ansi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFleetSummary(t *testing.T) {
+	var items []*session.Instance
+	for _, status := range []session.Status{session.Running, session.Ready, session.Ready, session.Paused} {
+		instance, err := session.NewInstance(session.InstanceOptions{Title: "one", Path: ".", Program: "claude"})
+		require.NoError(t, err)
+		instance.SetStatus(status)
+		items = append(items, instance)
+	}
+	items[0].TimeBudget, items[0].WorkTime = time.Minute, 2*time.Minute
+
+	stats := collectFleetStats(items)
+	assert.Equal(t, fleetStats{total: 4, running: 1, ready: 2, paused: 1, attention: 1}, stats)
+	assert.Equal(t, "4 sessions • 1 running • 2 ready • 1 paused • 1 needs attention", ansi.Strip(stats.render(100)))
+
+	stats = fleetStats{total: 1, ready: 1, added: 12, removed: 3}
+	assert.Equal(t, "1 session • 1 ready • +12 -3", ansi.Strip(stats.render(100)))
+	assert.Equal(t, "1 session…", ansi.Strip(stats.render(10)))
+
+	stats.usage = session.ParseUsage("Tokens: 2.4k sent, 1.2k cache write, 89 received. Cost: $0.01 message, $0.01 session.\n" +
+		"Tokens: 12,345 sent, 1.1k received. Cost: $0.04 message, $0.05 session.\n" +
+		"Total cost:            $0.0342\n" +
+		"Usage by model:\n" +
+		"    claude-3-5-haiku:  1.2k input, 123 output, 0 cache read, 0 cache write\n" +
+		"       claude-sonnet:  12 input, 1.5k output, 45.6k cache read, 10.2k cache write\n")
+	assert.Equal(t, session.Usage{InputTokens: 2400 + 12345 + 1200 + 12, OutputTokens: 89 + 1100 + 123 + 1500, Cost: 0.05 + 0.0342}, stats.usage)
+	assert.Equal(t, "1 session • 1 ready • +12 -3 • 18.8k tok $0.08", ansi.Strip(stats.render(100)))
+}
@@ -0,0 +1,137 @@
+package ui
+
+import (
+	"claude-squad/log"
+	"claude-squad/session"
+	"claude-squad/session/git"
+	"testing"
+
+	"github.com/charmbracelet/bubbles/spinner"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListRemoveInstance(t *testing.T) {
+	log.Initialize(false)
+	s := spinner.New()
+	l := NewList(&s, false)
+	instances := map[string]*session.Instance{}
+	for _, title := range []string{"one", "two", "three"} {
+		instance, err := session.NewInstance(session.InstanceOptions{Title: title, Path: ".", Program: "claude"})
+		require.NoError(t, err)
+		instances[title] = instance
+		l.AddInstance(instance)
+	}
+
+	// Removing an instance before the selected one keeps the selection
+	l.SetSelectedInstance(2)
+	l.RemoveInstance(instances["one"])
+	assert.Len(t, l.GetInstances(), 2)
+	assert.Equal(t, instances["three"], l.GetSelectedInstance())
+
+	// Removing the selected last instance selects the one before it
+	l.RemoveInstance(instances["three"])
+	assert.Equal(t, instances["two"], l.GetSelectedInstance())
+
+	// Instances not in the list are ignored
+	l.RemoveInstance(instances["one"])
+	assert.Len(t, l.GetInstances(), 1)
+}
+
+func TestListFilter(t *testing.T) {
+	log.Initialize(false)
+	s := spinner.New()
+	l := NewList(&s, false)
+	instances := map[string]*session.Instance{}
+	for _, title := range []string{"login-fix", "payments", "login-tests"} {
+		instance, err := session.NewInstance(session.InstanceOptions{Title: title, Path: ".", Program: "claude"})
+		require.NoError(t, err)
+		instances[title] = instance
+		l.AddInstance(instance)
+	}
+	instances["payments"].Tags = []string{"Backend"}
+	l.MoveToGroup(instances["login-tests"], "qa")
+	visible := func() []string {
+		var titles []string
+		for _, row := range l.rows() {
+			if row.instance >= 0 {
+				titles = append(titles, l.items[row.instance].Title)
+			}
+		}
+		return titles
+	}
+
+	// The selection moves to the first match when the filter hides it
+	l.SetSelectedInstance(2)
+	require.Equal(t, instances["payments"], l.GetSelectedInstance())
+	l.SetFilter("LOGIN")
+	assert.Equal(t, []string{"login-tests", "login-fix"}, visible())
+	assert.Equal(t, instances["login-tests"], l.GetSelectedInstance())
+
+	// Every word has to match, tags included
+	l.SetFilter("back")
+	assert.Equal(t, []string{"payments"}, visible())
+	assert.Equal(t, instances["payments"], l.GetSelectedInstance())
+	l.SetFilter("login fix")
+	assert.Equal(t, []string{"login-fix"}, visible())
+	assert.Len(t, l.rows(), 1, "groups without matches are hidden")
+
+	l.SetFilter("nothing")
+	assert.Empty(t, visible())
+	assert.Nil(t, l.GetSelectedInstance())
+
+	// Selecting a hidden instance clears the filter
+	l.SetSelectedInstance(0)
+	assert.Equal(t, "", l.Filter())
+	assert.Len(t, visible(), 3)
+}
+
+func TestRenderPRBadgeNewComments(t *testing.T) {
+	pr := &git.PRStatus{Number: 42, State: "OPEN", Approvals: 1}
+
+	badge, width := renderPRBadge(pr, git.CIStateNone, 0, 0, lipgloss.NewStyle())
+	assert.NotContains(t, badge, "✉")
+	assert.Equal(t, lipgloss.Width(badge), width)
+
+	badge, width = renderPRBadge(pr, git.CIStateNone, 3, 0, lipgloss.NewStyle())
+	assert.Contains(t, badge, "✉3")
+	assert.Equal(t, lipgloss.Width(badge), width)
+
+	// Merged PRs get no more comments worth flagging
+	pr.State = "MERGED"
+	badge, _ = renderPRBadge(pr, git.CIStateNone, 3, 0, lipgloss.NewStyle())
+	assert.NotContains(t, badge, "✉")
+}
+
+func TestRenderPRBadgeCI(t *testing.T) {
+	// A pushed branch without a PR shows its CI state alone
+	badge, width := renderPRBadge(nil, git.CIStateFailure, 0, 0, lipgloss.NewStyle())
+	assert.Equal(t, "✗CI ", badge)
+	assert.Equal(t, 4, width)
+
+	badge, _ = renderPRBadge(nil, git.CIStateNone, 0, 0, lipgloss.NewStyle())
+	assert.Empty(t, badge)
+
+	// The branch's checks win over the PR's rollup, which is used until they are known
+	pr := &git.PRStatus{Number: 7, State: "OPEN", CIState: git.CIStatePending}
+	badge, _ = renderPRBadge(pr, git.CIStateSuccess, 0, 0, lipgloss.NewStyle())
+	assert.Equal(t, "#7 ✓CI ", badge)
+	badge, _ = renderPRBadge(pr, git.CIStateNone, 0, 0, lipgloss.NewStyle())
+	assert.Equal(t, "#7 …CI ", badge)
+}
+
+func TestRenderPRBadgeBehindMain(t *testing.T) {
+	badge, width := renderPRBadge(nil, git.CIStateNone, 0, 4, lipgloss.NewStyle())
+	assert.Equal(t, "↓4 ", badge)
+	assert.Equal(t, 3, width)
+
+	pr := &git.PRStatus{Number: 7, State: "OPEN"}
+	badge, _ = renderPRBadge(pr, git.CIStateSuccess, 0, 2, lipgloss.NewStyle())
+	assert.Equal(t, "#7 ✓CI ↓2 ", badge)
+
+	// Merged branches are done with main
+	pr.State = "MERGED"
+	badge, _ = renderPRBadge(pr, git.CIStateNone, 0, 2, lipgloss.NewStyle())
+	assert.NotContains(t, badge, "↓")
+}
@@ -57,6 +57,9 @@ type Menu struct {
 
 	// updateChecker is used to check if updates are available
 	updateChecker UpdateChecker
+
+	// pendingOperations summarizes the background operations in flight, if any
+	pendingOperations string
 }
 
 // UpdateChecker interface for checking if updates are available
@@ -148,8 +151,11 @@ func (m *Menu) addInstanceOptions() {
 	// Instance management group
 	options := []keys.KeyName{keys.KeyNew, keys.KeyExistingBranch, keys.KeyKill}
 
-	// Action group
-	actionGroup := []keys.KeyName{keys.KeyEnter, keys.KeySubmit}
+	// Action group. Pushing needs a remote, so local-only repositories don't offer it.
+	actionGroup := []keys.KeyName{keys.KeyEnter}
+	if m.instance.HasRemote() {
+		actionGroup = append(actionGroup, keys.KeySubmit)
+	}
 	if m.instance.Status == session.Paused {
 		actionGroup = append(actionGroup, keys.KeyResume)
 	} else {
@@ -177,6 +183,11 @@ func (m *Menu) SetSize(width, height int) {
 	m.height = height
 }
 
+// SetPendingOperations sets the summary of the background operations in flight, or "" for none.
+func (m *Menu) SetPendingOperations(summary string) {
+	m.pendingOperations = summary
+}
+
 func (m *Menu) String() string {
 	var s strings.Builder
 
@@ -249,6 +260,14 @@ func (m *Menu) String() string {
 		s.WriteString(scrollLockStyle.Render("[SCROLL LOCK]"))
 	}
 
+	// Add the background operations in flight
+	if m.pendingOperations != "" {
+		s.WriteString(sepStyle.Render(verticalSeparator))
+		operationsStyle := lipgloss.NewStyle().
+			Foreground(lipgloss.Color("39"))
+		s.WriteString(operationsStyle.Render("[" + m.pendingOperations + "]"))
+	}
+
 	// Add update indicator if updates are available
 	if m.updateChecker != nil && m.updateChecker.IsUpdateAvailable() {
 		s.WriteString(sepStyle.Render(verticalSeparator))
@@ -0,0 +1,163 @@
+package overlay
+
+import (
+	"claude-squad/config"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textarea"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// AutoYesPolicyOverlay is a form editing the auto-yes policy: the regular expressions of the
+// prompts to always and never confirm, one per line.
+type AutoYesPolicyOverlay struct {
+	allow     textarea.Model
+	deny      textarea.Model
+	focus     int // 0 allow, 1 deny
+	err       string
+	submitted bool
+	width     int
+}
+
+// newPatternArea creates a text area holding one pattern per line.
+func newPatternArea(placeholder string, patterns []string) textarea.Model {
+	area := textarea.New()
+	area.Placeholder = placeholder
+	area.ShowLineNumbers = false
+	area.Prompt = ""
+	area.CharLimit = 0
+	area.SetHeight(5)
+	area.FocusedStyle.CursorLine = lipgloss.NewStyle()
+	area.SetValue(strings.Join(patterns, "\n"))
+	return area
+}
+
+// NewAutoYesPolicyOverlay creates the form, filled in with the current policy, which may be nil.
+func NewAutoYesPolicyOverlay(policy *config.AutoYesPolicy) *AutoYesPolicyOverlay {
+	if policy == nil {
+		policy = &config.AutoYesPolicy{}
+	}
+	o := &AutoYesPolicyOverlay{
+		allow: newPatternArea(`e.g. Run tests\? (empty allows every prompt not denied)`, policy.Allow),
+		deny:  newPatternArea(`e.g. (?i)delete|rm -rf`, policy.Deny),
+		width: 70,
+	}
+	o.setFocus(0)
+	return o
+}
+
+func (o *AutoYesPolicyOverlay) setFocus(focus int) {
+	o.focus = (focus + 2) % 2
+	o.allow.Blur()
+	o.deny.Blur()
+	if o.focus == 0 {
+		o.allow.Focus()
+	} else {
+		o.deny.Focus()
+	}
+}
+
+// HandleKeyPress processes a key press and returns true when the overlay should close. Saving a
+// pattern that isn't a valid regular expression keeps the overlay open and shows the error.
+func (o *AutoYesPolicyOverlay) HandleKeyPress(msg tea.KeyMsg) bool {
+	switch msg.String() {
+	case "esc", "ctrl+c":
+		return true
+	case "ctrl+s":
+		if err := o.Policy().Validate(); err != nil {
+			o.err = err.Error()
+			return false
+		}
+		o.submitted = true
+		return true
+	case "tab", "shift+tab":
+		o.setFocus(o.focus + 1)
+		return false
+	}
+
+	if o.focus == 0 {
+		o.allow, _ = o.allow.Update(msg)
+	} else {
+		o.deny, _ = o.deny.Update(msg)
+	}
+	o.err = ""
+	return false
+}
+
+// IsSubmitted returns true if the user saved the policy rather than cancelling.
+func (o *AutoYesPolicyOverlay) IsSubmitted() bool {
+	return o.submitted
+}
+
+// Policy returns the policy as edited, or nil when both lists are empty so every prompt is
+// confirmed.
+func (o *AutoYesPolicyOverlay) Policy() *config.AutoYesPolicy {
+	policy := &config.AutoYesPolicy{Allow: patternLines(o.allow.Value()), Deny: patternLines(o.deny.Value())}
+	if len(policy.Allow) == 0 && len(policy.Deny) == 0 {
+		return nil
+	}
+	return policy
+}
+
+// patternLines returns the non-blank lines of text.
+func patternLines(text string) []string {
+	var patterns []string
+	for _, line := range strings.Split(text, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			patterns = append(patterns, line)
+		}
+	}
+	return patterns
+}
+
+func (o *AutoYesPolicyOverlay) SetSize(width, height int) {
+	o.width = width
+	o.allow.SetWidth(width - 6)
+	o.deny.SetWidth(width - 6)
+}
+
+// Render renders the overlay.
+func (o *AutoYesPolicyOverlay) Render() string {
+	style := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("#7D56F4")).
+		Padding(1, 2).
+		Width(o.width)
+
+	titleStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("#7D56F4"))
+
+	labelStyle := lipgloss.NewStyle()
+	focusedLabelStyle := labelStyle.Foreground(lipgloss.Color("#7D56F4")).Bold(true)
+
+	mutedStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("#666666"))
+
+	errorStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("#FF0000"))
+
+	label := func(idx int, text string) string {
+		if o.focus == idx {
+			return focusedLabelStyle.Render("> " + text)
+		}
+		return labelStyle.Render("  " + text)
+	}
+
+	lines := []string{
+		titleStyle.Render("Auto-yes Policy"),
+		mutedStyle.Render("Regular expressions matched against the prompt, one per line. Deny wins over allow."),
+		"",
+		label(0, "Always confirm"),
+		o.allow.View(),
+		"",
+		label(1, "Never confirm"),
+		o.deny.View(),
+	}
+	if o.err != "" {
+		lines = append(lines, "", errorStyle.Render(o.err))
+	}
+	lines = append(lines, "", mutedStyle.Render("tab switch list • ctrl+s save • esc cancel"))
+	return style.Render(strings.Join(lines, "\n"))
+}
@@ -0,0 +1,168 @@
+package overlay
+
+import (
+	"claude-squad/session/git"
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// BranchImportOverlay lets the user pick several existing branches to import as paused instances.
+type BranchImportOverlay struct {
+	branches []git.BranchInfo
+	checked  []bool
+	prefix   string
+	cursor   int
+	imported bool
+	width    int
+	height   int
+}
+
+// NewBranchImportOverlay creates the import wizard. Branches starting with prefix are selected
+// initially.
+func NewBranchImportOverlay(branches []git.BranchInfo, prefix string) *BranchImportOverlay {
+	b := &BranchImportOverlay{
+		branches: branches,
+		checked:  make([]bool, len(branches)),
+		prefix:   prefix,
+		width:    80,
+		height:   20,
+	}
+	b.selectPrefixMatches()
+	return b
+}
+
+func (b *BranchImportOverlay) selectPrefixMatches() {
+	for i, branch := range b.branches {
+		b.checked[i] = b.prefix != "" && strings.HasPrefix(branch.Name, b.prefix)
+	}
+}
+
+// HandleKeyPress processes a key press and returns true when the overlay should close.
+func (b *BranchImportOverlay) HandleKeyPress(msg tea.KeyMsg) bool {
+	switch msg.String() {
+	case "esc", "ctrl+c", "q":
+		return true
+	case "enter":
+		b.imported = len(b.SelectedBranches()) > 0
+		return true
+	case "up", "k":
+		if b.cursor > 0 {
+			b.cursor--
+		}
+	case "down", "j":
+		if b.cursor < len(b.branches)-1 {
+			b.cursor++
+		}
+	case " ", "x":
+		if len(b.branches) > 0 {
+			b.checked[b.cursor] = !b.checked[b.cursor]
+		}
+	case "a":
+		// Select all, or clear the selection if everything is already selected
+		all := len(b.SelectedBranches()) == len(b.branches)
+		for i := range b.checked {
+			b.checked[i] = !all
+		}
+	case "p":
+		b.selectPrefixMatches()
+	}
+	return false
+}
+
+// IsImported returns true if the user confirmed the import with at least one branch selected.
+func (b *BranchImportOverlay) IsImported() bool {
+	return b.imported
+}
+
+// SelectedBranches returns the names of the selected branches.
+func (b *BranchImportOverlay) SelectedBranches() []string {
+	var selected []string
+	for i, branch := range b.branches {
+		if b.checked[i] {
+			selected = append(selected, branch.Name)
+		}
+	}
+	return selected
+}
+
+func (b *BranchImportOverlay) SetSize(width, height int) {
+	b.width = width
+	b.height = height
+}
+
+// Render renders the overlay.
+func (b *BranchImportOverlay) Render() string {
+	titleStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("#7D56F4")).
+		MarginBottom(1)
+
+	listStyle := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("#7D56F4")).
+		Padding(1, 2).
+		Width(b.width - 4)
+
+	selectedStyle := lipgloss.NewStyle().
+		Background(lipgloss.Color("#7D56F4")).
+		Foreground(lipgloss.Color("#FAFAFA"))
+
+	normalStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("#FAFAFA"))
+
+	mutedStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("#666666"))
+
+	var s strings.Builder
+	s.WriteString(titleStyle.Render(fmt.Sprintf("Import Branches as Paused Sessions (%d/%d selected)",
+		len(b.SelectedBranches()), len(b.branches))))
+	s.WriteString("\n")
+
+	var list strings.Builder
+	if len(b.branches) == 0 {
+		list.WriteString(mutedStyle.Render("No local branches available to import"))
+	}
+
+	maxVisible := b.height - 10
+	if maxVisible < 1 {
+		maxVisible = 5
+	}
+	startIdx := 0
+	if b.cursor >= maxVisible {
+		startIdx = b.cursor - maxVisible + 1
+	}
+	endIdx := min(startIdx+maxVisible, len(b.branches))
+
+	for i := startIdx; i < endIdx; i++ {
+		branch := b.branches[i]
+		box := "[ ]"
+		if b.checked[i] {
+			box = "[x]"
+		}
+		line := fmt.Sprintf("%s %-30s %s", box, truncateString(branch.Name, 30),
+			mutedStyle.Render(fmt.Sprintf("%s • %s", formatTimeAgo(branch.CommitTime), truncateString(branch.CommitMessage, 40))))
+		if i == b.cursor {
+			list.WriteString(selectedStyle.Render("> " + line))
+		} else {
+			list.WriteString(normalStyle.Render("  " + line))
+		}
+		if i < endIdx-1 {
+			list.WriteString("\n")
+		}
+	}
+	if startIdx > 0 {
+		list.WriteString("\n" + mutedStyle.Render("↑ more above"))
+	}
+	if endIdx < len(b.branches) {
+		list.WriteString("\n" + mutedStyle.Render("↓ more below"))
+	}
+
+	s.WriteString(listStyle.Render(list.String()))
+	s.WriteString("\n")
+	s.WriteString(mutedStyle.Render(fmt.Sprintf("↑/↓ navigate • space toggle • a all/none • p prefix (%s) • enter import • esc cancel", b.prefix)))
+
+	return s.String()
+}
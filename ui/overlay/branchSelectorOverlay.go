@@ -11,34 +11,105 @@ import (
 	"github.com/charmbracelet/lipgloss"
 )
 
+// BranchSelectorTab is a tab of the branch selector.
+type BranchSelectorTab int
+
+const (
+	// BranchSelectorBranches lists remote branches
+	BranchSelectorBranches BranchSelectorTab = iota
+	// BranchSelectorTags lists tags
+	BranchSelectorTags
+	// BranchSelectorCommit takes a commit SHA
+	BranchSelectorCommit
+)
+
+var branchSelectorTabNames = []string{"Branches", "Tags", "Commit"}
+
 type BranchSelectorOverlay struct {
 	branches         []git.BranchInfo
+	tags             []git.BranchInfo
 	filteredBranches []git.BranchInfo
 	cursor           int
 	selected         bool
 	selectedBranch   string
-	filter           textinput.Model
-	width            int
-	height           int
+	// selectedRef is the tag or commit selected on the other tabs
+	selectedRef string
+	tab         BranchSelectorTab
+	filter      textinput.Model
+	commitInput textinput.Model
+	width       int
+	height      int
+	// title heads the selector and commitHint explains what the commit tab's commit is used for
+	title      string
+	commitHint string
 }
 
-func NewBranchSelectorOverlay(branches []git.BranchInfo) *BranchSelectorOverlay {
+// NewBranchSelectorOverlay creates a selector for a remote branch, a tag or a commit. It opens on
+// the commit tab if there are no branches or tags to pick from.
+func NewBranchSelectorOverlay(branches, tags []git.BranchInfo) *BranchSelectorOverlay {
 	ti := textinput.New()
 	ti.Placeholder = "Filter branches..."
 	ti.Focus()
 	ti.CharLimit = 100
 	ti.Width = 50
 
+	commitInput := textinput.New()
+	commitInput.Placeholder = "Commit SHA"
+	commitInput.CharLimit = 64
+	commitInput.Width = 50
+
 	b := &BranchSelectorOverlay{
 		branches:         branches,
+		tags:             tags,
 		filteredBranches: branches,
 		filter:           ti,
+		commitInput:      commitInput,
 		width:            80,
 		height:           20,
+		title:            "Start From a Branch, Tag or Commit",
+		commitHint:       "The instance gets a new branch starting at this commit.",
+	}
+	switch {
+	case len(branches) > 0:
+	case len(tags) > 0:
+		b.setTab(BranchSelectorTags)
+	default:
+		b.setTab(BranchSelectorCommit)
 	}
 	return b
 }
 
+// SetPurpose replaces the title and the commit tab's explanation, for selecting a ref for
+// something other than a new instance.
+func (b *BranchSelectorOverlay) SetPurpose(title, commitHint string) {
+	b.title = title
+	b.commitHint = commitHint
+}
+
+// setTab switches to a tab, clearing the filter.
+func (b *BranchSelectorOverlay) setTab(tab BranchSelectorTab) {
+	b.tab = tab
+	b.cursor = 0
+	b.filter.SetValue("")
+	if tab == BranchSelectorCommit {
+		b.filter.Blur()
+		b.commitInput.Focus()
+		return
+	}
+	b.commitInput.Blur()
+	b.filter.Focus()
+	b.filter.Placeholder = "Filter " + strings.ToLower(branchSelectorTabNames[tab]) + "..."
+	b.updateFilteredBranches()
+}
+
+// refs returns the branches or tags listed on the current tab.
+func (b *BranchSelectorOverlay) refs() []git.BranchInfo {
+	if b.tab == BranchSelectorTags {
+		return b.tags
+	}
+	return b.branches
+}
+
 func (b *BranchSelectorOverlay) Init() tea.Cmd {
 	return textinput.Blink
 }
@@ -52,9 +123,26 @@ func (b *BranchSelectorOverlay) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		case "esc", "ctrl+c":
 			b.selected = true
 			b.selectedBranch = ""
+			b.selectedRef = ""
+			return b, nil
+		case "tab":
+			b.setTab((b.tab + 1) % BranchSelectorTab(len(branchSelectorTabNames)))
+			return b, nil
+		case "shift+tab":
+			b.setTab((b.tab + BranchSelectorTab(len(branchSelectorTabNames)) - 1) % BranchSelectorTab(len(branchSelectorTabNames)))
 			return b, nil
 		case "enter":
-			if len(b.filteredBranches) > 0 {
+			switch {
+			case b.tab == BranchSelectorCommit:
+				if sha := strings.TrimSpace(b.commitInput.Value()); sha != "" {
+					b.selected = true
+					b.selectedRef = sha
+				}
+			case len(b.filteredBranches) == 0:
+			case b.tab == BranchSelectorTags:
+				b.selected = true
+				b.selectedRef = "refs/tags/" + b.filteredBranches[b.cursor].Name
+			default:
 				b.selected = true
 				b.selectedBranch = b.filteredBranches[b.cursor].Name
 			}
@@ -68,6 +156,10 @@ func (b *BranchSelectorOverlay) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				b.cursor++
 			}
 		default:
+			if b.tab == BranchSelectorCommit {
+				b.commitInput, cmd = b.commitInput.Update(msg)
+				return b, cmd
+			}
 			// Update filter input
 			prevFilter := b.filter.Value()
 			b.filter, cmd = b.filter.Update(msg)
@@ -89,10 +181,10 @@ func (b *BranchSelectorOverlay) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 func (b *BranchSelectorOverlay) updateFilteredBranches() {
 	filter := strings.ToLower(b.filter.Value())
 	if filter == "" {
-		b.filteredBranches = b.branches
+		b.filteredBranches = b.refs()
 	} else {
 		b.filteredBranches = make([]git.BranchInfo, 0)
-		for _, branch := range b.branches {
+		for _, branch := range b.refs() {
 			if strings.Contains(strings.ToLower(branch.Name), filter) ||
 				strings.Contains(strings.ToLower(branch.CommitMessage), filter) {
 				b.filteredBranches = append(b.filteredBranches, branch)
@@ -134,13 +226,47 @@ func (b *BranchSelectorOverlay) View() string {
 	mutedStyle := lipgloss.NewStyle().
 		Foreground(lipgloss.Color("#666666"))
 
+	activeTabStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("#FAFAFA")).
+		Background(lipgloss.Color("#7D56F4")).
+		Padding(0, 1)
+
+	inactiveTabStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("#666666")).
+		Padding(0, 1)
+
 	// Build the view
 	var s strings.Builder
 
 	// Title
-	s.WriteString(titleStyle.Render("Select a Remote Branch"))
+	s.WriteString(titleStyle.Render(b.title))
+	s.WriteString("\n")
+
+	tabs := make([]string, len(branchSelectorTabNames))
+	for i, name := range branchSelectorTabNames {
+		if BranchSelectorTab(i) == b.tab {
+			tabs[i] = activeTabStyle.Render(name)
+		} else {
+			tabs[i] = inactiveTabStyle.Render(name)
+		}
+	}
+	s.WriteString(lipgloss.JoinHorizontal(lipgloss.Top, tabs...))
 	s.WriteString("\n\n")
 
+	helpStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("#666666")).
+		MarginTop(1)
+
+	if b.tab == BranchSelectorCommit {
+		s.WriteString(b.commitInput.View())
+		s.WriteString("\n\n")
+		s.WriteString(mutedStyle.Render(b.commitHint))
+		s.WriteString("\n")
+		s.WriteString(helpStyle.Render("tab switch tabs • enter select • esc cancel"))
+		return s.String()
+	}
+
 	// Filter input
 	s.WriteString(b.filter.View())
 	s.WriteString("\n\n")
@@ -193,14 +319,15 @@ func (b *BranchSelectorOverlay) View() string {
 		branchList.WriteString("\n" + mutedStyle.Render("↓ more below"))
 	}
 
+	if len(b.filteredBranches) == 0 {
+		branchList.WriteString(mutedStyle.Render("No " + strings.ToLower(branchSelectorTabNames[b.tab]) + " found"))
+	}
+
 	s.WriteString(listStyle.Render(branchList.String()))
 
 	// Help text
-	helpStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#666666")).
-		MarginTop(1)
 	s.WriteString("\n")
-	s.WriteString(helpStyle.Render("↑/↓ navigate • enter select • esc cancel"))
+	s.WriteString(helpStyle.Render("↑/↓ navigate • tab switch tabs • enter select • esc cancel"))
 
 	return s.String()
 }
@@ -209,10 +336,18 @@ func (b *BranchSelectorOverlay) IsSelected() bool {
 	return b.selected
 }
 
+// SelectedBranch returns the remote branch selected on the branches tab, empty if a tag or
+// commit was selected or the selector was cancelled.
 func (b *BranchSelectorOverlay) SelectedBranch() string {
 	return b.selectedBranch
 }
 
+// SelectedRef returns the tag (as refs/tags/<name>) or commit selected on the other tabs, empty if
+// a branch was selected or the selector was cancelled.
+func (b *BranchSelectorOverlay) SelectedRef() string {
+	return b.selectedRef
+}
+
 func formatTimeAgo(t time.Time) string {
 	duration := time.Since(t)
 
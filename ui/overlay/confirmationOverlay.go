@@ -1,10 +1,22 @@
 package overlay
 
 import (
+	"strings"
+
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 )
 
+// ConfirmationChoice is one labeled option of a multi-choice confirmation
+type ConfirmationChoice struct {
+	// Key that selects this choice
+	Key string
+	// Label describing what the choice does
+	Label string
+	// Callback function to be called when this choice is selected
+	OnSelect func()
+}
+
 // ConfirmationOverlay represents a confirmation dialog overlay
 type ConfirmationOverlay struct {
 	// Whether the overlay has been dismissed
@@ -23,10 +35,35 @@ type ConfirmationOverlay struct {
 	ConfirmKey string
 	// Custom cancel key (defaults to 'n')
 	CancelKey string
+	// Labeled choices. When set, they replace the confirm key and any of them confirms.
+	choices []ConfirmationChoice
+	// Key of the choice the user selected
+	selected string
 	// Custom styling options
 	borderColor lipgloss.Color
+	// LoadCommands, if set, offers showing the commands the action runs. It's called the first
+	// time they're shown; the command it returns is returned by PendingCmd and should end up
+	// calling SetCommands.
+	LoadCommands func() tea.Cmd
+	// commands the action runs, nil until they're loaded
+	commands []string
+	// Whether the commands are shown
+	showCommands bool
+	// pending is the command returned by LoadCommands, waiting for PendingCmd
+	pending tea.Cmd
+	// phrase, when set, has to be typed and entered to confirm instead of pressing a key
+	phrase string
+	// typed is what was typed of the phrase so far
+	typed string
 }
 
+// CommandsKey toggles showing the commands the confirmed action runs.
+const CommandsKey = "g"
+
+// TypedCommandsKey toggles showing the commands in a typed confirmation, where CommandsKey is
+// typed.
+const TypedCommandsKey = "ctrl+g"
+
 // NewConfirmationOverlay creates a new confirmation dialog overlay with the given message
 func NewConfirmationOverlay(message string) *ConfirmationOverlay {
 	return &ConfirmationOverlay{
@@ -39,23 +76,51 @@ func NewConfirmationOverlay(message string) *ConfirmationOverlay {
 	}
 }
 
+// NewConfirmationOverlayWithChoices creates a confirmation dialog offering several labeled
+// choices. Pressing a choice's key confirms with that choice; esc cancels.
+func NewConfirmationOverlayWithChoices(message string, choices []ConfirmationChoice) *ConfirmationOverlay {
+	c := NewConfirmationOverlay(message)
+	c.choices = choices
+	c.CancelKey = "esc"
+	return c
+}
+
+// NewTypedConfirmationOverlay creates a confirmation dialog for an action that's hard to undo,
+// which confirms only once phrase, e.g. the instance's name, is typed and entered; esc cancels.
+func NewTypedConfirmationOverlay(message, phrase string) *ConfirmationOverlay {
+	c := NewConfirmationOverlay(message)
+	c.phrase = phrase
+	c.CancelKey = "esc"
+	return c
+}
+
+// commandsKey returns the key toggling the commands the action runs.
+func (c *ConfirmationOverlay) commandsKey() string {
+	if c.phrase != "" {
+		return TypedCommandsKey
+	}
+	return CommandsKey
+}
+
 // HandleKeyPress processes a key press and updates the state
 // Returns true if the overlay should be closed
 func (c *ConfirmationOverlay) HandleKeyPress(msg tea.KeyMsg) bool {
+	if msg.String() == c.commandsKey() && c.LoadCommands != nil {
+		c.ToggleCommands()
+		return false
+	}
+	if c.phrase != "" {
+		return c.handleTypedKeyPress(msg)
+	}
+	if len(c.choices) > 0 {
+		return c.handleChoiceKeyPress(msg)
+	}
+
 	switch msg.String() {
 	case c.ConfirmKey:
-		c.Dismissed = true
-		c.confirmed = true
-		if c.OnConfirm != nil {
-			c.OnConfirm()
-		}
-		return true
+		return c.Confirm("")
 	case c.CancelKey, "esc":
-		c.Dismissed = true
-		c.confirmed = false
-		if c.OnCancel != nil {
-			c.OnCancel()
-		}
+		c.Cancel()
 		return true
 	default:
 		// Ignore other keys in confirmation state
@@ -63,11 +128,141 @@ func (c *ConfirmationOverlay) HandleKeyPress(msg tea.KeyMsg) bool {
 	}
 }
 
+// handleChoiceKeyPress processes a key press for a multi-choice confirmation
+func (c *ConfirmationOverlay) handleChoiceKeyPress(msg tea.KeyMsg) bool {
+	key := msg.String()
+	if key == c.CancelKey || key == "esc" {
+		c.Cancel()
+		return true
+	}
+
+	// Keys other than the choices' are ignored
+	return c.Confirm(key)
+}
+
+// handleTypedKeyPress processes a key press for a typed confirmation. Enter confirms only if
+// the phrase was typed exactly.
+func (c *ConfirmationOverlay) handleTypedKeyPress(msg tea.KeyMsg) bool {
+	switch msg.Type {
+	case tea.KeyEsc:
+		c.Cancel()
+		return true
+	case tea.KeyEnter:
+		return c.Confirm("")
+	case tea.KeyBackspace:
+		if typed := []rune(c.typed); len(typed) > 0 {
+			c.typed = string(typed[:len(typed)-1])
+		}
+	case tea.KeyCtrlU:
+		c.typed = ""
+	case tea.KeySpace:
+		c.typed += " "
+	case tea.KeyRunes:
+		c.typed += string(msg.Runes)
+	}
+	return false
+}
+
+// Confirm confirms with the key of one of the choices, or "" when there are none, as if it was
+// pressed, and returns true. It returns false, leaving the overlay open, if the key isn't one of
+// the choices, e.g. when answering it remotely, or if a typed confirmation's phrase wasn't typed.
+func (c *ConfirmationOverlay) Confirm(choiceKey string) bool {
+	if c.phrase != "" && c.typed != c.phrase {
+		return false
+	}
+	if len(c.choices) == 0 {
+		if choiceKey != "" {
+			return false
+		}
+	} else {
+		var chosen *ConfirmationChoice
+		for i := range c.choices {
+			if c.choices[i].Key == choiceKey {
+				chosen = &c.choices[i]
+				break
+			}
+		}
+		if chosen == nil {
+			return false
+		}
+		c.selected = chosen.Key
+		if chosen.OnSelect != nil {
+			chosen.OnSelect()
+		}
+	}
+	c.Dismissed = true
+	c.confirmed = true
+	if c.OnConfirm != nil {
+		c.OnConfirm()
+	}
+	return true
+}
+
+// Phrase returns the phrase that has to be typed to confirm, or "" if pressing a key confirms.
+func (c *ConfirmationOverlay) Phrase() string {
+	return c.phrase
+}
+
+// TypePhrase replaces what was typed of the phrase, e.g. with the phrase of a remote approval.
+func (c *ConfirmationOverlay) TypePhrase(typed string) {
+	c.typed = typed
+}
+
+// Cancel cancels the confirmation as if esc was pressed.
+func (c *ConfirmationOverlay) Cancel() {
+	c.Dismissed = true
+	c.confirmed = false
+	if c.OnCancel != nil {
+		c.OnCancel()
+	}
+}
+
+// Message returns the question the overlay asks.
+func (c *ConfirmationOverlay) Message() string {
+	return c.message
+}
+
+// Choices returns the labeled choices, or nil for a yes/no or typed confirmation.
+func (c *ConfirmationOverlay) Choices() []ConfirmationChoice {
+	return c.choices
+}
+
+// ToggleCommands shows or hides the commands the action runs, loading them the first time.
+func (c *ConfirmationOverlay) ToggleCommands() {
+	if c.LoadCommands == nil {
+		return
+	}
+	c.showCommands = !c.showCommands
+	if c.showCommands && c.commands == nil && c.pending == nil {
+		c.pending = c.LoadCommands()
+	}
+}
+
+// SetCommands sets the commands the action runs, shown while toggled on.
+func (c *ConfirmationOverlay) SetCommands(commands []string) {
+	if commands == nil {
+		commands = []string{}
+	}
+	c.commands = commands
+}
+
+// PendingCmd returns the command loading the action's commands once, if they were requested.
+func (c *ConfirmationOverlay) PendingCmd() tea.Cmd {
+	cmd := c.pending
+	c.pending = nil
+	return cmd
+}
+
 // IsConfirmed returns true if the user confirmed the action
 func (c *ConfirmationOverlay) IsConfirmed() bool {
 	return c.confirmed
 }
 
+// SelectedChoice returns the key of the selected choice, or "" if none was selected
+func (c *ConfirmationOverlay) SelectedChoice() string {
+	return c.selected
+}
+
 // Render renders the confirmation overlay
 func (c *ConfirmationOverlay) Render(opts ...WhitespaceOption) string {
 	style := lipgloss.NewStyle().
@@ -76,16 +271,83 @@ func (c *ConfirmationOverlay) Render(opts ...WhitespaceOption) string {
 		Padding(1, 2).
 		Width(c.width)
 
+	if len(c.choices) > 0 {
+		return style.Render(c.renderChoices())
+	}
+	if c.phrase != "" {
+		return style.Render(c.renderTyped())
+	}
+
 	// Add the confirmation instructions
-	content := c.message + "\n\n" +
+	content := c.message + c.renderCommands() + "\n\n" +
 		"Press " + lipgloss.NewStyle().Bold(true).Render(c.ConfirmKey) + " to confirm, " +
 		lipgloss.NewStyle().Bold(true).Render(c.CancelKey) + " or " +
-		lipgloss.NewStyle().Bold(true).Render("esc") + " to cancel"
+		lipgloss.NewStyle().Bold(true).Render("esc") + " to cancel" + c.commandsHint()
 
 	// Apply the border style and return
 	return style.Render(content)
 }
 
+// renderChoices renders the message followed by one line per choice
+func (c *ConfirmationOverlay) renderChoices() string {
+	keyStyle := lipgloss.NewStyle().Bold(true)
+
+	var b strings.Builder
+	b.WriteString(c.message)
+	b.WriteString("\n")
+	for _, choice := range c.choices {
+		b.WriteString("\n" + keyStyle.Render(choice.Key) + "  " + choice.Label)
+	}
+	b.WriteString(c.renderCommands())
+	b.WriteString("\n\nPress " + keyStyle.Render("esc") + " to cancel" + c.commandsHint())
+	return b.String()
+}
+
+// renderTyped renders the message, the phrase to type and what was typed so far
+func (c *ConfirmationOverlay) renderTyped() string {
+	keyStyle := lipgloss.NewStyle().Bold(true)
+	inputStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#de613e"))
+	if c.typed == c.phrase {
+		inputStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("#51bd73"))
+	}
+
+	var b strings.Builder
+	b.WriteString(c.message)
+	b.WriteString("\n\nType " + keyStyle.Render(c.phrase) + " to confirm:\n")
+	b.WriteString("> " + inputStyle.Render(c.typed) + "█")
+	b.WriteString(c.renderCommands())
+	b.WriteString("\n\nPress " + keyStyle.Render("enter") + " to confirm, " + keyStyle.Render("esc") +
+		" to cancel" + c.commandsHint())
+	return b.String()
+}
+
+// renderCommands renders the commands the action runs, if they're shown.
+func (c *ConfirmationOverlay) renderCommands() string {
+	if !c.showCommands {
+		return ""
+	}
+	mutedStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#888888"))
+	switch {
+	case c.commands == nil:
+		return "\n\n" + mutedStyle.Render("Loading commands...")
+	case len(c.commands) == 0:
+		return "\n\n" + mutedStyle.Render("No commands to run")
+	}
+	return "\n\n" + mutedStyle.Render(strings.Join(c.commands, "\n"))
+}
+
+// commandsHint tells how to toggle the commands, if they're offered.
+func (c *ConfirmationOverlay) commandsHint() string {
+	if c.LoadCommands == nil {
+		return ""
+	}
+	action := "show"
+	if c.showCommands {
+		action = "hide"
+	}
+	return ", " + lipgloss.NewStyle().Bold(true).Render(c.commandsKey()) + " to " + action + " commands"
+}
+
 // SetWidth sets the width of the confirmation overlay
 func (c *ConfirmationOverlay) SetWidth(width int) {
 	c.width = width
@@ -0,0 +1,280 @@
+package overlay
+
+import (
+	"claude-squad/session/git"
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// ConflictAction is what a key press in the conflict overlay asks the app to do.
+type ConflictAction int
+
+const (
+	// ConflictActionNone needs nothing from the app
+	ConflictActionNone ConflictAction = iota
+	// ConflictActionClose hides the overlay, leaving the rebase in progress
+	ConflictActionClose
+	// ConflictActionSave writes the current file with the chosen sides
+	ConflictActionSave
+	// ConflictActionTakeOurs resolves the whole current file with our version
+	ConflictActionTakeOurs
+	// ConflictActionTakeTheirs resolves the whole current file with their version
+	ConflictActionTakeTheirs
+	// ConflictActionOpenFile opens the current file in the IDE
+	ConflictActionOpenFile
+	// ConflictActionMergeTool resolves the current file in the external merge tool
+	ConflictActionMergeTool
+	// ConflictActionContinue continues the rebase
+	ConflictActionContinue
+	// ConflictActionAbort aborts the rebase
+	ConflictActionAbort
+)
+
+// ConflictOverlay lists the conflicted files of a rebase and lets the user pick a side for each
+// conflict hunk.
+type ConflictOverlay struct {
+	title string
+	files []*git.FileConflict
+	// choices holds the side picked for each hunk, by file path
+	choices map[string][]git.ConflictChoice
+	file    int
+	hunk    int
+	width   int
+	height  int
+}
+
+// NewConflictOverlay creates an overlay for resolving files.
+func NewConflictOverlay(title string, files []*git.FileConflict) *ConflictOverlay {
+	c := &ConflictOverlay{
+		title:   title,
+		choices: make(map[string][]git.ConflictChoice),
+		width:   100,
+		height:  30,
+	}
+	c.SetFiles(files)
+	return c
+}
+
+// SetFiles replaces the conflicted files, keeping the selected file and the choices of files
+// whose hunks are unchanged.
+func (c *ConflictOverlay) SetFiles(files []*git.FileConflict) {
+	selected := ""
+	if current := c.CurrentFile(); current != nil {
+		selected = current.Path
+	}
+
+	c.files = files
+	c.file, c.hunk = 0, 0
+	choices := make(map[string][]git.ConflictChoice, len(files))
+	for i, file := range files {
+		if previous, ok := c.choices[file.Path]; ok && len(previous) == len(file.Hunks) {
+			choices[file.Path] = previous
+		} else {
+			choices[file.Path] = make([]git.ConflictChoice, len(file.Hunks))
+		}
+		if file.Path == selected {
+			c.file = i
+		}
+	}
+	c.choices = choices
+}
+
+// CurrentFile returns the selected file, or nil if no conflicts are left.
+func (c *ConflictOverlay) CurrentFile() *git.FileConflict {
+	if c.file < 0 || c.file >= len(c.files) {
+		return nil
+	}
+	return c.files[c.file]
+}
+
+// CurrentChoices returns the sides picked for the selected file's hunks.
+func (c *ConflictOverlay) CurrentChoices() []git.ConflictChoice {
+	file := c.CurrentFile()
+	if file == nil {
+		return nil
+	}
+	return c.choices[file.Path]
+}
+
+// Remaining returns how many files still have conflicts.
+func (c *ConflictOverlay) Remaining() int {
+	return len(c.files)
+}
+
+// choose sets the side of the selected hunk and moves on to the next one.
+func (c *ConflictOverlay) choose(choice git.ConflictChoice) {
+	file := c.CurrentFile()
+	if file == nil || len(file.Hunks) == 0 {
+		return
+	}
+	c.choices[file.Path][c.hunk] = choice
+	if c.hunk < len(file.Hunks)-1 {
+		c.hunk++
+	}
+}
+
+// selectFile moves to the file delta places away, wrapping around.
+func (c *ConflictOverlay) selectFile(delta int) {
+	if len(c.files) == 0 {
+		return
+	}
+	c.file = (c.file + delta + len(c.files)) % len(c.files)
+	c.hunk = 0
+}
+
+// HandleKeyPress processes a key press and returns what the app should do.
+func (c *ConflictOverlay) HandleKeyPress(msg tea.KeyMsg) ConflictAction {
+	switch msg.String() {
+	case "esc", "q":
+		return ConflictActionClose
+	case "tab", "right", "l":
+		c.selectFile(1)
+	case "shift+tab", "left", "h":
+		c.selectFile(-1)
+	case "up", "k":
+		if c.hunk > 0 {
+			c.hunk--
+		}
+	case "down", "j":
+		if file := c.CurrentFile(); file != nil && c.hunk < len(file.Hunks)-1 {
+			c.hunk++
+		}
+	case "o":
+		c.choose(git.ConflictOurs)
+	case "t":
+		c.choose(git.ConflictTheirs)
+	case "b":
+		c.choose(git.ConflictBoth)
+	case "u":
+		c.choose(git.ConflictUnresolved)
+	case "enter", "s":
+		if c.CurrentFile() != nil {
+			return ConflictActionSave
+		}
+	case "O":
+		if c.CurrentFile() != nil {
+			return ConflictActionTakeOurs
+		}
+	case "T":
+		if c.CurrentFile() != nil {
+			return ConflictActionTakeTheirs
+		}
+	case "e":
+		if c.CurrentFile() != nil {
+			return ConflictActionOpenFile
+		}
+	case "m":
+		if c.CurrentFile() != nil {
+			return ConflictActionMergeTool
+		}
+	case "c":
+		return ConflictActionContinue
+	case "A":
+		return ConflictActionAbort
+	}
+	return ConflictActionNone
+}
+
+// SetSize sets the overlay's dimensions.
+func (c *ConflictOverlay) SetSize(width, height int) {
+	c.width = width
+	c.height = height
+}
+
+var conflictChoiceLabels = map[git.ConflictChoice]string{
+	git.ConflictUnresolved: "unresolved",
+	git.ConflictOurs:       "ours",
+	git.ConflictTheirs:     "theirs",
+	git.ConflictBoth:       "both",
+}
+
+// Render renders the overlay.
+func (c *ConflictOverlay) Render() string {
+	accent := lipgloss.Color("#7D56F4")
+	style := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(accent).
+		Padding(1, 2).
+		Width(c.width)
+	titleStyle := lipgloss.NewStyle().Bold(true).Foreground(accent)
+	selectedStyle := lipgloss.NewStyle().Background(accent).Foreground(lipgloss.Color("#FAFAFA"))
+	mutedStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#666666"))
+	oursStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#0ea5e9"))
+	theirsStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#22c55e"))
+
+	lines := []string{titleStyle.Render(c.title), ""}
+	if len(c.files) == 0 {
+		lines = append(lines,
+			"All conflicts in this commit are resolved.",
+			"",
+			mutedStyle.Render("c continue rebase • A abort rebase • esc close"))
+		return style.Render(strings.Join(lines, "\n"))
+	}
+
+	var tabs []string
+	for i, file := range c.files {
+		picked := 0
+		for _, choice := range c.choices[file.Path] {
+			if choice != git.ConflictUnresolved {
+				picked++
+			}
+		}
+		tab := fmt.Sprintf(" %s %d/%d ", file.Path, picked, len(file.Hunks))
+		if i == c.file {
+			tab = selectedStyle.Render(tab)
+		}
+		tabs = append(tabs, tab)
+	}
+	lines = append(lines, fmt.Sprintf("Conflicted files (%d):", len(c.files)), strings.Join(tabs, " "), "")
+
+	file := c.CurrentFile()
+	textWidth := c.width - 8
+	if len(file.Hunks) == 0 {
+		lines = append(lines,
+			"This file has no conflict markers: one side deleted or added it.",
+			"Keep ours (O) or theirs (T) for the whole file.")
+	} else {
+		hunk := file.Hunks[c.hunk]
+		choices := c.choices[file.Path]
+		lines = append(lines, fmt.Sprintf("Hunk %d/%d at line %d: %s", c.hunk+1, len(file.Hunks), hunk.Line,
+			conflictChoiceLabels[choices[c.hunk]]))
+
+		// Split the remaining height between the two sides
+		sideHeight := (c.height - 22) / 2
+		if sideHeight < 2 {
+			sideHeight = 2
+		}
+		lines = append(lines, "", oursStyle.Render(fmt.Sprintf("ours — main being rebased onto (%s):", hunk.OursLabel)))
+		lines = append(lines, conflictSide(hunk.Ours, sideHeight, textWidth, mutedStyle)...)
+		lines = append(lines, "", theirsStyle.Render(fmt.Sprintf("theirs — your commit (%s):", hunk.TheirsLabel)))
+		lines = append(lines, conflictSide(hunk.Theirs, sideHeight, textWidth, mutedStyle)...)
+	}
+
+	lines = append(lines, "",
+		mutedStyle.Render("j/k hunk • tab file • o ours • t theirs • b both • u undo • enter save file"),
+		mutedStyle.Render("O/T whole file • e open in IDE • m merge tool • c continue rebase • A abort • esc close"))
+	return style.Render(strings.Join(lines, "\n"))
+}
+
+// conflictSide renders up to height lines of one side of a hunk, cut to width.
+func conflictSide(side []string, height, width int, mutedStyle lipgloss.Style) []string {
+	if len(side) == 0 {
+		return []string{mutedStyle.Render("  (no lines)")}
+	}
+	var lines []string
+	for i, line := range side {
+		if i == height {
+			lines = append(lines, mutedStyle.Render(fmt.Sprintf("  ... %d more line(s)", len(side)-height)))
+			break
+		}
+		line = "  " + strings.ReplaceAll(strings.TrimRight(line, "\r"), "\t", "    ")
+		if width > 3 && len(line) > width {
+			line = line[:width-3] + "..."
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
@@ -1,9 +1,15 @@
 package overlay
 
 import (
+	"claude-squad/ui"
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
 	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
 )
 
 // HistoryOverlay represents a scrollable history view overlay
@@ -12,6 +18,11 @@ type HistoryOverlay struct {
 	Dismissed bool
 	// Callback function to be called when the overlay is dismissed
 	OnDismiss func()
+	// OnExport, if set, is called when e is pressed; the command it returns is returned by the
+	// next Update
+	OnExport func() tea.Cmd
+	// pending is the command returned by OnExport, waiting for Update
+	pending tea.Cmd
 	// Title of the overlay
 	title string
 	// Viewport for scrollable content
@@ -21,39 +32,200 @@ type HistoryOverlay struct {
 	height int
 	// Help text shown at the bottom
 	helpText string
+	// scrollAccel speeds up line scrolling while a key is held
+	scrollAccel ui.ScrollAccelerator
+	// fileHeaders are the content lines that start a new file
+	fileHeaders []int
+	// views are the contents the overlay can switch between; active is the one shown
+	views  []HistoryView
+	active int
+	// offsets remember the scroll position of each view, -1 meaning the bottom
+	offsets []int
+	// layout wraps or pans lines wider than the overlay
+	layout ui.LineLayout
+	// rows maps each line of the active view to its first row in the viewport
+	rows []int
+	// search is the input shown while typing a query after /
+	search    textinput.Model
+	searching bool
+	// query is the search highlighted in the active view; match indexes the current one
+	query   string
+	matches []ui.SearchMatch
+	match   int
+}
+
+// HistoryView is one of the contents a HistoryOverlay can switch between.
+type HistoryView struct {
+	Name    string
+	Content string
 }
 
 // NewHistoryOverlay creates a new history overlay with the given title and content
 func NewHistoryOverlay(title string, content string) *HistoryOverlay {
+	return NewTabbedHistoryOverlay(title, []HistoryView{{Content: content}}, 0)
+}
+
+// NewTabbedHistoryOverlay creates a history overlay holding several views, switched with tab,
+// starting with the active one.
+func NewTabbedHistoryOverlay(title string, views []HistoryView, active int) *HistoryOverlay {
+	search := textinput.New()
+	search.Prompt = "/"
+	search.Placeholder = "search"
+	search.CharLimit = 200
+
 	h := &HistoryOverlay{
 		Dismissed: false,
 		title:     title,
 		viewport:  viewport.New(0, 0),
-		helpText:  "↑/↓ to scroll • ESC to close",
+		helpText:  "↑/↓ scroll • ctrl+u/d half page • pgup/pgdn page • ctrl+↑/↓ jump • alt+↑/↓ file • ←/→ pan • w wrap • / search • ESC to close",
+		views:     views,
+		offsets:   make([]int, len(views)),
+		search:    search,
 	}
-	h.viewport.SetContent(content)
+	if len(views) > 1 {
+		h.helpText = "tab switch view • " + h.helpText
+	}
+	for i := range h.offsets {
+		h.offsets[i] = -1
+	}
+	h.showView(active)
 	return h
 }
 
+// showView switches to view idx, restoring its scroll position.
+func (h *HistoryOverlay) showView(idx int) {
+	if len(h.views) == 0 {
+		return
+	}
+	if h.active < len(h.offsets) && h.viewport.Height > 0 {
+		if h.viewport.AtBottom() {
+			h.offsets[h.active] = -1
+		} else {
+			h.offsets[h.active] = h.viewport.YOffset
+		}
+	}
+
+	h.active = (idx + len(h.views)) % len(h.views)
+	h.matches = ui.FindMatches(h.views[h.active].Content, h.query)
+	h.match = 0
+	h.applyLayout()
+	if h.offsets[h.active] < 0 {
+		h.viewport.GotoBottom()
+	} else {
+		h.viewport.SetYOffset(h.offsets[h.active])
+	}
+}
+
+// applyLayout lays the active view out for the viewport's width, keeping the scroll position.
+func (h *HistoryOverlay) applyLayout() {
+	if len(h.views) == 0 {
+		return
+	}
+	raw := h.views[h.active].Content
+	content, rows := h.layout.Apply(ui.HighlightMatches(raw, h.matches, h.match), h.viewport.Width)
+	offset := h.viewport.YOffset
+	h.viewport.SetContent(content)
+	h.viewport.SetYOffset(offset)
+	h.rows = rows
+	h.fileHeaders = ui.MapRows(ui.FileHeaderLines(raw), rows)
+}
+
+// setQuery searches the active view for query, making the first match from the top of the
+// viewport down the current one.
+func (h *HistoryOverlay) setQuery(query string) {
+	h.query = query
+	h.matches = ui.FindMatches(h.views[h.active].Content, query)
+	h.match = 0
+	for i, match := range h.matches {
+		if match.Line < len(h.rows) && h.rows[match.Line] >= h.viewport.YOffset {
+			h.match = i
+			break
+		}
+	}
+	h.showMatch()
+}
+
+// moveMatch moves the current match by delta, wrapping around at either end.
+func (h *HistoryOverlay) moveMatch(delta int) {
+	if len(h.matches) == 0 {
+		return
+	}
+	h.match = ((h.match+delta)%len(h.matches) + len(h.matches)) % len(h.matches)
+	h.showMatch()
+}
+
+// showMatch highlights the current match and scrolls it into view, panning to it if lines
+// aren't wrapped.
+func (h *HistoryOverlay) showMatch() {
+	if len(h.matches) == 0 {
+		h.applyLayout()
+		return
+	}
+	match := h.matches[h.match]
+	if !h.layout.Wrap {
+		line := ansi.Strip(strings.Split(h.views[h.active].Content, "\n")[match.Line])
+		start := ansi.StringWidth(line[:match.Start])
+		end := ansi.StringWidth(line[:match.End])
+		if start < h.layout.XOffset || end > h.layout.XOffset+h.viewport.Width {
+			h.layout.XOffset = max(0, start-h.viewport.Width/3)
+		}
+	}
+	h.applyLayout()
+	if match.Line < len(h.rows) {
+		h.viewport.SetYOffset(h.rows[match.Line] - h.viewport.Height/2)
+	}
+}
+
+// clearSearch removes the search and its highlights.
+func (h *HistoryOverlay) clearSearch() {
+	h.query = ""
+	h.matches = nil
+	h.match = 0
+	h.applyLayout()
+}
+
+// handleSearchKey processes a key press while a search query is being typed.
+func (h *HistoryOverlay) handleSearchKey(msg tea.KeyMsg) {
+	switch msg.String() {
+	case "enter":
+		h.searching = false
+		h.search.Blur()
+		if h.query == "" {
+			h.clearSearch()
+		}
+	case "esc", "ctrl+c":
+		h.searching = false
+		h.search.Blur()
+		h.clearSearch()
+	default:
+		h.search, _ = h.search.Update(msg)
+		if value := h.search.Value(); value != h.query {
+			h.setQuery(value)
+		}
+	}
+}
+
+// searchStatus describes the search for the help line.
+func (h *HistoryOverlay) searchStatus() string {
+	if len(h.matches) == 0 {
+		return "no matches"
+	}
+	return fmt.Sprintf("match %d of %d", h.match+1, len(h.matches))
+}
+
 // SetSize updates the dimensions of the overlay
 func (h *HistoryOverlay) SetSize(width, height int) {
 	h.width = width
 	h.height = height
 
-	// Calculate viewport dimensions (account for borders, padding, title, and help text)
-	// Border: 2 lines (top/bottom), padding: 2 lines, title: 2 lines, help: 2 lines
-	viewportHeight := height - 8
 	viewportWidth := width - 4 // Border and padding on sides
-
-	if viewportHeight < 1 {
-		viewportHeight = 1
-	}
 	if viewportWidth < 1 {
 		viewportWidth = 1
 	}
 
 	h.viewport.Width = viewportWidth
-	h.viewport.Height = viewportHeight
+	h.viewport.Height = h.viewportHeight(lipgloss.Height(h.renderHelp()))
+	h.applyLayout()
 
 	// After setting dimensions, position at bottom to show most recent content
 	h.viewport.GotoBottom()
@@ -62,25 +234,61 @@ func (h *HistoryOverlay) SetSize(width, height int) {
 // HandleKeyPress processes a key press and updates the state
 // Returns true if the overlay should be closed
 func (h *HistoryOverlay) HandleKeyPress(msg tea.KeyMsg) bool {
+	if h.searching {
+		h.handleSearchKey(msg)
+		return false
+	}
+
 	switch msg.String() {
-	case "esc", "ctrl+c", "q":
+	case "esc":
+		// Clear a search before closing
+		if h.query != "" {
+			h.clearSearch()
+			return false
+		}
 		h.Dismissed = true
 		if h.OnDismiss != nil {
 			h.OnDismiss()
 		}
 		return true
-	case "up", "k":
-		h.viewport.LineUp(1)
-	case "down", "j":
-		h.viewport.LineDown(1)
-	case "pgup":
-		h.viewport.HalfViewUp()
-	case "pgdown":
-		h.viewport.HalfViewDown()
-	case "home", "ctrl+home":
-		h.viewport.GotoTop()
-	case "end", "ctrl+end":
-		h.viewport.GotoBottom()
+	case "ctrl+c", "q":
+		h.Dismissed = true
+		if h.OnDismiss != nil {
+			h.OnDismiss()
+		}
+		return true
+	case "tab":
+		h.showView(h.active + 1)
+	case "shift+tab":
+		h.showView(h.active - 1)
+	case "alt+up":
+		ui.JumpToFileHeader(&h.viewport, h.fileHeaders, -1)
+	case "alt+down":
+		ui.JumpToFileHeader(&h.viewport, h.fileHeaders, 1)
+	case "/":
+		h.searching = true
+		h.search.SetValue(h.query)
+		h.search.CursorEnd()
+		h.search.Focus()
+	case "e":
+		if h.OnExport != nil {
+			h.pending = h.OnExport()
+		}
+	case "n":
+		h.moveMatch(1)
+	case "N":
+		h.moveMatch(-1)
+	case "w":
+		h.layout.ToggleWrap()
+		h.applyLayout()
+	case "left", "h":
+		h.layout.Pan(-ui.PanColumns)
+		h.applyLayout()
+	case "right", "l":
+		h.layout.Pan(ui.PanColumns)
+		h.applyLayout()
+	default:
+		ui.HandleViewportScrollKey(&h.viewport, &h.scrollAccel, msg.String())
 	}
 	return false
 }
@@ -93,11 +301,6 @@ func (h *HistoryOverlay) Render(opts ...WhitespaceOption) string {
 		Foreground(lipgloss.Color("62")).
 		MarginBottom(1)
 
-	// Help text style
-	helpStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("240")).
-		MarginTop(1)
-
 	// Container style
 	containerStyle := lipgloss.NewStyle().
 		Border(lipgloss.RoundedBorder()).
@@ -107,20 +310,83 @@ func (h *HistoryOverlay) Render(opts ...WhitespaceOption) string {
 		Height(h.height - 2)
 
 	// Build the content
-	content := lipgloss.JoinVertical(
-		lipgloss.Center,
-		titleStyle.Render(h.title),
-		h.viewport.View(),
-		helpStyle.Render(h.helpText),
-	)
+	sections := []string{titleStyle.Render(h.title)}
+	if len(h.views) > 1 {
+		activeTabStyle := lipgloss.NewStyle().
+			Bold(true).
+			Foreground(lipgloss.Color("230")).
+			Background(lipgloss.Color("62")).
+			Padding(0, 1)
+		tabStyle := lipgloss.NewStyle().
+			Foreground(lipgloss.Color("245")).
+			Padding(0, 1)
+
+		tabs := make([]string, len(h.views))
+		for i, view := range h.views {
+			if i == h.active {
+				tabs[i] = activeTabStyle.Render(view.Name)
+			} else {
+				tabs[i] = tabStyle.Render(view.Name)
+			}
+		}
+		sections = append(sections, lipgloss.JoinHorizontal(lipgloss.Top, tabs...))
+	}
+	// The help changes while searching, so the viewport makes room for it as it's rendered
+	help := h.renderHelp()
+	h.viewport.Height = h.viewportHeight(lipgloss.Height(help))
+	sections = append(sections, h.viewport.View(), help)
+	content := lipgloss.JoinVertical(lipgloss.Center, sections...)
 
 	return containerStyle.Render(content)
 }
 
+// viewportHeight returns the height left for the viewport by the border (2 lines), the padding
+// (2 lines), the title (2 lines), the view tabs and the help, helpHeight lines tall.
+func (h *HistoryOverlay) viewportHeight(helpHeight int) int {
+	height := h.height - 6 - helpHeight
+	if len(h.views) > 1 {
+		height-- // view tabs
+	}
+	return max(1, height)
+}
+
+// renderHelp renders the help under the viewport, wrapped to its width so it doesn't widen the
+// overlay.
+func (h *HistoryOverlay) renderHelp() string {
+	help := h.helpText
+	if h.OnExport != nil {
+		help = "e export • " + help
+	}
+	switch {
+	case h.searching:
+		help = h.search.View()
+		if h.query != "" {
+			help += "  " + h.searchStatus()
+		}
+		help += " • enter done • ESC cancel"
+	case h.query != "":
+		help = fmt.Sprintf("/%s: %s • n/N next/prev • ESC clear • ", h.query, h.searchStatus()) + help
+	}
+	return lipgloss.NewStyle().
+		Foreground(lipgloss.Color("240")).
+		MarginTop(1).
+		Width(h.viewport.Width).
+		Align(lipgloss.Center).
+		Render(help)
+}
+
 // Update handles viewport updates
 func (h *HistoryOverlay) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	// Keys typed into the search aren't for scrolling
+	if _, ok := msg.(tea.KeyMsg); ok && h.searching {
+		return h, nil
+	}
 	var cmd tea.Cmd
 	h.viewport, cmd = h.viewport.Update(msg)
+	if h.pending != nil {
+		cmd = tea.Batch(cmd, h.pending)
+		h.pending = nil
+	}
 	return h, cmd
 }
 
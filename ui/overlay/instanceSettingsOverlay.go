@@ -0,0 +1,230 @@
+package overlay
+
+import (
+	"claude-squad/session"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// onOff are the options of the settings toggled on and off.
+var onOff = []string{"on", "off"}
+
+// InstanceSettings are the settings of an instance that can change while it runs.
+type InstanceSettings struct {
+	AutoYes       bool
+	Notifications bool
+	// TimeBudget is zero for no budget
+	TimeBudget time.Duration
+	Tags       []string
+}
+
+// InstanceSettingsOverlay is a form editing the settings of a running instance.
+type InstanceSettingsOverlay struct {
+	title         string
+	autoYes       int
+	autoYesLocked bool // a policy enforces auto-yes, so it can't be changed
+	notifications int
+	budget        textinput.Model
+	tags          textinput.Model
+	// workTime is how long the agent has worked, shown next to the budget
+	workTime  time.Duration
+	focus     int // 0 auto-yes, 1 notifications, 2 budget, 3 tags
+	err       string
+	submitted bool
+	width     int
+}
+
+// NewInstanceSettingsOverlay creates the form, filled in with the instance's current settings.
+func NewInstanceSettingsOverlay(title string, settings InstanceSettings, workTime time.Duration) *InstanceSettingsOverlay {
+	budget := textinput.New()
+	budget.Placeholder = "e.g. 45m or 2h (empty for none)"
+	budget.Prompt = ""
+	budget.CharLimit = 20
+	if settings.TimeBudget > 0 {
+		budget.SetValue(settings.TimeBudget.String())
+	}
+
+	tags := textinput.New()
+	tags.Placeholder = "comma separated"
+	tags.Prompt = ""
+	tags.CharLimit = 200
+	tags.SetValue(strings.Join(settings.Tags, ", "))
+
+	o := &InstanceSettingsOverlay{
+		title:    title,
+		budget:   budget,
+		tags:     tags,
+		workTime: workTime,
+		width:    70,
+	}
+	if !settings.AutoYes {
+		o.autoYes = 1
+	}
+	if !settings.Notifications {
+		o.notifications = 1
+	}
+	return o
+}
+
+// LockAutoYes keeps auto-yes from being changed, e.g. when a policy enforces it.
+func (o *InstanceSettingsOverlay) LockAutoYes() {
+	o.autoYesLocked = true
+}
+
+func (o *InstanceSettingsOverlay) setFocus(focus int) {
+	o.focus = (focus + 4) % 4
+	o.budget.Blur()
+	o.tags.Blur()
+	switch o.focus {
+	case 2:
+		o.budget.Focus()
+	case 3:
+		o.tags.Focus()
+	}
+}
+
+// HandleKeyPress processes a key press and returns true when the overlay should close. Saving
+// with a budget that isn't a duration keeps the overlay open and shows the error.
+func (o *InstanceSettingsOverlay) HandleKeyPress(msg tea.KeyMsg) bool {
+	switch msg.String() {
+	case "esc", "ctrl+c":
+		return true
+	case "enter":
+		if _, err := o.timeBudget(); err != nil {
+			o.err = err.Error()
+			o.setFocus(2)
+			return false
+		}
+		o.submitted = true
+		return true
+	case "tab", "down":
+		o.setFocus(o.focus + 1)
+		return false
+	case "shift+tab", "up":
+		o.setFocus(o.focus - 1)
+		return false
+	}
+
+	switch o.focus {
+	case 0:
+		if !o.autoYesLocked {
+			o.autoYes = cycle(o.autoYes, len(onOff), msg.String())
+		}
+	case 1:
+		o.notifications = cycle(o.notifications, len(onOff), msg.String())
+	case 2:
+		o.budget, _ = o.budget.Update(msg)
+		o.err = ""
+	case 3:
+		o.tags, _ = o.tags.Update(msg)
+	}
+	return false
+}
+
+// timeBudget parses the budget field. An empty field is no budget.
+func (o *InstanceSettingsOverlay) timeBudget() (time.Duration, error) {
+	text := strings.TrimSpace(o.budget.Value())
+	if text == "" {
+		return 0, nil
+	}
+	budget, err := time.ParseDuration(text)
+	if err != nil || budget <= 0 {
+		return 0, fmt.Errorf("time budget must be a positive duration like 45m or 2h")
+	}
+	return budget, nil
+}
+
+// IsSubmitted returns true if the user saved the settings rather than cancelling.
+func (o *InstanceSettingsOverlay) IsSubmitted() bool {
+	return o.submitted
+}
+
+// Settings returns the settings as edited.
+func (o *InstanceSettingsOverlay) Settings() InstanceSettings {
+	budget, _ := o.timeBudget()
+	return InstanceSettings{
+		AutoYes:       o.autoYes == 0,
+		Notifications: o.notifications == 0,
+		TimeBudget:    budget,
+		Tags:          session.ParseTags(o.tags.Value()),
+	}
+}
+
+func (o *InstanceSettingsOverlay) SetSize(width, height int) {
+	o.width = width
+	o.budget.Width = width - 24
+	o.tags.Width = width - 24
+}
+
+// Render renders the overlay.
+func (o *InstanceSettingsOverlay) Render() string {
+	style := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("#7D56F4")).
+		Padding(1, 2).
+		Width(o.width)
+
+	titleStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("#7D56F4"))
+
+	labelStyle := lipgloss.NewStyle().Width(16)
+	focusedLabelStyle := labelStyle.Foreground(lipgloss.Color("#7D56F4")).Bold(true)
+
+	selectedStyle := lipgloss.NewStyle().
+		Background(lipgloss.Color("#7D56F4")).
+		Foreground(lipgloss.Color("#FAFAFA")).
+		Padding(0, 1)
+
+	optionStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("#AAAAAA")).
+		Padding(0, 1)
+
+	mutedStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("#666666"))
+
+	errorStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("#FF0000"))
+
+	label := func(idx int, text string) string {
+		if o.focus == idx {
+			return focusedLabelStyle.Render("> " + text)
+		}
+		return labelStyle.Render("  " + text)
+	}
+	options := func(selected int) string {
+		rendered := make([]string, len(onOff))
+		for i, value := range onOff {
+			if i == selected {
+				rendered[i] = selectedStyle.Render(value)
+			} else {
+				rendered[i] = optionStyle.Render(value)
+			}
+		}
+		return strings.Join(rendered, " ")
+	}
+
+	autoYes := options(o.autoYes)
+	if o.autoYesLocked {
+		autoYes += " " + mutedStyle.Render("set by policy")
+	}
+	lines := []string{
+		titleStyle.Render(o.title),
+		"",
+		label(0, "Auto-yes") + autoYes,
+		label(1, "Notifications") + options(o.notifications),
+		label(2, "Time budget") + o.budget.View(),
+		labelStyle.Render("") + mutedStyle.Render(fmt.Sprintf("worked %s so far", o.workTime.Round(time.Second))),
+		label(3, "Tags") + o.tags.View(),
+	}
+	if o.err != "" {
+		lines = append(lines, "", errorStyle.Render(o.err))
+	}
+	lines = append(lines, "", mutedStyle.Render("↑/↓ field • ←/→ choose • enter save • esc cancel"))
+	return style.Render(strings.Join(lines, "\n"))
+}
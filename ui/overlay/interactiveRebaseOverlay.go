@@ -0,0 +1,211 @@
+package overlay
+
+import (
+	"claude-squad/session/git"
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// InteractiveRebaseOverlay lists a branch's commits, oldest first as git's todo list does, and
+// lets the user reorder, reword, squash and drop them before rewriting the branch.
+type InteractiveRebaseOverlay struct {
+	title    string
+	steps    []git.RebaseStep
+	original []git.RebaseStep
+	selected int
+	// input is set while the selected commit's message is being reworded
+	input     *TextInputOverlay
+	submitted bool
+	width     int
+	height    int
+}
+
+// NewInteractiveRebaseOverlay creates an overlay editing steps, the branch's commits all picked.
+func NewInteractiveRebaseOverlay(title string, steps []git.RebaseStep) *InteractiveRebaseOverlay {
+	return &InteractiveRebaseOverlay{
+		title:    title,
+		steps:    append([]git.RebaseStep(nil), steps...),
+		original: steps,
+		width:    80,
+		height:   20,
+	}
+}
+
+// HandleKeyPress processes a key press and returns true when the overlay should close, either
+// submitted with enter or cancelled.
+func (r *InteractiveRebaseOverlay) HandleKeyPress(msg tea.KeyMsg) bool {
+	if r.input != nil {
+		r.input.HandleKeyPress(msg)
+		if r.input.IsSubmitted() {
+			step := &r.steps[r.selected]
+			if message := strings.TrimSpace(r.input.GetValue()); message != "" && message != r.original[r.originalIndex(step)].Message {
+				step.Action, step.Message = git.RebaseReword, message
+			}
+			r.input = nil
+		} else if r.input.IsCanceled() {
+			r.input = nil
+		}
+		return false
+	}
+
+	switch msg.String() {
+	case "esc", "q":
+		return true
+	case "enter":
+		r.submitted = true
+		return true
+	case "up", "k":
+		if r.selected > 0 {
+			r.selected--
+		}
+	case "down", "j":
+		if r.selected < len(r.steps)-1 {
+			r.selected++
+		}
+	case "shift+up", "K":
+		r.move(-1)
+	case "shift+down", "J":
+		r.move(1)
+	case "p":
+		r.setAction(git.RebasePick)
+	case "s":
+		r.setAction(git.RebaseSquash)
+	case "d", "x":
+		r.setAction(git.RebaseDrop)
+	case "r":
+		if len(r.steps) > 0 {
+			r.input = NewTextInputOverlay(fmt.Sprintf("Reword %s", r.steps[r.selected].Commit.ShortHash), r.steps[r.selected].Message)
+			r.input.SetSize(r.width-10, 8)
+		}
+	}
+	return false
+}
+
+// move moves the selected commit delta places, keeping it selected.
+func (r *InteractiveRebaseOverlay) move(delta int) {
+	to := r.selected + delta
+	if len(r.steps) == 0 || to < 0 || to >= len(r.steps) {
+		return
+	}
+	r.steps[r.selected], r.steps[to] = r.steps[to], r.steps[r.selected]
+	r.selected = to
+}
+
+// setAction sets the action of the selected commit, restoring its message unless reworded.
+func (r *InteractiveRebaseOverlay) setAction(action git.RebaseAction) {
+	if len(r.steps) == 0 {
+		return
+	}
+	step := &r.steps[r.selected]
+	step.Action = action
+	step.Message = r.original[r.originalIndex(step)].Message
+}
+
+// originalIndex returns the index of the step's commit in the branch's order.
+func (r *InteractiveRebaseOverlay) originalIndex(step *git.RebaseStep) int {
+	for i, original := range r.original {
+		if original.Commit.Hash == step.Commit.Hash {
+			return i
+		}
+	}
+	return 0
+}
+
+// Submitted returns true if the overlay was closed to rewrite the branch.
+func (r *InteractiveRebaseOverlay) Submitted() bool {
+	return r.submitted
+}
+
+// Steps returns the edited steps, in their new order.
+func (r *InteractiveRebaseOverlay) Steps() []git.RebaseStep {
+	return r.steps
+}
+
+// Changed returns true if the steps do anything but pick the commits in their order.
+func (r *InteractiveRebaseOverlay) Changed() bool {
+	for i, step := range r.steps {
+		if step.Action != git.RebasePick || step.Commit.Hash != r.original[i].Commit.Hash {
+			return true
+		}
+	}
+	return false
+}
+
+// SetSize sets the overlay's dimensions.
+func (r *InteractiveRebaseOverlay) SetSize(width, height int) {
+	r.width = width
+	r.height = height
+	if r.input != nil {
+		r.input.SetSize(width-10, 8)
+	}
+}
+
+// Render renders the overlay.
+func (r *InteractiveRebaseOverlay) Render() string {
+	if r.input != nil {
+		return r.input.Render()
+	}
+
+	style := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("#7D56F4")).
+		Padding(1, 2).
+		Width(r.width - 2)
+
+	titleStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("#7D56F4"))
+
+	selectedStyle := lipgloss.NewStyle().
+		Background(lipgloss.Color("#7D56F4")).
+		Foreground(lipgloss.Color("#FAFAFA"))
+
+	mutedStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("#666666"))
+
+	actionStyles := map[git.RebaseAction]lipgloss.Style{
+		git.RebasePick:   lipgloss.NewStyle(),
+		git.RebaseReword: lipgloss.NewStyle().Foreground(lipgloss.Color("#51bd73")),
+		git.RebaseSquash: lipgloss.NewStyle().Foreground(lipgloss.Color("#F0A868")),
+		git.RebaseDrop:   lipgloss.NewStyle().Foreground(lipgloss.Color("#de613e")).Strikethrough(true),
+	}
+
+	lines := []string{titleStyle.Render(r.title), ""}
+	if len(r.steps) == 0 {
+		lines = append(lines, mutedStyle.Render("The branch has no commits of its own."))
+	}
+
+	// Keep the selected commit in view when the branch has more than fit
+	visible := max(1, r.height-10)
+	start := 0
+	if r.selected >= visible {
+		start = r.selected - visible + 1
+	}
+	textWidth := r.width - 22
+	for i := start; i < len(r.steps) && i < start+visible; i++ {
+		step := r.steps[i]
+		subject := step.Commit.Subject
+		if step.Action == git.RebaseReword {
+			subject, _, _ = strings.Cut(step.Message, "\n")
+		}
+		if textWidth > 3 && len(subject) > textWidth {
+			subject = subject[:textWidth-3] + "..."
+		}
+		line := fmt.Sprintf("%-6s %s %s", step.Action, step.Commit.ShortHash, subject)
+		if i == r.selected {
+			line = selectedStyle.Render(line)
+		} else {
+			line = actionStyles[step.Action].Render(line)
+		}
+		lines = append(lines, line)
+	}
+
+	lines = append(lines, "",
+		mutedStyle.Render("Oldest first; squash melds a commit into the kept one above it."),
+		mutedStyle.Render("p pick • r reword • s squash • d drop • K/J move up/down"),
+		mutedStyle.Render("enter rewrite the branch • esc cancel"))
+	return style.Render(strings.Join(lines, "\n"))
+}
@@ -0,0 +1,138 @@
+package overlay
+
+import (
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// ListItem is an entry in a ListSelectorOverlay.
+type ListItem struct {
+	Title       string
+	Description string
+}
+
+// ListSelectorOverlay lets the user pick a single item from a list.
+type ListSelectorOverlay struct {
+	title    string
+	items    []ListItem
+	cursor   int
+	selected int
+	width    int
+	height   int
+}
+
+// NewListSelectorOverlay creates a list selector with the given title and items.
+func NewListSelectorOverlay(title string, items []ListItem) *ListSelectorOverlay {
+	return &ListSelectorOverlay{
+		title:    title,
+		items:    items,
+		selected: -1,
+		width:    80,
+		height:   20,
+	}
+}
+
+// HandleKeyPress processes a key press and returns true when the overlay should close.
+func (l *ListSelectorOverlay) HandleKeyPress(msg tea.KeyMsg) bool {
+	switch msg.String() {
+	case "esc", "ctrl+c", "q":
+		l.selected = -1
+		return true
+	case "enter":
+		if len(l.items) > 0 {
+			l.selected = l.cursor
+		}
+		return true
+	case "up", "k":
+		if l.cursor > 0 {
+			l.cursor--
+		}
+	case "down", "j":
+		if l.cursor < len(l.items)-1 {
+			l.cursor++
+		}
+	}
+	return false
+}
+
+// SelectedIndex returns the index of the chosen item, or -1 if the selection was cancelled.
+func (l *ListSelectorOverlay) SelectedIndex() int {
+	return l.selected
+}
+
+func (l *ListSelectorOverlay) SetSize(width, height int) {
+	l.width = width
+	l.height = height
+}
+
+// Render renders the overlay.
+func (l *ListSelectorOverlay) Render() string {
+	titleStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("#7D56F4")).
+		MarginBottom(1)
+
+	listStyle := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("#7D56F4")).
+		Padding(1, 2).
+		Width(l.width - 4)
+
+	selectedStyle := lipgloss.NewStyle().
+		Background(lipgloss.Color("#7D56F4")).
+		Foreground(lipgloss.Color("#FAFAFA"))
+
+	normalStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("#FAFAFA"))
+
+	mutedStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("#666666"))
+
+	var s strings.Builder
+	s.WriteString(titleStyle.Render(l.title))
+	s.WriteString("\n")
+
+	var list strings.Builder
+	if len(l.items) == 0 {
+		list.WriteString(mutedStyle.Render("Nothing to show"))
+	}
+
+	maxVisible := l.height - 10
+	if maxVisible < 1 {
+		maxVisible = 5
+	}
+	startIdx := 0
+	if l.cursor >= maxVisible {
+		startIdx = l.cursor - maxVisible + 1
+	}
+	endIdx := min(startIdx+maxVisible, len(l.items))
+
+	for i := startIdx; i < endIdx; i++ {
+		item := l.items[i]
+		line := truncateString(item.Title, 40)
+		if item.Description != "" {
+			line = line + "  " + mutedStyle.Render(item.Description)
+		}
+		if i == l.cursor {
+			list.WriteString(selectedStyle.Render("> " + line))
+		} else {
+			list.WriteString(normalStyle.Render("  " + line))
+		}
+		if i < endIdx-1 {
+			list.WriteString("\n")
+		}
+	}
+	if startIdx > 0 {
+		list.WriteString("\n" + mutedStyle.Render("↑ more above"))
+	}
+	if endIdx < len(l.items) {
+		list.WriteString("\n" + mutedStyle.Render("↓ more below"))
+	}
+
+	s.WriteString(listStyle.Render(list.String()))
+	s.WriteString("\n")
+	s.WriteString(mutedStyle.Render("↑/↓ navigate • enter select • esc cancel"))
+	return s.String()
+}
@@ -0,0 +1,170 @@
+package overlay
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// OutcomeOverlay asks for a structured rating of an agent run: a result, an effort estimate and
+// free-text notes.
+type OutcomeOverlay struct {
+	title     string
+	results   []string
+	efforts   []string
+	result    int
+	effort    int
+	notes     textinput.Model
+	focus     int // 0 result, 1 effort, 2 notes
+	submitted bool
+	width     int
+}
+
+// NewOutcomeOverlay creates the rating form. The first result and effort options are preselected.
+func NewOutcomeOverlay(title string, results, efforts []string) *OutcomeOverlay {
+	notes := textinput.New()
+	notes.Placeholder = "what worked, what didn't (optional)"
+	notes.Prompt = ""
+	notes.CharLimit = 500
+	return &OutcomeOverlay{
+		title:   title,
+		results: results,
+		efforts: efforts,
+		notes:   notes,
+		width:   70,
+	}
+}
+
+func (o *OutcomeOverlay) setFocus(focus int) {
+	o.focus = (focus + 3) % 3
+	if o.focus == 2 {
+		o.notes.Focus()
+	} else {
+		o.notes.Blur()
+	}
+}
+
+// HandleKeyPress processes a key press and returns true when the overlay should close.
+func (o *OutcomeOverlay) HandleKeyPress(msg tea.KeyMsg) bool {
+	switch msg.String() {
+	case "esc", "ctrl+c":
+		return true
+	case "enter":
+		o.submitted = true
+		return true
+	case "tab", "down":
+		o.setFocus(o.focus + 1)
+		return false
+	case "shift+tab", "up":
+		o.setFocus(o.focus - 1)
+		return false
+	}
+
+	switch o.focus {
+	case 0:
+		o.result = cycle(o.result, len(o.results), msg.String())
+	case 1:
+		o.effort = cycle(o.effort, len(o.efforts), msg.String())
+	case 2:
+		o.notes, _ = o.notes.Update(msg)
+	}
+	return false
+}
+
+// cycle moves an option index left or right, wrapping around.
+func cycle(idx, n int, key string) int {
+	if n == 0 {
+		return idx
+	}
+	switch key {
+	case "left", "h":
+		return (idx - 1 + n) % n
+	case "right", "l", " ":
+		return (idx + 1) % n
+	}
+	return idx
+}
+
+// IsSubmitted returns true if the user saved the rating rather than skipping it.
+func (o *OutcomeOverlay) IsSubmitted() bool {
+	return o.submitted
+}
+
+// Result returns the selected result.
+func (o *OutcomeOverlay) Result() string {
+	return o.results[o.result]
+}
+
+// Effort returns the selected effort estimate.
+func (o *OutcomeOverlay) Effort() string {
+	return o.efforts[o.effort]
+}
+
+// Notes returns the free-text notes.
+func (o *OutcomeOverlay) Notes() string {
+	return strings.TrimSpace(o.notes.Value())
+}
+
+func (o *OutcomeOverlay) SetSize(width, height int) {
+	o.width = width
+	o.notes.Width = width - 20
+}
+
+// Render renders the overlay.
+func (o *OutcomeOverlay) Render() string {
+	style := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("#7D56F4")).
+		Padding(1, 2).
+		Width(o.width)
+
+	titleStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("#7D56F4"))
+
+	labelStyle := lipgloss.NewStyle().Width(10)
+	focusedLabelStyle := labelStyle.Foreground(lipgloss.Color("#7D56F4")).Bold(true)
+
+	selectedStyle := lipgloss.NewStyle().
+		Background(lipgloss.Color("#7D56F4")).
+		Foreground(lipgloss.Color("#FAFAFA")).
+		Padding(0, 1)
+
+	optionStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("#AAAAAA")).
+		Padding(0, 1)
+
+	mutedStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("#666666"))
+
+	label := func(idx int, text string) string {
+		if o.focus == idx {
+			return focusedLabelStyle.Render("> " + text)
+		}
+		return labelStyle.Render("  " + text)
+	}
+	options := func(values []string, selected int) string {
+		rendered := make([]string, len(values))
+		for i, value := range values {
+			if i == selected {
+				rendered[i] = selectedStyle.Render(value)
+			} else {
+				rendered[i] = optionStyle.Render(value)
+			}
+		}
+		return strings.Join(rendered, " ")
+	}
+
+	lines := []string{
+		titleStyle.Render(o.title),
+		"",
+		label(0, "Result") + options(o.results, o.result),
+		label(1, "Effort") + options(o.efforts, o.effort),
+		label(2, "Notes") + o.notes.View(),
+		"",
+		mutedStyle.Render("↑/↓ field • ←/→ choose • enter save • esc skip"),
+	}
+	return style.Render(strings.Join(lines, "\n"))
+}
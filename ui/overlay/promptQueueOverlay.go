@@ -0,0 +1,149 @@
+package overlay
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// PromptQueue is a queue of prompts the overlay can view and edit.
+type PromptQueue interface {
+	QueuedPrompts() []string
+	EnqueuePrompt(prompt string)
+	RemoveQueuedPrompt(idx int)
+	MoveQueuedPrompt(idx, delta int) int
+}
+
+// PromptQueueOverlay lists an instance's pending prompts and lets the user add, reorder and
+// delete them.
+type PromptQueueOverlay struct {
+	title    string
+	queue    PromptQueue
+	selected int
+	// input is set while a new prompt is being typed
+	input  *TextInputOverlay
+	width  int
+	height int
+}
+
+// NewPromptQueueOverlay creates an overlay editing queue.
+func NewPromptQueueOverlay(title string, queue PromptQueue) *PromptQueueOverlay {
+	return &PromptQueueOverlay{
+		title:  title,
+		queue:  queue,
+		width:  70,
+		height: 20,
+	}
+}
+
+// HandleKeyPress processes a key press and returns true when the overlay should close.
+func (p *PromptQueueOverlay) HandleKeyPress(msg tea.KeyMsg) bool {
+	if p.input != nil {
+		p.input.HandleKeyPress(msg)
+		if p.input.IsSubmitted() {
+			if prompt := strings.TrimSpace(p.input.GetValue()); prompt != "" {
+				p.queue.EnqueuePrompt(prompt)
+				p.selected = len(p.queue.QueuedPrompts()) - 1
+			}
+			p.input = nil
+		} else if p.input.IsCanceled() {
+			p.input = nil
+		}
+		return false
+	}
+
+	prompts := p.queue.QueuedPrompts()
+	switch msg.String() {
+	case "esc", "q":
+		return true
+	case "up", "k":
+		if p.selected > 0 {
+			p.selected--
+		}
+	case "down", "j":
+		if p.selected < len(prompts)-1 {
+			p.selected++
+		}
+	case "shift+up", "K":
+		p.selected = p.queue.MoveQueuedPrompt(p.selected, -1)
+	case "shift+down", "J":
+		p.selected = p.queue.MoveQueuedPrompt(p.selected, 1)
+	case "d", "x", "delete":
+		p.queue.RemoveQueuedPrompt(p.selected)
+		if p.selected >= len(p.queue.QueuedPrompts()) && p.selected > 0 {
+			p.selected--
+		}
+	case "a", "n":
+		p.input = NewTextInputOverlay("Queue a prompt", "")
+		p.input.SetSize(p.width-10, 5)
+	}
+	return false
+}
+
+// SetSize sets the overlay's dimensions.
+func (p *PromptQueueOverlay) SetSize(width, height int) {
+	p.width = width
+	p.height = height
+	if p.input != nil {
+		p.input.SetSize(width-10, 5)
+	}
+}
+
+// Render renders the overlay.
+func (p *PromptQueueOverlay) Render() string {
+	if p.input != nil {
+		return p.input.Render()
+	}
+
+	style := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("#7D56F4")).
+		Padding(1, 2).
+		Width(p.width)
+
+	titleStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("#7D56F4"))
+
+	selectedStyle := lipgloss.NewStyle().
+		Background(lipgloss.Color("#7D56F4")).
+		Foreground(lipgloss.Color("#FAFAFA"))
+
+	mutedStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("#666666"))
+
+	lines := []string{titleStyle.Render(p.title), ""}
+	prompts := p.queue.QueuedPrompts()
+	if len(prompts) == 0 {
+		lines = append(lines, mutedStyle.Render("No queued prompts. Press a to add one."))
+	}
+
+	// Keep the selected prompt in view when the queue is taller than the overlay
+	visible := p.height - 8
+	if visible < 1 {
+		visible = 1
+	}
+	start := 0
+	if p.selected >= visible {
+		start = p.selected - visible + 1
+	}
+	textWidth := p.width - 12
+	for i := start; i < len(prompts) && i < start+visible; i++ {
+		text := strings.Join(strings.Fields(prompts[i]), " ")
+		if textWidth > 3 && len(text) > textWidth {
+			text = text[:textWidth-3] + "..."
+		}
+		line := fmt.Sprintf("%2d. %s", i+1, text)
+		if i == p.selected {
+			line = selectedStyle.Render(line)
+		}
+		lines = append(lines, line)
+	}
+
+	lines = append(lines, "",
+		mutedStyle.Render("Sent one at a time whenever the agent is ready."),
+		mutedStyle.Render("a add • d delete • K/J move up/down • esc close"))
+	return style.Render(strings.Join(lines, "\n"))
+}
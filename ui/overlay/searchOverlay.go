@@ -0,0 +1,163 @@
+package overlay
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// SearchOverlay lets the user type a query and pick one of the results it finds, which are
+// updated as the query changes.
+type SearchOverlay struct {
+	title    string
+	input    textinput.Model
+	search   func(query string) []ListItem
+	items    []ListItem
+	cursor   int
+	selected int
+	width    int
+	height   int
+}
+
+// NewSearchOverlay creates a search overlay that finds the results of a query with search.
+func NewSearchOverlay(title string, search func(query string) []ListItem) *SearchOverlay {
+	input := textinput.New()
+	input.Placeholder = "Search..."
+	input.Focus()
+	input.CharLimit = 200
+	input.Width = 50
+
+	return &SearchOverlay{
+		title:    title,
+		input:    input,
+		search:   search,
+		selected: -1,
+		width:    80,
+		height:   20,
+	}
+}
+
+// HandleKeyPress processes a key press and returns true when the overlay should close.
+func (s *SearchOverlay) HandleKeyPress(msg tea.KeyMsg) bool {
+	switch msg.String() {
+	case "esc", "ctrl+c":
+		s.selected = -1
+		return true
+	case "enter":
+		if len(s.items) == 0 {
+			return false
+		}
+		s.selected = s.cursor
+		return true
+	case "up", "ctrl+p":
+		if s.cursor > 0 {
+			s.cursor--
+		}
+	case "down", "ctrl+n":
+		if s.cursor < len(s.items)-1 {
+			s.cursor++
+		}
+	default:
+		query := s.input.Value()
+		s.input, _ = s.input.Update(msg)
+		if value := s.input.Value(); value != query {
+			s.items = nil
+			if strings.TrimSpace(value) != "" {
+				s.items = s.search(value)
+			}
+			s.cursor = 0
+		}
+	}
+	return false
+}
+
+// SelectedIndex returns the index of the chosen result, or -1 if the search was cancelled.
+func (s *SearchOverlay) SelectedIndex() int {
+	return s.selected
+}
+
+func (s *SearchOverlay) SetSize(width, height int) {
+	s.width = width
+	s.height = height
+	s.input.Width = max(10, width-12)
+}
+
+// Render renders the overlay.
+func (s *SearchOverlay) Render() string {
+	titleStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("#7D56F4")).
+		MarginBottom(1)
+
+	listStyle := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("#7D56F4")).
+		Padding(1, 2).
+		Width(s.width - 4)
+
+	selectedStyle := lipgloss.NewStyle().
+		Background(lipgloss.Color("#7D56F4")).
+		Foreground(lipgloss.Color("#FAFAFA"))
+
+	normalStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("#FAFAFA"))
+
+	mutedStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("#666666"))
+
+	var b strings.Builder
+	b.WriteString(titleStyle.Render(s.title))
+	b.WriteString("\n")
+
+	var list strings.Builder
+	list.WriteString(s.input.View())
+	list.WriteString("\n\n")
+	switch {
+	case strings.TrimSpace(s.input.Value()) == "":
+		list.WriteString(mutedStyle.Render("Type to search titles, branches, AI output and diffs"))
+	case len(s.items) == 0:
+		list.WriteString(mutedStyle.Render("No matches"))
+	default:
+		list.WriteString(mutedStyle.Render(fmt.Sprintf("%d result(s)", len(s.items))))
+		list.WriteString("\n")
+	}
+
+	// Each result takes two lines: the instance and where it matched
+	maxVisible := max(1, (s.height-14)/2)
+	startIdx := 0
+	if s.cursor >= maxVisible {
+		startIdx = s.cursor - maxVisible + 1
+	}
+	endIdx := min(startIdx+maxVisible, len(s.items))
+	lineWidth := max(20, s.width-12)
+
+	for i := startIdx; i < endIdx; i++ {
+		item := s.items[i]
+		title := ansi.Truncate(item.Title, lineWidth-2, "...")
+		description := ansi.Truncate(item.Description, lineWidth-4, "...")
+		if i == s.cursor {
+			list.WriteString(selectedStyle.Render("> " + title))
+		} else {
+			list.WriteString(normalStyle.Render("  " + title))
+		}
+		list.WriteString("\n" + mutedStyle.Render("    "+description))
+		if i < endIdx-1 {
+			list.WriteString("\n")
+		}
+	}
+	if startIdx > 0 {
+		list.WriteString("\n" + mutedStyle.Render("↑ more above"))
+	}
+	if endIdx < len(s.items) {
+		list.WriteString("\n" + mutedStyle.Render("↓ more below"))
+	}
+
+	b.WriteString(listStyle.Render(list.String()))
+	b.WriteString("\n")
+	b.WriteString(mutedStyle.Render("↑/↓ navigate • enter jump to session • esc cancel"))
+	return b.String()
+}
@@ -0,0 +1,275 @@
+package overlay
+
+import (
+	"claude-squad/session/git"
+	"claude-squad/ui/snapshot"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// confirmationWidth is the width the app gives confirmation dialogs.
+const confirmationWidth = 50
+
+func TestConfirmationOverlaySnapshots(t *testing.T) {
+	plain := NewConfirmationOverlay("[!] Kill session 'fix-login'?")
+
+	choices := NewConfirmationOverlayWithChoices("[!] Act on the 2 session(s) of group backend?", []ConfirmationChoice{
+		{Key: "c", Label: "pause all, committing their changes"},
+		{Key: "r", Label: "resume all"},
+		{Key: "u", Label: "ungroup, keeping the sessions"},
+	})
+
+	commands := NewConfirmationOverlay("[!] Push changes from session 'fix-login'?")
+	commands.LoadCommands = func() tea.Cmd { return nil }
+	commands.SetCommands([]string{
+		"git add -A",
+		"git commit -m 'update from fix-login on 2025-01-02 15:04:05 (paused)'",
+		"git push -u origin squad/fix-login",
+	})
+	commands.ToggleCommands()
+
+	typed := NewTypedConfirmationOverlay("[!] Reset session 'fix-login' to origin?", "fix-login")
+	typed.HandleKeyPress(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("fix-lo")})
+
+	for name, c := range map[string]*ConfirmationOverlay{
+		"confirmation":          plain,
+		"confirmation_choices":  choices,
+		"confirmation_commands": commands,
+		"confirmation_typed":    typed,
+	} {
+		t.Run(name, func(t *testing.T) {
+			c.SetWidth(confirmationWidth)
+			view := c.Render()
+			snapshot.Assert(t, name, view)
+			// The width is the content's, the border is drawn around it
+			snapshot.AssertFits(t, view, confirmationWidth+2, 0)
+		})
+	}
+}
+
+func TestTypedConfirmationOverlay(t *testing.T) {
+	confirmed := false
+	c := NewTypedConfirmationOverlay("[!] Reset session 'fix-login' to origin?", "fix-login")
+	c.OnConfirm = func() { confirmed = true }
+	typeText := func(text string) {
+		for _, r := range text {
+			c.HandleKeyPress(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+		}
+	}
+	enter := tea.KeyMsg{Type: tea.KeyEnter}
+
+	// The confirm key is just typed
+	typeText("yfix-log")
+	if c.HandleKeyPress(enter) || confirmed {
+		t.Fatal("enter confirmed before the name was typed")
+	}
+	c.HandleKeyPress(tea.KeyMsg{Type: tea.KeyCtrlU})
+	typeText("fix-logxx")
+	c.HandleKeyPress(tea.KeyMsg{Type: tea.KeyBackspace})
+	c.HandleKeyPress(tea.KeyMsg{Type: tea.KeyBackspace})
+	typeText("in")
+	if !c.HandleKeyPress(enter) || !confirmed {
+		t.Fatal("enter didn't confirm once the name was typed")
+	}
+
+	cancelled := NewTypedConfirmationOverlay("[!] Reset session 'fix-login' to origin?", "fix-login")
+	if !cancelled.HandleKeyPress(tea.KeyMsg{Type: tea.KeyEsc}) || !cancelled.Dismissed {
+		t.Fatal("esc didn't cancel")
+	}
+}
+
+func TestTextInputOverlaySnapshots(t *testing.T) {
+	for _, size := range snapshot.Sizes {
+		t.Run(size.String(), func(t *testing.T) {
+			o := NewTextInputOverlay("Name of the new group", "backend")
+			width, height := size.Scale(0.6, 0.4)
+			o.SetSize(width, height)
+			view := o.Render()
+			// The overlay grows to fit its hint rather than wrapping it, so it isn't checked to fit
+			snapshot.Assert(t, "text_input_"+size.String(), view)
+		})
+	}
+}
+
+func TestListSelectorOverlaySnapshots(t *testing.T) {
+	items := []ListItem{
+		{Title: "backend", Description: "2 session(s)"},
+		{Title: "frontend", Description: "1 session(s)"},
+		{Title: "New group...", Description: "name a new group to move it to"},
+	}
+	for _, size := range snapshot.Sizes {
+		t.Run(size.String(), func(t *testing.T) {
+			o := NewListSelectorOverlay("Move 'fix-login' to group", items)
+			width, height := size.Scale(0.6, 0.6)
+			o.SetSize(width, height)
+			view := o.Render()
+			snapshot.Assert(t, "list_selector_"+size.String(), view)
+			snapshot.AssertFits(t, view, width, height)
+		})
+	}
+}
+
+func TestSearchOverlaySnapshots(t *testing.T) {
+	search := func(query string) []ListItem {
+		return []ListItem{
+			{Title: "fix-login", Description: "branch squad/fix-login matches " + query},
+			{Title: "add-search", Description: "diff: + if err := " + query + "(); err != nil {"},
+		}
+	}
+	for _, size := range snapshot.Sizes {
+		t.Run(size.String(), func(t *testing.T) {
+			o := NewSearchOverlay("Search sessions", search)
+			width, height := size.Scale(0.7, 0.7)
+			o.SetSize(width, height)
+			o.HandleKeyPress(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("login")})
+			view := o.Render()
+			snapshot.Assert(t, "search_"+size.String(), view)
+			snapshot.AssertFits(t, view, width, height)
+		})
+	}
+}
+
+func TestHistoryOverlaySnapshots(t *testing.T) {
+	var content strings.Builder
+	for _, line := range []string{
+		"> Fix the login form so errors are shown",
+		"I'll look at the login handler first.",
+		"The error returned by Validate was dropped; it's now shown under the form.",
+	} {
+		content.WriteString(line + "\n\n")
+	}
+	for _, size := range snapshot.Sizes {
+		t.Run(size.String(), func(t *testing.T) {
+			o := NewHistoryOverlay("AI history: fix-login", content.String())
+			width, height := size.Scale(0.9, 0.9)
+			o.SetSize(width, height)
+			view := o.Render()
+			snapshot.Assert(t, "history_"+size.String(), view)
+			snapshot.AssertFits(t, view, width, height)
+		})
+	}
+}
+
+func TestStepLogOverlaySnapshots(t *testing.T) {
+	for _, size := range snapshot.Sizes {
+		t.Run(size.String(), func(t *testing.T) {
+			o := NewStepLogOverlay("Rebase fix-login onto main")
+			now := o.start
+			o.Now = func() time.Time { return now }
+			o.OnAbort = func() {}
+			width, height := size.Scale(0.7, 0.7)
+			o.SetSize(width, height)
+			for _, step := range []string{
+				"Backing up the branch",
+				"Created and pushed backup branch squad/fix-login-backup-1",
+				"Fetching origin",
+				"Rebasing onto origin/main",
+				"Rebase failed, aborting it",
+				"Retrying the rebase in a fresh clone",
+				"Cloning origin into /tmp/claude-squad-rebase-fix-login-1",
+			} {
+				now = now.Add(2 * time.Second)
+				o.AddStep(step)
+			}
+			o.SetProgress("clone: Receiving objects 45%")
+			view := o.Render()
+			snapshot.Assert(t, "step_log_"+size.String(), view)
+			snapshot.AssertFits(t, view, width, height)
+		})
+	}
+}
+
+func TestStepLogOverlayAbort(t *testing.T) {
+	aborted := 0
+	o := NewStepLogOverlay("Rebase fix-login onto main")
+	o.OnAbort = func() { aborted++ }
+	o.AddStep("Fetching origin")
+	abort := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("a")}
+	if o.HandleKeyPress(abort) || o.HandleKeyPress(abort) || aborted != 1 {
+		t.Fatalf("aborting called OnAbort %d times, want once", aborted)
+	}
+	o.Finish(errors.New("aborted"))
+	if o.Running() || o.HandleKeyPress(abort) || aborted != 1 {
+		t.Fatal("a finished operation was aborted")
+	}
+	if !o.HandleKeyPress(tea.KeyMsg{Type: tea.KeyEsc}) {
+		t.Fatal("esc didn't close the log")
+	}
+}
+
+// rebaseSteps returns the commits of a branch to rewrite, all picked.
+func rebaseSteps() []git.RebaseStep {
+	var steps []git.RebaseStep
+	for i, subject := range []string{"Add the login form", "Fix typo", "Validate the password", "WIP"} {
+		hash := strings.Repeat(string(rune('a'+i)), 40)
+		steps = append(steps, git.RebaseStep{
+			Commit:  git.CommitInfo{Hash: hash, ShortHash: hash[:7], Subject: subject},
+			Action:  git.RebasePick,
+			Message: subject,
+		})
+	}
+	return steps
+}
+
+func TestInteractiveRebaseOverlaySnapshots(t *testing.T) {
+	for _, size := range snapshot.Sizes {
+		t.Run(size.String(), func(t *testing.T) {
+			o := NewInteractiveRebaseOverlay("Rewrite the commits of 'fix-login'", rebaseSteps())
+			width, height := size.Scale(0.7, 0.7)
+			o.SetSize(width, height)
+			for _, key := range []string{"j", "s", "j", "J", "d", "k", "r"} {
+				o.HandleKeyPress(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(key)})
+			}
+			o.HandleKeyPress(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(" on submit")})
+			o.HandleKeyPress(tea.KeyMsg{Type: tea.KeyEnter})
+			view := o.Render()
+			snapshot.Assert(t, "interactive_rebase_"+size.String(), view)
+			snapshot.AssertFits(t, view, width, height)
+		})
+	}
+}
+
+func TestInteractiveRebaseOverlay(t *testing.T) {
+	press := func(o *InteractiveRebaseOverlay, key string) bool {
+		return o.HandleKeyPress(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(key)})
+	}
+	o := NewInteractiveRebaseOverlay("Rewrite", rebaseSteps())
+	if o.Changed() {
+		t.Fatal("Changed() before any edit")
+	}
+
+	// Moving the last commit up and back leaves the branch as it was
+	for _, key := range []string{"j", "j", "j", "K", "J"} {
+		press(o, key)
+	}
+	if o.Changed() {
+		t.Fatal("Changed() after moving a commit back where it was")
+	}
+
+	press(o, "K")
+	press(o, "s")
+	steps := o.Steps()
+	if steps[2].Commit.Subject != "WIP" || steps[2].Action != git.RebaseSquash || steps[3].Commit.Subject != "Validate the password" {
+		t.Fatalf("steps after moving WIP up and squashing it = %+v", steps)
+	}
+
+	// Rewording keeps the new message, and picking again restores the original
+	press(o, "r")
+	o.HandleKeyPress(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("!")})
+	o.HandleKeyPress(tea.KeyMsg{Type: tea.KeyEnter})
+	if step := o.Steps()[2]; step.Action != git.RebaseReword || step.Message != "WIP!" {
+		t.Fatalf("step after rewording = %+v", step)
+	}
+	press(o, "p")
+	if step := o.Steps()[2]; step.Action != git.RebasePick || step.Message != "WIP" {
+		t.Fatalf("step after picking again = %+v", step)
+	}
+
+	if !o.HandleKeyPress(tea.KeyMsg{Type: tea.KeyEnter}) || !o.Submitted() || !o.Changed() {
+		t.Fatal("enter didn't submit the reordered commits")
+	}
+}
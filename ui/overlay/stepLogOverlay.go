@@ -0,0 +1,202 @@
+package overlay
+
+import (
+	"claude-squad/ui"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// StepLogOverlay shows the steps of a running multi-step operation, such as updating a branch
+// with main, as they start, along with the progress of the current one. The log is kept once the
+// operation finishes so it can be looked at later.
+type StepLogOverlay struct {
+	// OnAbort, if set, is called when a is pressed while the operation runs
+	OnAbort func()
+	// Now returns the current time; steps are timed from the overlay's creation
+	Now func() time.Time
+
+	title    string
+	start    time.Time
+	steps    []logStep
+	progress string
+	aborting bool
+	finished bool
+	// result is the outcome shown once finished
+	result string
+	failed bool
+
+	viewport    viewport.Model
+	scrollAccel ui.ScrollAccelerator
+	width       int
+	height      int
+	// maxLines is the most lines of the log shown at once
+	maxLines int
+}
+
+// stepIndent is the width of a step's time and marker.
+const stepIndent = 8
+
+// logStep is a step of the operation and when it started.
+type logStep struct {
+	at   time.Duration
+	text string
+}
+
+// NewStepLogOverlay creates the log of the operation with the given title.
+func NewStepLogOverlay(title string) *StepLogOverlay {
+	return &StepLogOverlay{
+		Now:      time.Now,
+		title:    title,
+		start:    time.Now(),
+		viewport: viewport.New(0, 0),
+	}
+}
+
+// AddStep adds a step that just started, clearing the progress of the previous one.
+func (s *StepLogOverlay) AddStep(step string) {
+	s.steps = append(s.steps, logStep{at: s.Now().Sub(s.start), text: step})
+	s.progress = ""
+	s.refresh()
+}
+
+// SetProgress sets the progress of the current step, e.g. "fetch: Receiving objects 45%".
+func (s *StepLogOverlay) SetProgress(progress string) {
+	s.progress = progress
+	s.refresh()
+}
+
+// Finish marks the operation finished, failed if err isn't nil.
+func (s *StepLogOverlay) Finish(err error) {
+	s.finished = true
+	s.aborting = false
+	s.progress = ""
+	s.failed = err != nil
+	elapsed := s.Now().Sub(s.start).Round(time.Second)
+	if err != nil {
+		s.result = fmt.Sprintf("✗ Failed after %s: %v", elapsed, err)
+	} else {
+		s.result = fmt.Sprintf("✓ Finished in %s", elapsed)
+	}
+	s.refresh()
+}
+
+// Running returns true until the operation finished.
+func (s *StepLogOverlay) Running() bool {
+	return !s.finished
+}
+
+// Title returns the title of the operation.
+func (s *StepLogOverlay) Title() string {
+	return s.title
+}
+
+// SetSize updates the dimensions of the overlay.
+func (s *StepLogOverlay) SetSize(width, height int) {
+	s.width = width
+	s.height = height
+	s.viewport.Width = max(1, width-4)
+	// Border (2), padding (2), title (2) and help (2)
+	s.maxLines = max(1, height-8)
+	s.refresh()
+}
+
+// refresh lays the log out in the viewport, following its end unless scrolled up.
+func (s *StepLogOverlay) refresh() {
+	follow := s.viewport.AtBottom() || s.viewport.Height == 0
+	stepStyle := lipgloss.NewStyle().Width(s.viewport.Width)
+	// Steps wrap under their text, past the time and marker
+	textStyle := lipgloss.NewStyle().Width(max(1, s.viewport.Width-stepIndent))
+	timeStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("240"))
+	progressStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("245")).Italic(true)
+	resultStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#51bd73"))
+	if s.failed {
+		resultStyle = resultStyle.Foreground(lipgloss.Color("#de613e"))
+	}
+
+	lines := make([]string, 0, len(s.steps)+2)
+	for i, step := range s.steps {
+		marker := "✓"
+		if i == len(s.steps)-1 && !s.finished {
+			marker = "…"
+		}
+		prefix := fmt.Sprintf("%s %s ", timeStyle.Render(fmt.Sprintf("%5s", step.at.Round(time.Second))), marker)
+		text := strings.ReplaceAll(textStyle.Render(step.text), "\n", "\n"+strings.Repeat(" ", stepIndent))
+		lines = append(lines, prefix+text)
+	}
+	if s.progress != "" {
+		lines = append(lines, strings.Repeat(" ", stepIndent)+progressStyle.Render(s.progress))
+	}
+	if s.result != "" {
+		lines = append(lines, "", stepStyle.Render(resultStyle.Render(s.result)))
+	}
+	content := strings.Join(lines, "\n")
+	// The overlay grows with the log up to its height
+	s.viewport.Height = max(1, min(lipgloss.Height(content), s.maxLines))
+	s.viewport.SetContent(content)
+	if follow {
+		s.viewport.GotoBottom()
+	}
+}
+
+// HandleKeyPress processes a key press and returns true when the overlay should be hidden. Hiding
+// it while the operation runs doesn't stop it.
+func (s *StepLogOverlay) HandleKeyPress(msg tea.KeyMsg) bool {
+	switch msg.String() {
+	case "esc", "q", "enter", "ctrl+c":
+		return true
+	case "a":
+		if !s.finished && !s.aborting && s.OnAbort != nil {
+			s.aborting = true
+			s.OnAbort()
+		}
+	default:
+		ui.HandleViewportScrollKey(&s.viewport, &s.scrollAccel, msg.String())
+	}
+	return false
+}
+
+// help describes the keys available in the operation's current state.
+func (s *StepLogOverlay) help() string {
+	switch {
+	case s.finished:
+		return "↑/↓ scroll • ESC close"
+	case s.aborting:
+		return "aborting before the next step… • ESC hide"
+	case s.OnAbort != nil:
+		return "↑/↓ scroll • a abort • ESC hide (keeps running)"
+	default:
+		return "↑/↓ scroll • ESC hide (keeps running)"
+	}
+}
+
+// Render renders the overlay.
+func (s *StepLogOverlay) Render() string {
+	titleStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("62")).
+		MarginBottom(1)
+
+	containerStyle := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("62")).
+		Padding(1).
+		Width(s.width - 2)
+
+	helpStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("240")).
+		MarginTop(1).
+		Width(s.viewport.Width).
+		Align(lipgloss.Center)
+
+	content := lipgloss.JoinVertical(lipgloss.Left,
+		titleStyle.Render(s.title),
+		s.viewport.View(),
+		helpStyle.Render(s.help()),
+	)
+	return containerStyle.Render(content)
+}
@@ -1,6 +1,8 @@
 package overlay
 
 import (
+	"claude-squad/ui"
+
 	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
@@ -21,6 +23,10 @@ type TextOverlay struct {
 	height int
 	// Whether scrolling is needed
 	needsScrolling bool
+	// scrollAccel speeds up line scrolling while a key is held
+	scrollAccel ui.ScrollAccelerator
+	// fileHeaders are the content lines that start a new file
+	fileHeaders []int
 }
 
 // NewTextOverlay creates a new text screen overlay with the given title and content
@@ -31,6 +37,7 @@ func NewTextOverlay(content string) *TextOverlay {
 		viewport:  viewport.New(0, 0),
 	}
 	t.viewport.SetContent(content)
+	t.fileHeaders = ui.FileHeaderLines(content)
 	return t
 }
 
@@ -39,23 +46,20 @@ func NewTextOverlay(content string) *TextOverlay {
 func (t *TextOverlay) HandleKeyPress(msg tea.KeyMsg) bool {
 	// If scrolling is needed, handle navigation keys
 	if t.needsScrolling {
-		switch msg.String() {
-		case "up", "k":
-			t.viewport.LineUp(1)
-			return false
-		case "down", "j":
-			t.viewport.LineDown(1)
+		if ui.HandleViewportScrollKey(&t.viewport, &t.scrollAccel, msg.String()) {
 			return false
-		case "pgup":
-			t.viewport.HalfViewUp()
+		}
+		switch msg.String() {
+		case "alt+up":
+			ui.JumpToFileHeader(&t.viewport, t.fileHeaders, -1)
 			return false
-		case "pgdown":
-			t.viewport.HalfViewDown()
+		case "alt+down":
+			ui.JumpToFileHeader(&t.viewport, t.fileHeaders, 1)
 			return false
-		case "home", "ctrl+home", "g":
+		case "g":
 			t.viewport.GotoTop()
 			return false
-		case "end", "ctrl+end", "G":
+		case "G":
 			t.viewport.GotoBottom()
 			return false
 		}
@@ -87,7 +91,7 @@ func (t *TextOverlay) Render(opts ...WhitespaceOption) string {
 		if t.viewport.TotalLineCount() > t.viewport.Height {
 			scrollInfo := lipgloss.NewStyle().
 				Foreground(lipgloss.Color("241")).
-				Render("↑/↓ to scroll • Press any other key to close")
+				Render("↑/↓ scroll • ctrl+u/d half page • pgup/pgdn page • Press any other key to close")
 			content = lipgloss.JoinVertical(lipgloss.Left, content, "", scrollInfo)
 		}
 	} else {
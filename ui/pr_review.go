@@ -5,6 +5,7 @@ import (
 	"strings"
 
 	"claude-squad/session/git"
+	"github.com/charmbracelet/bubbles/textarea"
 	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
@@ -26,10 +27,22 @@ type PRReviewModel struct {
 	ready                bool
 	splitMode            bool
 	splitModel           *CommentSplitModel
+	// expanded holds the comments shown in full while unselected
+	expanded map[*git.PRComment]bool
+	// commentStarts and commentEnds are the viewport lines each active comment spans
+	commentStarts []int
+	commentEnds   []int
+	// replyTo is the comment being replied to while the reply input is open
+	replyTo    *git.PRComment
+	replyInput textarea.Model
+	// notice reports the outcome of the last reply
+	notice string
 }
 
 type PRReviewCompleteMsg struct {
 	AcceptedComments []*git.PRComment
+	// PR is the pull request the comments belong to
+	PR *git.PullRequest
 }
 
 type PRReviewCancelMsg struct{}
@@ -38,6 +51,25 @@ type PRReviewShowCommentMsg struct {
 	Comment *git.PRComment
 }
 
+// PRReviewPreviewPromptMsg asks to show the prompt a comment would be sent as.
+type PRReviewPreviewPromptMsg struct {
+	Comment *git.PRComment
+}
+
+// PRReviewReplyMsg asks to post a reply to a comment.
+type PRReviewReplyMsg struct {
+	PR      *git.PullRequest
+	Comment *git.PRComment
+	Body    string
+}
+
+// PRReviewReplyPostedMsg reports the outcome of posting a reply to Parent.
+type PRReviewReplyPostedMsg struct {
+	Parent *git.PRComment
+	Reply  *git.PRComment
+	Err    error
+}
+
 type PRResolveAllConversationsMsg struct{}
 
 type PRRequestResolveConfirmationMsg struct{}
@@ -55,6 +87,7 @@ func NewPRReviewModel(pr *git.PullRequest) PRReviewModel {
 		ready:                false,
 		width:                80, // Default width
 		height:               24, // Default height
+		expanded:             make(map[*git.PRComment]bool),
 	}
 }
 
@@ -69,6 +102,28 @@ func (m PRReviewModel) Update(msg tea.Msg) (PRReviewModel, tea.Cmd) {
 		cmds []tea.Cmd
 	)
 
+	if posted, ok := msg.(PRReviewReplyPostedMsg); ok {
+		if posted.Err != nil {
+			m.notice = fmt.Sprintf("Reply failed: %v", posted.Err)
+		} else {
+			m.pr.AddReply(posted.Parent, posted.Reply)
+			m.notice = fmt.Sprintf("Replied to @%s", posted.Parent.Author)
+		}
+		if m.ready {
+			m.updateViewportContent()
+		}
+		return m, nil
+	}
+
+	if m.replyTo != nil {
+		// Window sizes still reach the list below so it is laid out right once the reply is done
+		size, ok := msg.(tea.WindowSizeMsg)
+		if !ok {
+			return m.updateReply(msg)
+		}
+		m.replyInput.SetWidth(max(size.Width-4, 20))
+	}
+
 	// Handle split mode updates
 	if m.splitMode && m.splitModel != nil {
 		switch msg := msg.(type) {
@@ -251,6 +306,59 @@ func (m PRReviewModel) Update(msg tea.Msg) (PRReviewModel, tea.Cmd) {
 			}
 			return m, nil
 
+		case "p":
+			comments := m.getActiveComments()
+			if len(comments) > 0 && m.currentIndex < len(comments) {
+				return m, func() tea.Msg {
+					return PRReviewPreviewPromptMsg{Comment: comments[m.currentIndex]}
+				}
+			}
+			return m, nil
+
+		case "y":
+			// Open the reply input for the current comment
+			comments := m.getActiveComments()
+			if len(comments) > 0 && m.currentIndex < len(comments) {
+				ta := textarea.New()
+				ta.Placeholder = "Write a reply..."
+				ta.SetWidth(max(m.width-4, 20))
+				ta.SetHeight(8)
+				ta.Focus()
+				m.replyTo = comments[m.currentIndex]
+				m.replyInput = ta
+				return m, textarea.Blink
+			}
+			return m, nil
+
+		case " ":
+			// Toggle inline expansion of the current comment
+			comments := m.getActiveComments()
+			if len(comments) > 0 && m.currentIndex < len(comments) {
+				comment := comments[m.currentIndex]
+				m.expanded[comment] = !m.expanded[comment]
+				if m.ready {
+					m.updateViewportContent()
+					m.ensureCurrentCommentVisible()
+				}
+			}
+			return m, nil
+
+		case "E":
+			// Expand all comments inline, or collapse them if all are already expanded
+			comments := m.getActiveComments()
+			allExpanded := true
+			for _, comment := range comments {
+				allExpanded = allExpanded && m.expanded[comment]
+			}
+			for _, comment := range comments {
+				m.expanded[comment] = !allExpanded
+			}
+			if m.ready {
+				m.updateViewportContent()
+				m.ensureCurrentCommentVisible()
+			}
+			return m, nil
+
 		case "s":
 			// Enter split mode for current comment
 			comments := m.getActiveComments()
@@ -264,7 +372,7 @@ func (m PRReviewModel) Update(msg tea.Msg) (PRReviewModel, tea.Cmd) {
 
 		case "enter":
 			acceptedComments := m.pr.GetAcceptedComments()
-			return m, func() tea.Msg { return PRReviewCompleteMsg{AcceptedComments: acceptedComments} }
+			return m, func() tea.Msg { return PRReviewCompleteMsg{AcceptedComments: acceptedComments, PR: m.pr} }
 
 		// Additional viewport controls (only when ready)
 		case "pgup", "shift+up":
@@ -358,6 +466,62 @@ func (m PRReviewModel) buildFilterStatus() string {
 	return filterStatus
 }
 
+// updateReply handles input while a reply is being written. Ctrl+s posts it and esc discards it.
+func (m PRReviewModel) updateReply(msg tea.Msg) (PRReviewModel, tea.Cmd) {
+	if keyMsg, ok := msg.(tea.KeyMsg); ok {
+		switch keyMsg.String() {
+		case "esc":
+			m.replyTo = nil
+			return m, nil
+		case "ctrl+s":
+			body := strings.TrimSpace(m.replyInput.Value())
+			if body == "" {
+				return m, nil
+			}
+			comment := m.replyTo
+			m.replyTo = nil
+			m.notice = fmt.Sprintf("Posting reply to @%s...", comment.Author)
+			return m, func() tea.Msg { return PRReviewReplyMsg{PR: m.pr, Comment: comment, Body: body} }
+		}
+	}
+	var cmd tea.Cmd
+	m.replyInput, cmd = m.replyInput.Update(msg)
+	return m, cmd
+}
+
+// replyView shows the comment being replied to above the reply input.
+func (m PRReviewModel) replyView() string {
+	var b strings.Builder
+	titleStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("86"))
+	quoteStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("241"))
+
+	title := fmt.Sprintf("Reply to @%s", m.replyTo.Author)
+	if m.replyTo.Path != "" {
+		title += fmt.Sprintf(" on %s", m.replyTo.Path)
+		if m.replyTo.Line > 0 {
+			title += fmt.Sprintf(":%d", m.replyTo.Line)
+		}
+	}
+	b.WriteString(titleStyle.Render(title))
+	b.WriteString("\n\n")
+
+	quoted := m.wrapText(StripMarkdown(m.replyTo.Body), max(m.width-6, 20))
+	if len(quoted) > 8 {
+		quoted = append(quoted[:8], "...")
+	}
+	for _, line := range quoted {
+		b.WriteString(quoteStyle.Render("> "+line) + "\n")
+	}
+	b.WriteString("\n")
+	if m.replyTo.Type != "review_comment" {
+		b.WriteString(quoteStyle.Render("Posted as a general PR comment quoting the original.") + "\n\n")
+	}
+	b.WriteString(m.replyInput.View())
+	b.WriteString("\n\n")
+	b.WriteString(quoteStyle.Render("Ctrl+s: post reply • Esc: cancel"))
+	return b.String()
+}
+
 // getActiveComments returns the comments based on filter state
 func (m PRReviewModel) getActiveComments() []*git.PRComment {
 	var comments []*git.PRComment
@@ -404,6 +568,10 @@ func (m PRReviewModel) View() string {
 		return fmt.Sprintf("Error: %v\n\nPress 'q' to go back", m.err)
 	}
 
+	if m.replyTo != nil {
+		return m.replyView()
+	}
+
 	// Show split mode if active
 	if m.splitMode && m.splitModel != nil {
 		return m.splitModel.View()
@@ -483,6 +651,9 @@ func (m PRReviewModel) View() string {
 		header.WriteString(statusStyle.Render(fmt.Sprintf("All Comments: %d (%dR %dRC %dG, %s), %d accepted | %d/%d%s",
 			total, reviews, reviewComments, issueComments, filterInfo, acceptedCount, m.currentIndex+1, len(activeComments), scrollInfo)))
 	}
+	if m.notice != "" {
+		header.WriteString(statusStyle.Render(" | " + m.notice))
+	}
 	header.WriteString("\n") // Single newline after status
 
 	// Build the footer (help text)
@@ -495,7 +666,10 @@ func (m PRReviewModel) View() string {
 			"j/k:nav",
 			"a/d:accept/deny",
 			"A/D:all",
-			"e:expand",
+			"space/E:expand inline/all",
+			"e:detail",
+			"p:preview prompt",
+			"y:reply",
 			"s:split",
 			"f:toggle filter",
 			"c/C:toggle/only comments",
@@ -519,9 +693,13 @@ func (m *PRReviewModel) updateViewportContent() {
 	var content strings.Builder
 
 	comments := m.getActiveComments()
+	m.commentStarts = make([]int, len(comments))
+	m.commentEnds = make([]int, len(comments))
+	line := 0
 	for i, comment := range comments {
 		if i > 0 {
 			content.WriteString("\n\n") // Add consistent spacing between comments
+			line += 2
 		}
 
 		// Comment box styling
@@ -563,12 +741,20 @@ func (m *PRReviewModel) updateViewportContent() {
 		}
 
 		// Add visual indicators for filtered comment types
+		if comment.InReplyToID != 0 {
+			status += " (reply)"
+		}
 		if comment.IsResolved {
 			status += " (resolved)"
 		}
 		if comment.IsGeminiReview {
 			status += " (gemini)"
 		}
+		if comment.LikelyAddressed {
+			status += " (likely addressed)"
+		} else if comment.Sent {
+			status += " (sent)"
+		}
 
 		// Build header with better type display
 		typeDisplay := comment.Type
@@ -605,9 +791,9 @@ func (m *PRReviewModel) updateViewportContent() {
 			} else {
 				body = StripMarkdown(comment.Body)
 			}
-			// Truncate for preview
-			if len(body) > 150 {
-				body = body[:147] + "..."
+			// Truncate for preview unless expanded inline
+			if len(body) > 150 && !m.expanded[comment] {
+				body = body[:147] + "... [space to expand]"
 			}
 		}
 
@@ -626,7 +812,13 @@ func (m *PRReviewModel) updateViewportContent() {
 			prefix = "> "
 		}
 
-		content.WriteString(prefix + boxStyle.Render(commentContent))
+		box := prefix + boxStyle.Render(commentContent)
+		content.WriteString(box)
+
+		// Track where each comment lands so scrolling follows expanded comments exactly
+		m.commentStarts[i] = line
+		line += lipgloss.Height(box) - 1
+		m.commentEnds[i] = line
 	}
 
 	// Add substantial padding after the last comment to ensure it's fully visible when scrolled to bottom
@@ -679,29 +871,19 @@ func (m *PRReviewModel) wrapText(text string, width int) []string {
 	return result
 }
 
+// ensureCurrentCommentVisible scrolls the viewport so the selected comment is visible, showing
+// as much of it as fits when it is taller than the viewport.
 func (m *PRReviewModel) ensureCurrentCommentVisible() {
-	// This is a simple implementation - could be improved to calculate
-	// exact position of current comment
-	lines := strings.Split(m.viewport.View(), "\n")
-	totalLines := len(lines)
-
-	if totalLines == 0 {
-		return
-	}
-
-	// Estimate position based on comment index
-	comments := m.getActiveComments()
-	if len(comments) == 0 {
+	if m.currentIndex >= len(m.commentStarts) {
 		return
 	}
-	estimatedPosition := float64(m.currentIndex) / float64(len(comments))
-	targetLine := int(estimatedPosition * float64(m.viewport.TotalLineCount()))
+	start := m.commentStarts[m.currentIndex]
+	end := m.commentEnds[m.currentIndex]
 
-	// Scroll to make the comment visible
-	if targetLine < m.viewport.YOffset {
-		m.viewport.SetYOffset(targetLine)
-	} else if targetLine > m.viewport.YOffset+m.viewport.Height-5 {
-		m.viewport.SetYOffset(targetLine - m.viewport.Height + 5)
+	if start < m.viewport.YOffset || end-start >= m.viewport.Height {
+		m.viewport.SetYOffset(start)
+	} else if end >= m.viewport.YOffset+m.viewport.Height {
+		m.viewport.SetYOffset(end - m.viewport.Height + 1)
 	}
 }
 
@@ -787,6 +969,9 @@ func (m PRReviewModel) simpleView() string {
 		}
 
 		// Add visual indicators for filtered comment types
+		if comment.InReplyToID != 0 {
+			status += " (reply)"
+		}
 		if comment.IsResolved {
 			status += " (resolved)"
 		}
@@ -831,7 +1016,7 @@ func (m PRReviewModel) simpleView() string {
 		Foreground(lipgloss.Color("241"))
 
 	b.WriteString("\n")
-	b.WriteString(helpStyle.Render("Keys: j/k:nav • a/d:accept/deny • e:expand • s:split • f:toggle filter • c/C:toggle/only comments • r/R:toggle/only reviews • l/L:toggle/only line comments • Ctrl+r:resolve all • Enter:process • q:cancel"))
+	b.WriteString(helpStyle.Render("Keys: j/k:nav • a/d:accept/deny • e:expand • y:reply • s:split • f:toggle filter • c/C:toggle/only comments • r/R:toggle/only reviews • l/L:toggle/only line comments • Ctrl+r:resolve all • Enter:process • q:cancel"))
 
 	return b.String()
 }
@@ -0,0 +1,63 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+
+	"claude-squad/session/git"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPRReviewInlineExpansion(t *testing.T) {
+	long := strings.Repeat("word ", 100)
+	pr := &git.PullRequest{Number: 1, Title: "test"}
+	for _, author := range []string{"alice", "bob", "carol"} {
+		pr.Comments = append(pr.Comments, &git.PRComment{Type: "issue_comment", Author: author, Body: long})
+	}
+
+	m := NewPRReviewModel(pr)
+	m, _ = m.Update(tea.WindowSizeMsg{Width: 80, Height: 20})
+	require.True(t, m.ready)
+
+	// Unselected comments are truncated
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("j")})
+	collapsedHeight := m.commentEnds[0] - m.commentStarts[0]
+
+	// Expanding the first comment keeps it in full when unselected and shifts the others down
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("k")})
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeySpace, Runes: []rune(" ")})
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("j")})
+	assert.Greater(t, m.commentEnds[0]-m.commentStarts[0], collapsedHeight)
+	assert.Equal(t, m.commentEnds[0]+2, m.commentStarts[1])
+	assert.Equal(t, collapsedHeight, m.commentEnds[2]-m.commentStarts[2])
+
+	// The selected comment is scrolled into view
+	assert.LessOrEqual(t, m.viewport.YOffset, m.commentStarts[1])
+	assert.Greater(t, m.viewport.YOffset+m.viewport.Height, m.commentStarts[1])
+}
+
+func TestPRReviewReply(t *testing.T) {
+	parent := &git.PRComment{ID: 1, Type: "review_comment", Author: "alice", Path: "main.go", Line: 3, Body: "Why?"}
+	pr := &git.PullRequest{Number: 1, Title: "test", Comments: []*git.PRComment{parent}, AllComments: []*git.PRComment{parent}}
+
+	m := NewPRReviewModel(pr)
+	m, _ = m.Update(tea.WindowSizeMsg{Width: 80, Height: 20})
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("y")})
+	require.Equal(t, parent, m.replyTo)
+	assert.Contains(t, m.View(), "Reply to @alice on main.go:3")
+
+	// Keys go to the reply input, not the review shortcuts
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("q")})
+	m, cmd := m.Update(tea.KeyMsg{Type: tea.KeyCtrlS})
+	require.NotNil(t, cmd)
+	assert.Equal(t, PRReviewReplyMsg{PR: pr, Comment: parent, Body: "q"}, cmd())
+	assert.Nil(t, m.replyTo)
+
+	reply := &git.PRComment{ID: 2, Type: "review_comment", Author: "bob", Body: "q", InReplyToID: 1}
+	m, _ = m.Update(PRReviewReplyPostedMsg{Parent: parent, Reply: reply})
+	assert.Equal(t, []*git.PRComment{parent, reply}, pr.Comments)
+	assert.Contains(t, m.View(), "Replied to @alice")
+}
@@ -19,6 +19,12 @@ type PreviewPane struct {
 	previewState previewState
 	isScrolling  bool
 	viewport     viewport.Model
+	// fileHeaders are the scrollback lines that start a new file, for jumping between files
+	fileHeaders []int
+	// scrollContent is the scrollback shown in scroll mode, before layout
+	scrollContent string
+	// layout wraps or pans lines wider than the pane
+	layout LineLayout
 }
 
 type previewState struct {
@@ -70,6 +76,13 @@ func (p *PreviewPane) UpdateContent(instance *session.Instance) error {
 				)),
 		))
 		return nil
+	case instance.Broken():
+		p.setFallbackState(lipgloss.JoinVertical(lipgloss.Center,
+			"The worktree of this session was deleted.",
+			"",
+			"Press enter to recreate it from the branch or to pause the session.",
+		))
+		return nil
 	}
 
 	// If in scroll mode, don't update content (to preserve scroll position)
@@ -146,7 +159,8 @@ func (p *PreviewPane) String() string {
 	// Calculate available height accounting for border and margin
 	availableHeight := p.height - 1 //  1 for ellipsis
 
-	lines := strings.Split(p.previewState.text, "\n")
+	text, _ := p.layout.Apply(p.previewState.text, p.width)
+	lines := strings.Split(text, "\n")
 
 	// Truncate if we have more lines than available height
 	if availableHeight > 0 {
@@ -167,68 +181,132 @@ func (p *PreviewPane) String() string {
 
 // ScrollUp scrolls up in the viewport
 func (p *PreviewPane) ScrollUp(instance *session.Instance) error {
+	return p.scrollLine(instance, -1)
+}
+
+// ScrollDown scrolls down in the viewport
+func (p *PreviewPane) ScrollDown(instance *session.Instance) error {
+	return p.scrollLine(instance, 1)
+}
+
+// scrollLine scrolls one line in the given direction. The first scroll only enters scroll mode.
+func (p *PreviewPane) scrollLine(instance *session.Instance, direction int) error {
 	if instance == nil || instance.Status == session.Paused {
 		return nil
 	}
 
 	if !p.isScrolling {
-		// Entering scroll mode - capture entire pane content including scrollback history
-		content, err := instance.PreviewFullHistory()
-		if err != nil {
+		return p.enterScrollMode(instance)
+	}
+
+	// Already in scroll mode, just scroll the viewport
+	scrollViewport(&p.viewport, direction)
+	return nil
+}
+
+// ScrollBy scrolls the viewport by the given number of lines (negative scrolls up), entering
+// scroll mode first if needed.
+func (p *PreviewPane) ScrollBy(instance *session.Instance, lines int) error {
+	if instance == nil || instance.Status == session.Paused {
+		return nil
+	}
+
+	if !p.isScrolling {
+		if err := p.enterScrollMode(instance); err != nil {
 			return err
 		}
+	}
 
-		// Set content in the viewport
-		footer := lipgloss.NewStyle().
-			Foreground(lipgloss.AdaptiveColor{Light: "#808080", Dark: "#808080"}).
-			Render("ESC to exit scroll mode")
-
-		contentWithFooter := lipgloss.JoinVertical(lipgloss.Left, content, footer)
-		p.viewport.SetContent(contentWithFooter)
+	scrollViewport(&p.viewport, lines)
+	return nil
+}
 
-		// Position the viewport at the bottom initially
-		p.viewport.GotoBottom()
+// ScrollOffset returns the line the preview is scrolled to, and false if it isn't scrolled back
+// through the agent's history.
+func (p *PreviewPane) ScrollOffset() (offset int, scrolling bool) {
+	return p.viewport.YOffset, p.isScrolling
+}
 
-		p.isScrolling = true
+// ScrollTo scrolls back through the agent's history to line offset, entering scroll mode first
+// if needed.
+func (p *PreviewPane) ScrollTo(instance *session.Instance, offset int) error {
+	if instance == nil || instance.Status == session.Paused {
 		return nil
 	}
 
-	// Already in scroll mode, just scroll the viewport
-	p.viewport.LineUp(1)
+	if !p.isScrolling {
+		if err := p.enterScrollMode(instance); err != nil {
+			return err
+		}
+	}
+
+	p.viewport.SetYOffset(offset)
 	return nil
 }
 
-// ScrollDown scrolls down in the viewport
-func (p *PreviewPane) ScrollDown(instance *session.Instance) error {
+// JumpToFile moves to the next (direction > 0) or previous file header in the scrollback,
+// entering scroll mode first if needed.
+func (p *PreviewPane) JumpToFile(instance *session.Instance, direction int) error {
 	if instance == nil || instance.Status == session.Paused {
 		return nil
 	}
 
 	if !p.isScrolling {
-		// Entering scroll mode - capture entire pane content including scrollback history
-		content, err := instance.PreviewFullHistory()
-		if err != nil {
+		if err := p.enterScrollMode(instance); err != nil {
 			return err
 		}
+	}
+
+	JumpToFileHeader(&p.viewport, p.fileHeaders, direction)
+	return nil
+}
+
+// enterScrollMode captures the entire pane content including scrollback history into the
+// viewport, positioned at the bottom.
+func (p *PreviewPane) enterScrollMode(instance *session.Instance) error {
+	content, err := instance.PreviewFullHistory()
+	if err != nil {
+		return err
+	}
 
-		// Set content in the viewport
-		footer := lipgloss.NewStyle().
-			Foreground(lipgloss.AdaptiveColor{Light: "#808080", Dark: "#808080"}).
-			Render("ESC to exit scroll mode")
+	p.scrollContent = content
+	p.applyScrollLayout()
 
-		contentWithFooter := lipgloss.JoinVertical(lipgloss.Left, content, footer)
-		p.viewport.SetContent(contentWithFooter)
+	// Position the viewport at the bottom initially
+	p.viewport.GotoBottom()
 
-		// Position the viewport at the bottom initially
-		p.viewport.GotoBottom()
+	p.isScrolling = true
+	return nil
+}
 
-		p.isScrolling = true
-		return nil
+// applyScrollLayout lays the scrollback out for the pane's width, keeping the scroll position.
+func (p *PreviewPane) applyScrollLayout() {
+	footer := lipgloss.NewStyle().
+		Foreground(lipgloss.AdaptiveColor{Light: "#808080", Dark: "#808080"}).
+		Render("ESC to exit scroll mode")
+
+	content, rows := p.layout.Apply(p.scrollContent, p.width)
+	offset := p.viewport.YOffset
+	p.viewport.SetContent(lipgloss.JoinVertical(lipgloss.Left, content, footer))
+	p.viewport.SetYOffset(offset)
+	p.fileHeaders = MapRows(FileHeaderLines(p.scrollContent), rows)
+}
+
+// ToggleWrap switches between soft-wrapping long lines and panning them horizontally.
+func (p *PreviewPane) ToggleWrap() bool {
+	p.layout.ToggleWrap()
+	if p.isScrolling {
+		p.applyScrollLayout()
 	}
+	return p.layout.Wrap
+}
 
-	// Already in scroll mode, just scroll the viewport
-	p.viewport.LineDown(1)
-	return nil
+// Pan scrolls long lines horizontally by columns (negative pans left) when not wrapping.
+func (p *PreviewPane) Pan(columns int) {
+	p.layout.Pan(columns)
+	if p.isScrolling {
+		p.applyScrollLayout()
+	}
 }
 
 // ResetToNormalMode exits scroll mode and returns to normal mode
@@ -240,6 +318,7 @@ func (p *PreviewPane) ResetToNormalMode(instance *session.Instance) error {
 	if p.isScrolling {
 		p.isScrolling = false
 		// Reset viewport
+		p.scrollContent = ""
 		p.viewport.SetContent("")
 		p.viewport.GotoTop()
 
@@ -0,0 +1,86 @@
+package ui
+
+import (
+	"claude-squad/session/git"
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+var progressLabelStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("205")).Bold(true)
+
+var progressFilledStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("205"))
+
+var progressEmptyStyle = lipgloss.NewStyle().Foreground(lipgloss.AdaptiveColor{
+	Light: "#c0c0c0",
+	Dark:  "#3c3c3c",
+})
+
+// GitProgressBar renders the progress of a long-running git command (fetch, clone, push)
+// for a single instance.
+type GitProgressBar struct {
+	width    int
+	title    string
+	progress *git.GitProgress
+}
+
+func NewGitProgressBar() *GitProgressBar {
+	return &GitProgressBar{}
+}
+
+func (p *GitProgressBar) SetWidth(width int) {
+	p.width = width
+}
+
+// Start begins tracking progress for the instance with the given title.
+func (p *GitProgressBar) Start(title string) {
+	p.title = title
+	p.progress = nil
+}
+
+// Update records the latest progress for the tracked instance.
+func (p *GitProgressBar) Update(title string, progress git.GitProgress) {
+	p.title = title
+	p.progress = &progress
+}
+
+// Finish stops showing progress if the given instance is the one being tracked.
+func (p *GitProgressBar) Finish(title string) {
+	if p.title != title {
+		return
+	}
+	p.title = ""
+	p.progress = nil
+}
+
+// Active returns true if a git operation is currently being tracked.
+func (p *GitProgressBar) Active() bool {
+	return p.title != ""
+}
+
+func (p *GitProgressBar) String() string {
+	if !p.Active() {
+		return ""
+	}
+	if p.progress == nil {
+		return progressLabelStyle.Render(fmt.Sprintf("%s: waiting for git...", p.title))
+	}
+
+	label := fmt.Sprintf("%s: git %s - %s", p.title, p.progress.Operation, p.progress.Phase)
+	counts := fmt.Sprintf(" %3d%% (%d/%d)", p.progress.Percent, p.progress.Current, p.progress.Total)
+
+	// Give the bar whatever room is left after the label, within sensible bounds.
+	barWidth := p.width - lipgloss.Width(label) - lipgloss.Width(counts) - 3
+	if barWidth > 40 {
+		barWidth = 40
+	}
+	if barWidth < 10 {
+		barWidth = 10
+	}
+	filled := barWidth * p.progress.Percent / 100
+	bar := progressFilledStyle.Render(strings.Repeat("█", filled)) +
+		progressEmptyStyle.Render(strings.Repeat("░", barWidth-filled))
+
+	return progressLabelStyle.Render(label) + " " + bar + counts
+}
@@ -0,0 +1,135 @@
+package ui
+
+import (
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/viewport"
+)
+
+// JumpLines is the number of lines scrolled by a large jump (ctrl+↑/↓).
+const JumpLines = 10
+
+const (
+	// scrollRepeatWindow is the longest gap between two scroll key presses that still counts
+	// as the key being held down.
+	scrollRepeatWindow = 150 * time.Millisecond
+	// scrollRepeatsPerStep is how many repeats it takes for the scroll step to double.
+	scrollRepeatsPerStep = 5
+	// maxScrollStep caps the accelerated scroll step.
+	maxScrollStep = 16
+)
+
+// ScrollAccelerator grows the line scroll step while a scroll key is held down, so key
+// repeat gets through long output quickly while single presses still move one line.
+type ScrollAccelerator struct {
+	last      time.Time
+	direction int
+	repeats   int
+}
+
+// Step returns how many lines to scroll for a press in the given direction (-1 up, 1 down).
+func (a *ScrollAccelerator) Step(direction int) int {
+	now := time.Now()
+	if direction == a.direction && now.Sub(a.last) <= scrollRepeatWindow {
+		a.repeats++
+	} else {
+		a.repeats = 0
+	}
+	a.direction = direction
+	a.last = now
+
+	step := 1 << (a.repeats / scrollRepeatsPerStep)
+	if step > maxScrollStep {
+		step = maxScrollStep
+	}
+	return step
+}
+
+// scrollViewport scrolls vp by lines, where negative values scroll up.
+func scrollViewport(vp *viewport.Model, lines int) {
+	if lines < 0 {
+		vp.LineUp(-lines)
+	} else if lines > 0 {
+		vp.LineDown(lines)
+	}
+}
+
+// HandleViewportScrollKey applies the standard scroll keys to a viewport-backed view:
+// ↑/↓ (k/j) accelerate while held, ctrl+↑/↓ jump JumpLines, ctrl+u/ctrl+d move half a page,
+// pgup/pgdown a full page and home/end go to the top or bottom. accel may be nil. Returns
+// true if the key was a scroll key.
+func HandleViewportScrollKey(vp *viewport.Model, accel *ScrollAccelerator, key string) bool {
+	lineStep := func(direction int) int {
+		if accel == nil {
+			return direction
+		}
+		return direction * accel.Step(direction)
+	}
+
+	switch key {
+	case "up", "k":
+		scrollViewport(vp, lineStep(-1))
+	case "down", "j":
+		scrollViewport(vp, lineStep(1))
+	case "ctrl+up":
+		scrollViewport(vp, -JumpLines)
+	case "ctrl+down":
+		scrollViewport(vp, JumpLines)
+	case "ctrl+u":
+		vp.HalfViewUp()
+	case "ctrl+d":
+		vp.HalfViewDown()
+	case "pgup":
+		vp.ViewUp()
+	case "pgdown":
+		vp.ViewDown()
+	case "home", "ctrl+home":
+		vp.GotoTop()
+	case "end", "ctrl+end":
+		vp.GotoBottom()
+	default:
+		return false
+	}
+	return true
+}
+
+// ansiEscape matches terminal escape sequences so headers can be detected in captured output.
+var ansiEscape = regexp.MustCompile(`\x1b\[[0-9;?]*[A-Za-z]`)
+
+// fileHeaderPattern matches lines that start a new file: git diff headers and agent
+// file edit tool calls such as "⏺ Update(path)".
+var fileHeaderPattern = regexp.MustCompile(`^\s*(diff --git |[⏺●]\s*(Update|Write|Edit|MultiEdit|Create)\()`)
+
+// FileHeaderLines returns the line numbers in content that start a new file.
+func FileHeaderLines(content string) []int {
+	var headers []int
+	for i, line := range strings.Split(content, "\n") {
+		if fileHeaderPattern.MatchString(ansiEscape.ReplaceAllString(line, "")) {
+			headers = append(headers, i)
+		}
+	}
+	return headers
+}
+
+// JumpToFileHeader moves vp to the next (direction > 0) or previous (direction < 0) line in
+// headers relative to the current offset. Noop if there is no header in that direction.
+func JumpToFileHeader(vp *viewport.Model, headers []int, direction int) {
+	current := vp.YOffset
+	if direction > 0 {
+		for _, pos := range headers {
+			if pos > current {
+				vp.SetYOffset(pos)
+				return
+			}
+		}
+		return
+	}
+	for i := len(headers) - 1; i >= 0; i-- {
+		if headers[i] < current {
+			vp.SetYOffset(headers[i])
+			return
+		}
+	}
+}
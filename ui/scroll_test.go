@@ -0,0 +1,40 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/bubbles/viewport"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestScrollAcceleratorStep(t *testing.T) {
+	var accel ScrollAccelerator
+	for i := 0; i < scrollRepeatsPerStep; i++ {
+		assert.Equal(t, 1, accel.Step(1))
+	}
+	assert.Equal(t, 2, accel.Step(1))
+
+	// Changing direction resets the acceleration
+	assert.Equal(t, 1, accel.Step(-1))
+}
+
+func TestFileHeaderNavigation(t *testing.T) {
+	lines := make([]string, 50)
+	lines[5] = "diff --git a/foo.go b/foo.go"
+	lines[20] = "\x1b[1m⏺ Update(bar.go)\x1b[0m"
+	lines[40] = "  ● Write(baz.go)"
+	content := strings.Join(lines, "\n")
+
+	headers := FileHeaderLines(content)
+	assert.Equal(t, []int{5, 20, 40}, headers)
+
+	vp := viewport.New(10, 5)
+	vp.SetContent(content)
+	JumpToFileHeader(&vp, headers, 1)
+	assert.Equal(t, 5, vp.YOffset)
+	JumpToFileHeader(&vp, headers, 1)
+	assert.Equal(t, 20, vp.YOffset)
+	JumpToFileHeader(&vp, headers, -1)
+	assert.Equal(t, 5, vp.YOffset)
+}
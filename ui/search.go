@@ -0,0 +1,86 @@
+package ui
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+)
+
+var (
+	searchMatchStyle = lipgloss.NewStyle().
+				Foreground(lipgloss.Color("0")).
+				Background(lipgloss.Color("220"))
+	searchCurrentMatchStyle = lipgloss.NewStyle().
+				Foreground(lipgloss.Color("0")).
+				Background(lipgloss.Color("208")).
+				Bold(true)
+)
+
+// SearchMatch is an occurrence of a search query in a content line. Start and End are byte
+// offsets into the line with its ANSI escape sequences stripped.
+type SearchMatch struct {
+	Line  int
+	Start int
+	End   int
+}
+
+// FindMatches returns every occurrence of query in content, in order. The search ignores case
+// unless the query contains an upper case letter.
+func FindMatches(content, query string) []SearchMatch {
+	if query == "" {
+		return nil
+	}
+	foldCase := strings.ToLower(query) == query
+	var matches []SearchMatch
+	for i, line := range strings.Split(content, "\n") {
+		plain := ansi.Strip(line)
+		haystack := plain
+		if foldCase {
+			haystack = strings.Map(unicode.ToLower, plain)
+			// Lowering can change the byte length of some runes; search as is then
+			if len(haystack) != len(plain) {
+				haystack = plain
+			}
+		}
+		for offset := 0; ; {
+			idx := strings.Index(haystack[offset:], query)
+			if idx < 0 {
+				break
+			}
+			start := offset + idx
+			matches = append(matches, SearchMatch{Line: i, Start: start, End: start + len(query)})
+			offset = start + len(query)
+		}
+	}
+	return matches
+}
+
+// HighlightMatches highlights matches in content, the one at index current standing out.
+// Lines holding a match lose their own styling so the highlight stays readable.
+func HighlightMatches(content string, matches []SearchMatch, current int) string {
+	if len(matches) == 0 {
+		return content
+	}
+	lines := strings.Split(content, "\n")
+	for i := 0; i < len(matches); {
+		lineIdx := matches[i].Line
+		plain := ansi.Strip(lines[lineIdx])
+		var b strings.Builder
+		last := 0
+		for ; i < len(matches) && matches[i].Line == lineIdx; i++ {
+			match := matches[i]
+			style := searchMatchStyle
+			if i == current {
+				style = searchCurrentMatchStyle
+			}
+			b.WriteString(plain[last:match.Start])
+			b.WriteString(style.Render(plain[match.Start:match.End]))
+			last = match.End
+		}
+		b.WriteString(plain[last:])
+		lines[lineIdx] = b.String()
+	}
+	return strings.Join(lines, "\n")
+}
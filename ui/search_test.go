@@ -0,0 +1,30 @@
+package ui
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFindMatches(t *testing.T) {
+	content := "Error: build failed\nok\n\x1b[31merror\x1b[0m again, error"
+
+	assert.Equal(t, []SearchMatch{
+		{Line: 0, Start: 0, End: 5},
+		{Line: 2, Start: 0, End: 5},
+		{Line: 2, Start: 13, End: 18},
+	}, FindMatches(content, "error"))
+
+	// An upper case letter makes the search case sensitive
+	assert.Equal(t, []SearchMatch{{Line: 0, Start: 0, End: 5}}, FindMatches(content, "Error"))
+
+	assert.Empty(t, FindMatches(content, "missing"))
+	assert.Empty(t, FindMatches(content, ""))
+}
+
+func TestHighlightMatchesStripsStylingOfMatchedLines(t *testing.T) {
+	content := "\x1b[32mkept\x1b[0m\n\x1b[31merror\x1b[0m here"
+	got := HighlightMatches(content, FindMatches(content, "error"), 0)
+
+	assert.Equal(t, "\x1b[32mkept\x1b[0m\nerror here", got)
+}
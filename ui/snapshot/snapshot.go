@@ -0,0 +1,112 @@
+// Package snapshot compares rendered views with golden files, so rendering regressions such as
+// truncation, overflow and broken borders are caught by tests.
+//
+// Golden files live in the testdata/snapshots directory of the package under test. Run the
+// tests with UPDATE_SNAPSHOTS=1 to write them after an intended change, and review the diff.
+package snapshot
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+// Dir is the directory golden files are kept in, relative to the package under test.
+const Dir = "testdata/snapshots"
+
+// updateEnv is the environment variable that makes Assert write golden files.
+const updateEnv = "UPDATE_SNAPSHOTS"
+
+// Size is a terminal size views are rendered at.
+type Size struct {
+	Width, Height int
+}
+
+// Sizes are the terminal sizes views are snapshotted at: a small terminal, a common one and a
+// large one.
+var Sizes = []Size{{Width: 80, Height: 24}, {Width: 120, Height: 40}, {Width: 200, Height: 60}}
+
+func (s Size) String() string {
+	return fmt.Sprintf("%dx%d", s.Width, s.Height)
+}
+
+// Scale returns the size scaled by the given fractions, the way the app sizes its panes and
+// overlays.
+func (s Size) Scale(width, height float32) (int, int) {
+	return int(float32(s.Width) * width), int(float32(s.Height) * height)
+}
+
+// Normalize returns view as stored in a golden file: without ANSI escape sequences or trailing
+// whitespace on its lines, so styling and padding changes alone don't fail snapshots.
+func Normalize(view string) string {
+	lines := strings.Split(ansi.Strip(view), "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimRight(line, " \t")
+	}
+	return strings.TrimRight(strings.Join(lines, "\n"), "\n") + "\n"
+}
+
+// Assert compares view with the golden file named name, failing the test with both versions if
+// they differ. With UPDATE_SNAPSHOTS set, it writes the golden file instead.
+func Assert(t testing.TB, name, view string) {
+	t.Helper()
+	path := filepath.Join(Dir, name+".golden")
+	got := Normalize(view)
+
+	if os.Getenv(updateEnv) != "" {
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("failed to create snapshot directory: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(got), 0644); err != nil {
+			t.Fatalf("failed to write snapshot %s: %v", path, err)
+		}
+		return
+	}
+
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read snapshot %s (run with %s=1 to create it): %v", path, updateEnv, err)
+	}
+	if got != string(want) {
+		t.Errorf("view differs from snapshot %s (run with %s=1 to update it)\n%s", path, updateEnv, diff(string(want), got))
+	}
+}
+
+// AssertFits fails the test if view is wider or taller than the given size. A zero height
+// doesn't limit the height.
+func AssertFits(t testing.TB, view string, width, height int) {
+	t.Helper()
+	lines := strings.Split(view, "\n")
+	if height > 0 && len(lines) > height {
+		t.Errorf("view is %d lines tall, more than the %d available", len(lines), height)
+	}
+	for i, line := range lines {
+		if w := ansi.StringWidth(line); w > width {
+			t.Errorf("line %d is %d columns wide, more than the %d available: %q", i+1, w, width, ansi.Strip(line))
+		}
+	}
+}
+
+// diff returns the lines of want and got that differ, marked - and +.
+func diff(want, got string) string {
+	wantLines, gotLines := strings.Split(want, "\n"), strings.Split(got, "\n")
+	var b strings.Builder
+	for i := 0; i < max(len(wantLines), len(gotLines)); i++ {
+		var w, g string
+		if i < len(wantLines) {
+			w = wantLines[i]
+		}
+		if i < len(gotLines) {
+			g = gotLines[i]
+		}
+		if w == g {
+			continue
+		}
+		fmt.Fprintf(&b, "line %d:\n- %s\n+ %s\n", i+1, w, g)
+	}
+	return b.String()
+}
@@ -0,0 +1,111 @@
+package ui
+
+import (
+	"claude-squad/session"
+	"claude-squad/session/git"
+	"claude-squad/ui/snapshot"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/bubbles/spinner"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/stretchr/testify/require"
+)
+
+const snapshotDiff = `diff --git a/app/app.go b/app/app.go
+index 1111111..2222222 100644
+--- a/app/app.go
++++ b/app/app.go
+@@ -10,6 +10,8 @@ func main() {
+ 	m := newHome()
+-	m.Run()
++	if err := m.Run(); err != nil {
++		log.Fatal(err)
++	}
+ 	return
+ }
+diff --git a/README.md b/README.md
+index 3333333..4444444 100644
+--- a/README.md
++++ b/README.md
+@@ -1,3 +1,3 @@
+-# Claude Squad
++# Claude Squad, a terminal app that manages several AI agents working in separate workspaces
+
+ Install it with brew.
+`
+
+func TestListSnapshots(t *testing.T) {
+	s := spinner.New()
+	l := NewList(&s, false)
+	for _, opts := range []struct {
+		title  string
+		status session.Status
+		group  string
+	}{
+		{"fix-login", session.Running, "backend"},
+		{"add-search", session.Ready, "backend"},
+		{"a-session-with-a-title-long-enough-to-be-truncated", session.Paused, ""},
+		{"docs", session.Ready, ""},
+	} {
+		instance, err := session.NewInstance(session.InstanceOptions{Title: opts.title, Path: ".", Program: "claude"})
+		require.NoError(t, err)
+		instance.Status = opts.status
+		instance.Branch = "squad/" + opts.title
+		instance.Group = opts.group
+		l.AddInstance(instance)
+	}
+	l.SetGroups([]session.Group{{Name: "backend"}})
+
+	for _, size := range snapshot.Sizes {
+		t.Run(size.String(), func(t *testing.T) {
+			width, height := size.Scale(0.3, 0.9)
+			l.SetSize(width, height)
+			view := l.String()
+			snapshot.Assert(t, "list_"+size.String(), view)
+			// The list doesn't scroll yet, so only its width is checked
+			snapshot.AssertFits(t, view, width, 0)
+		})
+	}
+}
+
+func TestDiffPaneSnapshots(t *testing.T) {
+	for _, size := range snapshot.Sizes {
+		t.Run(size.String(), func(t *testing.T) {
+			d := NewDiffPane()
+			width, height := size.Scale(0.7, 0.9)
+			d.SetSize(width, height)
+			d.showStats("[All Changes] ", &git.DiffStats{Added: 4, Removed: 2, Content: snapshotDiff})
+			view := d.String()
+			snapshot.Assert(t, "diff_"+size.String(), view)
+			snapshot.AssertFits(t, view, width, height)
+		})
+	}
+}
+
+func TestPRReviewSnapshots(t *testing.T) {
+	created := time.Date(2025, 1, 2, 15, 4, 0, 0, time.UTC)
+	pr := &git.PullRequest{Number: 42, Title: "Handle errors returned by Run", State: "open"}
+	for i, comment := range []*git.PRComment{
+		{Type: "review", Author: "alice", Body: "Looks good overall, a few nits below."},
+		{Type: "review_comment", Author: "bob", Path: "app/app.go", Line: 12,
+			Body: "Should this wrap the error with some context before logging it? Something like failed to run the app."},
+		{Type: "issue_comment", Author: "carol", Body: "Can we get this merged before the release?"},
+	} {
+		comment.ID = i + 1
+		comment.CreatedAt = created
+		comment.UpdatedAt = created
+		pr.Comments = append(pr.Comments, comment)
+	}
+
+	for _, size := range snapshot.Sizes {
+		t.Run(size.String(), func(t *testing.T) {
+			m := NewPRReviewModel(pr)
+			m, _ = m.Update(tea.WindowSizeMsg{Width: size.Width, Height: size.Height})
+			view := m.View()
+			// The help footer isn't cut to the width yet, so the view isn't checked to fit
+			snapshot.Assert(t, fmt.Sprintf("pr_review_%s", size), view)
+		})
+	}
+}
@@ -3,6 +3,10 @@ package ui
 import (
 	"claude-squad/log"
 	"claude-squad/session"
+	"claude-squad/session/git"
+	"math"
+	"strings"
+
 	"github.com/charmbracelet/lipgloss"
 )
 
@@ -34,9 +38,30 @@ const (
 	AITab = iota
 	DiffTab
 	TerminalTab
-	JestTab
+	TestTab
 )
 
+// tabNames name the tabs in the config and saved instances, in the order of their indexes.
+var tabNames = []string{"ai", "diff", "terminal", "tests"}
+
+// TabByName returns the index of the tab named name, e.g. "diff". ok is false for an unknown name.
+func TabByName(name string) (tab int, ok bool) {
+	for tab, tabName := range tabNames {
+		if strings.EqualFold(tabName, name) {
+			return tab, true
+		}
+	}
+	return 0, false
+}
+
+// TabName returns the name of the tab at index tab, the inverse of TabByName.
+func TabName(tab int) string {
+	if tab < 0 || tab >= len(tabNames) {
+		return ""
+	}
+	return tabNames[tab]
+}
+
 type Tab struct {
 	Name   string
 	Render func(width int, height int) string
@@ -55,28 +80,28 @@ type TabbedWindow struct {
 	diff     *DiffPane
 	instance *session.Instance
 	terminal *TerminalPane
-	jest     *JestPane
+	tests    *TestPane
 }
 
-func NewTabbedWindow(preview *PreviewPane, diff *DiffPane, terminal *TerminalPane, jest *JestPane) *TabbedWindow {
+func NewTabbedWindow(preview *PreviewPane, diff *DiffPane, terminal *TerminalPane, tests *TestPane) *TabbedWindow {
 	return &TabbedWindow{
 		tabs: []string{
 			"AI",
 			"Diff",
 			"Terminal",
-			"Jest",
+			"Tests",
 		},
 		preview:  preview,
 		diff:     diff,
 		terminal: terminal,
-		jest:     jest,
+		tests:    tests,
 	}
 }
 
 func (w *TabbedWindow) SetInstance(instance *session.Instance) {
 	w.instance = instance
-	// Update Jest pane with the current instance
-	w.jest.SetInstance(instance)
+	// Update the test pane with the current instance
+	w.tests.SetInstance(instance)
 }
 
 // AdjustPreviewWidth adjusts the width of the preview pane to be 90% of the provided width.
@@ -99,7 +124,7 @@ func (w *TabbedWindow) SetSize(width, height int) {
 	w.preview.SetSize(contentWidth, contentHeight)
 	w.diff.SetSize(contentWidth, contentHeight)
 	w.terminal.SetSize(contentWidth, contentHeight)
-	w.jest.SetSize(contentWidth, contentHeight)
+	w.tests.SetSize(contentWidth, contentHeight)
 }
 
 func (w *TabbedWindow) GetPreviewSize() (width, height int) {
@@ -192,8 +217,8 @@ func (w *TabbedWindow) ScrollUp() {
 		if err != nil {
 			log.InfoLog.Printf("terminal pane failed to scroll up: %v", err)
 		}
-	case JestTab:
-		w.jest.ScrollUp()
+	case TestTab:
+		w.tests.ScrollUp()
 	}
 }
 
@@ -211,45 +236,154 @@ func (w *TabbedWindow) ScrollDown() {
 		if err != nil {
 			log.InfoLog.Printf("terminal pane failed to scroll down: %v", err)
 		}
-	case JestTab:
-		w.jest.ScrollDown()
+	case TestTab:
+		w.tests.ScrollDown()
+	}
+}
+
+// ScrollBy scrolls the active pane by the given number of lines (negative scrolls up)
+func (w *TabbedWindow) ScrollBy(lines int) {
+	switch w.activeTab {
+	case AITab:
+		if err := w.preview.ScrollBy(w.instance, lines); err != nil {
+			log.InfoLog.Printf("tabbed window failed to scroll: %v", err)
+		}
+	case DiffTab:
+		w.diff.ScrollBy(lines)
+	case TerminalTab:
+		if err := w.terminal.ScrollBy(w.instance, lines); err != nil {
+			log.InfoLog.Printf("terminal pane failed to scroll: %v", err)
+		}
+	case TestTab:
+		w.tests.ScrollBy(lines)
 	}
 }
 
+// pageSize returns the number of content lines visible in the active pane
+func (w *TabbedWindow) pageSize() int {
+	if w.preview.height < 2 {
+		return 1
+	}
+	return w.preview.height
+}
+
 func (w *TabbedWindow) ScrollToTop() {
 	if w.activeTab == DiffTab {
 		w.diff.ScrollToTop()
+		return
 	}
+	w.ScrollBy(-math.MaxInt32)
 }
 
 func (w *TabbedWindow) ScrollToBottom() {
 	if w.activeTab == DiffTab {
 		w.diff.ScrollToBottom()
+		return
 	}
+	w.ScrollBy(math.MaxInt32)
 }
 
 func (w *TabbedWindow) PageUp() {
 	if w.activeTab == DiffTab {
 		w.diff.PageUp()
+		return
 	}
+	w.ScrollBy(-w.pageSize())
 }
 
 func (w *TabbedWindow) PageDown() {
 	if w.activeTab == DiffTab {
 		w.diff.PageDown()
+		return
 	}
+	w.ScrollBy(w.pageSize())
+}
+
+// HalfPageUp scrolls the active pane up by half a page
+func (w *TabbedWindow) HalfPageUp() {
+	w.ScrollBy(-w.pageSize() / 2)
+}
+
+// HalfPageDown scrolls the active pane down by half a page
+func (w *TabbedWindow) HalfPageDown() {
+	w.ScrollBy(w.pageSize() / 2)
 }
 
 func (w *TabbedWindow) JumpToNextFile() {
-	if w.activeTab == DiffTab {
+	switch w.activeTab {
+	case DiffTab:
 		w.diff.JumpToNextFile()
+	case AITab:
+		if err := w.preview.JumpToFile(w.instance, 1); err != nil {
+			log.InfoLog.Printf("tabbed window failed to jump to file: %v", err)
+		}
 	}
 }
 
 func (w *TabbedWindow) JumpToPrevFile() {
-	if w.activeTab == DiffTab {
+	switch w.activeTab {
+	case DiffTab:
 		w.diff.JumpToPrevFile()
+	case AITab:
+		if err := w.preview.JumpToFile(w.instance, -1); err != nil {
+			log.InfoLog.Printf("tabbed window failed to jump to file: %v", err)
+		}
+	}
+}
+
+// ToggleWrap switches the active pane between soft-wrapping long lines and panning them. It
+// returns whether the pane now wraps, and false for ok if the pane has no wrap setting.
+func (w *TabbedWindow) ToggleWrap() (wrap bool, ok bool) {
+	switch w.activeTab {
+	case AITab:
+		return w.preview.ToggleWrap(), true
+	case DiffTab:
+		return w.diff.ToggleWrap(), true
 	}
+	return false, false
+}
+
+// ToggleSideBySide switches the diff between unified and side-by-side columns. It returns whether
+// the diff is now side by side, and false for ok if the diff tab isn't active.
+func (w *TabbedWindow) ToggleSideBySide() (sideBySide bool, ok bool) {
+	if w.activeTab != DiffTab {
+		return false, false
+	}
+	return w.diff.ToggleSideBySide(), true
+}
+
+// Pan scrolls long lines of the active pane horizontally by columns (negative pans left).
+func (w *TabbedWindow) Pan(columns int) {
+	switch w.activeTab {
+	case AITab:
+		w.preview.Pan(columns)
+	case DiffTab:
+		w.diff.Pan(columns)
+	}
+}
+
+// ScrollPositions returns the lines the diff and the preview are scrolled to, and whether the
+// preview is scrolled back through the agent's history.
+func (w *TabbedWindow) ScrollPositions() (diffOffset, previewOffset int, previewScrolling bool) {
+	previewOffset, previewScrolling = w.preview.ScrollOffset()
+	return w.diff.ScrollOffset(), previewOffset, previewScrolling
+}
+
+// RestoreScrollPositions scrolls the diff and, if previewScrolling, the preview of the current
+// instance back to where ScrollPositions returned.
+func (w *TabbedWindow) RestoreScrollPositions(diffOffset, previewOffset int, previewScrolling bool) {
+	w.diff.SetScrollOffset(diffOffset)
+	if !previewScrolling {
+		return
+	}
+	if err := w.preview.ScrollTo(w.instance, previewOffset); err != nil {
+		log.InfoLog.Printf("tabbed window failed to restore the preview scroll: %v", err)
+	}
+}
+
+// ActiveTab returns the index of the active tab, e.g. DiffTab.
+func (w *TabbedWindow) ActiveTab() int {
+	return w.activeTab
 }
 
 // IsInDiffTab returns true if the diff tab is currently active
@@ -277,23 +411,23 @@ func (w *TabbedWindow) IsInTerminalTab() bool {
 	return w.activeTab == TerminalTab
 }
 
-// IsInJestTab returns true if the Jest tab is currently active
-func (w *TabbedWindow) IsInJestTab() bool {
-	return w.activeTab == JestTab
+// IsInTestTab returns true if the test tab is currently active
+func (w *TabbedWindow) IsInTestTab() bool {
+	return w.activeTab == TestTab
 }
 
-// UpdateJest updates the Jest pane with test results
-func (w *TabbedWindow) UpdateJest(instance *session.Instance) {
-	if w.activeTab != JestTab {
+// UpdateTests runs the tests for the instance if the test tab is active
+func (w *TabbedWindow) UpdateTests(instance *session.Instance) {
+	if w.activeTab != TestTab {
 		return
 	}
-	w.jest.RunTests(instance)
+	w.tests.RunTests(instance)
 }
 
-// JestRerunTests reruns the Jest tests
-func (w *TabbedWindow) JestRerunTests() {
-	if w.activeTab == JestTab && w.instance != nil {
-		w.jest.RunTests(w.instance)
+// RerunTests reruns the tests of the current instance
+func (w *TabbedWindow) RerunTests() {
+	if w.activeTab == TestTab && w.instance != nil {
+		w.tests.RunTests(w.instance)
 	}
 }
 
@@ -307,6 +441,31 @@ func (w *TabbedWindow) SetDiffModeLastCommit() {
 	w.diff.SetDiffMode(DiffModeLastCommit)
 }
 
+// SetDiffBase shows all changes against ref, a branch, tag or commit, or against the branch's
+// base again if ref is empty
+func (w *TabbedWindow) SetDiffBase(ref string) {
+	w.diff.SetBaseRef(ref)
+}
+
+// DiffBase returns the ref the diff tab shows all changes against, or "" for the branch's base
+func (w *TabbedWindow) DiffBase() string {
+	return w.diff.BaseRef()
+}
+
+// SetDiffModeStaging sets the diff view to show staged and unstaged changes separately
+func (w *TabbedWindow) SetDiffModeStaging() {
+	w.diff.SetDiffMode(DiffModeStaging)
+}
+
+// SelectedHunk returns the hunk at the top of the diff tab's staging view and whether it is
+// staged. ok is false if the staging view isn't showing a hunk.
+func (w *TabbedWindow) SelectedHunk() (hunk git.DiffHunk, staged bool, ok bool) {
+	if w.activeTab != DiffTab {
+		return git.DiffHunk{}, false, false
+	}
+	return w.diff.SelectedHunk()
+}
+
 // NavigateToPrevCommit moves to the previous (older) commit in diff view
 func (w *TabbedWindow) NavigateToPrevCommit() {
 	if w.activeTab == DiffTab {
@@ -377,8 +536,8 @@ func (w *TabbedWindow) String() string {
 		content = w.diff.String()
 	case TerminalTab:
 		content = w.terminal.String()
-	case JestTab:
-		content = w.jest.String()
+	case TestTab:
+		content = w.tests.String()
 	}
 	window := windowStyle.Render(
 		lipgloss.Place(
@@ -57,6 +57,9 @@ func (t *TerminalPane) UpdateContent(instance *session.Instance) error {
 	case instance.Status == session.Paused:
 		t.setFallbackState("Session is paused. Press 'r' to resume.")
 		return nil
+	case instance.Broken():
+		t.setFallbackState("The worktree of this session was deleted. Press enter to repair it.")
+		return nil
 	}
 
 	// If in scroll mode, don't update content (to preserve scroll position)
@@ -153,67 +156,66 @@ func (t *TerminalPane) String() string {
 
 // ScrollUp scrolls up in the viewport
 func (t *TerminalPane) ScrollUp(instance *session.Instance) error {
+	return t.scrollLine(instance, -1)
+}
+
+// ScrollDown scrolls down in the viewport
+func (t *TerminalPane) ScrollDown(instance *session.Instance) error {
+	return t.scrollLine(instance, 1)
+}
+
+// scrollLine scrolls one line in the given direction. The first scroll only enters scroll mode.
+func (t *TerminalPane) scrollLine(instance *session.Instance, direction int) error {
 	if instance == nil || instance.Status == session.Paused {
 		return nil
 	}
 
 	if !t.isScrolling {
-		// Entering scroll mode - capture entire terminal content including scrollback history
-		content, err := instance.GetTerminalFullHistory()
-		if err != nil {
-			return err
-		}
-
-		// Set content in the viewport
-		footer := lipgloss.NewStyle().
-			Foreground(lipgloss.AdaptiveColor{Light: "#808080", Dark: "#808080"}).
-			Render("ESC to exit scroll mode")
-
-		contentWithFooter := lipgloss.JoinVertical(lipgloss.Left, content, footer)
-		t.viewport.SetContent(contentWithFooter)
-
-		// Position the viewport at the bottom initially
-		t.viewport.GotoBottom()
-
-		t.isScrolling = true
-		return nil
+		return t.enterScrollMode(instance)
 	}
 
 	// Already in scroll mode, just scroll the viewport
-	t.viewport.LineUp(1)
+	scrollViewport(&t.viewport, direction)
 	return nil
 }
 
-// ScrollDown scrolls down in the viewport
-func (t *TerminalPane) ScrollDown(instance *session.Instance) error {
+// ScrollBy scrolls the viewport by the given number of lines (negative scrolls up), entering
+// scroll mode first if needed.
+func (t *TerminalPane) ScrollBy(instance *session.Instance, lines int) error {
 	if instance == nil || instance.Status == session.Paused {
 		return nil
 	}
 
 	if !t.isScrolling {
-		// Entering scroll mode - capture entire terminal content including scrollback history
-		content, err := instance.GetTerminalFullHistory()
-		if err != nil {
+		if err := t.enterScrollMode(instance); err != nil {
 			return err
 		}
+	}
 
-		// Set content in the viewport
-		footer := lipgloss.NewStyle().
-			Foreground(lipgloss.AdaptiveColor{Light: "#808080", Dark: "#808080"}).
-			Render("ESC to exit scroll mode")
+	scrollViewport(&t.viewport, lines)
+	return nil
+}
 
-		contentWithFooter := lipgloss.JoinVertical(lipgloss.Left, content, footer)
-		t.viewport.SetContent(contentWithFooter)
+// enterScrollMode captures the entire terminal content including scrollback history into the
+// viewport, positioned at the bottom.
+func (t *TerminalPane) enterScrollMode(instance *session.Instance) error {
+	content, err := instance.GetTerminalFullHistory()
+	if err != nil {
+		return err
+	}
 
-		// Position the viewport at the bottom initially
-		t.viewport.GotoBottom()
+	// Set content in the viewport
+	footer := lipgloss.NewStyle().
+		Foreground(lipgloss.AdaptiveColor{Light: "#808080", Dark: "#808080"}).
+		Render("ESC to exit scroll mode")
 
-		t.isScrolling = true
-		return nil
-	}
+	contentWithFooter := lipgloss.JoinVertical(lipgloss.Left, content, footer)
+	t.viewport.SetContent(contentWithFooter)
 
-	// Already in scroll mode, just scroll the viewport
-	t.viewport.LineDown(1)
+	// Position the viewport at the bottom initially
+	t.viewport.GotoBottom()
+
+	t.isScrolling = true
 	return nil
 }
 
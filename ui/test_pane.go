@@ -25,23 +25,26 @@ const (
 	ideOpenDelay = 100 * time.Millisecond
 )
 
-type JestPane struct {
+type TestPane struct {
 	width    int
 	height   int
 	viewport viewport.Model
 	content  string
 	// Per-instance state maps
-	instanceStates  map[string]*JestInstanceState
+	instanceStates  map[string]*TestInstanceState
 	currentInstance *session.Instance
 	mu              sync.Mutex
 	globalConfig    *config.Config
+	// onTestsFailed is called, from the test goroutine, when a run finishes with failures
+	onTestsFailed func(instance *session.Instance, failedFiles []string)
 }
 
-type JestInstanceState struct {
+type TestInstanceState struct {
 	running      bool
 	testResults  []TestResult
 	failedFiles  []string
 	workingDir   string
+	command      string
 	currentIndex int
 	liveOutput   string
 	cmd          *exec.Cmd
@@ -56,22 +59,28 @@ type TestResult struct {
 	Line        int
 }
 
-func NewJestPane(globalConfig *config.Config) *JestPane {
+func NewTestPane(globalConfig *config.Config) *TestPane {
 	vp := viewport.New(0, 0)
-	return &JestPane{
+	return &TestPane{
 		viewport:       vp,
-		instanceStates: make(map[string]*JestInstanceState),
+		instanceStates: make(map[string]*TestInstanceState),
 		globalConfig:   globalConfig,
 	}
 }
 
-func (j *JestPane) SetSize(width, height int) {
+// SetOnTestsFailed sets a callback run when a test run finishes with failed files. It is called
+// from the goroutine running the tests.
+func (j *TestPane) SetOnTestsFailed(callback func(instance *session.Instance, failedFiles []string)) {
+	j.onTestsFailed = callback
+}
+
+func (j *TestPane) SetSize(width, height int) {
 	j.width = width
 	j.height = height
 	j.updateViewport()
 }
 
-func (j *JestPane) getInstanceKey(instance *session.Instance) string {
+func (j *TestPane) getInstanceKey(instance *session.Instance) string {
 	if instance == nil {
 		return ""
 	}
@@ -80,7 +89,7 @@ func (j *JestPane) getInstanceKey(instance *session.Instance) string {
 	return fmt.Sprintf("%s:%s", instance.Path, instance.Branch)
 }
 
-func (j *JestPane) getCurrentState() *JestInstanceState {
+func (j *TestPane) getCurrentState() *TestInstanceState {
 	j.mu.Lock()
 	defer j.mu.Unlock()
 
@@ -92,7 +101,7 @@ func (j *JestPane) getCurrentState() *JestInstanceState {
 	state, exists := j.instanceStates[key]
 	if !exists {
 		// Create a new state for this instance if it doesn't exist
-		state = &JestInstanceState{
+		state = &TestInstanceState{
 			testResults:  []TestResult{},
 			failedFiles:  []string{},
 			currentIndex: -1,
@@ -102,7 +111,7 @@ func (j *JestPane) getCurrentState() *JestInstanceState {
 	return state
 }
 
-func (j *JestPane) getOrCreateState(instance *session.Instance) *JestInstanceState {
+func (j *TestPane) getOrCreateState(instance *session.Instance) *TestInstanceState {
 	j.mu.Lock()
 	defer j.mu.Unlock()
 
@@ -113,7 +122,7 @@ func (j *JestPane) getOrCreateState(instance *session.Instance) *JestInstanceSta
 	key := j.getInstanceKey(instance)
 	state, exists := j.instanceStates[key]
 	if !exists {
-		state = &JestInstanceState{
+		state = &TestInstanceState{
 			testResults:  []TestResult{},
 			failedFiles:  []string{},
 			currentIndex: -1,
@@ -123,7 +132,7 @@ func (j *JestPane) getOrCreateState(instance *session.Instance) *JestInstanceSta
 	return state
 }
 
-func (j *JestPane) SetInstance(instance *session.Instance) {
+func (j *TestPane) SetInstance(instance *session.Instance) {
 	j.mu.Lock()
 	j.currentInstance = instance
 	j.mu.Unlock()
@@ -136,22 +145,25 @@ func (j *JestPane) SetInstance(instance *session.Instance) {
 	}
 }
 
-func (j *JestPane) String() string {
+func (j *TestPane) String() string {
 	if j.height < 5 {
 		return ""
 	}
 
-	header := titleStyle.Render("Jest Test Runner")
+	header := titleStyle.Render("Test Runner")
 
 	var status string
 	var instanceInfo string
 	statusStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("241"))
 
+	state := j.getCurrentState()
 	if j.currentInstance != nil {
 		instanceInfo = fmt.Sprintf(" - %s", j.currentInstance.Title)
+		if state != nil && state.command != "" {
+			instanceInfo += statusStyle.Render(fmt.Sprintf(" (%s)", state.command))
+		}
 	}
 
-	state := j.getCurrentState()
 	if j.currentInstance == nil {
 		status = statusStyle.Render("No instance selected")
 	} else if state != nil && state.running {
@@ -212,7 +224,7 @@ func (j *JestPane) String() string {
 	)
 }
 
-func (j *JestPane) formatContent() string {
+func (j *TestPane) formatContent() string {
 	state := j.getCurrentState()
 	if state == nil {
 		dimStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("241"))
@@ -230,14 +242,14 @@ func (j *JestPane) formatContent() string {
 
 Available commands:
   t - Run all tests
-  r - Re-run tests (when in Jest tab)
+  r - Re-run tests (when in the test tab)
   n - Navigate to next failure
   p - Navigate to previous failure
   ↵ - Open failed test in IDE`
 	return dimStyle.Render(helpText)
 }
 
-func (j *JestPane) RunTests(instance *session.Instance) error {
+func (j *TestPane) RunTests(instance *session.Instance) error {
 	state := j.getOrCreateState(instance)
 	if state == nil {
 		return fmt.Errorf("no instance provided")
@@ -271,18 +283,24 @@ func (j *JestPane) RunTests(instance *session.Instance) error {
 
 	worktreePath := gitWorktree.GetWorktreePath()
 
-	// Find package.json in the worktree directory
-	workDir, err := j.findJestWorkingDir(worktreePath)
+	runner, workDir, err := j.resolveTestRunner(worktreePath)
+	var parser TestOutputParser
+	if err == nil {
+		parser, err = newTestParser(runner, workDir)
+	}
 	if err != nil {
 		j.mu.Lock()
 		state.running = false
-		state.liveOutput = errorStyle.Render(fmt.Sprintf("Error finding package.json: %v", err))
+		state.liveOutput = errorStyle.Render(fmt.Sprintf("Error finding the test command: %v", err))
 		j.mu.Unlock()
 		j.updateViewport()
 		return err
 	}
 
+	j.mu.Lock()
 	state.workingDir = workDir
+	state.command = runner.Command
+	j.mu.Unlock()
 
 	// Create output channel for live updates
 	outputChan := make(chan string, 100)
@@ -300,12 +318,48 @@ func (j *JestPane) RunTests(instance *session.Instance) error {
 		}
 	}()
 
-	// Run Jest with streaming output
-	go j.runJestWithStream(instance, state, workDir, outputChan)
+	// Run the tests with streaming output
+	go j.runWithStream(instance, state, runner.Command, parser, workDir, outputChan)
 
 	return nil
 }
 
+// detectedTestRunners are the test commands used for worktrees without a configured runner,
+// picked by the first marker file found at the worktree root.
+var detectedTestRunners = []struct {
+	marker string
+	runner config.TestRunnerConfig
+}{
+	{"package.json", config.TestRunnerConfig{Command: "yarn tester", Parser: "jest"}},
+	{"go.mod", config.TestRunnerConfig{Command: "go test ./...", Parser: "go"}},
+	{"Cargo.toml", config.TestRunnerConfig{Command: "cargo test", Parser: "cargo"}},
+	{"pytest.ini", config.TestRunnerConfig{Command: "pytest", Parser: "pytest"}},
+	{"pyproject.toml", config.TestRunnerConfig{Command: "pytest", Parser: "pytest"}},
+	{"setup.py", config.TestRunnerConfig{Command: "pytest", Parser: "pytest"}},
+}
+
+// resolveTestRunner returns the test runner for a worktree and the directory to run it in. A
+// configured runner wins; otherwise it is detected from the files at the worktree root, falling
+// back to "yarn tester" next to the nearest package.json.
+func (j *TestPane) resolveTestRunner(worktreePath string) (*config.TestRunnerConfig, string, error) {
+	if runner := config.GetEffectiveTestRunner(worktreePath, j.globalConfig); runner != nil {
+		return runner, filepath.Join(worktreePath, runner.Dir), nil
+	}
+
+	for _, detected := range detectedTestRunners {
+		if _, err := os.Stat(filepath.Join(worktreePath, detected.marker)); err == nil {
+			runner := detected.runner
+			return &runner, worktreePath, nil
+		}
+	}
+
+	workDir, err := j.findJestWorkingDir(worktreePath)
+	if err != nil {
+		return nil, "", fmt.Errorf("no test_runner configured and none detected: %w", err)
+	}
+	return &config.TestRunnerConfig{Command: "yarn tester", Parser: "jest"}, workDir, nil
+}
+
 // parseFailedTestFile extracts the file path from a FAIL line if present
 func parseFailedTestFile(line string, workDir string) string {
 	// Check if line starts with "FAIL "
@@ -342,15 +396,14 @@ func parseFailedTestFile(line string, workDir string) string {
 	return absPath
 }
 
-func (j *JestPane) runJestWithStream(instance *session.Instance, state *JestInstanceState, workDir string, outputChan chan<- string) {
+func (j *TestPane) runWithStream(instance *session.Instance, state *TestInstanceState, command string, parser TestOutputParser, workDir string, outputChan chan<- string) {
 	defer close(outputChan)
 
-	// Run Jest without JSON for live output
-	cmd := exec.Command("yarn", "tester")
+	cmd := exec.Command("sh", "-c", command)
 	cmd.Dir = workDir
 
 	// Log debug info
-	log.InfoLog.Printf("Running Jest tests - command: yarn tester, workDir: %s, instance path: %s", workDir, instance.Path)
+	log.InfoLog.Printf("Running tests - command: %s, workDir: %s, instance path: %s", command, workDir, instance.Path)
 
 	// Store cmd in state so we can kill it if needed
 	j.mu.Lock()
@@ -372,7 +425,7 @@ func (j *JestPane) runJestWithStream(instance *session.Instance, state *JestInst
 
 	// Start the command
 	if err := cmd.Start(); err != nil {
-		outputChan <- fmt.Sprintf("Error starting Jest: %v", err)
+		outputChan <- fmt.Sprintf("Error starting tests: %v", err)
 		j.mu.Lock()
 		state.running = false
 		j.mu.Unlock()
@@ -384,16 +437,20 @@ func (j *JestPane) runJestWithStream(instance *session.Instance, state *JestInst
 	var failedFilesMu sync.Mutex
 	failedFiles := []string{}
 
-	// Helper function to add failed files with thread safety
-	addFailedFile := func(file string) {
+	// Helper function to parse a line and add its failed files with thread safety. The parser
+	// may keep state between lines, so it is only called with the lock held.
+	addFailedFiles := func(line string) {
 		failedFilesMu.Lock()
 		defer failedFilesMu.Unlock()
-		for _, f := range failedFiles {
-			if f == file {
-				return // Already added
+	files:
+		for _, file := range parser.FailedFiles(line) {
+			for _, f := range failedFiles {
+				if f == file {
+					continue files // Already added
+				}
 			}
+			failedFiles = append(failedFiles, file)
 		}
-		failedFiles = append(failedFiles, file)
 	}
 
 	// Create wait group for both readers
@@ -410,9 +467,7 @@ func (j *JestPane) runJestWithStream(instance *session.Instance, state *JestInst
 			allOutput.WriteString(line + "\n")
 
 			// Look for test failures in real-time
-			if failedFile := parseFailedTestFile(line, workDir); failedFile != "" {
-				addFailedFile(failedFile)
-			}
+			addFailedFiles(line)
 		}
 		if err := scanner.Err(); err != nil {
 			outputChan <- fmt.Sprintf("Error reading stdout: %v", err)
@@ -428,10 +483,8 @@ func (j *JestPane) runJestWithStream(instance *session.Instance, state *JestInst
 			outputChan <- line
 			allOutput.WriteString(line + "\n")
 
-			// Also check stderr for FAIL lines (Jest might output to stderr)
-			if failedFile := parseFailedTestFile(line, workDir); failedFile != "" {
-				addFailedFile(failedFile)
-			}
+			// Also check stderr for failures (Jest reports them on stderr)
+			addFailedFiles(line)
 		}
 		if err := scanner.Err(); err != nil {
 			outputChan <- fmt.Sprintf("Error reading stderr: %v", err)
@@ -451,7 +504,7 @@ func (j *JestPane) runJestWithStream(instance *session.Instance, state *JestInst
 	// If we didn't get any output, try running with CombinedOutput as fallback
 	if allOutput.Len() == 0 {
 		outputChan <- "\nNo output captured from pipes, trying alternative method..."
-		fallbackCmd := exec.Command("yarn", "tester")
+		fallbackCmd := exec.Command("sh", "-c", command)
 		fallbackCmd.Dir = workDir
 		fallbackOutput, fallbackErr := fallbackCmd.CombinedOutput()
 		if fallbackErr != nil {
@@ -461,16 +514,19 @@ func (j *JestPane) runJestWithStream(instance *session.Instance, state *JestInst
 		// Parse fallback output for failed files and send to UI
 		lines := strings.Split(string(fallbackOutput), "\n")
 		for _, line := range lines {
-			if failedFile := parseFailedTestFile(line, workDir); failedFile != "" {
-				addFailedFile(failedFile)
-			}
+			addFailedFiles(line)
 			outputChan <- line
 		}
 	}
 
+	instance.RecordTestRun(len(failedFiles))
+
 	// Auto-open failed files in IDE
 	if len(failedFiles) > 0 {
 		j.autoOpenFailedTests(failedFiles)
+		if j.onTestsFailed != nil {
+			j.onTestsFailed(instance, failedFiles)
+		}
 	}
 
 	j.mu.Lock()
@@ -484,7 +540,7 @@ func (j *JestPane) runJestWithStream(instance *session.Instance, state *JestInst
 	j.viewport.GotoBottom()
 }
 
-func (j *JestPane) stopTests(instance *session.Instance) {
+func (j *TestPane) stopTests(instance *session.Instance) {
 	state := j.getOrCreateState(instance)
 	if state == nil || state.cmd == nil {
 		return
@@ -502,7 +558,7 @@ func (j *JestPane) stopTests(instance *session.Instance) {
 	j.mu.Unlock()
 }
 
-func (j *JestPane) autoOpenFailedTests(failedFiles []string) {
+func (j *TestPane) autoOpenFailedTests(failedFiles []string) {
 	state := j.getCurrentState()
 	if state == nil {
 		return
@@ -532,7 +588,7 @@ func (j *JestPane) autoOpenFailedTests(failedFiles []string) {
 	}
 }
 
-func (j *JestPane) findJestWorkingDir(startPath string) (string, error) {
+func (j *TestPane) findJestWorkingDir(startPath string) (string, error) {
 	// First check if startPath is a file or directory
 	info, err := os.Stat(startPath)
 	if err != nil {
@@ -598,7 +654,7 @@ func (j *JestPane) findJestWorkingDir(startPath string) (string, error) {
 	return "", fmt.Errorf("no package.json found in %s or parent directories", startPath)
 }
 
-func (j *JestPane) updateViewport() {
+func (j *TestPane) updateViewport() {
 	// Ensure viewport has correct dimensions
 	if j.viewport.Width != j.width {
 		j.viewport.Width = j.width
@@ -611,26 +667,16 @@ func (j *JestPane) updateViewport() {
 	j.viewport.SetContent(j.formatContent())
 }
 
-func (j *JestPane) ScrollUp() {
-	state := j.getCurrentState()
-	// Only allow scrolling when tests are not running
-	if state == nil || state.running {
-		return
-	}
-
-	// Update viewport for scrolling
-	j.updateViewport()
+func (j *TestPane) ScrollUp() {
+	j.ScrollBy(-3)
+}
 
-	// Update scroll position
-	if j.viewport.YOffset > 0 {
-		j.viewport.YOffset -= 3
-		if j.viewport.YOffset < 0 {
-			j.viewport.YOffset = 0
-		}
-	}
+func (j *TestPane) ScrollDown() {
+	j.ScrollBy(3)
 }
 
-func (j *JestPane) ScrollDown() {
+// ScrollBy scrolls the results by the given number of lines (negative scrolls up)
+func (j *TestPane) ScrollBy(lines int) {
 	state := j.getCurrentState()
 	// Only allow scrolling when tests are not running
 	if state == nil || state.running {
@@ -645,17 +691,23 @@ func (j *JestPane) ScrollDown() {
 	totalLines := len(strings.Split(content, "\n"))
 	availableHeight := j.height - 4
 
-	// Update scroll position
 	maxOffset := totalLines - availableHeight
-	if maxOffset > 0 && j.viewport.YOffset < maxOffset {
-		j.viewport.YOffset += 3
-		if j.viewport.YOffset > maxOffset {
-			j.viewport.YOffset = maxOffset
-		}
+	if maxOffset < 0 {
+		maxOffset = 0
+	}
+
+	// Update scroll position
+	offset := j.viewport.YOffset + lines
+	if offset < 0 {
+		offset = 0
+	}
+	if offset > maxOffset {
+		offset = maxOffset
 	}
+	j.viewport.YOffset = offset
 }
 
-func (j *JestPane) ResetToNormalMode() {
+func (j *TestPane) ResetToNormalMode() {
 	j.viewport.SetYOffset(0)
 }
 
@@ -0,0 +1,192 @@
+package ui
+
+import (
+	"bufio"
+	"claude-squad/config"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// TestOutputParser finds failed test files in a test run's output. A parser is created per run
+// and fed every output line in order, so it may keep state between lines.
+type TestOutputParser interface {
+	// FailedFiles returns the absolute paths of the test files the line reports as failed.
+	FailedFiles(line string) []string
+}
+
+// testParserFactories creates the parser for a run in workDir, keyed by the parser's config name.
+var testParserFactories = map[string]func(workDir string) TestOutputParser{
+	"jest": func(workDir string) TestOutputParser { return jestParser{workDir: workDir} },
+	"go":   newGoTestParser,
+	"pytest": func(workDir string) TestOutputParser {
+		return &patternParser{workDir: workDir, pattern: pytestFailureRe}
+	},
+	"cargo": func(workDir string) TestOutputParser {
+		return &patternParser{workDir: workDir, pattern: cargoFailureRe}
+	},
+	"none": func(string) TestOutputParser { return noParser{} },
+}
+
+// RegisterTestParser adds a parser that test runners can name in their "parser" setting.
+func RegisterTestParser(name string, factory func(workDir string) TestOutputParser) {
+	testParserFactories[name] = factory
+}
+
+var (
+	// pytestFailureRe matches pytest's short summary, e.g. "FAILED tests/test_x.py::test_y - ..."
+	pytestFailureRe = regexp.MustCompile(`^FAILED\s+([^\s:]+\.py)::`)
+	// cargoFailureRe matches a panicking test, e.g. "thread 'tests::x' panicked at src/lib.rs:10:5:"
+	// or, before Rust 1.73, "panicked at 'msg', src/lib.rs:10:5"
+	cargoFailureRe = regexp.MustCompile(`panicked at (?:'.*', )?([^\s:']+\.rs):\d+:\d+`)
+	// goTestFileRe matches a failure message, e.g. "    stats_test.go:12: got 1, want 2"
+	goTestFileRe = regexp.MustCompile(`^\s+(\S+_test\.go):\d+:`)
+	// goPackageResultRe matches a package result, e.g. "FAIL\tclaude-squad/ui\t0.1s" or "ok  \t..."
+	goPackageResultRe = regexp.MustCompile(`^(FAIL|ok)\s+(\S+)`)
+)
+
+// newTestParser returns the parser a runner asks for, picking one from its command when it
+// doesn't name one.
+func newTestParser(runner *config.TestRunnerConfig, workDir string) (TestOutputParser, error) {
+	if runner.FailurePattern != "" {
+		pattern, err := regexp.Compile(runner.FailurePattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid test failure pattern: %w", err)
+		}
+		if pattern.NumSubexp() < 1 {
+			return nil, fmt.Errorf("test failure pattern %q has no group capturing the file", runner.FailurePattern)
+		}
+		return &patternParser{workDir: workDir, pattern: pattern}, nil
+	}
+
+	name := runner.Parser
+	if name == "" {
+		name = detectTestParser(runner.Command)
+	}
+	factory, ok := testParserFactories[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown test output parser %q", name)
+	}
+	return factory(workDir), nil
+}
+
+// detectTestParser guesses the parser for a test command from the tools it mentions.
+func detectTestParser(command string) string {
+	switch {
+	// Checked before go since "cargo test" contains "go test"
+	case strings.Contains(command, "cargo test"), strings.Contains(command, "cargo nextest"):
+		return "cargo"
+	case strings.Contains(command, "go test"):
+		return "go"
+	case strings.Contains(command, "pytest"):
+		return "pytest"
+	case strings.Contains(command, "jest"), strings.Contains(command, "yarn"), strings.Contains(command, "npm"),
+		strings.Contains(command, "npx"), strings.Contains(command, "pnpm"):
+		return "jest"
+	default:
+		return "none"
+	}
+}
+
+// absTestPath resolves a path from test output against the directory the tests ran in.
+func absTestPath(path, workDir string) string {
+	if filepath.IsAbs(path) {
+		return path
+	}
+	return filepath.Join(workDir, path)
+}
+
+// jestParser reads Jest's "FAIL path" lines.
+type jestParser struct {
+	workDir string
+}
+
+func (p jestParser) FailedFiles(line string) []string {
+	if file := parseFailedTestFile(line, p.workDir); file != "" {
+		return []string{file}
+	}
+	return nil
+}
+
+// patternParser reports the file captured by the first group of a regular expression.
+type patternParser struct {
+	workDir string
+	pattern *regexp.Regexp
+}
+
+func (p *patternParser) FailedFiles(line string) []string {
+	matches := p.pattern.FindStringSubmatch(line)
+	if len(matches) < 2 || matches[1] == "" {
+		return nil
+	}
+	return []string{absTestPath(matches[1], p.workDir)}
+}
+
+// goTestParser collects the test files named in failure messages, which go test prints without
+// their directory, and resolves them once the package's FAIL line names it.
+type goTestParser struct {
+	workDir    string
+	modulePath string
+	pending    []string
+}
+
+func newGoTestParser(workDir string) TestOutputParser {
+	return &goTestParser{workDir: workDir, modulePath: readModulePath(workDir)}
+}
+
+func (p *goTestParser) FailedFiles(line string) []string {
+	if matches := goTestFileRe.FindStringSubmatch(line); matches != nil {
+		p.pending = append(p.pending, matches[1])
+		return nil
+	}
+
+	matches := goPackageResultRe.FindStringSubmatch(line)
+	if matches == nil {
+		return nil
+	}
+	pending := p.pending
+	p.pending = nil
+	if matches[1] != "FAIL" || p.modulePath == "" {
+		return nil
+	}
+	rel, ok := strings.CutPrefix(matches[2], p.modulePath)
+	if !ok {
+		return nil
+	}
+	dir := filepath.Join(p.workDir, filepath.FromSlash(strings.TrimPrefix(rel, "/")))
+
+	var files []string
+	for _, name := range pending {
+		path := filepath.Join(dir, filepath.Base(name))
+		if _, err := os.Stat(path); err == nil {
+			files = append(files, path)
+		}
+	}
+	return files
+}
+
+// readModulePath returns the module path declared in workDir's go.mod, or "" if there is none.
+func readModulePath(workDir string) string {
+	file, err := os.Open(filepath.Join(workDir, "go.mod"))
+	if err != nil {
+		return ""
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		if path, ok := strings.CutPrefix(strings.TrimSpace(scanner.Text()), "module "); ok {
+			return strings.Trim(strings.TrimSpace(path), `"`)
+		}
+	}
+	return ""
+}
+
+// noParser finds no failed files, for commands whose output isn't understood.
+type noParser struct{}
+
+func (noParser) FailedFiles(string) []string {
+	return nil
+}
@@ -0,0 +1,86 @@
+package ui
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"claude-squad/config"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// failedFiles feeds output lines to the parser the runner asks for and collects what it reports.
+func failedFiles(t *testing.T, runner config.TestRunnerConfig, workDir string, lines ...string) []string {
+	t.Helper()
+	parser, err := newTestParser(&runner, workDir)
+	require.NoError(t, err)
+	var files []string
+	for _, line := range lines {
+		files = append(files, parser.FailedFiles(line)...)
+	}
+	return files
+}
+
+func TestTestParsers(t *testing.T) {
+	t.Run("go resolves files once the package fails", func(t *testing.T) {
+		dir := t.TempDir()
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "go.mod"), []byte("module example.com/app\n"), 0644))
+		require.NoError(t, os.MkdirAll(filepath.Join(dir, "pkg", "store"), 0755))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "pkg", "store", "store_test.go"), nil, 0644))
+
+		files := failedFiles(t, config.TestRunnerConfig{Command: "go test ./..."}, dir,
+			"ok  \texample.com/app/pkg/other\t0.01s",
+			"--- FAIL: TestStore (0.00s)",
+			"    store_test.go:12: got 1, want 2",
+			"    store_test.go:20: got 3, want 4",
+			"FAIL",
+			"FAIL\texample.com/app/pkg/store\t0.02s",
+		)
+		assert.Equal(t, []string{
+			filepath.Join(dir, "pkg", "store", "store_test.go"),
+			filepath.Join(dir, "pkg", "store", "store_test.go"),
+		}, files)
+	})
+
+	t.Run("pytest", func(t *testing.T) {
+		files := failedFiles(t, config.TestRunnerConfig{Command: "uv run pytest -q"}, "/repo",
+			"tests/test_api.py:14: AssertionError",
+			"FAILED tests/test_api.py::test_get - AssertionError: 1 != 2",
+		)
+		assert.Equal(t, []string{"/repo/tests/test_api.py"}, files)
+	})
+
+	t.Run("cargo", func(t *testing.T) {
+		files := failedFiles(t, config.TestRunnerConfig{Command: "cargo test"}, "/repo",
+			"thread 'tests::adds' panicked at src/lib.rs:10:5:",
+			"thread 'tests::old' panicked at 'assertion failed', tests/old.rs:3:9",
+		)
+		assert.Equal(t, []string{"/repo/src/lib.rs", "/repo/tests/old.rs"}, files)
+	})
+
+	t.Run("jest", func(t *testing.T) {
+		files := failedFiles(t, config.TestRunnerConfig{Command: "yarn tester"}, "/repo/web",
+			"PASS src/ok.test.js",
+			"FAIL src/broken.test.tsx (5.1 s)",
+		)
+		assert.Equal(t, []string{"/repo/web/src/broken.test.tsx"}, files)
+	})
+
+	t.Run("custom pattern", func(t *testing.T) {
+		files := failedFiles(t, config.TestRunnerConfig{Command: "make check", FailurePattern: `^ERROR in (\S+)`}, "/repo",
+			"ERROR in lib/a.ex",
+		)
+		assert.Equal(t, []string{"/repo/lib/a.ex"}, files)
+	})
+
+	t.Run("invalid settings", func(t *testing.T) {
+		_, err := newTestParser(&config.TestRunnerConfig{Command: "x", FailurePattern: `^ERROR`}, "/repo")
+		assert.Error(t, err, "a pattern without a group can't capture the file")
+		_, err = newTestParser(&config.TestRunnerConfig{Command: "x", Parser: "mocha"}, "/repo")
+		assert.Error(t, err)
+	})
+
+	assert.Equal(t, "none", detectTestParser("make check"))
+}
@@ -0,0 +1,175 @@
+package ui
+
+import (
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/mattn/go-runewidth"
+)
+
+// ToastLevel is the severity of a toast notification.
+type ToastLevel int
+
+const (
+	ToastInfo ToastLevel = iota
+	ToastSuccess
+	ToastWarning
+	ToastError
+)
+
+// String returns the config name of the level.
+func (l ToastLevel) String() string {
+	switch l {
+	case ToastSuccess:
+		return "success"
+	case ToastWarning:
+		return "warning"
+	case ToastError:
+		return "error"
+	default:
+		return "info"
+	}
+}
+
+// DefaultToastDuration returns how long a toast of the given level stays on screen when
+// no override is configured.
+func DefaultToastDuration(level ToastLevel) time.Duration {
+	switch level {
+	case ToastWarning, ToastError:
+		return 5 * time.Second
+	default:
+		return 3 * time.Second
+	}
+}
+
+// ToastAction is a key hint rendered next to a toast, e.g. "l view log".
+type ToastAction struct {
+	Key   string
+	Label string
+}
+
+// Toast is a single notification in the toast area.
+type Toast struct {
+	ID      int
+	Level   ToastLevel
+	Message string
+	Action  *ToastAction
+}
+
+var toastStyles = map[ToastLevel]lipgloss.Style{
+	ToastInfo: lipgloss.NewStyle().Foreground(lipgloss.AdaptiveColor{
+		Light: "#1a1a1a",
+		Dark:  "#dddddd",
+	}),
+	ToastSuccess: lipgloss.NewStyle().Foreground(lipgloss.AdaptiveColor{
+		Light: "#51bd73",
+		Dark:  "#51bd73",
+	}),
+	ToastWarning: lipgloss.NewStyle().Foreground(lipgloss.AdaptiveColor{
+		Light: "#d19a00",
+		Dark:  "#ffcc00",
+	}),
+	ToastError: lipgloss.NewStyle().Foreground(lipgloss.AdaptiveColor{
+		Light: "#FF0000",
+		Dark:  "#FF0000",
+	}),
+}
+
+var toastIcons = map[ToastLevel]string{
+	ToastInfo:    "ℹ ",
+	ToastSuccess: "✓ ",
+	ToastWarning: "! ",
+	ToastError:   "✗ ",
+}
+
+var toastActionStyle = lipgloss.NewStyle().Foreground(lipgloss.AdaptiveColor{
+	Light: "#655F5F",
+	Dark:  "#7F7A7A",
+})
+
+// maxToasts caps how many toasts are kept in the stack at once.
+const maxToasts = 5
+
+// ToastBox displays a stack of toast notifications below the menu. The newest toast is
+// rendered at the bottom; older ones are shown above it if there is room.
+type ToastBox struct {
+	height, width int
+	toasts        []Toast
+	nextID        int
+}
+
+func NewToastBox() *ToastBox {
+	return &ToastBox{}
+}
+
+// Push adds a toast to the stack and returns its ID so the caller can dismiss it later.
+func (t *ToastBox) Push(level ToastLevel, message string, action *ToastAction) int {
+	t.nextID++
+	t.toasts = append(t.toasts, Toast{
+		ID:      t.nextID,
+		Level:   level,
+		Message: message,
+		Action:  action,
+	})
+	if len(t.toasts) > maxToasts {
+		t.toasts = t.toasts[len(t.toasts)-maxToasts:]
+	}
+	return t.nextID
+}
+
+// Dismiss removes the toast with the given ID. Noop if it is already gone.
+func (t *ToastBox) Dismiss(id int) {
+	for i, toast := range t.toasts {
+		if toast.ID == id {
+			t.toasts = append(t.toasts[:i], t.toasts[i+1:]...)
+			return
+		}
+	}
+}
+
+// Clear removes all toasts.
+func (t *ToastBox) Clear() {
+	t.toasts = nil
+}
+
+// Toasts returns the toasts currently in the stack, oldest first.
+func (t *ToastBox) Toasts() []Toast {
+	return t.toasts
+}
+
+func (t *ToastBox) SetSize(width, height int) {
+	t.width = width
+	t.height = height
+}
+
+func (t *ToastBox) renderToast(toast Toast) string {
+	msg := strings.Join(strings.Split(toast.Message, "\n"), "//")
+
+	var action string
+	if toast.Action != nil {
+		action = " [" + toast.Action.Key + " " + toast.Action.Label + "]"
+	}
+
+	// Measured in cells, so wide characters count double and none is cut in half
+	text := toastIcons[toast.Level] + msg
+	avail := t.width - 3 - runewidth.StringWidth(action)
+	if avail >= 0 && runewidth.StringWidth(text) > avail {
+		text = runewidth.Truncate(text, avail, "") + "..."
+	}
+	return toastStyles[toast.Level].Render(text) + toastActionStyle.Render(action)
+}
+
+func (t *ToastBox) String() string {
+	visible := t.toasts
+	if t.height > 0 && len(visible) > t.height {
+		visible = visible[len(visible)-t.height:]
+	}
+
+	lines := make([]string, 0, len(visible))
+	for _, toast := range visible {
+		lines = append(lines, t.renderToast(toast))
+	}
+	return lipgloss.Place(t.width, t.height, lipgloss.Center, lipgloss.Bottom,
+		lipgloss.JoinVertical(lipgloss.Center, lines...))
+}
@@ -0,0 +1,75 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"github.com/charmbracelet/x/ansi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestToastBoxStacking(t *testing.T) {
+	box := NewToastBox()
+	var ids []int
+	for n := 1; n <= maxToasts+2; n++ {
+		ids = append(ids, box.Push(ToastInfo, fmt.Sprintf("toast %d", n), nil))
+	}
+
+	// The oldest toasts make room for new ones
+	toasts := box.Toasts()
+	require.Len(t, toasts, maxToasts)
+	assert.Equal(t, "toast 3", toasts[0].Message)
+	assert.Equal(t, "toast 7", toasts[len(toasts)-1].Message)
+	assert.Equal(t, ids[len(ids)-1], toasts[len(toasts)-1].ID)
+
+	// Only the newest fit when the box is short, with the newest at the bottom
+	box.SetSize(40, 2)
+	lines := strings.Split(ansi.Strip(box.String()), "\n")
+	require.Len(t, lines, 2)
+	assert.Contains(t, lines[0], "toast 6")
+	assert.Contains(t, lines[1], "toast 7")
+}
+
+func TestToastBoxExpiry(t *testing.T) {
+	box := NewToastBox()
+	first := box.Push(ToastInfo, "first", nil)
+	second := box.Push(ToastError, "second", &ToastAction{Key: "E", Label: "view log"})
+
+	// A toast expiring is dismissed by its ID, leaving the others
+	box.Dismiss(first)
+	require.Len(t, box.Toasts(), 1)
+	assert.Equal(t, second, box.Toasts()[0].ID)
+	box.Dismiss(first)
+	assert.Len(t, box.Toasts(), 1, "dismissing a toast twice is a noop")
+	box.Dismiss(second)
+	assert.Empty(t, box.Toasts())
+
+	// Problems stay on screen longer than other news
+	assert.Greater(t, DefaultToastDuration(ToastError), DefaultToastDuration(ToastSuccess))
+	assert.Equal(t, DefaultToastDuration(ToastWarning), DefaultToastDuration(ToastError))
+	assert.Equal(t, 3*time.Second, DefaultToastDuration(ToastInfo))
+}
+
+func TestToastTruncation(t *testing.T) {
+	box := NewToastBox()
+	box.SetSize(30, 1)
+	for _, message := range []string{
+		strings.Repeat("long message ", 10),
+		strings.Repeat("日本語のメッセージ", 10),
+		"multi\nline",
+	} {
+		rendered := ansi.Strip(box.renderToast(Toast{Level: ToastWarning, Message: message,
+			Action: &ToastAction{Key: "E", Label: "log"}}))
+		assert.True(t, utf8.ValidString(rendered), "%q cut a character in half", rendered)
+		assert.LessOrEqual(t, ansi.StringWidth(rendered), 30, rendered)
+		assert.True(t, strings.HasSuffix(rendered, "[E log]"), rendered)
+	}
+
+	short := ansi.Strip(box.renderToast(Toast{Level: ToastInfo, Message: "done"}))
+	assert.Equal(t, toastIcons[ToastInfo]+"done", short)
+	assert.Contains(t, ansi.Strip(box.renderToast(Toast{Message: "multi\nline"})), "multi//line")
+}
@@ -0,0 +1,86 @@
+package ui
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+// PanColumns is how many columns a horizontal pan moves.
+const PanColumns = 8
+
+// LineLayout controls how lines wider than a pane are shown: soft-wrapped onto the following
+// rows, or cut to the pane's width and panned horizontally.
+type LineLayout struct {
+	// Wrap soft-wraps long lines instead of cutting them
+	Wrap bool
+	// XOffset is the first column shown when lines are cut
+	XOffset int
+}
+
+// ToggleWrap switches between wrapping and panning, returning to the first column.
+func (l *LineLayout) ToggleWrap() {
+	l.Wrap = !l.Wrap
+	l.XOffset = 0
+}
+
+// Pan moves the view by columns (negative pans left). It has no effect while wrapping; the
+// offset is clamped to the content when it is next applied.
+func (l *LineLayout) Pan(columns int) {
+	if l.Wrap {
+		return
+	}
+	l.XOffset += columns
+	if l.XOffset < 0 {
+		l.XOffset = 0
+	}
+}
+
+// Apply lays content out for a pane width columns wide, keeping escape sequences intact. It also
+// returns the row each line of content starts on in the result, for mapping line positions.
+func (l *LineLayout) Apply(content string, width int) (string, []int) {
+	lines := strings.Split(content, "\n")
+	rows := make([]int, len(lines))
+	for i := range rows {
+		rows[i] = i
+	}
+	if width <= 0 {
+		return content, rows
+	}
+
+	if l.Wrap {
+		wrapped := make([]string, 0, len(lines))
+		for i, line := range lines {
+			rows[i] = len(wrapped)
+			if ansi.StringWidth(line) <= width {
+				wrapped = append(wrapped, line)
+				continue
+			}
+			wrapped = append(wrapped, strings.Split(ansi.Wrap(line, width, ""), "\n")...)
+		}
+		return strings.Join(wrapped, "\n"), rows
+	}
+
+	widest := 0
+	for _, line := range lines {
+		widest = max(widest, ansi.StringWidth(line))
+	}
+	l.XOffset = max(0, min(l.XOffset, widest-width))
+	for i, line := range lines {
+		lines[i] = ansi.Cut(line, l.XOffset, l.XOffset+width)
+	}
+	return strings.Join(lines, "\n"), rows
+}
+
+// MapRows converts line numbers of the original content to the rows returned by Apply.
+func MapRows(lines, rows []int) []int {
+	mapped := make([]int, 0, len(lines))
+	for _, line := range lines {
+		if line >= 0 && line < len(rows) {
+			mapped = append(mapped, rows[line])
+		} else {
+			mapped = append(mapped, line)
+		}
+	}
+	return mapped
+}
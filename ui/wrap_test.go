@@ -0,0 +1,51 @@
+package ui
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLineLayoutCutsAndPans(t *testing.T) {
+	layout := &LineLayout{}
+	content := "0123456789abcdef\nshort"
+
+	got, rows := layout.Apply(content, 8)
+	assert.Equal(t, "01234567\nshort", got)
+	assert.Equal(t, []int{0, 1}, rows)
+
+	layout.Pan(PanColumns)
+	got, _ = layout.Apply(content, 8)
+	assert.Equal(t, "89abcdef\n", got)
+
+	// Panning past the widest line stops at its end
+	layout.Pan(PanColumns)
+	got, _ = layout.Apply(content, 8)
+	assert.Equal(t, "89abcdef\n", got)
+	assert.Equal(t, 8, layout.XOffset)
+
+	layout.Pan(-100)
+	assert.Equal(t, 0, layout.XOffset)
+}
+
+func TestLineLayoutWraps(t *testing.T) {
+	layout := &LineLayout{}
+	layout.Pan(4)
+	layout.ToggleWrap()
+	assert.Equal(t, 0, layout.XOffset)
+
+	got, rows := layout.Apply("0123456789abcdef\nshort", 8)
+	assert.Equal(t, "01234567\n89abcdef\nshort", got)
+	assert.Equal(t, []int{0, 2}, rows)
+	assert.Equal(t, []int{0, 2, 7}, MapRows([]int{0, 1, 7}, rows))
+
+	// Panning does nothing while wrapping
+	layout.Pan(PanColumns)
+	assert.Equal(t, 0, layout.XOffset)
+}
+
+func TestLineLayoutKeepsColors(t *testing.T) {
+	layout := &LineLayout{}
+	got, _ := layout.Apply("\x1b[32m+added line\x1b[0m", 6)
+	assert.Equal(t, "\x1b[32m+added\x1b[0m", got)
+}